@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/audit"
+	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+)
+
+// setupAuditLog returns the audit logger that records calls to write tools
+// in the file at path and posts them to url, whichever are set, or nil if
+// neither is. Calls made with the server credentials are attributed to
+// key. The returned function closes the audit log file.
+func setupAuditLog(
+	ctx context.Context,
+	obs *observability.Observability,
+	path, url, key string,
+) (*audit.Logger, func() error, error) {
+	var sinks []audit.Sink
+	closeLog := func() error { return nil }
+
+	if path != "" {
+		fileSink, err := audit.NewFileSink(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		sinks = append(sinks, fileSink)
+		closeLog = fileSink.Close
+	}
+	if url != "" {
+		sinks = append(sinks, audit.NewHTTPSink(url))
+	}
+	if len(sinks) == 0 {
+		return nil, closeLog, nil
+	}
+
+	logger := audit.NewLogger(sinks,
+		audit.WithDefaultCaller(key),
+		audit.WithErrorHandler(func(err error) {
+			obs.Logger.Errorf(ctx, "audit log error", "error", err)
+		}),
+	)
+	return logger, closeLog, nil
+}
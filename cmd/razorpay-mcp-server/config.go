@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/spf13/viper"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay"
+)
+
+// serverConfigFromViper returns the locale, the key mode, the unit of
+// amounts, the generated email settings, the accounts, partner auth, the
+// currencies and the toolset and tool settings from the toolset_config and
+// tool_config sections of the config file. Unknown settings are rejected
+// so that typos do not go unnoticed.
+func serverConfigFromViper() (razorpay.Config, error) {
+	config := razorpay.Config{
+		Profiles: viper.GetStringSlice("profile"),
+
+		Locale: viper.GetString("locale"),
+		Mode:   viper.GetString("mode"),
+
+		AmountUnit: viper.GetString("amount_unit"),
+
+		ConfirmHighRisk:    viper.GetBool("confirm_high_risk"),
+		ConfirmRefundAbove: viper.GetInt64("confirm_refund_above"),
+
+		GeneratedEmailDomain:  viper.GetString("generated_email_domain"),
+		DisableGeneratedEmail: viper.GetBool("disable_generated_email"),
+
+		DefaultAccount: viper.GetString("default_account"),
+		Partner:        viper.GetBool("partner"),
+	}
+	errorUnused := func(c *mapstructure.DecoderConfig) {
+		c.ErrorUnused = true
+	}
+
+	err := viper.UnmarshalKey("toolset_config", &config.Toolsets, errorUnused)
+	if err != nil {
+		return config, fmt.Errorf("invalid toolset_config: %w", err)
+	}
+	err = viper.UnmarshalKey("tool_config", &config.Tools, errorUnused)
+	if err != nil {
+		return config, fmt.Errorf("invalid tool_config: %w", err)
+	}
+	err = viper.UnmarshalKey("policy", &config.Policy, errorUnused)
+	if err != nil {
+		return config, fmt.Errorf("invalid policy: %w", err)
+	}
+	var currencies map[string]razorpay.CurrencyConfig
+	err = viper.UnmarshalKey("currencies", &currencies, errorUnused)
+	if err != nil {
+		return config, fmt.Errorf("invalid currencies: %w", err)
+	}
+	// Viper lowercases keys, while currency codes are uppercase
+	if len(currencies) > 0 {
+		config.Currencies = make(map[string]razorpay.CurrencyConfig,
+			len(currencies))
+		for code, currency := range currencies {
+			config.Currencies[strings.ToUpper(code)] = currency
+		}
+	}
+	err = viper.UnmarshalKey("accounts", &config.Accounts, errorUnused)
+	if err != nil {
+		return config, fmt.Errorf("invalid accounts: %w", err)
+	}
+
+	return config, nil
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay"
+)
+
+// readTestConfig loads a yaml config into viper
+func readTestConfig(t *testing.T, config string) {
+	t.Helper()
+
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.SetConfigType("yaml")
+	require.NoError(t, viper.ReadConfig(strings.NewReader(config)))
+}
+
+func TestServerConfigFromViper(t *testing.T) {
+	t.Run("loads toolset and tool settings", func(t *testing.T) {
+		readTestConfig(t, `
+toolset_config:
+  orders:
+    read_only: true
+    default_currency: USD
+    default_capture: manual
+  payouts:
+    enabled: false
+tool_config:
+  fetch_order:
+    description: Looks up an order
+  fetch_all_orders:
+    timeout: 1m30s
+`)
+
+		config, err := serverConfigFromViper()
+		require.NoError(t, err)
+
+		orders := config.Toolsets["orders"]
+		assert.True(t, orders.ReadOnly)
+		assert.Nil(t, orders.Enabled)
+		assert.Equal(t, "USD", orders.DefaultCurrency)
+		assert.Equal(t, "manual", orders.DefaultCapture)
+		require.NotNil(t, config.Toolsets["payouts"].Enabled)
+		assert.False(t, *config.Toolsets["payouts"].Enabled)
+		assert.Equal(t, "Looks up an order",
+			config.Tools["fetch_order"].Description)
+		assert.Nil(t, config.Tools["fetch_order"].Timeout)
+		require.NotNil(t, config.Tools["fetch_all_orders"].Timeout)
+		assert.Equal(t, 90*time.Second,
+			*config.Tools["fetch_all_orders"].Timeout)
+	})
+
+	t.Run("returns empty config without sections", func(t *testing.T) {
+		readTestConfig(t, "read_only: true\n")
+
+		config, err := serverConfigFromViper()
+		require.NoError(t, err)
+		assert.Empty(t, config.Toolsets)
+		assert.Empty(t, config.Tools)
+		assert.Empty(t, config.Locale)
+	})
+
+	t.Run("loads the locale and mode", func(t *testing.T) {
+		readTestConfig(t, "locale: hi\nmode: test\n")
+
+		config, err := serverConfigFromViper()
+		require.NoError(t, err)
+		assert.Equal(t, "hi", config.Locale)
+		assert.Equal(t, "test", config.Mode)
+	})
+
+	t.Run("loads the currencies", func(t *testing.T) {
+		readTestConfig(t, `
+currencies:
+  USD:
+    min_amount: 50
+`)
+
+		config, err := serverConfigFromViper()
+		require.NoError(t, err)
+		assert.Equal(t, map[string]razorpay.CurrencyConfig{
+			"USD": {MinAmount: 50},
+		}, config.Currencies)
+	})
+
+	t.Run("loads the amount unit", func(t *testing.T) {
+		readTestConfig(t, "amount_unit: rupees\n")
+
+		config, err := serverConfigFromViper()
+		require.NoError(t, err)
+		assert.Equal(t, "rupees", config.AmountUnit)
+	})
+
+	t.Run("loads the policy", func(t *testing.T) {
+		readTestConfig(t, `
+policy:
+  max_refund_amount: 100000
+  allowed_currencies: [INR, USD]
+`)
+
+		config, err := serverConfigFromViper()
+		require.NoError(t, err)
+		assert.Equal(t, int64(100000), config.Policy.MaxRefundAmount)
+		assert.Equal(t, []string{"INR", "USD"},
+			config.Policy.AllowedCurrencies)
+	})
+
+	t.Run("rejects unknown policy settings", func(t *testing.T) {
+		readTestConfig(t, "policy:\n  max_payout_amount: 100\n")
+
+		_, err := serverConfigFromViper()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid policy")
+	})
+
+	t.Run("loads the confirmation settings", func(t *testing.T) {
+		readTestConfig(t,
+			"confirm_high_risk: true\nconfirm_refund_above: 50000\n")
+
+		config, err := serverConfigFromViper()
+		require.NoError(t, err)
+		assert.True(t, config.ConfirmHighRisk)
+		assert.Equal(t, int64(50000), config.ConfirmRefundAbove)
+	})
+
+	t.Run("loads the generated email settings", func(t *testing.T) {
+		readTestConfig(t, "generated_email_domain: example.com\n"+
+			"disable_generated_email: true\n")
+
+		config, err := serverConfigFromViper()
+		require.NoError(t, err)
+		assert.Equal(t, "example.com", config.GeneratedEmailDomain)
+		assert.True(t, config.DisableGeneratedEmail)
+	})
+
+	t.Run("loads the accounts", func(t *testing.T) {
+		readTestConfig(t, `
+default_account: prod_in
+accounts:
+  prod_in:
+    key: rzp_live_in
+    secret: in-secret
+  prod_intl:
+    key: rzp_live_intl
+    secret: intl-secret
+`)
+
+		config, err := serverConfigFromViper()
+		require.NoError(t, err)
+		assert.Equal(t, "prod_in", config.DefaultAccount)
+		assert.Equal(t, map[string]razorpay.AccountConfig{
+			"prod_in":   {Key: "rzp_live_in", Secret: "in-secret"},
+			"prod_intl": {Key: "rzp_live_intl", Secret: "intl-secret"},
+		}, config.Accounts)
+	})
+
+	t.Run("rejects unknown account settings", func(t *testing.T) {
+		readTestConfig(t, "accounts:\n  prod_in:\n    key_id: rzp_live_in\n")
+
+		_, err := serverConfigFromViper()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid accounts")
+	})
+
+	t.Run("rejects unknown toolset settings", func(t *testing.T) {
+		readTestConfig(t, `
+toolset_config:
+  orders:
+    readonly: true
+`)
+
+		_, err := serverConfigFromViper()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid toolset_config")
+		assert.Contains(t, err.Error(), "readonly")
+	})
+
+	t.Run("rejects unknown tool settings", func(t *testing.T) {
+		readTestConfig(t, `
+tool_config:
+  fetch_order:
+    title: Order
+`)
+
+		_, err := serverConfigFromViper()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid tool_config")
+		assert.Contains(t, err.Error(), "title")
+	})
+
+	t.Run("rejects settings of the wrong type", func(t *testing.T) {
+		readTestConfig(t, `
+toolset_config:
+  orders:
+    enabled: sometimes
+`)
+
+		_, err := serverConfigFromViper()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "enabled")
+	})
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
+	"github.com/razorpay/razorpay-mcp-server/pkg/oauth"
+)
+
+// Headers carrying the Razorpay credentials of a single request
+const (
+	keyIDHeader     = "X-Razorpay-Key-Id"
+	keySecretHeader = "X-Razorpay-Key-Secret"
+)
+
+var (
+	errIncompleteCredentials = errors.New(
+		"both " + keyIDHeader + " and " + keySecretHeader + " are required")
+	errInvalidAuthorization = errors.New(
+		"authorization header must use basic auth with key id and secret")
+	errMissingCredentials = errors.New("missing razorpay credentials")
+	errUnknownSubject     = errors.New(
+		"no razorpay account is configured for the subject of the token")
+)
+
+// credentialsFromRequest returns the Razorpay key ID and secret sent with a
+// request, either in the X-Razorpay-Key-Id and X-Razorpay-Key-Secret
+// headers or as basic auth in the Authorization header. ok is false if the
+// request carries no credentials.
+func credentialsFromRequest(
+	r *http.Request,
+) (key string, secret string, ok bool, err error) {
+	key = r.Header.Get(keyIDHeader)
+	secret = r.Header.Get(keySecretHeader)
+	if key != "" || secret != "" {
+		if key == "" || secret == "" {
+			return "", "", false, errIncompleteCredentials
+		}
+		return key, secret, true, nil
+	}
+
+	if r.Header.Get("Authorization") == "" {
+		return "", "", false, nil
+	}
+
+	key, secret, ok = r.BasicAuth()
+	if !ok || key == "" || secret == "" {
+		return "", "", false, errInvalidAuthorization
+	}
+
+	return key, secret, true, nil
+}
+
+// withRequestCredentials serves requests that carry their own Razorpay
+// credentials with a client built from them, so one server can be shared
+// by several merchants. Requests without credentials use the default
+// client, and are rejected if requireCredentials is set.
+func withRequestCredentials(
+	next http.Handler,
+	newClient func(key, secret string) (*rzpsdk.Client, error),
+	requireCredentials bool,
+) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key, secret, ok, err := credentialsFromRequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		if !ok {
+			if requireCredentials {
+				http.Error(w, errMissingCredentials.Error(),
+					http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		client, err := newClient(key, secret)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		ctx := contextkey.WithClient(r.Context(), client)
+		ctx = contextkey.WithCacheScope(ctx, cacheScope(key, secret))
+		ctx = contextkey.WithCaller(ctx, key)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// withBearerClient serves requests authenticated with an OAuth bearer token
+// with the client of the subject of the token. Subjects without a client
+// are forbidden.
+func withBearerClient(
+	next http.Handler,
+	clients map[string]*rzpsdk.Client,
+) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims := oauth.ClaimsFromContext(r.Context())
+		if claims == nil {
+			http.Error(w, errMissingCredentials.Error(),
+				http.StatusUnauthorized)
+			return
+		}
+
+		client, ok := clients[claims.Subject]
+		if !ok {
+			http.Error(w, errUnknownSubject.Error(), http.StatusForbidden)
+			return
+		}
+
+		auth := client.Request.Auth
+		ctx := contextkey.WithClient(r.Context(), client)
+		ctx = contextkey.WithCacheScope(ctx, cacheScope(auth.Key, auth.Secret))
+		ctx = contextkey.WithCaller(ctx, claims.Subject)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// cacheScope returns the cache scope of requests made with the given
+// credentials, so that cached tool results are only shared between
+// requests that could have fetched them
+func cacheScope(credentials ...string) string {
+	hash := sha256.Sum256([]byte(strings.Join(credentials, "\x00")))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
+)
+
+func TestCredentialsFromRequest(t *testing.T) {
+	tests := []struct {
+		name           string
+		headers        map[string]string
+		basicAuth      []string
+		expectedKey    string
+		expectedSecret string
+		expectedOK     bool
+		expectedErr    error
+	}{
+		{
+			name: "key id and secret headers",
+			headers: map[string]string{
+				keyIDHeader:     "rzp_test_key",
+				keySecretHeader: "test_secret",
+			},
+			expectedKey:    "rzp_test_key",
+			expectedSecret: "test_secret",
+			expectedOK:     true,
+		},
+		{
+			name:           "basic authorization header",
+			basicAuth:      []string{"rzp_test_key", "test_secret"},
+			expectedKey:    "rzp_test_key",
+			expectedSecret: "test_secret",
+			expectedOK:     true,
+		},
+		{
+			name: "key id headers take precedence over authorization",
+			headers: map[string]string{
+				keyIDHeader:     "rzp_test_key",
+				keySecretHeader: "test_secret",
+			},
+			basicAuth:      []string{"rzp_other_key", "other_secret"},
+			expectedKey:    "rzp_test_key",
+			expectedSecret: "test_secret",
+			expectedOK:     true,
+		},
+		{
+			name:       "no credentials",
+			expectedOK: false,
+		},
+		{
+			name: "key id without secret",
+			headers: map[string]string{
+				keyIDHeader: "rzp_test_key",
+			},
+			expectedErr: errIncompleteCredentials,
+		},
+		{
+			name: "bearer authorization header",
+			headers: map[string]string{
+				"Authorization": "Bearer token",
+			},
+			expectedErr: errInvalidAuthorization,
+		},
+		{
+			name:        "basic authorization without secret",
+			basicAuth:   []string{"rzp_test_key", ""},
+			expectedErr: errInvalidAuthorization,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+			for name, value := range tc.headers {
+				r.Header.Set(name, value)
+			}
+			if tc.basicAuth != nil {
+				r.SetBasicAuth(tc.basicAuth[0], tc.basicAuth[1])
+			}
+
+			key, secret, ok, err := credentialsFromRequest(r)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOK, ok)
+			assert.Equal(t, tc.expectedKey, key)
+			assert.Equal(t, tc.expectedSecret, secret)
+		})
+	}
+}
+
+func TestWithRequestCredentials(t *testing.T) {
+	newClient := func(key, secret string) (*rzpsdk.Client, error) {
+		return rzpsdk.NewClient(key, secret), nil
+	}
+
+	// serve runs a request through the middleware and returns the response
+	// and the client the wrapped handler found in the request context
+	serve := func(
+		r *http.Request,
+		newClient func(key, secret string) (*rzpsdk.Client, error),
+		requireCredentials bool,
+	) (*httptest.ResponseRecorder, interface{}) {
+		var client interface{}
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			client = contextkey.ClientFromContext(r.Context())
+		})
+
+		w := httptest.NewRecorder()
+		withRequestCredentials(next, newClient, requireCredentials).
+			ServeHTTP(w, r)
+		return w, client
+	}
+
+	t.Run("adds a client built from the request credentials",
+		func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+			r.Header.Set(keyIDHeader, "rzp_test_key")
+			r.Header.Set(keySecretHeader, "test_secret")
+
+			w, client := serve(r, newClient, true)
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			require.IsType(t, &rzpsdk.Client{}, client)
+			auth := client.(*rzpsdk.Client).Request.Auth
+			assert.Equal(t, "rzp_test_key", auth.Key)
+			assert.Equal(t, "test_secret", auth.Secret)
+		})
+
+	t.Run("records the key id as the caller", func(t *testing.T) {
+		var caller string
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			caller = contextkey.CallerFromContext(r.Context())
+		})
+
+		r := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		r.SetBasicAuth("rzp_test_key", "test_secret")
+		withRequestCredentials(next, newClient, true).
+			ServeHTTP(httptest.NewRecorder(), r)
+
+		assert.Equal(t, "rzp_test_key", caller)
+	})
+
+	t.Run("falls back to the default client", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+
+		w, client := serve(r, newClient, false)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Nil(t, client)
+	})
+
+	t.Run("rejects requests without required credentials",
+		func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+
+			w, _ := serve(r, newClient, true)
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			assert.Contains(t, w.Body.String(), "missing razorpay credentials")
+		})
+
+	t.Run("rejects invalid credentials", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		r.Header.Set(keySecretHeader, "test_secret")
+
+		w, _ := serve(r, newClient, false)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("reports client creation errors", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		r.SetBasicAuth("rzp_test_key", "test_secret")
+
+		failingClient := func(key, secret string) (*rzpsdk.Client, error) {
+			return nil, errors.New("invalid proxy url")
+		}
+
+		w, _ := serve(r, failingClient, false)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
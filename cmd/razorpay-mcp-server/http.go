@@ -0,0 +1,627 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	stdlog "log"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
+	"github.com/razorpay/razorpay-mcp-server/pkg/events"
+	"github.com/razorpay/razorpay-mcp-server/pkg/log"
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay"
+	"github.com/razorpay/razorpay-mcp-server/pkg/redis"
+)
+
+// readOnlyHeader enables read-only mode for the requests that carry it, so
+// clients can open read-only sessions on a server that allows writes
+const readOnlyHeader = "X-Razorpay-Read-Only"
+
+// defaultShutdownTimeout is how long running tool calls are given to
+// finish when the http server shuts down, unless configured
+const defaultShutdownTimeout = 30 * time.Second
+
+// closeTimeout bounds how long the requests left once tool calls finished
+// are given to end when the http server shuts down
+const closeTimeout = 5 * time.Second
+
+// httpServerConfig holds the settings of the http transport
+type httpServerConfig struct {
+	address      string
+	endpointPath string
+	keepAlive    time.Duration
+	sessionTTL   time.Duration
+	// shutdownTimeout is how long running tool calls are given to finish
+	// on shutdown
+	shutdownTimeout time.Duration
+	// maxRequestSize is the largest request body accepted, in bytes
+	maxRequestSize int64
+	// transport is shared by clients built from request credentials, so
+	// that they reuse its connections
+	transport *http.Transport
+	// retry holds the retry settings of clients built from request
+	// credentials
+	retry retryConfig
+	// metrics records the requests of clients built from request
+	// credentials, and is served on /metrics if set
+	metrics *observability.Metrics
+	// oauth enables OAuth bearer token authentication when configured
+	oauth oauthConfig
+	// redis shares sessions between replicas of the server, if set
+	redis *redis.Client
+	// tls serves the server over TLS when configured
+	tls tlsConfig
+	// webhook receives Razorpay webhook events when configured
+	webhook webhookConfig
+	// accounts is set when tool calls can be made with the accounts of the
+	// config, so that requests need no credentials
+	accounts bool
+	// partner is set when the server has partner credentials, so that
+	// requests can name a sub-merchant account
+	partner bool
+}
+
+// httpCmd starts the mcp server in streamable http transport mode
+var httpCmd = &cobra.Command{
+	Use:   "http",
+	Short: "start the streamable http server",
+	Run: func(cmd *cobra.Command, args []string) {
+		logPath := viper.GetString("log_file")
+
+		config := log.NewConfig(
+			log.WithMode(log.ModeStdio),
+			log.WithLogLevel(slog.LevelInfo),
+			log.WithLogPath(logPath),
+		)
+
+		ctx, logger := log.New(context.Background(), config)
+		defer func() { _ = logger.Close() }()
+
+		// Trace tool calls if an OTLP endpoint is configured
+		tracer, shutdownTracing, err := setupTracing(ctx)
+		if err != nil {
+			stdlog.Fatalf("failed to set up tracing: %v", err)
+		}
+		defer func() { _ = shutdownTracing(context.WithoutCancel(ctx)) }()
+
+		obsOpts := []observability.Option{
+			observability.WithLoggingService(logger),
+		}
+		if tracer != nil {
+			obsOpts = append(obsOpts, observability.WithTracer(tracer))
+		}
+		// Collect metrics to serve if enabled
+		if viper.GetBool("http_metrics") {
+			obsOpts = append(obsOpts,
+				observability.WithMetrics(observability.NewMetrics()))
+		}
+		obs := observability.New(obsOpts...)
+
+		key := viper.GetString("key")
+		secret := viper.GetString("secret")
+		retry := retryConfigFromViper()
+
+		// Send outbound requests over pooled connections, through a proxy
+		// if configured
+		transport, err := newTransport(transportConfigFromViper(),
+			viper.GetString("proxy_url"))
+		if err != nil {
+			stdlog.Fatalf("failed to configure proxy: %v", err)
+		}
+		client := newRazorpayHTTPClient(key, secret, transport, retry,
+			obs.Metrics)
+
+		// Record calls to write tools if an audit log is configured
+		auditLogger, closeAuditLog, err := setupAuditLog(ctx, obs,
+			viper.GetString("audit_log"), viper.GetString("audit_url"), key)
+		if err != nil {
+			stdlog.Fatalf("failed to set up audit log: %v", err)
+		}
+		defer func() { _ = closeAuditLog() }()
+
+		// Get toolsets to enable from config
+		enabledToolsets := viper.GetStringSlice("toolsets")
+
+		// Get individual tools to enable and disable from config
+		enabledTools := viper.GetStringSlice("enabled_tools")
+		disabledTools := viper.GetStringSlice("disabled_tools")
+
+		// Get the toolset and tool settings from config
+		serverConfig, err := serverConfigFromViper()
+		if err != nil {
+			stdlog.Fatalf("failed to load config: %v", err)
+		}
+
+		// Share state between replicas of the server if Redis is configured
+		redisClient, err := setupRedis(ctx, viper.GetString("redis_url"))
+		if err != nil {
+			stdlog.Fatalf("failed to connect to redis: %v", err)
+		}
+		if redisClient != nil {
+			defer func() { _ = redisClient.Close() }()
+		}
+
+		// Keep the results of write tool calls with an idempotency key
+		idempotency, err := idempotencyFromViper(redisClient)
+		if err != nil {
+			stdlog.Fatalf("failed to set up idempotency store: %v", err)
+		}
+
+		// Get read-only mode from config
+		readOnly := viper.GetBool("read_only")
+
+		// Reject undeclared tool parameters if strict mode is enabled
+		ctx = contextkey.WithStrictParams(ctx, viper.GetBool("strict_params"))
+
+		httpConfig := httpServerConfig{
+			address:         viper.GetString("http_address"),
+			endpointPath:    viper.GetString("http_endpoint_path"),
+			keepAlive:       viper.GetDuration("http_keep_alive"),
+			sessionTTL:      viper.GetDuration("http_session_ttl"),
+			shutdownTimeout: viper.GetDuration("http_shutdown_timeout"),
+			maxRequestSize:  viper.GetInt64("http_max_request_size"),
+			transport:       transport,
+			retry:           retry,
+			metrics:         obs.Metrics,
+			oauth: oauthConfig{
+				issuer:      viper.GetString("oauth_issuer"),
+				audience:    viper.GetString("oauth_audience"),
+				jwksURL:     viper.GetString("oauth_jwks_url"),
+				resourceURL: viper.GetString("oauth_resource_url"),
+				accounts:    serverConfig.OAuthAccounts(),
+			},
+			redis: redisClient,
+			tls: tlsConfig{
+				certFile:     viper.GetString("http_tls_cert"),
+				keyFile:      viper.GetString("http_tls_key"),
+				clientCAFile: viper.GetString("http_mtls_ca"),
+			},
+			webhook: webhookConfig{
+				secret:      viper.GetString("http_webhook_secret"),
+				path:        viper.GetString("http_webhook_path"),
+				historySize: viper.GetInt("http_webhook_history_size"),
+			},
+			accounts: len(serverConfig.Accounts) > 0,
+			partner:  serverConfig.Partner,
+		}
+
+		// Cache results of read-only tools if a cache TTL is configured
+		cacheTTL := viper.GetDuration("cache_ttl")
+
+		// Limit the rate of tool calls if rate limits are configured
+		sessionRateLimit := mcpgo.RateLimit{
+			Rate:  viper.GetFloat64("http_session_rate_limit"),
+			Burst: viper.GetInt("http_session_rate_burst"),
+		}
+		keyRateLimit := mcpgo.RateLimit{
+			Rate:  viper.GetFloat64("http_key_rate_limit"),
+			Burst: viper.GetInt("http_key_rate_burst"),
+		}
+		globalRateLimit := mcpgo.RateLimit{
+			Rate:  viper.GetFloat64("http_global_rate_limit"),
+			Burst: viper.GetInt("http_global_rate_burst"),
+		}
+
+		err = runHTTPServer(ctx, obs, client, enabledToolsets,
+			enabledTools, disabledTools, readOnly, httpConfig,
+			mcpgo.WithCacheTTL(cacheTTL),
+			mcpgo.WithToolTimeout(viper.GetDuration("tool_timeout")),
+			mcpgo.WithSessionRateLimit(sessionRateLimit),
+			mcpgo.WithKeyRateLimit(keyRateLimit),
+			mcpgo.WithGlobalRateLimit(globalRateLimit),
+			mcpgo.WithAuditLog(auditLogger),
+			mcpgo.WithDryRun(viper.GetBool("dry_run")),
+			idempotency,
+			mcpgo.WithRedisCache(redisClient),
+			mcpgo.WithMaxResultSize(viper.GetInt("http_max_result_size")),
+			razorpay.WithConfig(serverConfig))
+		if err != nil {
+			obs.Logger.Errorf(ctx,
+				"error running http server", "error", err)
+			stdlog.Fatalf("failed to run http server: %v", err)
+		}
+	},
+}
+
+func init() {
+	httpCmd.Flags().String("address", ":8080",
+		"address the http server listens on")
+	httpCmd.Flags().String("endpoint-path", mcpgo.DefaultEndpointPath,
+		"path the mcp endpoint is served on")
+	httpCmd.Flags().Bool("metrics", false,
+		"serve prometheus metrics on "+metricsPath)
+	httpCmd.Flags().Duration("keep-alive", mcpgo.DefaultKeepAlive,
+		"interval between pings on idle event streams, 0 to disable")
+	httpCmd.Flags().Duration("session-ttl", mcpgo.DefaultSessionTTL,
+		"how long an idle session is kept before it expires, 0 to keep "+
+			"sessions until the client ends them")
+
+	_ = viper.BindPFlag("http_address", httpCmd.Flags().Lookup("address"))
+	_ = viper.BindPFlag("http_endpoint_path",
+		httpCmd.Flags().Lookup("endpoint-path"))
+	_ = viper.BindPFlag("http_metrics", httpCmd.Flags().Lookup("metrics"))
+	_ = viper.BindPFlag("http_keep_alive",
+		httpCmd.Flags().Lookup("keep-alive"))
+	httpCmd.Flags().Duration("shutdown-timeout", defaultShutdownTimeout,
+		"how long running tool calls are given to finish on shutdown")
+	_ = viper.BindPFlag("http_session_ttl",
+		httpCmd.Flags().Lookup("session-ttl"))
+	_ = viper.BindPFlag("http_shutdown_timeout",
+		httpCmd.Flags().Lookup("shutdown-timeout"))
+
+	httpCmd.Flags().Float64("session-rate-limit", 0,
+		"tool calls per second allowed for each session, 0 to disable")
+	httpCmd.Flags().Int("session-rate-burst", 10,
+		"most tool calls a session can make at once under its rate limit")
+	httpCmd.Flags().Float64("key-rate-limit", 0,
+		"tool calls per second allowed for each api key, across its "+
+			"sessions, 0 to disable")
+	httpCmd.Flags().Int("key-rate-burst", 20,
+		"most tool calls an api key can make at once under its rate limit")
+	httpCmd.Flags().Float64("global-rate-limit", 0,
+		"tool calls per second allowed across all sessions, 0 to disable")
+	httpCmd.Flags().Int("global-rate-burst", 50,
+		"most tool calls all sessions can make at once under the global "+
+			"rate limit")
+
+	_ = viper.BindPFlag("http_session_rate_limit",
+		httpCmd.Flags().Lookup("session-rate-limit"))
+	_ = viper.BindPFlag("http_session_rate_burst",
+		httpCmd.Flags().Lookup("session-rate-burst"))
+	_ = viper.BindPFlag("http_key_rate_limit",
+		httpCmd.Flags().Lookup("key-rate-limit"))
+	_ = viper.BindPFlag("http_key_rate_burst",
+		httpCmd.Flags().Lookup("key-rate-burst"))
+	_ = viper.BindPFlag("http_global_rate_limit",
+		httpCmd.Flags().Lookup("global-rate-limit"))
+	_ = viper.BindPFlag("http_global_rate_burst",
+		httpCmd.Flags().Lookup("global-rate-burst"))
+
+	httpCmd.Flags().String("oauth-issuer", "",
+		"oauth authorization server whose bearer tokens are required")
+	httpCmd.Flags().String("oauth-audience", "",
+		"audience oauth tokens must be issued for, defaults to the "+
+			"resource url")
+	httpCmd.Flags().String("oauth-jwks-url", "",
+		"url of the keys oauth tokens are signed with, defaults to "+
+			"/.well-known/jwks.json under the issuer")
+	httpCmd.Flags().String("oauth-resource-url", "",
+		"public url of the mcp endpoint, required with --oauth-issuer")
+
+	_ = viper.BindPFlag("oauth_issuer", httpCmd.Flags().Lookup("oauth-issuer"))
+	_ = viper.BindPFlag("oauth_audience",
+		httpCmd.Flags().Lookup("oauth-audience"))
+	_ = viper.BindPFlag("oauth_jwks_url",
+		httpCmd.Flags().Lookup("oauth-jwks-url"))
+	_ = viper.BindPFlag("oauth_resource_url",
+		httpCmd.Flags().Lookup("oauth-resource-url"))
+
+	httpCmd.Flags().String("redis-url", "",
+		"redis url, such as redis://localhost:6379/0, to share sessions, "+
+			"cached results and idempotency keys between replicas")
+
+	_ = viper.BindPFlag("redis_url", httpCmd.Flags().Lookup("redis-url"))
+
+	httpCmd.Flags().Int64("max-request-size", mcpgo.DefaultMaxRequestSize,
+		"largest request body accepted in bytes, 0 for no limit")
+	httpCmd.Flags().Int("max-result-size", 0,
+		"size in bytes above which tool results are returned in parts, 0 "+
+			"for no limit")
+
+	_ = viper.BindPFlag("http_max_request_size",
+		httpCmd.Flags().Lookup("max-request-size"))
+	_ = viper.BindPFlag("http_max_result_size",
+		httpCmd.Flags().Lookup("max-result-size"))
+
+	httpCmd.Flags().String("tls-cert", "",
+		"path to the tls certificate to serve https with, reloaded when "+
+			"it changes")
+	httpCmd.Flags().String("tls-key", "",
+		"path to the key of the tls certificate")
+	httpCmd.Flags().String("mtls-ca", "",
+		"path to the ca certificates client certificates must be signed "+
+			"by, enables mutual tls")
+
+	_ = viper.BindPFlag("http_tls_cert", httpCmd.Flags().Lookup("tls-cert"))
+	_ = viper.BindPFlag("http_tls_key", httpCmd.Flags().Lookup("tls-key"))
+	_ = viper.BindPFlag("http_mtls_ca", httpCmd.Flags().Lookup("mtls-ca"))
+
+	httpCmd.Flags().String("webhook-secret", "",
+		"secret of the razorpay webhook, enables receiving its events for "+
+			"the fetch_recent_events and wait_for_event tools")
+	httpCmd.Flags().String("webhook-path", defaultWebhookPath,
+		"path razorpay webhook events are received on")
+	httpCmd.Flags().Int("webhook-history-size", defaultWebhookHistorySize,
+		"number of latest webhook events kept")
+
+	_ = viper.BindPFlag("http_webhook_secret",
+		httpCmd.Flags().Lookup("webhook-secret"))
+	_ = viper.BindPFlag("http_webhook_path",
+		httpCmd.Flags().Lookup("webhook-path"))
+	_ = viper.BindPFlag("http_webhook_history_size",
+		httpCmd.Flags().Lookup("webhook-history-size"))
+}
+
+// setupRedis connects to the Redis server at url, returning nil if url is
+// empty
+func setupRedis(ctx context.Context, url string) (*redis.Client, error) {
+	if url == "" {
+		return nil, nil
+	}
+
+	client, err := redis.New(url)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.Ping(ctx); err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+// newRazorpayHTTPClient creates a Razorpay client for the http transport,
+// sending its requests through the shared transport, recording them in
+// metrics if set and retrying them on transient errors
+func newRazorpayHTTPClient(
+	key, secret string,
+	transport *http.Transport,
+	retry retryConfig,
+	metrics *observability.Metrics,
+) *rzpsdk.Client {
+	client := rzpsdk.NewClient(key, secret)
+
+	client.SetUserAgent("razorpay-mcp" + version + "/http")
+
+	configureTransport(client, transport)
+	configureMetrics(client, metrics)
+	configureRetries(client, retry)
+
+	return client
+}
+
+// httpContextFunc returns the function that prepares the context tool calls
+// of a request are handled with. With partner credentials, the calls of a
+// request are made on behalf of the sub-merchant account of its
+// X-Razorpay-Account header, unless they select another.
+func httpContextFunc(
+	strictParams bool,
+	partner bool,
+) func(ctx context.Context, r *http.Request) context.Context {
+	return func(ctx context.Context, r *http.Request) context.Context {
+		ctx = contextkey.WithStrictParams(ctx, strictParams)
+
+		// A request can only add to the restrictions of the server
+		if readOnly, _ := strconv.ParseBool(
+			r.Header.Get(readOnlyHeader)); readOnly {
+			ctx = contextkey.WithReadOnly(ctx, true)
+		}
+
+		if account := r.Header.Get(razorpay.MerchantAccountHeader); partner &&
+			account != "" {
+			ctx = contextkey.WithMerchantAccount(ctx, account)
+		}
+
+		return ctx
+	}
+}
+
+func runHTTPServer(
+	ctx context.Context,
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+	enabledToolsets []string,
+	enabledTools []string,
+	disabledTools []string,
+	readOnly bool,
+	config httpServerConfig,
+	mcpOpts ...mcpgo.ServerOption,
+) error {
+	ctx, stop := signal.NotifyContext(
+		ctx,
+		os.Interrupt,
+		syscall.SIGTERM,
+	)
+	defer stop()
+
+	// Keep the webhook events received for the event tools, if enabled
+	var history *events.History
+	if config.webhook.enabled() {
+		history = events.NewHistory(config.webhook.historySize)
+		mcpOpts = append(mcpOpts, razorpay.WithEventHistory(history))
+	}
+
+	srv, err := razorpay.NewRzpMcpServer(obs, client,
+		enabledToolsets, enabledTools, disabledTools, readOnly, mcpOpts...)
+	if err != nil {
+		return fmt.Errorf("failed to create server: %w", err)
+	}
+
+	// Tool calls are handled with the request context, so carry over the
+	// settings stored in the server context
+	strictParams := contextkey.StrictParamsFromContext(ctx)
+
+	httpOpts := []mcpgo.StreamableHTTPOption{
+		mcpgo.WithEndpointPath(config.endpointPath),
+		mcpgo.WithKeepAlive(config.keepAlive),
+		mcpgo.WithSessionTTL(config.sessionTTL),
+		mcpgo.WithMaxRequestSize(config.maxRequestSize),
+		mcpgo.WithHTTPContextFunc(httpContextFunc(strictParams,
+			config.partner)),
+	}
+	if config.redis != nil {
+		httpOpts = append(httpOpts, mcpgo.WithRedisSessions(config.redis))
+	}
+
+	httpSrv, err := mcpgo.NewStreamableHTTPServer(srv, httpOpts...)
+	if err != nil {
+		return fmt.Errorf("failed to create http server: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	if config.metrics != nil {
+		config.metrics.SetActiveSessionsFunc(httpSrv.ActiveSessions)
+		mux.Handle(metricsPath, config.metrics.Handler())
+	}
+	newClient := func(key, secret string) (*rzpsdk.Client, error) {
+		return newRazorpayHTTPClient(key, secret, config.transport,
+			config.retry, config.metrics), nil
+	}
+	if config.oauth.enabled() {
+		err = registerOAuthHandlers(mux, httpSrv.EndpointPath(), httpSrv,
+			config.oauth, newClient)
+		if err != nil {
+			return fmt.Errorf("failed to configure oauth: %w", err)
+		}
+	} else {
+		// Requests may carry their own credentials. Without server
+		// credentials or accounts to fall back to, they must.
+		requireCredentials := client.Request.Auth.Key == "" &&
+			!config.accounts
+
+		mux.Handle(httpSrv.EndpointPath(),
+			withRequestCredentials(httpSrv, newClient, requireCredentials))
+	}
+
+	if history != nil {
+		// Razorpay authenticates webhook requests with their signature,
+		// not with credentials or oauth tokens
+		err = registerWebhookHandler(mux, config.webhook, history,
+			httpSrv.EndpointPath(), metricsPath)
+		if err != nil {
+			return fmt.Errorf("failed to configure webhooks: %w", err)
+		}
+	}
+
+	// Event streams only end when their request context is done, so
+	// cancel all request contexts once shutdown starts
+	baseCtx, cancelRequests := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelRequests()
+
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext: func(net.Listener) context.Context {
+			return baseCtx
+		},
+	}
+	server.RegisterOnShutdown(cancelRequests)
+
+	if err := configureTLS(ctx, obs, server, config.tls); err != nil {
+		return err
+	}
+	scheme := "http"
+	if server.TLSConfig != nil {
+		scheme = "https"
+	}
+
+	listener, err := net.Listen("tcp", config.address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", config.address, err)
+	}
+
+	errC := make(chan error, 1)
+	go func() {
+		obs.Logger.Infof(ctx, "starting server")
+		errC <- serve(server, listener)
+	}()
+
+	_, _ = fmt.Fprintf(
+		os.Stderr,
+		"Razorpay MCP Server running on %s://%s%s\n",
+		scheme, listener.Addr(), httpSrv.EndpointPath(),
+	)
+
+	// Wait for shutdown signal
+	select {
+	case <-ctx.Done():
+		return shutdownHTTPServer(ctx, obs, server, httpSrv.Drain,
+			config.shutdownTimeout)
+	case err := <-errC:
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			obs.Logger.Errorf(ctx, "server error", "error", err)
+			return err
+		}
+		return nil
+	}
+}
+
+// configureTLS serves the server over TLS if configured, reloading the
+// certificates when their files change
+func configureTLS(
+	ctx context.Context,
+	obs *observability.Observability,
+	server *http.Server,
+	config tlsConfig,
+) error {
+	if !config.enabled() {
+		return nil
+	}
+
+	reloader, err := newTLSReloader(config, func(err error) {
+		obs.Logger.Errorf(ctx, "failed to reload tls files", "error", err)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to configure tls: %w", err)
+	}
+	server.TLSConfig = reloader.serverConfig()
+
+	return nil
+}
+
+// serve serves connections from the listener, over TLS if the server has
+// a TLS configuration
+func serve(server *http.Server, listener net.Listener) error {
+	if server.TLSConfig != nil {
+		return server.ServeTLS(listener, "", "")
+	}
+	return server.Serve(listener)
+}
+
+// shutdownHTTPServer stops the server from opening sessions and starting
+// tool calls, waits up to timeout for the running tool calls to finish so
+// that no refund or payout is cut off midway, and then closes the server
+func shutdownHTTPServer(
+	ctx context.Context,
+	obs *observability.Observability,
+	server *http.Server,
+	drain func(ctx context.Context) error,
+	timeout time.Duration,
+) error {
+	ctx = context.WithoutCancel(ctx)
+	obs.Logger.Infof(ctx, "shutting down server...")
+
+	drainCtx, cancelDrain := context.WithTimeout(ctx, timeout)
+	defer cancelDrain()
+	if err := drain(drainCtx); err != nil {
+		obs.Logger.Errorf(ctx, "tool calls cut off by shutdown",
+			"error", err)
+	}
+
+	// Event streams end once shutdown starts, so only short requests are
+	// left
+	closeCtx, cancelClose := context.WithTimeout(ctx, closeTimeout)
+	defer cancelClose()
+	if err := server.Shutdown(closeCtx); err != nil {
+		obs.Logger.Errorf(ctx, "closing connections on shutdown",
+			"error", err)
+		return server.Close()
+	}
+
+	return nil
+}
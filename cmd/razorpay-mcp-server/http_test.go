@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay"
+	"github.com/razorpay/razorpay-mcp-server/pkg/redis/redistest"
+)
+
+func testHTTPServerConfig() httpServerConfig {
+	return httpServerConfig{
+		address:         "127.0.0.1:0",
+		endpointPath:    mcpgo.DefaultEndpointPath,
+		keepAlive:       mcpgo.DefaultKeepAlive,
+		sessionTTL:      mcpgo.DefaultSessionTTL,
+		shutdownTimeout: time.Second,
+	}
+}
+
+func TestHTTPCmd(t *testing.T) {
+	t.Run("http command is configured correctly", func(t *testing.T) {
+		assert.NotNil(t, httpCmd)
+		assert.Equal(t, "http", httpCmd.Use)
+		assert.Equal(t, "start the streamable http server", httpCmd.Short)
+		assert.NotNil(t, httpCmd.Run)
+	})
+
+	t.Run("http command is added to root command", func(t *testing.T) {
+		found := false
+		for _, cmd := range rootCmd.Commands() {
+			if cmd == httpCmd {
+				found = true
+				break
+			}
+		}
+		assert.True(t, found, "httpCmd should be added to rootCmd")
+	})
+
+	t.Run("http command has transport flags", func(t *testing.T) {
+		for _, name := range []string{
+			"address", "endpoint-path", "keep-alive", "session-ttl", "metrics",
+		} {
+			assert.NotNil(t, httpCmd.Flags().Lookup(name), name)
+		}
+	})
+}
+
+func TestRunHTTPServer(t *testing.T) {
+	t.Run("stops on context cancellation", func(t *testing.T) {
+		ctx, cancel, obs, client := setupTestServer(t)
+		defer cancel()
+
+		errChan := make(chan error, 1)
+		go func() {
+			errChan <- runHTTPServer(ctx, obs, client, []string{}, nil, nil, false,
+				testHTTPServerConfig())
+		}()
+
+		time.Sleep(100 * time.Millisecond)
+		cancel()
+
+		select {
+		case err := <-errChan:
+			assert.NoError(t, err)
+		case <-time.After(2 * time.Second):
+			t.Fatal("server did not stop in time")
+		}
+	})
+
+	t.Run("returns error for invalid address", func(t *testing.T) {
+		ctx, cancel, obs, client := setupTestServer(t)
+		defer cancel()
+
+		config := testHTTPServerConfig()
+		config.address = "invalid-address"
+
+		err := runHTTPServer(ctx, obs, client, []string{}, nil, nil, false, config)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to listen on invalid-address")
+	})
+
+	t.Run("returns error for webhook path in use", func(t *testing.T) {
+		ctx, cancel, obs, client := setupTestServer(t)
+		defer cancel()
+
+		config := testHTTPServerConfig()
+		config.webhook = webhookConfig{
+			secret:      "webhook-secret",
+			path:        config.endpointPath,
+			historySize: defaultWebhookHistorySize,
+		}
+
+		err := runHTTPServer(ctx, obs, client, []string{}, nil, nil, false, config)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to configure webhooks")
+	})
+
+	t.Run("returns error from NewRzpMcpServer with nil obs",
+		func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			client := rzpsdk.NewClient("test-key", "test-secret")
+
+			err := runHTTPServer(ctx, nil, client, []string{}, nil, nil, false,
+				testHTTPServerConfig())
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "failed to create server")
+		})
+}
+
+func TestShutdownHTTPServer(t *testing.T) {
+	ctx, cancel, obs, _ := setupTestServer(t)
+	defer cancel()
+
+	t.Run("waits for tool calls within the timeout", func(t *testing.T) {
+		server := httptest.NewUnstartedServer(http.NotFoundHandler())
+		server.Start()
+
+		var drainCtx context.Context
+		drain := func(ctx context.Context) error {
+			drainCtx = ctx
+			return nil
+		}
+
+		err := shutdownHTTPServer(ctx, obs, server.Config, drain, time.Minute)
+		assert.NoError(t, err)
+		deadline, ok := drainCtx.Deadline()
+		assert.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), deadline,
+			time.Second)
+	})
+
+	t.Run("closes the server when tool calls are cut off",
+		func(t *testing.T) {
+			server := httptest.NewUnstartedServer(http.NotFoundHandler())
+			server.Start()
+
+			drain := func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			}
+
+			err := shutdownHTTPServer(ctx, obs, server.Config, drain,
+				time.Millisecond)
+			assert.NoError(t, err)
+			_, err = http.Get(server.URL)
+			assert.Error(t, err)
+		})
+}
+
+func TestHTTPContextFunc(t *testing.T) {
+	t.Run("sets strict params", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+
+		ctx := httpContextFunc(true, false)(context.Background(), req)
+		assert.True(t, contextkey.StrictParamsFromContext(ctx))
+		assert.False(t, contextkey.ReadOnlyFromContext(ctx))
+	})
+
+	t.Run("enables read-only mode from header", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		req.Header.Set(readOnlyHeader, "true")
+
+		ctx := httpContextFunc(false, false)(context.Background(), req)
+		assert.True(t, contextkey.ReadOnlyFromContext(ctx))
+	})
+
+	t.Run("ignores invalid header values", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		req.Header.Set(readOnlyHeader, "maybe")
+
+		ctx := httpContextFunc(false, false)(context.Background(), req)
+		assert.False(t, contextkey.ReadOnlyFromContext(ctx))
+	})
+
+	t.Run("sets the merchant account of partners", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		req.Header.Set(razorpay.MerchantAccountHeader, "acc_Ef7ArAsdU5t0XL")
+
+		ctx := httpContextFunc(false, true)(context.Background(), req)
+		assert.Equal(t, "acc_Ef7ArAsdU5t0XL",
+			contextkey.MerchantAccountFromContext(ctx))
+
+		ctx = httpContextFunc(false, false)(context.Background(), req)
+		assert.Empty(t, contextkey.MerchantAccountFromContext(ctx))
+	})
+}
+
+func TestSetupRedis(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("returns no client without a url", func(t *testing.T) {
+		client, err := setupRedis(ctx, "")
+		assert.NoError(t, err)
+		assert.Nil(t, client)
+	})
+
+	t.Run("connects to the server", func(t *testing.T) {
+		server := redistest.NewServer(t)
+
+		client, err := setupRedis(ctx, server.URL())
+		require.NoError(t, err)
+		assert.NotNil(t, client)
+		_ = client.Close()
+	})
+
+	t.Run("fails for unreachable servers", func(t *testing.T) {
+		// a closed server refuses connections
+		closed := httptest.NewServer(http.NotFoundHandler())
+		closed.Close()
+
+		_, err := setupRedis(ctx, "redis://"+closed.Listener.Addr().String())
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects invalid urls", func(t *testing.T) {
+		_, err := setupRedis(ctx, "localhost:6379")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"github.com/spf13/viper"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+	"github.com/razorpay/razorpay-mcp-server/pkg/redis"
+)
+
+// idempotencyFromViper returns the server option that keeps the results
+// of write tool calls with an idempotency key, in the file set by
+// idempotency_store, in Redis if a client is given, or in memory
+func idempotencyFromViper(
+	redisClient *redis.Client,
+) (mcpgo.ServerOption, error) {
+	ttl := viper.GetDuration("idempotency_ttl")
+	path := viper.GetString("idempotency_store")
+	if path != "" {
+		store, err := mcpgo.NewFileIdempotencyStore(path)
+		if err != nil {
+			return nil, err
+		}
+		return mcpgo.WithIdempotency(store, ttl), nil
+	}
+	if redisClient != nil {
+		return mcpgo.WithIdempotency(
+			mcpgo.NewRedisIdempotencyStore(redisClient), ttl), nil
+	}
+
+	return mcpgo.WithIdempotency(nil, ttl), nil
+}
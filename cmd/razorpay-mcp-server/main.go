@@ -2,11 +2,17 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay"
 )
 
 var (
@@ -35,18 +41,81 @@ func init() {
 	cobra.OnInitialize(initConfig)
 
 	// flags will be available for all subcommands
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "path to the yaml config file (default $HOME/.razorpay-mcp-server.yaml)")
 	rootCmd.PersistentFlags().StringP("key", "k", "", "your razorpay api key")
 	rootCmd.PersistentFlags().StringP("secret", "s", "", "your razorpay api secret")
 	rootCmd.PersistentFlags().StringP("log-file", "l", "", "path to the log file")
 	rootCmd.PersistentFlags().StringSliceP("toolsets", "t", []string{}, "comma-separated list of toolsets to enable")
+	rootCmd.PersistentFlags().StringSlice("profile", []string{}, "comma-separated list of product line profiles whose toolsets to enable, of "+strings.Join(razorpay.Profiles(), ", "))
+	rootCmd.PersistentFlags().StringSlice("enabled-tools", []string{}, "comma-separated list of tools to enable, all tools of the enabled toolsets if empty")
+	rootCmd.PersistentFlags().StringSlice("disabled-tools", []string{}, "comma-separated list of tools to disable")
 	rootCmd.PersistentFlags().Bool("read-only", false, "run server in read-only mode")
+	rootCmd.PersistentFlags().String("proxy-url", "", "proxy url for outbound razorpay api requests")
+	rootCmd.PersistentFlags().Int("api-max-idle-conns", defaultMaxIdleConns, "number of idle connections to the razorpay api kept open")
+	rootCmd.PersistentFlags().Int("api-max-idle-conns-per-host", defaultMaxIdleConns, "number of idle connections kept open per host")
+	rootCmd.PersistentFlags().Int("api-max-conns-per-host", 0, "maximum number of connections per host, requests over it wait for a connection, 0 for no limit")
+	rootCmd.PersistentFlags().Duration("api-idle-conn-timeout", defaultIdleConnTimeout, "how long an idle connection to the razorpay api is kept open")
+	rootCmd.PersistentFlags().Duration("api-dial-timeout", defaultDialTimeout, "how long connecting to the razorpay api may take")
+	rootCmd.PersistentFlags().Duration("api-tls-handshake-timeout", defaultTLSHandshakeTimeout, "how long the tls handshake with the razorpay api may take")
+	rootCmd.PersistentFlags().Duration("api-response-header-timeout", defaultResponseHeaderTimeout, "how long to wait for the response headers of a razorpay api request")
+	rootCmd.PersistentFlags().Bool("api-http2", true, "use http/2 for razorpay api requests when the server supports it")
+	rootCmd.PersistentFlags().Bool("strict-params", false, "reject tool calls with parameters not declared by the tool")
+	rootCmd.PersistentFlags().Int("max-retries", 2, "number of times razorpay api requests are retried on rate limits, server and network errors, writes only when not sent, 0 to disable")
+	rootCmd.PersistentFlags().Duration("retry-backoff", 500*time.Millisecond, "delay before the first retry of a razorpay api request, doubled for every following retry")
+	rootCmd.PersistentFlags().Duration("tool-timeout", mcpgo.DefaultToolTimeout, "how long a tool call may take before it is cancelled, 0 to disable")
+	rootCmd.PersistentFlags().Duration("cache-ttl", 0, "how long results of read-only tools are cached, 0 to disable caching")
+	rootCmd.PersistentFlags().String("audit-log", "", "path to the file calls to write tools are recorded in as json lines")
+	rootCmd.PersistentFlags().String("audit-url", "", "url calls to write tools are posted to as json audit records")
+	rootCmd.PersistentFlags().String("locale", "en", "language of tool descriptions and guidance messages, such as hi")
+	rootCmd.PersistentFlags().String("mode", "", "test or live, reject write tools called with a key of the other mode")
+	rootCmd.PersistentFlags().String("amount-unit", "paise", "unit of the amounts of tool calls that pass no amount_unit, paise or rupees")
+	rootCmd.PersistentFlags().Bool("confirm-high-risk", false, "ask the user to confirm large refunds, instant settlements and token revocations")
+	rootCmd.PersistentFlags().Int64("confirm-refund-above", 0, "refund amount in currency subunits above which refunds need confirmation, 0 to confirm every refund")
+	rootCmd.PersistentFlags().String("generated-email-domain", "mcp.razorpay.com", "domain of the emails generated from the contact number of customers that pay without an email")
+	rootCmd.PersistentFlags().Bool("disable-generated-email", false, "create payments without an email when the customer gives none, instead of generating one")
+	rootCmd.PersistentFlags().Bool("partner", false, "the key is a partner key: tools accept a merchant_account_id to act on behalf of a sub-merchant")
+	rootCmd.PersistentFlags().String("default-account", "", "account of the accounts of the config file that tool calls selecting none are made with, instead of --key and --secret")
+	rootCmd.PersistentFlags().String("idempotency-store", "", "path to the file results of write tool calls with an idempotency key are kept in, in memory if empty")
+	rootCmd.PersistentFlags().Duration("idempotency-ttl", mcpgo.DefaultIdempotencyTTL, "how long results of write tool calls are kept under their idempotency key")
+	rootCmd.PersistentFlags().Bool("dry-run", false, "return the razorpay api requests write tools would make without sending them")
 
 	// bind flags to viper
 	_ = viper.BindPFlag("key", rootCmd.PersistentFlags().Lookup("key"))
 	_ = viper.BindPFlag("secret", rootCmd.PersistentFlags().Lookup("secret"))
 	_ = viper.BindPFlag("log_file", rootCmd.PersistentFlags().Lookup("log-file"))
 	_ = viper.BindPFlag("toolsets", rootCmd.PersistentFlags().Lookup("toolsets"))
+	_ = viper.BindPFlag("profile", rootCmd.PersistentFlags().Lookup("profile"))
+	_ = viper.BindPFlag("enabled_tools", rootCmd.PersistentFlags().Lookup("enabled-tools"))
+	_ = viper.BindPFlag("disabled_tools", rootCmd.PersistentFlags().Lookup("disabled-tools"))
 	_ = viper.BindPFlag("read_only", rootCmd.PersistentFlags().Lookup("read-only"))
+	_ = viper.BindPFlag("proxy_url", rootCmd.PersistentFlags().Lookup("proxy-url"))
+	_ = viper.BindPFlag("api_max_idle_conns", rootCmd.PersistentFlags().Lookup("api-max-idle-conns"))
+	_ = viper.BindPFlag("api_max_idle_conns_per_host", rootCmd.PersistentFlags().Lookup("api-max-idle-conns-per-host"))
+	_ = viper.BindPFlag("api_max_conns_per_host", rootCmd.PersistentFlags().Lookup("api-max-conns-per-host"))
+	_ = viper.BindPFlag("api_idle_conn_timeout", rootCmd.PersistentFlags().Lookup("api-idle-conn-timeout"))
+	_ = viper.BindPFlag("api_dial_timeout", rootCmd.PersistentFlags().Lookup("api-dial-timeout"))
+	_ = viper.BindPFlag("api_tls_handshake_timeout", rootCmd.PersistentFlags().Lookup("api-tls-handshake-timeout"))
+	_ = viper.BindPFlag("api_response_header_timeout", rootCmd.PersistentFlags().Lookup("api-response-header-timeout"))
+	_ = viper.BindPFlag("api_http2", rootCmd.PersistentFlags().Lookup("api-http2"))
+	_ = viper.BindPFlag("strict_params", rootCmd.PersistentFlags().Lookup("strict-params"))
+	_ = viper.BindPFlag("max_retries", rootCmd.PersistentFlags().Lookup("max-retries"))
+	_ = viper.BindPFlag("retry_backoff", rootCmd.PersistentFlags().Lookup("retry-backoff"))
+	_ = viper.BindPFlag("tool_timeout", rootCmd.PersistentFlags().Lookup("tool-timeout"))
+	_ = viper.BindPFlag("cache_ttl", rootCmd.PersistentFlags().Lookup("cache-ttl"))
+	_ = viper.BindPFlag("audit_log", rootCmd.PersistentFlags().Lookup("audit-log"))
+	_ = viper.BindPFlag("audit_url", rootCmd.PersistentFlags().Lookup("audit-url"))
+	_ = viper.BindPFlag("locale", rootCmd.PersistentFlags().Lookup("locale"))
+	_ = viper.BindPFlag("mode", rootCmd.PersistentFlags().Lookup("mode"))
+	_ = viper.BindPFlag("amount_unit", rootCmd.PersistentFlags().Lookup("amount-unit"))
+	_ = viper.BindPFlag("confirm_high_risk", rootCmd.PersistentFlags().Lookup("confirm-high-risk"))
+	_ = viper.BindPFlag("confirm_refund_above", rootCmd.PersistentFlags().Lookup("confirm-refund-above"))
+	_ = viper.BindPFlag("generated_email_domain", rootCmd.PersistentFlags().Lookup("generated-email-domain"))
+	_ = viper.BindPFlag("disable_generated_email", rootCmd.PersistentFlags().Lookup("disable-generated-email"))
+	_ = viper.BindPFlag("default_account", rootCmd.PersistentFlags().Lookup("default-account"))
+	_ = viper.BindPFlag("partner", rootCmd.PersistentFlags().Lookup("partner"))
+	_ = viper.BindPFlag("idempotency_store", rootCmd.PersistentFlags().Lookup("idempotency-store"))
+	_ = viper.BindPFlag("idempotency_ttl", rootCmd.PersistentFlags().Lookup("idempotency-ttl"))
+	_ = viper.BindPFlag("dry_run", rootCmd.PersistentFlags().Lookup("dry-run"))
 
 	// Set environment variable mappings
 	_ = viper.BindEnv("key", "RAZORPAY_KEY_ID")        // Maps RAZORPAY_KEY_ID to key
@@ -57,6 +126,7 @@ func init() {
 
 	// subcommands
 	rootCmd.AddCommand(stdioCmd)
+	rootCmd.AddCommand(httpCmd)
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -74,9 +144,18 @@ func initConfig() {
 
 	viper.AutomaticEnv()
 
-	if err := viper.ReadInConfig(); err == nil {
+	err := viper.ReadInConfig()
+	if err == nil {
 		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
 	}
+
+	// A config file that exists but can not be parsed is an error, rather
+	// than being silently ignored
+	var parseErr viper.ConfigParseError
+	if errors.As(err, &parseErr) {
+		cobra.CheckErr(fmt.Errorf("invalid config file %s: %w",
+			viper.ConfigFileUsed(), err))
+	}
 }
 
 func main() {
@@ -94,6 +94,12 @@ func TestRootCmdFlags(t *testing.T) {
 		toolsetsFlag := rootCmd.PersistentFlags().Lookup("toolsets")
 		assert.NotNil(t, toolsetsFlag)
 
+		enabledToolsFlag := rootCmd.PersistentFlags().Lookup("enabled-tools")
+		assert.NotNil(t, enabledToolsFlag)
+
+		disabledToolsFlag := rootCmd.PersistentFlags().Lookup("disabled-tools")
+		assert.NotNil(t, disabledToolsFlag)
+
 		readOnlyFlag := rootCmd.PersistentFlags().Lookup("read-only")
 		assert.NotNil(t, readOnlyFlag)
 	})
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"net/http"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+)
+
+// metricsPath is the path metrics are served on
+const metricsPath = "/metrics"
+
+// configureMetrics records the requests of the Razorpay client in metrics.
+// It wraps the transport of the client, so it is applied after
+// configureTransport and before configureRetries, to record every attempt.
+func configureMetrics(client *rzpsdk.Client, metrics *observability.Metrics) {
+	if metrics == nil {
+		return
+	}
+
+	httpClient := &http.Client{}
+	if current := client.Request.HTTPClient; current != nil {
+		clone := *current
+		httpClient = &clone
+	}
+
+	next := httpClient.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	httpClient.Transport = metrics.Transport(next)
+	client.Request.HTTPClient = httpClient
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+)
+
+func TestConfigureMetrics(t *testing.T) {
+	t.Run("records every attempt of razorpay api calls", func(t *testing.T) {
+		server, _ := newFlakyServer(t, nil, http.StatusServiceUnavailable)
+		metrics := observability.NewMetrics()
+		client := newRazorpayHTTPClient("test-key", "test-secret", nil,
+			retryConfig{maxRetries: 1}, metrics)
+		client.Request.BaseURL = server.URL
+
+		_, err := client.Payment.Fetch("pay_29QQoUBi66xm2f", nil, nil)
+		require.NoError(t, err)
+
+		body := scrapeMetrics(t, metrics)
+		assert.Contains(t, body,
+			`razorpay_mcp_api_requests_total{method="GET",code="200"} 1`)
+		assert.Contains(t, body,
+			`razorpay_mcp_api_requests_total{method="GET",code="503"} 1`)
+	})
+
+	t.Run("keeps the proxy transport", func(t *testing.T) {
+		client := rzpsdk.NewClient("test-key", "test-secret")
+		transport, err := newTransport(transportConfig{},
+			"http://proxy.internal:3128")
+		require.NoError(t, err)
+		configureTransport(client, transport)
+		proxyClient := client.Request.HTTPClient
+
+		configureMetrics(client, observability.NewMetrics())
+
+		assert.NotSame(t, proxyClient, client.Request.HTTPClient)
+		assert.Equal(t, proxyClient.Timeout, client.Request.HTTPClient.Timeout)
+		assert.NotEqual(t, proxyClient.Transport,
+			client.Request.HTTPClient.Transport)
+	})
+
+	t.Run("nil metrics leaves client untouched", func(t *testing.T) {
+		client := rzpsdk.NewClient("test-key", "test-secret")
+		original := client.Request.HTTPClient
+
+		configureMetrics(client, nil)
+		assert.Same(t, original, client.Request.HTTPClient)
+	})
+}
+
+// scrapeMetrics returns the metrics as served on the metrics endpoint
+func scrapeMetrics(t *testing.T, metrics *observability.Metrics) string {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(recorder,
+		httptest.NewRequest(http.MethodGet, metricsPath, nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	return strings.TrimSpace(recorder.Body.String())
+}
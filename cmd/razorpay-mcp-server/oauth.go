@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/oauth"
+	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay"
+)
+
+// oauthLeeway is the clock skew allowed when checking token lifetimes
+const oauthLeeway = 30 * time.Second
+
+// oauthConfig holds the settings of OAuth bearer token authentication
+type oauthConfig struct {
+	issuer      string
+	audience    string
+	jwksURL     string
+	resourceURL string
+	// accounts are the Razorpay accounts requests are made with, keyed by
+	// the subject of their token
+	accounts map[string]razorpay.AccountConfig
+}
+
+// enabled reports whether OAuth authentication is configured
+func (c oauthConfig) enabled() bool {
+	return c.issuer != ""
+}
+
+// registerOAuthHandlers serves the mcp endpoint only to requests with a
+// valid OAuth bearer token, which are then made with the Razorpay account
+// of the subject of the token, and serves the metadata clients discover
+// the authorization server from. Tokens are issued for this server, so
+// they are never sent on to the Razorpay API.
+func registerOAuthHandlers(
+	mux *http.ServeMux,
+	endpointPath string,
+	next http.Handler,
+	config oauthConfig,
+	newClient func(key, secret string) (*rzpsdk.Client, error),
+) error {
+	if config.resourceURL == "" {
+		return errors.New("oauth resource url is required")
+	}
+	if len(config.accounts) == 0 {
+		return errors.New("oauth requires accounts with oauth_subjects " +
+			"in the config file")
+	}
+
+	clients := make(map[string]*rzpsdk.Client, len(config.accounts))
+	for subject, account := range config.accounts {
+		client, err := newClient(account.Key, account.Secret)
+		if err != nil {
+			return err
+		}
+		clients[subject] = client
+	}
+
+	// Tokens must be issued for this server unless configured otherwise
+	audience := config.audience
+	if audience == "" {
+		audience = config.resourceURL
+	}
+
+	validator, err := oauth.NewValidator(oauth.Config{
+		Issuer:   config.issuer,
+		Audience: audience,
+		JWKSURL:  config.jwksURL,
+		Leeway:   oauthLeeway,
+	})
+	if err != nil {
+		return err
+	}
+
+	metadataURL, err := oauth.MetadataURL(config.resourceURL)
+	if err != nil {
+		return err
+	}
+	parsed, err := url.Parse(metadataURL)
+	if err != nil {
+		return fmt.Errorf("invalid metadata url: %w", err)
+	}
+
+	metadata := oauth.ProtectedResourceMetadataHandler(
+		config.resourceURL, config.issuer)
+	mux.Handle(parsed.Path, metadata)
+	if parsed.Path != oauth.ProtectedResourceMetadataPath {
+		mux.Handle(oauth.ProtectedResourceMetadataPath, metadata)
+	}
+
+	mux.Handle(endpointPath, oauth.Middleware(validator, metadataURL,
+		withBearerClient(next, clients)))
+
+	return nil
+}
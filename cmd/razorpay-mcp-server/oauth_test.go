@@ -0,0 +1,168 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
+	"github.com/razorpay/razorpay-mcp-server/pkg/oauth"
+	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay"
+)
+
+func testOAuthConfig() oauthConfig {
+	return oauthConfig{
+		issuer:      "https://auth.example.com",
+		resourceURL: "https://mcp.example.com/mcp",
+		accounts: map[string]razorpay.AccountConfig{
+			"user_1": {Key: "rzp_test_oauth", Secret: "oauth-secret"},
+		},
+	}
+}
+
+func TestRegisterOAuthHandlers(t *testing.T) {
+	newClient := func(key, secret string) (*rzpsdk.Client, error) {
+		return newRazorpayHTTPClient(key, secret, nil, retryConfig{}, nil),
+			nil
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	t.Run("serves the protected resource metadata", func(t *testing.T) {
+		mux := http.NewServeMux()
+		err := registerOAuthHandlers(mux, "/mcp", next, testOAuthConfig(),
+			newClient)
+		require.NoError(t, err)
+
+		for _, path := range []string{
+			oauth.ProtectedResourceMetadataPath + "/mcp",
+			oauth.ProtectedResourceMetadataPath,
+		} {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+			require.Equal(t, http.StatusOK, w.Code, path)
+
+			var metadata map[string]interface{}
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&metadata))
+			assert.Equal(t, "https://mcp.example.com/mcp",
+				metadata["resource"])
+		}
+	})
+
+	t.Run("requires a bearer token on the endpoint", func(t *testing.T) {
+		mux := http.NewServeMux()
+		err := registerOAuthHandlers(mux, "/mcp", next, testOAuthConfig(),
+			newClient)
+		require.NoError(t, err)
+
+		r := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		r.SetBasicAuth("rzp_test_key", "test_secret")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Contains(t, w.Header().Get("WWW-Authenticate"),
+			"https://mcp.example.com"+oauth.ProtectedResourceMetadataPath+
+				"/mcp")
+	})
+
+	t.Run("requires a resource url", func(t *testing.T) {
+		config := testOAuthConfig()
+		config.resourceURL = ""
+
+		err := registerOAuthHandlers(http.NewServeMux(), "/mcp", next,
+			config, newClient)
+		assert.EqualError(t, err, "oauth resource url is required")
+	})
+
+	t.Run("requires accounts for the subjects of tokens",
+		func(t *testing.T) {
+			config := testOAuthConfig()
+			config.accounts = nil
+
+			err := registerOAuthHandlers(http.NewServeMux(), "/mcp", next,
+				config, newClient)
+			assert.EqualError(t, err, "oauth requires accounts with "+
+				"oauth_subjects in the config file")
+		})
+
+	t.Run("rejects a relative resource url", func(t *testing.T) {
+		config := testOAuthConfig()
+		config.resourceURL = "/mcp"
+
+		err := registerOAuthHandlers(http.NewServeMux(), "/mcp", next,
+			config, newClient)
+		assert.Error(t, err)
+	})
+}
+
+func TestWithBearerClient(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			authorization = r.Header.Get("Authorization")
+			_, _ = w.Write([]byte(`{"id":"pay_Aa00000000001"}`))
+		}))
+	defer server.Close()
+
+	accountClient := newRazorpayHTTPClient("rzp_test_oauth", "oauth-secret",
+		nil, retryConfig{}, nil)
+	accountClient.Request.BaseURL = server.URL
+	clients := map[string]*rzpsdk.Client{"user_1": accountClient}
+
+	var client *rzpsdk.Client
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client, _ = contextkey.ClientFromContext(r.Context()).(*rzpsdk.Client)
+		_, err := client.Payment.Fetch("pay_Aa00000000001", nil, nil)
+		require.NoError(t, err)
+	})
+	handler := withBearerClient(next, clients)
+
+	// serve serves a request authenticated with a token of the subject
+	serve := func(subject string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		r = r.WithContext(oauth.WithClaims(r.Context(), &oauth.Claims{
+			Subject: subject,
+			Token:   "access-token",
+		}))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("calls the API with the account of the subject",
+		func(t *testing.T) {
+			w := serve("user_1")
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Same(t, accountClient, client)
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.SetBasicAuth("rzp_test_oauth", "oauth-secret")
+			assert.Equal(t, r.Header.Get("Authorization"), authorization)
+			assert.NotContains(t, authorization, "access-token")
+		})
+
+	t.Run("forbids subjects without an account", func(t *testing.T) {
+		client = nil
+		w := serve("user_2")
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "no razorpay account is "+
+			"configured for the subject of the token")
+		assert.Nil(t, client)
+	})
+
+	t.Run("rejects unauthenticated requests", func(t *testing.T) {
+		client = nil
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/mcp", nil))
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Nil(t, client)
+	})
+}
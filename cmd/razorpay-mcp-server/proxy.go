@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// parseProxyURL validates the proxy URL and returns the parsed value.
+// Only http, https and socks5 proxies are supported.
+func parseProxyURL(proxyURL string) (*url.URL, error) {
+	parsed, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy url: %w", err)
+	}
+
+	switch parsed.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf(
+			"invalid proxy url: unsupported scheme %q", parsed.Scheme)
+	}
+
+	if parsed.Host == "" {
+		return nil, fmt.Errorf("invalid proxy url: missing host")
+	}
+
+	return parsed, nil
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+	"github.com/razorpay/razorpay-go/requests"
+	"github.com/spf13/viper"
+)
+
+// maxRetryDelay caps the delay before a retry, including delays asked for
+// with a Retry-After header
+const maxRetryDelay = 10 * time.Second
+
+// retryConfig holds the settings of retries of Razorpay API requests
+type retryConfig struct {
+	// maxRetries is the number of times a request is retried, 0 disables
+	// retries
+	maxRetries int
+	// backoff is the delay before the first retry, doubled for every
+	// following retry
+	backoff time.Duration
+}
+
+// retryConfigFromViper returns the retry settings from the config
+func retryConfigFromViper() retryConfig {
+	return retryConfig{
+		maxRetries: viper.GetInt("max_retries"),
+		backoff:    viper.GetDuration("retry_backoff"),
+	}
+}
+
+// retryTransport retries requests that fail with a network error, are rate
+// limited or hit a Razorpay server error, with exponential backoff and full
+// jitter. The payments API ignores idempotency keys, so a write that may
+// have reached Razorpay is never sent again: writes are only retried when
+// rate limited, or when the connection could not be made.
+type retryTransport struct {
+	next   http.RoundTripper
+	config retryConfig
+	// sleep waits before a retry unless ctx is done first, and is replaced
+	// in tests
+	sleep func(ctx context.Context, delay time.Duration) error
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.next.RoundTrip(req)
+		if attempt >= t.config.maxRetries ||
+			!retryable(req.Method, resp, err) {
+			return resp, err
+		}
+
+		// The body can only be sent again if it can be rewound
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, err
+			}
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return resp, err
+			}
+			req.Body = body
+		}
+
+		// Requests of a cancelled tool call are not retried
+		if req.Context().Err() != nil {
+			return resp, err
+		}
+
+		delay := t.delay(attempt, resp)
+		if resp != nil {
+			resp.Body.Close()
+		}
+		if err := t.sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// sleepContext waits for delay, or until ctx is done
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// delay returns how long to wait before retrying after the given attempt,
+// honoring a Retry-After header in seconds
+func (t *retryTransport) delay(
+	attempt int,
+	resp *http.Response,
+) time.Duration {
+	if resp != nil {
+		retryAfter := resp.Header.Get("Retry-After")
+		if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+			return min(time.Duration(seconds)*time.Second, maxRetryDelay)
+		}
+	}
+
+	backoff := min(t.config.backoff<<attempt, maxRetryDelay)
+	if backoff <= 0 {
+		return 0
+	}
+	return rand.N(backoff) + 1
+}
+
+// retryable reports whether a request with the method that got the
+// response or error is worth retrying, and safe to send again. Writes are
+// only safe to send again if Razorpay did not run them.
+func retryable(method string, resp *http.Response, err error) bool {
+	if err != nil {
+		return !isWriteMethod(method) || !requestSent(err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return !isWriteMethod(method)
+	default:
+		return false
+	}
+}
+
+// requestSent reports whether a request that failed with the error may
+// have been sent. Only requests that failed to connect were not.
+func requestSent(err error) bool {
+	var opErr *net.OpError
+	return !errors.As(err, &opErr) || opErr.Op != "dial"
+}
+
+// isWriteMethod reports whether requests with the method change state
+func isWriteMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	default:
+		return true
+	}
+}
+
+// configureRetries makes the Razorpay client retry failed requests. It wraps
+// the transport of the client, so it is applied after configureTransport.
+func configureRetries(client *rzpsdk.Client, config retryConfig) {
+	if config.maxRetries <= 0 {
+		return
+	}
+
+	next := http.DefaultTransport
+	if current := client.Request.HTTPClient; current != nil &&
+		current.Transport != nil {
+		next = current.Transport
+	}
+
+	// The timeout covers all attempts of a request and the delays between
+	// them
+	attemptTimeout := time.Duration(requests.TIMEOUT) * time.Second
+	timeout := attemptTimeout*time.Duration(config.maxRetries+1) +
+		maxRetryDelay*time.Duration(config.maxRetries)
+
+	client.Request.HTTPClient = &http.Client{
+		Timeout: timeout,
+		Transport: &retryTransport{
+			next:   next,
+			config: config,
+			sleep:  sleepContext,
+		},
+	}
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+)
+
+// recordedRequest is a request seen by the test server
+type recordedRequest struct {
+	body string
+}
+
+// newFlakyServer returns a server that answers with the given statuses in
+// turn, and then with 200, recording the requests it receives
+func newFlakyServer(
+	t *testing.T,
+	headers http.Header,
+	statuses ...int,
+) (*httptest.Server, *[]recordedRequest) {
+	var mu sync.Mutex
+	var requests []recordedRequest
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			body, _ := io.ReadAll(r.Body)
+			requests = append(requests, recordedRequest{body: string(body)})
+
+			status := http.StatusOK
+			if len(requests) <= len(statuses) {
+				status = statuses[len(requests)-1]
+				for key, values := range headers {
+					w.Header()[key] = values
+				}
+			}
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(`{"id":"pay_29QQoUBi66xm2f"}`))
+		}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+// newRetryClient returns an http client that retries with the config, and
+// the delays it waited before retries
+func newRetryClient(config retryConfig) (*http.Client, *[]time.Duration) {
+	var delays []time.Duration
+	return &http.Client{
+		Transport: &retryTransport{
+			next:   http.DefaultTransport,
+			config: config,
+			sleep: func(ctx context.Context, delay time.Duration) error {
+				delays = append(delays, delay)
+				return nil
+			},
+		},
+	}, &delays
+}
+
+func TestRetryTransport(t *testing.T) {
+	config := retryConfig{maxRetries: 3, backoff: 100 * time.Millisecond}
+
+	t.Run("retries rate limits and server errors", func(t *testing.T) {
+		server, requests := newFlakyServer(t, nil,
+			http.StatusTooManyRequests, http.StatusServiceUnavailable)
+		client, delays := newRetryClient(config)
+
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Len(t, *requests, 3)
+		require.Len(t, *delays, 2)
+		assert.LessOrEqual(t, (*delays)[0], 100*time.Millisecond)
+		assert.LessOrEqual(t, (*delays)[1], 200*time.Millisecond)
+		for _, delay := range *delays {
+			assert.Positive(t, delay)
+		}
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		server, requests := newFlakyServer(t, nil, http.StatusBadRequest)
+		client, delays := newRetryClient(config)
+
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Len(t, *requests, 1)
+		assert.Empty(t, *delays)
+	})
+
+	t.Run("returns the last response once retries run out", func(t *testing.T) {
+		server, requests := newFlakyServer(t, nil,
+			http.StatusBadGateway, http.StatusBadGateway,
+			http.StatusBadGateway, http.StatusBadGateway)
+		client, _ := newRetryClient(config)
+
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+		assert.Len(t, *requests, 4)
+	})
+
+	t.Run("honors Retry-After", func(t *testing.T) {
+		server, _ := newFlakyServer(t,
+			http.Header{"Retry-After": []string{"3"}},
+			http.StatusServiceUnavailable)
+		client, delays := newRetryClient(config)
+
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, []time.Duration{3 * time.Second}, *delays)
+	})
+
+	t.Run("stops retrying once the request is cancelled", func(t *testing.T) {
+		server, requests := newFlakyServer(t, nil,
+			http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+		ctx, cancel := context.WithCancel(context.Background())
+		client := &http.Client{Transport: &retryTransport{
+			next:   http.DefaultTransport,
+			config: config,
+			sleep:  sleepContext,
+		}}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+			server.URL, nil)
+		require.NoError(t, err)
+
+		cancel()
+		_, err = client.Do(req)
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, *requests)
+
+		// A tool call cancelled while waiting to retry stops waiting
+		ctx, cancel = context.WithCancel(context.Background())
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet,
+			server.URL, nil)
+		require.NoError(t, err)
+		client.Transport.(*retryTransport).sleep = func(
+			ctx context.Context,
+			delay time.Duration,
+		) error {
+			cancel()
+			return sleepContext(ctx, time.Hour)
+		}
+		_, err = client.Do(req)
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Len(t, *requests, 1)
+	})
+
+	t.Run("does not resend writes after a server error", func(t *testing.T) {
+		server, requests := newFlakyServer(t, nil,
+			http.StatusInternalServerError, http.StatusServiceUnavailable)
+		client, delays := newRetryClient(config)
+
+		resp, err := client.Post(server.URL, "application/json",
+			bytes.NewBufferString(`{"amount":100}`))
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		assert.Len(t, *requests, 1)
+		assert.Empty(t, *delays)
+	})
+
+	t.Run("resends rate limited writes", func(t *testing.T) {
+		server, requests := newFlakyServer(t, nil,
+			http.StatusTooManyRequests)
+		client, _ := newRetryClient(config)
+
+		resp, err := client.Post(server.URL, "application/json",
+			bytes.NewBufferString(`{"amount":100}`))
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Len(t, *requests, 2)
+		assert.Equal(t, `{"amount":100}`, (*requests)[0].body)
+		assert.Equal(t, `{"amount":100}`, (*requests)[1].body)
+	})
+
+	t.Run("resends writes that could not connect", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+		client, delays := newRetryClient(config)
+
+		_, err := client.Post(server.URL, "application/json",
+			bytes.NewBufferString(`{"amount":100}`))
+		require.Error(t, err)
+
+		assert.Len(t, *delays, config.maxRetries)
+	})
+
+	t.Run("does not resend writes that failed once sent", func(t *testing.T) {
+		var mu sync.Mutex
+		received := 0
+		server := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				received++
+				mu.Unlock()
+				conn, _, err := w.(http.Hijacker).Hijack()
+				require.NoError(t, err)
+				conn.Close()
+			}))
+		t.Cleanup(server.Close)
+		client, delays := newRetryClient(config)
+
+		_, err := client.Post(server.URL, "application/json",
+			bytes.NewBufferString(`{"amount":100}`))
+		require.Error(t, err)
+
+		assert.Equal(t, 1, received)
+		assert.Empty(t, *delays)
+	})
+}
+
+func TestConfigureRetries(t *testing.T) {
+	t.Run("wraps the transport of the client", func(t *testing.T) {
+		client := rzpsdk.NewClient("test-key", "test-secret")
+		transport, err := newTransport(transportConfig{},
+			"http://proxy.internal:3128")
+		require.NoError(t, err)
+		configureTransport(client, transport)
+		proxyTransport := client.Request.HTTPClient.Transport
+
+		configureRetries(client, retryConfig{maxRetries: 2})
+
+		retry, ok := client.Request.HTTPClient.Transport.(*retryTransport)
+		require.True(t, ok)
+		assert.Same(t, proxyTransport, retry.next)
+		assert.Equal(t, 2, retry.config.maxRetries)
+		assert.Equal(t, 50*time.Second, client.Request.HTTPClient.Timeout)
+	})
+
+	t.Run("zero retries leaves client untouched", func(t *testing.T) {
+		client := rzpsdk.NewClient("test-key", "test-secret")
+		original := client.Request.HTTPClient
+
+		configureRetries(client, retryConfig{})
+		assert.Same(t, original, client.Request.HTTPClient)
+	})
+
+	t.Run("retries razorpay api calls", func(t *testing.T) {
+		server, requests := newFlakyServer(t, nil,
+			http.StatusServiceUnavailable)
+		client := rzpsdk.NewClient("test-key", "test-secret")
+		client.Request.BaseURL = server.URL
+		configureRetries(client, retryConfig{maxRetries: 1})
+
+		payment, err := client.Payment.Fetch("pay_29QQoUBi66xm2f", nil, nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, "pay_29QQoUBi66xm2f", payment["id"])
+		assert.Len(t, *requests, 2)
+	})
+}
@@ -15,6 +15,7 @@ import (
 
 	rzpsdk "github.com/razorpay/razorpay-go"
 
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
 	"github.com/razorpay/razorpay-mcp-server/pkg/log"
 	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
 	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
@@ -36,10 +37,21 @@ var stdioCmd = &cobra.Command{
 
 		ctx, logger := log.New(context.Background(), config)
 
+		// Trace tool calls if an OTLP endpoint is configured
+		tracer, shutdownTracing, err := setupTracing(ctx)
+		if err != nil {
+			stdlog.Fatalf("failed to set up tracing: %v", err)
+		}
+		defer func() { _ = shutdownTracing(context.WithoutCancel(ctx)) }()
+
 		// Create observability with SSE mode
-		obs := observability.New(
+		obsOpts := []observability.Option{
 			observability.WithLoggingService(logger),
-		)
+		}
+		if tracer != nil {
+			obsOpts = append(obsOpts, observability.WithTracer(tracer))
+		}
+		obs := observability.New(obsOpts...)
 
 		key := viper.GetString("key")
 		secret := viper.GetString("secret")
@@ -47,13 +59,62 @@ var stdioCmd = &cobra.Command{
 
 		client.SetUserAgent("razorpay-mcp" + version + "/stdio")
 
+		// Record calls to write tools if an audit log is configured
+		auditLogger, closeAuditLog, err := setupAuditLog(ctx, obs,
+			viper.GetString("audit_log"), viper.GetString("audit_url"), key)
+		if err != nil {
+			stdlog.Fatalf("failed to set up audit log: %v", err)
+		}
+		defer func() { _ = closeAuditLog() }()
+
+		// Send outbound requests over pooled connections, through a proxy
+		// if configured
+		transport, err := newTransport(transportConfigFromViper(),
+			viper.GetString("proxy_url"))
+		if err != nil {
+			stdlog.Fatalf("failed to configure proxy: %v", err)
+		}
+		configureTransport(client, transport)
+
+		// Retry requests that fail with transient errors
+		configureRetries(client, retryConfigFromViper())
+
 		// Get toolsets to enable from config
 		enabledToolsets := viper.GetStringSlice("toolsets")
 
+		// Get individual tools to enable and disable from config
+		enabledTools := viper.GetStringSlice("enabled_tools")
+		disabledTools := viper.GetStringSlice("disabled_tools")
+
+		// Get the toolset and tool settings from config
+		serverConfig, err := serverConfigFromViper()
+		if err != nil {
+			stdlog.Fatalf("failed to load config: %v", err)
+		}
+
+		// Keep the results of write tool calls with an idempotency key
+		idempotency, err := idempotencyFromViper(nil)
+		if err != nil {
+			stdlog.Fatalf("failed to set up idempotency store: %v", err)
+		}
+
 		// Get read-only mode from config
 		readOnly := viper.GetBool("read_only")
 
-		err := runStdioServer(ctx, obs, client, enabledToolsets, readOnly)
+		// Reject undeclared tool parameters if strict mode is enabled
+		ctx = contextkey.WithStrictParams(ctx, viper.GetBool("strict_params"))
+
+		// Cache results of read-only tools if a cache TTL is configured
+		cacheTTL := viper.GetDuration("cache_ttl")
+
+		err = runStdioServer(ctx, obs, client,
+			enabledToolsets, enabledTools, disabledTools, readOnly,
+			mcpgo.WithCacheTTL(cacheTTL),
+			mcpgo.WithToolTimeout(viper.GetDuration("tool_timeout")),
+			mcpgo.WithAuditLog(auditLogger),
+			mcpgo.WithDryRun(viper.GetBool("dry_run")),
+			idempotency,
+			razorpay.WithConfig(serverConfig))
 		if err != nil {
 			obs.Logger.Errorf(ctx,
 				"error running stdio server", "error", err)
@@ -67,7 +128,10 @@ func runStdioServer(
 	obs *observability.Observability,
 	client *rzpsdk.Client,
 	enabledToolsets []string,
+	enabledTools []string,
+	disabledTools []string,
 	readOnly bool,
+	mcpOpts ...mcpgo.ServerOption,
 ) error {
 	ctx, stop := signal.NotifyContext(
 		ctx,
@@ -76,7 +140,8 @@ func runStdioServer(
 	)
 	defer stop()
 
-	srv, err := razorpay.NewRzpMcpServer(obs, client, enabledToolsets, readOnly)
+	srv, err := razorpay.NewRzpMcpServer(obs, client,
+		enabledToolsets, enabledTools, disabledTools, readOnly, mcpOpts...)
 	if err != nil {
 		return fmt.Errorf("failed to create server: %w", err)
 	}
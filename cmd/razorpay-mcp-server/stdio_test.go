@@ -59,7 +59,7 @@ func runServerAndCancel(
 	t.Helper()
 	errChan := make(chan error, 1)
 	go func() {
-		errChan <- runStdioServer(ctx, obs, client, toolsets, readOnly)
+		errChan <- runStdioServer(ctx, obs, client, toolsets, nil, nil, readOnly)
 	}()
 	cancel()
 	select {
@@ -92,7 +92,8 @@ func TestRunStdioServer(t *testing.T) {
 		defer stop()
 		errChan := make(chan error, 1)
 		go func() {
-			errChan <- runStdioServer(signalCtx, obs, client, []string{}, false)
+			errChan <- runStdioServer(
+				signalCtx, obs, client, []string{}, nil, nil, false)
 		}()
 		time.Sleep(100 * time.Millisecond)
 		stop()
@@ -150,7 +151,7 @@ func TestRunStdioServer(t *testing.T) {
 		// Pass nil observability to trigger error
 		client := rzpsdk.NewClient("test-key", "test-secret")
 
-		err := runStdioServer(ctx, nil, client, []string{}, false)
+		err := runStdioServer(ctx, nil, client, []string{}, nil, nil, false)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to create server")
 	})
@@ -166,7 +167,7 @@ func TestRunStdioServer(t *testing.T) {
 			obs := observability.New(observability.WithLoggingService(logger))
 
 			// Pass nil client to trigger error
-			err := runStdioServer(ctx, obs, nil, []string{}, false)
+			err := runStdioServer(ctx, obs, nil, []string{}, nil, nil, false)
 			assert.Error(t, err)
 			assert.Contains(t, err.Error(), "failed to create server")
 		})
@@ -239,7 +240,7 @@ func TestStdioServerIO(t *testing.T) {
 		// Run server briefly
 		errChan := make(chan error, 1)
 		go func() {
-			errChan <- runStdioServer(ctx, obs, client, []string{}, false)
+			errChan <- runStdioServer(ctx, obs, client, []string{}, nil, nil, false)
 		}()
 
 		cancel()
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// tlsConfig holds the TLS settings of the http transport
+type tlsConfig struct {
+	// certFile and keyFile hold the server certificate and its key
+	certFile string
+	keyFile  string
+	// clientCAFile holds the CAs client certificates must be signed by,
+	// and enables mutual TLS if set
+	clientCAFile string
+}
+
+// enabled reports whether the server is served over TLS
+func (c tlsConfig) enabled() bool {
+	return c.certFile != "" || c.keyFile != "" || c.clientCAFile != ""
+}
+
+// tlsReloader builds the TLS configuration of the server from its files,
+// and builds it again when a file changes, so that certificates can be
+// rotated without restarting the server
+type tlsReloader struct {
+	config tlsConfig
+	// onError reports files that changed but could not be loaded, in which
+	// case the previous configuration is kept
+	onError func(err error)
+
+	mu       sync.Mutex
+	current  *tls.Config
+	modTimes []time.Time
+}
+
+// newTLSReloader loads the files of the configuration
+func newTLSReloader(
+	config tlsConfig,
+	onError func(err error),
+) (*tlsReloader, error) {
+	if config.certFile == "" || config.keyFile == "" {
+		return nil, errors.New("tls requires both a certificate and a key")
+	}
+
+	r := &tlsReloader{config: config, onError: onError}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// serverConfig returns the TLS configuration to serve with
+func (r *tlsReloader) serverConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		GetConfigForClient: r.getConfigForClient,
+	}
+}
+
+// getConfigForClient returns the configuration for a new connection,
+// reloading the files first if they changed
+func (r *tlsReloader) getConfigForClient(
+	*tls.ClientHelloInfo,
+) (*tls.Config, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.changed() {
+		if err := r.reloadLocked(); err != nil && r.onError != nil {
+			r.onError(err)
+		}
+	}
+	return r.current, nil
+}
+
+// reload loads the files and replaces the current configuration
+func (r *tlsReloader) reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.reloadLocked()
+}
+
+// reloadLocked loads the files and replaces the current configuration.
+// The caller must hold r.mu.
+func (r *tlsReloader) reloadLocked() error {
+	// Files that fail to load are tried again once they change again, and
+	// a change made while reading is seen by the next connection
+	r.modTimes = r.stat()
+
+	cert, err := tls.LoadX509KeyPair(r.config.certFile, r.config.keyFile)
+	if err != nil {
+		return fmt.Errorf("loading tls certificate: %w", err)
+	}
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+
+	if r.config.clientCAFile != "" {
+		pem, err := os.ReadFile(r.config.clientCAFile)
+		if err != nil {
+			return fmt.Errorf("loading mtls ca: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("loading mtls ca: no certificates in %s",
+				r.config.clientCAFile)
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	r.current = config
+	return nil
+}
+
+// files returns the files of the configuration
+func (r *tlsReloader) files() []string {
+	files := []string{r.config.certFile, r.config.keyFile}
+	if r.config.clientCAFile != "" {
+		files = append(files, r.config.clientCAFile)
+	}
+	return files
+}
+
+// stat returns the modification times of the files, zero for files that
+// cannot be read
+func (r *tlsReloader) stat() []time.Time {
+	files := r.files()
+	modTimes := make([]time.Time, len(files))
+	for i, file := range files {
+		if info, err := os.Stat(file); err == nil {
+			modTimes[i] = info.ModTime()
+		}
+	}
+	return modTimes
+}
+
+// changed reports whether a file changed since it was loaded. The caller
+// must hold r.mu.
+func (r *tlsReloader) changed() bool {
+	for i, modTime := range r.stat() {
+		if !modTime.Equal(r.modTimes[i]) {
+			return true
+		}
+	}
+	return false
+}
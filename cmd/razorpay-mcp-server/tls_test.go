@@ -0,0 +1,265 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCA signs certificates for tests
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pool *x509.CertPool
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template,
+		&key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	return &testCA{
+		cert: cert,
+		key:  key,
+		pool: pool,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+// issue returns a certificate for 127.0.0.1 signed by the CA, and its key,
+// PEM encoded
+func (ca *testCA) issue(
+	t *testing.T,
+	name string,
+	usage x509.ExtKeyUsage,
+) ([]byte, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert,
+		&key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// writeFile writes a file in dir and returns its path
+func writeFile(t *testing.T, dir, name string, data []byte) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+// startTLSServer starts a server with the TLS configuration of reloader
+func startTLSServer(t *testing.T, reloader *tlsReloader) *httptest.Server {
+	t.Helper()
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+	ts.TLS = reloader.serverConfig()
+	ts.StartTLS()
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+// tlsClient returns a client trusting the CA, presenting certs if any
+func tlsClient(ca *testCA, certs ...tls.Certificate) *http.Client {
+	return &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			RootCAs:      ca.pool,
+			Certificates: certs,
+		},
+		DisableKeepAlives: true,
+	}}
+}
+
+// servedCertificate returns the common name of the certificate the server
+// presents
+func servedCertificate(t *testing.T, client *http.Client, url string) string {
+	t.Helper()
+
+	resp, err := client.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	return resp.TLS.PeerCertificates[0].Subject.CommonName
+}
+
+func TestTLSReloader(t *testing.T) {
+	ca := newTestCA(t)
+	serverCert, serverKey := ca.issue(t, "server",
+		x509.ExtKeyUsageServerAuth)
+
+	t.Run("serves the certificate", func(t *testing.T) {
+		dir := t.TempDir()
+		reloader, err := newTLSReloader(tlsConfig{
+			certFile: writeFile(t, dir, "cert.pem", serverCert),
+			keyFile:  writeFile(t, dir, "key.pem", serverKey),
+		}, nil)
+		require.NoError(t, err)
+		ts := startTLSServer(t, reloader)
+
+		assert.Equal(t, "server",
+			servedCertificate(t, tlsClient(ca), ts.URL))
+	})
+
+	t.Run("reloads changed certificates", func(t *testing.T) {
+		dir := t.TempDir()
+		certFile := writeFile(t, dir, "cert.pem", serverCert)
+		keyFile := writeFile(t, dir, "key.pem", serverKey)
+		reloadErrs := &atomic.Int32{}
+		reloader, err := newTLSReloader(tlsConfig{
+			certFile: certFile,
+			keyFile:  keyFile,
+		}, func(err error) { reloadErrs.Add(1) })
+		require.NoError(t, err)
+		ts := startTLSServer(t, reloader)
+		client := tlsClient(ca)
+
+		// a half written rotation keeps the previous certificate
+		rotatedCert, rotatedKey := ca.issue(t, "rotated",
+			x509.ExtKeyUsageServerAuth)
+		writeFile(t, dir, "cert.pem", rotatedCert)
+		future := time.Now().Add(time.Minute)
+		require.NoError(t, os.Chtimes(certFile, future, future))
+		assert.Equal(t, "server", servedCertificate(t, client, ts.URL))
+		assert.Equal(t, int32(1), reloadErrs.Load())
+
+		writeFile(t, dir, "key.pem", rotatedKey)
+		require.NoError(t, os.Chtimes(keyFile, future, future))
+		assert.Equal(t, "rotated", servedCertificate(t, client, ts.URL))
+		assert.Equal(t, int32(1), reloadErrs.Load())
+	})
+
+	t.Run("requires client certificates with mtls", func(t *testing.T) {
+		dir := t.TempDir()
+		reloader, err := newTLSReloader(tlsConfig{
+			certFile:     writeFile(t, dir, "cert.pem", serverCert),
+			keyFile:      writeFile(t, dir, "key.pem", serverKey),
+			clientCAFile: writeFile(t, dir, "ca.pem", ca.pem),
+		}, nil)
+		require.NoError(t, err)
+		ts := startTLSServer(t, reloader)
+
+		_, err = tlsClient(ca).Get(ts.URL)
+		assert.Error(t, err)
+
+		otherCA := newTestCA(t)
+		otherCert, otherKey := otherCA.issue(t, "other",
+			x509.ExtKeyUsageClientAuth)
+		other, err := tls.X509KeyPair(otherCert, otherKey)
+		require.NoError(t, err)
+		_, err = tlsClient(ca, other).Get(ts.URL)
+		assert.Error(t, err)
+
+		clientCert, clientKey := ca.issue(t, "client",
+			x509.ExtKeyUsageClientAuth)
+		client, err := tls.X509KeyPair(clientCert, clientKey)
+		require.NoError(t, err)
+		resp, err := tlsClient(ca, client).Get(ts.URL)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	})
+
+	t.Run("rejects invalid configurations", func(t *testing.T) {
+		dir := t.TempDir()
+		certFile := writeFile(t, dir, "cert.pem", serverCert)
+		keyFile := writeFile(t, dir, "key.pem", serverKey)
+
+		tests := []struct {
+			name   string
+			config tlsConfig
+			err    string
+		}{
+			{
+				name:   "missing key",
+				config: tlsConfig{certFile: certFile},
+				err:    "tls requires both a certificate and a key",
+			},
+			{
+				name: "mtls without a certificate",
+				config: tlsConfig{
+					clientCAFile: writeFile(t, dir, "ca.pem", ca.pem),
+				},
+				err: "tls requires both a certificate and a key",
+			},
+			{
+				name:   "unreadable certificate",
+				config: tlsConfig{certFile: keyFile, keyFile: keyFile},
+				err:    "loading tls certificate",
+			},
+			{
+				name: "empty ca file",
+				config: tlsConfig{
+					certFile:     certFile,
+					keyFile:      keyFile,
+					clientCAFile: writeFile(t, dir, "empty.pem", nil),
+				},
+				err: "no certificates in",
+			},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				_, err := newTLSReloader(tc.config, nil)
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.err)
+			})
+		}
+	})
+}
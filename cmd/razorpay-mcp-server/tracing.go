@@ -0,0 +1,27 @@
+package main
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+)
+
+// setupTracing returns the tracer of tool calls if an OTLP endpoint is
+// configured in the environment, or nil otherwise, and a function that
+// flushes pending spans on shutdown
+func setupTracing(
+	ctx context.Context,
+) (trace.Tracer, func(context.Context) error, error) {
+	if !observability.TracingConfigured() {
+		return nil, func(context.Context) error { return nil }, nil
+	}
+
+	provider, err := observability.NewTracerProvider(ctx, version)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return provider.Tracer(observability.TracerName), provider.Shutdown, nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+	"github.com/razorpay/razorpay-go/requests"
+	"github.com/spf13/viper"
+)
+
+// Defaults of the connection settings of the Razorpay client
+const (
+	defaultMaxIdleConns          = 100
+	defaultIdleConnTimeout       = 90 * time.Second
+	defaultDialTimeout           = 10 * time.Second
+	defaultTLSHandshakeTimeout   = 10 * time.Second
+	defaultResponseHeaderTimeout = 10 * time.Second
+)
+
+// transportConfig holds the connection settings of the Razorpay client
+type transportConfig struct {
+	// maxIdleConns is the number of idle connections kept open, in total
+	// and per host
+	maxIdleConns        int
+	maxIdleConnsPerHost int
+	// maxConnsPerHost limits the connections to a host, 0 for no limit.
+	// Requests over the limit wait for a connection.
+	maxConnsPerHost int
+	// idleConnTimeout is how long an idle connection is kept open
+	idleConnTimeout       time.Duration
+	dialTimeout           time.Duration
+	tlsHandshakeTimeout   time.Duration
+	responseHeaderTimeout time.Duration
+	// http2 lets requests be multiplexed over HTTP/2 connections
+	http2 bool
+}
+
+// transportConfigFromViper reads the connection settings from the config
+func transportConfigFromViper() transportConfig {
+	return transportConfig{
+		maxIdleConns:          viper.GetInt("api_max_idle_conns"),
+		maxIdleConnsPerHost:   viper.GetInt("api_max_idle_conns_per_host"),
+		maxConnsPerHost:       viper.GetInt("api_max_conns_per_host"),
+		idleConnTimeout:       viper.GetDuration("api_idle_conn_timeout"),
+		dialTimeout:           viper.GetDuration("api_dial_timeout"),
+		tlsHandshakeTimeout:   viper.GetDuration("api_tls_handshake_timeout"),
+		responseHeaderTimeout: viper.GetDuration("api_response_header_timeout"),
+		http2:                 viper.GetBool("api_http2"),
+	}
+}
+
+// newTransport creates the transport of Razorpay clients, routing requests
+// through the proxy if one is set and through the proxy of the environment
+// otherwise. Clients share a transport so that they reuse its connections
+// instead of opening new ones for every client.
+func newTransport(
+	config transportConfig,
+	proxyURL string,
+) (*http.Transport, error) {
+	proxy := http.ProxyFromEnvironment
+	if proxyURL != "" {
+		parsed, err := parseProxyURL(proxyURL)
+		if err != nil {
+			return nil, err
+		}
+		proxy = http.ProxyURL(parsed)
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   config.dialTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+	return &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     config.http2,
+		MaxIdleConns:          config.maxIdleConns,
+		MaxIdleConnsPerHost:   config.maxIdleConnsPerHost,
+		MaxConnsPerHost:       config.maxConnsPerHost,
+		IdleConnTimeout:       config.idleConnTimeout,
+		TLSHandshakeTimeout:   config.tlsHandshakeTimeout,
+		ResponseHeaderTimeout: config.responseHeaderTimeout,
+		ExpectContinueTimeout: time.Second,
+	}, nil
+}
+
+// configureTransport sends the requests of the Razorpay client through the
+// transport, or the default transport if it is nil. The other transports of
+// the client wrap it, so it is applied first. The Request of the client is
+// shared by reference across all its API resources, so setting its
+// HTTPClient here, and in the helpers that wrap it, applies to all of them.
+func configureTransport(client *rzpsdk.Client, transport *http.Transport) {
+	client.Request.HTTPClient = &http.Client{
+		Timeout: time.Duration(requests.TIMEOUT) * time.Second,
+	}
+	if transport != nil {
+		client.Request.HTTPClient.Transport = transport
+	}
+}
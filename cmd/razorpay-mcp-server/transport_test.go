@@ -0,0 +1,128 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+)
+
+func TestNewTransport(t *testing.T) {
+	t.Run("routes razorpay requests through the proxy", func(t *testing.T) {
+		transport, err := newTransport(transportConfig{},
+			"http://proxy.internal:3128")
+		require.NoError(t, err)
+
+		req, err := http.NewRequest(
+			http.MethodGet, "https://api.razorpay.com/v1/payments", nil)
+		require.NoError(t, err)
+
+		proxy, err := transport.Proxy(req)
+		require.NoError(t, err)
+		assert.Equal(t, "http://proxy.internal:3128", proxy.String())
+	})
+
+	t.Run("rejects invalid proxy urls", func(t *testing.T) {
+		invalid := map[string]string{
+			"unsupported scheme": "ftp://proxy.internal:21",
+			"missing host":       "http://",
+			"unparsable":         "http://[::1",
+		}
+
+		for name, proxyURL := range invalid {
+			t.Run(name, func(t *testing.T) {
+				_, err := newTransport(transportConfig{}, proxyURL)
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), "invalid proxy url")
+			})
+		}
+	})
+
+	t.Run("applies the connection settings", func(t *testing.T) {
+		transport, err := newTransport(transportConfig{
+			maxIdleConns:          50,
+			maxIdleConnsPerHost:   20,
+			maxConnsPerHost:       10,
+			idleConnTimeout:       time.Minute,
+			dialTimeout:           5 * time.Second,
+			tlsHandshakeTimeout:   3 * time.Second,
+			responseHeaderTimeout: 7 * time.Second,
+			http2:                 true,
+		}, "")
+		require.NoError(t, err)
+
+		assert.Equal(t, 50, transport.MaxIdleConns)
+		assert.Equal(t, 20, transport.MaxIdleConnsPerHost)
+		assert.Equal(t, 10, transport.MaxConnsPerHost)
+		assert.Equal(t, time.Minute, transport.IdleConnTimeout)
+		assert.Equal(t, 3*time.Second, transport.TLSHandshakeTimeout)
+		assert.Equal(t, 7*time.Second, transport.ResponseHeaderTimeout)
+		assert.True(t, transport.ForceAttemptHTTP2)
+	})
+
+	t.Run("reuses connections across clients", func(t *testing.T) {
+		var mu sync.Mutex
+		conns := 0
+		server := httptest.NewUnstartedServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"id":"pay_1"}`))
+			}))
+		server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+			if state == http.StateNew {
+				mu.Lock()
+				conns++
+				mu.Unlock()
+			}
+		}
+		server.Start()
+		defer server.Close()
+
+		transport, err := newTransport(transportConfig{
+			maxIdleConns:        10,
+			maxIdleConnsPerHost: 10,
+		}, "")
+		require.NoError(t, err)
+
+		for i := 0; i < 3; i++ {
+			client := rzpsdk.NewClient("test-key", "test-secret")
+			configureTransport(client, transport)
+			client.Request.BaseURL = server.URL
+
+			_, err := client.Payment.Fetch("pay_1", nil, nil)
+			require.NoError(t, err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, 1, conns)
+	})
+}
+
+func TestConfigureTransport(t *testing.T) {
+	t.Run("sends requests through the transport", func(t *testing.T) {
+		transport, err := newTransport(transportConfig{}, "")
+		require.NoError(t, err)
+		client := rzpsdk.NewClient("test-key", "test-secret")
+
+		configureTransport(client, transport)
+
+		assert.Same(t, transport, client.Request.HTTPClient.Transport)
+		assert.Equal(t, 10*time.Second, client.Request.HTTPClient.Timeout)
+	})
+
+	t.Run("nil transport uses the default one", func(t *testing.T) {
+		client := rzpsdk.NewClient("test-key", "test-secret")
+
+		configureTransport(client, nil)
+
+		assert.Nil(t, client.Request.HTTPClient.Transport)
+	})
+}
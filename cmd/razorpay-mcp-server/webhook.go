@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/events"
+)
+
+// defaultWebhookPath is the path Razorpay webhooks are received on
+const defaultWebhookPath = "/webhooks/razorpay"
+
+// defaultWebhookHistorySize is the number of webhook events kept for the
+// event tools
+const defaultWebhookHistorySize = 100
+
+// webhookConfig holds the settings of the webhook endpoint of the http
+// transport
+type webhookConfig struct {
+	// secret is the secret of the Razorpay webhook events are signed with,
+	// and enables the endpoint if set
+	secret string
+	path   string
+	// historySize is the number of latest events kept
+	historySize int
+}
+
+// enabled reports whether webhook events are received
+func (c webhookConfig) enabled() bool {
+	return c.secret != ""
+}
+
+// registerWebhookHandler serves the webhook endpoint on mux, adding the
+// events it receives to history. The endpoint may not be served on the
+// path of another endpoint.
+func registerWebhookHandler(
+	mux *http.ServeMux,
+	config webhookConfig,
+	history *events.History,
+	reservedPaths ...string,
+) error {
+	if !strings.HasPrefix(config.path, "/") {
+		return fmt.Errorf("webhook path %q must start with /", config.path)
+	}
+	for _, path := range reservedPaths {
+		if config.path == path {
+			return fmt.Errorf("webhook path %q is already in use", path)
+		}
+	}
+
+	mux.Handle(config.path, events.WebhookHandler(config.secret, history))
+	return nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/events"
+)
+
+func TestRegisterWebhookHandler(t *testing.T) {
+	config := webhookConfig{
+		secret:      "webhook-secret",
+		path:        defaultWebhookPath,
+		historySize: defaultWebhookHistorySize,
+	}
+
+	t.Run("adds signed events to the history", func(t *testing.T) {
+		mux := http.NewServeMux()
+		history := events.NewHistory(config.historySize)
+		require.NoError(t, registerWebhookHandler(mux, config, history,
+			"/mcp"))
+
+		body := `{"event":"order.paid","payload":{}}`
+		req := httptest.NewRequest(http.MethodPost, defaultWebhookPath,
+			strings.NewReader(body))
+		req.Header.Set(events.SignatureHeader,
+			events.Sign([]byte(body), config.secret))
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, int64(1), history.LastSeq())
+	})
+
+	t.Run("rejects paths of other endpoints", func(t *testing.T) {
+		config := config
+		config.path = "/mcp"
+
+		err := registerWebhookHandler(http.NewServeMux(), config,
+			events.NewHistory(1), "/mcp", metricsPath)
+		assert.EqualError(t, err, `webhook path "/mcp" is already in use`)
+	})
+
+	t.Run("rejects relative paths", func(t *testing.T) {
+		config := config
+		config.path = "webhooks"
+
+		err := registerWebhookHandler(http.NewServeMux(), config,
+			events.NewHistory(1))
+		assert.EqualError(t, err, `webhook path "webhooks" must start with /`)
+	})
+}
@@ -0,0 +1,268 @@
+// Package audit records the write tool calls made through the server, for
+// compliance purposes. Audit records are kept apart from debug logs.
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
+)
+
+// Statuses of audited tool calls
+const (
+	StatusSuccess = "success"
+	StatusError   = "error"
+)
+
+// redacted replaces the values of sensitive parameters
+const redacted = "[REDACTED]"
+
+// sinkTimeout bounds how long an http sink waits for the endpoint
+const sinkTimeout = 5 * time.Second
+
+// sensitiveSuffixes end the names of parameters whose values are kept out
+// of audit records, such as key_secret or access_token
+var sensitiveSuffixes = []string{
+	"secret", "password", "token", "cvv", "pin", "otp", "card_number",
+	"account_number",
+}
+
+// Entry is the audit record of a tool call
+type Entry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Tool      string    `json:"tool"`
+	Toolset   string    `json:"toolset,omitempty"`
+	// Caller identifies who made the call, such as the key ID of the
+	// credentials it was made with
+	Caller string `json:"caller"`
+	// Parameters are the arguments of the call, with sensitive values
+	// redacted
+	Parameters map[string]interface{} `json:"parameters"`
+	Status     string                 `json:"status"`
+	// Error is the error message of a failed call
+	Error string `json:"error,omitempty"`
+	// EntityIDs are the IDs of the Razorpay entities the call named or
+	// returned, keyed by field name
+	EntityIDs map[string]string `json:"entity_ids,omitempty"`
+}
+
+// Sink stores audit records
+type Sink interface {
+	Write(entry Entry) error
+}
+
+// Logger records tool calls in audit sinks
+type Logger struct {
+	sinks []Sink
+	// defaultCaller is the caller of calls that carry no caller of their
+	// own, such as calls made with the server credentials
+	defaultCaller string
+	// onError is called with the errors of sinks that failed to store a
+	// record
+	onError func(err error)
+}
+
+// Option configures a Logger
+type Option func(*Logger)
+
+// WithDefaultCaller sets the caller recorded for calls whose context
+// carries no caller
+func WithDefaultCaller(caller string) Option {
+	return func(l *Logger) {
+		l.defaultCaller = caller
+	}
+}
+
+// WithErrorHandler sets the function that is called when a sink fails to
+// store a record
+func WithErrorHandler(onError func(err error)) Option {
+	return func(l *Logger) {
+		l.onError = onError
+	}
+}
+
+// NewLogger creates a logger that writes records to all the sinks
+func NewLogger(sinks []Sink, opts ...Option) *Logger {
+	l := &Logger{sinks: sinks}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Record stores the record of a tool call in every sink. The timestamp
+// and caller are filled in if not set, and sensitive parameters are
+// redacted.
+func (l *Logger) Record(ctx context.Context, entry Entry) {
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now().UTC()
+	}
+	if entry.Caller == "" {
+		entry.Caller = contextkey.CallerFromContext(ctx)
+	}
+	if entry.Caller == "" {
+		entry.Caller = l.defaultCaller
+	}
+	entry.Parameters = Redact(entry.Parameters)
+
+	for _, sink := range l.sinks {
+		if err := sink.Write(entry); err != nil && l.onError != nil {
+			l.onError(fmt.Errorf("failed to write audit record: %w", err))
+		}
+	}
+}
+
+// Redact returns a copy of the parameters with the values of sensitive
+// parameters, at any depth, replaced
+func Redact(parameters map[string]interface{}) map[string]interface{} {
+	if parameters == nil {
+		return nil
+	}
+
+	redactedParams := make(map[string]interface{}, len(parameters))
+	for name, value := range parameters {
+		if isSensitive(name) {
+			redactedParams[name] = redacted
+			continue
+		}
+		redactedParams[name] = redactValue(value)
+	}
+	return redactedParams
+}
+
+// redactValue redacts the sensitive parameters nested in a value
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return Redact(v)
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = redactValue(item)
+		}
+		return items
+	default:
+		return value
+	}
+}
+
+// isSensitive reports whether a parameter holds a value that must not be
+// recorded. Only the last words of the name count, so that token_id, the
+// ID of a saved card token, is kept.
+func isSensitive(name string) bool {
+	name = strings.ToLower(name)
+	for _, suffix := range sensitiveSuffixes {
+		if name == suffix || strings.HasSuffix(name, "_"+suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// EntityIDs returns the Razorpay entity IDs named by the string fields id
+// and *_id of the objects, with the fields of later objects taking
+// precedence. The id field of the result of a call, the created or
+// updated entity, is returned as "id".
+func EntityIDs(objects ...map[string]interface{}) map[string]string {
+	ids := make(map[string]string)
+	for _, object := range objects {
+		for name, value := range object {
+			id, ok := value.(string)
+			if !ok || id == "" {
+				continue
+			}
+			if name == "id" ||
+				(strings.HasSuffix(name, "_id") && name != "_id") {
+				ids[name] = id
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return ids
+}
+
+// FileSink appends audit records to a file as JSON lines. The file is
+// only ever appended to, and every record is synced to disk before Write
+// returns.
+type FileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileSink opens the file at path for appending, creating it if needed
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path,
+		os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return &FileSink{file: file}, nil
+}
+
+// Write implements Sink
+func (s *FileSink) Write(entry Entry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.file.Write(line); err != nil {
+		return err
+	}
+	return s.file.Sync()
+}
+
+// Close closes the file
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
+
+// HTTPSink posts every audit record as JSON to an endpoint, such as the
+// ingestion endpoint of a log store
+type HTTPSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPSink creates a sink that posts records to the url
+func NewHTTPSink(url string) *HTTPSink {
+	return &HTTPSink{
+		url:    url,
+		client: &http.Client{Timeout: sinkTimeout},
+	}
+}
+
+// Write implements Sink
+func (s *HTTPSink) Write(entry Entry) error {
+	body, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Post(s.url, "application/json",
+		bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.New("audit endpoint responded with " + resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
+)
+
+// memorySink keeps the records written to it
+type memorySink struct {
+	entries []Entry
+	err     error
+}
+
+func (s *memorySink) Write(entry Entry) error {
+	s.entries = append(s.entries, entry)
+	return s.err
+}
+
+func TestLoggerRecord(t *testing.T) {
+	t.Run("fills in timestamp and caller", func(t *testing.T) {
+		sink := &memorySink{}
+		logger := NewLogger([]Sink{sink}, WithDefaultCaller("rzp_server"))
+
+		ctx := contextkey.WithCaller(context.Background(), "rzp_merchant")
+		logger.Record(ctx, Entry{Tool: "create_order", Status: StatusSuccess})
+		logger.Record(context.Background(), Entry{Tool: "create_order"})
+
+		require.Len(t, sink.entries, 2)
+		assert.Equal(t, "rzp_merchant", sink.entries[0].Caller)
+		assert.False(t, sink.entries[0].Timestamp.IsZero())
+		assert.Equal(t, "rzp_server", sink.entries[1].Caller)
+	})
+
+	t.Run("redacts sensitive parameters", func(t *testing.T) {
+		sink := &memorySink{}
+		logger := NewLogger([]Sink{sink})
+
+		params := map[string]interface{}{"amount": float64(100),
+			"key_secret": "s3cret"}
+		logger.Record(context.Background(), Entry{Parameters: params})
+
+		require.Len(t, sink.entries, 1)
+		assert.Equal(t, redacted, sink.entries[0].Parameters["key_secret"])
+		assert.Equal(t, float64(100), sink.entries[0].Parameters["amount"])
+		assert.Equal(t, "s3cret", params["key_secret"],
+			"the caller's parameters must not be changed")
+	})
+
+	t.Run("writes to every sink and reports errors", func(t *testing.T) {
+		failing := &memorySink{err: errors.New("disk full")}
+		working := &memorySink{}
+		var reported []error
+		logger := NewLogger([]Sink{failing, working},
+			WithErrorHandler(func(err error) {
+				reported = append(reported, err)
+			}))
+
+		logger.Record(context.Background(), Entry{Tool: "create_order"})
+
+		assert.Len(t, failing.entries, 1)
+		assert.Len(t, working.entries, 1)
+		require.Len(t, reported, 1)
+		assert.ErrorContains(t, reported[0], "disk full")
+	})
+}
+
+func TestRedact(t *testing.T) {
+	params := map[string]interface{}{
+		"amount":   float64(100),
+		"token_id": "token_Aa00000000001",
+		"card": map[string]interface{}{
+			"card_number": "4111111111111111",
+			"cvv":         "123",
+			"name":        "Gaurav Kumar",
+		},
+		"bank_accounts": []interface{}{
+			map[string]interface{}{"account_number": "1121431121541121"},
+		},
+		"access_token": "tok",
+		"shipping_pin": "560001",
+		"PASSWORD":     "hunter2",
+	}
+
+	expected := map[string]interface{}{
+		"amount":   float64(100),
+		"token_id": "token_Aa00000000001",
+		"card": map[string]interface{}{
+			"card_number": redacted,
+			"cvv":         redacted,
+			"name":        "Gaurav Kumar",
+		},
+		"bank_accounts": []interface{}{
+			map[string]interface{}{"account_number": redacted},
+		},
+		"access_token": redacted,
+		"shipping_pin": redacted,
+		"PASSWORD":     redacted,
+	}
+
+	assert.Equal(t, expected, Redact(params))
+	assert.Nil(t, Redact(nil))
+}
+
+func TestEntityIDs(t *testing.T) {
+	args := map[string]interface{}{
+		"payment_id": "pay_Aa00000000001",
+		"amount":     float64(100),
+		"notes_id":   float64(5),
+		"_id":        "ignored",
+	}
+	result := map[string]interface{}{
+		"id":         "rfnd_Aa00000000001",
+		"payment_id": "pay_Aa00000000001",
+		"status":     "processed",
+	}
+
+	assert.Equal(t, map[string]string{
+		"id":         "rfnd_Aa00000000001",
+		"payment_id": "pay_Aa00000000001",
+	}, EntityIDs(args, result))
+	assert.Nil(t, EntityIDs(map[string]interface{}{"amount": 1}))
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	sink, err := NewFileSink(path)
+	require.NoError(t, err)
+	ts := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, sink.Write(Entry{Timestamp: ts, Tool: "create_order"}))
+	require.NoError(t, sink.Close())
+
+	// Reopening the file appends to it
+	sink, err = NewFileSink(path)
+	require.NoError(t, err)
+	require.NoError(t, sink.Write(Entry{Timestamp: ts, Tool: "create_refund"}))
+	require.NoError(t, sink.Close())
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var tools []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry Entry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		assert.Equal(t, ts, entry.Timestamp)
+		tools = append(tools, entry.Tool)
+	}
+	assert.Equal(t, []string{"create_order", "create_refund"}, tools)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	_, err = NewFileSink(filepath.Join(t.TempDir(), "missing", "audit.jsonl"))
+	assert.ErrorContains(t, err, "failed to open audit log")
+}
+
+func TestHTTPSink(t *testing.T) {
+	t.Run("posts records as json", func(t *testing.T) {
+		received := make(chan Entry, 1)
+		srv := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "application/json",
+					r.Header.Get("Content-Type"))
+
+				var entry Entry
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&entry))
+				received <- entry
+			}))
+		defer srv.Close()
+
+		err := NewHTTPSink(srv.URL).Write(Entry{Tool: "create_order"})
+		require.NoError(t, err)
+		assert.Equal(t, "create_order", (<-received).Tool)
+	})
+
+	t.Run("fails on error responses", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}))
+		defer srv.Close()
+
+		err := NewHTTPSink(srv.URL).Write(Entry{Tool: "create_order"})
+		assert.ErrorContains(t, err, "503")
+	})
+}
@@ -9,7 +9,16 @@ type contextKey string
 
 // Context keys for storing various values.
 const (
-	clientKey contextKey = "client"
+	clientKey       contextKey = "client"
+	strictParamsKey contextKey = "strict_params"
+	readOnlyKey     contextKey = "read_only"
+	cacheScopeKey   contextKey = "cache_scope"
+	callerKey       contextKey = "caller"
+	localeKey       contextKey = "locale"
+	emailDomainKey  contextKey = "generated_email_domain"
+	eventBusKey     contextKey = "event_bus"
+	eventHistoryKey contextKey = "event_history"
+	merchantKey     contextKey = "merchant_account"
 )
 
 // WithClient returns a new context with the client instance attached.
@@ -22,3 +31,125 @@ func WithClient(ctx context.Context, client interface{}) context.Context {
 func ClientFromContext(ctx context.Context) interface{} {
 	return ctx.Value(clientKey)
 }
+
+// WithStrictParams returns a new context recording whether tool calls
+// should reject parameters not declared by the tool.
+func WithStrictParams(ctx context.Context, strict bool) context.Context {
+	return context.WithValue(ctx, strictParamsKey, strict)
+}
+
+// StrictParamsFromContext reports whether strict parameter checking is
+// enabled in the context. Returns false if it was never set.
+func StrictParamsFromContext(ctx context.Context) bool {
+	strict, _ := ctx.Value(strictParamsKey).(bool)
+	return strict
+}
+
+// WithReadOnly returns a new context recording whether calls to write
+// tools must be rejected.
+func WithReadOnly(ctx context.Context, readOnly bool) context.Context {
+	return context.WithValue(ctx, readOnlyKey, readOnly)
+}
+
+// ReadOnlyFromContext reports whether read-only mode is enabled in the
+// context. Returns false if it was never set.
+func ReadOnlyFromContext(ctx context.Context) bool {
+	readOnly, _ := ctx.Value(readOnlyKey).(bool)
+	return readOnly
+}
+
+// WithCacheScope returns a new context recording the scope cached tool
+// results are shared in, such as the credentials of the request.
+func WithCacheScope(ctx context.Context, scope string) context.Context {
+	return context.WithValue(ctx, cacheScopeKey, scope)
+}
+
+// CacheScopeFromContext returns the cache scope of the context. Returns ""
+// if it was never set.
+func CacheScopeFromContext(ctx context.Context) string {
+	scope, _ := ctx.Value(cacheScopeKey).(string)
+	return scope
+}
+
+// WithCaller returns a new context recording who makes the tool calls,
+// such as the key ID of the credentials of the request.
+func WithCaller(ctx context.Context, caller string) context.Context {
+	return context.WithValue(ctx, callerKey, caller)
+}
+
+// CallerFromContext returns the caller recorded in the context. Returns ""
+// if it was never set.
+func CallerFromContext(ctx context.Context) string {
+	caller, _ := ctx.Value(callerKey).(string)
+	return caller
+}
+
+// WithLocale returns a new context recording the language tool guidance
+// is written in, such as "hi".
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey, locale)
+}
+
+// LocaleFromContext returns the locale recorded in the context. Returns ""
+// if it was never set.
+func LocaleFromContext(ctx context.Context) string {
+	locale, _ := ctx.Value(localeKey).(string)
+	return locale
+}
+
+// WithGeneratedEmailDomain returns a new context recording the domain of
+// the emails generated for customers from their contact number. An empty
+// domain disables generated emails.
+func WithGeneratedEmailDomain(
+	ctx context.Context,
+	domain string,
+) context.Context {
+	return context.WithValue(ctx, emailDomainKey, domain)
+}
+
+// GeneratedEmailDomainFromContext returns the domain of generated emails
+// recorded in the context, and false if it was never set.
+func GeneratedEmailDomainFromContext(ctx context.Context) (string, bool) {
+	domain, ok := ctx.Value(emailDomainKey).(string)
+	return domain, ok
+}
+
+// WithEventBus returns a new context with the bus delivering entity
+// changes to subscribed sessions attached.
+func WithEventBus(ctx context.Context, bus interface{}) context.Context {
+	return context.WithValue(ctx, eventBusKey, bus)
+}
+
+// EventBusFromContext extracts the event bus from the context. Returns nil
+// if no bus is found.
+func EventBusFromContext(ctx context.Context) interface{} {
+	return ctx.Value(eventBusKey)
+}
+
+// WithEventHistory returns a new context with the history of the webhook
+// events the server received attached.
+func WithEventHistory(
+	ctx context.Context,
+	history interface{},
+) context.Context {
+	return context.WithValue(ctx, eventHistoryKey, history)
+}
+
+// EventHistoryFromContext extracts the webhook event history from the
+// context. Returns nil if no history is found.
+func EventHistoryFromContext(ctx context.Context) interface{} {
+	return ctx.Value(eventHistoryKey)
+}
+
+// WithMerchantAccount returns a new context recording the sub-merchant
+// account, such as acc_Ef7ArAsdU5t0XL, a partner makes calls on behalf of.
+func WithMerchantAccount(ctx context.Context, account string) context.Context {
+	return context.WithValue(ctx, merchantKey, account)
+}
+
+// MerchantAccountFromContext returns the sub-merchant account recorded in
+// the context. Returns "" if none is set.
+func MerchantAccountFromContext(ctx context.Context) string {
+	account, _ := ctx.Value(merchantKey).(string)
+	return account
+}
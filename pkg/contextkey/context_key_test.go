@@ -115,3 +115,128 @@ func TestClientFromContext(t *testing.T) {
 		assert.Equal(t, client, retrieved)
 	})
 }
+
+func TestStrictParams(t *testing.T) {
+	t.Run("defaults to false", func(t *testing.T) {
+		assert.False(t, StrictParamsFromContext(context.Background()))
+	})
+
+	t.Run("returns the stored value", func(t *testing.T) {
+		ctx := WithStrictParams(context.Background(), true)
+		assert.True(t, StrictParamsFromContext(ctx))
+
+		ctx = WithStrictParams(ctx, false)
+		assert.False(t, StrictParamsFromContext(ctx))
+	})
+}
+
+func TestReadOnly(t *testing.T) {
+	t.Run("defaults to false", func(t *testing.T) {
+		assert.False(t, ReadOnlyFromContext(context.Background()))
+	})
+
+	t.Run("returns the stored value", func(t *testing.T) {
+		ctx := WithReadOnly(context.Background(), true)
+		assert.True(t, ReadOnlyFromContext(ctx))
+
+		ctx = WithReadOnly(ctx, false)
+		assert.False(t, ReadOnlyFromContext(ctx))
+	})
+}
+
+func TestWithCacheScope(t *testing.T) {
+	t.Run("adds cache scope to context", func(t *testing.T) {
+		ctx := WithCacheScope(context.Background(), "scope-1")
+
+		assert.Equal(t, "scope-1", CacheScopeFromContext(ctx))
+	})
+
+	t.Run("returns empty scope when not set", func(t *testing.T) {
+		assert.Empty(t, CacheScopeFromContext(context.Background()))
+	})
+}
+
+func TestWithCaller(t *testing.T) {
+	t.Run("adds caller to context", func(t *testing.T) {
+		ctx := WithCaller(context.Background(), "rzp_test_key")
+
+		assert.Equal(t, "rzp_test_key", CallerFromContext(ctx))
+	})
+
+	t.Run("returns empty caller when not set", func(t *testing.T) {
+		assert.Empty(t, CallerFromContext(context.Background()))
+	})
+}
+
+func TestWithLocale(t *testing.T) {
+	t.Run("adds locale to context", func(t *testing.T) {
+		ctx := WithLocale(context.Background(), "hi")
+
+		assert.Equal(t, "hi", LocaleFromContext(ctx))
+	})
+
+	t.Run("returns empty locale when not set", func(t *testing.T) {
+		assert.Empty(t, LocaleFromContext(context.Background()))
+	})
+}
+
+func TestWithGeneratedEmailDomain(t *testing.T) {
+	t.Run("adds domain to context", func(t *testing.T) {
+		ctx := WithGeneratedEmailDomain(context.Background(), "example.com")
+
+		domain, ok := GeneratedEmailDomainFromContext(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, "example.com", domain)
+	})
+
+	t.Run("records disabled generated emails", func(t *testing.T) {
+		ctx := WithGeneratedEmailDomain(context.Background(), "")
+
+		domain, ok := GeneratedEmailDomainFromContext(ctx)
+		assert.True(t, ok)
+		assert.Empty(t, domain)
+	})
+
+	t.Run("reports a domain that is not set", func(t *testing.T) {
+		_, ok := GeneratedEmailDomainFromContext(context.Background())
+		assert.False(t, ok)
+	})
+}
+
+func TestWithEventBus(t *testing.T) {
+	t.Run("adds bus to context", func(t *testing.T) {
+		bus := &struct{ name string }{name: "events"}
+		ctx := WithEventBus(context.Background(), bus)
+
+		assert.Same(t, bus, EventBusFromContext(ctx))
+	})
+
+	t.Run("returns nil when bus not set", func(t *testing.T) {
+		assert.Nil(t, EventBusFromContext(context.Background()))
+	})
+}
+
+func TestWithEventHistory(t *testing.T) {
+	t.Run("adds history to context", func(t *testing.T) {
+		history := &struct{ name string }{name: "webhooks"}
+		ctx := WithEventHistory(context.Background(), history)
+
+		assert.Same(t, history, EventHistoryFromContext(ctx))
+	})
+
+	t.Run("returns nil when history not set", func(t *testing.T) {
+		assert.Nil(t, EventHistoryFromContext(context.Background()))
+	})
+}
+
+func TestWithMerchantAccount(t *testing.T) {
+	t.Run("adds merchant account to context", func(t *testing.T) {
+		ctx := WithMerchantAccount(context.Background(), "acc_Ef7ArAsdU5t0XL")
+
+		assert.Equal(t, "acc_Ef7ArAsdU5t0XL", MerchantAccountFromContext(ctx))
+	})
+
+	t.Run("returns empty when merchant account not set", func(t *testing.T) {
+		assert.Empty(t, MerchantAccountFromContext(context.Background()))
+	})
+}
@@ -0,0 +1,240 @@
+// Package events delivers the changes of Razorpay entities, such as
+// payments and orders, to the sessions that subscribed to them. Changes are
+// found by polling the watched entities, or published as they happen, such
+// as from webhook events.
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Sources of events
+const (
+	SourcePoll    = "poll"
+	SourceWebhook = "webhook"
+)
+
+// ErrSessionNotFound is returned by a NotifyFunc when the session ended.
+// The subscriptions of the session are then removed.
+var ErrSessionNotFound = errors.New("session not found")
+
+// Event is a change of the status of an entity
+type Event struct {
+	// Type is the entity and its new status, such as payment.captured
+	Type           string `json:"type"`
+	Entity         string `json:"entity"`
+	ID             string `json:"id"`
+	Status         string `json:"status"`
+	PreviousStatus string `json:"previous_status,omitempty"`
+	Source         string `json:"source"`
+	// Final is set when the entity reached a final status. No events of
+	// the entity follow.
+	Final bool `json:"final,omitempty"`
+	// Payload is the entity after the change, if known
+	Payload map[string]interface{} `json:"payload,omitempty"`
+}
+
+// FetchFunc fetches the current state of a watched entity
+type FetchFunc func(ctx context.Context) (map[string]interface{}, error)
+
+// NotifyFunc delivers an event to a session
+type NotifyFunc func(sessionID string, event Event) error
+
+// Watch describes an entity sessions subscribe to the changes of
+type Watch struct {
+	Entity string
+	ID     string
+	// Status is the current status of the entity
+	Status string
+	// Fetch fetches the entity to poll it for changes, nil if its changes
+	// are only published
+	Fetch FetchFunc
+	// Final reports whether a status is final, nil if none is
+	Final func(status string) bool
+}
+
+// key identifies a watched entity
+type key struct {
+	entity string
+	id     string
+}
+
+// watch is a watched entity and the sessions subscribed to it
+type watch struct {
+	Watch
+	sessions map[string]bool
+}
+
+// Bus keeps the subscriptions of sessions to entities and delivers the
+// changes of the entities to them
+type Bus struct {
+	interval time.Duration
+	notify   NotifyFunc
+
+	mu      sync.Mutex
+	watches map[key]*watch
+	polling bool
+}
+
+// NewBus creates a bus that delivers events with notify and polls the
+// watched entities every interval while any session is subscribed
+func NewBus(interval time.Duration, notify NotifyFunc) *Bus {
+	return &Bus{
+		interval: interval,
+		notify:   notify,
+		watches:  make(map[key]*watch),
+	}
+}
+
+// Subscribe subscribes the session to the changes of the entity. An entity
+// already watched keeps its status and fetch func.
+func (b *Bus) Subscribe(sessionID string, w Watch) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	k := key{entity: w.Entity, id: w.ID}
+	existing, ok := b.watches[k]
+	if !ok {
+		existing = &watch{Watch: w, sessions: make(map[string]bool)}
+		b.watches[k] = existing
+	}
+	existing.sessions[sessionID] = true
+
+	if existing.Fetch != nil && b.interval > 0 && !b.polling {
+		b.polling = true
+		go b.poll()
+	}
+}
+
+// Unsubscribe ends the subscription of the session to the entity. It
+// reports whether the session was subscribed.
+func (b *Bus) Unsubscribe(sessionID, entity, id string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	k := key{entity: entity, id: id}
+	w, ok := b.watches[k]
+	if !ok || !w.sessions[sessionID] {
+		return false
+	}
+	delete(w.sessions, sessionID)
+	if len(w.sessions) == 0 {
+		delete(b.watches, k)
+	}
+	return true
+}
+
+// Subscribed reports whether the session is subscribed to the entity
+func (b *Bus) Subscribed(sessionID, entity, id string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	w, ok := b.watches[key{entity: entity, id: id}]
+	return ok && w.sessions[sessionID]
+}
+
+// Publish delivers the event to the sessions subscribed to its entity, if
+// its status differs from the last known one. The subscriptions end once
+// the entity reaches a final status.
+func (b *Bus) Publish(event Event) {
+	b.mu.Lock()
+	k := key{entity: event.Entity, id: event.ID}
+	w, ok := b.watches[k]
+	if !ok || event.Status == "" || event.Status == w.Status {
+		b.mu.Unlock()
+		return
+	}
+
+	if event.Type == "" {
+		event.Type = event.Entity + "." + event.Status
+	}
+	if event.PreviousStatus == "" {
+		event.PreviousStatus = w.Status
+	}
+	event.Final = w.Final != nil && w.Final(event.Status)
+	w.Status = event.Status
+
+	sessions := make([]string, 0, len(w.sessions))
+	for sessionID := range w.sessions {
+		sessions = append(sessions, sessionID)
+	}
+	if event.Final {
+		delete(b.watches, k)
+	}
+	b.mu.Unlock()
+
+	for _, sessionID := range sessions {
+		err := b.notify(sessionID, event)
+		if errors.Is(err, ErrSessionNotFound) {
+			b.removeSession(sessionID)
+		}
+	}
+}
+
+// removeSession ends the subscriptions of a session
+func (b *Bus) removeSession(sessionID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for k, w := range b.watches {
+		delete(w.sessions, sessionID)
+		if len(w.sessions) == 0 {
+			delete(b.watches, k)
+		}
+	}
+}
+
+// polled returns the watched entities to poll. Polling stops, and false is
+// returned, once no entity is left to poll.
+func (b *Bus) polled() ([]Watch, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	watches := make([]Watch, 0, len(b.watches))
+	for _, w := range b.watches {
+		if w.Fetch != nil {
+			watches = append(watches, w.Watch)
+		}
+	}
+	if len(watches) == 0 {
+		b.polling = false
+		return nil, false
+	}
+	return watches, true
+}
+
+// poll fetches the watched entities every interval and publishes the
+// changes of their status
+func (b *Bus) poll() {
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		watches, ok := b.polled()
+		if !ok {
+			return
+		}
+
+		for _, w := range watches {
+			ctx, cancel := context.WithTimeout(context.Background(),
+				b.interval)
+			entity, err := w.Fetch(ctx)
+			cancel()
+			if err != nil {
+				continue
+			}
+
+			status, _ := entity["status"].(string)
+			b.Publish(Event{
+				Entity:  w.Entity,
+				ID:      w.ID,
+				Status:  status,
+				Source:  SourcePoll,
+				Payload: entity,
+			})
+		}
+	}
+}
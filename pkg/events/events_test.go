@@ -0,0 +1,179 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder records the events delivered to sessions
+type recorder struct {
+	mu     sync.Mutex
+	events map[string][]Event
+	ended  map[string]bool
+}
+
+func newRecorder() *recorder {
+	return &recorder{
+		events: make(map[string][]Event),
+		ended:  make(map[string]bool),
+	}
+}
+
+func (r *recorder) notify(sessionID string, event Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.ended[sessionID] {
+		return ErrSessionNotFound
+	}
+	r.events[sessionID] = append(r.events[sessionID], event)
+	return nil
+}
+
+func (r *recorder) of(sessionID string) []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Event(nil), r.events[sessionID]...)
+}
+
+func (r *recorder) end(sessionID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.ended[sessionID] = true
+}
+
+// paymentWatch returns the watch of a payment whose final statuses are
+// captured and failed
+func paymentWatch(status string, fetch FetchFunc) Watch {
+	return Watch{
+		Entity: "payment",
+		ID:     "pay_1",
+		Status: status,
+		Fetch:  fetch,
+		Final: func(status string) bool {
+			return status == "captured" || status == "failed"
+		},
+	}
+}
+
+func TestBusPublish(t *testing.T) {
+	t.Run("delivers changes to subscribed sessions", func(t *testing.T) {
+		events := newRecorder()
+		bus := NewBus(0, events.notify)
+		bus.Subscribe("session-1", paymentWatch("created", nil))
+
+		bus.Publish(Event{
+			Entity: "payment",
+			ID:     "pay_1",
+			Status: "authorized",
+			Source: SourceWebhook,
+		})
+
+		assert.Equal(t, []Event{{
+			Type:           "payment.authorized",
+			Entity:         "payment",
+			ID:             "pay_1",
+			Status:         "authorized",
+			PreviousStatus: "created",
+			Source:         SourceWebhook,
+		}}, events.of("session-1"))
+		assert.Empty(t, events.of("session-2"))
+	})
+
+	t.Run("skips events that change nothing", func(t *testing.T) {
+		events := newRecorder()
+		bus := NewBus(0, events.notify)
+		bus.Subscribe("session-1", paymentWatch("created", nil))
+
+		bus.Publish(Event{Entity: "payment", ID: "pay_1", Status: "created"})
+		bus.Publish(Event{Entity: "payment", ID: "pay_2", Status: "failed"})
+
+		assert.Empty(t, events.of("session-1"))
+	})
+
+	t.Run("ends subscriptions at a final status", func(t *testing.T) {
+		events := newRecorder()
+		bus := NewBus(0, events.notify)
+		bus.Subscribe("session-1", paymentWatch("authorized", nil))
+
+		bus.Publish(Event{Entity: "payment", ID: "pay_1", Status: "captured"})
+
+		delivered := events.of("session-1")
+		require.Len(t, delivered, 1)
+		assert.True(t, delivered[0].Final)
+		assert.False(t, bus.Subscribed("session-1", "payment", "pay_1"))
+	})
+
+	t.Run("drops the subscriptions of ended sessions", func(t *testing.T) {
+		events := newRecorder()
+		bus := NewBus(0, events.notify)
+		bus.Subscribe("session-1", paymentWatch("created", nil))
+		bus.Subscribe("session-2", paymentWatch("created", nil))
+		events.end("session-1")
+
+		bus.Publish(Event{Entity: "payment", ID: "pay_1", Status: "authorized"})
+
+		assert.False(t, bus.Subscribed("session-1", "payment", "pay_1"))
+		assert.True(t, bus.Subscribed("session-2", "payment", "pay_1"))
+		assert.Len(t, events.of("session-2"), 1)
+	})
+}
+
+func TestBusUnsubscribe(t *testing.T) {
+	events := newRecorder()
+	bus := NewBus(0, events.notify)
+	bus.Subscribe("session-1", paymentWatch("created", nil))
+
+	assert.False(t, bus.Unsubscribe("session-2", "payment", "pay_1"))
+	assert.True(t, bus.Unsubscribe("session-1", "payment", "pay_1"))
+	assert.False(t, bus.Subscribed("session-1", "payment", "pay_1"))
+
+	bus.Publish(Event{Entity: "payment", ID: "pay_1", Status: "authorized"})
+	assert.Empty(t, events.of("session-1"))
+}
+
+func TestBusPoll(t *testing.T) {
+	var mu sync.Mutex
+	statuses := []string{"created", "authorized", "captured"}
+	fetches := 0
+	fetch := func(ctx context.Context) (map[string]interface{}, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		status := statuses[min(fetches, len(statuses)-1)]
+		fetches++
+		return map[string]interface{}{"id": "pay_1", "status": status}, nil
+	}
+
+	events := newRecorder()
+	bus := NewBus(time.Millisecond, events.notify)
+	bus.Subscribe("session-1", paymentWatch("created", fetch))
+
+	require.Eventually(t, func() bool {
+		return !bus.Subscribed("session-1", "payment", "pay_1")
+	}, time.Second, time.Millisecond)
+
+	delivered := events.of("session-1")
+	require.Len(t, delivered, 2)
+	assert.Equal(t, "payment.authorized", delivered[0].Type)
+	assert.Equal(t, SourcePoll, delivered[0].Source)
+	assert.Equal(t, map[string]interface{}{
+		"id": "pay_1", "status": "authorized",
+	}, delivered[0].Payload)
+	assert.Equal(t, "payment.captured", delivered[1].Type)
+	assert.True(t, delivered[1].Final)
+
+	// Polling stops once nothing is watched
+	require.Eventually(t, func() bool {
+		bus.mu.Lock()
+		defer bus.mu.Unlock()
+		return !bus.polling
+	}, time.Second, time.Millisecond)
+}
@@ -0,0 +1,236 @@
+package events
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WebhookEvent is an event Razorpay sent to the webhook endpoint
+type WebhookEvent struct {
+	// Seq orders the events by when they were received, starting at 1
+	Seq int64 `json:"seq"`
+	// ID is the ID Razorpay gave the event, the same for every delivery
+	ID        string                 `json:"id,omitempty"`
+	Event     string                 `json:"event"`
+	AccountID string                 `json:"account_id,omitempty"`
+	Contains  []string               `json:"contains,omitempty"`
+	Payload   map[string]interface{} `json:"payload"`
+	// CreatedAt is when the event occurred, as a Unix timestamp
+	CreatedAt  int64     `json:"created_at,omitempty"`
+	ReceivedAt time.Time `json:"received_at"`
+}
+
+// entities returns the entities in the payload of the event, by name, such
+// as the payment of a payment.captured event
+func (e WebhookEvent) entities() map[string]map[string]interface{} {
+	entities := make(map[string]map[string]interface{})
+	for name, value := range e.Payload {
+		wrapper, _ := value.(map[string]interface{})
+		if entity, ok := wrapper["entity"].(map[string]interface{}); ok {
+			entities[name] = entity
+		}
+	}
+	return entities
+}
+
+// Changes returns the changes of the entities in the payload of the event,
+// to publish them to the sessions subscribed to the entities
+func (e WebhookEvent) Changes() []Event {
+	var changes []Event
+	for name, entity := range e.entities() {
+		id, _ := entity["id"].(string)
+		status, _ := entity["status"].(string)
+		if id == "" || status == "" {
+			continue
+		}
+		changes = append(changes, Event{
+			Entity:  name,
+			ID:      id,
+			Status:  status,
+			Source:  SourceWebhook,
+			Payload: entity,
+		})
+	}
+	return changes
+}
+
+// Filter selects webhook events
+type Filter struct {
+	// Events are the types of the events to select, such as
+	// payment.captured, or payment.* for every payment event. Empty selects
+	// every type.
+	Events []string
+	// EntityID selects the events whose payload has the entity, such as
+	// pay_29QQoUBi66xm2f
+	EntityID string
+	// AfterSeq selects the events received after the event with the seq
+	AfterSeq int64
+	// AccountID selects the events of the account, such as
+	// acc_BFQ7uQEaa7j2z7. Empty selects the events of every account.
+	AccountID string
+}
+
+// matches reports whether the filter selects the event
+func (f Filter) matches(e WebhookEvent) bool {
+	if e.Seq <= f.AfterSeq {
+		return false
+	}
+	if f.AccountID != "" && e.AccountID != f.AccountID {
+		return false
+	}
+	if len(f.Events) > 0 && !matchesEventType(f.Events, e.Event) {
+		return false
+	}
+	if f.EntityID != "" {
+		for _, entity := range e.entities() {
+			if id, _ := entity["id"].(string); id == f.EntityID {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// matchesEventType reports whether the event type is one of the types,
+// where a type ending in .* matches every event of its entity
+func matchesEventType(types []string, eventType string) bool {
+	for _, t := range types {
+		if prefix, ok := strings.CutSuffix(t, "*"); ok &&
+			strings.HasPrefix(eventType, prefix) {
+			return true
+		}
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// History keeps the latest webhook events in a ring buffer
+type History struct {
+	mu sync.Mutex
+	// events holds the kept events, oldest at start
+	events []WebhookEvent
+	start  int
+	size   int
+	seq    int64
+	// added is closed, and replaced, when an event is added
+	added     chan struct{}
+	listeners []func(WebhookEvent)
+}
+
+// NewHistory creates a history that keeps the latest size events
+func NewHistory(size int) *History {
+	if size < 1 {
+		size = 1
+	}
+	return &History{
+		events: make([]WebhookEvent, 0, size),
+		size:   size,
+		added:  make(chan struct{}),
+	}
+}
+
+// Listen calls fn with every event added to the history
+func (h *History) Listen(fn func(WebhookEvent)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.listeners = append(h.listeners, fn)
+}
+
+// Add adds the event to the history, replacing the oldest event once the
+// history is full, and returns it with its seq. An event with the ID of a
+// kept event is a repeated delivery and is not added, so false is
+// returned.
+func (h *History) Add(event WebhookEvent) (WebhookEvent, bool) {
+	h.mu.Lock()
+	if event.ID != "" {
+		for _, kept := range h.events {
+			if kept.ID == event.ID {
+				h.mu.Unlock()
+				return kept, false
+			}
+		}
+	}
+
+	h.seq++
+	event.Seq = h.seq
+	if event.ReceivedAt.IsZero() {
+		event.ReceivedAt = time.Now().UTC()
+	}
+	if len(h.events) < h.size {
+		h.events = append(h.events, event)
+	} else {
+		h.events[h.start] = event
+		h.start = (h.start + 1) % h.size
+	}
+
+	close(h.added)
+	h.added = make(chan struct{})
+	listeners := h.listeners
+	h.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn(event)
+	}
+	return event, true
+}
+
+// LastSeq returns the seq of the latest event, 0 if none was received
+func (h *History) LastSeq() int64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.seq
+}
+
+// Recent returns the latest events the filter selects, newest first, at
+// most limit of them if limit is positive
+func (h *History) Recent(filter Filter, limit int) []WebhookEvent {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.recent(filter, limit)
+}
+
+func (h *History) recent(filter Filter, limit int) []WebhookEvent {
+	selected := []WebhookEvent{}
+	for i := len(h.events) - 1; i >= 0; i-- {
+		event := h.events[(h.start+i)%len(h.events)]
+		if !filter.matches(event) {
+			continue
+		}
+		selected = append(selected, event)
+		if limit > 0 && len(selected) == limit {
+			break
+		}
+	}
+	return selected
+}
+
+// Wait returns the first event the filter selects, waiting for it to be
+// received if no kept event is selected. It returns the error of ctx once
+// ctx is done.
+func (h *History) Wait(ctx context.Context, filter Filter) (
+	WebhookEvent, error) {
+	for {
+		h.mu.Lock()
+		selected := h.recent(filter, 0)
+		added := h.added
+		h.mu.Unlock()
+
+		if len(selected) > 0 {
+			return selected[len(selected)-1], nil
+		}
+
+		select {
+		case <-added:
+		case <-ctx.Done():
+			return WebhookEvent{}, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// paymentEvent returns a webhook event about a payment
+func paymentEvent(id, eventType, paymentID, status string) WebhookEvent {
+	return WebhookEvent{
+		ID:    id,
+		Event: eventType,
+		Payload: map[string]interface{}{
+			"payment": map[string]interface{}{
+				"entity": map[string]interface{}{
+					"id": paymentID, "status": status,
+				},
+			},
+		},
+	}
+}
+
+// seqs returns the seqs of the events
+func seqs(events []WebhookEvent) []int64 {
+	result := []int64{}
+	for _, event := range events {
+		result = append(result, event.Seq)
+	}
+	return result
+}
+
+func TestHistoryAdd(t *testing.T) {
+	t.Run("keeps the latest events", func(t *testing.T) {
+		history := NewHistory(2)
+		for _, id := range []string{"evt_1", "evt_2", "evt_3"} {
+			history.Add(paymentEvent(id, "payment.authorized", "pay_1",
+				"authorized"))
+		}
+
+		assert.Equal(t, []int64{3, 2}, seqs(history.Recent(Filter{}, 0)))
+		assert.Equal(t, int64(3), history.LastSeq())
+	})
+
+	t.Run("skips repeated deliveries", func(t *testing.T) {
+		history := NewHistory(10)
+		first, added := history.Add(paymentEvent("evt_1",
+			"payment.captured", "pay_1", "captured"))
+		require.True(t, added)
+		assert.False(t, first.ReceivedAt.IsZero())
+
+		repeated, added := history.Add(paymentEvent("evt_1",
+			"payment.captured", "pay_1", "captured"))
+		assert.False(t, added)
+		assert.Equal(t, first, repeated)
+		assert.Equal(t, int64(1), history.LastSeq())
+	})
+
+	t.Run("calls listeners", func(t *testing.T) {
+		history := NewHistory(10)
+		var received []WebhookEvent
+		history.Listen(func(event WebhookEvent) {
+			received = append(received, event)
+		})
+
+		history.Add(paymentEvent("evt_1", "payment.captured", "pay_1",
+			"captured"))
+		history.Add(paymentEvent("evt_1", "payment.captured", "pay_1",
+			"captured"))
+
+		require.Len(t, received, 1)
+		assert.Equal(t, int64(1), received[0].Seq)
+	})
+}
+
+func TestHistoryRecent(t *testing.T) {
+	history := NewHistory(10)
+	history.Add(paymentEvent("evt_1", "payment.authorized", "pay_1",
+		"authorized"))
+	history.Add(paymentEvent("evt_2", "payment.captured", "pay_1",
+		"captured"))
+	history.Add(paymentEvent("evt_3", "payment.failed", "pay_2", "failed"))
+	history.Add(WebhookEvent{ID: "evt_4", Event: "order.paid",
+		AccountID: "acc_1"})
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		limit    int
+		expected []int64
+	}{
+		{
+			name:     "every event, newest first",
+			expected: []int64{4, 3, 2, 1},
+		},
+		{
+			name:     "at most limit events",
+			limit:    2,
+			expected: []int64{4, 3},
+		},
+		{
+			name:     "events of the types",
+			filter:   Filter{Events: []string{"payment.failed", "order.paid"}},
+			expected: []int64{4, 3},
+		},
+		{
+			name:     "events of the entity type",
+			filter:   Filter{Events: []string{"payment.*"}},
+			expected: []int64{3, 2, 1},
+		},
+		{
+			name:     "events about the entity",
+			filter:   Filter{EntityID: "pay_1"},
+			expected: []int64{2, 1},
+		},
+		{
+			name:     "events after the seq",
+			filter:   Filter{AfterSeq: 2},
+			expected: []int64{4, 3},
+		},
+		{
+			name:     "events of the account",
+			filter:   Filter{AccountID: "acc_1"},
+			expected: []int64{4},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected,
+				seqs(history.Recent(tc.filter, tc.limit)))
+		})
+	}
+}
+
+func TestHistoryWait(t *testing.T) {
+	t.Run("returns the oldest kept event selected", func(t *testing.T) {
+		history := NewHistory(10)
+		history.Add(paymentEvent("evt_1", "payment.captured", "pay_1",
+			"captured"))
+		history.Add(paymentEvent("evt_2", "payment.captured", "pay_2",
+			"captured"))
+
+		event, err := history.Wait(context.Background(),
+			Filter{Events: []string{"payment.captured"}})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), event.Seq)
+	})
+
+	t.Run("waits for the event", func(t *testing.T) {
+		history := NewHistory(10)
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			history.Add(paymentEvent("evt_1", "payment.authorized", "pay_1",
+				"authorized"))
+			history.Add(paymentEvent("evt_2", "payment.captured", "pay_1",
+				"captured"))
+		}()
+
+		event, err := history.Wait(context.Background(),
+			Filter{Events: []string{"payment.captured"}})
+		require.NoError(t, err)
+		assert.Equal(t, "evt_2", event.ID)
+	})
+
+	t.Run("stops when the context is done", func(t *testing.T) {
+		history := NewHistory(10)
+		ctx, cancel := context.WithTimeout(context.Background(),
+			10*time.Millisecond)
+		defer cancel()
+
+		_, err := history.Wait(ctx, Filter{})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestWebhookEventChanges(t *testing.T) {
+	event := paymentEvent("evt_1", "payment.captured", "pay_1", "captured")
+	event.Payload["order"] = map[string]interface{}{
+		"entity": map[string]interface{}{"id": "order_1"},
+	}
+
+	assert.Equal(t, []Event{{
+		Entity: "payment",
+		ID:     "pay_1",
+		Status: "captured",
+		Source: SourceWebhook,
+		Payload: map[string]interface{}{
+			"id": "pay_1", "status": "captured",
+		},
+	}}, event.Changes())
+}
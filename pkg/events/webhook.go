@@ -0,0 +1,95 @@
+package events
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// Headers of webhook requests
+const (
+	SignatureHeader = "X-Razorpay-Signature"
+	EventIDHeader   = "X-Razorpay-Event-Id"
+)
+
+// maxWebhookSize is the largest webhook request body accepted, in bytes
+const maxWebhookSize = 1 << 20
+
+// webhookBody is the body of a webhook request
+type webhookBody struct {
+	Event     string                 `json:"event"`
+	AccountID string                 `json:"account_id"`
+	Contains  []string               `json:"contains"`
+	Payload   map[string]interface{} `json:"payload"`
+	CreatedAt int64                  `json:"created_at"`
+}
+
+// WebhookHandler returns the handler of the webhook endpoint Razorpay
+// sends events to. Events signed with the secret of the webhook are added
+// to the history, and requests without a valid signature are rejected.
+func WebhookHandler(secret string, history *History) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body,
+			maxWebhookSize))
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "request body too large",
+				http.StatusRequestEntityTooLarge)
+			return
+		}
+		if err != nil {
+			http.Error(w, "failed to read request body",
+				http.StatusBadRequest)
+			return
+		}
+
+		if !validSignature(body, r.Header.Get(SignatureHeader), secret) {
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+
+		var event webhookBody
+		if err := json.Unmarshal(body, &event); err != nil ||
+			event.Event == "" {
+			http.Error(w, "invalid event", http.StatusBadRequest)
+			return
+		}
+
+		history.Add(WebhookEvent{
+			ID:        r.Header.Get(EventIDHeader),
+			Event:     event.Event,
+			AccountID: event.AccountID,
+			Contains:  event.Contains,
+			Payload:   event.Payload,
+			CreatedAt: event.CreatedAt,
+		})
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// Sign returns the signature of a webhook request body, the hex encoded
+// HMAC-SHA256 of the body keyed with the secret of the webhook
+func Sign(body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// validSignature reports whether the signature is the signature of the
+// body
+func validSignature(body []byte, signature, secret string) bool {
+	if signature == "" {
+		return false
+	}
+	return hmac.Equal([]byte(Sign(body, secret)), []byte(signature))
+}
@@ -0,0 +1,107 @@
+package events
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookHandler(t *testing.T) {
+	const secret = "webhook-secret"
+	const body = `{"event":"payment.captured","account_id":"acc_1",` +
+		`"contains":["payment"],"created_at":1700000000,"payload":` +
+		`{"payment":{"entity":{"id":"pay_1","status":"captured"}}}}`
+
+	// send sends a webhook request to a handler adding to history
+	send := func(
+		history *History,
+		method, body, signature string,
+	) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/webhooks/razorpay",
+			strings.NewReader(body))
+		req.Header.Set(EventIDHeader, "evt_1")
+		if signature != "" {
+			req.Header.Set(SignatureHeader, signature)
+		}
+		rec := httptest.NewRecorder()
+		WebhookHandler(secret, history).ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("adds signed events", func(t *testing.T) {
+		history := NewHistory(10)
+		rec := send(history, http.MethodPost, body,
+			Sign([]byte(body), secret))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		received := history.Recent(Filter{}, 0)
+		require.Len(t, received, 1)
+		assert.Equal(t, "evt_1", received[0].ID)
+		assert.Equal(t, "payment.captured", received[0].Event)
+		assert.Equal(t, "acc_1", received[0].AccountID)
+		assert.Equal(t, []string{"payment"}, received[0].Contains)
+		assert.Equal(t, int64(1700000000), received[0].CreatedAt)
+
+		// Repeated deliveries are acknowledged but kept once
+		rec = send(history, http.MethodPost, body,
+			Sign([]byte(body), secret))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, int64(1), history.LastSeq())
+	})
+
+	tests := []struct {
+		name      string
+		method    string
+		body      string
+		signature string
+		expected  int
+	}{
+		{
+			name:      "method other than POST",
+			method:    http.MethodGet,
+			signature: Sign(nil, secret),
+			expected:  http.StatusMethodNotAllowed,
+		},
+		{
+			name:     "missing signature",
+			method:   http.MethodPost,
+			body:     body,
+			expected: http.StatusUnauthorized,
+		},
+		{
+			name:      "signature with another secret",
+			method:    http.MethodPost,
+			body:      body,
+			signature: Sign([]byte(body), "other-secret"),
+			expected:  http.StatusUnauthorized,
+		},
+		{
+			name:      "body that is not an event",
+			method:    http.MethodPost,
+			body:      `{"payload":{}}`,
+			signature: Sign([]byte(`{"payload":{}}`), secret),
+			expected:  http.StatusBadRequest,
+		},
+		{
+			name:   "body too large",
+			method: http.MethodPost,
+			body:   strings.Repeat("a", maxWebhookSize+1),
+			signature: Sign([]byte(strings.Repeat("a", maxWebhookSize+1)),
+				secret),
+			expected: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			history := NewHistory(10)
+			rec := send(history, tc.method, tc.body, tc.signature)
+			assert.Equal(t, tc.expected, rec.Code)
+			assert.Equal(t, int64(0), history.LastSeq())
+		})
+	}
+}
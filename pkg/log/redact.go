@@ -0,0 +1,181 @@
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// redacted replaces sensitive values in log output
+const redacted = "[REDACTED]"
+
+var (
+	// secretKeyPattern matches the names of fields holding secrets
+	secretKeyPattern = regexp.MustCompile(
+		`(?i)(?:secret|password|token|otp|cvv|pin)$`)
+	// secretFieldPattern matches fields holding secrets, such as
+	// "key_secret":"..." in JSON or otp=123456 in text. The field name must
+	// end with the sensitive word, so that token_id is kept.
+	secretFieldPattern = regexp.MustCompile(
+		`(?i)("?[\w-]*(?:secret|password|token|otp|cvv|pin)"?\s*[:=]\s*)` +
+			`("(?:[^"\\]|\\.)*"|[^\s,&}\]]+)`)
+	// authorizationPattern matches the credentials of Authorization headers
+	authorizationPattern = regexp.MustCompile(
+		`(?i)\b(Basic|Bearer)\s+[A-Za-z0-9._~+/=-]+`)
+	// emailPattern matches email addresses
+	emailPattern = regexp.MustCompile(
+		`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`)
+	// vpaPattern matches UPI virtual payment addresses, such as
+	// gaurav.kumar@okhdfc. Emails are masked before, so only handles
+	// without a domain are left.
+	vpaPattern = regexp.MustCompile(`[A-Za-z0-9._-]{2,256}@[A-Za-z]{2,64}\b`)
+	// cardNumberPattern matches runs of 13 to 19 digits, which may be
+	// separated by spaces or dashes
+	cardNumberPattern = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+	// phonePattern matches Indian mobile numbers, with or without the
+	// country code, and international numbers with a leading +
+	phonePattern = regexp.MustCompile(
+		`(?:\+91[ -]?|\b0?)[6-9]\d{9}\b|\+\d{1,3}[ -]?\d{6,14}\b`)
+)
+
+// Sanitize masks the card numbers, VPAs, email addresses, phone numbers,
+// OTPs and API secrets in s, so that request and response payloads can be
+// logged
+func Sanitize(s string) string {
+	s = secretFieldPattern.ReplaceAllStringFunc(s, func(field string) string {
+		match := secretFieldPattern.FindStringSubmatch(field)
+		if strings.HasPrefix(match[2], `"`) {
+			return match[1] + `"` + redacted + `"`
+		}
+		return match[1] + redacted
+	})
+	s = authorizationPattern.ReplaceAllString(s, "$1 "+redacted)
+	s = emailPattern.ReplaceAllString(s, "[REDACTED_EMAIL]")
+	s = vpaPattern.ReplaceAllString(s, "[REDACTED_VPA]")
+	s = cardNumberPattern.ReplaceAllStringFunc(s, maskCardNumber)
+	s = phonePattern.ReplaceAllString(s, "[REDACTED_PHONE]")
+	return s
+}
+
+// maskCardNumber masks all but the last four digits of a card number.
+// Digit runs that fail the Luhn check are not card numbers and are kept.
+func maskCardNumber(match string) string {
+	digits := strings.NewReplacer(" ", "", "-", "").Replace(match)
+	if !luhnValid(digits) {
+		return match
+	}
+	return strings.Repeat("*", len(digits)-4) + digits[len(digits)-4:]
+}
+
+// luhnValid reports whether a string of digits passes the Luhn check
+func luhnValid(digits string) bool {
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// redactingHandler sanitizes the message and attributes of records before
+// passing them to the next handler
+type redactingHandler struct {
+	next slog.Handler
+}
+
+// newRedactingHandler returns a handler that sanitizes records before
+// they are written by next
+func newRedactingHandler(next slog.Handler) slog.Handler {
+	return &redactingHandler{next: next}
+}
+
+// Enabled implements slog.Handler
+func (h *redactingHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.next.Enabled(ctx, l)
+}
+
+// Handle implements slog.Handler
+func (h *redactingHandler) Handle(ctx context.Context, r slog.Record) error {
+	sanitized := slog.NewRecord(r.Time, r.Level, Sanitize(r.Message), r.PC)
+	r.Attrs(func(attr slog.Attr) bool {
+		sanitized.AddAttrs(sanitizeAttr(attr))
+		return true
+	})
+	return h.next.Handle(ctx, sanitized)
+}
+
+// WithAttrs implements slog.Handler
+func (h *redactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	sanitized := make([]slog.Attr, 0, len(attrs))
+	for _, attr := range attrs {
+		sanitized = append(sanitized, sanitizeAttr(attr))
+	}
+	return &redactingHandler{next: h.next.WithAttrs(sanitized)}
+}
+
+// WithGroup implements slog.Handler
+func (h *redactingHandler) WithGroup(name string) slog.Handler {
+	return &redactingHandler{next: h.next.WithGroup(name)}
+}
+
+// sanitizeAttr sanitizes the value of an attribute. Values other than
+// strings, errors and groups, such as tool requests and results, are
+// rendered as JSON first, so that their fields can be masked.
+func sanitizeAttr(attr slog.Attr) slog.Attr {
+	value := attr.Value.Resolve()
+	if secretKeyPattern.MatchString(attr.Key) &&
+		value.Kind() != slog.KindGroup {
+		return slog.String(attr.Key, redacted)
+	}
+
+	switch value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, Sanitize(value.String()))
+	case slog.KindGroup:
+		group := value.Group()
+		attrs := make([]slog.Attr, 0, len(group))
+		for _, a := range group {
+			attrs = append(attrs, sanitizeAttr(a))
+		}
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(attrs...)}
+	case slog.KindAny:
+		return slog.String(attr.Key, Sanitize(renderValue(value.Any())))
+	default:
+		return slog.Attr{Key: attr.Key, Value: value}
+	}
+}
+
+// renderValue renders a log value as text
+func renderValue(v interface{}) string {
+	switch v := v.(type) {
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	case nil:
+		return "<nil>"
+	}
+
+	// Named strings and numbers, such as mcp.MCPMethod, are not quoted
+	if kind := reflect.TypeOf(v).Kind(); kind <= reflect.Complex128 ||
+		kind == reflect.String {
+		return fmt.Sprint(v)
+	}
+
+	if data, err := json.Marshal(v); err == nil {
+		return string(data)
+	}
+	return fmt.Sprintf("%+v", v)
+}
@@ -103,9 +103,10 @@ func NewSlogger() (*slogLogger, error) {
 	// For stdio mode, always use stderr regardless of path
 	// This ensures logs don't interfere with MCP protocol on stdout
 	return &slogLogger{
-		logger: slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
-			Level: slog.LevelInfo,
-		})),
+		logger: slog.New(newRedactingHandler(
+			slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+				Level: slog.LevelInfo,
+			}))),
 	}, nil
 }
 
@@ -113,9 +114,10 @@ func NewSloggerWithStdout(config *Config) (*slogLogger, error) {
 	// For stdio mode, always use Stdout regardless of path
 	// This ensures logs don't interfere with MCP protocol on stdout
 	return &slogLogger{
-		logger: slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-			Level: config.slog.logLevel,
-		})),
+		logger: slog.New(newRedactingHandler(
+			slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+				Level: config.slog.logLevel,
+			}))),
 	}, nil
 }
 
@@ -135,9 +137,8 @@ func getDefaultLogPath() string {
 // NewSloggerWithFile returns a new slog.Logger.
 // If path to log file is not provided then
 // logger uses a default path next to the executable
-// If the log file cannot be opened, falls back to stderr
-//
-// TODO: add redaction of sensitive data
+// If the log file cannot be opened, falls back to stderr.
+// Sensitive data is masked before it is written, see Sanitize.
 func NewSloggerWithFile(path string) (*slogLogger, error) {
 	if path == "" {
 		path = getDefaultLogPath()
@@ -151,7 +152,8 @@ func NewSloggerWithFile(path string) (*slogLogger, error) {
 			"Warning: Failed to open log file: %v\nFalling back to stderr\n",
 			err,
 		)
-		logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+		logger := slog.New(newRedactingHandler(
+			slog.NewTextHandler(os.Stderr, nil)))
 		noop := func() error { return nil }
 		return &slogLogger{
 			logger: logger,
@@ -161,7 +163,7 @@ func NewSloggerWithFile(path string) (*slogLogger, error) {
 
 	fmt.Fprintf(os.Stderr, "logs are stored in: %v\n", path)
 	return &slogLogger{
-		logger: slog.New(slog.NewTextHandler(file, nil)),
+		logger: slog.New(newRedactingHandler(slog.NewTextHandler(file, nil))),
 		closer: func() error {
 			if err := file.Close(); err != nil {
 				log.Printf("close log file: %v", err)
@@ -0,0 +1,102 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// AccountParameter is the name of the parameter, accepted by every tool of
+// a server with accounts, that selects the account a call is made with
+const AccountParameter = "account"
+
+// AccountFunc prepares the context of a call made with the named account,
+// such as by attaching its client and its cache scope. The name is empty
+// when the call does not select an account. It returns an error if the
+// call cannot be made with the account.
+type AccountFunc func(
+	ctx context.Context,
+	account string,
+) (context.Context, error)
+
+// accountsOption is the option value that sets the accounts of the server
+type accountsOption struct {
+	names          []string
+	defaultAccount string
+	use            AccountFunc
+}
+
+// WithAccounts returns a server option that adds the account parameter to
+// every tool, and prepares the context of each call with use. The names
+// and default account describe the parameter. No names leaves the tools
+// as they are.
+func WithAccounts(
+	names []string,
+	defaultAccount string,
+	use AccountFunc,
+) ServerOption {
+	return func(s OptionSetter) error {
+		return s.SetOption(accountsOption{
+			names:          names,
+			defaultAccount: defaultAccount,
+			use:            use,
+		})
+	}
+}
+
+// withAccounts wraps the handler of a tool so that calls are made with the
+// account they select, and declares the account parameter
+func (s *Mark3labsImpl) withAccounts(
+	serverTool server.ServerTool,
+) server.ServerTool {
+	if len(s.accounts.names) == 0 {
+		return serverTool
+	}
+
+	description := fmt.Sprintf("Optional: Razorpay account to make the "+
+		"call with, one of %s", strings.Join(s.accounts.names, ", "))
+	if s.accounts.defaultAccount != "" {
+		description += fmt.Sprintf(" (default: %s)",
+			s.accounts.defaultAccount)
+	}
+	serverTool.Tool.InputSchema.Properties = withProperty(
+		serverTool.Tool.InputSchema.Properties, AccountParameter,
+		map[string]any{
+			"type":        "string",
+			"description": description,
+			"enum":        s.accounts.names,
+		})
+
+	handler := serverTool.Handler
+	serverTool.Handler = func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		account, _ := req.GetArguments()[AccountParameter].(string)
+		ctx, err := s.accounts.use(ctx, account)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return handler(ctx, req)
+	}
+
+	return serverTool
+}
+
+// withProperty returns a copy of the properties of an input schema with
+// the property added, so that the schema of other tools is not changed
+func withProperty(
+	properties map[string]any,
+	name string,
+	property map[string]any,
+) map[string]any {
+	result := make(map[string]any, len(properties)+1)
+	for key, value := range properties {
+		result[key] = value
+	}
+	result[name] = property
+	return result
+}
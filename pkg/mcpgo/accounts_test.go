@@ -0,0 +1,105 @@
+package mcpgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
+)
+
+func TestWithAccounts(t *testing.T) {
+	// useAccount records the account of calls as their caller, and
+	// rejects the account named blocked
+	useAccount := func(
+		ctx context.Context,
+		account string,
+	) (context.Context, error) {
+		if account == "blocked" {
+			return ctx, errors.New("account blocked is not allowed")
+		}
+		return contextkey.WithCaller(ctx, "account:"+account), nil
+	}
+
+	// newServer returns a server with a tool that returns the caller of
+	// its context
+	newServer := func(opts ...ServerOption) *Mark3labsImpl {
+		tool := NewTool("fetch_caller", "Returns the caller", nil,
+			func(ctx context.Context, r CallToolRequest) (*ToolResult, error) {
+				return NewToolResultText(contextkey.CallerFromContext(ctx)), nil
+			})
+		tool.SetReadOnly(true)
+		srv := NewMcpServer("test-server", "1.0.0", opts...)
+		srv.AddTools(tool)
+		return srv
+	}
+
+	// call calls the tool with the arguments
+	call := func(
+		srv *Mark3labsImpl,
+		args map[string]interface{},
+	) *mcp.CallToolResult {
+		serverTool := srv.McpServer.GetTool("fetch_caller")
+		require.NotNil(t, serverTool)
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := serverTool.Handler(context.Background(), request)
+		require.NoError(t, err)
+		return result
+	}
+
+	// text returns the text of a result
+	text := func(result *mcp.CallToolResult) string {
+		content, ok := mcp.AsTextContent(result.Content[0])
+		require.True(t, ok)
+		return content.Text
+	}
+
+	t.Run("leaves tools as they are without accounts", func(t *testing.T) {
+		srv := newServer(WithAccounts(nil, "", useAccount))
+
+		tool := srv.McpServer.GetTool("fetch_caller")
+		require.NotNil(t, tool)
+		assert.NotContains(t, tool.Tool.InputSchema.Properties,
+			AccountParameter)
+		assert.Empty(t, text(call(srv, map[string]interface{}{})))
+	})
+
+	t.Run("declares the account parameter", func(t *testing.T) {
+		srv := newServer(WithAccounts([]string{"prod_in", "prod_intl"},
+			"prod_in", useAccount))
+
+		tool := srv.McpServer.GetTool("fetch_caller")
+		require.NotNil(t, tool)
+		assert.Equal(t, map[string]any{
+			"type": "string",
+			"description": "Optional: Razorpay account to make the call " +
+				"with, one of prod_in, prod_intl (default: prod_in)",
+			"enum": []string{"prod_in", "prod_intl"},
+		}, tool.Tool.InputSchema.Properties[AccountParameter])
+	})
+
+	t.Run("makes calls with the account", func(t *testing.T) {
+		srv := newServer(WithAccounts([]string{"prod_in", "prod_intl"},
+			"", useAccount))
+
+		assert.Equal(t, "account:prod_intl", text(call(srv,
+			map[string]interface{}{AccountParameter: "prod_intl"})))
+		assert.Equal(t, "account:", text(call(srv,
+			map[string]interface{}{})))
+	})
+
+	t.Run("rejects calls the account cannot make", func(t *testing.T) {
+		srv := newServer(WithAccounts([]string{"blocked"}, "", useAccount))
+
+		result := call(srv, map[string]interface{}{
+			AccountParameter: "blocked",
+		})
+		assert.True(t, result.IsError)
+		assert.Equal(t, "account blocked is not allowed", text(result))
+	})
+}
@@ -0,0 +1,95 @@
+package mcpgo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// CurrencyCheckFunc checks the currency of a call to a tool that declares
+// amounts. It returns the problem found, or an empty string if the
+// currency is supported.
+type CurrencyCheckFunc func(currency string) string
+
+// AmountCheckFunc checks an amount of a call, in currency sub-units, named
+// by its path, such as transfers[0].amount. The currency is the currency
+// of the closest object around the amount that has one, such as
+// orders[0].currency, or else of the call, empty if none has one. It
+// returns the problem found, or an empty string if the amount is accepted.
+type AmountCheckFunc func(name, currency string, amount float64) string
+
+// amountChecksOption is the option value that holds the checks of the
+// currency and amounts of calls
+type amountChecksOption struct {
+	currency CurrencyCheckFunc
+	amount   AmountCheckFunc
+}
+
+// WithAmountChecks returns a server option that checks the currency and
+// the amounts of calls to tools that declare amounts, after their amounts
+// are converted to currency sub-units, and rejects the calls that fail as
+// validation errors. Nil funcs check nothing.
+func WithAmountChecks(
+	currency CurrencyCheckFunc,
+	amount AmountCheckFunc,
+) ServerOption {
+	return func(s OptionSetter) error {
+		return s.SetOption(amountChecksOption{
+			currency: currency,
+			amount:   amount,
+		})
+	}
+}
+
+// errAmountCheck stops the walk over the amounts of a call at the first
+// amount that fails its check
+var errAmountCheck = errors.New("amount check failed")
+
+// checkAmounts returns the error result of a call whose currency or
+// amounts fail the checks of the server, nil if they pass
+func (s *Mark3labsImpl) checkAmounts(
+	ctx context.Context,
+	args map[string]interface{},
+	amounts []string,
+) *mcp.CallToolResult {
+	currency, _ := args["currency"].(string)
+	if currency != "" && s.amountChecks.currency != nil {
+		if problem := s.amountChecks.currency(currency); problem != "" {
+			return validationError(ctx, "currency", "invalid currency: "+problem)
+		}
+	}
+
+	// The amounts are mapped to themselves, which leaves the arguments as
+	// they are. The currencies of the objects of the amounts, such as the
+	// orders of a batch, are checked as they are reached.
+	var field, problem string
+	checked := map[string]bool{"currency": true}
+	_ = mapAmounts(args, amounts, func(
+		name string,
+		currency amountCurrency,
+		amount float64,
+	) (float64, error) {
+		if !checked[currency.name] && s.amountChecks.currency != nil {
+			checked[currency.name] = true
+			problem = s.amountChecks.currency(currency.code)
+			if problem != "" {
+				field = currency.name
+				return 0, errAmountCheck
+			}
+		}
+		if s.amountChecks.amount == nil {
+			return amount, nil
+		}
+		problem = s.amountChecks.amount(name, currency.code, amount)
+		if problem != "" {
+			field = name
+			return 0, errAmountCheck
+		}
+		return amount, nil
+	})
+	if problem != "" {
+		return validationError(ctx, field, "invalid "+field+": "+problem)
+	}
+	return nil
+}
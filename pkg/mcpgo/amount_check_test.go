@@ -0,0 +1,132 @@
+package mcpgo
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAmountChecks(t *testing.T) {
+	// checkCurrency supports INR and USD
+	checkCurrency := func(currency string) string {
+		if currency != "INR" && currency != "USD" {
+			return currency + " is not supported"
+		}
+		return ""
+	}
+	// checkAmount rejects amounts below 100, and records the amounts
+	var checked []string
+	checkAmount := func(name, currency string, amount float64) string {
+		checked = append(checked, fmt.Sprintf("%s %s %v", name, currency,
+			amount))
+		if amount < 100 {
+			return "below 100"
+		}
+		return ""
+	}
+
+	t.Run("checks the converted amounts", func(t *testing.T) {
+		checked = nil
+		srv := newAmountTestServer(
+			WithAmountChecks(checkCurrency, checkAmount))
+
+		_, errText := callAmountTool(t, srv, `{"amount": 295, `+
+			`"currency": "INR", "amount_unit": "rupees", `+
+			`"transfers": [{"amount": 1.5}]}`)
+
+		require.Empty(t, errText)
+		assert.Equal(t, []string{
+			"amount INR 29500",
+			"transfers[0].amount INR 150",
+		}, checked)
+	})
+
+	t.Run("rejects amounts that fail the check", func(t *testing.T) {
+		srv := newAmountTestServer(
+			WithAmountChecks(checkCurrency, checkAmount))
+
+		result := callToolWithArgs(t, srv, "create_order", `{"amount": 500, `+
+			`"currency": "USD", "transfers": [{"amount": 50}]}`)
+
+		assert.True(t, result.IsError)
+		assert.Equal(t, "invalid transfers[0].amount: below 100",
+			resultText(result))
+		content, ok := result.StructuredContent.(map[string]interface{})
+		require.True(t, ok)
+		details, ok := content["error"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, ValidationErrorCode, details["code"])
+		assert.Equal(t, "transfers[0].amount", details["field"])
+	})
+
+	t.Run("checks amounts in the currency of their object",
+		func(t *testing.T) {
+			checked = nil
+			srv := newAmountTestServer(
+				WithAmountChecks(checkCurrency, checkAmount))
+
+			_, errText := callAmountTool(t, srv, `{"amount": 500, `+
+				`"currency": "INR", "transfers": [{"amount": 200}, `+
+				`{"amount": 300, "currency": "USD"}]}`)
+
+			require.Empty(t, errText)
+			assert.Equal(t, []string{
+				"amount INR 500",
+				"transfers[0].amount INR 200",
+				"transfers[1].amount USD 300",
+			}, checked)
+		})
+
+	t.Run("rejects unsupported currencies of objects", func(t *testing.T) {
+		srv := newAmountTestServer(
+			WithAmountChecks(checkCurrency, checkAmount))
+
+		result := callToolWithArgs(t, srv, "create_order", `{"amount": 500, `+
+			`"currency": "INR", "transfers": [{"amount": 200, `+
+			`"currency": "EUR"}]}`)
+
+		assert.True(t, result.IsError)
+		assert.Equal(t, "invalid transfers[0].currency: EUR is not supported",
+			resultText(result))
+		content, ok := result.StructuredContent.(map[string]interface{})
+		require.True(t, ok)
+		details, ok := content["error"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, "transfers[0].currency", details["field"])
+	})
+
+	t.Run("rejects unsupported currencies", func(t *testing.T) {
+		srv := newAmountTestServer(
+			WithAmountChecks(checkCurrency, checkAmount))
+
+		_, errText := callAmountTool(t, srv,
+			`{"amount": 500, "currency": "EUR"}`)
+
+		assert.Equal(t, "invalid currency: EUR is not supported", errText)
+	})
+
+	t.Run("checks the default currency of the toolset",
+		func(t *testing.T) {
+			srv := newAmountTestServer(
+				WithAmountChecks(checkCurrency, nil),
+				WithToolsetDefaults(map[string]map[string]interface{}{
+					"orders": {"currency": "EUR"},
+				}))
+
+			_, errText := callAmountTool(t, srv, `{"amount": 100}`)
+
+			assert.Equal(t, "invalid currency: EUR is not supported",
+				errText)
+		})
+
+	t.Run("checks nothing without checks", func(t *testing.T) {
+		srv := newAmountTestServer(WithAmountChecks(nil, nil))
+
+		_, errText := callAmountTool(t, srv,
+			`{"amount": 1, "currency": "EUR"}`)
+
+		assert.Empty(t, errText)
+	})
+}
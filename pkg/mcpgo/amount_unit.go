@@ -0,0 +1,250 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// AmountUnitParameter is the parameter that selects the unit of the
+// amounts of a call to a tool that declares amounts
+const AmountUnitParameter = "amount_unit"
+
+// Units of the amounts of tool calls
+const (
+	// AmountUnitPaise passes amounts in the smallest currency sub-unit, as
+	// the Razorpay API expects them
+	AmountUnitPaise = "paise"
+	// AmountUnitRupees passes INR amounts in rupees, which are converted to
+	// paise before the call runs
+	AmountUnitRupees = "rupees"
+)
+
+// amountUnitOption is the option value that sets the default unit of
+// amounts
+type amountUnitOption string
+
+// WithAmountUnit returns a server option that sets the unit of the amounts
+// of calls that do not pass the amount_unit parameter. Empty keeps paise.
+func WithAmountUnit(unit string) ServerOption {
+	return func(s OptionSetter) error {
+		return s.SetOption(amountUnitOption(unit))
+	}
+}
+
+// WithAmounts declares the parameters of the tool that hold amounts in
+// currency sub-units, and adds the amount_unit parameter that lets calls
+// pass them in rupees instead. Parameters of objects, or of the objects of
+// arrays, are named by their path, such as transfers.amount.
+func (t *mark3labsToolImpl) WithAmounts(params ...string) *mark3labsToolImpl {
+	t.amountParams = params
+	t.parameters = append(t.parameters, WithString(
+		AmountUnitParameter,
+		Description("Unit of the amounts of this call: paise, the "+
+			"smallest currency sub-unit (e.g., 29500 for ₹295), or rupees "+
+			"(e.g., 295 or 295.50), which are converted to paise. Rupees "+
+			"only apply to INR amounts"),
+		Enum(AmountUnitPaise, AmountUnitRupees),
+	))
+	return t
+}
+
+// amounts returns the parameters declared with WithAmounts
+func (t *mark3labsToolImpl) amounts() []string {
+	return t.amountParams
+}
+
+// withAmountUnit wraps the handler of a tool that declares amounts so that
+// the amounts of calls in rupees are converted to paise, and then checked,
+// before the call runs. The policy and confirmations of the server, which
+// run after it, see the converted amounts. It runs after the toolset
+// defaults are applied, so rupees are rejected for a default currency
+// other than INR.
+func (s *Mark3labsImpl) withAmountUnit(
+	serverTool server.ServerTool,
+	amounts []string,
+) server.ServerTool {
+	if len(amounts) == 0 {
+		return serverTool
+	}
+
+	handler := serverTool.Handler
+	serverTool.Handler = func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		args := make(map[string]interface{}, len(req.GetArguments()))
+		for name, value := range req.GetArguments() {
+			args[name] = value
+		}
+
+		unit := AmountUnitPaise
+		if value, ok := args[AmountUnitParameter]; ok {
+			unit, _ = value.(string)
+			delete(args, AmountUnitParameter)
+		}
+
+		switch unit {
+		case AmountUnitPaise:
+		case AmountUnitRupees:
+			if err := convertRupees(args, amounts); err != nil {
+				return validationError(ctx, AmountUnitParameter,
+					err.Error()), nil
+			}
+		default:
+			return validationError(ctx, AmountUnitParameter, fmt.Sprintf(
+				"invalid %s: must be %s or %s", AmountUnitParameter,
+				AmountUnitPaise, AmountUnitRupees)), nil
+		}
+
+		if result := s.checkAmounts(ctx, args, amounts); result != nil {
+			return result, nil
+		}
+
+		req.Params.Arguments = args
+		return handler(ctx, req)
+	}
+
+	// Calls that pass no unit get the unit of the server
+	if s.amountUnit != "" {
+		serverTool = withParameterDefaults(serverTool,
+			map[string]interface{}{AmountUnitParameter: s.amountUnit})
+	}
+
+	return serverTool
+}
+
+// convertRupees converts the amounts of the arguments from rupees to
+// paise, in place. Amounts that are not numbers are left for the tool to
+// reject.
+func convertRupees(args map[string]interface{}, amounts []string) error {
+	if currency, ok := args["currency"].(string); ok && currency != "INR" {
+		return rupeesCurrencyError(currency)
+	}
+
+	return mapAmounts(args, amounts, func(
+		name string,
+		currency amountCurrency,
+		rupees float64,
+	) (float64, error) {
+		if currency.code != "" && currency.code != "INR" {
+			return 0, rupeesCurrencyError(currency.code)
+		}
+		paise := rupees * 100
+		rounded := math.Round(paise)
+		if math.Abs(paise-rounded) > 1e-6 {
+			return 0, fmt.Errorf("invalid %s: %s rupees has more than 2 "+
+				"decimal places", name,
+				strconv.FormatFloat(rupees, 'f', -1, 64))
+		}
+		return rounded, nil
+	})
+}
+
+// rupeesCurrencyError returns the error of a call in rupees with amounts
+// in another currency
+func rupeesCurrencyError(currency string) error {
+	return fmt.Errorf("invalid %s: rupees only apply to INR amounts, "+
+		"pass %s amounts in paise, the smallest currency sub-unit",
+		AmountUnitParameter, currency)
+}
+
+// amountCurrency is the currency of an amount of a call, and the
+// parameter it is passed in, such as orders[0].currency. It is the
+// currency of the closest object around the amount that has one, or the
+// currency of the call, and empty if none has one.
+type amountCurrency struct {
+	name string
+	code string
+}
+
+// amountFunc maps an amount of a call, named by its path, such as
+// transfers[0].amount
+type amountFunc func(
+	name string,
+	currency amountCurrency,
+	amount float64,
+) (float64, error)
+
+// mapAmounts replaces the amounts of the arguments with their value
+// mapped by fn, in place, and returns the first error of fn
+func mapAmounts(
+	args map[string]interface{},
+	amounts []string,
+	fn amountFunc,
+) error {
+	currency := amountCurrency{name: "currency"}
+	currency.code, _ = args["currency"].(string)
+
+	for _, amount := range amounts {
+		path := strings.Split(amount, ".")
+		value, ok := args[path[0]]
+		if !ok {
+			continue
+		}
+		mapped, err := mapAmount(value, path[0], path[1:], currency, fn)
+		if err != nil {
+			return err
+		}
+		args[path[0]] = mapped
+	}
+	return nil
+}
+
+// mapAmount returns a copy of the value with the amounts at the path
+// mapped by fn. The name of the value is the start of the names of its
+// amounts, and the currency is the currency of the objects around it.
+func mapAmount(
+	value interface{},
+	name string,
+	path []string,
+	currency amountCurrency,
+	fn amountFunc,
+) (interface{}, error) {
+	switch v := value.(type) {
+	case float64:
+		if len(path) > 0 {
+			return v, nil
+		}
+		return fn(name, currency, v)
+	case map[string]interface{}:
+		if len(path) == 0 {
+			return v, nil
+		}
+		field, ok := v[path[0]]
+		if !ok {
+			return v, nil
+		}
+		if code, ok := v["currency"].(string); ok && code != "" {
+			currency = amountCurrency{name: name + ".currency", code: code}
+		}
+		mapped, err := mapAmount(field, name+"."+path[0], path[1:],
+			currency, fn)
+		if err != nil {
+			return nil, err
+		}
+		object := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			object[key] = item
+		}
+		object[path[0]] = mapped
+		return object, nil
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			mapped, err := mapAmount(item, fmt.Sprintf("%s[%d]", name, i),
+				path, currency, fn)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = mapped
+		}
+		return items, nil
+	}
+	return value, nil
+}
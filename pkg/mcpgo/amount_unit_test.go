@@ -0,0 +1,187 @@
+package mcpgo
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newAmountTestServer creates a server with a create_order tool declaring
+// amounts, which returns the arguments it is called with
+func newAmountTestServer(opts ...ServerOption) *Mark3labsImpl {
+	tool := NewTool("create_order", "Creates an order", []ToolParameter{
+		WithNumber("amount"),
+		WithString("currency"),
+		WithArray("transfers"),
+	}, func(ctx context.Context, r CallToolRequest) (*ToolResult, error) {
+		return NewToolResultJSON(r.Arguments)
+	}).WithAmounts("amount", "transfers.amount")
+	tool.SetReadOnly(false)
+	tool.SetToolset("orders")
+
+	srv := NewMcpServer("test-server", "1.0.0",
+		append(opts, WithToolCapabilities(true))...)
+	srv.AddTools(tool)
+
+	return srv
+}
+
+// callAmountTool calls create_order and returns the arguments the tool
+// ran with, or the error result
+func callAmountTool(
+	t *testing.T,
+	srv *Mark3labsImpl,
+	args string,
+) (map[string]interface{}, string) {
+	t.Helper()
+
+	result := callToolWithArgs(t, srv, "create_order", args)
+	if result.IsError {
+		return nil, resultText(result)
+	}
+
+	var handled map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(resultText(result)), &handled))
+	return handled, ""
+}
+
+func TestAmountUnit(t *testing.T) {
+	t.Run("converts rupees to paise", func(t *testing.T) {
+		srv := newAmountTestServer()
+
+		args, errText := callAmountTool(t, srv, `{"amount": 295.5, `+
+			`"currency": "INR", "amount_unit": "rupees", "transfers": [`+
+			`{"account": "acc_1", "amount": 100.25}, {"account": "acc_2"}]}`)
+
+		require.Empty(t, errText)
+		assert.Equal(t, map[string]interface{}{
+			"amount":   float64(29550),
+			"currency": "INR",
+			"transfers": []interface{}{
+				map[string]interface{}{
+					"account": "acc_1",
+					"amount":  float64(10025),
+				},
+				map[string]interface{}{"account": "acc_2"},
+			},
+		}, args)
+	})
+
+	t.Run("keeps amounts in paise", func(t *testing.T) {
+		srv := newAmountTestServer()
+
+		args, errText := callAmountTool(t, srv,
+			`{"amount": 29500, "amount_unit": "paise"}`)
+
+		require.Empty(t, errText)
+		assert.Equal(t, map[string]interface{}{
+			"amount": float64(29500),
+		}, args)
+
+		args, _ = callAmountTool(t, srv, `{"amount": 29500}`)
+		assert.Equal(t, float64(29500), args["amount"])
+	})
+
+	t.Run("uses the unit of the server", func(t *testing.T) {
+		srv := newAmountTestServer(WithAmountUnit(AmountUnitRupees))
+
+		args, errText := callAmountTool(t, srv, `{"amount": 295}`)
+		require.Empty(t, errText)
+		assert.Equal(t, float64(29500), args["amount"])
+
+		args, _ = callAmountTool(t, srv,
+			`{"amount": 29500, "amount_unit": "paise"}`)
+		assert.Equal(t, float64(29500), args["amount"])
+
+		tool := srv.McpServer.GetTool("create_order")
+		require.NotNil(t, tool)
+		property, ok := tool.Tool.InputSchema.
+			Properties[AmountUnitParameter].(map[string]any)
+		require.True(t, ok)
+		assert.Equal(t, AmountUnitRupees, property["default"])
+	})
+
+	t.Run("rejects fractions of paise", func(t *testing.T) {
+		srv := newAmountTestServer()
+
+		_, errText := callAmountTool(t, srv, `{"amount": 295, `+
+			`"amount_unit": "rupees", "transfers": [{"amount": 10.005}]}`)
+
+		assert.Equal(t, "invalid transfers[0].amount: 10.005 rupees has "+
+			"more than 2 decimal places", errText)
+	})
+
+	t.Run("rejects rupees for other currencies", func(t *testing.T) {
+		srv := newAmountTestServer()
+
+		result := callToolWithArgs(t, srv, "create_order", `{"amount": 295, `+
+			`"currency": "USD", "amount_unit": "rupees"}`)
+
+		assert.True(t, result.IsError)
+		assert.Equal(t, "invalid amount_unit: rupees only apply to INR "+
+			"amounts, pass USD amounts in paise, the smallest currency "+
+			"sub-unit", resultText(result))
+		content, ok := result.StructuredContent.(map[string]interface{})
+		require.True(t, ok)
+		details, ok := content["error"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, ValidationErrorCode, details["code"])
+		assert.Equal(t, AmountUnitParameter, details["field"])
+	})
+
+	t.Run("rejects rupees for the default currency of the toolset",
+		func(t *testing.T) {
+			srv := newAmountTestServer(WithToolsetDefaults(
+				map[string]map[string]interface{}{
+					"orders": {"currency": "USD"},
+				}))
+
+			_, errText := callAmountTool(t, srv,
+				`{"amount": 100, "amount_unit": "rupees"}`)
+
+			assert.Equal(t, "invalid amount_unit: rupees only apply to "+
+				"INR amounts, pass USD amounts in paise, the smallest "+
+				"currency sub-unit", errText)
+		})
+
+	t.Run("rejects rupees for objects in other currencies",
+		func(t *testing.T) {
+			srv := newAmountTestServer()
+
+			_, errText := callAmountTool(t, srv, `{"amount": 295, `+
+				`"amount_unit": "rupees", "transfers": [{"amount": 10}, `+
+				`{"amount": 10, "currency": "USD"}]}`)
+
+			assert.Equal(t, "invalid amount_unit: rupees only apply to INR "+
+				"amounts, pass USD amounts in paise, the smallest currency "+
+				"sub-unit", errText)
+		})
+
+	t.Run("rejects unknown units", func(t *testing.T) {
+		srv := newAmountTestServer()
+
+		_, errText := callAmountTool(t, srv,
+			`{"amount": 295, "amount_unit": "dollars"}`)
+
+		assert.Equal(t, "invalid amount_unit: must be paise or rupees",
+			errText)
+	})
+
+	t.Run("checks the policy against paise", func(t *testing.T) {
+		var checked interface{}
+		srv := newAmountTestServer(WithPolicy(
+			func(toolName string, args map[string]interface{}) string {
+				checked = args["amount"]
+				return ""
+			}))
+
+		_, errText := callAmountTool(t, srv,
+			`{"amount": 295, "amount_unit": "rupees"}`)
+
+		require.Empty(t, errText)
+		assert.Equal(t, float64(29500), checked)
+	})
+}
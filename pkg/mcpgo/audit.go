@@ -0,0 +1,99 @@
+package mcpgo
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/audit"
+)
+
+// auditOption is the option value that enables the audit log
+type auditOption struct {
+	logger *audit.Logger
+}
+
+// WithAuditLog returns a server option that records every call to a write
+// tool in the audit log, including calls that were rejected
+func WithAuditLog(logger *audit.Logger) ServerOption {
+	return func(s OptionSetter) error {
+		return s.SetOption(auditOption{logger: logger})
+	}
+}
+
+// withAuditLog wraps the handler of a write tool so that its calls are
+// recorded in the audit log. Read-only tools are returned unchanged.
+func withAuditLog(
+	logger *audit.Logger,
+	serverTool server.ServerTool,
+	toolset string,
+) server.ServerTool {
+	if isReadOnlyTool(serverTool.Tool) {
+		return serverTool
+	}
+
+	handler := serverTool.Handler
+	serverTool.Handler = func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		result, err := handler(ctx, req)
+
+		args := req.GetArguments()
+		entry := audit.Entry{
+			Tool:       req.Params.Name,
+			Toolset:    toolset,
+			Parameters: args,
+			Status:     audit.StatusSuccess,
+		}
+		switch {
+		case err != nil:
+			entry.Status = audit.StatusError
+			entry.Error = err.Error()
+			entry.EntityIDs = audit.EntityIDs(args)
+		case result == nil || result.IsError:
+			entry.Status = audit.StatusError
+			entry.Error = resultText(result)
+			entry.EntityIDs = audit.EntityIDs(args)
+		default:
+			entry.EntityIDs = audit.EntityIDs(args, resultObject(result))
+		}
+		logger.Record(ctx, entry)
+
+		return result, err
+	}
+
+	return serverTool
+}
+
+// resultText returns the text content of a tool result
+func resultText(result *mcp.CallToolResult) string {
+	if result == nil {
+		return ""
+	}
+
+	var texts []string
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			texts = append(texts, text.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// resultObject returns the entity a tool returned, or nil if the result
+// is not a JSON object
+func resultObject(result *mcp.CallToolResult) map[string]interface{} {
+	if structured, ok := result.StructuredContent.(map[string]interface{}); ok {
+		return structured
+	}
+
+	var object map[string]interface{}
+	if err := json.Unmarshal([]byte(resultText(result)), &object); err != nil {
+		return nil
+	}
+	return object
+}
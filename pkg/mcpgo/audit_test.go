@@ -0,0 +1,125 @@
+package mcpgo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/audit"
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
+)
+
+// auditSink keeps the audit records written to it
+type auditSink struct {
+	entries []audit.Entry
+}
+
+func (s *auditSink) Write(entry audit.Entry) error {
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+// newAuditTestServer creates a server with a read tool, a write tool that
+// returns the created entity and a write tool that fails
+func newAuditTestServer(opts ...ServerOption) *Mark3labsImpl {
+	fetch := NewTool("fetch_thing", "Fetches a thing", nil,
+		func(ctx context.Context, r CallToolRequest) (*ToolResult, error) {
+			return NewToolResultText(`{"id":"thing_1"}`), nil
+		})
+	fetch.SetReadOnly(true)
+
+	create := NewTool("create_thing", "Creates a thing", nil,
+		func(ctx context.Context, r CallToolRequest) (*ToolResult, error) {
+			return NewToolResultJSON(map[string]interface{}{
+				"id":       "thing_2",
+				"order_id": "order_1",
+			})
+		})
+	create.SetReadOnly(false)
+	create.SetToolset("things")
+
+	fail := NewTool("delete_thing", "Deletes a thing", nil,
+		func(ctx context.Context, r CallToolRequest) (*ToolResult, error) {
+			return NewToolResultError("thing not found"), nil
+		})
+
+	srv := NewMcpServer("test-server", "1.0.0",
+		append([]ServerOption{WithToolCapabilities(true)}, opts...)...)
+	srv.AddTools(fetch, create, fail)
+
+	return srv
+}
+
+func TestAuditLog(t *testing.T) {
+	ctx := contextkey.WithCaller(context.Background(), "rzp_test_key")
+
+	t.Run("records successful write tool calls", func(t *testing.T) {
+		sink := &auditSink{}
+		srv := newAuditTestServer(
+			WithAuditLog(audit.NewLogger([]audit.Sink{sink})))
+
+		callToolWithArguments(t, ctx, srv, "create_thing",
+			`{"thing_id":"thing_1","key_secret":"s3cret"}`)
+
+		require.Len(t, sink.entries, 1)
+		entry := sink.entries[0]
+		assert.Equal(t, "create_thing", entry.Tool)
+		assert.Equal(t, "things", entry.Toolset)
+		assert.Equal(t, "rzp_test_key", entry.Caller)
+		assert.Equal(t, audit.StatusSuccess, entry.Status)
+		assert.Empty(t, entry.Error)
+		assert.Equal(t, "[REDACTED]", entry.Parameters["key_secret"])
+		assert.Equal(t, map[string]string{
+			"id":       "thing_2",
+			"order_id": "order_1",
+			"thing_id": "thing_1",
+		}, entry.EntityIDs)
+	})
+
+	t.Run("records failed write tool calls", func(t *testing.T) {
+		sink := &auditSink{}
+		srv := newAuditTestServer(
+			WithAuditLog(audit.NewLogger([]audit.Sink{sink})))
+
+		callToolWithArguments(t, ctx, srv, "delete_thing",
+			`{"thing_id":"thing_1"}`)
+
+		require.Len(t, sink.entries, 1)
+		entry := sink.entries[0]
+		assert.Equal(t, audit.StatusError, entry.Status)
+		assert.Equal(t, "thing not found", entry.Error)
+		assert.Equal(t, map[string]string{"thing_id": "thing_1"},
+			entry.EntityIDs)
+	})
+
+	t.Run("records write tool calls rejected in read-only mode",
+		func(t *testing.T) {
+			sink := &auditSink{}
+			srv := newAuditTestServer(WithReadOnly(true),
+				WithAuditLog(audit.NewLogger([]audit.Sink{sink})))
+
+			callToolWithArguments(t, ctx, srv, "create_thing", `{}`)
+
+			require.Len(t, sink.entries, 1)
+			assert.Equal(t, audit.StatusError, sink.entries[0].Status)
+		})
+
+	t.Run("does not record read-only tool calls", func(t *testing.T) {
+		sink := &auditSink{}
+		srv := newAuditTestServer(
+			WithAuditLog(audit.NewLogger([]audit.Sink{sink})))
+
+		callToolWithArguments(t, ctx, srv, "fetch_thing", `{}`)
+
+		assert.Empty(t, sink.entries)
+	})
+
+	t.Run("is disabled without a logger", func(t *testing.T) {
+		srv := newAuditTestServer(WithAuditLog(nil))
+
+		assert.Equal(t, `{"id":"thing_1"}`,
+			callToolWithArguments(t, ctx, srv, "fetch_thing", `{}`))
+	})
+}
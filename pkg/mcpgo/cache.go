@@ -0,0 +1,227 @@
+package mcpgo
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
+)
+
+// maxCacheEntries bounds the number of results kept by the result cache
+const maxCacheEntries = 10000
+
+// cacheTTLOption is the option value that enables the result cache
+type cacheTTLOption time.Duration
+
+// WithCacheTTL returns a server option that caches the results of read-only
+// tools for the given time, keyed by tool name and arguments. Results are
+// only shared between calls with the same contextkey.CacheScope, and a
+// successful call to a write tool drops the cached results of its scope. A
+// TTL of 0 disables the cache.
+func WithCacheTTL(ttl time.Duration) ServerOption {
+	return func(s OptionSetter) error {
+		return s.SetOption(cacheTTLOption(ttl))
+	}
+}
+
+// cacheEntry is a cached tool result
+type cacheEntry struct {
+	result  *mcp.CallToolResult
+	expires time.Time
+}
+
+// resultCache caches tool results per cache scope
+type resultCache struct {
+	ttl time.Duration
+	// now returns the current time, and is replaced in tests
+	now func() time.Time
+
+	mu      sync.Mutex
+	scopes  map[string]map[string]cacheEntry
+	entries int
+
+	// shared keeps the results in Redis instead, if set
+	shared *redisResults
+}
+
+// newResultCache creates a result cache, or returns nil if ttl disables it
+func newResultCache(ttl time.Duration) *resultCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &resultCache{
+		ttl:    ttl,
+		now:    time.Now,
+		scopes: make(map[string]map[string]cacheEntry),
+	}
+}
+
+// get returns the cached result for the key, if it has not expired
+func (c *resultCache) get(scope, key string) (*mcp.CallToolResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.scopes[scope][key]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(entry.expires) {
+		delete(c.scopes[scope], key)
+		c.entries--
+		return nil, false
+	}
+	return entry.result, true
+}
+
+// set caches a result for the key
+func (c *resultCache) set(scope, key string, result *mcp.CallToolResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries >= maxCacheEntries {
+		c.evictExpired()
+	}
+	if c.entries >= maxCacheEntries {
+		return
+	}
+
+	entries, ok := c.scopes[scope]
+	if !ok {
+		entries = make(map[string]cacheEntry)
+		c.scopes[scope] = entries
+	}
+	if _, exists := entries[key]; !exists {
+		c.entries++
+	}
+	entries[key] = cacheEntry{result: result, expires: c.now().Add(c.ttl)}
+}
+
+// invalidate drops the cached results of a scope
+func (c *resultCache) invalidate(scope string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries -= len(c.scopes[scope])
+	delete(c.scopes, scope)
+}
+
+// evictExpired drops expired results. The caller must hold c.mu.
+func (c *resultCache) evictExpired() {
+	now := c.now()
+	for scope, entries := range c.scopes {
+		for key, entry := range entries {
+			if !now.Before(entry.expires) {
+				delete(entries, key)
+				c.entries--
+			}
+		}
+		if len(entries) == 0 {
+			delete(c.scopes, scope)
+		}
+	}
+}
+
+// load returns the cached result for the key from the shared cache if
+// set, or from memory
+func (c *resultCache) load(
+	ctx context.Context,
+	scope string,
+	key string,
+) (*mcp.CallToolResult, bool) {
+	if c.shared != nil {
+		return c.shared.get(ctx, scope, key)
+	}
+	return c.get(scope, key)
+}
+
+// store caches a result for the key in the shared cache if set, or in
+// memory
+func (c *resultCache) store(
+	ctx context.Context,
+	scope string,
+	key string,
+	result *mcp.CallToolResult,
+) {
+	if c.shared != nil {
+		c.shared.set(ctx, scope, key, result)
+		return
+	}
+	c.set(scope, key, result)
+}
+
+// drop drops the cached results of a scope from the shared cache if set,
+// or from memory
+func (c *resultCache) drop(ctx context.Context, scope string) {
+	if c.shared != nil {
+		c.shared.invalidate(ctx, scope)
+		return
+	}
+	c.invalidate(scope)
+}
+
+// cacheKey returns the key of a tool call, made of the tool name and its
+// arguments. Map keys are sorted when marshalled, so equal arguments give
+// equal keys.
+func cacheKey(req mcp.CallToolRequest) (string, bool) {
+	arguments, err := json.Marshal(req.Params.Arguments)
+	if err != nil {
+		return "", false
+	}
+	return req.Params.Name + "\x00" + string(arguments), true
+}
+
+// withCache wraps the handler of a tool so that the results of read-only
+// tools are served from the cache, and calls to write tools drop the cached
+// results of their scope
+func (c *resultCache) withCache(
+	serverTool server.ServerTool,
+	cacheable bool,
+) server.ServerTool {
+	handler := serverTool.Handler
+
+	if !isReadOnlyTool(serverTool.Tool) {
+		serverTool.Handler = func(
+			ctx context.Context,
+			req mcp.CallToolRequest,
+		) (*mcp.CallToolResult, error) {
+			result, err := handler(ctx, req)
+			if err == nil && result != nil && !result.IsError {
+				c.drop(ctx, contextkey.CacheScopeFromContext(ctx))
+			}
+			return result, err
+		}
+		return serverTool
+	}
+
+	if !cacheable {
+		return serverTool
+	}
+
+	serverTool.Handler = func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		key, ok := cacheKey(req)
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		scope := contextkey.CacheScopeFromContext(ctx)
+		if result, ok := c.load(ctx, scope, key); ok {
+			return result, nil
+		}
+
+		result, err := handler(ctx, req)
+		if err == nil && result != nil && !result.IsError {
+			c.store(ctx, scope, key, result)
+		}
+		return result, err
+	}
+
+	return serverTool
+}
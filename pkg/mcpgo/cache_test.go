@@ -0,0 +1,204 @@
+package mcpgo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
+)
+
+// newCacheTestServer creates a server caching results for ttl, with tools
+// that count the calls reaching their handlers
+func newCacheTestServer(
+	ttl time.Duration,
+) (*Mark3labsImpl, map[string]int) {
+	calls := make(map[string]int)
+	counting := func(name string, fail bool) ToolHandler {
+		return func(
+			ctx context.Context,
+			r CallToolRequest,
+		) (*ToolResult, error) {
+			calls[name]++
+			if fail {
+				return NewToolResultError("failed"), nil
+			}
+			return NewToolResultText(fmt.Sprintf("call %d", calls[name])), nil
+		}
+	}
+
+	fetchTool := NewTool("fetch_thing", "Fetches a thing",
+		[]ToolParameter{WithString("id"), WithObject("filters")},
+		counting("fetch_thing", false))
+	fetchTool.SetReadOnly(true)
+	exportTool := NewTool("export_thing", "Exports a thing", nil,
+		counting("export_thing", false)).WithoutCache()
+	exportTool.SetReadOnly(true)
+	failingTool := NewTool("fetch_broken_thing", "Fails", nil,
+		counting("fetch_broken_thing", true))
+	failingTool.SetReadOnly(true)
+	writeTool := NewTool("update_thing", "Updates a thing", nil,
+		counting("update_thing", false))
+	writeTool.SetReadOnly(false)
+
+	srv := NewMcpServer("test-server", "1.0.0",
+		WithToolCapabilities(true), WithCacheTTL(ttl))
+	srv.AddTools(fetchTool, exportTool, failingTool, writeTool)
+
+	return srv, calls
+}
+
+// callToolWithArguments calls a tool on the server and returns the text of
+// its result
+func callToolWithArguments(
+	t *testing.T,
+	ctx context.Context,
+	srv *Mark3labsImpl,
+	name string,
+	arguments string,
+) string {
+	t.Helper()
+
+	response := srv.McpServer.HandleMessage(ctx, json.RawMessage(
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call",`+
+			`"params":{"name":"`+name+`","arguments":`+arguments+`}}`))
+	resp, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok, "unexpected response %v", response)
+	result, ok := resp.Result.(mcp.CallToolResult)
+	require.True(t, ok)
+	require.NotEmpty(t, result.Content)
+
+	text, ok := result.Content[0].(mcp.TextContent)
+	require.True(t, ok)
+	return text.Text
+}
+
+func TestResultCache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("serves repeated calls from the cache", func(t *testing.T) {
+		srv, calls := newCacheTestServer(time.Minute)
+
+		first := callToolWithArguments(t, ctx, srv, "fetch_thing",
+			`{"id":"pay_1","filters":{"a":1,"b":2}}`)
+		second := callToolWithArguments(t, ctx, srv, "fetch_thing",
+			`{"filters":{"b":2,"a":1},"id":"pay_1"}`)
+
+		assert.Equal(t, "call 1", first)
+		assert.Equal(t, "call 1", second)
+		assert.Equal(t, 1, calls["fetch_thing"])
+	})
+
+	t.Run("keys results by arguments", func(t *testing.T) {
+		srv, calls := newCacheTestServer(time.Minute)
+
+		callToolWithArguments(t, ctx, srv, "fetch_thing", `{"id":"pay_1"}`)
+		result := callToolWithArguments(t, ctx, srv, "fetch_thing",
+			`{"id":"pay_2"}`)
+
+		assert.Equal(t, "call 2", result)
+		assert.Equal(t, 2, calls["fetch_thing"])
+	})
+
+	t.Run("keys results by cache scope", func(t *testing.T) {
+		srv, calls := newCacheTestServer(time.Minute)
+		merchantA := contextkey.WithCacheScope(ctx, "merchant-a")
+		merchantB := contextkey.WithCacheScope(ctx, "merchant-b")
+
+		callToolWithArguments(t, merchantA, srv, "fetch_thing", `{}`)
+		callToolWithArguments(t, merchantB, srv, "fetch_thing", `{}`)
+		callToolWithArguments(t, merchantA, srv, "fetch_thing", `{}`)
+
+		assert.Equal(t, 2, calls["fetch_thing"])
+	})
+
+	t.Run("expires results after the ttl", func(t *testing.T) {
+		srv, calls := newCacheTestServer(time.Minute)
+		clock := time.Now()
+		srv.cache.now = func() time.Time { return clock }
+
+		callToolWithArguments(t, ctx, srv, "fetch_thing", `{}`)
+		clock = clock.Add(59 * time.Second)
+		callToolWithArguments(t, ctx, srv, "fetch_thing", `{}`)
+		clock = clock.Add(time.Second)
+		result := callToolWithArguments(t, ctx, srv, "fetch_thing", `{}`)
+
+		assert.Equal(t, "call 2", result)
+		assert.Equal(t, 2, calls["fetch_thing"])
+	})
+
+	t.Run("does not cache errors", func(t *testing.T) {
+		srv, calls := newCacheTestServer(time.Minute)
+
+		callToolWithArguments(t, ctx, srv, "fetch_broken_thing", `{}`)
+		callToolWithArguments(t, ctx, srv, "fetch_broken_thing", `{}`)
+
+		assert.Equal(t, 2, calls["fetch_broken_thing"])
+	})
+
+	t.Run("skips tools without cache", func(t *testing.T) {
+		srv, calls := newCacheTestServer(time.Minute)
+
+		callToolWithArguments(t, ctx, srv, "export_thing", `{}`)
+		callToolWithArguments(t, ctx, srv, "export_thing", `{}`)
+
+		assert.Equal(t, 2, calls["export_thing"])
+	})
+
+	t.Run("write calls drop the results of their scope", func(t *testing.T) {
+		srv, calls := newCacheTestServer(time.Minute)
+		merchantA := contextkey.WithCacheScope(ctx, "merchant-a")
+		merchantB := contextkey.WithCacheScope(ctx, "merchant-b")
+
+		callToolWithArguments(t, merchantA, srv, "fetch_thing", `{}`)
+		callToolWithArguments(t, merchantB, srv, "fetch_thing", `{}`)
+		callToolWithArguments(t, merchantA, srv, "update_thing", `{}`)
+		callToolWithArguments(t, merchantA, srv, "fetch_thing", `{}`)
+		callToolWithArguments(t, merchantB, srv, "fetch_thing", `{}`)
+
+		assert.Equal(t, 3, calls["fetch_thing"])
+		assert.Equal(t, 1, calls["update_thing"])
+	})
+
+	t.Run("is disabled without a ttl", func(t *testing.T) {
+		srv, calls := newCacheTestServer(0)
+
+		callToolWithArguments(t, ctx, srv, "fetch_thing", `{}`)
+		callToolWithArguments(t, ctx, srv, "fetch_thing", `{}`)
+
+		assert.Nil(t, srv.cache)
+		assert.Equal(t, 2, calls["fetch_thing"])
+	})
+}
+
+func TestResultCache_Eviction(t *testing.T) {
+	cache := newResultCache(time.Minute)
+	clock := time.Now()
+	cache.now = func() time.Time { return clock }
+	result := mcp.NewToolResultText("done")
+
+	for i := 0; i < maxCacheEntries; i++ {
+		cache.set("", fmt.Sprint(i), result)
+	}
+
+	t.Run("drops results once full", func(t *testing.T) {
+		cache.set("", "full", result)
+		_, ok := cache.get("", "full")
+		assert.False(t, ok)
+	})
+
+	t.Run("evicts expired results to make room", func(t *testing.T) {
+		clock = clock.Add(time.Minute)
+		cache.set("", "fresh", result)
+
+		_, ok := cache.get("", "fresh")
+		assert.True(t, ok)
+		assert.Equal(t, 1, cache.entries)
+	})
+}
@@ -0,0 +1,49 @@
+package mcpgo
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// CallContextFunc prepares the context a tool call is handled with, such
+// as by recording settings of the server that tools read
+type CallContextFunc func(ctx context.Context) context.Context
+
+// callContextOption is the option value that sets the call context func
+type callContextOption struct {
+	prepare CallContextFunc
+}
+
+// WithCallContext returns a server option that prepares the context of
+// every tool call with the func, after the funcs of earlier options. Nil
+// leaves the context as it is.
+func WithCallContext(prepare CallContextFunc) ServerOption {
+	return func(s OptionSetter) error {
+		return s.SetOption(callContextOption{prepare: prepare})
+	}
+}
+
+// withCallContext wraps the handler of a tool so that it runs with the
+// context prepared by the call context funcs of the server
+func (s *Mark3labsImpl) withCallContext(
+	serverTool server.ServerTool,
+) server.ServerTool {
+	if len(s.callContexts) == 0 {
+		return serverTool
+	}
+
+	handler := serverTool.Handler
+	serverTool.Handler = func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		for _, prepare := range s.callContexts {
+			ctx = prepare(ctx)
+		}
+		return handler(ctx, req)
+	}
+
+	return serverTool
+}
@@ -0,0 +1,57 @@
+package mcpgo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
+)
+
+func TestWithCallContext(t *testing.T) {
+	// callCaller calls a tool that returns the caller of its context
+	callCaller := func(opts ...ServerOption) string {
+		tool := NewTool("fetch_caller", "Returns the caller", nil,
+			func(ctx context.Context, r CallToolRequest) (*ToolResult, error) {
+				return NewToolResultText(contextkey.CallerFromContext(ctx)), nil
+			})
+		tool.SetReadOnly(true)
+		srv := NewMcpServer("test-server", "1.0.0", opts...)
+		srv.AddTools(tool)
+
+		serverTool := srv.McpServer.GetTool("fetch_caller")
+		require.NotNil(t, serverTool)
+		result, err := serverTool.Handler(context.Background(),
+			mcp.CallToolRequest{})
+		require.NoError(t, err)
+		text, ok := mcp.AsTextContent(result.Content[0])
+		require.True(t, ok)
+		return text.Text
+	}
+
+	t.Run("leaves the context as it is by default", func(t *testing.T) {
+		assert.Empty(t, callCaller())
+		assert.Empty(t, callCaller(WithCallContext(nil)))
+	})
+
+	t.Run("prepares the context of calls", func(t *testing.T) {
+		caller := callCaller(WithCallContext(
+			func(ctx context.Context) context.Context {
+				return contextkey.WithCaller(ctx, "rzp_test_key")
+			}))
+		assert.Equal(t, "rzp_test_key", caller)
+	})
+	t.Run("applies the funcs in order", func(t *testing.T) {
+		withCaller := func(caller string) ServerOption {
+			return WithCallContext(
+				func(ctx context.Context) context.Context {
+					return contextkey.WithCaller(ctx,
+						contextkey.CallerFromContext(ctx)+caller)
+				})
+		}
+		assert.Equal(t, "ab", callCaller(withCaller("a"), withCaller("b")))
+	})
+}
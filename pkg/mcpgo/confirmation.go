@@ -0,0 +1,142 @@
+package mcpgo
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Codes of the errors returned for calls that were not confirmed
+const (
+	// ConfirmationUnavailableErrorCode identifies calls that need
+	// confirmation from a client that cannot ask its user for it
+	ConfirmationUnavailableErrorCode = "CONFIRMATION_UNAVAILABLE"
+	// ConfirmationDeclinedErrorCode identifies calls the user did not
+	// confirm
+	ConfirmationDeclinedErrorCode = "CONFIRMATION_DECLINED"
+)
+
+// ConfirmationFunc returns the question the user must confirm before a
+// call with the arguments runs, or an empty string if the call needs no
+// confirmation
+type ConfirmationFunc func(args map[string]interface{}) string
+
+// confirmationsOption is the option value that holds the confirmations of
+// tools, keyed by tool name
+type confirmationsOption map[string]ConfirmationFunc
+
+// WithConfirmations returns a server option that asks the user to confirm
+// calls to the tools before they run, through an MCP elicitation request.
+// The model cannot confirm calls itself: calls the user declines, and
+// calls from clients that do not support elicitation, fail.
+func WithConfirmations(confirmations map[string]ConfirmationFunc) ServerOption {
+	return func(s OptionSetter) error {
+		return s.SetOption(confirmationsOption(confirmations))
+	}
+}
+
+// confirmationSchema is the schema of the answer to a confirmation request
+var confirmationSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"confirm": map[string]interface{}{
+			"type":        "boolean",
+			"title":       "Confirm",
+			"description": "Allow the call to run",
+		},
+	},
+	"required": []string{"confirm"},
+}
+
+// requireConfirmation wraps the handler of a tool so that calls that need
+// confirmation only run once the user confirmed them. Dry runs need no
+// confirmation, since they send no request.
+func (s *Mark3labsImpl) requireConfirmation(
+	serverTool server.ServerTool,
+) server.ServerTool {
+	confirmation, ok := s.confirmations[serverTool.Tool.Name]
+	if !ok {
+		return serverTool
+	}
+
+	handler := serverTool.Handler
+	serverTool.Handler = func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		question := confirmation(req.GetArguments())
+		if question == "" || DryRunFromContext(ctx) != nil {
+			return handler(ctx, req)
+		}
+
+		if !clientSupportsElicitation(ctx) {
+			return confirmationError(req.Params.Name,
+				ConfirmationUnavailableErrorCode, "tool "+req.Params.Name+
+					" needs confirmation from the user, but the client "+
+					"cannot ask for it"), nil
+		}
+
+		result, err := s.McpServer.RequestElicitation(ctx,
+			mcp.ElicitationRequest{Params: mcp.ElicitationParams{
+				Message:         question,
+				RequestedSchema: confirmationSchema,
+			}})
+		if err != nil {
+			description := "asking the user to confirm tool " +
+				req.Params.Name + " failed: " + err.Error()
+			return confirmationError(req.Params.Name,
+				ConfirmationUnavailableErrorCode, description), nil
+		}
+		if !confirmed(result) {
+			return confirmationError(req.Params.Name,
+				ConfirmationDeclinedErrorCode, "the user did not confirm "+
+					"the call to tool "+req.Params.Name), nil
+		}
+
+		return handler(ctx, req)
+	}
+
+	return serverTool
+}
+
+// clientSupportsElicitation reports whether the client of the session in
+// ctx declared that it can ask its user for input
+func clientSupportsElicitation(ctx context.Context) bool {
+	session := server.ClientSessionFromContext(ctx)
+	clientInfo, ok := session.(server.SessionWithClientInfo)
+	if !ok {
+		return false
+	}
+	return clientInfo.GetClientCapabilities().Elicitation != nil
+}
+
+// confirmed reports whether the user accepted a confirmation request
+func confirmed(result *mcp.ElicitationResult) bool {
+	if result == nil ||
+		result.Action != mcp.ElicitationResponseActionAccept {
+		return false
+	}
+	content, _ := result.Content.(map[string]interface{})
+	confirm, _ := content["confirm"].(bool)
+	return confirm
+}
+
+// confirmationError returns the error result of a call that was not
+// confirmed
+func confirmationError(
+	toolName string,
+	code string,
+	description string,
+) *mcp.CallToolResult {
+	result := mcp.NewToolResultStructured(map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":        code,
+			"tool":        toolName,
+			"description": description,
+		},
+	}, description)
+	result.IsError = true
+
+	return result
+}
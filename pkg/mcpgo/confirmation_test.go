@@ -0,0 +1,170 @@
+package mcpgo
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// elicitationHandler answers confirmation requests with a fixed response
+// and records the questions it was asked
+type elicitationHandler struct {
+	response  mcp.ElicitationResponse
+	questions []string
+}
+
+func (h *elicitationHandler) Elicit(
+	ctx context.Context,
+	request mcp.ElicitationRequest,
+) (*mcp.ElicitationResult, error) {
+	h.questions = append(h.questions, request.Params.Message)
+	return &mcp.ElicitationResult{ElicitationResponse: h.response}, nil
+}
+
+// callConfirmedTool calls a tool with the JSON arguments in a session of a
+// client that answers confirmation requests with the handler, or of a
+// client without elicitation support if the handler is nil
+func callConfirmedTool(
+	t *testing.T,
+	srv *Mark3labsImpl,
+	handler *elicitationHandler,
+	name string,
+	args string,
+) *mcp.CallToolResult {
+	t.Helper()
+
+	var session *server.InProcessSession
+	if handler != nil {
+		session = server.NewInProcessSessionWithHandlers("session-1", nil,
+			handler, nil)
+		session.SetClientCapabilities(
+			mcp.ClientCapabilities{Elicitation: &struct{}{}})
+	} else {
+		session = server.NewInProcessSession("session-1", nil)
+	}
+	ctx := context.Background()
+	require.NoError(t, srv.McpServer.RegisterSession(ctx, session))
+	defer srv.McpServer.UnregisterSession(ctx, session.SessionID())
+
+	response := srv.McpServer.HandleMessage(
+		srv.McpServer.WithContext(ctx, session),
+		json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"tools/call",`+
+			`"params":{"name":"`+name+`","arguments":`+args+`}}`))
+	resp, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok, "unexpected response %v", response)
+	result, ok := resp.Result.(mcp.CallToolResult)
+	require.True(t, ok)
+
+	return &result
+}
+
+func TestConfirmation(t *testing.T) {
+	// create_thing needs confirmation for amounts above 100
+	newServer := func(opts ...ServerOption) *Mark3labsImpl {
+		return newReadOnlyTestServer(append([]ServerOption{
+			WithConfirmations(map[string]ConfirmationFunc{
+				"create_thing": func(args map[string]interface{}) string {
+					if amount, _ := args["amount"].(float64); amount > 100 {
+						return "Create a thing of 500?"
+					}
+					return ""
+				},
+			}),
+		}, opts...)...)
+	}
+	accept := mcp.ElicitationResponse{
+		Action:  mcp.ElicitationResponseActionAccept,
+		Content: map[string]interface{}{"confirm": true},
+	}
+
+	t.Run("runs calls that need no confirmation", func(t *testing.T) {
+		handler := &elicitationHandler{}
+
+		result := callConfirmedTool(t, newServer(), handler, "create_thing",
+			`{"amount":100}`)
+		assert.False(t, result.IsError)
+		assert.Empty(t, handler.questions)
+	})
+
+	t.Run("runs confirmed calls", func(t *testing.T) {
+		handler := &elicitationHandler{response: accept}
+
+		result := callConfirmedTool(t, newServer(), handler, "create_thing",
+			`{"amount":500}`)
+		assert.False(t, result.IsError)
+		assert.Equal(t, []string{"Create a thing of 500?"}, handler.questions)
+	})
+
+	t.Run("rejects calls the user did not confirm", func(t *testing.T) {
+		responses := []mcp.ElicitationResponse{
+			{Action: mcp.ElicitationResponseActionDecline},
+			{Action: mcp.ElicitationResponseActionCancel},
+			{
+				Action:  mcp.ElicitationResponseActionAccept,
+				Content: map[string]interface{}{"confirm": false},
+			},
+		}
+		for _, response := range responses {
+			handler := &elicitationHandler{response: response}
+
+			result := callConfirmedTool(t, newServer(), handler,
+				"create_thing", `{"amount":500}`)
+			assert.True(t, result.IsError)
+			assert.Equal(t, map[string]interface{}{
+				"error": map[string]interface{}{
+					"code": ConfirmationDeclinedErrorCode,
+					"tool": "create_thing",
+					"description": "the user did not confirm the call to " +
+						"tool create_thing",
+				},
+			}, result.StructuredContent)
+		}
+	})
+
+	t.Run("rejects calls from clients without elicitation",
+		func(t *testing.T) {
+			result := callConfirmedTool(t, newServer(), nil, "create_thing",
+				`{"amount":500}`)
+			assert.True(t, result.IsError)
+			assert.Equal(t, map[string]interface{}{
+				"error": map[string]interface{}{
+					"code": ConfirmationUnavailableErrorCode,
+					"tool": "create_thing",
+					"description": "tool create_thing needs confirmation " +
+						"from the user, but the client cannot ask for it",
+				},
+			}, result.StructuredContent)
+
+			// without a session
+			result = callToolWithArgs(t, newServer(), "create_thing",
+				`{"amount":500}`)
+			assert.True(t, result.IsError)
+		})
+
+	t.Run("skips confirmation of dry runs", func(t *testing.T) {
+		handler := &elicitationHandler{}
+
+		result := callConfirmedTool(t, newServer(), handler, "create_thing",
+			`{"amount":500,"dry_run":true}`)
+		assert.False(t, result.IsError)
+		assert.Empty(t, handler.questions)
+	})
+
+	t.Run("declares the elicitation capability", func(t *testing.T) {
+		response := newServer().McpServer.HandleMessage(
+			context.Background(), json.RawMessage(`{"jsonrpc":"2.0",`+
+				`"id":1,"method":"initialize","params":{`+
+				`"protocolVersion":"2025-06-18","capabilities":{},`+
+				`"clientInfo":{"name":"test","version":"1.0.0"}}}`))
+		resp, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok, "unexpected response %v", response)
+		result, ok := resp.Result.(mcp.InitializeResult)
+		require.True(t, ok)
+		assert.NotNil(t, result.Capabilities.Elicitation)
+	})
+}
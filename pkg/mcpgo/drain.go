@@ -0,0 +1,92 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// runningCalls counts the tool calls running on the streamable HTTP
+// transport, so that shutdown can wait for them to finish
+type runningCalls struct {
+	mu       sync.Mutex
+	draining bool
+	running  int
+	// idle is closed once draining and no call is running
+	idle chan struct{}
+}
+
+func newRunningCalls() *runningCalls {
+	return &runningCalls{idle: make(chan struct{})}
+}
+
+// start records the start of a call. It returns false once draining, and
+// the call must then not run.
+func (c *runningCalls) start() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.draining {
+		return false
+	}
+	c.running++
+	return true
+}
+
+// finish records the end of a call started with start
+func (c *runningCalls) finish() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.running--
+	if c.draining && c.running == 0 {
+		close(c.idle)
+	}
+}
+
+// isDraining reports whether new calls are refused
+func (c *runningCalls) isDraining() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.draining
+}
+
+// drain refuses new calls and waits for the running ones to finish, or for
+// ctx to be done
+func (c *runningCalls) drain(ctx context.Context) error {
+	c.mu.Lock()
+	if !c.draining {
+		c.draining = true
+		if c.running == 0 {
+			close(c.idle)
+		}
+	}
+	c.mu.Unlock()
+
+	// Checked first, so that an idle transport drains even with no time
+	// left
+	select {
+	case <-c.idle:
+		return nil
+	default:
+	}
+
+	select {
+	case <-c.idle:
+		return nil
+	case <-ctx.Done():
+		c.mu.Lock()
+		running := c.running
+		c.mu.Unlock()
+		return fmt.Errorf("%d tool calls still running: %w", running,
+			ctx.Err())
+	}
+}
+
+// shuttingDown answers a request refused while draining. Clients can send
+// it again to another replica of the server.
+func shuttingDown(w http.ResponseWriter) {
+	http.Error(w, "server is shutting down", http.StatusServiceUnavailable)
+}
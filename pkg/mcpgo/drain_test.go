@@ -0,0 +1,107 @@
+package mcpgo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newBlockingHTTPServer starts a streamable HTTP server with a tool that
+// signals started when it runs and returns once release is closed
+func newBlockingHTTPServer(
+	t *testing.T,
+) (*httptest.Server, *mark3labsStreamableHTTPImpl, chan struct{},
+	chan struct{}) {
+	t.Helper()
+
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	mcpServer := NewMcpServer("test-server", "1.0.0",
+		WithToolCapabilities(true))
+	mcpServer.AddTools(NewTool("count", "Blocks until released", nil,
+		func(ctx context.Context, r CallToolRequest) (*ToolResult, error) {
+			started <- struct{}{}
+			<-release
+			return NewToolResultText("done"), nil
+		}))
+
+	httpServer, err := NewStreamableHTTPServer(mcpServer)
+	require.NoError(t, err)
+
+	ts := httptest.NewServer(httpServer)
+	t.Cleanup(ts.Close)
+
+	return ts, httpServer, started, release
+}
+
+func TestMark3labsStreamableHTTPImpl_Drain(t *testing.T) {
+	t.Run("waits for running tool calls", func(t *testing.T) {
+		ts, httpServer, started, release := newBlockingHTTPServer(t)
+		sessionID := initializeSession(t, ts)
+
+		type response struct {
+			status int
+			body   string
+		}
+		responses := make(chan response, 1)
+		go func() {
+			resp, body := postMessage(t, ts, sessionID, toolCallMessage(1))
+			responses <- response{resp.StatusCode, body}
+		}()
+		<-started
+
+		drained := make(chan error, 1)
+		go func() { drained <- httpServer.Drain(context.Background()) }()
+
+		// The server keeps answering sessions while draining, but opens
+		// no session and starts no tool call
+		require.Eventually(t, httpServer.calls.isDraining,
+			time.Second, time.Millisecond)
+		resp, _ := postMessage(t, ts, "",
+			`{"jsonrpc":"2.0","id":0,"method":"initialize","params":{}}`)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		resp, _ = postMessage(t, ts, sessionID, toolCallMessage(2))
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		select {
+		case <-drained:
+			t.Fatal("drained with a tool call running")
+		default:
+		}
+
+		close(release)
+		require.NoError(t, <-drained)
+		r := <-responses
+		assert.Equal(t, http.StatusOK, r.status)
+		assert.Contains(t, r.body, "done")
+	})
+
+	t.Run("gives up when the context is done", func(t *testing.T) {
+		ts, httpServer, started, release := newBlockingHTTPServer(t)
+		defer close(release)
+		sessionID := initializeSession(t, ts)
+
+		go func() { _, _ = postMessage(t, ts, sessionID, toolCallMessage(1)) }()
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(),
+			10*time.Millisecond)
+		defer cancel()
+		err := httpServer.Drain(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Contains(t, err.Error(), "1 tool calls still running")
+	})
+
+	t.Run("drains at once without running calls", func(t *testing.T) {
+		_, httpServer, _, _ := newBlockingHTTPServer(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.NoError(t, httpServer.Drain(ctx))
+		assert.NoError(t, httpServer.Drain(ctx))
+	})
+}
@@ -0,0 +1,122 @@
+package mcpgo
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// DryRunParameter is the name of the parameter, accepted by every write
+// tool, that runs a single call in dry-run mode
+const DryRunParameter = "dry_run"
+
+// dryRunOption is the option value that enables dry-run mode
+type dryRunOption bool
+
+// WithDryRun returns a server option that runs every call to a write tool
+// in dry-run mode. A single call can also be run in dry-run mode with the
+// dry_run parameter.
+func WithDryRun(dryRun bool) ServerOption {
+	return func(s OptionSetter) error {
+		return s.SetOption(dryRunOption(dryRun))
+	}
+}
+
+// DryRunRequest is an API request a write tool would have sent
+type DryRunRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Body   interface{} `json:"body,omitempty"`
+}
+
+// DryRun collects the API requests a tool call in dry-run mode would have
+// sent. Tools record their requests instead of sending them.
+type DryRun struct {
+	mu       sync.Mutex
+	requests []DryRunRequest
+}
+
+// Record records a request that was not sent
+func (d *DryRun) Record(request DryRunRequest) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.requests = append(d.requests, request)
+}
+
+// Requests returns the recorded requests in the order they were recorded
+func (d *DryRun) Requests() []DryRunRequest {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]DryRunRequest(nil), d.requests...)
+}
+
+// dryRunKey is the context key of the DryRun of a tool call
+type dryRunKey struct{}
+
+// ContextWithDryRun returns a new context that runs tool calls in dry-run
+// mode, recording their requests in the DryRun
+func ContextWithDryRun(ctx context.Context, dryRun *DryRun) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, dryRun)
+}
+
+// DryRunFromContext returns the DryRun of a tool call in dry-run mode, or
+// nil if the call is not a dry run
+func DryRunFromContext(ctx context.Context) *DryRun {
+	dryRun, _ := ctx.Value(dryRunKey{}).(*DryRun)
+	return dryRun
+}
+
+// withDryRun wraps the handler of a write tool so that calls in dry-run
+// mode return the requests the tool would have sent instead of its result.
+// Calls that fail before sending a request, such as on invalid
+// parameters, return the result of the tool.
+func (s *Mark3labsImpl) withDryRun(
+	serverTool server.ServerTool,
+) server.ServerTool {
+	if isReadOnlyTool(serverTool.Tool) {
+		return serverTool
+	}
+
+	handler := serverTool.Handler
+	serverTool.Handler = func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		dryRunArg, _ := req.GetArguments()[DryRunParameter].(bool)
+		if !s.dryRun && !dryRunArg {
+			return handler(ctx, req)
+		}
+
+		dryRun := &DryRun{}
+		result, err := handler(ContextWithDryRun(ctx, dryRun), req)
+		requests := dryRun.Requests()
+		if err != nil || len(requests) == 0 {
+			return result, err
+		}
+
+		return dryRunResult(req.Params.Name, requests), nil
+	}
+
+	return serverTool
+}
+
+// dryRunResult returns the result of a write tool called in dry-run mode
+func dryRunResult(
+	toolName string,
+	requests []DryRunRequest,
+) *mcp.CallToolResult {
+	response := map[string]interface{}{
+		"dry_run":  true,
+		"tool":     toolName,
+		"requests": requests,
+	}
+	// Requests are built from JSON values, so they always marshal
+	text, _ := json.Marshal(response)
+
+	var structured map[string]interface{}
+	_ = json.Unmarshal(text, &structured)
+	return mcp.NewToolResultStructured(structured, string(text))
+}
@@ -0,0 +1,141 @@
+package mcpgo
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDryRunTestServer creates a server with a read tool and a write tool
+// that record a request in dry runs, and a write tool that fails before
+// sending one
+func newDryRunTestServer(opts ...ServerOption) *Mark3labsImpl {
+	handler := func(
+		ctx context.Context,
+		r CallToolRequest,
+	) (*ToolResult, error) {
+		if dryRun := DryRunFromContext(ctx); dryRun != nil {
+			dryRun.Record(DryRunRequest{
+				Method: "POST",
+				URL:    "https://api.razorpay.com/v1/things",
+				Body:   map[string]interface{}{"name": "a"},
+			})
+			return NewToolResultError("request not sent"), nil
+		}
+		return NewToolResultText("done"), nil
+	}
+	failing := func(
+		ctx context.Context,
+		r CallToolRequest,
+	) (*ToolResult, error) {
+		return NewToolResultError("missing parameter: name"), nil
+	}
+
+	readTool := NewTool("fetch_thing", "Fetches a thing", nil, handler)
+	readTool.SetReadOnly(true)
+	writeTool := NewTool("create_thing", "Creates a thing", nil, handler)
+	writeTool.SetReadOnly(false)
+	failingTool := NewTool("update_thing", "Updates a thing", nil, failing)
+	failingTool.SetReadOnly(false)
+
+	srv := NewMcpServer("test-server", "1.0.0",
+		append([]ServerOption{WithToolCapabilities(true)}, opts...)...)
+	srv.AddTools(readTool, writeTool, failingTool)
+
+	return srv
+}
+
+// callToolWithArgs calls a tool on the server with the JSON arguments and
+// returns its result
+func callToolWithArgs(
+	t *testing.T,
+	srv *Mark3labsImpl,
+	name string,
+	args string,
+) *mcp.CallToolResult {
+	t.Helper()
+
+	response := srv.McpServer.HandleMessage(context.Background(),
+		json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"tools/call",`+
+			`"params":{"name":"`+name+`","arguments":`+args+`}}`))
+	resp, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok, "unexpected response %v", response)
+	result, ok := resp.Result.(mcp.CallToolResult)
+	require.True(t, ok)
+
+	return &result
+}
+
+func TestDryRun(t *testing.T) {
+	dryRunResult := map[string]interface{}{
+		"dry_run": true,
+		"tool":    "create_thing",
+		"requests": []interface{}{
+			map[string]interface{}{
+				"method": "POST",
+				"url":    "https://api.razorpay.com/v1/things",
+				"body":   map[string]interface{}{"name": "a"},
+			},
+		},
+	}
+
+	t.Run("adds the parameter to write tools only", func(t *testing.T) {
+		srv := newDryRunTestServer()
+
+		writeTool := srv.McpServer.GetTool("create_thing")
+		require.NotNil(t, writeTool)
+		assert.Contains(t, writeTool.Tool.InputSchema.Properties,
+			DryRunParameter)
+
+		readTool := srv.McpServer.GetTool("fetch_thing")
+		require.NotNil(t, readTool)
+		assert.NotContains(t, readTool.Tool.InputSchema.Properties,
+			DryRunParameter)
+	})
+
+	t.Run("sends requests by default", func(t *testing.T) {
+		srv := newDryRunTestServer()
+
+		result := callToolWithArgs(t, srv, "create_thing", `{}`)
+		assert.False(t, result.IsError)
+		assert.Nil(t, result.StructuredContent)
+	})
+
+	t.Run("returns requests of calls with the parameter",
+		func(t *testing.T) {
+			srv := newDryRunTestServer()
+
+			result := callToolWithArgs(t, srv, "create_thing",
+				`{"dry_run":true}`)
+			assert.False(t, result.IsError)
+			assert.Equal(t, dryRunResult, result.StructuredContent)
+		})
+
+	t.Run("returns requests of every write call in dry-run mode",
+		func(t *testing.T) {
+			srv := newDryRunTestServer(WithDryRun(true))
+
+			result := callToolWithArgs(t, srv, "create_thing", `{}`)
+			assert.False(t, result.IsError)
+			assert.Equal(t, dryRunResult, result.StructuredContent)
+
+			result = callToolWithArgs(t, srv, "fetch_thing", `{}`)
+			assert.False(t, result.IsError)
+			assert.Nil(t, result.StructuredContent)
+		})
+
+	t.Run("returns errors of calls that send no request",
+		func(t *testing.T) {
+			srv := newDryRunTestServer(WithDryRun(true))
+
+			result := callToolWithArgs(t, srv, "update_thing", `{}`)
+			assert.True(t, result.IsError)
+			text, ok := mcp.AsTextContent(result.Content[0])
+			require.True(t, ok)
+			assert.Equal(t, "missing parameter: name", text.Text)
+		})
+}
@@ -0,0 +1,229 @@
+package mcpgo
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// FieldsParameter is the parameter that selects the fields of the result
+// of a call to a tool that supports field selection
+const FieldsParameter = "fields"
+
+// WithFieldSelection adds the fields parameter, which prunes the JSON
+// results of the tool to the fields a call selects. Fields of nested
+// objects are named by their path, such as card.network.
+func (t *mark3labsToolImpl) WithFieldSelection() *mark3labsToolImpl {
+	t.fieldSelection = true
+	t.parameters = append(t.parameters, WithArray(
+		FieldsParameter,
+		Description("Fields of the result to return, leaving out the "+
+			"others, such as [\"status\", \"amount\", \"card.network\"]. "+
+			"Fields of nested objects are named by their path. For "+
+			"collections, the fields of each item are selected. id is "+
+			"always returned. Returns every field when not set"),
+		Items(map[string]interface{}{"type": "string"}),
+	))
+	return t
+}
+
+// selectsFields returns whether WithFieldSelection was set
+func (t *mark3labsToolImpl) selectsFields() bool {
+	return t.fieldSelection
+}
+
+// fieldPaths returns the paths of the fields parameter, false if it is not
+// an array of field names
+func fieldPaths(value interface{}) ([]string, bool) {
+	if value == nil {
+		return nil, true
+	}
+	fields, ok := value.([]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	paths := make([]string, 0, len(fields))
+	for _, field := range fields {
+		path, ok := field.(string)
+		if !ok || path == "" {
+			return nil, false
+		}
+		paths = append(paths, path)
+	}
+	return paths, true
+}
+
+// fieldTree holds the selected fields of an object by name. A field
+// without nested fields is selected as a whole.
+type fieldTree map[string]fieldTree
+
+// newFieldTree returns the tree of the dot separated field paths, with the
+// id field, which is always selected
+func newFieldTree(paths []string) fieldTree {
+	tree := fieldTree{"id": nil}
+	for _, path := range paths {
+		node := tree
+		names := strings.Split(path, ".")
+		for i, name := range names {
+			child, ok := node[name]
+			if ok && child == nil {
+				// The whole field is already selected
+				break
+			}
+			if i == len(names)-1 {
+				node[name] = nil
+				break
+			}
+			if !ok {
+				child = fieldTree{}
+				node[name] = child
+			}
+			node = child
+		}
+	}
+	return tree
+}
+
+// selectFields returns a copy of the value with the fields of its objects
+// pruned to the tree. The items of arrays are pruned each.
+func selectFields(value interface{}, tree fieldTree) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		selected := make(map[string]interface{}, len(tree))
+		for name, subtree := range tree {
+			field, ok := v[name]
+			if !ok {
+				continue
+			}
+			if subtree == nil {
+				selected[name] = field
+			} else {
+				selected[name] = selectFields(field, subtree)
+			}
+		}
+		return selected
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = selectFields(item, tree)
+		}
+		return items
+	}
+	return value
+}
+
+// SelectFields returns a copy of the object with its fields pruned to the
+// dot separated paths and id, as field selection prunes JSON results
+func SelectFields(
+	object map[string]interface{},
+	paths []string,
+) map[string]interface{} {
+	tree := newFieldTree(paths)
+	selected, _ := selectFields(object, tree).(map[string]interface{})
+	return selected
+}
+
+// selectResultFields returns the structured content of a result with its
+// fields selected. The items of collections are selected each, keeping
+// the other fields of the collection, such as count.
+func selectResultFields(
+	content map[string]interface{},
+	tree fieldTree,
+) map[string]interface{} {
+	items, ok := content["items"].([]interface{})
+	if !ok {
+		selected, _ := selectFields(content, tree).(map[string]interface{})
+		return selected
+	}
+
+	selected := make(map[string]interface{}, len(content))
+	for name, value := range content {
+		selected[name] = value
+	}
+	selected["items"] = selectFields(items, tree)
+	return selected
+}
+
+// selectedFieldsKey is the context key of the fields a tool call selects
+type selectedFieldsKey struct{}
+
+// ContextWithSelectedFields returns a new context whose tool call selects
+// the fields with the paths
+func ContextWithSelectedFields(
+	ctx context.Context,
+	paths []string,
+) context.Context {
+	return context.WithValue(ctx, selectedFieldsKey{}, paths)
+}
+
+// SelectedFieldsFromContext returns the paths of the fields a tool call
+// selects, or nil if it returns every field
+func SelectedFieldsFromContext(ctx context.Context) []string {
+	paths, _ := ctx.Value(selectedFieldsKey{}).([]string)
+	return paths
+}
+
+// withFieldSelection wraps the handler of a tool that supports field
+// selection so that the structured content and JSON text of its results
+// are pruned to the fields the call selects. Results without structured
+// content, such as CSV, are returned as they are, so their tools select
+// the fields of the context themselves.
+func withFieldSelection(
+	serverTool server.ServerTool,
+	enabled bool,
+) server.ServerTool {
+	if !enabled {
+		return serverTool
+	}
+
+	handler := serverTool.Handler
+	serverTool.Handler = func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		args := make(map[string]interface{}, len(req.GetArguments()))
+		for name, value := range req.GetArguments() {
+			args[name] = value
+		}
+
+		var paths []string
+		if value, ok := args[FieldsParameter]; ok {
+			delete(args, FieldsParameter)
+			if paths, ok = fieldPaths(value); !ok {
+				return validationError(ctx, FieldsParameter,
+					"invalid fields: must be an array of field names"), nil
+			}
+		}
+		req.Params.Arguments = args
+		if len(paths) > 0 {
+			ctx = ContextWithSelectedFields(ctx, paths)
+		}
+
+		result, err := handler(ctx, req)
+		if err != nil || result == nil || result.IsError || len(paths) == 0 {
+			return result, err
+		}
+
+		content, ok := result.StructuredContent.(map[string]interface{})
+		if !ok || len(result.Content) == 0 {
+			return result, nil
+		}
+		selected := selectResultFields(content, newFieldTree(paths))
+		text, err := json.Marshal(selected)
+		if err != nil {
+			return result, nil
+		}
+
+		pruned := *result
+		pruned.StructuredContent = selected
+		pruned.Content = append([]mcp.Content{mcp.NewTextContent(
+			string(text))}, result.Content[1:]...)
+		return &pruned, nil
+	}
+
+	return serverTool
+}
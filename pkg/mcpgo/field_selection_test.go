@@ -0,0 +1,157 @@
+package mcpgo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFieldsTestServer creates a server with a fetch_thing tool selecting
+// fields, which returns the result and records the arguments it got
+func newFieldsTestServer(
+	result map[string]interface{},
+	args *map[string]interface{},
+) *Mark3labsImpl {
+	tool := NewTool("fetch_thing", "Fetches a thing", []ToolParameter{
+		WithString("thing_id"),
+	}, func(ctx context.Context, r CallToolRequest) (*ToolResult, error) {
+		*args, _ = r.Arguments.(map[string]interface{})
+		return NewToolResultJSON(result)
+	}).WithOutputSchema(map[string]interface{}{"type": "object"}).
+		WithFieldSelection()
+	tool.SetReadOnly(true)
+
+	srv := NewMcpServer("test-server", "1.0.0", WithToolCapabilities(true))
+	srv.AddTools(tool)
+
+	return srv
+}
+
+func TestFieldSelection(t *testing.T) {
+	payment := map[string]interface{}{
+		"id":     "pay_1",
+		"amount": float64(50000),
+		"status": "captured",
+		"card": map[string]interface{}{
+			"network": "Visa",
+			"last4":   "1111",
+		},
+		"notes": []interface{}{
+			map[string]interface{}{"key": "a", "value": "b"},
+		},
+	}
+
+	t.Run("selects fields by path", func(t *testing.T) {
+		var args map[string]interface{}
+		srv := newFieldsTestServer(payment, &args)
+
+		result := callToolWithArgs(t, srv, "fetch_thing",
+			`{"thing_id": "pay_1", "fields": ["status", "card.network", `+
+				`"notes.key", "missing.field"]}`)
+
+		require.False(t, result.IsError)
+		want := map[string]interface{}{
+			"id":     "pay_1",
+			"status": "captured",
+			"card":   map[string]interface{}{"network": "Visa"},
+			"notes": []interface{}{
+				map[string]interface{}{"key": "a"},
+			},
+		}
+		assert.Equal(t, want, result.StructuredContent)
+		assert.JSONEq(t, `{"id": "pay_1", "status": "captured", `+
+			`"card": {"network": "Visa"}, "notes": [{"key": "a"}]}`,
+			resultTextOf(t, result))
+		assert.Equal(t, map[string]interface{}{"thing_id": "pay_1"}, args)
+	})
+
+	t.Run("selects the fields of collection items", func(t *testing.T) {
+		var args map[string]interface{}
+		srv := newFieldsTestServer(map[string]interface{}{
+			"entity": "collection",
+			"count":  float64(1),
+			"items":  []interface{}{payment},
+		}, &args)
+
+		result := callToolWithArgs(t, srv, "fetch_thing",
+			`{"fields": ["card", "card.network"]}`)
+
+		require.False(t, result.IsError)
+		assert.Equal(t, map[string]interface{}{
+			"entity": "collection",
+			"count":  float64(1),
+			"items": []interface{}{
+				map[string]interface{}{
+					"id": "pay_1",
+					"card": map[string]interface{}{
+						"network": "Visa",
+						"last4":   "1111",
+					},
+				},
+			},
+		}, result.StructuredContent)
+	})
+
+	t.Run("returns every field without fields", func(t *testing.T) {
+		var args map[string]interface{}
+		srv := newFieldsTestServer(payment, &args)
+
+		result := callToolWithArgs(t, srv, "fetch_thing", `{}`)
+
+		require.False(t, result.IsError)
+		assert.Equal(t, payment, result.StructuredContent)
+	})
+
+	t.Run("passes the fields to results that are not JSON",
+		func(t *testing.T) {
+			var selected []string
+			tool := NewTool("export_things", "Exports things", nil,
+				func(ctx context.Context, r CallToolRequest) (
+					*ToolResult, error) {
+					selected = SelectedFieldsFromContext(ctx)
+					return NewToolResultRawText("id,status\n"), nil
+				}).WithFieldSelection()
+			tool.SetReadOnly(true)
+			srv := NewMcpServer("test-server", "1.0.0",
+				WithToolCapabilities(true))
+			srv.AddTools(tool)
+
+			result := callToolWithArgs(t, srv, "export_things",
+				`{"fields": ["status"]}`)
+
+			require.False(t, result.IsError)
+			assert.Equal(t, "id,status\n", resultTextOf(t, result))
+			assert.Equal(t, []string{"status"}, selected)
+		})
+
+	t.Run("rejects fields that are not names", func(t *testing.T) {
+		var args map[string]interface{}
+		srv := newFieldsTestServer(payment, &args)
+
+		result := callToolWithArgs(t, srv, "fetch_thing",
+			`{"fields": "status"}`)
+
+		assert.True(t, result.IsError)
+		assert.Equal(t, "invalid fields: must be an array of field names",
+			resultTextOf(t, result))
+		assert.Nil(t, args)
+	})
+}
+
+func TestSelectFields(t *testing.T) {
+	record := map[string]interface{}{
+		"id":     "pay_1",
+		"status": "captured",
+		"amount": float64(100),
+		"card":   map[string]interface{}{"network": "Visa", "last4": "1111"},
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"id":     "pay_1",
+		"status": "captured",
+		"card":   map[string]interface{}{"network": "Visa"},
+	}, SelectFields(record, []string{"status", "card.network"}))
+	assert.Equal(t, "1111", record["card"].(map[string]interface{})["last4"])
+}
@@ -0,0 +1,242 @@
+package mcpgo
+
+import (
+	"crypto/rand"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// sessionIDPrefix is the prefix of the session IDs issued to clients
+const sessionIDPrefix = "mcp-session-"
+
+// maxReplayableCalls is the number of finished tool calls kept per session
+// for replay to retrying clients
+const maxReplayableCalls = 100
+
+// errMissingSessionID is returned when a request carries no session ID
+var errMissingSessionID = errors.New("missing session id")
+
+// sessionStore issues and tracks the sessions of the streamable HTTP
+// transport. Sessions expire after being idle for longer than the TTL.
+type sessionStore struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	now      func() time.Time
+	sessions map[string]*httpSession
+	// backend shares sessions with other replicas of the server, if set.
+	// Sessions opened on other replicas are then tracked locally from
+	// their first request to this replica.
+	backend sessionBackend
+}
+
+// sessionBackend shares sessions between replicas of the server
+type sessionBackend interface {
+	// create records a new session, expiring after ttl if it is positive
+	create(sessionID string, ttl time.Duration) error
+	// touch reports whether a session exists, and extends its life by ttl
+	// if it is positive
+	touch(sessionID string, ttl time.Duration) (bool, error)
+	// remove deletes a session
+	remove(sessionID string) error
+}
+
+// httpSession is a client session and the tool calls made in it
+type httpSession struct {
+	lastSeen time.Time
+	calls    map[string]*toolCall
+	// finished holds the IDs of finished calls, oldest first
+	finished []string
+}
+
+// toolCall is a tool call whose response can be replayed. done is closed
+// once response is set.
+type toolCall struct {
+	done     chan struct{}
+	response *responseRecorder
+}
+
+func newSessionStore(
+	ttl time.Duration,
+	backend sessionBackend,
+) *sessionStore {
+	return &sessionStore{
+		ttl:      ttl,
+		now:      time.Now,
+		sessions: make(map[string]*httpSession),
+		backend:  backend,
+	}
+}
+
+// Generate issues a new session ID
+func (s *sessionStore) Generate() string {
+	id := sessionIDPrefix + rand.Text()
+
+	s.track(id)
+	if s.backend != nil {
+		// A session the backend does not know is only valid on this
+		// replica, and clients start a new one on other replicas
+		_ = s.backend.create(id, s.ttl)
+	}
+
+	return id
+}
+
+// track starts tracking a session locally, unless it already is
+func (s *sessionStore) track(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.removeExpired()
+	if _, ok := s.sessions[sessionID]; ok {
+		return
+	}
+	s.sessions[sessionID] = &httpSession{
+		lastSeen: s.now(),
+		calls:    make(map[string]*toolCall),
+	}
+}
+
+// Validate reports a session as terminated if it is unknown or has expired,
+// so the client starts a new one. Otherwise the session is kept alive.
+func (s *sessionStore) Validate(sessionID string) (bool, error) {
+	if sessionID == "" {
+		return false, errMissingSessionID
+	}
+
+	s.mu.Lock()
+	session := s.activeSession(sessionID)
+	if session != nil {
+		session.lastSeen = s.now()
+	}
+	s.mu.Unlock()
+
+	if s.backend == nil {
+		return session == nil, nil
+	}
+
+	exists, err := s.backend.touch(sessionID, s.ttl)
+	if err != nil {
+		return false, err
+	}
+	if !exists {
+		// The session expired or was terminated on another replica
+		s.mu.Lock()
+		delete(s.sessions, sessionID)
+		s.mu.Unlock()
+		return true, nil
+	}
+	if session == nil {
+		s.track(sessionID)
+	}
+
+	return false, nil
+}
+
+// active returns the number of sessions that have not expired
+func (s *sessionStore) active() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.removeExpired()
+	return len(s.sessions)
+}
+
+// Terminate ends a session at the client's request
+func (s *sessionStore) Terminate(sessionID string) (bool, error) {
+	s.mu.Lock()
+	delete(s.sessions, sessionID)
+	s.mu.Unlock()
+
+	if s.backend != nil {
+		return false, s.backend.remove(sessionID)
+	}
+	return false, nil
+}
+
+// startCall returns the tool call with the given request ID in a session,
+// and whether the caller started it and must run it. It returns nil if the
+// session is unknown or has expired.
+func (s *sessionStore) startCall(
+	sessionID string,
+	requestID string,
+) (*toolCall, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session := s.activeSession(sessionID)
+	if session == nil {
+		return nil, false
+	}
+	session.lastSeen = s.now()
+
+	if call, ok := session.calls[requestID]; ok {
+		return call, false
+	}
+
+	call := &toolCall{done: make(chan struct{})}
+	session.calls[requestID] = call
+
+	return call, true
+}
+
+// finishCall records the response of a tool call and releases any retries
+// waiting for it. Only successful responses are kept for replay.
+func (s *sessionStore) finishCall(
+	sessionID string,
+	requestID string,
+	call *toolCall,
+	response *responseRecorder,
+) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	call.response = response
+	close(call.done)
+
+	session, ok := s.sessions[sessionID]
+	if !ok {
+		return
+	}
+
+	if response.status != http.StatusOK {
+		delete(session.calls, requestID)
+		return
+	}
+
+	session.finished = append(session.finished, requestID)
+	if len(session.finished) > maxReplayableCalls {
+		delete(session.calls, session.finished[0])
+		session.finished = session.finished[1:]
+	}
+}
+
+// activeSession returns a session if it exists and has not expired,
+// removing it if it has. The caller must hold the lock.
+func (s *sessionStore) activeSession(sessionID string) *httpSession {
+	session, ok := s.sessions[sessionID]
+	if !ok {
+		return nil
+	}
+	if s.expired(session) {
+		delete(s.sessions, sessionID)
+		return nil
+	}
+
+	return session
+}
+
+// removeExpired removes all expired sessions. The caller must hold the lock.
+func (s *sessionStore) removeExpired() {
+	for id, session := range s.sessions {
+		if s.expired(session) {
+			delete(s.sessions, id)
+		}
+	}
+}
+
+// expired reports whether a session has been idle for longer than the TTL
+func (s *sessionStore) expired(session *httpSession) bool {
+	return s.ttl > 0 && s.now().Sub(session.lastSeen) > s.ttl
+}
@@ -0,0 +1,377 @@
+package mcpgo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
+)
+
+// IdempotencyKeyParameter is the name of the parameter, accepted by every
+// write tool, that makes retries of a call return the result of the first
+// call instead of running again
+const IdempotencyKeyParameter = "idempotency_key"
+
+// IdempotencyKeyReusedErrorCode identifies the error returned for calls
+// that reuse the idempotency key of a call with other parameters
+const IdempotencyKeyReusedErrorCode = "IDEMPOTENCY_KEY_REUSED"
+
+// DefaultIdempotencyTTL is how long results are kept under their
+// idempotency key by default
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+// maxIdempotencyRecords bounds the number of results kept by the memory
+// store
+const maxIdempotencyRecords = 10000
+
+// IdempotencyRecord is the result of a successful call to a write tool,
+// kept under the idempotency key of the call
+type IdempotencyRecord struct {
+	// Arguments is the digest of the arguments of the call
+	Arguments string `json:"arguments"`
+	// Result is the JSON encoded result of the call
+	Result json.RawMessage `json:"result"`
+	// Expires is when the record stops being replayed
+	Expires time.Time `json:"expires"`
+}
+
+// IdempotencyStore keeps the results of write tool calls under their
+// idempotency keys. Stores may drop expired records.
+type IdempotencyStore interface {
+	// Load returns the record kept under the key, if any
+	Load(key string) (IdempotencyRecord, bool, error)
+	// Save keeps the record under the key
+	Save(key string, record IdempotencyRecord) error
+}
+
+// idempotencyOption is the option value that configures idempotency keys
+type idempotencyOption struct {
+	store IdempotencyStore
+	ttl   time.Duration
+}
+
+// WithIdempotency returns a server option that keeps the results of write
+// tool calls with an idempotency key in the store for the given time. A nil
+// store keeps them in memory, and a TTL of 0 keeps them for
+// DefaultIdempotencyTTL.
+func WithIdempotency(store IdempotencyStore, ttl time.Duration) ServerOption {
+	return func(s OptionSetter) error {
+		return s.SetOption(idempotencyOption{store: store, ttl: ttl})
+	}
+}
+
+// idempotencyGuard replays the results of write tool calls that reuse an
+// idempotency key
+type idempotencyGuard struct {
+	store IdempotencyStore
+	ttl   time.Duration
+	// now returns the current time, and is replaced in tests
+	now func() time.Time
+
+	// locks serialize the calls with the same key, so that a retry sent
+	// while the first call runs waits for its result
+	mu    sync.Mutex
+	locks map[string]*idempotencyLock
+}
+
+// idempotencyLock is the lock of a key, dropped once no call holds it
+type idempotencyLock struct {
+	mu    sync.Mutex
+	calls int
+}
+
+// newIdempotencyGuard creates a guard that keeps results in the store
+func newIdempotencyGuard(option idempotencyOption) *idempotencyGuard {
+	guard := &idempotencyGuard{
+		store: option.store,
+		ttl:   option.ttl,
+		now:   time.Now,
+		locks: make(map[string]*idempotencyLock),
+	}
+	if guard.store == nil {
+		guard.store = NewMemoryIdempotencyStore()
+	}
+	if guard.ttl <= 0 {
+		guard.ttl = DefaultIdempotencyTTL
+	}
+	return guard
+}
+
+// lock locks the key and returns the function that unlocks it
+func (g *idempotencyGuard) lock(key string) func() {
+	g.mu.Lock()
+	l, ok := g.locks[key]
+	if !ok {
+		l = &idempotencyLock{}
+		g.locks[key] = l
+	}
+	l.calls++
+	g.mu.Unlock()
+
+	l.mu.Lock()
+	return func() {
+		l.mu.Unlock()
+
+		g.mu.Lock()
+		l.calls--
+		if l.calls == 0 {
+			delete(g.locks, key)
+		}
+		g.mu.Unlock()
+	}
+}
+
+// withIdempotency wraps the handler of a write tool so that calls with the
+// idempotency key of an earlier successful call return its result instead
+// of running again. Dry runs are neither replayed nor kept.
+func (g *idempotencyGuard) withIdempotency(
+	serverTool server.ServerTool,
+) server.ServerTool {
+	if isReadOnlyTool(serverTool.Tool) {
+		return serverTool
+	}
+
+	handler := serverTool.Handler
+	serverTool.Handler = func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		idempotencyKey, _ := req.GetArguments()[IdempotencyKeyParameter].(string)
+		if idempotencyKey == "" || DryRunFromContext(ctx) != nil {
+			return handler(ctx, req)
+		}
+
+		// Keys are only shared between calls to the same tool with the
+		// same credentials
+		key := contextkey.CacheScopeFromContext(ctx) + "\x00" +
+			req.Params.Name + "\x00" + idempotencyKey
+		digest, err := argumentsDigest(req)
+		if err != nil {
+			return handler(ctx, req)
+		}
+
+		unlock := g.lock(key)
+		defer unlock()
+
+		record, ok, err := g.store.Load(key)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("loading idempotency "+
+				"key %s failed: %s", idempotencyKey, err)), nil
+		}
+		if ok && g.now().Before(record.Expires) {
+			if record.Arguments != digest {
+				return idempotencyKeyReused(req.Params.Name,
+					idempotencyKey), nil
+			}
+			var result mcp.CallToolResult
+			if err := json.Unmarshal(record.Result, &result); err == nil {
+				return &result, nil
+			}
+		}
+
+		result, err := handler(ctx, req)
+		if err != nil || result == nil || result.IsError {
+			return result, err
+		}
+
+		// The call already ran, so a result that cannot be kept only
+		// loses its protection against retries
+		if data, err := json.Marshal(result); err == nil {
+			_ = g.store.Save(key, IdempotencyRecord{
+				Arguments: digest,
+				Result:    data,
+				Expires:   g.now().Add(g.ttl),
+			})
+		}
+		return result, nil
+	}
+
+	return serverTool
+}
+
+// argumentsDigest returns the digest of the arguments of a call. Map keys
+// are sorted when marshalled, so equal arguments give equal digests.
+func argumentsDigest(req mcp.CallToolRequest) (string, error) {
+	arguments, err := json.Marshal(req.Params.Arguments)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(arguments)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// idempotencyKeyReused returns the error result of a call that reuses the
+// idempotency key of a call with other parameters
+func idempotencyKeyReused(
+	toolName string,
+	idempotencyKey string,
+) *mcp.CallToolResult {
+	description := fmt.Sprintf("idempotency key %s was already used for "+
+		"a call to tool %s with other parameters, use a new key for a new "+
+		"call", idempotencyKey, toolName)
+
+	result := mcp.NewToolResultStructured(map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":            IdempotencyKeyReusedErrorCode,
+			"tool":            toolName,
+			"idempotency_key": idempotencyKey,
+			"description":     description,
+		},
+	}, description)
+	result.IsError = true
+
+	return result
+}
+
+// MemoryIdempotencyStore keeps idempotency records in memory, so they are
+// lost when the server stops
+type MemoryIdempotencyStore struct {
+	// now returns the current time, and is replaced in tests
+	now func() time.Time
+
+	mu      sync.Mutex
+	records map[string]IdempotencyRecord
+}
+
+// NewMemoryIdempotencyStore creates an empty memory store
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{
+		now:     time.Now,
+		records: make(map[string]IdempotencyRecord),
+	}
+}
+
+// Load returns the record kept under the key, if any
+func (s *MemoryIdempotencyStore) Load(
+	key string,
+) (IdempotencyRecord, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[key]
+	return record, ok, nil
+}
+
+// Save keeps the record under the key. Expired records are dropped when
+// the store is full, and records that do not fit are not kept.
+func (s *MemoryIdempotencyStore) Save(
+	key string,
+	record IdempotencyRecord,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.records[key]; !exists &&
+		len(s.records) >= maxIdempotencyRecords {
+		s.evictExpired()
+		if len(s.records) >= maxIdempotencyRecords {
+			return fmt.Errorf("idempotency store is full")
+		}
+	}
+	s.records[key] = record
+	return nil
+}
+
+// evictExpired drops expired records. The caller must hold s.mu.
+func (s *MemoryIdempotencyStore) evictExpired() {
+	now := s.now()
+	for key, record := range s.records {
+		if !now.Before(record.Expires) {
+			delete(s.records, key)
+		}
+	}
+}
+
+// FileIdempotencyStore keeps idempotency records in memory and in a JSON
+// file, so they survive restarts of the server
+type FileIdempotencyStore struct {
+	memory *MemoryIdempotencyStore
+	path   string
+
+	// mu serializes writes of the file
+	mu sync.Mutex
+}
+
+// NewFileIdempotencyStore creates a store backed by the file at path,
+// loading the records the file holds. The file is created on the first
+// save if it does not exist.
+func NewFileIdempotencyStore(path string) (*FileIdempotencyStore, error) {
+	s := &FileIdempotencyStore{
+		memory: NewMemoryIdempotencyStore(),
+		path:   path,
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading idempotency store: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &s.memory.records); err != nil {
+			return nil, fmt.Errorf("parsing idempotency store %s: %w",
+				path, err)
+		}
+	}
+	s.memory.evictExpired()
+
+	return s, nil
+}
+
+// Load returns the record kept under the key, if any
+func (s *FileIdempotencyStore) Load(
+	key string,
+) (IdempotencyRecord, bool, error) {
+	return s.memory.Load(key)
+}
+
+// Save keeps the record under the key and rewrites the file
+func (s *FileIdempotencyStore) Save(
+	key string,
+	record IdempotencyRecord,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.memory.Save(key, record); err != nil {
+		return err
+	}
+
+	s.memory.mu.Lock()
+	s.memory.evictExpired()
+	data, err := json.Marshal(s.memory.records)
+	s.memory.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	// Replacing the file keeps it whole if the server stops mid write
+	tmp, err := os.CreateTemp(filepath.Dir(s.path),
+		filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("writing idempotency store: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("writing idempotency store: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing idempotency store: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("writing idempotency store: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package mcpgo
+
+import (
+	"context"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newIdempotencyTestServer creates a server with a write tool that returns
+// the number of times it ran, and fails for calls with fail set
+func newIdempotencyTestServer(opts ...ServerOption) *Mark3labsImpl {
+	var mu sync.Mutex
+	calls := 0
+	handler := func(
+		ctx context.Context,
+		r CallToolRequest,
+	) (*ToolResult, error) {
+		args, _ := r.Arguments.(map[string]interface{})
+		if fail, _ := args["fail"].(bool); fail {
+			return NewToolResultError("creating thing failed"), nil
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		return NewToolResultText("call " + strconv.Itoa(calls)), nil
+	}
+
+	writeTool := NewTool("create_thing", "Creates a thing", nil, handler)
+	writeTool.SetReadOnly(false)
+
+	srv := NewMcpServer("test-server", "1.0.0",
+		append([]ServerOption{WithToolCapabilities(true)}, opts...)...)
+	srv.AddTools(writeTool)
+
+	return srv
+}
+
+// resultContent returns the text content of a tool result
+func resultContent(t *testing.T, result *mcp.CallToolResult) string {
+	t.Helper()
+
+	require.NotEmpty(t, result.Content)
+	text, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	return text.Text
+}
+
+func TestIdempotency(t *testing.T) {
+	t.Run("runs calls without a key every time", func(t *testing.T) {
+		srv := newIdempotencyTestServer()
+
+		assert.Equal(t, "call 1", resultContent(t,
+			callToolWithArgs(t, srv, "create_thing", `{}`)))
+		assert.Equal(t, "call 2", resultContent(t,
+			callToolWithArgs(t, srv, "create_thing", `{}`)))
+	})
+
+	t.Run("replays the result of retried calls", func(t *testing.T) {
+		srv := newIdempotencyTestServer()
+		args := `{"name":"a","idempotency_key":"key-1"}`
+
+		assert.Equal(t, "call 1", resultContent(t,
+			callToolWithArgs(t, srv, "create_thing", args)))
+		assert.Equal(t, "call 1", resultContent(t,
+			callToolWithArgs(t, srv, "create_thing", args)))
+		assert.Equal(t, "call 2", resultContent(t,
+			callToolWithArgs(t, srv, "create_thing",
+				`{"name":"a","idempotency_key":"key-2"}`)))
+	})
+
+	t.Run("rejects keys reused with other parameters", func(t *testing.T) {
+		srv := newIdempotencyTestServer()
+
+		callToolWithArgs(t, srv, "create_thing",
+			`{"name":"a","idempotency_key":"key-1"}`)
+		result := callToolWithArgs(t, srv, "create_thing",
+			`{"name":"b","idempotency_key":"key-1"}`)
+		assert.True(t, result.IsError)
+		assert.Equal(t, map[string]interface{}{
+			"error": map[string]interface{}{
+				"code":            IdempotencyKeyReusedErrorCode,
+				"tool":            "create_thing",
+				"idempotency_key": "key-1",
+				"description": "idempotency key key-1 was already used " +
+					"for a call to tool create_thing with other " +
+					"parameters, use a new key for a new call",
+			},
+		}, result.StructuredContent)
+	})
+
+	t.Run("runs failed calls again", func(t *testing.T) {
+		srv := newIdempotencyTestServer()
+
+		result := callToolWithArgs(t, srv, "create_thing",
+			`{"fail":true,"idempotency_key":"key-1"}`)
+		assert.True(t, result.IsError)
+		result = callToolWithArgs(t, srv, "create_thing",
+			`{"fail":true,"idempotency_key":"key-1"}`)
+		assert.True(t, result.IsError)
+	})
+
+	t.Run("runs calls again once their key expired", func(t *testing.T) {
+		srv := newIdempotencyTestServer(WithIdempotency(nil, time.Minute))
+		now := time.Now()
+		srv.idempotency.now = func() time.Time { return now }
+		args := `{"idempotency_key":"key-1"}`
+
+		assert.Equal(t, "call 1", resultContent(t,
+			callToolWithArgs(t, srv, "create_thing", args)))
+		now = now.Add(time.Minute)
+		assert.Equal(t, "call 2", resultContent(t,
+			callToolWithArgs(t, srv, "create_thing", args)))
+	})
+
+	t.Run("runs concurrent retries once", func(t *testing.T) {
+		srv := newIdempotencyTestServer()
+
+		var wg sync.WaitGroup
+		results := make([]string, 5)
+		for i := range results {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				results[i] = resultContent(t, callToolWithArgs(t, srv,
+					"create_thing", `{"idempotency_key":"key-1"}`))
+			}(i)
+		}
+		wg.Wait()
+
+		for _, result := range results {
+			assert.Equal(t, "call 1", result)
+		}
+		assert.Empty(t, srv.idempotency.locks)
+	})
+
+	t.Run("keeps results in a file store across restarts",
+		func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "idempotency.json")
+			args := `{"idempotency_key":"key-1"}`
+
+			store, err := NewFileIdempotencyStore(path)
+			require.NoError(t, err)
+			srv := newIdempotencyTestServer(WithIdempotency(store, 0))
+			assert.Equal(t, "call 1", resultContent(t,
+				callToolWithArgs(t, srv, "create_thing", args)))
+
+			store, err = NewFileIdempotencyStore(path)
+			require.NoError(t, err)
+			srv = newIdempotencyTestServer(WithIdempotency(store, 0))
+			assert.Equal(t, "call 1", resultContent(t,
+				callToolWithArgs(t, srv, "create_thing", args)))
+		})
+}
+
+func TestMemoryIdempotencyStore(t *testing.T) {
+	store := NewMemoryIdempotencyStore()
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	for i := 0; i < maxIdempotencyRecords; i++ {
+		require.NoError(t, store.Save(strconv.Itoa(i),
+			IdempotencyRecord{Expires: now.Add(time.Minute)}))
+	}
+	assert.Error(t, store.Save("full", IdempotencyRecord{}))
+
+	// expired records make room for new ones
+	now = now.Add(time.Minute)
+	require.NoError(t, store.Save("new", IdempotencyRecord{}))
+	_, ok, err := store.Load("0")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,25 @@
+package mcpgo
+
+import (
+	"regexp"
+	"strings"
+)
+
+// AccountIDPrefix is the prefix of the IDs of Razorpay accounts, such as
+// acc_Ef7ArAsdU5t0XL
+const AccountIDPrefix = "acc_"
+
+// idSuffixPattern matches what follows the prefix of a Razorpay ID
+var idSuffixPattern = regexp.MustCompile(`^[A-Za-z0-9]+$`)
+
+// IsID returns whether the value is a Razorpay ID with the prefix
+func IsID(value, prefix string) bool {
+	suffix, ok := strings.CutPrefix(value, prefix)
+	return ok && idSuffixPattern.MatchString(suffix)
+}
+
+// IDPattern returns the pattern of the Razorpay IDs with the prefix, for
+// the schemas of ID parameters
+func IDPattern(prefix string) string {
+	return "^" + regexp.QuoteMeta(prefix) + "[A-Za-z0-9]+$"
+}
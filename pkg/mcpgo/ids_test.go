@@ -0,0 +1,22 @@
+package mcpgo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsID(t *testing.T) {
+	assert.True(t, IsID("acc_Ef7ArAsdU5t0XL", AccountIDPrefix))
+	assert.True(t, IsID("HK890egfiItP3H", ""))
+	assert.False(t, IsID("pay_Ef7ArAsdU5t0XL", AccountIDPrefix))
+	assert.False(t, IsID("acc_", AccountIDPrefix))
+	assert.False(t, IsID("acc_Ef7/../payments", AccountIDPrefix))
+	assert.False(t, IsID("acc_prd_HEgNpywUFctQ9e", AccountIDPrefix))
+	assert.False(t, IsID("", ""))
+}
+
+func TestIDPattern(t *testing.T) {
+	assert.Equal(t, `^acc_[A-Za-z0-9]+$`, IDPattern(AccountIDPrefix))
+	assert.Equal(t, `^[A-Za-z0-9]+$`, IDPattern(""))
+}
@@ -0,0 +1,107 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// KeyModeErrorCode identifies the error returned for calls to write tools
+// with a key of the other mode
+const KeyModeErrorCode = "KEY_MODE_MISMATCH"
+
+// Modes of Razorpay API keys
+const (
+	KeyModeTest = "test"
+	KeyModeLive = "live"
+)
+
+// keyModeOption is the option value that restricts write tools to keys of
+// a mode
+type keyModeOption struct {
+	mode string
+	key  func(ctx context.Context) string
+}
+
+// WithKeyMode returns a server option that rejects calls to write tools
+// unless the key they are made with, as returned by key, is of the given
+// mode: test for rzp_test_ keys and live for rzp_live_ keys. Keys of
+// neither form are rejected too. An empty mode allows every key.
+func WithKeyMode(
+	mode string,
+	key func(ctx context.Context) string,
+) ServerOption {
+	return func(s OptionSetter) error {
+		return s.SetOption(keyModeOption{mode: mode, key: key})
+	}
+}
+
+// KeyMode returns the mode of a Razorpay API key, or an empty string if
+// the key is of neither mode
+func KeyMode(key string) string {
+	switch {
+	case strings.HasPrefix(key, "rzp_test_"):
+		return KeyModeTest
+	case strings.HasPrefix(key, "rzp_live_"):
+		return KeyModeLive
+	default:
+		return ""
+	}
+}
+
+// enforceKeyMode wraps the handler of a write tool so that it rejects
+// calls made with a key of another mode than the server's
+func (s *Mark3labsImpl) enforceKeyMode(
+	serverTool server.ServerTool,
+) server.ServerTool {
+	if s.keyMode.mode == "" || isReadOnlyTool(serverTool.Tool) {
+		return serverTool
+	}
+
+	handler := serverTool.Handler
+	serverTool.Handler = func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		keyMode := KeyMode(s.keyMode.key(ctx))
+		if keyMode != s.keyMode.mode {
+			return keyModeViolation(req.Params.Name, s.keyMode.mode,
+				keyMode), nil
+		}
+		return handler(ctx, req)
+	}
+
+	return serverTool
+}
+
+// keyModeViolation returns the error result of a write tool called with a
+// key of another mode than the server's
+func keyModeViolation(
+	toolName string,
+	mode string,
+	keyMode string,
+) *mcp.CallToolResult {
+	keyDescription := "a key of unknown mode"
+	if keyMode != "" {
+		keyDescription = "a " + keyMode + " key"
+	}
+	description := fmt.Sprintf(
+		"tool %s modifies data and cannot be called with %s while the "+
+			"server runs in %s mode", toolName, keyDescription, mode)
+
+	result := mcp.NewToolResultStructured(map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":        KeyModeErrorCode,
+			"tool":        toolName,
+			"mode":        mode,
+			"key_mode":    keyMode,
+			"description": description,
+		},
+	}, description)
+	result.IsError = true
+
+	return result
+}
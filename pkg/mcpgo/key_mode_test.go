@@ -0,0 +1,80 @@
+package mcpgo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fixedKey returns a key function that always returns the key
+func fixedKey(key string) func(ctx context.Context) string {
+	return func(ctx context.Context) string { return key }
+}
+
+func TestKeyMode(t *testing.T) {
+	assert.Equal(t, KeyModeTest, KeyMode("rzp_test_abc"))
+	assert.Equal(t, KeyModeLive, KeyMode("rzp_live_abc"))
+	assert.Empty(t, KeyMode("abc"))
+	assert.Empty(t, KeyMode(""))
+}
+
+func TestKeyModeGuard(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("allows every key without a mode", func(t *testing.T) {
+		srv := newReadOnlyTestServer(
+			WithKeyMode("", fixedKey("rzp_live_abc")))
+
+		assert.False(t, callTool(t, ctx, srv, "create_thing").IsError)
+	})
+
+	t.Run("allows write tools with a key of the mode", func(t *testing.T) {
+		srv := newReadOnlyTestServer(
+			WithKeyMode(KeyModeTest, fixedKey("rzp_test_abc")))
+
+		assert.False(t, callTool(t, ctx, srv, "create_thing").IsError)
+	})
+
+	t.Run("rejects write tools with a key of the other mode",
+		func(t *testing.T) {
+			srv := newReadOnlyTestServer(
+				WithKeyMode(KeyModeTest, fixedKey("rzp_live_abc")))
+
+			assert.False(t, callTool(t, ctx, srv, "fetch_thing").IsError)
+
+			result := callTool(t, ctx, srv, "create_thing")
+			assert.True(t, result.IsError)
+			assert.Equal(t, map[string]interface{}{
+				"error": map[string]interface{}{
+					"code":     KeyModeErrorCode,
+					"tool":     "create_thing",
+					"mode":     "test",
+					"key_mode": "live",
+					"description": "tool create_thing modifies data and " +
+						"cannot be called with a live key while the server " +
+						"runs in test mode",
+				},
+			}, result.StructuredContent)
+		})
+
+	t.Run("rejects write tools with a key of unknown mode",
+		func(t *testing.T) {
+			srv := newReadOnlyTestServer(
+				WithKeyMode(KeyModeLive, fixedKey("")))
+
+			result := callTool(t, ctx, srv, "update_thing")
+			assert.True(t, result.IsError)
+			assert.Equal(t, map[string]interface{}{
+				"error": map[string]interface{}{
+					"code":     KeyModeErrorCode,
+					"tool":     "update_thing",
+					"mode":     "live",
+					"key_mode": "",
+					"description": "tool update_thing modifies data and " +
+						"cannot be called with a key of unknown mode while " +
+						"the server runs in live mode",
+				},
+			}, result.StructuredContent)
+		})
+}
@@ -0,0 +1,45 @@
+package mcpgo
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
+)
+
+// localeOption is the option value that sets the locale of tool calls
+type localeOption string
+
+// WithLocale returns a server option that records the locale in the
+// context of every tool call, so that tools can write guidance in that
+// language. A locale already recorded in the request context is kept.
+func WithLocale(locale string) ServerOption {
+	return func(s OptionSetter) error {
+		return s.SetOption(localeOption(locale))
+	}
+}
+
+// withLocale wraps the handler of a tool so that it runs with the locale
+// of the server
+func (s *Mark3labsImpl) withLocale(
+	serverTool server.ServerTool,
+) server.ServerTool {
+	if s.locale == "" {
+		return serverTool
+	}
+
+	handler := serverTool.Handler
+	serverTool.Handler = func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		if contextkey.LocaleFromContext(ctx) == "" {
+			ctx = contextkey.WithLocale(ctx, s.locale)
+		}
+		return handler(ctx, req)
+	}
+
+	return serverTool
+}
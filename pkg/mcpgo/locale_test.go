@@ -0,0 +1,49 @@
+package mcpgo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
+)
+
+func TestWithLocale(t *testing.T) {
+	// callLocale calls a tool that returns the locale of its context
+	callLocale := func(
+		ctx context.Context,
+		opts ...ServerOption,
+	) string {
+		tool := NewTool("fetch_locale", "Returns the locale", nil,
+			func(ctx context.Context, r CallToolRequest) (*ToolResult, error) {
+				return NewToolResultText(contextkey.LocaleFromContext(ctx)), nil
+			})
+		tool.SetReadOnly(true)
+		srv := NewMcpServer("test-server", "1.0.0", opts...)
+		srv.AddTools(tool)
+
+		serverTool := srv.McpServer.GetTool("fetch_locale")
+		require.NotNil(t, serverTool)
+		result, err := serverTool.Handler(ctx, mcp.CallToolRequest{})
+		require.NoError(t, err)
+		text, ok := mcp.AsTextContent(result.Content[0])
+		require.True(t, ok)
+		return text.Text
+	}
+
+	t.Run("leaves the locale unset by default", func(t *testing.T) {
+		assert.Empty(t, callLocale(context.Background()))
+	})
+
+	t.Run("records the server locale", func(t *testing.T) {
+		assert.Equal(t, "hi", callLocale(context.Background(), WithLocale("hi")))
+	})
+
+	t.Run("keeps the locale of the request", func(t *testing.T) {
+		ctx := contextkey.WithLocale(context.Background(), "ta")
+		assert.Equal(t, "ta", callLocale(ctx, WithLocale("hi")))
+	})
+}
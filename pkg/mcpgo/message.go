@@ -0,0 +1,170 @@
+package mcpgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// DefaultMaxRequestSize is the largest request body the streamable HTTP
+// transport accepts by default, in bytes
+const DefaultMaxRequestSize = 4 << 20
+
+// messageError is the JSON-RPC error returned for a malformed message
+type messageError struct {
+	// id is the ID of the message, nil if it has none or it is invalid
+	id      interface{}
+	code    int
+	message string
+}
+
+// readMessage reads the JSON-RPC message in the body of a POST request. It
+// answers the request with an error and returns false if the body is
+// larger than maxSize bytes or is not a valid message.
+func readMessage(
+	w http.ResponseWriter,
+	r *http.Request,
+	maxSize int64,
+) ([]byte, bool) {
+	if maxSize > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, maxSize)
+	}
+	body, err := io.ReadAll(r.Body)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeMessageError(w, http.StatusRequestEntityTooLarge, &messageError{
+			code: mcp.INVALID_REQUEST,
+			message: fmt.Sprintf("request body is larger than %d bytes",
+				tooLarge.Limit),
+		})
+		return nil, false
+	}
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return nil, false
+	}
+
+	if msgErr := validateMessage(body); msgErr != nil {
+		writeMessageError(w, http.StatusBadRequest, msgErr)
+		return nil, false
+	}
+	return body, true
+}
+
+// validateMessage checks that body is a single JSON-RPC 2.0 request,
+// notification or response, with the parameters tool calls need
+func validateMessage(body []byte) *messageError {
+	if !json.Valid(body) {
+		return &messageError{code: mcp.PARSE_ERROR,
+			message: "request body is not valid json"}
+	}
+	var message map[string]json.RawMessage
+	if err := json.Unmarshal(body, &message); err != nil || message == nil {
+		return &messageError{code: mcp.INVALID_REQUEST,
+			message: "message must be a json object, batches are not " +
+				"supported"}
+	}
+
+	id, hasID := message["id"]
+	var validID interface{}
+	if hasID {
+		validID = messageID(id)
+		if validID == nil {
+			return &messageError{code: mcp.INVALID_REQUEST,
+				message: "id must be a string or a number"}
+		}
+	}
+	invalid := func(code int, message string) *messageError {
+		return &messageError{id: validID, code: code, message: message}
+	}
+
+	if !bytes.Equal(message["jsonrpc"], []byte(`"2.0"`)) {
+		return invalid(mcp.INVALID_REQUEST, `jsonrpc must be "2.0"`)
+	}
+
+	rawMethod, hasMethod := message["method"]
+	if !hasMethod {
+		_, hasResult := message["result"]
+		_, hasError := message["error"]
+		if !hasID || hasResult == hasError {
+			return invalid(mcp.INVALID_REQUEST, "message must be a request "+
+				"with a method, or a response with an id and either a "+
+				"result or an error")
+		}
+		return nil
+	}
+
+	var method string
+	if err := json.Unmarshal(rawMethod, &method); err != nil || method == "" {
+		return invalid(mcp.INVALID_REQUEST, "method must be a string")
+	}
+	params, hasParams := message["params"]
+	if hasParams && !isJSONObject(params) {
+		return invalid(mcp.INVALID_PARAMS, "params must be an object")
+	}
+	if method == string(mcp.MethodToolsCall) {
+		return validateToolCallParams(params, invalid)
+	}
+	return nil
+}
+
+// validateToolCallParams checks the parameters of a tools/call request
+func validateToolCallParams(
+	params json.RawMessage,
+	invalid func(code int, message string) *messageError,
+) *messageError {
+	var call map[string]json.RawMessage
+	if err := json.Unmarshal(params, &call); err != nil || call == nil {
+		return invalid(mcp.INVALID_PARAMS, "tools/call requires params")
+	}
+
+	var name string
+	if err := json.Unmarshal(call["name"], &name); err != nil || name == "" {
+		return invalid(mcp.INVALID_PARAMS, "params.name must be the name "+
+			"of a tool")
+	}
+	arguments, ok := call["arguments"]
+	if ok && !isJSONObject(arguments) && !bytes.Equal(arguments,
+		[]byte("null")) {
+		return invalid(mcp.INVALID_PARAMS, "params.arguments must be an "+
+			"object")
+	}
+	return nil
+}
+
+// messageID returns the ID of a message if it is a string or a number, and
+// nil otherwise
+func messageID(raw json.RawMessage) interface{} {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var id interface{}
+	if err := decoder.Decode(&id); err != nil {
+		return nil
+	}
+	switch id.(type) {
+	case string, json.Number:
+		return id
+	default:
+		return nil
+	}
+}
+
+// isJSONObject reports whether raw holds a JSON object
+func isJSONObject(raw json.RawMessage) bool {
+	trimmed := bytes.TrimSpace(raw)
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
+
+// writeMessageError answers a request with a JSON-RPC error
+func writeMessageError(w http.ResponseWriter, status int, err *messageError) {
+	response := mcp.NewJSONRPCError(mcp.NewRequestId(err.id), err.code,
+		err.message, nil)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(response)
+}
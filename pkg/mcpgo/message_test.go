@@ -0,0 +1,140 @@
+package mcpgo
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		code    int
+		id      interface{}
+	}{
+		{
+			name:    "request",
+			message: `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`,
+		},
+		{
+			name:    "notification",
+			message: `{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		},
+		{
+			name:    "response",
+			message: `{"jsonrpc":"2.0","id":"s-1","result":{}}`,
+		},
+		{
+			name: "tool call",
+			message: `{"jsonrpc":"2.0","id":1,"method":"tools/call",` +
+				`"params":{"name":"fetch_payment","arguments":{"id":"pay_1"}}}`,
+		},
+		{
+			name:    "invalid json",
+			message: `{"jsonrpc":"2.0",`,
+			code:    mcp.PARSE_ERROR,
+		},
+		{
+			name:    "batch",
+			message: `[{"jsonrpc":"2.0","id":1,"method":"ping"}]`,
+			code:    mcp.INVALID_REQUEST,
+		},
+		{
+			name:    "object id",
+			message: `{"jsonrpc":"2.0","id":{},"method":"ping"}`,
+			code:    mcp.INVALID_REQUEST,
+		},
+		{
+			name:    "wrong version",
+			message: `{"jsonrpc":"1.0","id":1,"method":"ping"}`,
+			code:    mcp.INVALID_REQUEST,
+			id:      json.Number("1"),
+		},
+		{
+			name:    "number method",
+			message: `{"jsonrpc":"2.0","id":"a","method":1}`,
+			code:    mcp.INVALID_REQUEST,
+			id:      "a",
+		},
+		{
+			name:    "response with result and error",
+			message: `{"jsonrpc":"2.0","id":1,"result":{},"error":{}}`,
+			code:    mcp.INVALID_REQUEST,
+			id:      json.Number("1"),
+		},
+		{
+			name:    "array params",
+			message: `{"jsonrpc":"2.0","id":1,"method":"ping","params":[]}`,
+			code:    mcp.INVALID_PARAMS,
+			id:      json.Number("1"),
+		},
+		{
+			name:    "tool call without params",
+			message: `{"jsonrpc":"2.0","id":1,"method":"tools/call"}`,
+			code:    mcp.INVALID_PARAMS,
+			id:      json.Number("1"),
+		},
+		{
+			name: "tool call without a name",
+			message: `{"jsonrpc":"2.0","id":1,"method":"tools/call",` +
+				`"params":{"name":5}}`,
+			code: mcp.INVALID_PARAMS,
+			id:   json.Number("1"),
+		},
+		{
+			name: "tool call with string arguments",
+			message: `{"jsonrpc":"2.0","id":1,"method":"tools/call",` +
+				`"params":{"name":"fetch_payment","arguments":"pay_1"}}`,
+			code: mcp.INVALID_PARAMS,
+			id:   json.Number("1"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateMessage([]byte(tc.message))
+			if tc.code == 0 {
+				assert.Nil(t, err)
+				return
+			}
+			require.NotNil(t, err)
+			assert.Equal(t, tc.code, err.code)
+			assert.Equal(t, tc.id, err.id)
+		})
+	}
+}
+
+func TestMark3labsStreamableHTTPImpl_Messages(t *testing.T) {
+	t.Run("rejects malformed messages", func(t *testing.T) {
+		ts, _, calls := newCountingHTTPServer(t)
+		sessionID := initializeSession(t, ts)
+
+		resp, body := postMessage(t, ts, sessionID,
+			`{"jsonrpc":"2.0","id":7,"method":"tools/call",`+
+				`"params":{"name":"count","arguments":[]}}`)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.JSONEq(t, `{"jsonrpc":"2.0","id":7,"error":{"code":-32602,`+
+			`"message":"params.arguments must be an object"}}`, body)
+		assert.Equal(t, int32(0), calls.Load())
+	})
+
+	t.Run("rejects requests over the size limit", func(t *testing.T) {
+		ts, _, calls := newCountingHTTPServer(t, WithMaxRequestSize(64))
+
+		resp, body := postMessage(t, ts, "",
+			`{"jsonrpc":"2.0","id":0,"method":"initialize","params":{`+
+				`"clientInfo":{"name":"`+strings.Repeat("a", 64)+`"}}}`)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+		assert.JSONEq(t, `{"jsonrpc":"2.0","id":null,"error":{"code":-32600,`+
+			`"message":"request body is larger than 64 bytes"}}`, body)
+		assert.Equal(t, int32(0), calls.Load())
+	})
+}
@@ -0,0 +1,45 @@
+package mcpgo
+
+import (
+	"context"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+)
+
+// metricsOption is the option value that enables tool call metrics
+type metricsOption struct {
+	metrics *observability.Metrics
+}
+
+// WithMetrics returns a server option that records the count, duration
+// and outcome of tool calls in metrics
+func WithMetrics(metrics *observability.Metrics) ServerOption {
+	return func(s OptionSetter) error {
+		return s.SetOption(metricsOption{metrics: metrics})
+	}
+}
+
+// withToolMetrics wraps the handler of a tool so that its calls are
+// recorded in metrics. Calls that return an error result count as failed.
+func withToolMetrics(
+	metrics *observability.Metrics,
+	serverTool server.ServerTool,
+) server.ServerTool {
+	handler := serverTool.Handler
+	serverTool.Handler = func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		result, err := handler(ctx, req)
+		failed := err != nil || result == nil || result.IsError
+		metrics.ObserveToolCall(req.Params.Name, time.Since(start), failed)
+		return result, err
+	}
+
+	return serverTool
+}
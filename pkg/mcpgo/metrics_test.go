@@ -0,0 +1,35 @@
+package mcpgo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+)
+
+func TestToolMetrics(t *testing.T) {
+	metrics := observability.NewMetrics()
+	srv := newReadOnlyTestServer(
+		WithMetrics(metrics), WithReadOnly(true))
+	ctx := context.Background()
+
+	callTool(t, ctx, srv, "fetch_thing")
+	callTool(t, ctx, srv, "fetch_thing")
+	callTool(t, ctx, srv, "create_thing")
+
+	recorder := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(recorder,
+		httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := recorder.Body.String()
+
+	assert.Contains(t, body,
+		`razorpay_mcp_tool_calls_total{tool="fetch_thing",status="success"} 2`)
+	assert.Contains(t, body,
+		`razorpay_mcp_tool_calls_total{tool="create_thing",status="error"} 1`)
+	assert.Contains(t, body,
+		`razorpay_mcp_tool_call_duration_seconds_count{tool="fetch_thing"} 2`)
+}
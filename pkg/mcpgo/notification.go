@@ -0,0 +1,38 @@
+package mcpgo
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ErrSessionNotFound is returned when notifying a session that ended or
+// never existed
+var ErrSessionNotFound = server.ErrSessionNotFound
+
+// SessionID returns the ID of the session a tool call in ctx is made in,
+// empty if the call is not made in a session
+func SessionID(ctx context.Context) string {
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil {
+		return ""
+	}
+	return session.SessionID()
+}
+
+// SendLogMessage sends an info logging notification with the data to the
+// session with the ID. The notification is sent regardless of the logging
+// level the client set, so it should only carry what the client asked for.
+func (s *Mark3labsImpl) SendLogMessage(
+	sessionID string,
+	logger string,
+	data interface{},
+) error {
+	return s.McpServer.SendNotificationToSpecificClient(sessionID,
+		"notifications/message", map[string]any{
+			"level":  mcp.LoggingLevelInfo,
+			"logger": logger,
+			"data":   data,
+		})
+}
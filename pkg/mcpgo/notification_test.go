@@ -0,0 +1,63 @@
+package mcpgo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// notifiedSession is a client session whose notifications can be read
+type notifiedSession struct {
+	id            string
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *notifiedSession) SessionID() string { return s.id }
+func (s *notifiedSession) Initialize()       {}
+func (s *notifiedSession) Initialized() bool { return true }
+func (s *notifiedSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+
+func TestSessionID(t *testing.T) {
+	srv := NewMcpServer("test-server", "1.0.0")
+
+	assert.Empty(t, SessionID(context.Background()))
+	assert.Equal(t, "session-1", SessionID(srv.McpServer.WithContext(
+		context.Background(), server.NewInProcessSession("session-1", nil))))
+}
+
+func TestSendLogMessage(t *testing.T) {
+	t.Run("notifies the session", func(t *testing.T) {
+		srv := NewMcpServer("test-server", "1.0.0", WithLogging())
+		session := &notifiedSession{
+			id:            "session-1",
+			notifications: make(chan mcp.JSONRPCNotification, 1),
+		}
+		require.NoError(t, srv.McpServer.RegisterSession(
+			context.Background(), session))
+
+		err := srv.SendLogMessage("session-1", "events",
+			map[string]interface{}{"id": "pay_1"})
+		require.NoError(t, err)
+
+		notification := <-session.notifications
+		assert.Equal(t, "notifications/message", notification.Method)
+		assert.Equal(t, map[string]any{
+			"level":  mcp.LoggingLevelInfo,
+			"logger": "events",
+			"data":   map[string]interface{}{"id": "pay_1"},
+		}, notification.Params.AdditionalFields)
+	})
+
+	t.Run("reports sessions that ended", func(t *testing.T) {
+		srv := NewMcpServer("test-server", "1.0.0", WithLogging())
+
+		err := srv.SendLogMessage("session-1", "events", nil)
+		assert.ErrorIs(t, err, ErrSessionNotFound)
+	})
+}
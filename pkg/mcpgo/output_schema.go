@@ -0,0 +1,89 @@
+package mcpgo
+
+import (
+	"reflect"
+	"strings"
+)
+
+// SchemaFromStruct returns the JSON schema of the value v, which is
+// usually a struct, as used for tool output schemas.
+//
+// Struct fields are named after their json tags and described by their
+// description tags. Pointer fields may also be null, and interface fields
+// accept any value. No field is required and unknown fields are allowed,
+// so that the schema keeps validating as the API adds fields.
+func SchemaFromStruct(v interface{}) map[string]interface{} {
+	return schemaFromType(reflect.TypeOf(v))
+}
+
+// schemaFromType returns the JSON schema of values of type t
+func schemaFromType(t reflect.Type) map[string]interface{} {
+	if t == nil {
+		return map[string]interface{}{}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		schema := schemaFromType(t.Elem())
+		if typ, ok := schema["type"].(string); ok {
+			schema["type"] = []string{typ, "null"}
+		}
+		return schema
+	case reflect.Struct:
+		return structSchema(t)
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{
+			"type":  "array",
+			"items": schemaFromType(t.Elem()),
+		}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16,
+		reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	default:
+		// Interfaces and anything else accept any value
+		return map[string]interface{}{}
+	}
+}
+
+// structSchema returns the JSON schema of the object a struct type is
+// encoded as
+func structSchema(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Name
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			tagName, _, _ := strings.Cut(tag, ",")
+			if tagName == "-" {
+				continue
+			}
+			if tagName != "" {
+				name = tagName
+			}
+		}
+
+		schema := schemaFromType(field.Type)
+		if description := field.Tag.Get("description"); description != "" {
+			schema["description"] = description
+		}
+		properties[name] = schema
+	}
+
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+}
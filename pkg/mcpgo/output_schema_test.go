@@ -0,0 +1,71 @@
+package mcpgo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type schemaTestAddress struct {
+	City string `json:"city"`
+}
+
+type schemaTestEntity struct {
+	ID        string              `json:"id" description:"Entity ID"`
+	Amount    int64               `json:"amount"`
+	Rate      float64             `json:"rate,omitempty"`
+	Active    bool                `json:"active"`
+	Receipt   *string             `json:"receipt"`
+	Notes     interface{}         `json:"notes"`
+	Tags      []string            `json:"tags"`
+	Meta      map[string]string   `json:"meta"`
+	Address   *schemaTestAddress  `json:"address"`
+	Addresses []schemaTestAddress `json:"addresses"`
+	Untagged  string
+	Skipped   string `json:"-"`
+	internal  string
+}
+
+func TestSchemaFromStruct(t *testing.T) {
+	t.Run("converts struct fields", func(t *testing.T) {
+		address := map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"city": map[string]interface{}{"type": "string"},
+			},
+		}
+
+		assert.Equal(t, map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"id": map[string]interface{}{
+					"type":        "string",
+					"description": "Entity ID",
+				},
+				"amount":  map[string]interface{}{"type": "integer"},
+				"rate":    map[string]interface{}{"type": "number"},
+				"active":  map[string]interface{}{"type": "boolean"},
+				"receipt": map[string]interface{}{"type": []string{"string", "null"}},
+				"notes":   map[string]interface{}{},
+				"tags": map[string]interface{}{
+					"type":  "array",
+					"items": map[string]interface{}{"type": "string"},
+				},
+				"meta": map[string]interface{}{"type": "object"},
+				"address": map[string]interface{}{
+					"type":       []string{"object", "null"},
+					"properties": address["properties"],
+				},
+				"addresses": map[string]interface{}{
+					"type":  "array",
+					"items": address,
+				},
+				"Untagged": map[string]interface{}{"type": "string"},
+			},
+		}, SchemaFromStruct(schemaTestEntity{internal: "unused"}))
+	})
+
+	t.Run("accepts any value for nil", func(t *testing.T) {
+		assert.Equal(t, map[string]interface{}{}, SchemaFromStruct(nil))
+	})
+}
@@ -0,0 +1,74 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
+)
+
+// MerchantAccountParameter is the name of the parameter, accepted by every
+// tool of a server with partner credentials, that selects the sub-merchant
+// account a call is made on behalf of
+const MerchantAccountParameter = "merchant_account_id"
+
+// partnerOption is the option value that enables partner auth
+type partnerOption bool
+
+// WithPartnerAuth returns a server option that adds the
+// merchant_account_id parameter to every tool, so that partners can make
+// calls on behalf of their sub-merchants. The account is recorded in the
+// context of calls, overriding the one the context already carries, such
+// as from a header of the request.
+func WithPartnerAuth(enabled bool) ServerOption {
+	return func(s OptionSetter) error {
+		return s.SetOption(partnerOption(enabled))
+	}
+}
+
+// withPartnerAuth wraps the handler of a tool so that calls record the
+// sub-merchant account they select, and declares the merchant_account_id
+// parameter. Results are cached and replayed for each account separately.
+func (s *Mark3labsImpl) withPartnerAuth(
+	serverTool server.ServerTool,
+) server.ServerTool {
+	if !s.partner {
+		return serverTool
+	}
+
+	serverTool.Tool.InputSchema.Properties = withProperty(
+		serverTool.Tool.InputSchema.Properties, MerchantAccountParameter,
+		map[string]any{
+			"type": "string",
+			"description": "Optional: ID of the sub-merchant account to " +
+				"make the call on behalf of, such as acc_Ef7ArAsdU5t0XL",
+		})
+
+	handler := serverTool.Handler
+	serverTool.Handler = func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		account, _ := req.GetArguments()[MerchantAccountParameter].(string)
+		if account == "" {
+			account = contextkey.MerchantAccountFromContext(ctx)
+		}
+		if account == "" {
+			return handler(ctx, req)
+		}
+		if !IsID(account, AccountIDPrefix) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid %s: %s",
+				MerchantAccountParameter, account)), nil
+		}
+
+		ctx = contextkey.WithMerchantAccount(ctx, account)
+		ctx = contextkey.WithCacheScope(ctx,
+			contextkey.CacheScopeFromContext(ctx)+"\x00"+account)
+		return handler(ctx, req)
+	}
+
+	return serverTool
+}
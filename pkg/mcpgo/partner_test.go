@@ -0,0 +1,88 @@
+package mcpgo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
+)
+
+func TestWithPartnerAuth(t *testing.T) {
+	// call calls a tool that returns the merchant account and cache scope
+	// of its context, and returns the properties of its input schema too
+	call := func(
+		ctx context.Context,
+		partner bool,
+		args map[string]interface{},
+	) (*mcp.CallToolResult, map[string]any) {
+		tool := NewTool("fetch_account", "Returns the account", nil,
+			func(ctx context.Context, r CallToolRequest) (*ToolResult, error) {
+				return NewToolResultText(
+					contextkey.MerchantAccountFromContext(ctx) + "|" +
+						contextkey.CacheScopeFromContext(ctx)), nil
+			})
+		tool.SetReadOnly(true)
+		srv := NewMcpServer("test-server", "1.0.0", WithPartnerAuth(partner))
+		srv.AddTools(tool)
+
+		serverTool := srv.McpServer.GetTool("fetch_account")
+		require.NotNil(t, serverTool)
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := serverTool.Handler(ctx, request)
+		require.NoError(t, err)
+		return result, serverTool.Tool.InputSchema.Properties
+	}
+
+	// text returns the text of a result
+	text := func(result *mcp.CallToolResult) string {
+		content, ok := mcp.AsTextContent(result.Content[0])
+		require.True(t, ok)
+		return content.Text
+	}
+
+	t.Run("leaves tools as they are by default", func(t *testing.T) {
+		result, properties := call(context.Background(), false,
+			map[string]interface{}{
+				MerchantAccountParameter: "acc_Ef7ArAsdU5t0XL",
+			})
+		assert.NotContains(t, properties, MerchantAccountParameter)
+		assert.Equal(t, "|", text(result))
+	})
+
+	t.Run("records the selected account", func(t *testing.T) {
+		ctx := contextkey.WithCacheScope(context.Background(), "scope")
+		result, properties := call(ctx, true, map[string]interface{}{
+			MerchantAccountParameter: "acc_Ef7ArAsdU5t0XL",
+		})
+		assert.Contains(t, properties, MerchantAccountParameter)
+		assert.Equal(t, "acc_Ef7ArAsdU5t0XL|scope\x00acc_Ef7ArAsdU5t0XL",
+			text(result))
+	})
+
+	t.Run("keeps the account of the context", func(t *testing.T) {
+		ctx := contextkey.WithMerchantAccount(context.Background(),
+			"acc_Ef7ArAsdU5t0XL")
+
+		result, _ := call(ctx, true, map[string]interface{}{})
+		assert.Equal(t, "acc_Ef7ArAsdU5t0XL|\x00acc_Ef7ArAsdU5t0XL",
+			text(result))
+
+		result, _ = call(ctx, true, map[string]interface{}{
+			MerchantAccountParameter: "acc_9A33XWu170gUtm",
+		})
+		assert.Equal(t, "acc_9A33XWu170gUtm|\x00acc_9A33XWu170gUtm",
+			text(result))
+	})
+
+	t.Run("rejects invalid account IDs", func(t *testing.T) {
+		result, _ := call(context.Background(), true,
+			map[string]interface{}{MerchantAccountParameter: "pay_1"})
+		assert.True(t, result.IsError)
+		assert.Equal(t, "invalid merchant_account_id: pay_1", text(result))
+	})
+}
@@ -0,0 +1,73 @@
+package mcpgo
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// PolicyViolationErrorCode identifies the error returned for calls to
+// write tools that violate the policy of the server
+const PolicyViolationErrorCode = "POLICY_VIOLATION"
+
+// PolicyFunc checks a call to a write tool against a policy. It returns
+// the description of the violation, or an empty string if the call
+// complies.
+type PolicyFunc func(toolName string, args map[string]interface{}) string
+
+// policyOption is the option value that holds the policy of write tools
+type policyOption struct {
+	policy PolicyFunc
+}
+
+// WithPolicy returns a server option that checks every call to a write
+// tool against the policy before it runs, and rejects the calls that
+// violate it
+func WithPolicy(policy PolicyFunc) ServerOption {
+	return func(s OptionSetter) error {
+		return s.SetOption(policyOption{policy: policy})
+	}
+}
+
+// enforcePolicy wraps the handler of a write tool so that it rejects calls
+// that violate the policy of the server
+func (s *Mark3labsImpl) enforcePolicy(
+	serverTool server.ServerTool,
+) server.ServerTool {
+	if s.policy == nil || isReadOnlyTool(serverTool.Tool) {
+		return serverTool
+	}
+
+	handler := serverTool.Handler
+	serverTool.Handler = func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		violation := s.policy(req.Params.Name, req.GetArguments())
+		if violation != "" {
+			return policyViolation(req.Params.Name, violation), nil
+		}
+		return handler(ctx, req)
+	}
+
+	return serverTool
+}
+
+// policyViolation returns the error result of a write tool called in
+// violation of the policy of the server
+func policyViolation(toolName string, violation string) *mcp.CallToolResult {
+	description := "tool " + toolName + " violates the server policy: " +
+		violation
+
+	result := mcp.NewToolResultStructured(map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":        PolicyViolationErrorCode,
+			"tool":        toolName,
+			"description": description,
+		},
+	}, description)
+	result.IsError = true
+
+	return result
+}
@@ -0,0 +1,49 @@
+package mcpgo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy(t *testing.T) {
+	// the policy allows amounts up to 100
+	policy := func(toolName string, args map[string]interface{}) string {
+		if amount, _ := args["amount"].(float64); amount > 100 {
+			return "amount exceeds 100"
+		}
+		return ""
+	}
+
+	t.Run("runs calls that comply", func(t *testing.T) {
+		srv := newReadOnlyTestServer(WithPolicy(policy))
+
+		result := callToolWithArgs(t, srv, "create_thing", `{"amount":100}`)
+		assert.False(t, result.IsError)
+	})
+
+	t.Run("rejects write calls that violate the policy", func(t *testing.T) {
+		srv := newReadOnlyTestServer(WithPolicy(policy))
+
+		result := callToolWithArgs(t, srv, "create_thing", `{"amount":500}`)
+		assert.True(t, result.IsError)
+		assert.Equal(t, map[string]interface{}{
+			"error": map[string]interface{}{
+				"code": PolicyViolationErrorCode,
+				"tool": "create_thing",
+				"description": "tool create_thing violates the server " +
+					"policy: amount exceeds 100",
+			},
+		}, result.StructuredContent)
+
+		result = callToolWithArgs(t, srv, "update_thing", `{"amount":500}`)
+		assert.True(t, result.IsError)
+	})
+
+	t.Run("leaves read tools unchecked", func(t *testing.T) {
+		srv := newReadOnlyTestServer(WithPolicy(policy))
+
+		result := callToolWithArgs(t, srv, "fetch_thing", `{"amount":500}`)
+		assert.False(t, result.IsError)
+	})
+}
@@ -0,0 +1,115 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// PromptHandler renders a prompt from its arguments into the text of the
+// user message that starts the workflow
+type PromptHandler func(
+	ctx context.Context,
+	arguments map[string]string) (string, error)
+
+// PromptArgument describes an argument a prompt can be filled in with
+type PromptArgument struct {
+	Name        string
+	Description string
+	Required    bool
+}
+
+// Prompt represents a reusable prompt template that can be added to the
+// server
+type Prompt interface {
+	// internal method to convert to mcp's ServerPrompt
+	toMCPServerPrompt() server.ServerPrompt
+
+	// GetName returns the name the prompt is requested with
+	GetName() string
+
+	// GetHandler returns the handler that renders the prompt
+	GetHandler() PromptHandler
+}
+
+// mark3labsPromptImpl implements the Prompt interface
+type mark3labsPromptImpl struct {
+	name        string
+	description string
+	arguments   []PromptArgument
+	handler     PromptHandler
+}
+
+// NewPrompt creates a new prompt with the given name, description,
+// arguments and handler
+func NewPrompt(
+	name,
+	description string,
+	arguments []PromptArgument,
+	handler PromptHandler) *mark3labsPromptImpl {
+	return &mark3labsPromptImpl{
+		name:        name,
+		description: description,
+		arguments:   arguments,
+		handler:     handler,
+	}
+}
+
+// GetName returns the name of the prompt
+func (p *mark3labsPromptImpl) GetName() string {
+	return p.name
+}
+
+// GetHandler returns the handler for the prompt
+func (p *mark3labsPromptImpl) GetHandler() PromptHandler {
+	return p.handler
+}
+
+// toMCPServerPrompt converts our prompt to mcp's ServerPrompt
+func (p *mark3labsPromptImpl) toMCPServerPrompt() server.ServerPrompt {
+	promptOpts := []mcp.PromptOption{
+		mcp.WithPromptDescription(p.description),
+	}
+	for _, arg := range p.arguments {
+		argOpts := []mcp.ArgumentOption{
+			mcp.ArgumentDescription(arg.Description),
+		}
+		if arg.Required {
+			argOpts = append(argOpts, mcp.RequiredArgument())
+		}
+		promptOpts = append(promptOpts, mcp.WithArgument(arg.Name, argOpts...))
+	}
+
+	handler := func(
+		ctx context.Context,
+		req mcp.GetPromptRequest,
+	) (*mcp.GetPromptResult, error) {
+		arguments := req.Params.Arguments
+		if arguments == nil {
+			arguments = map[string]string{}
+		}
+
+		// Required arguments are not checked by mcp-go
+		for _, arg := range p.arguments {
+			if arg.Required && arguments[arg.Name] == "" {
+				return nil, fmt.Errorf("missing required argument: %s", arg.Name)
+			}
+		}
+
+		text, err := p.handler(ctx, arguments)
+		if err != nil {
+			return nil, err
+		}
+
+		return mcp.NewGetPromptResult(p.description, []mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text)),
+		}), nil
+	}
+
+	return server.ServerPrompt{
+		Prompt:  mcp.NewPrompt(p.name, promptOpts...),
+		Handler: handler,
+	}
+}
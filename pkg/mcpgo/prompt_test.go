@@ -0,0 +1,99 @@
+package mcpgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestPrompt creates a prompt that greets the name argument
+func newTestPrompt() *mark3labsPromptImpl {
+	return NewPrompt(
+		"greet",
+		"Greets someone",
+		[]PromptArgument{
+			{Name: "name", Description: "Who to greet", Required: true},
+			{Name: "greeting", Description: "Greeting to use"},
+		},
+		func(ctx context.Context, arguments map[string]string) (string, error) {
+			if arguments["name"] == "nobody" {
+				return "", errors.New("nobody to greet")
+			}
+			return "Say hello to " + arguments["name"], nil
+		},
+	)
+}
+
+// getPrompt renders the prompt with the given arguments
+func getPrompt(
+	prompt *mark3labsPromptImpl,
+	arguments map[string]string,
+) (*mcp.GetPromptResult, error) {
+	req := mcp.GetPromptRequest{}
+	req.Params.Name = prompt.GetName()
+	req.Params.Arguments = arguments
+
+	return prompt.toMCPServerPrompt().Handler(context.Background(), req)
+}
+
+func TestNewPrompt(t *testing.T) {
+	t.Run("converts prompt with arguments", func(t *testing.T) {
+		prompt := newTestPrompt()
+		assert.Equal(t, "greet", prompt.GetName())
+
+		mcpPrompt := prompt.toMCPServerPrompt().Prompt
+		assert.Equal(t, "greet", mcpPrompt.Name)
+		assert.Equal(t, "Greets someone", mcpPrompt.Description)
+		assert.Equal(t, []mcp.PromptArgument{
+			{Name: "name", Description: "Who to greet", Required: true},
+			{Name: "greeting", Description: "Greeting to use"},
+		}, mcpPrompt.Arguments)
+	})
+
+	t.Run("renders user message", func(t *testing.T) {
+		result, err := getPrompt(newTestPrompt(),
+			map[string]string{"name": "Asha"})
+		require.NoError(t, err)
+
+		assert.Equal(t, "Greets someone", result.Description)
+		require.Len(t, result.Messages, 1)
+		assert.Equal(t, mcp.RoleUser, result.Messages[0].Role)
+		assert.Equal(t, mcp.NewTextContent("Say hello to Asha"),
+			result.Messages[0].Content)
+	})
+
+	t.Run("rejects missing required argument", func(t *testing.T) {
+		_, err := getPrompt(newTestPrompt(), nil)
+		assert.EqualError(t, err, "missing required argument: name")
+	})
+
+	t.Run("returns handler error", func(t *testing.T) {
+		_, err := getPrompt(newTestPrompt(),
+			map[string]string{"name": "nobody"})
+		assert.EqualError(t, err, "nobody to greet")
+	})
+}
+
+func TestMark3labsImpl_AddPrompts(t *testing.T) {
+	t.Run("adds prompts", func(t *testing.T) {
+		srv := NewMcpServer("test-server", "1.0.0",
+			WithPromptCapabilities(true))
+		srv.AddPrompts(newTestPrompt())
+
+		response := srv.McpServer.HandleMessage(context.Background(),
+			json.RawMessage(
+				`{"jsonrpc":"2.0","id":1,"method":"prompts/list"}`))
+		resp, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok, "unexpected response %v", response)
+		result, ok := resp.Result.(mcp.ListPromptsResult)
+		require.True(t, ok)
+
+		require.Len(t, result.Prompts, 1)
+		assert.Equal(t, "greet", result.Prompts[0].Name)
+	})
+}
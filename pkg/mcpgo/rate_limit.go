@@ -0,0 +1,262 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
+)
+
+// RateLimitedErrorCode identifies the error returned for tool calls that
+// exceed a rate limit
+const RateLimitedErrorCode = "RATE_LIMITED"
+
+// maxRateLimitedSessions bounds the number of session buckets, and of key
+// buckets, kept before idle ones are dropped
+const maxRateLimitedSessions = 10000
+
+// RateLimit is a token bucket limit: tool calls are allowed at Rate calls
+// per second on average, in bursts of up to Burst calls. A zero Rate
+// disables the limit.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// enabled reports whether the limit restricts calls
+func (l RateLimit) enabled() bool {
+	return l.Rate > 0
+}
+
+// burst returns the size of the bucket, at least one call
+func (l RateLimit) burst() float64 {
+	return math.Max(float64(l.Burst), 1)
+}
+
+// sessionRateLimitOption is the option value that limits tool calls per
+// session
+type sessionRateLimitOption RateLimit
+
+// keyRateLimitOption is the option value that limits tool calls per API
+// key
+type keyRateLimitOption RateLimit
+
+// globalRateLimitOption is the option value that limits tool calls across
+// all sessions
+type globalRateLimitOption RateLimit
+
+// WithSessionRateLimit returns a server option that limits the rate of
+// tool calls of each session
+func WithSessionRateLimit(limit RateLimit) ServerOption {
+	return func(s OptionSetter) error {
+		return s.SetOption(sessionRateLimitOption(limit))
+	}
+}
+
+// WithKeyRateLimit returns a server option that limits the rate of tool
+// calls made with each API key, across the sessions that use it. Calls
+// are keyed by their cache scope, so calls with the credentials of the
+// server share a bucket.
+func WithKeyRateLimit(limit RateLimit) ServerOption {
+	return func(s OptionSetter) error {
+		return s.SetOption(keyRateLimitOption(limit))
+	}
+}
+
+// WithGlobalRateLimit returns a server option that limits the rate of tool
+// calls of all sessions together
+func WithGlobalRateLimit(limit RateLimit) ServerOption {
+	return func(s OptionSetter) error {
+		return s.SetOption(globalRateLimitOption(limit))
+	}
+}
+
+// tokenBucket holds the calls a bucket allows as of last
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// refill adds the tokens earned since the last refill
+func (b *tokenBucket) refill(limit RateLimit, now time.Time) {
+	elapsed := now.Sub(b.last).Seconds()
+	b.tokens = math.Min(b.tokens+elapsed*limit.Rate, limit.burst())
+	b.last = now
+}
+
+// wait returns how long until the bucket allows a call
+func (b *tokenBucket) wait(limit RateLimit) time.Duration {
+	if b.tokens >= 1 {
+		return 0
+	}
+	seconds := (1 - b.tokens) / limit.Rate
+	return time.Duration(math.Ceil(seconds * float64(time.Second)))
+}
+
+// rateLimiter limits tool calls with a bucket per session, a bucket per
+// API key and a bucket shared by all sessions
+type rateLimiter struct {
+	session RateLimit
+	key     RateLimit
+	global  RateLimit
+	// now returns the current time, and is replaced in tests
+	now func() time.Time
+
+	mu       sync.Mutex
+	sessions map[string]*tokenBucket
+	keys     map[string]*tokenBucket
+	all      *tokenBucket
+}
+
+// newRateLimiter creates a rate limiter, or returns nil if no limit is
+// enabled
+func newRateLimiter(session, key, global RateLimit) *rateLimiter {
+	if !session.enabled() && !key.enabled() && !global.enabled() {
+		return nil
+	}
+	return &rateLimiter{
+		session:  session,
+		key:      key,
+		global:   global,
+		now:      time.Now,
+		sessions: make(map[string]*tokenBucket),
+		keys:     make(map[string]*tokenBucket),
+	}
+}
+
+// allow takes a call from the buckets of the session, of the API key and
+// of all sessions. If any is empty, no call is taken and allow returns the
+// limit that was hit and how long until it allows the call.
+func (l *rateLimiter) allow(
+	sessionID string,
+	key string,
+) (allowed bool, scope string, retryAfter time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+
+	if l.global.enabled() {
+		if l.all == nil {
+			l.all = &tokenBucket{tokens: l.global.burst(), last: now}
+		}
+		l.all.refill(l.global, now)
+		if wait := l.all.wait(l.global); wait > 0 {
+			return false, "global", wait
+		}
+	}
+
+	var keyBucket *tokenBucket
+	if l.key.enabled() {
+		keyBucket = l.bucket(l.keys, key, l.key, now)
+		keyBucket.refill(l.key, now)
+		if wait := keyBucket.wait(l.key); wait > 0 {
+			return false, "key", wait
+		}
+	}
+
+	var bucket *tokenBucket
+	if l.session.enabled() {
+		bucket = l.bucket(l.sessions, sessionID, l.session, now)
+		bucket.refill(l.session, now)
+		if wait := bucket.wait(l.session); wait > 0 {
+			return false, "session", wait
+		}
+	}
+
+	if l.all != nil {
+		l.all.tokens--
+	}
+	if keyBucket != nil {
+		keyBucket.tokens--
+	}
+	if bucket != nil {
+		bucket.tokens--
+	}
+	return true, "", 0
+}
+
+// bucket returns the bucket of a session or key, creating a full one for a
+// new one. Buckets that have refilled are dropped when there are too
+// many, since a new bucket would be the same. The caller must hold l.mu.
+func (l *rateLimiter) bucket(
+	buckets map[string]*tokenBucket,
+	id string,
+	limit RateLimit,
+	now time.Time,
+) *tokenBucket {
+	if bucket, ok := buckets[id]; ok {
+		return bucket
+	}
+
+	if len(buckets) >= maxRateLimitedSessions {
+		for id, bucket := range buckets {
+			bucket.refill(limit, now)
+			if bucket.tokens >= limit.burst() {
+				delete(buckets, id)
+			}
+		}
+	}
+
+	bucket := &tokenBucket{tokens: limit.burst(), last: now}
+	buckets[id] = bucket
+	return bucket
+}
+
+// withRateLimit wraps the handler of a tool so that it rejects calls over
+// the rate limits
+func (l *rateLimiter) withRateLimit(
+	serverTool server.ServerTool,
+) server.ServerTool {
+	handler := serverTool.Handler
+	serverTool.Handler = func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		var sessionID string
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			sessionID = session.SessionID()
+		}
+
+		allowed, scope, retryAfter := l.allow(sessionID,
+			contextkey.CacheScopeFromContext(ctx))
+		if !allowed {
+			return rateLimitExceeded(req.Params.Name, scope, retryAfter), nil
+		}
+		return handler(ctx, req)
+	}
+
+	return serverTool
+}
+
+// rateLimitExceeded returns the error result of a tool call over a rate
+// limit
+func rateLimitExceeded(
+	toolName string,
+	scope string,
+	retryAfter time.Duration,
+) *mcp.CallToolResult {
+	retryAfterSeconds := math.Ceil(retryAfter.Seconds())
+	description := fmt.Sprintf(
+		"%s rate limit exceeded, retry %s in %.0fs",
+		scope, toolName, retryAfterSeconds)
+
+	result := mcp.NewToolResultStructured(map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":                RateLimitedErrorCode,
+			"tool":                toolName,
+			"scope":               scope,
+			"retry_after_seconds": retryAfterSeconds,
+			"description":         description,
+		},
+	}, description)
+	result.IsError = true
+
+	return result
+}
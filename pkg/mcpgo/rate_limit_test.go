@@ -0,0 +1,173 @@
+package mcpgo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
+)
+
+// newRateLimitTestServer creates a server with a read and a write tool that
+// limits tool calls with the given limits
+func newRateLimitTestServer(
+	session, global RateLimit,
+) (*Mark3labsImpl, *time.Time) {
+	srv := newReadOnlyTestServer(
+		WithSessionRateLimit(session), WithGlobalRateLimit(global))
+
+	clock := time.Now()
+	if srv.limiter != nil {
+		srv.limiter.now = func() time.Time { return clock }
+	}
+	return srv, &clock
+}
+
+// sessionContext returns a context carrying a client session with the ID
+func sessionContext(srv *Mark3labsImpl, sessionID string) context.Context {
+	return srv.McpServer.WithContext(context.Background(),
+		server.NewInProcessSession(sessionID, nil))
+}
+
+func TestRateLimit(t *testing.T) {
+	t.Run("limits calls of a session", func(t *testing.T) {
+		srv, _ := newRateLimitTestServer(RateLimit{Rate: 1, Burst: 2},
+			RateLimit{})
+		ctx := sessionContext(srv, "session-a")
+
+		assert.False(t, callTool(t, ctx, srv, "fetch_thing").IsError)
+		assert.False(t, callTool(t, ctx, srv, "create_thing").IsError)
+		result := callTool(t, ctx, srv, "fetch_thing")
+
+		require.True(t, result.IsError)
+		structured, err := json.Marshal(result.StructuredContent)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"error":{
+			"code":"RATE_LIMITED",
+			"tool":"fetch_thing",
+			"scope":"session",
+			"retry_after_seconds":1,
+			"description":"session rate limit exceeded, retry fetch_thing in 1s"
+		}}`, string(structured))
+	})
+
+	t.Run("limits sessions separately", func(t *testing.T) {
+		srv, _ := newRateLimitTestServer(RateLimit{Rate: 1, Burst: 1},
+			RateLimit{})
+		sessionA := sessionContext(srv, "session-a")
+		sessionB := sessionContext(srv, "session-b")
+
+		assert.False(t, callTool(t, sessionA, srv, "fetch_thing").IsError)
+		assert.False(t, callTool(t, sessionB, srv, "fetch_thing").IsError)
+		assert.True(t, callTool(t, sessionA, srv, "fetch_thing").IsError)
+	})
+
+	t.Run("refills over time", func(t *testing.T) {
+		srv, clock := newRateLimitTestServer(RateLimit{Rate: 2, Burst: 1},
+			RateLimit{})
+		ctx := sessionContext(srv, "session-a")
+
+		assert.False(t, callTool(t, ctx, srv, "fetch_thing").IsError)
+		assert.True(t, callTool(t, ctx, srv, "fetch_thing").IsError)
+		*clock = clock.Add(500 * time.Millisecond)
+		assert.False(t, callTool(t, ctx, srv, "fetch_thing").IsError)
+	})
+
+	t.Run("limits calls across sessions", func(t *testing.T) {
+		srv, _ := newRateLimitTestServer(RateLimit{Rate: 10, Burst: 10},
+			RateLimit{Rate: 1, Burst: 2})
+
+		assert.False(t, callTool(t, sessionContext(srv, "session-a"), srv,
+			"fetch_thing").IsError)
+		assert.False(t, callTool(t, sessionContext(srv, "session-b"), srv,
+			"fetch_thing").IsError)
+		result := callTool(t, sessionContext(srv, "session-c"), srv,
+			"fetch_thing")
+
+		require.True(t, result.IsError)
+		text, ok := result.Content[0].(mcp.TextContent)
+		require.True(t, ok)
+		assert.Equal(t,
+			"global rate limit exceeded, retry fetch_thing in 1s", text.Text)
+	})
+
+	t.Run("limits calls of a key across sessions", func(t *testing.T) {
+		srv := newReadOnlyTestServer(WithKeyRateLimit(
+			RateLimit{Rate: 1, Burst: 1}))
+		keyContext := func(sessionID, scope string) context.Context {
+			return contextkey.WithCacheScope(
+				sessionContext(srv, sessionID), scope)
+		}
+
+		assert.False(t, callTool(t, keyContext("session-a", "key-a"), srv,
+			"fetch_thing").IsError)
+		assert.False(t, callTool(t, keyContext("session-b", "key-b"), srv,
+			"fetch_thing").IsError)
+		result := callTool(t, keyContext("session-c", "key-a"), srv,
+			"fetch_thing")
+
+		require.True(t, result.IsError)
+		assert.Equal(t, "key rate limit exceeded, retry fetch_thing in 1s",
+			resultText(result))
+	})
+
+	t.Run("does not limit without rates", func(t *testing.T) {
+		srv, _ := newRateLimitTestServer(RateLimit{Burst: 1}, RateLimit{})
+		ctx := sessionContext(srv, "session-a")
+
+		assert.Nil(t, srv.limiter)
+		for i := 0; i < 5; i++ {
+			assert.False(t, callTool(t, ctx, srv, "fetch_thing").IsError)
+		}
+	})
+}
+
+func TestRateLimiter_Allow(t *testing.T) {
+	t.Run("rejected calls do not use the global bucket", func(t *testing.T) {
+		limiter := newRateLimiter(RateLimit{Rate: 1, Burst: 1}, RateLimit{},
+			RateLimit{Rate: 1, Burst: 2})
+
+		allowed, _, _ := limiter.allow("session-a", "")
+		assert.True(t, allowed)
+		allowed, scope, _ := limiter.allow("session-a", "")
+		assert.False(t, allowed)
+		assert.Equal(t, "session", scope)
+		allowed, _, _ = limiter.allow("session-b", "")
+		assert.True(t, allowed)
+	})
+
+	t.Run("waits until a call is allowed", func(t *testing.T) {
+		limiter := newRateLimiter(RateLimit{Rate: 4, Burst: 1}, RateLimit{},
+			RateLimit{})
+		clock := time.Now()
+		limiter.now = func() time.Time { return clock }
+
+		limiter.allow("session-a", "")
+		clock = clock.Add(100 * time.Millisecond)
+		_, _, retryAfter := limiter.allow("session-a", "")
+
+		assert.Equal(t, 150*time.Millisecond, retryAfter)
+	})
+
+	t.Run("drops refilled sessions when full", func(t *testing.T) {
+		limiter := newRateLimiter(RateLimit{Rate: 1, Burst: 1}, RateLimit{},
+			RateLimit{})
+		clock := time.Now()
+		limiter.now = func() time.Time { return clock }
+
+		for i := 0; i < maxRateLimitedSessions; i++ {
+			limiter.allow(fmt.Sprint(i), "")
+		}
+		clock = clock.Add(time.Second)
+		limiter.allow("new-session", "")
+
+		assert.Len(t, limiter.sessions, 1)
+	})
+}
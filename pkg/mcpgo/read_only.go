@@ -0,0 +1,100 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
+)
+
+// ReadOnlyErrorCode identifies the error returned for calls to write tools
+// in read-only mode
+const ReadOnlyErrorCode = "READ_ONLY_MODE"
+
+// readOnlyOption is the option value that enables read-only mode
+type readOnlyOption bool
+
+// WithReadOnly returns a server option that rejects calls to write tools.
+// Read-only mode can also be enabled for a single request or session by
+// marking its context with contextkey.WithReadOnly.
+func WithReadOnly(readOnly bool) ServerOption {
+	return func(s OptionSetter) error {
+		return s.SetOption(readOnlyOption(readOnly))
+	}
+}
+
+// isReadOnlyTool reports whether a tool is tagged as read-only. Untagged
+// tools are treated as write tools.
+func isReadOnlyTool(tool mcp.Tool) bool {
+	hint := tool.Annotations.ReadOnlyHint
+	return hint != nil && *hint
+}
+
+// readOnlyEnforced reports whether write tools are disabled for a call
+func (s *Mark3labsImpl) readOnlyEnforced(ctx context.Context) bool {
+	return s.readOnly || contextkey.ReadOnlyFromContext(ctx)
+}
+
+// enforceReadOnly wraps the handler of a write tool so that it rejects
+// calls in read-only mode, whichever way the tool was registered
+func (s *Mark3labsImpl) enforceReadOnly(
+	serverTool server.ServerTool,
+) server.ServerTool {
+	if isReadOnlyTool(serverTool.Tool) {
+		return serverTool
+	}
+
+	handler := serverTool.Handler
+	serverTool.Handler = func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		if s.readOnlyEnforced(ctx) {
+			return readOnlyViolation(req.Params.Name), nil
+		}
+		return handler(ctx, req)
+	}
+
+	return serverTool
+}
+
+// filterWriteTools hides write tools from tool listings in read-only mode
+func (s *Mark3labsImpl) filterWriteTools(
+	ctx context.Context,
+	tools []mcp.Tool,
+) []mcp.Tool {
+	if !s.readOnlyEnforced(ctx) {
+		return tools
+	}
+
+	readTools := make([]mcp.Tool, 0, len(tools))
+	for _, tool := range tools {
+		if isReadOnlyTool(tool) {
+			readTools = append(readTools, tool)
+		}
+	}
+
+	return readTools
+}
+
+// readOnlyViolation returns the error result of a write tool called in
+// read-only mode
+func readOnlyViolation(toolName string) *mcp.CallToolResult {
+	description := fmt.Sprintf(
+		"tool %s modifies data and cannot be called in read-only mode",
+		toolName)
+
+	result := mcp.NewToolResultStructured(map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":        ReadOnlyErrorCode,
+			"tool":        toolName,
+			"description": description,
+		},
+	}, description)
+	result.IsError = true
+
+	return result
+}
@@ -0,0 +1,133 @@
+package mcpgo
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
+)
+
+// newReadOnlyTestServer creates a server with a read tool, a write tool and
+// a tool that was never tagged
+func newReadOnlyTestServer(opts ...ServerOption) *Mark3labsImpl {
+	handler := func(
+		ctx context.Context,
+		r CallToolRequest,
+	) (*ToolResult, error) {
+		return NewToolResultText("done"), nil
+	}
+
+	readTool := NewTool("fetch_thing", "Fetches a thing", nil, handler)
+	readTool.SetReadOnly(true)
+	writeTool := NewTool("create_thing", "Creates a thing", nil, handler)
+	writeTool.SetReadOnly(false)
+	untaggedTool := NewTool("update_thing", "Updates a thing", nil, handler)
+
+	srv := NewMcpServer("test-server", "1.0.0",
+		append([]ServerOption{WithToolCapabilities(true)}, opts...)...)
+	srv.AddTools(readTool, writeTool, untaggedTool)
+
+	return srv
+}
+
+// listTools returns the names of the tools listed by the server
+func listTools(
+	t *testing.T,
+	ctx context.Context,
+	srv *Mark3labsImpl,
+) []string {
+	t.Helper()
+
+	response := srv.McpServer.HandleMessage(ctx, json.RawMessage(
+		`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+	resp, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok, "unexpected response %v", response)
+	result, ok := resp.Result.(mcp.ListToolsResult)
+	require.True(t, ok)
+
+	names := make([]string, 0, len(result.Tools))
+	for _, tool := range result.Tools {
+		names = append(names, tool.Name)
+	}
+	return names
+}
+
+// callTool calls a tool on the server and returns its result
+func callTool(
+	t *testing.T,
+	ctx context.Context,
+	srv *Mark3labsImpl,
+	name string,
+) *mcp.CallToolResult {
+	t.Helper()
+
+	response := srv.McpServer.HandleMessage(ctx, json.RawMessage(
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call",`+
+			`"params":{"name":"`+name+`","arguments":{}}}`))
+	resp, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok, "unexpected response %v", response)
+	result, ok := resp.Result.(mcp.CallToolResult)
+	require.True(t, ok)
+
+	return &result
+}
+
+func TestReadOnlyPolicy(t *testing.T) {
+	t.Run("allows every tool by default", func(t *testing.T) {
+		srv := newReadOnlyTestServer()
+		ctx := context.Background()
+
+		assert.ElementsMatch(t,
+			[]string{"fetch_thing", "create_thing", "update_thing"},
+			listTools(t, ctx, srv))
+		assert.False(t, callTool(t, ctx, srv, "create_thing").IsError)
+	})
+
+	t.Run("rejects write tools in read-only mode", func(t *testing.T) {
+		srv := newReadOnlyTestServer(WithReadOnly(true))
+		ctx := context.Background()
+
+		assert.Equal(t, []string{"fetch_thing"}, listTools(t, ctx, srv))
+		assert.False(t, callTool(t, ctx, srv, "fetch_thing").IsError)
+
+		result := callTool(t, ctx, srv, "create_thing")
+		assert.True(t, result.IsError)
+		assert.Equal(t, map[string]interface{}{
+			"error": map[string]interface{}{
+				"code": ReadOnlyErrorCode,
+				"tool": "create_thing",
+				"description": "tool create_thing modifies data and " +
+					"cannot be called in read-only mode",
+			},
+		}, result.StructuredContent)
+	})
+
+	t.Run("treats untagged tools as write tools", func(t *testing.T) {
+		srv := newReadOnlyTestServer(WithReadOnly(true))
+
+		result := callTool(t, context.Background(), srv, "update_thing")
+		assert.True(t, result.IsError)
+	})
+
+	t.Run("enables read-only mode per request", func(t *testing.T) {
+		srv := newReadOnlyTestServer()
+		ctx := contextkey.WithReadOnly(context.Background(), true)
+
+		assert.Equal(t, []string{"fetch_thing"}, listTools(t, ctx, srv))
+		assert.True(t, callTool(t, ctx, srv, "create_thing").IsError)
+		assert.False(t,
+			callTool(t, context.Background(), srv, "create_thing").IsError)
+	})
+
+	t.Run("requests cannot lift server read-only mode", func(t *testing.T) {
+		srv := newReadOnlyTestServer(WithReadOnly(true))
+		ctx := contextkey.WithReadOnly(context.Background(), false)
+
+		assert.True(t, callTool(t, ctx, srv, "create_thing").IsError)
+	})
+}
@@ -0,0 +1,272 @@
+package mcpgo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/redis"
+)
+
+// Prefixes of the Redis keys of the server
+const (
+	redisCachePrefix        = "razorpay-mcp:cache:"
+	redisContinuationPrefix = "razorpay-mcp:continuation:"
+	redisIdempotencyPrefix  = "razorpay-mcp:idempotency:"
+	redisSessionPrefix      = "razorpay-mcp:session:"
+)
+
+// redisCacheOption is the option value that keeps the result cache in
+// Redis
+type redisCacheOption struct {
+	client *redis.Client
+}
+
+// WithRedisCache returns a server option that keeps the result cache, and
+// the rests of truncated results, in Redis, so that replicas of the server
+// share them. The cache is still enabled by WithCacheTTL, and truncation
+// by WithMaxResultSize.
+func WithRedisCache(client *redis.Client) ServerOption {
+	return func(s OptionSetter) error {
+		return s.SetOption(redisCacheOption{client: client})
+	}
+}
+
+// WithRedisSessions keeps the sessions of the streamable HTTP transport in
+// Redis, so that a session opened on one replica of the server is valid on
+// every replica. Tool calls retried after a dropped connection are only
+// replayed by the replica that ran them.
+func WithRedisSessions(client *redis.Client) StreamableHTTPOption {
+	return func(c *StreamableHTTPConfig) {
+		c.sessionBackend = &redisSessions{client: client}
+	}
+}
+
+// redisKey returns the Redis key of a value, hashing the parts that can be
+// long or hold credentials
+func redisKey(prefix string, parts ...string) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return prefix + hex.EncodeToString(hash.Sum(nil))
+}
+
+// redisResults keeps the results of the result cache in Redis. Each scope
+// has a generation that is part of the keys of its results, so dropping
+// the results of a scope only takes incrementing its generation.
+type redisResults struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// generation returns the current generation of a scope
+func (r *redisResults) generation(
+	ctx context.Context,
+	scope string,
+) (string, error) {
+	generation, _, err := r.client.Get(ctx,
+		redisKey(redisCachePrefix+"generation:", scope))
+	return generation, err
+}
+
+// get returns the cached result for the key, if any
+func (r *redisResults) get(
+	ctx context.Context,
+	scope string,
+	key string,
+) (*mcp.CallToolResult, bool) {
+	generation, err := r.generation(ctx, scope)
+	if err != nil {
+		return nil, false
+	}
+	data, ok, err := r.client.Get(ctx,
+		redisKey(redisCachePrefix, scope, generation, key))
+	if err != nil || !ok {
+		return nil, false
+	}
+
+	var result mcp.CallToolResult
+	if err := json.Unmarshal([]byte(data), &result); err != nil {
+		return nil, false
+	}
+	return &result, true
+}
+
+// set caches a result for the key. Results that cannot be cached are
+// dropped, so that the call is run again.
+func (r *redisResults) set(
+	ctx context.Context,
+	scope string,
+	key string,
+	result *mcp.CallToolResult,
+) {
+	generation, err := r.generation(ctx, scope)
+	if err != nil {
+		return
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return
+	}
+	_ = r.client.Set(ctx, redisKey(redisCachePrefix, scope, generation, key),
+		string(data), r.ttl)
+}
+
+// invalidate drops the cached results of a scope
+func (r *redisResults) invalidate(ctx context.Context, scope string) {
+	_, _ = r.client.Incr(ctx,
+		redisKey(redisCachePrefix+"generation:", scope))
+}
+
+// redisContinuations keeps the rests of truncated results in Redis, so
+// that continue_result can be called on any replica of the server. Rests
+// are keyed by the cache scope of the call with their token, and expire
+// in Redis.
+type redisContinuations struct {
+	client *redis.Client
+}
+
+// redisContinuation is the rest of a truncated result as kept in Redis
+type redisContinuation struct {
+	Text       string                 `json:"text,omitempty"`
+	Collection map[string]interface{} `json:"collection"`
+	Items      []interface{}          `json:"items,omitempty"`
+	Offset     int                    `json:"offset"`
+	Total      int                    `json:"total"`
+	Expires    time.Time              `json:"expires"`
+}
+
+// save keeps the rest under the token. Rests that cannot be kept are
+// dropped, so that continue_result asks to call the tool again.
+func (r *redisContinuations) save(
+	ctx context.Context,
+	token string,
+	rest continuation,
+) {
+	data, err := json.Marshal(redisContinuation{
+		Text:       rest.text,
+		Collection: rest.collection,
+		Items:      rest.items,
+		Offset:     rest.offset,
+		Total:      rest.total,
+		Expires:    rest.expires,
+	})
+	if err != nil {
+		return
+	}
+	_ = r.client.Set(ctx, redisKey(redisContinuationPrefix, rest.scope,
+		token), string(data), continuationTTL)
+}
+
+// load returns the rest kept under the token for the scope, if any
+func (r *redisContinuations) load(
+	ctx context.Context,
+	scope string,
+	token string,
+) (continuation, bool) {
+	data, ok, err := r.client.Get(ctx,
+		redisKey(redisContinuationPrefix, scope, token))
+	if err != nil || !ok {
+		return continuation{}, false
+	}
+
+	var rest redisContinuation
+	if err := json.Unmarshal([]byte(data), &rest); err != nil {
+		return continuation{}, false
+	}
+	return continuation{
+		text:       rest.Text,
+		collection: rest.Collection,
+		items:      rest.Items,
+		offset:     rest.Offset,
+		total:      rest.Total,
+		scope:      scope,
+		expires:    rest.Expires,
+	}, true
+}
+
+// RedisIdempotencyStore keeps idempotency records in Redis, so that
+// replicas of the server share them. Records expire in Redis.
+type RedisIdempotencyStore struct {
+	client *redis.Client
+	// now returns the current time, and is replaced in tests
+	now func() time.Time
+}
+
+// NewRedisIdempotencyStore creates a store backed by the Redis client
+func NewRedisIdempotencyStore(client *redis.Client) *RedisIdempotencyStore {
+	return &RedisIdempotencyStore{client: client, now: time.Now}
+}
+
+// Load returns the record kept under the key, if any
+func (s *RedisIdempotencyStore) Load(
+	key string,
+) (IdempotencyRecord, bool, error) {
+	data, ok, err := s.client.Get(context.Background(),
+		redisKey(redisIdempotencyPrefix, key))
+	if err != nil || !ok {
+		return IdempotencyRecord{}, false, err
+	}
+
+	var record IdempotencyRecord
+	if err := json.Unmarshal([]byte(data), &record); err != nil {
+		return IdempotencyRecord{}, false, err
+	}
+	return record, true, nil
+}
+
+// Save keeps the record under the key until it expires
+func (s *RedisIdempotencyStore) Save(
+	key string,
+	record IdempotencyRecord,
+) error {
+	ttl := record.Expires.Sub(s.now())
+	if ttl <= 0 {
+		return nil
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(context.Background(),
+		redisKey(redisIdempotencyPrefix, key), string(data), ttl)
+}
+
+// redisSessions shares the sessions of the streamable HTTP transport
+// between replicas through Redis
+type redisSessions struct {
+	client *redis.Client
+}
+
+// create records a new session, expiring after ttl if it is positive
+func (r *redisSessions) create(sessionID string, ttl time.Duration) error {
+	return r.client.Set(context.Background(),
+		redisSessionPrefix+sessionID, strconv.FormatInt(time.Now().Unix(), 10),
+		ttl)
+}
+
+// touch reports whether a session exists, and extends its life by ttl if
+// it is positive
+func (r *redisSessions) touch(
+	sessionID string,
+	ttl time.Duration,
+) (bool, error) {
+	if ttl <= 0 {
+		return r.client.Exists(context.Background(),
+			redisSessionPrefix+sessionID)
+	}
+	return r.client.PExpire(context.Background(),
+		redisSessionPrefix+sessionID, ttl)
+}
+
+// remove deletes a session
+func (r *redisSessions) remove(sessionID string) error {
+	return r.client.Del(context.Background(), redisSessionPrefix+sessionID)
+}
@@ -0,0 +1,270 @@
+package mcpgo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
+	"github.com/razorpay/razorpay-mcp-server/pkg/redis"
+	"github.com/razorpay/razorpay-mcp-server/pkg/redis/redistest"
+)
+
+// newRedisTestClient returns a client of a new in-memory Redis server
+func newRedisTestClient(t *testing.T) (*redis.Client, *redistest.Server) {
+	t.Helper()
+
+	server := redistest.NewServer(t)
+	client, err := redis.New(server.URL())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	return client, server
+}
+
+// newRedisCacheTestServer creates a server caching results in Redis, with
+// tools counting the calls reaching their handlers
+func newRedisCacheTestServer(client *redis.Client, calls *int) *Mark3labsImpl {
+	counting := func(
+		ctx context.Context,
+		r CallToolRequest,
+	) (*ToolResult, error) {
+		*calls++
+		return NewToolResultText(fmt.Sprintf("call %d", *calls)), nil
+	}
+	fetchTool := NewTool("fetch_thing", "Fetches a thing",
+		[]ToolParameter{WithString("id")}, counting)
+	fetchTool.SetReadOnly(true)
+	writeTool := NewTool("update_thing", "Updates a thing", nil, counting)
+	writeTool.SetReadOnly(false)
+
+	srv := NewMcpServer("test-server", "1.0.0", WithToolCapabilities(true),
+		WithCacheTTL(time.Minute), WithRedisCache(client))
+	srv.AddTools(fetchTool, writeTool)
+
+	return srv
+}
+
+func TestRedisCache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("shares results between servers", func(t *testing.T) {
+		client, _ := newRedisTestClient(t)
+		calls := 0
+		first := newRedisCacheTestServer(client, &calls)
+		second := newRedisCacheTestServer(client, &calls)
+
+		callToolWithArguments(t, ctx, first, "fetch_thing", `{"id":"pay_1"}`)
+		result := callToolWithArguments(t, ctx, second, "fetch_thing",
+			`{"id":"pay_1"}`)
+
+		assert.Equal(t, "call 1", result)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("drops results after a write on any server", func(t *testing.T) {
+		client, _ := newRedisTestClient(t)
+		calls := 0
+		first := newRedisCacheTestServer(client, &calls)
+		second := newRedisCacheTestServer(client, &calls)
+
+		callToolWithArguments(t, ctx, first, "fetch_thing", `{}`)
+		callToolWithArguments(t, ctx, second, "update_thing", `{}`)
+		result := callToolWithArguments(t, ctx, first, "fetch_thing", `{}`)
+
+		assert.Equal(t, "call 3", result)
+	})
+
+	t.Run("expires results after the ttl", func(t *testing.T) {
+		client, server := newRedisTestClient(t)
+		now := time.Now()
+		server.SetNow(func() time.Time { return now })
+		calls := 0
+		srv := newRedisCacheTestServer(client, &calls)
+
+		callToolWithArguments(t, ctx, srv, "fetch_thing", `{}`)
+		now = now.Add(time.Minute)
+		result := callToolWithArguments(t, ctx, srv, "fetch_thing", `{}`)
+
+		assert.Equal(t, "call 2", result)
+	})
+}
+
+func TestRedisContinuations(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("continues results on another replica", func(t *testing.T) {
+		client, _ := newRedisTestClient(t)
+		text := strings.Repeat("a", 50)
+		first := newResultLimitTestServer(10, text, WithRedisCache(client))
+		second := newResultLimitTestServer(10, text, WithRedisCache(client))
+
+		part, token := readTruncated(t,
+			callToolResult(t, ctx, first, "fetch_thing", `{}`))
+		next, _ := readTruncated(t, callToolResult(t, ctx, second,
+			ContinueResultToolName, `{"continuation_token":"`+token+`"}`))
+
+		assert.Equal(t, `{"text":"a`, part)
+		assert.Equal(t, "aaaaaaaaaa", next)
+		assert.Empty(t, first.resultLimit.rests)
+	})
+
+	t.Run("continues collections on another replica", func(t *testing.T) {
+		client, _ := newRedisTestClient(t)
+		items := []interface{}{
+			strings.Repeat("a", 20), strings.Repeat("b", 20),
+			strings.Repeat("c", 20), strings.Repeat("d", 20),
+		}
+		newServer := func() *Mark3labsImpl {
+			tool := NewTool("fetch_things", "Fetches things", nil,
+				func(ctx context.Context, r CallToolRequest) (
+					*ToolResult, error) {
+					return NewToolResultJSON(map[string]interface{}{
+						"entity": "collection",
+						"count":  len(items),
+						"items":  items,
+					})
+				})
+			tool.SetReadOnly(true)
+			srv := NewMcpServer("test-server", "1.0.0",
+				WithToolCapabilities(true), WithMaxResultSize(120),
+				WithRedisCache(client))
+			srv.AddTools(tool)
+			return srv
+		}
+
+		servers := []*Mark3labsImpl{newServer(), newServer()}
+		result := callToolResult(t, ctx, servers[0], "fetch_things", `{}`)
+		var collected []interface{}
+		for pages := 1; ; pages++ {
+			text, token := readTruncated(t, result)
+			var page map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(text), &page))
+			pageItems, _ := page["items"].([]interface{})
+			collected = append(collected, pageItems...)
+			if token == "" {
+				assert.Greater(t, pages, 1)
+				break
+			}
+			result = callToolResult(t, ctx, servers[pages%2],
+				ContinueResultToolName, `{"continuation_token":"`+token+`"}`)
+		}
+
+		assert.Equal(t, items, collected)
+	})
+
+	t.Run("rejects tokens of other credentials", func(t *testing.T) {
+		client, _ := newRedisTestClient(t)
+		srv := newResultLimitTestServer(10, strings.Repeat("a", 50),
+			WithRedisCache(client))
+		_, token := readTruncated(t, callToolResult(t,
+			contextkey.WithCacheScope(ctx, "merchant-a"), srv,
+			"fetch_thing", `{}`))
+
+		result := callToolResult(t,
+			contextkey.WithCacheScope(ctx, "merchant-b"), srv,
+			ContinueResultToolName, `{"continuation_token":"`+token+`"}`)
+
+		assert.True(t, result.IsError)
+	})
+}
+
+func TestRedisIdempotencyStore(t *testing.T) {
+	client, server := newRedisTestClient(t)
+	store := NewRedisIdempotencyStore(client)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+	server.SetNow(func() time.Time { return now })
+
+	_, ok, err := store.Load("key")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	record := IdempotencyRecord{
+		Arguments: "digest",
+		Result:    []byte(`{"content":[]}`),
+		Expires:   now.Add(time.Minute).UTC(),
+	}
+	require.NoError(t, store.Save("key", record))
+	loaded, ok, err := store.Load("key")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, record.Arguments, loaded.Arguments)
+	assert.JSONEq(t, string(record.Result), string(loaded.Result))
+	assert.True(t, record.Expires.Equal(loaded.Expires))
+
+	// records expire in Redis
+	now = now.Add(time.Minute)
+	_, ok, err = store.Load("key")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// expired records are not kept
+	require.NoError(t, store.Save("expired", record))
+	assert.Equal(t, 0, server.Keys())
+}
+
+func TestSessionStore_Redis(t *testing.T) {
+	t.Run("shares sessions between replicas", func(t *testing.T) {
+		client, _ := newRedisTestClient(t)
+		first := newSessionStore(time.Minute, &redisSessions{client: client})
+		second := newSessionStore(time.Minute, &redisSessions{client: client})
+
+		sessionID := first.Generate()
+		terminated, err := second.Validate(sessionID)
+		require.NoError(t, err)
+		assert.False(t, terminated)
+		assert.Equal(t, 1, second.active())
+
+		_, err = second.Terminate(sessionID)
+		require.NoError(t, err)
+		terminated, err = first.Validate(sessionID)
+		require.NoError(t, err)
+		assert.True(t, terminated)
+		assert.Equal(t, 0, first.active())
+	})
+
+	t.Run("rejects unknown sessions", func(t *testing.T) {
+		client, _ := newRedisTestClient(t)
+		store := newSessionStore(time.Minute, &redisSessions{client: client})
+
+		terminated, err := store.Validate(sessionIDPrefix + "unknown")
+		require.NoError(t, err)
+		assert.True(t, terminated)
+	})
+
+	t.Run("expires idle sessions", func(t *testing.T) {
+		client, server := newRedisTestClient(t)
+		now := time.Now()
+		server.SetNow(func() time.Time { return now })
+		store := newSessionStore(time.Minute, &redisSessions{client: client})
+		store.now = func() time.Time { return now }
+		sessionID := store.Generate()
+
+		now = now.Add(50 * time.Second)
+		terminated, err := store.Validate(sessionID)
+		require.NoError(t, err)
+		assert.False(t, terminated)
+
+		now = now.Add(time.Minute)
+		terminated, err = store.Validate(sessionID)
+		require.NoError(t, err)
+		assert.True(t, terminated)
+	})
+
+	t.Run("fails when redis cannot be reached", func(t *testing.T) {
+		client, _ := newRedisTestClient(t)
+		store := newSessionStore(time.Minute, &redisSessions{client: client})
+		sessionID := store.Generate()
+		require.NoError(t, client.Close())
+
+		_, err := store.Validate(sessionID)
+		assert.ErrorIs(t, err, redis.ErrClosed)
+	})
+}
@@ -0,0 +1,94 @@
+package mcpgo
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ResourceHandler returns the text content of the resource with the given
+// URI
+type ResourceHandler func(ctx context.Context, uri string) (string, error)
+
+// Resource represents a read-only piece of context, such as a schema or a
+// document, that can be added to the server
+type Resource interface {
+	// internal method to convert to mcp's ServerResource
+	toMCPServerResource() server.ServerResource
+
+	// GetURI returns the URI the resource is read with
+	GetURI() string
+}
+
+// mark3labsResourceImpl implements the Resource interface
+type mark3labsResourceImpl struct {
+	uri         string
+	name        string
+	description string
+	mimeType    string
+	handler     ResourceHandler
+}
+
+// NewResource creates a new resource with the given URI, name,
+// description, MIME type and handler
+func NewResource(
+	uri,
+	name,
+	description,
+	mimeType string,
+	handler ResourceHandler) *mark3labsResourceImpl {
+	return &mark3labsResourceImpl{
+		uri:         uri,
+		name:        name,
+		description: description,
+		mimeType:    mimeType,
+		handler:     handler,
+	}
+}
+
+// NewStaticResource creates a resource whose content never changes
+func NewStaticResource(
+	uri,
+	name,
+	description,
+	mimeType,
+	text string) *mark3labsResourceImpl {
+	return NewResource(uri, name, description, mimeType,
+		func(ctx context.Context, uri string) (string, error) {
+			return text, nil
+		})
+}
+
+// GetURI returns the URI of the resource
+func (r *mark3labsResourceImpl) GetURI() string {
+	return r.uri
+}
+
+// toMCPServerResource converts our resource to mcp's ServerResource
+func (r *mark3labsResourceImpl) toMCPServerResource() server.ServerResource {
+	resource := mcp.NewResource(r.uri, r.name,
+		mcp.WithResourceDescription(r.description),
+		mcp.WithMIMEType(r.mimeType),
+	)
+
+	handler := func(
+		ctx context.Context,
+		req mcp.ReadResourceRequest,
+	) ([]mcp.ResourceContents, error) {
+		text, err := r.handler(ctx, req.Params.URI)
+		if err != nil {
+			return nil, err
+		}
+
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      req.Params.URI,
+				MIMEType: r.mimeType,
+				Text:     text,
+			},
+		}, nil
+	}
+
+	return server.ServerResource{Resource: resource, Handler: handler}
+}
@@ -0,0 +1,72 @@
+package mcpgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewResource(t *testing.T) {
+	t.Run("creates resource with all fields", func(t *testing.T) {
+		resource := NewResource(
+			"test://resource",
+			"Test resource",
+			"Test description",
+			"text/plain",
+			func(ctx context.Context, uri string) (string, error) {
+				return "content", nil
+			},
+		)
+		assert.Equal(t, "test://resource", resource.GetURI())
+
+		serverResource := resource.toMCPServerResource()
+		assert.Equal(t, "test://resource", serverResource.Resource.URI)
+		assert.Equal(t, "Test resource", serverResource.Resource.Name)
+		assert.Equal(t, "Test description",
+			serverResource.Resource.Description)
+		assert.Equal(t, "text/plain", serverResource.Resource.MIMEType)
+	})
+
+	t.Run("returns handler error", func(t *testing.T) {
+		resource := NewResource("test://resource", "Test", "Test",
+			"text/plain",
+			func(ctx context.Context, uri string) (string, error) {
+				return "", errors.New("read failed")
+			},
+		)
+
+		_, err := resource.toMCPServerResource().Handler(
+			context.Background(), mcp.ReadResourceRequest{})
+		assert.EqualError(t, err, "read failed")
+	})
+}
+
+func TestMark3labsImpl_AddResources(t *testing.T) {
+	t.Run("serves added resources", func(t *testing.T) {
+		srv := NewMcpServer("test-server", "1.0.0",
+			WithResourceCapabilities(true, true))
+		srv.AddResources(NewStaticResource(
+			"test://resource", "Test", "Test", "text/markdown", "# Test"))
+
+		response := srv.McpServer.HandleMessage(context.Background(),
+			json.RawMessage(`{"jsonrpc":"2.0","id":1,`+
+				`"method":"resources/read",`+
+				`"params":{"uri":"test://resource"}}`))
+		resp, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok, "unexpected response %v", response)
+		result, ok := resp.Result.(mcp.ReadResourceResult)
+		require.True(t, ok)
+
+		require.Len(t, result.Contents, 1)
+		assert.Equal(t, mcp.TextResourceContents{
+			URI:      "test://resource",
+			MIMEType: "text/markdown",
+			Text:     "# Test",
+		}, result.Contents[0])
+	})
+}
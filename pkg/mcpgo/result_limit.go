@@ -0,0 +1,399 @@
+package mcpgo
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
+)
+
+// ContinueResultToolName is the name of the tool that returns the rest of
+// a truncated tool result
+const ContinueResultToolName = "continue_result"
+
+// ContinuationTokenParameter is the name of the parameter of the
+// continue_result tool that identifies the rest of a result
+const ContinuationTokenParameter = "continuation_token"
+
+// NextCursorField is the field of a truncated collection holding the
+// continuation token of its next items
+const NextCursorField = "next_cursor"
+
+// cursorPlaceholder has the length of continuation tokens, to size pages
+// of collections before their token is known
+var cursorPlaceholder = strings.Repeat("0", len(rand.Text()))
+
+// continuationTTL is how long the rest of a truncated result is kept
+const continuationTTL = 10 * time.Minute
+
+// maxContinuations bounds the number of truncated results kept, the oldest
+// being dropped first
+const maxContinuations = 1000
+
+// maxResultSizeOption is the option value that limits the size of tool
+// results
+type maxResultSizeOption int
+
+// WithMaxResultSize returns a server option that truncates the text of
+// tool results larger than the given number of bytes. Truncated results
+// carry a continuation token, which the continue_result tool takes to
+// return the rest. A size of 0 disables the limit.
+func WithMaxResultSize(bytes int) ServerOption {
+	return func(s OptionSetter) error {
+		return s.SetOption(maxResultSizeOption(bytes))
+	}
+}
+
+// resultLimiter truncates large tool results and keeps their rest for the
+// continue_result tool
+type resultLimiter struct {
+	maxSize int
+	// now returns the current time, and is replaced in tests
+	now func() time.Time
+	// shared keeps the rests in Redis instead, if set
+	shared *redisContinuations
+
+	mu    sync.Mutex
+	rests map[string]continuation
+	// order holds the tokens of rests, oldest first
+	order []string
+}
+
+// continuation is the rest of a truncated result
+type continuation struct {
+	text string
+	// collection holds the fields of a collection other than its items,
+	// and items the items left, for collections returned in pages of
+	// whole items instead of in parts of text
+	collection map[string]interface{}
+	items      []interface{}
+	// offset is the number of bytes, or items, of the result returned
+	// before the rest
+	offset int
+	total  int
+	// scope is the cache scope of the call, so that only callers with the
+	// same credentials can read the rest
+	scope   string
+	expires time.Time
+}
+
+// newResultLimiter creates a limiter for results larger than maxSize
+// bytes, or returns nil if maxSize is not positive
+func newResultLimiter(maxSize int) *resultLimiter {
+	if maxSize <= 0 {
+		return nil
+	}
+	return &resultLimiter{
+		maxSize: maxSize,
+		now:     time.Now,
+		rests:   make(map[string]continuation),
+	}
+}
+
+// withResultLimit wraps the handler of a tool so that results whose text
+// is larger than the limit are truncated
+func (l *resultLimiter) withResultLimit(
+	serverTool server.ServerTool,
+) server.ServerTool {
+	handler := serverTool.Handler
+	serverTool.Handler = func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		result, err := handler(ctx, req)
+		if err != nil || result == nil {
+			return result, err
+		}
+
+		text := resultText(result)
+		if !textOnly(result) || len(text) <= l.maxSize {
+			return result, nil
+		}
+
+		scope := contextkey.CacheScopeFromContext(ctx)
+		if collection, items, ok := splitCollection(text); ok {
+			page := l.truncateItems(ctx, result, continuation{
+				collection: collection,
+				items:      items,
+				total:      len(items),
+				scope:      scope,
+			})
+			if page != nil {
+				return page, nil
+			}
+		}
+		return l.truncate(ctx, result, continuation{
+			text:  text,
+			total: len(text),
+			scope: scope,
+		}), nil
+	}
+
+	return serverTool
+}
+
+// splitCollection returns the fields other than items and the items of a
+// collection result, false if the text is not the JSON of an object with
+// items
+func splitCollection(
+	text string,
+) (map[string]interface{}, []interface{}, bool) {
+	var collection map[string]interface{}
+	if err := json.Unmarshal([]byte(text), &collection); err != nil {
+		return nil, nil, false
+	}
+	items, ok := collection["items"].([]interface{})
+	if !ok {
+		return nil, nil, false
+	}
+	delete(collection, "items")
+	return collection, items, true
+}
+
+// collectionPage returns the collection with the items, and the cursor of
+// the next items unless it is empty
+func collectionPage(
+	collection map[string]interface{},
+	items []interface{},
+	cursor string,
+) map[string]interface{} {
+	page := make(map[string]interface{}, len(collection)+2)
+	for name, value := range collection {
+		page[name] = value
+	}
+	page["items"] = items
+	if _, ok := collection["count"]; ok {
+		page["count"] = len(items)
+	}
+	if cursor != "" {
+		page[NextCursorField] = cursor
+	}
+	return page
+}
+
+// truncateItems returns the first items of the rest of a collection that
+// fit the limit as the result, as a collection whose next_cursor is the
+// continuation token of the items left. It returns nil if not even one
+// item fits, for the collection to be returned in parts of text instead.
+// The structured content of the result is replaced with the page.
+func (l *resultLimiter) truncateItems(
+	ctx context.Context,
+	result *mcp.CallToolResult,
+	rest continuation,
+) *mcp.CallToolResult {
+	// Size the page as if it had a cursor
+	base, err := json.Marshal(collectionPage(rest.collection,
+		[]interface{}{}, cursorPlaceholder))
+	if err != nil {
+		return nil
+	}
+	size, fit := len(base), 0
+	for _, item := range rest.items {
+		encoded, err := json.Marshal(item)
+		if err != nil {
+			return nil
+		}
+		size += len(encoded)
+		if fit > 0 {
+			size++
+		}
+		if size > l.maxSize {
+			break
+		}
+		fit++
+	}
+	if fit == 0 {
+		return nil
+	}
+
+	var token string
+	if fit < len(rest.items) {
+		token = l.keep(ctx, continuation{
+			collection: rest.collection,
+			items:      rest.items[fit:],
+			offset:     rest.offset + fit,
+			total:      rest.total,
+			scope:      rest.scope,
+		})
+	}
+	page := collectionPage(rest.collection, rest.items[:fit], token)
+	text, err := json.Marshal(page)
+	if err != nil {
+		return nil
+	}
+
+	truncated := &mcp.CallToolResult{
+		Result:  result.Result,
+		IsError: result.IsError,
+		Content: []mcp.Content{mcp.NewTextContent(string(text))},
+	}
+	if result.StructuredContent != nil {
+		truncated.StructuredContent = page
+	}
+	if token != "" {
+		truncated.Content = append(truncated.Content, mcp.NewTextContent(
+			fmt.Sprintf("[Items %d to %d of %d returned. Call %s with %s "+
+				"%q, the %s, for the next items.]", rest.offset+1,
+				rest.offset+fit, rest.total, ContinueResultToolName,
+				ContinuationTokenParameter, token, NextCursorField)))
+	}
+	return truncated
+}
+
+// truncate returns the first part of rest as the result, keeping what is
+// left for the continue_result tool. The structured content of the result
+// holds the same data as its text, so it is dropped.
+func (l *resultLimiter) truncate(
+	ctx context.Context,
+	result *mcp.CallToolResult,
+	rest continuation,
+) *mcp.CallToolResult {
+	size := len(rest.text)
+	if size > l.maxSize {
+		size = l.maxSize
+		// Cut at the start of a character
+		for size > 0 && !utf8.RuneStart(rest.text[size]) {
+			size--
+		}
+	}
+
+	truncated := &mcp.CallToolResult{
+		Result:  result.Result,
+		IsError: result.IsError,
+		Content: []mcp.Content{mcp.NewTextContent(rest.text[:size])},
+	}
+	if size == len(rest.text) {
+		return truncated
+	}
+
+	token := l.keep(ctx, continuation{
+		text:   rest.text[size:],
+		offset: rest.offset + size,
+		total:  rest.total,
+		scope:  rest.scope,
+	})
+	truncated.Content = append(truncated.Content, mcp.NewTextContent(
+		fmt.Sprintf("[Result truncated after byte %d of %d. Call %s with "+
+			"%s %q for the rest.]", rest.offset+size, rest.total,
+			ContinueResultToolName, ContinuationTokenParameter, token)))
+	return truncated
+}
+
+// keep keeps the rest of a result and returns its continuation token
+func (l *resultLimiter) keep(ctx context.Context, rest continuation) string {
+	token := rand.Text()
+	rest.expires = l.now().Add(continuationTTL)
+	if l.shared != nil {
+		l.shared.save(ctx, token, rest)
+		return token
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	for len(l.order) > 0 {
+		oldest, ok := l.rests[l.order[0]]
+		if ok && now.Before(oldest.expires) &&
+			len(l.order) < maxContinuations {
+			break
+		}
+		delete(l.rests, l.order[0])
+		l.order = l.order[1:]
+	}
+
+	l.rests[token] = rest
+	l.order = append(l.order, token)
+
+	return token
+}
+
+// load returns the rest of a result kept under the token for the cache
+// scope of the call
+func (l *resultLimiter) load(
+	ctx context.Context,
+	token string,
+) (continuation, bool) {
+	scope := contextkey.CacheScopeFromContext(ctx)
+	if l.shared != nil {
+		return l.shared.load(ctx, scope, token)
+	}
+
+	l.mu.Lock()
+	rest, ok := l.rests[token]
+	l.mu.Unlock()
+	return rest, ok && rest.scope == scope
+}
+
+// continueResult returns the next part of a truncated result. Tokens stay
+// valid until they expire, so a retried call returns the same part.
+func (l *resultLimiter) continueResult(
+	ctx context.Context,
+	req mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	token, _ := req.GetArguments()[ContinuationTokenParameter].(string)
+
+	rest, ok := l.load(ctx, token)
+	if !ok || !l.now().Before(rest.expires) {
+		return mcp.NewToolResultError(fmt.Sprintf("continuation token %q "+
+			"is unknown or has expired, call the tool again", token)), nil
+	}
+
+	if rest.collection == nil {
+		return l.truncate(ctx, &mcp.CallToolResult{}, rest), nil
+	}
+	if page := l.truncateItems(ctx, &mcp.CallToolResult{},
+		rest); page != nil {
+		return page, nil
+	}
+
+	// The next item does not fit, so the items left are returned in parts
+	// of text
+	text, err := json.Marshal(collectionPage(rest.collection, rest.items,
+		""))
+	if err != nil {
+		return nil, err
+	}
+	return l.truncate(ctx, &mcp.CallToolResult{}, continuation{
+		text:  string(text),
+		total: len(text),
+		scope: rest.scope,
+	}), nil
+}
+
+// continueResultTool returns the continue_result tool
+func (l *resultLimiter) continueResultTool() server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool(ContinueResultToolName,
+			mcp.WithDescription("Returns the rest of a tool result that "+
+				"was truncated for its size. Pass the continuation token "+
+				"given at the end of the truncated result, which is the "+
+				"next_cursor of truncated collections."),
+			mcp.WithString(ContinuationTokenParameter,
+				mcp.Required(),
+				mcp.Description("Continuation token of the truncated "+
+					"result")),
+			mcp.WithReadOnlyHintAnnotation(true),
+		),
+		Handler: l.continueResult,
+	}
+}
+
+// textOnly reports whether the content of a result is all text
+func textOnly(result *mcp.CallToolResult) bool {
+	for _, content := range result.Content {
+		if _, ok := content.(mcp.TextContent); !ok {
+			return false
+		}
+	}
+	return true
+}
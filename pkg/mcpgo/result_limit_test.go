@@ -0,0 +1,261 @@
+package mcpgo
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
+)
+
+// tokenPattern matches the continuation token in a truncated result
+var tokenPattern = regexp.MustCompile(`continuation_token "([^"]+)"`)
+
+// newResultLimitTestServer creates a server truncating results over
+// maxSize bytes, with a tool returning text
+func newResultLimitTestServer(
+	maxSize int,
+	text string,
+	opts ...ServerOption,
+) *Mark3labsImpl {
+	tool := NewTool("fetch_thing", "Fetches a thing", nil,
+		func(ctx context.Context, r CallToolRequest) (*ToolResult, error) {
+			return NewToolResultJSON(map[string]interface{}{"text": text})
+		})
+	tool.SetReadOnly(true)
+
+	srv := NewMcpServer("test-server", "1.0.0", append(opts,
+		WithToolCapabilities(true), WithMaxResultSize(maxSize))...)
+	srv.AddTools(tool)
+
+	return srv
+}
+
+// callToolResult calls a tool on the server and returns its result
+func callToolResult(
+	t *testing.T,
+	ctx context.Context,
+	srv *Mark3labsImpl,
+	name string,
+	arguments string,
+) mcp.CallToolResult {
+	t.Helper()
+
+	response := srv.McpServer.HandleMessage(ctx, json.RawMessage(
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call",`+
+			`"params":{"name":"`+name+`","arguments":`+arguments+`}}`))
+	resp, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok, "unexpected response %v", response)
+	result, ok := resp.Result.(mcp.CallToolResult)
+	require.True(t, ok)
+
+	return result
+}
+
+// readTruncated returns the text of a result and its continuation token,
+// empty if the result is complete
+func readTruncated(t *testing.T, result mcp.CallToolResult) (string, string) {
+	t.Helper()
+
+	require.NotEmpty(t, result.Content)
+	text, ok := result.Content[0].(mcp.TextContent)
+	require.True(t, ok)
+	if len(result.Content) == 1 {
+		return text.Text, ""
+	}
+
+	note, ok := result.Content[1].(mcp.TextContent)
+	require.True(t, ok)
+	match := tokenPattern.FindStringSubmatch(note.Text)
+	require.Len(t, match, 2, note.Text)
+	return text.Text, match[1]
+}
+
+func TestResultLimit(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("returns small results unchanged", func(t *testing.T) {
+		srv := newResultLimitTestServer(100, "small")
+
+		result := callToolResult(t, ctx, srv, "fetch_thing", `{}`)
+
+		text, token := readTruncated(t, result)
+		assert.JSONEq(t, `{"text":"small"}`, text)
+		assert.Empty(t, token)
+	})
+
+	t.Run("returns large results in parts", func(t *testing.T) {
+		long := strings.Repeat("0123456789", 10)
+		srv := newResultLimitTestServer(40, long)
+
+		result := callToolResult(t, ctx, srv, "fetch_thing", `{}`)
+		text, token := readTruncated(t, result)
+		assert.Len(t, text, 40)
+		full := text
+		for token != "" {
+			result = callToolResult(t, ctx, srv, ContinueResultToolName,
+				`{"continuation_token":"`+token+`"}`)
+			text, token = readTruncated(t, result)
+			assert.LessOrEqual(t, len(text), 40)
+			full += text
+		}
+
+		assert.JSONEq(t, `{"text":"`+long+`"}`, full)
+	})
+
+	t.Run("cuts results between characters", func(t *testing.T) {
+		srv := newResultLimitTestServer(11, "ab₹₹")
+
+		// `{"text":"ab` is 11 bytes, then ₹ takes 3
+		text, token := readTruncated(t,
+			callToolResult(t, ctx, srv, "fetch_thing", `{}`))
+		assert.Equal(t, `{"text":"ab`, text)
+		text, _ = readTruncated(t, callToolResult(t, ctx, srv,
+			ContinueResultToolName, `{"continuation_token":"`+token+`"}`))
+		assert.Equal(t, "₹₹", text[:6])
+	})
+
+	t.Run("returns the same part for a retried token", func(t *testing.T) {
+		srv := newResultLimitTestServer(10, strings.Repeat("a", 50))
+		_, token := readTruncated(t,
+			callToolResult(t, ctx, srv, "fetch_thing", `{}`))
+
+		args := `{"continuation_token":"` + token + `"}`
+		first := callToolResult(t, ctx, srv, ContinueResultToolName, args)
+		second := callToolResult(t, ctx, srv, ContinueResultToolName, args)
+		firstText, _ := readTruncated(t, first)
+		secondText, _ := readTruncated(t, second)
+		assert.Equal(t, firstText, secondText)
+	})
+
+	t.Run("rejects tokens of other credentials", func(t *testing.T) {
+		srv := newResultLimitTestServer(10, strings.Repeat("a", 50))
+		merchantA := contextkey.WithCacheScope(ctx, "merchant-a")
+		merchantB := contextkey.WithCacheScope(ctx, "merchant-b")
+		_, token := readTruncated(t,
+			callToolResult(t, merchantA, srv, "fetch_thing", `{}`))
+
+		result := callToolResult(t, merchantB, srv, ContinueResultToolName,
+			`{"continuation_token":"`+token+`"}`)
+
+		assert.True(t, result.IsError)
+	})
+
+	t.Run("expires tokens", func(t *testing.T) {
+		srv := newResultLimitTestServer(10, strings.Repeat("a", 50))
+		now := time.Now()
+		srv.resultLimit.now = func() time.Time { return now }
+		_, token := readTruncated(t,
+			callToolResult(t, ctx, srv, "fetch_thing", `{}`))
+
+		now = now.Add(continuationTTL)
+		result := callToolResult(t, ctx, srv, ContinueResultToolName,
+			`{"continuation_token":"`+token+`"}`)
+
+		assert.True(t, result.IsError)
+	})
+
+	t.Run("keeps a bounded number of results", func(t *testing.T) {
+		limiter := newResultLimiter(1)
+		first := limiter.keep(ctx, continuation{text: "a"})
+		for i := 0; i < maxContinuations; i++ {
+			limiter.keep(ctx, continuation{text: "a"})
+		}
+
+		assert.Len(t, limiter.rests, maxContinuations)
+		assert.NotContains(t, limiter.rests, first)
+	})
+
+	t.Run("is disabled without a size", func(t *testing.T) {
+		assert.Nil(t, newResultLimiter(0))
+
+		srv := newResultLimitTestServer(0, strings.Repeat("a", 50))
+		assert.Nil(t, srv.McpServer.GetTool(ContinueResultToolName))
+	})
+}
+
+func TestResultLimitCollections(t *testing.T) {
+	ctx := context.Background()
+
+	// newCollectionServer creates a server truncating results over maxSize
+	// bytes, with a tool returning a collection of the items
+	newCollectionServer := func(
+		maxSize int,
+		items []interface{},
+	) *Mark3labsImpl {
+		tool := NewTool("fetch_things", "Fetches things", nil,
+			func(ctx context.Context, r CallToolRequest) (*ToolResult, error) {
+				return NewToolResultJSON(map[string]interface{}{
+					"entity": "collection",
+					"count":  len(items),
+					"items":  items,
+				})
+			}).WithOutputSchema(map[string]interface{}{"type": "object"})
+		tool.SetReadOnly(true)
+
+		srv := NewMcpServer("test-server", "1.0.0",
+			WithToolCapabilities(true), WithMaxResultSize(maxSize))
+		srv.AddTools(tool)
+		return srv
+	}
+
+	var items []interface{}
+	for i := 0; i < 10; i++ {
+		items = append(items, map[string]interface{}{
+			"id": strings.Repeat(string(rune('a'+i)), 20),
+		})
+	}
+
+	t.Run("returns whole items with a next cursor", func(t *testing.T) {
+		srv := newCollectionServer(150, items)
+
+		result := callToolResult(t, ctx, srv, "fetch_things", `{}`)
+		var collected []interface{}
+		pages := 0
+		for {
+			text, token := readTruncated(t, result)
+			assert.LessOrEqual(t, len(text), 150)
+
+			var page map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(text), &page))
+			pageItems, _ := page["items"].([]interface{})
+			assert.Equal(t, float64(len(pageItems)), page["count"])
+			assert.Equal(t, "collection", page["entity"])
+			collected = append(collected, pageItems...)
+			pages++
+
+			if token == "" {
+				assert.NotContains(t, page, NextCursorField)
+				break
+			}
+			assert.Equal(t, token, page[NextCursorField])
+			if pages == 1 {
+				structured, err := json.Marshal(result.StructuredContent)
+				require.NoError(t, err)
+				assert.JSONEq(t, text, string(structured))
+			}
+			result = callToolResult(t, ctx, srv, ContinueResultToolName,
+				`{"continuation_token":"`+token+`"}`)
+		}
+
+		assert.Greater(t, pages, 1)
+		assert.Equal(t, items, collected)
+	})
+
+	t.Run("returns items too large in parts", func(t *testing.T) {
+		srv := newCollectionServer(40, items[:2])
+
+		text, token := readTruncated(t,
+			callToolResult(t, ctx, srv, "fetch_things", `{}`))
+
+		assert.Len(t, text, 40)
+		assert.NotEmpty(t, token)
+	})
+}
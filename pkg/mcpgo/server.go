@@ -2,17 +2,28 @@ package mcpgo
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel/trace"
 
+	"github.com/razorpay/razorpay-mcp-server/pkg/audit"
 	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+	"github.com/razorpay/razorpay-mcp-server/pkg/redis"
 )
 
 // Server defines the minimal MCP server interface needed by the application
 type Server interface {
 	// AddTools adds tools to the server
 	AddTools(tools ...Tool)
+
+	// AddResources adds resources to the server
+	AddResources(resources ...Resource)
+
+	// AddPrompts adds prompts to the server
+	AddPrompts(prompts ...Prompt)
 }
 
 // NewMcpServer creates a new MCP server
@@ -27,18 +38,61 @@ func NewMcpServer(name, version string, opts ...ServerOption) *Mark3labsImpl {
 		_ = opt(optSetter)
 	}
 
-	// Create the underlying mcp server
-	mcpServer := server.NewMCPServer(
-		name,
-		version,
-		optSetter.mcpOptions...,
-	)
+	impl := &Mark3labsImpl{
+		Name:     name,
+		Version:  version,
+		readOnly: optSetter.readOnly,
+		cache:    newResultCache(optSetter.cacheTTL),
+		limiter: newRateLimiter(optSetter.sessionRateLimit,
+			optSetter.keyRateLimit, optSetter.globalRateLimit),
+		metrics: optSetter.metrics,
+		tracer:  optSetter.tracer,
+		audit:   optSetter.audit,
+
+		descriptions:    optSetter.descriptions,
+		toolsetDefaults: optSetter.toolsetDefaults,
+		locale:          optSetter.locale,
+		amountUnit:      optSetter.amountUnit,
+		amountChecks:    optSetter.amountChecks,
+		callContexts:    optSetter.callContexts,
+		keyMode:         optSetter.keyMode,
+		accounts:        optSetter.accounts,
+		partner:         optSetter.partner,
+		dryRun:          optSetter.dryRun,
+		confirmations:   optSetter.confirmations,
+		policy:          optSetter.policy,
+		idempotency:     newIdempotencyGuard(optSetter.idempotency),
+		resultLimit:     newResultLimiter(optSetter.maxResultSize),
+		toolTimeout:     optSetter.toolTimeout,
+		toolTimeouts:    optSetter.toolTimeouts,
+	}
+	if impl.cache != nil && optSetter.redisCache != nil {
+		impl.cache.shared = &redisResults{
+			client: optSetter.redisCache,
+			ttl:    optSetter.cacheTTL,
+		}
+	}
+	if impl.resultLimit != nil && optSetter.redisCache != nil {
+		impl.resultLimit.shared = &redisContinuations{
+			client: optSetter.redisCache,
+		}
+	}
 
-	return &Mark3labsImpl{
-		McpServer: mcpServer,
-		Name:      name,
-		Version:   version,
+	// Create the underlying mcp server
+	mcpOptions := append(optSetter.mcpOptions,
+		server.WithToolFilter(impl.filterWriteTools))
+	if len(optSetter.confirmations) > 0 {
+		mcpOptions = append(mcpOptions, server.WithElicitation())
+	}
+	impl.McpServer = server.NewMCPServer(name, version, mcpOptions...)
+	if impl.resultLimit != nil {
+		impl.McpServer.AddTools(impl.resultLimit.continueResultTool())
 	}
+	if optSetter.toolCatalog {
+		impl.AddTools(impl.listToolsVerboseTool())
+	}
+
+	return impl
 }
 
 // Mark3labsImpl implements the Server interface using mark3labs/mcp-go
@@ -46,16 +100,176 @@ type Mark3labsImpl struct {
 	McpServer *server.MCPServer
 	Name      string
 	Version   string
+
+	// readOnly rejects calls to write tools for every session
+	readOnly bool
+
+	// cache holds results of read-only tools, nil if caching is disabled
+	cache *resultCache
+
+	// limiter rejects tool calls over the rate limits, nil if calls are not
+	// limited
+	limiter *rateLimiter
+
+	// metrics records tool calls, nil if metrics are not collected
+	metrics *observability.Metrics
+
+	// tracer creates the spans of tool calls, nil if tracing is disabled
+	tracer trace.Tracer
+
+	// audit records calls to write tools, nil if there is no audit log
+	audit *audit.Logger
+
+	// descriptions override the descriptions of tools, keyed by tool name
+	descriptions map[string]string
+
+	// toolsetDefaults are the parameter defaults of the tools of toolsets
+	toolsetDefaults map[string]map[string]interface{}
+
+	// locale is recorded in the context of tool calls, empty to keep the
+	// default language
+	locale string
+
+	// amountUnit is the unit of the amounts of calls that pass none, empty
+	// for paise
+	amountUnit string
+
+	// amountChecks check the currency and amounts of calls to tools that
+	// declare amounts
+	amountChecks amountChecksOption
+
+	// callContexts prepare the context of tool calls, in order
+	callContexts []CallContextFunc
+
+	// keyMode restricts write tools to keys of a mode, if its mode is set
+	keyMode keyModeOption
+
+	// accounts selects the account of tool calls, if it has names
+	accounts accountsOption
+
+	// partner lets tool calls select the sub-merchant account they are
+	// made on behalf of
+	partner bool
+
+	// dryRun runs every call to a write tool in dry-run mode
+	dryRun bool
+
+	// confirmations are the confirmations tools ask the user for, keyed by
+	// tool name
+	confirmations map[string]ConfirmationFunc
+
+	// policy checks calls to write tools before they run, if set
+	policy PolicyFunc
+
+	// idempotency replays the results of calls that reuse an idempotency
+	// key
+	idempotency *idempotencyGuard
+
+	// resultLimit truncates large tool results, nil if results are not
+	// limited
+	resultLimit *resultLimiter
+
+	// toolTimeout is how long tool calls may take, 0 for no limit
+	toolTimeout time.Duration
+	// toolTimeouts are the timeouts of single tools, keyed by tool name
+	toolTimeouts map[string]time.Duration
+
+	// toolsets are the toolsets tools are registered from, keyed by tool
+	// name
+	toolsetsMu sync.Mutex
+	toolsets   map[string]string
 }
 
 // mark3labsOptionSetter is used to apply options to the server
 type mark3labsOptionSetter struct {
 	mcpOptions []server.ServerOption
+	readOnly   bool
+	cacheTTL   time.Duration
+
+	sessionRateLimit RateLimit
+	keyRateLimit     RateLimit
+	globalRateLimit  RateLimit
+	metrics          *observability.Metrics
+	tracer           trace.Tracer
+	audit            *audit.Logger
+	descriptions     map[string]string
+	toolsetDefaults  map[string]map[string]interface{}
+	locale           string
+	amountUnit       string
+	amountChecks     amountChecksOption
+	callContexts     []CallContextFunc
+	keyMode          keyModeOption
+	accounts         accountsOption
+	partner          bool
+	dryRun           bool
+	confirmations    map[string]ConfirmationFunc
+	policy           PolicyFunc
+	idempotency      idempotencyOption
+	redisCache       *redis.Client
+	maxResultSize    int
+	toolCatalog      bool
+	toolTimeout      time.Duration
+	toolTimeouts     map[string]time.Duration
 }
 
 func (s *mark3labsOptionSetter) SetOption(option interface{}) error {
-	if opt, ok := option.(server.ServerOption); ok {
+	switch opt := option.(type) {
+	case server.ServerOption:
 		s.mcpOptions = append(s.mcpOptions, opt)
+	case readOnlyOption:
+		s.readOnly = bool(opt)
+	case cacheTTLOption:
+		s.cacheTTL = time.Duration(opt)
+	case sessionRateLimitOption:
+		s.sessionRateLimit = RateLimit(opt)
+	case keyRateLimitOption:
+		s.keyRateLimit = RateLimit(opt)
+	case globalRateLimitOption:
+		s.globalRateLimit = RateLimit(opt)
+	case metricsOption:
+		s.metrics = opt.metrics
+	case tracerOption:
+		s.tracer = opt.tracer
+	case auditOption:
+		s.audit = opt.logger
+	case toolDescriptionsOption:
+		s.descriptions = opt
+	case toolsetDefaultsOption:
+		s.toolsetDefaults = opt
+	case localeOption:
+		s.locale = string(opt)
+	case amountUnitOption:
+		s.amountUnit = string(opt)
+	case amountChecksOption:
+		s.amountChecks = opt
+	case callContextOption:
+		if opt.prepare != nil {
+			s.callContexts = append(s.callContexts, opt.prepare)
+		}
+	case keyModeOption:
+		s.keyMode = opt
+	case accountsOption:
+		s.accounts = opt
+	case partnerOption:
+		s.partner = bool(opt)
+	case dryRunOption:
+		s.dryRun = bool(opt)
+	case confirmationsOption:
+		s.confirmations = opt
+	case policyOption:
+		s.policy = opt.policy
+	case idempotencyOption:
+		s.idempotency = opt
+	case redisCacheOption:
+		s.redisCache = opt.client
+	case maxResultSizeOption:
+		s.maxResultSize = int(opt)
+	case toolCatalogOption:
+		s.toolCatalog = bool(opt)
+	case toolTimeoutOption:
+		s.toolTimeout = time.Duration(opt)
+	case toolTimeoutsOption:
+		s.toolTimeouts = opt
 	}
 	return nil
 }
@@ -65,11 +279,66 @@ func (s *Mark3labsImpl) AddTools(tools ...Tool) {
 	// Convert our Tool to mcp's ServerTool
 	var mcpTools []server.ServerTool
 	for _, tool := range tools {
-		mcpTools = append(mcpTools, tool.toMCPServerTool())
+		s.recordToolset(tool.GetName(), tool.toolset())
+		serverTool := s.applyToolOverrides(tool.toMCPServerTool())
+		serverTool = s.withTimeout(serverTool, tool.timeout())
+		serverTool = s.requireConfirmation(serverTool)
+		serverTool = s.enforcePolicy(serverTool)
+		serverTool = s.withAmountUnit(serverTool, tool.amounts())
+		serverTool = s.withToolsetDefaults(serverTool, tool.toolset())
+		serverTool = withFieldSelection(serverTool, tool.selectsFields())
+		serverTool = s.idempotency.withIdempotency(serverTool)
+		serverTool = s.withLocale(serverTool)
+		serverTool = s.withCallContext(serverTool)
+		serverTool = s.withDryRun(serverTool)
+		serverTool = s.enforceKeyMode(serverTool)
+		serverTool = s.enforceReadOnly(serverTool)
+		if s.cache != nil {
+			serverTool = s.cache.withCache(serverTool, tool.cacheable())
+		}
+		serverTool = s.withPartnerAuth(serverTool)
+		serverTool = s.withAccounts(serverTool)
+		if s.limiter != nil {
+			serverTool = s.limiter.withRateLimit(serverTool)
+		}
+		if s.audit != nil {
+			serverTool = withAuditLog(s.audit, serverTool, tool.toolset())
+		}
+		if s.metrics != nil {
+			serverTool = withToolMetrics(s.metrics, serverTool)
+		}
+		if s.resultLimit != nil {
+			serverTool = s.resultLimit.withResultLimit(serverTool)
+		}
+		if s.tracer != nil {
+			serverTool = withToolTracing(s.tracer, serverTool, tool.toolset())
+		}
+		serverTool = withToolErrors(serverTool)
+		mcpTools = append(mcpTools, serverTool)
 	}
 	s.McpServer.AddTools(mcpTools...)
 }
 
+// AddResources adds resources to the server
+func (s *Mark3labsImpl) AddResources(resources ...Resource) {
+	// Convert our Resource to mcp's ServerResource
+	mcpResources := make([]server.ServerResource, 0, len(resources))
+	for _, resource := range resources {
+		mcpResources = append(mcpResources, resource.toMCPServerResource())
+	}
+	s.McpServer.AddResources(mcpResources...)
+}
+
+// AddPrompts adds prompts to the server
+func (s *Mark3labsImpl) AddPrompts(prompts ...Prompt) {
+	// Convert our Prompt to mcp's ServerPrompt
+	mcpPrompts := make([]server.ServerPrompt, 0, len(prompts))
+	for _, prompt := range prompts {
+		mcpPrompts = append(mcpPrompts, prompt.toMCPServerPrompt())
+	}
+	s.McpServer.AddPrompts(mcpPrompts...)
+}
+
 // OptionSetter is an interface for setting options on a configurable object
 type OptionSetter interface {
 	SetOption(option interface{}) error
@@ -99,6 +368,14 @@ func WithResourceCapabilities(read, list bool) ServerOption {
 	}
 }
 
+// WithPromptCapabilities returns a server option
+// that enables prompt capabilities
+func WithPromptCapabilities(listChanged bool) ServerOption {
+	return func(s OptionSetter) error {
+		return s.SetOption(server.WithPromptCapabilities(listChanged))
+	}
+}
+
 // WithToolCapabilities returns a server option that enables tool capabilities
 func WithToolCapabilities(enabled bool) ServerOption {
 	return func(s OptionSetter) error {
@@ -121,3 +121,11 @@ type invalidServerImpl struct{}
 func (i *invalidServerImpl) AddTools(tools ...Tool) {
 	// Empty implementation for testing
 }
+
+func (i *invalidServerImpl) AddResources(resources ...Resource) {
+	// Empty implementation for testing
+}
+
+func (i *invalidServerImpl) AddPrompts(prompts ...Prompt) {
+	// Empty implementation for testing
+}
@@ -0,0 +1,280 @@
+package mcpgo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Defaults for the streamable HTTP transport
+const (
+	DefaultEndpointPath = "/mcp"
+	DefaultKeepAlive    = 30 * time.Second
+	DefaultSessionTTL   = 30 * time.Minute
+)
+
+// StreamableHTTPConfig holds the streamable HTTP transport configuration
+type StreamableHTTPConfig struct {
+	// endpointPath is the path the MCP endpoint is served on
+	endpointPath string
+	// keepAlive is the interval between pings on idle event streams
+	keepAlive time.Duration
+	// sessionTTL is how long an idle session is kept before it expires
+	sessionTTL time.Duration
+	// contextFunc adds request scoped values to the tool call context
+	contextFunc func(ctx context.Context, r *http.Request) context.Context
+	// sessionBackend shares sessions between replicas, if set
+	sessionBackend sessionBackend
+	// maxRequestSize is the largest request body accepted, in bytes
+	maxRequestSize int64
+}
+
+// StreamableHTTPOption configures the streamable HTTP transport
+type StreamableHTTPOption func(*StreamableHTTPConfig)
+
+// WithEndpointPath sets the path the MCP endpoint is served on
+func WithEndpointPath(path string) StreamableHTTPOption {
+	return func(c *StreamableHTTPConfig) {
+		c.endpointPath = path
+	}
+}
+
+// WithKeepAlive sets the interval between pings sent on idle event
+// streams. A zero interval disables keep-alive pings.
+func WithKeepAlive(interval time.Duration) StreamableHTTPOption {
+	return func(c *StreamableHTTPConfig) {
+		c.keepAlive = interval
+	}
+}
+
+// WithSessionTTL sets how long an idle session is kept before it expires.
+// A zero TTL keeps sessions until the client terminates them.
+func WithSessionTTL(ttl time.Duration) StreamableHTTPOption {
+	return func(c *StreamableHTTPConfig) {
+		c.sessionTTL = ttl
+	}
+}
+
+// WithMaxRequestSize sets the largest request body accepted, in bytes.
+// Larger requests are rejected with a JSON-RPC error and a 413 status. A
+// size of 0 accepts bodies of any size.
+func WithMaxRequestSize(bytes int64) StreamableHTTPOption {
+	return func(c *StreamableHTTPConfig) {
+		c.maxRequestSize = bytes
+	}
+}
+
+// WithHTTPContextFunc sets a function that adds request scoped values to
+// the context tool calls are handled with
+func WithHTTPContextFunc(
+	fn func(ctx context.Context, r *http.Request) context.Context,
+) StreamableHTTPOption {
+	return func(c *StreamableHTTPConfig) {
+		c.contextFunc = fn
+	}
+}
+
+// NewStreamableHTTPServer creates a new streamable HTTP transport server.
+// Sessions survive client reconnects until they expire, and a tool call
+// retried within its session after a dropped connection is answered with
+// the result of the original call instead of being run again.
+func NewStreamableHTTPServer(
+	mcpServer Server,
+	opts ...StreamableHTTPOption,
+) (*mark3labsStreamableHTTPImpl, error) {
+	sImpl, ok := mcpServer.(*Mark3labsImpl)
+	if !ok {
+		return nil, fmt.Errorf("%w: expected *Mark3labsImpl, got %T",
+			ErrInvalidServerImplementation, mcpServer)
+	}
+
+	config := StreamableHTTPConfig{
+		endpointPath:   DefaultEndpointPath,
+		keepAlive:      DefaultKeepAlive,
+		sessionTTL:     DefaultSessionTTL,
+		maxRequestSize: DefaultMaxRequestSize,
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	sessions := newSessionStore(config.sessionTTL, config.sessionBackend)
+
+	mcpOpts := []server.StreamableHTTPOption{
+		server.WithEndpointPath(config.endpointPath),
+		server.WithSessionIdManager(sessions),
+	}
+	if config.keepAlive > 0 {
+		mcpOpts = append(mcpOpts,
+			server.WithHeartbeatInterval(config.keepAlive))
+	}
+	if config.contextFunc != nil {
+		mcpOpts = append(mcpOpts,
+			server.WithHTTPContextFunc(config.contextFunc))
+	}
+
+	return &mark3labsStreamableHTTPImpl{
+		mcpHTTPServer: server.NewStreamableHTTPServer(
+			sImpl.McpServer, mcpOpts...),
+		sessions:       sessions,
+		calls:          newRunningCalls(),
+		endpointPath:   config.endpointPath,
+		maxRequestSize: config.maxRequestSize,
+	}, nil
+}
+
+// mark3labsStreamableHTTPImpl serves the MCP endpoint over streamable HTTP
+type mark3labsStreamableHTTPImpl struct {
+	mcpHTTPServer *server.StreamableHTTPServer
+	sessions      *sessionStore
+	calls         *runningCalls
+	endpointPath  string
+	// maxRequestSize is the largest request body accepted, in bytes
+	maxRequestSize int64
+}
+
+// EndpointPath returns the path the MCP endpoint is served on
+func (s *mark3labsStreamableHTTPImpl) EndpointPath() string {
+	return s.endpointPath
+}
+
+// ActiveSessions returns the number of sessions that have not expired
+func (s *mark3labsStreamableHTTPImpl) ActiveSessions() int {
+	return s.sessions.active()
+}
+
+// Drain stops the transport from opening sessions and starting tool calls,
+// and waits for the running tool calls to finish or for ctx to be done.
+// Requests refused while draining get a 503 response.
+func (s *mark3labsStreamableHTTPImpl) Drain(ctx context.Context) error {
+	return s.calls.drain(ctx)
+}
+
+// ServeHTTP implements http.Handler. Messages posted to the endpoint are
+// checked to be well-formed before the MCP server handles them.
+func (s *mark3labsStreamableHTTPImpl) ServeHTTP(
+	w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.mcpHTTPServer.ServeHTTP(w, r)
+		return
+	}
+	sessionID := r.Header.Get(server.HeaderKeySessionID)
+	if sessionID == "" && s.calls.isDraining() {
+		// Requests without a session open one
+		shuttingDown(w)
+		return
+	}
+
+	body, ok := readMessage(w, r, s.maxRequestSize)
+	if !ok {
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if sessionID == "" {
+		s.mcpHTTPServer.ServeHTTP(w, r)
+		return
+	}
+
+	requestID, ok := toolCallID(body)
+	if !ok {
+		s.mcpHTTPServer.ServeHTTP(w, r)
+		return
+	}
+
+	call, owner := s.sessions.startCall(sessionID, requestID)
+	if call == nil {
+		// Unknown or expired session, which the MCP server rejects
+		s.mcpHTTPServer.ServeHTTP(w, r)
+		return
+	}
+
+	if !owner {
+		// A retry of a call that is still running or already finished
+		select {
+		case <-call.done:
+			call.response.writeTo(w)
+		case <-r.Context().Done():
+		}
+		return
+	}
+
+	// Run the call to completion even if the client disconnects, so a
+	// retry can pick up its result
+	recorder := newResponseRecorder()
+	if s.calls.start() {
+		s.mcpHTTPServer.ServeHTTP(recorder,
+			r.WithContext(context.WithoutCancel(r.Context())))
+		s.calls.finish()
+	} else {
+		// Failed responses are not kept, so retries run the call again
+		shuttingDown(recorder)
+	}
+	s.sessions.finishCall(sessionID, requestID, call, recorder)
+
+	recorder.writeTo(w)
+}
+
+// toolCallID returns the JSON-RPC ID of a tools/call request body
+func toolCallID(body []byte) (string, bool) {
+	var message struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+	}
+	if err := json.Unmarshal(body, &message); err != nil {
+		return "", false
+	}
+	if message.Method != "tools/call" || len(message.ID) == 0 {
+		return "", false
+	}
+
+	return string(message.ID), true
+}
+
+// responseRecorder buffers a response so it can be replayed to a client
+// retrying the request
+type responseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newResponseRecorder() *responseRecorder {
+	return &responseRecorder{
+		header: make(http.Header),
+		status: http.StatusOK,
+	}
+}
+
+// Header implements http.ResponseWriter
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+// Write implements http.ResponseWriter
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
+
+// WriteHeader implements http.ResponseWriter
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+}
+
+// Flush implements http.Flusher. Events are buffered until the call
+// finishes and are written to the client together with its result.
+func (r *responseRecorder) Flush() {}
+
+// writeTo writes the recorded response to w
+func (r *responseRecorder) writeTo(w http.ResponseWriter) {
+	for key, values := range r.header {
+		w.Header()[key] = values
+	}
+	w.WriteHeader(r.status)
+	_, _ = w.Write(r.body.Bytes())
+}
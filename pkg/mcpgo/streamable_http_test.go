@@ -0,0 +1,309 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCountingHTTPServer starts a streamable HTTP server with a tool that
+// counts how often it runs
+func newCountingHTTPServer(
+	t *testing.T,
+	opts ...StreamableHTTPOption,
+) (*httptest.Server, *mark3labsStreamableHTTPImpl, *atomic.Int32) {
+	t.Helper()
+
+	calls := &atomic.Int32{}
+	mcpServer := NewMcpServer("test-server", "1.0.0",
+		WithToolCapabilities(true))
+	mcpServer.AddTools(NewTool("count", "Counts calls", nil,
+		func(ctx context.Context, r CallToolRequest) (*ToolResult, error) {
+			return NewToolResultText(
+				fmt.Sprintf("call %d", calls.Add(1))), nil
+		}))
+
+	httpServer, err := NewStreamableHTTPServer(mcpServer, opts...)
+	require.NoError(t, err)
+
+	ts := httptest.NewServer(httpServer)
+	t.Cleanup(ts.Close)
+
+	return ts, httpServer, calls
+}
+
+// postMessage posts a JSON-RPC message to the MCP endpoint
+func postMessage(
+	t *testing.T,
+	ts *httptest.Server,
+	sessionID string,
+	message string,
+) (*http.Response, string) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost,
+		ts.URL+DefaultEndpointPath, strings.NewReader(message))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if sessionID != "" {
+		req.Header.Set(server.HeaderKeySessionID, sessionID)
+	}
+
+	resp, err := ts.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return resp, string(body)
+}
+
+// initializeSession starts a session and returns its ID
+func initializeSession(t *testing.T, ts *httptest.Server) string {
+	t.Helper()
+
+	resp, _ := postMessage(t, ts, "",
+		`{"jsonrpc":"2.0","id":0,"method":"initialize","params":{}}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	sessionID := resp.Header.Get(server.HeaderKeySessionID)
+	require.True(t, strings.HasPrefix(sessionID, sessionIDPrefix))
+
+	return sessionID
+}
+
+func toolCallMessage(id int) string {
+	return fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"tools/call",`+
+		`"params":{"name":"count","arguments":{}}}`, id)
+}
+
+func TestNewStreamableHTTPServer(t *testing.T) {
+	t.Run("creates server with defaults", func(t *testing.T) {
+		httpServer, err := NewStreamableHTTPServer(
+			NewMcpServer("test-server", "1.0.0"))
+		assert.NoError(t, err)
+		assert.Equal(t, DefaultEndpointPath, httpServer.EndpointPath())
+		assert.Equal(t, DefaultSessionTTL, httpServer.sessions.ttl)
+	})
+
+	t.Run("applies options", func(t *testing.T) {
+		httpServer, err := NewStreamableHTTPServer(
+			NewMcpServer("test-server", "1.0.0"),
+			WithEndpointPath("/razorpay"),
+			WithKeepAlive(0),
+			WithSessionTTL(time.Minute),
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, "/razorpay", httpServer.EndpointPath())
+		assert.Equal(t, time.Minute, httpServer.sessions.ttl)
+	})
+
+	t.Run("returns error with invalid server implementation",
+		func(t *testing.T) {
+			httpServer, err := NewStreamableHTTPServer(&invalidServerImpl{})
+			assert.Error(t, err)
+			assert.Nil(t, httpServer)
+			assert.Contains(t, err.Error(), "invalid server implementation")
+		})
+}
+
+func TestMark3labsStreamableHTTPImpl_ServeHTTP(t *testing.T) {
+	t.Run("replays a retried tool call", func(t *testing.T) {
+		ts, _, calls := newCountingHTTPServer(t)
+		sessionID := initializeSession(t, ts)
+
+		resp, first := postMessage(t, ts, sessionID, toolCallMessage(1))
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, first, "call 1")
+
+		resp, retry := postMessage(t, ts, sessionID, toolCallMessage(1))
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, first, retry)
+		assert.Equal(t, int32(1), calls.Load())
+
+		_, next := postMessage(t, ts, sessionID, toolCallMessage(2))
+		assert.Contains(t, next, "call 2")
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("finishes a call the client disconnected from",
+		func(t *testing.T) {
+			started := make(chan struct{})
+			release := make(chan struct{})
+			calls := &atomic.Int32{}
+
+			mcpServer := NewMcpServer("test-server", "1.0.0",
+				WithToolCapabilities(true))
+			mcpServer.AddTools(NewTool("count", "Counts calls", nil,
+				func(ctx context.Context, r CallToolRequest) (*ToolResult, error) {
+					close(started)
+					<-release
+					return NewToolResultText(
+						fmt.Sprintf("call %d", calls.Add(1))), nil
+				}))
+			httpServer, err := NewStreamableHTTPServer(mcpServer)
+			require.NoError(t, err)
+			ts := httptest.NewServer(httpServer)
+			defer ts.Close()
+
+			sessionID := initializeSession(t, ts)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+				ts.URL+DefaultEndpointPath,
+				strings.NewReader(toolCallMessage(1)))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set(server.HeaderKeySessionID, sessionID)
+
+			dropped := make(chan struct{})
+			go func() {
+				defer close(dropped)
+				resp, err := ts.Client().Do(req)
+				if err == nil {
+					resp.Body.Close()
+				}
+			}()
+
+			<-started
+			cancel()
+			<-dropped
+			close(release)
+
+			resp, retry := postMessage(t, ts, sessionID, toolCallMessage(1))
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Contains(t, retry, "call 1")
+			assert.Equal(t, int32(1), calls.Load())
+		})
+
+	t.Run("does not share calls between sessions", func(t *testing.T) {
+		ts, _, calls := newCountingHTTPServer(t)
+
+		_, first := postMessage(t, ts, initializeSession(t, ts), toolCallMessage(1))
+		_, second := postMessage(t, ts, initializeSession(t, ts), toolCallMessage(1))
+		assert.Contains(t, first, "call 1")
+		assert.Contains(t, second, "call 2")
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("rejects expired sessions", func(t *testing.T) {
+		ts, httpServer, calls := newCountingHTTPServer(t,
+			WithSessionTTL(time.Minute))
+		sessionID := initializeSession(t, ts)
+
+		now := time.Now()
+		httpServer.sessions.now = func() time.Time {
+			return now.Add(2 * time.Minute)
+		}
+
+		resp, _ := postMessage(t, ts, sessionID, toolCallMessage(1))
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, int32(0), calls.Load())
+	})
+
+	t.Run("rejects terminated sessions", func(t *testing.T) {
+		ts, _, _ := newCountingHTTPServer(t)
+		sessionID := initializeSession(t, ts)
+
+		req, err := http.NewRequest(http.MethodDelete,
+			ts.URL+DefaultEndpointPath, nil)
+		require.NoError(t, err)
+		req.Header.Set(server.HeaderKeySessionID, sessionID)
+		resp, err := ts.Client().Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		resp, _ = postMessage(t, ts, sessionID, toolCallMessage(1))
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestSessionStore(t *testing.T) {
+	t.Run("keeps active sessions alive", func(t *testing.T) {
+		store := newSessionStore(time.Minute, nil)
+		now := time.Now()
+		store.now = func() time.Time { return now }
+		sessionID := store.Generate()
+
+		for i := 0; i < 3; i++ {
+			now = now.Add(50 * time.Second)
+			terminated, err := store.Validate(sessionID)
+			assert.NoError(t, err)
+			assert.False(t, terminated)
+		}
+	})
+
+	t.Run("never expires sessions without a ttl", func(t *testing.T) {
+		store := newSessionStore(0, nil)
+		now := time.Now()
+		store.now = func() time.Time { return now }
+		sessionID := store.Generate()
+
+		now = now.Add(24 * time.Hour)
+		terminated, err := store.Validate(sessionID)
+		assert.NoError(t, err)
+		assert.False(t, terminated)
+	})
+
+	t.Run("counts active sessions", func(t *testing.T) {
+		store := newSessionStore(time.Minute, nil)
+		now := time.Now()
+		store.now = func() time.Time { return now }
+		store.Generate()
+		now = now.Add(30 * time.Second)
+		terminated := store.Generate()
+		store.Generate()
+		_, _ = store.Terminate(terminated)
+
+		assert.Equal(t, 2, store.active())
+		now = now.Add(45 * time.Second)
+		assert.Equal(t, 1, store.active())
+	})
+
+	t.Run("rejects a missing session id", func(t *testing.T) {
+		_, err := newSessionStore(time.Minute, nil).Validate("")
+		assert.Error(t, err)
+	})
+
+	t.Run("evicts the oldest finished calls", func(t *testing.T) {
+		store := newSessionStore(time.Minute, nil)
+		sessionID := store.Generate()
+
+		for i := 0; i <= maxReplayableCalls; i++ {
+			requestID := fmt.Sprint(i)
+			call, owner := store.startCall(sessionID, requestID)
+			require.True(t, owner)
+			store.finishCall(sessionID, requestID, call,
+				newResponseRecorder())
+		}
+
+		_, owner := store.startCall(sessionID, "0")
+		assert.True(t, owner)
+		_, owner = store.startCall(sessionID, "1")
+		assert.False(t, owner)
+	})
+
+	t.Run("does not keep failed calls", func(t *testing.T) {
+		store := newSessionStore(time.Minute, nil)
+		sessionID := store.Generate()
+
+		call, _ := store.startCall(sessionID, "1")
+		response := newResponseRecorder()
+		response.WriteHeader(http.StatusBadRequest)
+		store.finishCall(sessionID, "1", call, response)
+
+		_, owner := store.startCall(sessionID, "1")
+		assert.True(t, owner)
+	})
+}
@@ -0,0 +1,110 @@
+package mcpgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// DefaultToolTimeout is how long a tool call may take by default
+const DefaultToolTimeout = 30 * time.Second
+
+// noTimeout is the timeout of tools that run without the server's tool
+// timeout
+const noTimeout time.Duration = -1
+
+// toolTimeoutOption is the option value that limits how long tool calls
+// may take
+type toolTimeoutOption time.Duration
+
+// WithToolTimeout returns a server option that cancels the context of tool
+// calls running longer than timeout, which aborts the API requests they
+// are making. Tools that set a longer timeout with WithTimeout get theirs.
+// A timeout of 0 disables the limit.
+func WithToolTimeout(timeout time.Duration) ServerOption {
+	return func(s OptionSetter) error {
+		return s.SetOption(toolTimeoutOption(timeout))
+	}
+}
+
+// toolTimeoutsOption is the option value that sets the timeouts of single
+// tools, keyed by tool name
+type toolTimeoutsOption map[string]time.Duration
+
+// WithToolTimeouts returns a server option that sets the timeouts of the
+// named tools, taking precedence over WithToolTimeout and the timeouts of
+// the tools. A timeout of 0 lets calls of the tool run without a limit.
+func WithToolTimeouts(timeouts map[string]time.Duration) ServerOption {
+	return func(s OptionSetter) error {
+		return s.SetOption(toolTimeoutsOption(timeouts))
+	}
+}
+
+// callTimeout returns how long calls of a tool may take, 0 for no limit
+func (s *Mark3labsImpl) callTimeout(
+	name string,
+	own time.Duration,
+) time.Duration {
+	if timeout, ok := s.toolTimeouts[name]; ok {
+		return timeout
+	}
+	if own == noTimeout {
+		return 0
+	}
+	if s.toolTimeout > 0 && own > s.toolTimeout {
+		return own
+	}
+	return s.toolTimeout
+}
+
+// withTimeout wraps the handler of a tool so that its context is cancelled
+// once the timeout of the tool passes. Handlers pass the context on to the
+// API requests they make, which are aborted when it is cancelled. A write
+// tool may have made changes before it was cancelled, so its timeouts
+// report the result it got to instead of asking to call it again.
+func (s *Mark3labsImpl) withTimeout(
+	serverTool server.ServerTool,
+	own time.Duration,
+) server.ServerTool {
+	timeout := s.callTimeout(serverTool.Tool.Name, own)
+	if timeout <= 0 {
+		return serverTool
+	}
+
+	name := serverTool.Tool.Name
+	write := !isReadOnlyTool(serverTool.Tool)
+	handler := serverTool.Handler
+	serverTool.Handler = func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		result, err := handler(ctx, req)
+		failed := err != nil || result == nil || result.IsError
+		if !failed || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return result, err
+		}
+		if !write {
+			return mcp.NewToolResultError(fmt.Sprintf("tool %s timed out "+
+				"after %s, try again or narrow down the request", name,
+				timeout)), nil
+		}
+
+		text := fmt.Sprintf("tool %s timed out after %s, and may have made "+
+			"some of its changes before it was cancelled. Check their "+
+			"state, such as by fetching the entities, before calling it "+
+			"again", name, timeout)
+		if completed := resultText(result); completed != "" {
+			text += ". Result when it was cancelled:\n" + completed
+		}
+		return mcp.NewToolResultError(text), nil
+	}
+
+	return serverTool
+}
@@ -0,0 +1,149 @@
+package mcpgo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTimeoutTestServer creates a server with a tool that waits for its
+// context to be done, and a tool that answers at once
+func newTimeoutTestServer(
+	own time.Duration,
+	opts ...ServerOption,
+) *Mark3labsImpl {
+	wait := NewTool("wait_thing", "Waits for its context", nil,
+		func(ctx context.Context, r CallToolRequest) (*ToolResult, error) {
+			select {
+			case <-ctx.Done():
+				return NewToolResultError(ctx.Err().Error()), nil
+			case <-time.After(100 * time.Millisecond):
+				return NewToolResultText("waited"), nil
+			}
+		}).WithTimeout(own)
+	wait.SetReadOnly(true)
+	fetch := NewTool("fetch_thing", "Fetches a thing", nil,
+		func(ctx context.Context, r CallToolRequest) (*ToolResult, error) {
+			return NewToolResultText("done"), nil
+		})
+
+	srv := NewMcpServer("test-server", "1.0.0",
+		append([]ServerOption{WithToolCapabilities(true)}, opts...)...)
+	srv.AddTools(wait, fetch)
+
+	return srv
+}
+
+// resultTextOf returns the text of a tool result
+func resultTextOf(t *testing.T, result *mcp.CallToolResult) string {
+	t.Helper()
+
+	require.Len(t, result.Content, 1)
+	text, ok := result.Content[0].(mcp.TextContent)
+	require.True(t, ok)
+	return text.Text
+}
+
+func TestToolTimeout(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("runs without a limit by default", func(t *testing.T) {
+		srv := newTimeoutTestServer(0)
+
+		result := callTool(t, ctx, srv, "wait_thing")
+		assert.False(t, result.IsError)
+		assert.Equal(t, "waited", resultTextOf(t, result))
+	})
+
+	t.Run("cancels calls running too long", func(t *testing.T) {
+		srv := newTimeoutTestServer(0,
+			WithToolTimeout(10*time.Millisecond))
+
+		result := callTool(t, ctx, srv, "wait_thing")
+		assert.True(t, result.IsError)
+		assert.Equal(t, "tool wait_thing timed out after 10ms, try again "+
+			"or narrow down the request", resultTextOf(t, result))
+
+		result = callTool(t, ctx, srv, "fetch_thing")
+		assert.False(t, result.IsError)
+	})
+
+	t.Run("lets tools take longer", func(t *testing.T) {
+		srv := newTimeoutTestServer(2*time.Second,
+			WithToolTimeout(10*time.Millisecond))
+
+		result := callTool(t, ctx, srv, "wait_thing")
+		assert.False(t, result.IsError)
+	})
+
+	t.Run("keeps shorter timeouts of the server", func(t *testing.T) {
+		srv := newTimeoutTestServer(time.Millisecond,
+			WithToolTimeout(2*time.Second))
+
+		result := callTool(t, ctx, srv, "wait_thing")
+		assert.False(t, result.IsError)
+	})
+
+	t.Run("sets the timeouts of single tools", func(t *testing.T) {
+		srv := newTimeoutTestServer(2*time.Second,
+			WithToolTimeout(time.Hour),
+			WithToolTimeouts(map[string]time.Duration{
+				"wait_thing": 10 * time.Millisecond,
+			}))
+
+		result := callTool(t, ctx, srv, "wait_thing")
+		assert.True(t, result.IsError)
+		assert.Contains(t, resultTextOf(t, result), "timed out after 10ms")
+
+		srv = newTimeoutTestServer(0,
+			WithToolTimeout(10*time.Millisecond),
+			WithToolTimeouts(map[string]time.Duration{"wait_thing": 0}))
+
+		result = callTool(t, ctx, srv, "wait_thing")
+		assert.False(t, result.IsError)
+	})
+	t.Run("reports what a write tool did before it timed out",
+		func(t *testing.T) {
+			write := NewTool("create_things", "Creates things", nil,
+				func(ctx context.Context, r CallToolRequest) (
+					*ToolResult, error) {
+					<-ctx.Done()
+					return NewToolResultError(`{"created":["thing_1"]}`), nil
+				})
+			srv := NewMcpServer("test-server", "1.0.0",
+				WithToolCapabilities(true),
+				WithToolTimeout(10*time.Millisecond))
+			srv.AddTools(write)
+
+			result := callTool(t, ctx, srv, "create_things")
+			assert.True(t, result.IsError)
+			text := resultTextOf(t, result)
+			assert.NotContains(t, text, "try again")
+			assert.Contains(t, text, "before calling it again")
+			assert.Contains(t, text, `{"created":["thing_1"]}`)
+		})
+
+	t.Run("lets tools run without a timeout", func(t *testing.T) {
+		batch := NewTool("create_batch", "Creates a batch", nil,
+			func(ctx context.Context, r CallToolRequest) (*ToolResult, error) {
+				select {
+				case <-ctx.Done():
+					return NewToolResultError(ctx.Err().Error()), nil
+				case <-time.After(50 * time.Millisecond):
+					return NewToolResultText("created"), nil
+				}
+			}).WithoutTimeout()
+		srv := NewMcpServer("test-server", "1.0.0",
+			WithToolCapabilities(true),
+			WithToolTimeout(10*time.Millisecond))
+		srv.AddTools(batch)
+
+		result := callTool(t, ctx, srv, "create_batch")
+		assert.False(t, result.IsError)
+		assert.Equal(t, "created", resultTextOf(t, result))
+	})
+}
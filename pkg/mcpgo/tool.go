@@ -2,12 +2,24 @@ package mcpgo
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
 )
 
+// StrictParameter is the name of the parameter, accepted by every tool,
+// that enables or disables rejection of unknown parameters for one call
+const StrictParameter = "strict"
+
 // ToolHandler handles tool calls
 type ToolHandler func(
 	ctx context.Context,
@@ -17,13 +29,65 @@ type ToolHandler func(
 type CallToolRequest struct {
 	Name      string
 	Arguments any
+
+	// Parameters holds the names of the parameters declared by the tool
+	Parameters []string
+	// Strict is set when arguments not in Parameters must be rejected
+	Strict bool
 }
 
 // ToolResult represents the result of a tool call
 type ToolResult struct {
 	Text    string
 	IsError bool
+	// Content holds the content blocks returned after Text: TextContent,
+	// ImageContent, AudioContent, ResourceLink or EmbeddedResource
 	Content []interface{}
+	// Error is the structured error of an error result, if the tool
+	// classifies it
+	Error *ToolError
+	// Raw marks a result whose text is not JSON, such as CSV, which is
+	// never returned as structured content
+	Raw bool
+}
+
+// TextContent is a text content block of a tool result
+type TextContent struct {
+	Text string
+}
+
+// ImageContent is an image content block of a tool result
+type ImageContent struct {
+	// Data is the base64 encoded image
+	Data string
+	// MIMEType is the type of the image, such as image/png
+	MIMEType string
+}
+
+// AudioContent is an audio content block of a tool result
+type AudioContent struct {
+	// Data is the base64 encoded audio
+	Data string
+	// MIMEType is the type of the audio, such as audio/wav
+	MIMEType string
+}
+
+// ResourceLink is a content block pointing to a resource the client can
+// fetch, such as a file the tool wrote
+type ResourceLink struct {
+	URI         string
+	Name        string
+	Description string
+	MIMEType    string
+}
+
+// EmbeddedResource is a content block carrying the contents of a resource,
+// either as Text or as base64 encoded binary Blob
+type EmbeddedResource struct {
+	URI      string
+	MIMEType string
+	Text     string
+	Blob     string
 }
 
 // Tool represents a tool that can be added to the server
@@ -31,11 +95,34 @@ type Tool interface {
 	// internal method to convert to mcp's ServerTool
 	toMCPServerTool() server.ServerTool
 
+	// GetName returns the name the tool is registered under
+	GetName() string
+
 	// GetHandler internal method for fetching the underlying handler
 	GetHandler() ToolHandler
 
 	// SetReadOnly sets whether this tool is read-only for annotation purposes
 	SetReadOnly(readOnly bool)
+
+	// SetToolset records the name of the toolset the tool is registered
+	// from, for tracing purposes
+	SetToolset(name string)
+
+	// internal method returning the toolset set with SetToolset
+	toolset() string
+
+	// internal method reporting whether results of the tool may be cached
+	cacheable() bool
+
+	// internal method returning the timeout set with WithTimeout
+	timeout() time.Duration
+
+	// internal method returning the amount parameters set with WithAmounts
+	amounts() []string
+
+	// internal method reporting whether calls may select the fields of
+	// results, as set with WithFieldSelection
+	selectsFields() bool
 }
 
 // PropertyOption represents a customization option for
@@ -93,6 +180,40 @@ func Pattern(pattern string) PropertyOption {
 	}
 }
 
+// Base64 marks a string parameter as base64 encoded binary content of at
+// most maxBytes bytes, and limits the length of the encoded string
+// accordingly. DecodeBase64 decodes and checks the content of calls.
+func Base64(maxBytes int) PropertyOption {
+	return func(schema map[string]interface{}) {
+		propType, ok := schema["type"].(string)
+		if !ok || propType != "string" {
+			return
+		}
+		schema["contentEncoding"] = "base64"
+		schema["maxLength"] = base64.StdEncoding.EncodedLen(maxBytes)
+	}
+}
+
+// DecodeBase64 decodes the base64 encoded content of a parameter declared
+// with Base64, ignoring line breaks. It returns an error if the content is
+// not valid base64 or is larger than maxBytes bytes.
+func DecodeBase64(content string, maxBytes int) ([]byte, error) {
+	content = strings.NewReplacer("\n", "", "\r", "").Replace(content)
+	tooLarge := fmt.Errorf("content is larger than %d bytes", maxBytes)
+	if len(content) > base64.StdEncoding.EncodedLen(maxBytes) {
+		return nil, tooLarge
+	}
+
+	data, err := base64.StdEncoding.DecodeString(content)
+	if err != nil {
+		return nil, errors.New("content is not valid base64")
+	}
+	if len(data) > maxBytes {
+		return nil, tooLarge
+	}
+	return data, nil
+}
+
 // Enum sets allowed values for a parameter
 func Enum(values ...interface{}) PropertyOption {
 	return func(schema map[string]interface{}) {
@@ -230,6 +351,25 @@ type mark3labsToolImpl struct {
 	handler     ToolHandler
 	parameters  []ToolParameter
 	isReadOnly  bool
+	// outputSchema is the JSON schema of the tool's results, if declared
+	outputSchema map[string]interface{}
+	// noCache keeps the results of the tool out of the result cache
+	noCache bool
+	// callTimeout is how long calls of the tool may take, if longer than
+	// the server's tool timeout
+	callTimeout time.Duration
+	// toolsetName is the toolset the tool is registered from, if known
+	toolsetName string
+	// amountParams are the parameters holding amounts in currency
+	// sub-units, which calls can pass in rupees
+	amountParams []string
+	// fieldSelection lets calls prune results to the fields they select
+	fieldSelection bool
+
+	// paramOpts caches the converted parameter schemas, which only depend
+	// on the tool definition and not on its read/write classification
+	paramOptsOnce sync.Once
+	paramOpts     []mcp.ToolOption
 }
 
 // NewTool creates a new tool with the given
@@ -283,6 +423,13 @@ func addStringPropertyOptions(
 		propOpts = append(propOpts, mcp.Pattern(pattern))
 	}
 
+	// Add contentEncoding if present
+	if encoding, ok := schema["contentEncoding"].(string); ok {
+		propOpts = append(propOpts, func(property map[string]any) {
+			property["contentEncoding"] = encoding
+		})
+	}
+
 	return propOpts
 }
 
@@ -425,6 +572,53 @@ func addTypeSpecificPropertyOptions(
 	return propOpts
 }
 
+// WithOutputSchema declares the JSON schema of the tool's results, which
+// must describe an object. Results of tools with an output schema are also
+// returned as structured content.
+func (t *mark3labsToolImpl) WithOutputSchema(
+	schema map[string]interface{}) *mark3labsToolImpl {
+	t.outputSchema = schema
+	return t
+}
+
+// WithoutCache keeps the results of the tool out of the result cache, for
+// read-only tools whose calls have effects beyond their result
+func (t *mark3labsToolImpl) WithoutCache() *mark3labsToolImpl {
+	t.noCache = true
+	return t
+}
+
+// cacheable reports whether results of the tool may be cached
+func (t *mark3labsToolImpl) cacheable() bool {
+	return !t.noCache
+}
+
+// WithTimeout lets calls of the tool run for up to d, for tools that take
+// longer than the server's tool timeout, such as tools that poll or export
+func (t *mark3labsToolImpl) WithTimeout(d time.Duration) *mark3labsToolImpl {
+	t.callTimeout = d
+	return t
+}
+
+// WithoutTimeout lets calls of the tool run without the server's tool
+// timeout, for write tools that must not be stopped partway, such as
+// tools that make a batch of changes
+func (t *mark3labsToolImpl) WithoutTimeout() *mark3labsToolImpl {
+	t.callTimeout = noTimeout
+	return t
+}
+
+// timeout returns how long calls of the tool may take, 0 if the server's
+// tool timeout applies, and noTimeout if none applies
+func (t *mark3labsToolImpl) timeout() time.Duration {
+	return t.callTimeout
+}
+
+// GetName returns the name of the tool
+func (t *mark3labsToolImpl) GetName() string {
+	return t.name
+}
+
 // GetHandler returns the handler for the tool
 func (t *mark3labsToolImpl) GetHandler() ToolHandler {
 	return t.handler
@@ -435,6 +629,81 @@ func (t *mark3labsToolImpl) SetReadOnly(readOnly bool) {
 	t.isReadOnly = readOnly
 }
 
+// SetToolset records the name of the toolset the tool is registered from
+func (t *mark3labsToolImpl) SetToolset(name string) {
+	t.toolsetName = name
+}
+
+// toolset returns the name of the toolset the tool is registered from
+func (t *mark3labsToolImpl) toolset() string {
+	return t.toolsetName
+}
+
+// parameterOptions converts the tool parameters to mcp tool options.
+// The conversion is done once per tool and reused on later calls.
+func (t *mark3labsToolImpl) parameterOptions() []mcp.ToolOption {
+	t.paramOptsOnce.Do(func() {
+		paramOpts := make([]mcp.ToolOption, 0, len(t.parameters))
+		for _, param := range t.parameters {
+			// Get property options from schema
+			propOpts := convertSchemaToPropertyOptions(param.Schema)
+
+			// Get the type from the schema
+			schemaType, ok := param.Schema["type"].(string)
+			if !ok {
+				// Default to string if type is missing or not a string
+				schemaType = "string"
+			}
+
+			// Use the appropriate function based on schema type
+			switch schemaType {
+			case "string":
+				paramOpts = append(paramOpts, mcp.WithString(param.Name, propOpts...))
+			case "number", "integer":
+				paramOpts = append(paramOpts, mcp.WithNumber(param.Name, propOpts...))
+			case "boolean":
+				paramOpts = append(paramOpts, mcp.WithBoolean(param.Name, propOpts...))
+			case "object":
+				paramOpts = append(paramOpts, mcp.WithObject(param.Name, propOpts...))
+			case "array":
+				paramOpts = append(paramOpts, mcp.WithArray(param.Name, propOpts...))
+			default:
+				// Unknown type, default to string
+				paramOpts = append(paramOpts, mcp.WithString(param.Name, propOpts...))
+			}
+		}
+		paramOpts = append(paramOpts, mcp.WithBoolean(StrictParameter,
+			mcp.Description("Optional: Reject parameters not declared by "+
+				"this tool instead of ignoring them. Overrides the "+
+				"server's --strict-params setting for this call"),
+		))
+		t.paramOpts = paramOpts
+	})
+
+	return t.paramOpts
+}
+
+// parameterNames returns the names of the parameters declared by the tool
+func (t *mark3labsToolImpl) parameterNames() []string {
+	names := make([]string, 0, len(t.parameters))
+	for _, param := range t.parameters {
+		names = append(names, param.Name)
+	}
+	return names
+}
+
+// isStrictCall reports whether unknown parameters must be rejected for a
+// call. The strict argument of the call takes precedence over the server
+// wide setting carried by the context.
+func isStrictCall(ctx context.Context, arguments any) bool {
+	if args, ok := arguments.(map[string]interface{}); ok {
+		if strict, ok := args[StrictParameter].(bool); ok {
+			return strict
+		}
+	}
+	return contextkey.StrictParamsFromContext(ctx)
+}
+
 // toMCPServerTool converts our Tool to mcp's ServerTool
 func (t *mark3labsToolImpl) toMCPServerTool() server.ServerTool {
 	// Create the mcp tool with appropriate options
@@ -444,34 +713,7 @@ func (t *mark3labsToolImpl) toMCPServerTool() server.ServerTool {
 	toolOpts = append(toolOpts, mcp.WithDescription(t.description))
 
 	// Add parameters with their schemas
-	for _, param := range t.parameters {
-		// Get property options from schema
-		propOpts := convertSchemaToPropertyOptions(param.Schema)
-
-		// Get the type from the schema
-		schemaType, ok := param.Schema["type"].(string)
-		if !ok {
-			// Default to string if type is missing or not a string
-			schemaType = "string"
-		}
-
-		// Use the appropriate function based on schema type
-		switch schemaType {
-		case "string":
-			toolOpts = append(toolOpts, mcp.WithString(param.Name, propOpts...))
-		case "number", "integer":
-			toolOpts = append(toolOpts, mcp.WithNumber(param.Name, propOpts...))
-		case "boolean":
-			toolOpts = append(toolOpts, mcp.WithBoolean(param.Name, propOpts...))
-		case "object":
-			toolOpts = append(toolOpts, mcp.WithObject(param.Name, propOpts...))
-		case "array":
-			toolOpts = append(toolOpts, mcp.WithArray(param.Name, propOpts...))
-		default:
-			// Unknown type, default to string
-			toolOpts = append(toolOpts, mcp.WithString(param.Name, propOpts...))
-		}
-	}
+	toolOpts = append(toolOpts, t.parameterOptions()...)
 
 	// Add tool annotations based on read/write classification
 	if t.isReadOnly {
@@ -482,6 +724,24 @@ func (t *mark3labsToolImpl) toMCPServerTool() server.ServerTool {
 		toolOpts = append(toolOpts, mcp.WithReadOnlyHintAnnotation(false))
 		toolOpts = append(toolOpts, mcp.WithDestructiveHintAnnotation(true))
 		toolOpts = append(toolOpts, mcp.WithOpenWorldHintAnnotation(false))
+		toolOpts = append(toolOpts, mcp.WithBoolean(DryRunParameter,
+			mcp.Description("Optional: Validate the call and return the "+
+				"Razorpay API requests it would make without sending them"),
+		))
+		toolOpts = append(toolOpts, mcp.WithString(IdempotencyKeyParameter,
+			mcp.Description("Optional: Unique key of this call, such as a "+
+				"UUID. Retrying the call with the same key and parameters "+
+				"returns the result of the first successful call instead of "+
+				"running it again"),
+		))
+	}
+
+	// Add the output schema if declared
+	if t.outputSchema != nil {
+		// A schema built from JSON-compatible values always marshals
+		if schema, err := json.Marshal(t.outputSchema); err == nil {
+			toolOpts = append(toolOpts, mcp.WithRawOutputSchema(schema))
+		}
 	}
 
 	// Create the tool with all options
@@ -494,8 +754,10 @@ func (t *mark3labsToolImpl) toMCPServerTool() server.ServerTool {
 	) (*mcp.CallToolResult, error) {
 		// Convert mcp request to our request
 		ourReq := CallToolRequest{
-			Name:      req.Params.Name,
-			Arguments: req.Params.Arguments,
+			Name:       req.Params.Name,
+			Arguments:  req.Params.Arguments,
+			Parameters: t.parameterNames(),
+			Strict:     isStrictCall(ctx, req.Params.Arguments),
 		}
 
 		// Call our handler
@@ -508,9 +770,19 @@ func (t *mark3labsToolImpl) toMCPServerTool() server.ServerTool {
 		var mcpResult *mcp.CallToolResult
 		if result.IsError {
 			mcpResult = mcp.NewToolResultError(result.Text)
+			toolErrors := ToolErrorsFromContext(ctx)
+			if toolErrors != nil && result.Error != nil {
+				toolErrors.Record(*result.Error)
+			}
+		} else if structured := t.structuredContent(result); structured != nil {
+			mcpResult = mcp.NewToolResultStructured(structured, result.Text)
 		} else {
 			mcpResult = mcp.NewToolResultText(result.Text)
 		}
+		if !result.IsError {
+			mcpResult.Content = append(mcpResult.Content,
+				contentBlocks(result.Content)...)
+		}
 
 		return mcpResult, nil
 	}
@@ -521,6 +793,63 @@ func (t *mark3labsToolImpl) toMCPServerTool() server.ServerTool {
 	}
 }
 
+// structuredContent returns the structured content of a successful result,
+// which is its JSON object text if the tool declares an output schema and
+// the result is not raw
+func (t *mark3labsToolImpl) structuredContent(
+	result *ToolResult) map[string]interface{} {
+	if t.outputSchema == nil || result.Raw {
+		return nil
+	}
+
+	var structured map[string]interface{}
+	if err := json.Unmarshal([]byte(result.Text), &structured); err != nil {
+		return nil
+	}
+	return structured
+}
+
+// contentBlocks converts the content blocks of a result to mcp content,
+// skipping blocks of unknown types
+func contentBlocks(content []interface{}) []mcp.Content {
+	var blocks []mcp.Content
+	for _, block := range content {
+		switch block := block.(type) {
+		case TextContent:
+			blocks = append(blocks, mcp.NewTextContent(block.Text))
+		case ImageContent:
+			blocks = append(blocks,
+				mcp.NewImageContent(block.Data, block.MIMEType))
+		case AudioContent:
+			blocks = append(blocks,
+				mcp.NewAudioContent(block.Data, block.MIMEType))
+		case ResourceLink:
+			blocks = append(blocks, mcp.NewResourceLink(block.URI,
+				block.Name, block.Description, block.MIMEType))
+		case EmbeddedResource:
+			blocks = append(blocks, embeddedResource(block))
+		}
+	}
+	return blocks
+}
+
+// embeddedResource converts an embedded resource to mcp content, as a blob
+// if it has binary contents
+func embeddedResource(resource EmbeddedResource) mcp.EmbeddedResource {
+	if resource.Blob != "" {
+		return mcp.NewEmbeddedResource(mcp.BlobResourceContents{
+			URI:      resource.URI,
+			MIMEType: resource.MIMEType,
+			Blob:     resource.Blob,
+		})
+	}
+	return mcp.NewEmbeddedResource(mcp.TextResourceContents{
+		URI:      resource.URI,
+		MIMEType: resource.MIMEType,
+		Text:     resource.Text,
+	})
+}
+
 // NewToolResultJSON creates a new tool result with JSON content
 func NewToolResultJSON(data interface{}) (*ToolResult, error) {
 	jsonBytes, err := json.Marshal(data)
@@ -544,6 +873,14 @@ func NewToolResultText(text string) *ToolResult {
 	}
 }
 
+// NewToolResultRawText creates a new tool result with text that is not
+// JSON, such as CSV or NDJSON, even if it would parse as JSON
+func NewToolResultRawText(text string) *ToolResult {
+	result := NewToolResultText(text)
+	result.Raw = true
+	return result
+}
+
 // NewToolResultError creates a new tool result with an error
 func NewToolResultError(text string) *ToolResult {
 	return &ToolResult{
@@ -552,3 +889,38 @@ func NewToolResultError(text string) *ToolResult {
 		Content: nil,
 	}
 }
+
+// NewToolResultValidationError creates a new error tool result for a call
+// with invalid parameters, naming the invalid parameter if there is one
+func NewToolResultValidationError(text, field string) *ToolResult {
+	result := NewToolResultError(text)
+	result.Error = &ToolError{Code: ValidationErrorCode, Field: field}
+	return result
+}
+
+// NewToolResultImage creates a new tool result with text content followed
+// by an image, given as base64 encoded data
+func NewToolResultImage(text, data, mimeType string) *ToolResult {
+	return NewToolResultContent(text,
+		ImageContent{Data: data, MIMEType: mimeType})
+}
+
+// NewToolResultContent creates a new tool result with text content followed
+// by the given content blocks
+func NewToolResultContent(text string, content ...interface{}) *ToolResult {
+	return &ToolResult{
+		Text:    text,
+		IsError: false,
+		Content: content,
+	}
+}
+
+// NewBlobResource returns an embedded resource with binary contents, which
+// it base64 encodes
+func NewBlobResource(uri, mimeType string, data []byte) EmbeddedResource {
+	return EmbeddedResource{
+		URI:      uri,
+		MIMEType: mimeType,
+		Blob:     base64.StdEncoding.EncodeToString(data),
+	}
+}
@@ -0,0 +1,185 @@
+//nolint:lll
+package mcpgo
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ListToolsVerboseToolName is the name of the tool that describes the
+// tools of the server
+const ListToolsVerboseToolName = "list_tools_verbose"
+
+// toolCatalogOption is the option value that registers the
+// list_tools_verbose tool
+type toolCatalogOption bool
+
+// WithToolCatalog returns a server option that registers the
+// list_tools_verbose tool, which describes the tools of the server for
+// agents to discover them
+func WithToolCatalog() ServerOption {
+	return func(s OptionSetter) error {
+		return s.SetOption(toolCatalogOption(true))
+	}
+}
+
+// toolCatalog is the result of the list_tools_verbose tool
+type toolCatalog struct {
+	Tools []ToolInfo `json:"tools" description:"Tools available to the session"`
+	Count int        `json:"count" description:"Number of tools listed"`
+}
+
+// ToolInfo describes a tool available to a session
+type ToolInfo struct {
+	Name               string      `json:"name" description:"Name of the tool"`
+	Description        string      `json:"description" description:"Description of the tool"`
+	Toolset            string      `json:"toolset,omitempty" description:"Toolset the tool is registered from, empty for tools of the server itself"`
+	ReadOnly           bool        `json:"read_only" description:"Whether the tool only reads data"`
+	InputSchema        interface{} `json:"input_schema" description:"JSON schema of the arguments of the tool"`
+	RequiredParameters []string    `json:"required_parameters" description:"Parameters every call must set"`
+	Example            ToolExample `json:"example" description:"Example call with the required parameters"`
+}
+
+// ToolExample is an example call of a tool
+type ToolExample struct {
+	Name      string                 `json:"name" description:"Name of the tool to call"`
+	Arguments map[string]interface{} `json:"arguments" description:"Arguments of the call"`
+}
+
+// recordToolset records the toolset a tool is registered from
+func (s *Mark3labsImpl) recordToolset(name, toolset string) {
+	if toolset == "" {
+		return
+	}
+
+	s.toolsetsMu.Lock()
+	defer s.toolsetsMu.Unlock()
+	if s.toolsets == nil {
+		s.toolsets = make(map[string]string)
+	}
+	s.toolsets[name] = toolset
+}
+
+// toolsetOf returns the toolset a tool is registered from, if known
+func (s *Mark3labsImpl) toolsetOf(name string) string {
+	s.toolsetsMu.Lock()
+	defer s.toolsetsMu.Unlock()
+	return s.toolsets[name]
+}
+
+// listToolsVerboseTool returns the list_tools_verbose tool
+func (s *Mark3labsImpl) listToolsVerboseTool() Tool {
+	tool := NewTool(ListToolsVerboseToolName,
+		"Lists the tools available to this session with their parameter "+
+			"schemas, required parameters, read-only classification, "+
+			"toolset and an example call. Useful to discover tools and "+
+			"route requests to them.",
+		[]ToolParameter{
+			WithString("toolset",
+				Description("Optional: Only list the tools of this toolset")),
+			WithBoolean("read_only",
+				Description("Optional: Only list read-only tools if true, "+
+					"or tools that modify data if false")),
+		},
+		s.listToolsVerbose,
+	).WithOutputSchema(SchemaFromStruct(toolCatalog{})).WithoutCache()
+	tool.SetReadOnly(true)
+	return tool
+}
+
+// listToolsVerbose describes the tools the session can call, as listed by
+// tools/list, so that description overrides and read-only sessions apply
+func (s *Mark3labsImpl) listToolsVerbose(
+	ctx context.Context,
+	r CallToolRequest,
+) (*ToolResult, error) {
+	args, _ := r.Arguments.(map[string]interface{})
+	toolset, _ := args["toolset"].(string)
+	readOnly, filterReadOnly := args["read_only"].(bool)
+
+	registered := s.McpServer.ListTools()
+	tools := make([]mcp.Tool, 0, len(registered))
+	for _, serverTool := range registered {
+		tools = append(tools, serverTool.Tool)
+	}
+	tools = s.filterWriteTools(ctx, tools)
+	sort.Slice(tools, func(i, j int) bool {
+		return tools[i].Name < tools[j].Name
+	})
+
+	catalog := toolCatalog{Tools: []ToolInfo{}}
+	for _, tool := range tools {
+		info := s.toolInfo(tool)
+		if toolset != "" && info.Toolset != toolset ||
+			filterReadOnly && info.ReadOnly != readOnly {
+			continue
+		}
+		catalog.Tools = append(catalog.Tools, info)
+	}
+	catalog.Count = len(catalog.Tools)
+
+	return NewToolResultJSON(catalog)
+}
+
+// toolInfo describes a tool
+func (s *Mark3labsImpl) toolInfo(tool mcp.Tool) ToolInfo {
+	var inputSchema interface{} = tool.InputSchema
+	if len(tool.RawInputSchema) > 0 {
+		inputSchema = json.RawMessage(tool.RawInputSchema)
+	}
+	required := append([]string{}, tool.InputSchema.Required...)
+	sort.Strings(required)
+
+	arguments := make(map[string]interface{}, len(required))
+	for _, name := range required {
+		property, _ := tool.InputSchema.Properties[name].(map[string]any)
+		arguments[name] = exampleValue(name, property)
+	}
+
+	return ToolInfo{
+		Name:               tool.Name,
+		Description:        tool.Description,
+		Toolset:            s.toolsetOf(tool.Name),
+		ReadOnly:           isReadOnlyTool(tool),
+		InputSchema:        inputSchema,
+		RequiredParameters: required,
+		Example:            ToolExample{Name: tool.Name, Arguments: arguments},
+	}
+}
+
+// exampleValue returns an example value of a parameter, taken from its
+// default or allowed values when it has them
+func exampleValue(name string, property map[string]any) interface{} {
+	if value, ok := property["default"]; ok {
+		return value
+	}
+	switch values := property["enum"].(type) {
+	case []string:
+		if len(values) > 0 {
+			return values[0]
+		}
+	case []interface{}:
+		if len(values) > 0 {
+			return values[0]
+		}
+	}
+
+	switch property["type"] {
+	case "number", "integer":
+		if minimum, ok := property["minimum"]; ok {
+			return minimum
+		}
+		return 1
+	case "boolean":
+		return true
+	case "object":
+		return map[string]interface{}{}
+	case "array":
+		return []interface{}{}
+	default:
+		return "<" + name + ">"
+	}
+}
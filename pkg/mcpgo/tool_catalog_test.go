@@ -0,0 +1,159 @@
+package mcpgo
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
+)
+
+// newToolCatalogTestServer creates a server with the tool catalog, a read
+// tool and a write tool registered from toolsets
+func newToolCatalogTestServer(opts ...ServerOption) *Mark3labsImpl {
+	handler := func(
+		ctx context.Context,
+		r CallToolRequest,
+	) (*ToolResult, error) {
+		return NewToolResultText("done"), nil
+	}
+
+	readTool := NewTool("fetch_thing", "Fetches a thing", []ToolParameter{
+		WithString("thing_id", Required()),
+		WithNumber("count", Min(1)),
+	}, handler)
+	readTool.SetReadOnly(true)
+	readTool.SetToolset("things")
+	writeTool := NewTool("create_thing", "Creates a thing", []ToolParameter{
+		WithNumber("amount", Required(), Min(100)),
+		WithString("kind", Required(), Enum("small", "large")),
+		WithBoolean("notify", Required()),
+		WithString("currency", Required(), DefaultValue("INR")),
+	}, handler)
+	writeTool.SetReadOnly(false)
+	writeTool.SetToolset("writes")
+
+	srv := NewMcpServer("test-server", "1.0.0", append([]ServerOption{
+		WithToolCapabilities(true),
+		WithToolCatalog(),
+	}, opts...)...)
+	srv.AddTools(readTool, writeTool)
+
+	return srv
+}
+
+// listToolsVerbose calls the list_tools_verbose tool with the arguments
+func listToolsVerbose(
+	t *testing.T,
+	ctx context.Context,
+	srv *Mark3labsImpl,
+	arguments string,
+) map[string]ToolInfo {
+	t.Helper()
+
+	response := srv.McpServer.HandleMessage(ctx, json.RawMessage(
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":`+
+			`{"name":"list_tools_verbose","arguments":`+arguments+`}}`))
+	resp, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok, "unexpected response %v", response)
+	result, ok := resp.Result.(mcp.CallToolResult)
+	require.True(t, ok)
+	require.False(t, result.IsError)
+	assert.NotNil(t, result.StructuredContent)
+
+	var catalog toolCatalog
+	require.NoError(t, json.Unmarshal(
+		[]byte(result.Content[0].(mcp.TextContent).Text), &catalog))
+	assert.Equal(t, len(catalog.Tools), catalog.Count)
+
+	tools := make(map[string]ToolInfo, len(catalog.Tools))
+	for _, tool := range catalog.Tools {
+		tools[tool.Name] = tool
+	}
+	return tools
+}
+
+func TestToolCatalog(t *testing.T) {
+	t.Run("is not registered by default", func(t *testing.T) {
+		srv := NewMcpServer("test-server", "1.0.0")
+
+		assert.Nil(t, srv.McpServer.GetTool(ListToolsVerboseToolName))
+	})
+
+	t.Run("describes every tool", func(t *testing.T) {
+		srv := newToolCatalogTestServer()
+
+		tools := listToolsVerbose(t, context.Background(), srv, "{}")
+		assert.Len(t, tools, 3)
+
+		fetch := tools["fetch_thing"]
+		assert.Equal(t, "Fetches a thing", fetch.Description)
+		assert.Equal(t, "things", fetch.Toolset)
+		assert.True(t, fetch.ReadOnly)
+		assert.Equal(t, []string{"thing_id"}, fetch.RequiredParameters)
+		assert.Equal(t, ToolExample{
+			Name:      "fetch_thing",
+			Arguments: map[string]interface{}{"thing_id": "<thing_id>"},
+		}, fetch.Example)
+		schema, ok := fetch.InputSchema.(map[string]interface{})
+		require.True(t, ok)
+		assert.Contains(t, schema["properties"], "count")
+
+		create := tools["create_thing"]
+		assert.Equal(t, "writes", create.Toolset)
+		assert.False(t, create.ReadOnly)
+		assert.Equal(t, []string{"amount", "currency", "kind", "notify"},
+			create.RequiredParameters)
+		assert.Equal(t, map[string]interface{}{
+			"amount":   float64(100),
+			"currency": "INR",
+			"kind":     "small",
+			"notify":   true,
+		}, create.Example.Arguments)
+
+		catalog := tools[ListToolsVerboseToolName]
+		assert.Empty(t, catalog.Toolset)
+		assert.True(t, catalog.ReadOnly)
+	})
+
+	t.Run("filters by toolset and classification", func(t *testing.T) {
+		srv := newToolCatalogTestServer()
+		ctx := context.Background()
+
+		tools := listToolsVerbose(t, ctx, srv, `{"toolset":"things"}`)
+		assert.Equal(t, []string{"fetch_thing"}, toolNames(tools))
+
+		tools = listToolsVerbose(t, ctx, srv, `{"read_only":false}`)
+		assert.Equal(t, []string{"create_thing"}, toolNames(tools))
+	})
+
+	t.Run("lists only read tools in read-only mode", func(t *testing.T) {
+		srv := newToolCatalogTestServer()
+		ctx := contextkey.WithReadOnly(context.Background(), true)
+
+		tools := listToolsVerbose(t, ctx, srv, "{}")
+		assert.ElementsMatch(t,
+			[]string{"fetch_thing", ListToolsVerboseToolName}, toolNames(tools))
+	})
+
+	t.Run("reflects description overrides", func(t *testing.T) {
+		srv := newToolCatalogTestServer(WithToolDescriptions(
+			map[string]string{"fetch_thing": "Looks up a thing"}))
+
+		tools := listToolsVerbose(t, context.Background(), srv, "{}")
+		assert.Equal(t, "Looks up a thing", tools["fetch_thing"].Description)
+	})
+}
+
+// toolNames returns the names of the tools
+func toolNames(tools map[string]ToolInfo) []string {
+	names := make([]string, 0, len(tools))
+	for name := range tools {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,141 @@
+package mcpgo
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Codes of the errors of tool calls other than Razorpay API errors, which
+// keep the code the API returned, such as BAD_REQUEST_ERROR
+const (
+	// ValidationErrorCode identifies the error of a call with invalid
+	// parameters
+	ValidationErrorCode = "VALIDATION_ERROR"
+	// NetworkErrorCode identifies the error of a call whose API request
+	// could not be sent or got no response
+	NetworkErrorCode = "NETWORK_ERROR"
+	// ToolErrorCode identifies every other error of a call
+	ToolErrorCode = "TOOL_ERROR"
+)
+
+// ToolError is the structured error of a failed tool call, returned in the
+// structured content of its result under "error", so that clients can act
+// on the code of the error instead of its description
+type ToolError struct {
+	Code        string `json:"code"`
+	Tool        string `json:"tool"`
+	Description string `json:"description"`
+	// Source, Step and Reason locate the failure of a payment, such as
+	// the bank declining it during authentication
+	Source string `json:"source,omitempty"`
+	Step   string `json:"step,omitempty"`
+	Reason string `json:"reason,omitempty"`
+	// Field is the parameter of the API request that was invalid
+	Field      string `json:"field,omitempty"`
+	HTTPStatus int    `json:"http_status,omitempty"`
+	// Retryable is set when repeating the call may succeed, such as after
+	// a gateway or network error
+	Retryable bool `json:"retryable"`
+}
+
+// ToolErrors keeps the structured error of a tool call, recorded while
+// the call runs, such as from the response of a failed API request
+type ToolErrors struct {
+	mu  sync.Mutex
+	err *ToolError
+}
+
+// Record records the error, replacing the error recorded before
+func (e *ToolErrors) Record(err ToolError) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.err = &err
+}
+
+// Last returns the error recorded last, or nil if none was recorded
+func (e *ToolErrors) Last() *ToolError {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.err
+}
+
+// toolErrorsKey is the context key of the ToolErrors of a tool call
+type toolErrorsKey struct{}
+
+// ContextWithToolErrors returns a new context that records the errors of
+// a tool call in errors
+func ContextWithToolErrors(
+	ctx context.Context,
+	errors *ToolErrors,
+) context.Context {
+	return context.WithValue(ctx, toolErrorsKey{}, errors)
+}
+
+// ToolErrorsFromContext returns the ToolErrors of a tool call, or nil if
+// the errors of the call are not recorded
+func ToolErrorsFromContext(ctx context.Context) *ToolErrors {
+	errors, _ := ctx.Value(toolErrorsKey{}).(*ToolErrors)
+	return errors
+}
+
+// withToolErrors wraps the handler of a tool so that its error results
+// carry a structured error: the error recorded during the call, or a
+// TOOL_ERROR with the description of the result. Results that already
+// carry structured content, such as READ_ONLY_MODE errors, are kept.
+func withToolErrors(serverTool server.ServerTool) server.ServerTool {
+	handler := serverTool.Handler
+	serverTool.Handler = func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		errors := &ToolErrors{}
+		result, err := handler(ContextWithToolErrors(ctx, errors), req)
+		if err != nil || result == nil || !result.IsError ||
+			result.StructuredContent != nil {
+			return result, err
+		}
+
+		toolErr := ToolError{Code: ToolErrorCode}
+		if recorded := errors.Last(); recorded != nil {
+			toolErr = *recorded
+		}
+		toolErr.Tool = req.Params.Name
+		toolErr.Description = resultText(result)
+		result.StructuredContent = toolErrorContent(toolErr)
+
+		return result, nil
+	}
+
+	return serverTool
+}
+
+// toolErrorContent returns the structured content of an error result
+func toolErrorContent(toolErr ToolError) map[string]interface{} {
+	// A ToolError only holds strings, numbers and booleans, so it always
+	// marshals
+	data, _ := json.Marshal(toolErr)
+
+	var content map[string]interface{}
+	_ = json.Unmarshal(data, &content)
+	return map[string]interface{}{"error": content}
+}
+
+// validationError returns the error result of a call with an invalid
+// parameter, recorded as a validation error of the field
+func validationError(
+	ctx context.Context,
+	field string,
+	text string,
+) *mcp.CallToolResult {
+	if toolErrors := ToolErrorsFromContext(ctx); toolErrors != nil {
+		toolErrors.Record(ToolError{
+			Code:  ValidationErrorCode,
+			Field: field,
+		})
+	}
+	return mcp.NewToolResultError(text)
+}
@@ -0,0 +1,144 @@
+package mcpgo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newToolErrorTestServer creates a server with a tool running the handler
+func newToolErrorTestServer(handler ToolHandler) *Mark3labsImpl {
+	tool := NewTool("fetch_thing", "Fetches a thing", nil, handler)
+	tool.SetReadOnly(true)
+
+	srv := NewMcpServer("test-server", "1.0.0", WithToolCapabilities(true))
+	srv.AddTools(tool)
+
+	return srv
+}
+
+func TestToolErrors(t *testing.T) {
+	t.Run("describes unclassified errors", func(t *testing.T) {
+		srv := newToolErrorTestServer(func(
+			ctx context.Context,
+			r CallToolRequest,
+		) (*ToolResult, error) {
+			return NewToolResultError("fetching thing failed"), nil
+		})
+
+		result := callTool(t, context.Background(), srv, "fetch_thing")
+
+		assert.True(t, result.IsError)
+		assert.Equal(t, "fetching thing failed", resultText(result))
+		assert.Equal(t, map[string]interface{}{
+			"error": map[string]interface{}{
+				"code":        ToolErrorCode,
+				"tool":        "fetch_thing",
+				"description": "fetching thing failed",
+				"retryable":   false,
+			},
+		}, result.StructuredContent)
+	})
+
+	t.Run("describes validation errors", func(t *testing.T) {
+		srv := newToolErrorTestServer(func(
+			ctx context.Context,
+			r CallToolRequest,
+		) (*ToolResult, error) {
+			return NewToolResultValidationError("invalid thing_id: x",
+				"thing_id"), nil
+		})
+
+		result := callTool(t, context.Background(), srv, "fetch_thing")
+
+		assert.Equal(t, map[string]interface{}{
+			"error": map[string]interface{}{
+				"code":        ValidationErrorCode,
+				"tool":        "fetch_thing",
+				"description": "invalid thing_id: x",
+				"field":       "thing_id",
+				"retryable":   false,
+			},
+		}, result.StructuredContent)
+	})
+
+	t.Run("describes recorded errors", func(t *testing.T) {
+		srv := newToolErrorTestServer(func(
+			ctx context.Context,
+			r CallToolRequest,
+		) (*ToolResult, error) {
+			toolErrors := ToolErrorsFromContext(ctx)
+			require.NotNil(t, toolErrors)
+			toolErrors.Record(ToolError{Code: NetworkErrorCode})
+			toolErrors.Record(ToolError{
+				Code:       "BAD_REQUEST_ERROR",
+				Source:     "customer",
+				Step:       "payment_authentication",
+				Reason:     "payment_cancelled",
+				HTTPStatus: 400,
+			})
+			return NewToolResultError("fetching thing failed: " +
+				"Payment was cancelled"), nil
+		})
+
+		result := callTool(t, context.Background(), srv, "fetch_thing")
+
+		assert.Equal(t, map[string]interface{}{
+			"error": map[string]interface{}{
+				"code":        "BAD_REQUEST_ERROR",
+				"tool":        "fetch_thing",
+				"description": "fetching thing failed: Payment was cancelled",
+				"source":      "customer",
+				"step":        "payment_authentication",
+				"reason":      "payment_cancelled",
+				"http_status": float64(400),
+				"retryable":   false,
+			},
+		}, result.StructuredContent)
+	})
+
+	t.Run("ignores errors of successful calls", func(t *testing.T) {
+		srv := newToolErrorTestServer(func(
+			ctx context.Context,
+			r CallToolRequest,
+		) (*ToolResult, error) {
+			ToolErrorsFromContext(ctx).Record(ToolError{
+				Code:      NetworkErrorCode,
+				Retryable: true,
+			})
+			return NewToolResultText("done"), nil
+		})
+
+		result := callTool(t, context.Background(), srv, "fetch_thing")
+
+		assert.False(t, result.IsError)
+		assert.Nil(t, result.StructuredContent)
+	})
+
+	t.Run("keeps structured errors", func(t *testing.T) {
+		tool := NewTool("create_thing", "Creates a thing", nil, func(
+			ctx context.Context,
+			r CallToolRequest,
+		) (*ToolResult, error) {
+			return NewToolResultText("done"), nil
+		})
+		tool.SetReadOnly(false)
+		srv := NewMcpServer("test-server", "1.0.0",
+			WithToolCapabilities(true), WithReadOnly(true))
+		srv.AddTools(tool)
+
+		result := callTool(t, context.Background(), srv, "create_thing")
+
+		content, ok := result.StructuredContent.(map[string]interface{})
+		require.True(t, ok)
+		details, ok := content["error"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, ReadOnlyErrorCode, details["code"])
+	})
+
+	t.Run("records nothing outside tool calls", func(t *testing.T) {
+		assert.Nil(t, ToolErrorsFromContext(context.Background()))
+	})
+}
@@ -0,0 +1,121 @@
+package mcpgo
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// toolDescriptionsOption is the option value that overrides descriptions of
+// tools, keyed by tool name
+type toolDescriptionsOption map[string]string
+
+// WithToolDescriptions returns a server option that replaces the
+// descriptions of the named tools, so that they can be tuned for a model
+// without changing the tools
+func WithToolDescriptions(descriptions map[string]string) ServerOption {
+	return func(s OptionSetter) error {
+		return s.SetOption(toolDescriptionsOption(descriptions))
+	}
+}
+
+// toolsetDefaultsOption is the option value that sets parameter defaults
+// of the tools of toolsets, keyed by toolset and parameter name
+type toolsetDefaultsOption map[string]map[string]interface{}
+
+// WithToolsetDefaults returns a server option that sets default values of
+// parameters for the tools of a toolset. A default applies to the tools of
+// the toolset that declare the parameter: the parameter becomes optional
+// and calls that leave it out get the default.
+func WithToolsetDefaults(
+	defaults map[string]map[string]interface{},
+) ServerOption {
+	return func(s OptionSetter) error {
+		return s.SetOption(toolsetDefaultsOption(defaults))
+	}
+}
+
+// applyToolOverrides applies the configured description to a tool
+func (s *Mark3labsImpl) applyToolOverrides(
+	serverTool server.ServerTool,
+) server.ServerTool {
+	if description, ok := s.descriptions[serverTool.Tool.Name]; ok {
+		serverTool.Tool.Description = description
+	}
+	return serverTool
+}
+
+// withToolsetDefaults applies the configured parameter defaults of the
+// toolset to a tool registered from it. It wraps the amount unit, amount
+// checks and policy of the server, so that they check the arguments the
+// tool runs with, such as a default currency.
+func (s *Mark3labsImpl) withToolsetDefaults(
+	serverTool server.ServerTool,
+	toolset string,
+) server.ServerTool {
+	return withParameterDefaults(serverTool, s.toolsetDefaults[toolset])
+}
+
+// withParameterDefaults sets the defaults of the parameters the tool
+// declares, in its input schema and in calls that leave them out
+func withParameterDefaults(
+	serverTool server.ServerTool,
+	defaults map[string]interface{},
+) server.ServerTool {
+	schema := serverTool.Tool.InputSchema
+	applied := make(map[string]interface{})
+	properties := make(map[string]any, len(schema.Properties))
+	for name, property := range schema.Properties {
+		properties[name] = property
+		value, ok := defaults[name]
+		if !ok {
+			continue
+		}
+		propertySchema, ok := property.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		withDefault := make(map[string]any, len(propertySchema)+1)
+		for key, item := range propertySchema {
+			withDefault[key] = item
+		}
+		withDefault["default"] = value
+		properties[name] = withDefault
+		applied[name] = value
+	}
+	if len(applied) == 0 {
+		return serverTool
+	}
+
+	required := make([]string, 0, len(schema.Required))
+	for _, name := range schema.Required {
+		if _, ok := applied[name]; !ok {
+			required = append(required, name)
+		}
+	}
+	schema.Properties = properties
+	schema.Required = required
+	serverTool.Tool.InputSchema = schema
+
+	handler := serverTool.Handler
+	serverTool.Handler = func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		args := make(map[string]any, len(applied))
+		for name, value := range req.GetArguments() {
+			args[name] = value
+		}
+		for name, value := range applied {
+			if _, ok := args[name]; !ok {
+				args[name] = value
+			}
+		}
+		req.Params.Arguments = args
+		return handler(ctx, req)
+	}
+
+	return serverTool
+}
@@ -0,0 +1,118 @@
+package mcpgo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newOverridesTestServer creates a server with a tool of the orders toolset
+// that echoes its arguments
+func newOverridesTestServer(opts ...ServerOption) *Mark3labsImpl {
+	tool := NewTool("create_thing", "Creates a thing",
+		[]ToolParameter{
+			WithString("currency", Required()),
+			WithString("name", Required()),
+		},
+		func(ctx context.Context, r CallToolRequest) (*ToolResult, error) {
+			return NewToolResultJSON(r.Arguments)
+		})
+	tool.SetReadOnly(false)
+	tool.SetToolset("orders")
+
+	srv := NewMcpServer("test-server", "1.0.0",
+		append([]ServerOption{WithToolCapabilities(true)}, opts...)...)
+	srv.AddTools(tool)
+
+	return srv
+}
+
+func TestToolOverrides(t *testing.T) {
+	t.Run("leaves tools unchanged by default", func(t *testing.T) {
+		srv := newOverridesTestServer()
+
+		tool := srv.McpServer.GetTool("create_thing")
+		require.NotNil(t, tool)
+		assert.Equal(t, "Creates a thing", tool.Tool.Description)
+		assert.ElementsMatch(t, []string{"currency", "name"},
+			tool.Tool.InputSchema.Required)
+	})
+
+	t.Run("overrides tool descriptions", func(t *testing.T) {
+		srv := newOverridesTestServer(WithToolDescriptions(
+			map[string]string{"create_thing": "Creates a shiny thing"}))
+
+		tool := srv.McpServer.GetTool("create_thing")
+		require.NotNil(t, tool)
+		assert.Equal(t, "Creates a shiny thing", tool.Tool.Description)
+	})
+
+	t.Run("applies toolset parameter defaults", func(t *testing.T) {
+		srv := newOverridesTestServer(WithToolsetDefaults(
+			map[string]map[string]interface{}{
+				"orders":   {"currency": "USD", "capture": "manual"},
+				"payments": {"name": "ignored"},
+			}))
+
+		tool := srv.McpServer.GetTool("create_thing")
+		require.NotNil(t, tool)
+		assert.Equal(t, []string{"name"}, tool.Tool.InputSchema.Required)
+		properties := tool.Tool.InputSchema.Properties
+		property, ok := properties["currency"].(map[string]any)
+		require.True(t, ok)
+		assert.Equal(t, "USD", property["default"])
+		assert.NotContains(t, properties, "capture")
+
+		call := func(args string) map[string]interface{} {
+			response := srv.McpServer.HandleMessage(context.Background(),
+				json.RawMessage(`{"jsonrpc":"2.0","id":1,`+
+					`"method":"tools/call","params":{"name":"create_thing",`+
+					`"arguments":`+args+`}}`))
+			resp, ok := response.(mcp.JSONRPCResponse)
+			require.True(t, ok, "unexpected response %v", response)
+			result, ok := resp.Result.(mcp.CallToolResult)
+			require.True(t, ok)
+			require.False(t, result.IsError)
+
+			var echoed map[string]interface{}
+			text, ok := mcp.AsTextContent(result.Content[0])
+			require.True(t, ok)
+			require.NoError(t, json.Unmarshal([]byte(text.Text), &echoed))
+			return echoed
+		}
+
+		assert.Equal(t, map[string]interface{}{
+			"currency": "USD", "name": "a",
+		}, call(`{"name":"a"}`))
+		assert.Equal(t, map[string]interface{}{
+			"currency": "INR", "name": "a",
+		}, call(`{"name":"a","currency":"INR"}`))
+	})
+	t.Run("checks the policy against the defaults", func(t *testing.T) {
+		srv := newOverridesTestServer(
+			WithToolsetDefaults(map[string]map[string]interface{}{
+				"orders": {"currency": "USD"},
+			}),
+			WithPolicy(func(
+				toolName string,
+				args map[string]interface{},
+			) string {
+				if args["currency"] != "INR" {
+					return fmt.Sprintf("currency %v is not allowed",
+						args["currency"])
+				}
+				return ""
+			}))
+
+		result := callToolWithArgs(t, srv, "create_thing", `{"name":"a"}`)
+
+		assert.True(t, result.IsError)
+		assert.Equal(t, "tool create_thing violates the server policy: "+
+			"currency USD is not allowed", resultText(result))
+	})
+}
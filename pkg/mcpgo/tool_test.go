@@ -7,6 +7,8 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
 )
 
 func TestNewTool(t *testing.T) {
@@ -35,6 +37,17 @@ func TestNewTool(t *testing.T) {
 	})
 }
 
+func TestMark3labsToolImpl_GetName(t *testing.T) {
+	t.Run("returns name", func(t *testing.T) {
+		handler := func(
+			ctx context.Context, req CallToolRequest) (*ToolResult, error) {
+			return NewToolResultText("success"), nil
+		}
+		tool := NewTool("test-tool", "Test", []ToolParameter{}, handler)
+		assert.Equal(t, "test-tool", tool.GetName())
+	})
+}
+
 func TestMark3labsToolImpl_GetHandler(t *testing.T) {
 	t.Run("returns handler", func(t *testing.T) {
 		handler := func(
@@ -46,6 +59,100 @@ func TestMark3labsToolImpl_GetHandler(t *testing.T) {
 	})
 }
 
+func TestMark3labsToolImpl_WithOutputSchema(t *testing.T) {
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"id": map[string]interface{}{"type": "string"},
+		},
+	}
+
+	callTool := func(
+		t *testing.T,
+		tool *mark3labsToolImpl,
+	) *mcp.CallToolResult {
+		t.Helper()
+
+		result, err := tool.toMCPServerTool().Handler(
+			context.Background(), mcp.CallToolRequest{})
+		assert.NoError(t, err)
+		return result
+	}
+
+	t.Run("declares output schema", func(t *testing.T) {
+		tool := NewTool("test-tool", "Test", []ToolParameter{},
+			func(ctx context.Context, req CallToolRequest) (*ToolResult, error) {
+				return NewToolResultJSON(map[string]interface{}{"id": "1"})
+			}).WithOutputSchema(schema)
+
+		mcpTool := tool.toMCPServerTool().Tool
+		assert.JSONEq(t,
+			`{"type":"object","properties":{"id":{"type":"string"}}}`,
+			string(mcpTool.RawOutputSchema))
+	})
+
+	t.Run("returns structured content", func(t *testing.T) {
+		tool := NewTool("test-tool", "Test", []ToolParameter{},
+			func(ctx context.Context, req CallToolRequest) (*ToolResult, error) {
+				return NewToolResultJSON(map[string]interface{}{"id": "1"})
+			}).WithOutputSchema(schema)
+
+		result := callTool(t, tool)
+		assert.False(t, result.IsError)
+		assert.Equal(t, map[string]interface{}{"id": "1"},
+			result.StructuredContent)
+		assert.Equal(t, mcp.NewTextContent(`{"id":"1"}`), result.Content[0])
+	})
+
+	t.Run("returns text without output schema", func(t *testing.T) {
+		tool := NewTool("test-tool", "Test", []ToolParameter{},
+			func(ctx context.Context, req CallToolRequest) (*ToolResult, error) {
+				return NewToolResultJSON(map[string]interface{}{"id": "1"})
+			})
+
+		result := callTool(t, tool)
+		assert.Nil(t, tool.toMCPServerTool().Tool.RawOutputSchema)
+		assert.Nil(t, result.StructuredContent)
+	})
+
+	t.Run("returns text for non-object results", func(t *testing.T) {
+		tool := NewTool("test-tool", "Test", []ToolParameter{},
+			func(ctx context.Context, req CallToolRequest) (*ToolResult, error) {
+				return NewToolResultText("done"), nil
+			}).WithOutputSchema(schema)
+
+		result := callTool(t, tool)
+		assert.Nil(t, result.StructuredContent)
+	})
+
+	t.Run("returns raw text without structured content",
+		func(t *testing.T) {
+			tool := NewTool("test-tool", "Test", []ToolParameter{},
+				func(
+					ctx context.Context,
+					req CallToolRequest,
+				) (*ToolResult, error) {
+					return NewToolResultRawText(`{"id":"1"}` + "\n"), nil
+				}).WithOutputSchema(schema)
+
+			result := callTool(t, tool)
+			assert.Nil(t, result.StructuredContent)
+			assert.Equal(t, mcp.NewTextContent(`{"id":"1"}`+"\n"),
+				result.Content[0])
+		})
+
+	t.Run("returns errors without structured content", func(t *testing.T) {
+		tool := NewTool("test-tool", "Test", []ToolParameter{},
+			func(ctx context.Context, req CallToolRequest) (*ToolResult, error) {
+				return NewToolResultError(`{"error":"failed"}`), nil
+			}).WithOutputSchema(schema)
+
+		result := callTool(t, tool)
+		assert.True(t, result.IsError)
+		assert.Nil(t, result.StructuredContent)
+	})
+}
+
 func TestMark3labsToolImpl_ToMCPServerTool(t *testing.T) {
 	t.Run("converts tool with string parameter", func(t *testing.T) {
 		tool := NewTool(
@@ -388,6 +495,62 @@ func TestPropertyOption_Pattern(t *testing.T) {
 	})
 }
 
+func TestPropertyOption_Base64(t *testing.T) {
+	t.Run("sets encoding and maxLength for string", func(t *testing.T) {
+		schema := map[string]interface{}{"type": "string"}
+		Base64(1024)(schema)
+		assert.Equal(t, "base64", schema["contentEncoding"])
+		assert.Equal(t, 1368, schema["maxLength"])
+	})
+
+	t.Run("ignores for non-string type", func(t *testing.T) {
+		schema := map[string]interface{}{"type": "object"}
+		Base64(1024)(schema)
+		assert.NotContains(t, schema, "contentEncoding")
+		assert.NotContains(t, schema, "maxLength")
+	})
+
+	t.Run("declares encoding in input schema", func(t *testing.T) {
+		tool := NewTool(
+			"test-tool",
+			"Test",
+			[]ToolParameter{WithString("file", Base64(1024))},
+			func(ctx context.Context, req CallToolRequest) (*ToolResult, error) {
+				return NewToolResultText("success"), nil
+			},
+		)
+		mcpTool := tool.toMCPServerTool()
+		properties := mcpTool.Tool.InputSchema.Properties
+		property, ok := properties["file"].(map[string]any)
+		assert.True(t, ok)
+		assert.Equal(t, "base64", property["contentEncoding"])
+		assert.Equal(t, 1368, property["maxLength"])
+	})
+}
+
+func TestDecodeBase64(t *testing.T) {
+	t.Run("decodes content", func(t *testing.T) {
+		data, err := DecodeBase64("aGVsbG8gd29y\nbGQ=", 11)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("hello world"), data)
+	})
+
+	t.Run("rejects content larger than the limit", func(t *testing.T) {
+		_, err := DecodeBase64("aGVsbG8gd29ybGQ=", 10)
+		assert.EqualError(t, err, "content is larger than 10 bytes")
+	})
+
+	t.Run("rejects long encoded content before decoding", func(t *testing.T) {
+		_, err := DecodeBase64("aGVsbG8gd29ybGQ=aGVsbG8gd29ybGQ=", 11)
+		assert.EqualError(t, err, "content is larger than 11 bytes")
+	})
+
+	t.Run("rejects invalid base64", func(t *testing.T) {
+		_, err := DecodeBase64("not base64!", 100)
+		assert.EqualError(t, err, "content is not valid base64")
+	})
+}
+
 func TestPropertyOption_Enum(t *testing.T) {
 	t.Run("sets enum values", func(t *testing.T) {
 		schema := map[string]interface{}{}
@@ -951,6 +1114,93 @@ func TestNewToolResultError(t *testing.T) {
 	})
 }
 
+func TestNewToolResultImage(t *testing.T) {
+	t.Run("creates image result", func(t *testing.T) {
+		result := NewToolResultImage("QR code", "aW1hZ2U=", "image/png")
+		assert.Equal(t, "QR code", result.Text)
+		assert.False(t, result.IsError)
+		assert.Equal(t, []interface{}{
+			ImageContent{Data: "aW1hZ2U=", MIMEType: "image/png"},
+		}, result.Content)
+	})
+
+	t.Run("returns the image after the text", func(t *testing.T) {
+		tool := NewTool("test-tool", "Test", []ToolParameter{},
+			func(ctx context.Context, req CallToolRequest) (*ToolResult, error) {
+				return NewToolResultImage(`{"id":"1"}`, "aW1hZ2U=",
+					"image/png"), nil
+			}).WithOutputSchema(map[string]interface{}{"type": "object"})
+
+		result, err := tool.toMCPServerTool().Handler(
+			context.Background(), mcp.CallToolRequest{})
+		assert.NoError(t, err)
+		assert.False(t, result.IsError)
+		assert.Equal(t, map[string]interface{}{"id": "1"},
+			result.StructuredContent)
+		assert.Equal(t, []mcp.Content{
+			mcp.NewTextContent(`{"id":"1"}`),
+			mcp.NewImageContent("aW1hZ2U=", "image/png"),
+		}, result.Content)
+	})
+}
+
+func TestNewToolResultContent(t *testing.T) {
+	t.Run("creates multi-part result", func(t *testing.T) {
+		link := ResourceLink{URI: "file:///tmp/recon.csv", Name: "recon.csv"}
+		result := NewToolResultContent("done", TextContent{Text: "more"},
+			link)
+		assert.Equal(t, "done", result.Text)
+		assert.False(t, result.IsError)
+		assert.Equal(t, []interface{}{TextContent{Text: "more"}, link},
+			result.Content)
+	})
+
+	t.Run("converts every content block", func(t *testing.T) {
+		tool := NewTool("test-tool", "Test", []ToolParameter{},
+			func(ctx context.Context, req CallToolRequest) (*ToolResult, error) {
+				return NewToolResultContent("done",
+					TextContent{Text: "more"},
+					AudioContent{Data: "YXVkaW8=", MIMEType: "audio/wav"},
+					ResourceLink{
+						URI:         "file:///tmp/recon.csv",
+						Name:        "recon.csv",
+						Description: "Settlement recon",
+						MIMEType:    "text/csv",
+					},
+					EmbeddedResource{
+						URI:      "file:///tmp/recon.csv",
+						MIMEType: "text/csv",
+						Text:     "id,amount",
+					},
+					NewBlobResource("file:///tmp/receipt.pdf",
+						"application/pdf", []byte("%PDF")),
+					"unknown",
+				), nil
+			})
+
+		result, err := tool.toMCPServerTool().Handler(
+			context.Background(), mcp.CallToolRequest{})
+		assert.NoError(t, err)
+		assert.Equal(t, []mcp.Content{
+			mcp.NewTextContent("done"),
+			mcp.NewTextContent("more"),
+			mcp.NewAudioContent("YXVkaW8=", "audio/wav"),
+			mcp.NewResourceLink("file:///tmp/recon.csv", "recon.csv",
+				"Settlement recon", "text/csv"),
+			mcp.NewEmbeddedResource(mcp.TextResourceContents{
+				URI:      "file:///tmp/recon.csv",
+				MIMEType: "text/csv",
+				Text:     "id,amount",
+			}),
+			mcp.NewEmbeddedResource(mcp.BlobResourceContents{
+				URI:      "file:///tmp/receipt.pdf",
+				MIMEType: "application/pdf",
+				Blob:     "JVBERg==",
+			}),
+		}, result.Content)
+	})
+}
+
 func TestSetReadOnly(t *testing.T) {
 	t.Run("sets read-only flag to true", func(t *testing.T) {
 		handler := func(
@@ -983,6 +1233,17 @@ func TestSetReadOnly(t *testing.T) {
 	})
 }
 
+func TestSetToolset(t *testing.T) {
+	tool := NewTool("test-tool", "Test description", []ToolParameter{},
+		func(ctx context.Context, req CallToolRequest) (*ToolResult, error) {
+			return NewToolResultText("success"), nil
+		})
+	assert.Empty(t, tool.toolset())
+
+	tool.SetToolset("payments")
+	assert.Equal(t, "payments", tool.toolset())
+}
+
 func TestToolAnnotations(t *testing.T) {
 	t.Run("read-only tool has correct annotations", func(t *testing.T) {
 		handler := func(
@@ -1030,3 +1291,142 @@ func TestToolAnnotations(t *testing.T) {
 		assert.NotNil(t, mcpTool.Tool)
 	})
 }
+
+func TestMark3labsToolImpl_ParameterOptionsCache(t *testing.T) {
+	handler := func(
+		ctx context.Context, req CallToolRequest) (*ToolResult, error) {
+		return NewToolResultText("success"), nil
+	}
+
+	t.Run("repeated conversions return equivalent schemas", func(t *testing.T) {
+		tool := NewTool(
+			"test-tool",
+			"Test",
+			[]ToolParameter{
+				WithString("id", Required(), Pattern("^pay_")),
+				WithNumber("amount", Min(100)),
+				WithArray("tags", Items(map[string]interface{}{
+					"type": "string",
+				})),
+			},
+			handler,
+		)
+
+		first := tool.toMCPServerTool()
+		second := tool.toMCPServerTool()
+
+		assert.Equal(t, first.Tool.InputSchema, second.Tool.InputSchema)
+		assert.Equal(t, []string{"id"}, second.Tool.InputSchema.Required)
+		// the declared parameters plus the strict, dry run and idempotency
+		// key parameters
+		assert.Len(t, second.Tool.InputSchema.Properties, 6)
+	})
+
+	t.Run("annotations follow read-only changes", func(t *testing.T) {
+		tool := NewTool("test-tool", "Test",
+			[]ToolParameter{WithString("id")}, handler)
+
+		tool.SetReadOnly(true)
+		readTool := tool.toMCPServerTool()
+		tool.SetReadOnly(false)
+		writeTool := tool.toMCPServerTool()
+
+		assert.True(t, *readTool.Tool.Annotations.ReadOnlyHint)
+		assert.False(t, *writeTool.Tool.Annotations.ReadOnlyHint)
+		// only write tools accept the dry run and idempotency key parameters
+		writeProperties := writeTool.Tool.InputSchema.Properties
+		for _, name := range []string{DryRunParameter, IdempotencyKeyParameter} {
+			assert.Contains(t, writeProperties, name)
+			delete(writeProperties, name)
+		}
+		assert.Equal(t, readTool.Tool.InputSchema, writeTool.Tool.InputSchema)
+	})
+
+	t.Run("cache does not leak across distinct tools", func(t *testing.T) {
+		toolA := NewTool("tool-a", "A",
+			[]ToolParameter{WithString("a", Required())}, handler)
+		toolB := NewTool("tool-b", "B",
+			[]ToolParameter{WithNumber("b")}, handler)
+
+		schemaA := toolA.toMCPServerTool().Tool.InputSchema
+		schemaB := toolB.toMCPServerTool().Tool.InputSchema
+
+		assert.Contains(t, schemaA.Properties, "a")
+		assert.NotContains(t, schemaA.Properties, "b")
+		assert.Contains(t, schemaB.Properties, "b")
+		assert.NotContains(t, schemaB.Properties, "a")
+		assert.Empty(t, schemaB.Required)
+	})
+}
+
+func TestMark3labsToolImpl_StrictParams(t *testing.T) {
+	var received CallToolRequest
+	tool := NewTool(
+		"test-tool",
+		"Test",
+		[]ToolParameter{WithString("id"), WithNumber("amount")},
+		func(ctx context.Context, req CallToolRequest) (*ToolResult, error) {
+			received = req
+			return NewToolResultText("success"), nil
+		},
+	)
+	serverTool := tool.toMCPServerTool()
+
+	call := func(ctx context.Context, args map[string]interface{}) {
+		req := mcp.CallToolRequest{}
+		req.Params.Name = "test-tool"
+		req.Params.Arguments = args
+		_, err := serverTool.Handler(ctx, req)
+		assert.NoError(t, err)
+	}
+
+	t.Run("declares strict parameter", func(t *testing.T) {
+		assert.Contains(t,
+			serverTool.Tool.InputSchema.Properties, StrictParameter)
+	})
+
+	t.Run("passes declared parameter names", func(t *testing.T) {
+		call(context.Background(), map[string]interface{}{"id": "pay_1"})
+		assert.Equal(t, []string{"id", "amount"}, received.Parameters)
+		assert.False(t, received.Strict)
+	})
+
+	t.Run("strict from context", func(t *testing.T) {
+		ctx := contextkey.WithStrictParams(context.Background(), true)
+		call(ctx, map[string]interface{}{"id": "pay_1"})
+		assert.True(t, received.Strict)
+	})
+
+	t.Run("strict argument overrides context", func(t *testing.T) {
+		ctx := contextkey.WithStrictParams(context.Background(), true)
+		call(ctx, map[string]interface{}{"strict": false})
+		assert.False(t, received.Strict)
+
+		call(context.Background(), map[string]interface{}{"strict": true})
+		assert.True(t, received.Strict)
+	})
+}
+
+func BenchmarkMark3labsToolImpl_ToMCPServerTool(b *testing.B) {
+	tool := NewTool(
+		"bench-tool",
+		"Benchmark",
+		[]ToolParameter{
+			WithString("id", Required(), Pattern("^pay_")),
+			WithNumber("amount", Min(100), Max(1000000)),
+			WithObject("notes", MaxProperties(15)),
+			WithArray("expand", Items(map[string]interface{}{
+				"type": "string",
+				"enum": []interface{}{"card", "emi"},
+			})),
+		},
+		func(ctx context.Context, req CallToolRequest) (*ToolResult, error) {
+			return NewToolResultText("success"), nil
+		},
+	)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = tool.toMCPServerTool()
+	}
+}
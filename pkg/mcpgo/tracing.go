@@ -0,0 +1,59 @@
+package mcpgo
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+)
+
+// tracerOption is the option value that enables tool call tracing
+type tracerOption struct {
+	tracer trace.Tracer
+}
+
+// WithTracer returns a server option that runs every tool call in a span
+// created with the tracer
+func WithTracer(tracer trace.Tracer) ServerOption {
+	return func(s OptionSetter) error {
+		return s.SetOption(tracerOption{tracer: tracer})
+	}
+}
+
+// withToolTracing wraps the handler of a tool so that its calls run in a
+// span. Calls that return an error result mark the span as failed.
+func withToolTracing(
+	tracer trace.Tracer,
+	serverTool server.ServerTool,
+	toolset string,
+) server.ServerTool {
+	handler := serverTool.Handler
+	readOnly := isReadOnlyTool(serverTool.Tool)
+	serverTool.Handler = func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		ctx, span := tracer.Start(ctx, "tools/call "+req.Params.Name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(observability.ToolSpanAttributes(
+				req.Params.Name, toolset, readOnly, req.GetArguments())...),
+		)
+		defer span.End()
+
+		result, err := handler(ctx, req)
+		switch {
+		case err != nil:
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		case result == nil || result.IsError:
+			span.SetStatus(codes.Error, "tool call failed")
+		}
+		return result, err
+	}
+
+	return serverTool
+}
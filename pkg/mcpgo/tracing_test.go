@@ -0,0 +1,77 @@
+package mcpgo
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestToolTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	var handlerSpan trace.SpanContext
+	fetchTool := NewTool("fetch_thing", "Fetches a thing",
+		[]ToolParameter{WithString("thing_id")},
+		func(ctx context.Context, r CallToolRequest) (*ToolResult, error) {
+			handlerSpan = trace.SpanContextFromContext(ctx)
+			return NewToolResultText("thing"), nil
+		})
+	fetchTool.SetReadOnly(true)
+	fetchTool.SetToolset("things")
+	failingTool := NewTool("update_thing", "Updates a thing", nil,
+		func(ctx context.Context, r CallToolRequest) (*ToolResult, error) {
+			return NewToolResultError("thing not found"), nil
+		})
+	failingTool.SetReadOnly(false)
+
+	srv := NewMcpServer("test-server", "1.0.0",
+		WithToolCapabilities(true), WithTracer(tracer))
+	srv.AddTools(fetchTool, failingTool)
+
+	call := func(name, arguments string) {
+		response := srv.McpServer.HandleMessage(context.Background(),
+			json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"tools/call",`+
+				`"params":{"name":"`+name+`","arguments":`+arguments+`}}`))
+		_, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok, "unexpected response %v", response)
+	}
+
+	t.Run("runs tool calls in a span", func(t *testing.T) {
+		call("fetch_thing", `{"thing_id":"thing_1"}`)
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 1)
+		span := spans[0]
+		assert.Equal(t, "tools/call fetch_thing", span.Name())
+		assert.Equal(t, trace.SpanKindServer, span.SpanKind())
+		assert.Equal(t, span.SpanContext(), handlerSpan)
+		assert.Subset(t, span.Attributes(), []attribute.KeyValue{
+			attribute.String("gen_ai.tool.name", "fetch_thing"),
+			attribute.String("razorpay.toolset", "things"),
+			attribute.Bool("mcp.tool.read_only", true),
+			attribute.String("razorpay.thing_id", "thing_1"),
+		})
+		assert.Equal(t, codes.Unset, span.Status().Code)
+	})
+
+	t.Run("marks failed tool calls", func(t *testing.T) {
+		call("update_thing", `{}`)
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 2)
+		assert.Equal(t, codes.Error, spans[1].Status().Code)
+		assert.Contains(t, spans[1].Attributes(),
+			attribute.Bool("mcp.tool.read_only", false))
+	})
+}
@@ -0,0 +1,128 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ProtectedResourceMetadataPath is the well-known path the protected
+// resource metadata (RFC 9728) is served under
+const ProtectedResourceMetadataPath = "/.well-known/oauth-protected-resource"
+
+// claimsKey is the context key the validated token claims are stored under
+type claimsKey struct{}
+
+// WithClaims returns a new context with the validated token claims attached
+func WithClaims(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// ClaimsFromContext returns the validated token claims of a request.
+// Returns nil if the request was not authenticated.
+func ClaimsFromContext(ctx context.Context) *Claims {
+	claims, _ := ctx.Value(claimsKey{}).(*Claims)
+	return claims
+}
+
+// TokenValidator validates bearer tokens
+type TokenValidator interface {
+	// Validate returns the claims of a valid token
+	Validate(ctx context.Context, token string) (*Claims, error)
+}
+
+// MetadataURL returns the URL of the protected resource metadata of a
+// resource, which RFC 9728 places under the well-known path of its host
+func MetadataURL(resource string) (string, error) {
+	parsed, err := url.Parse(resource)
+	if err != nil {
+		return "", fmt.Errorf("invalid resource url: %w", err)
+	}
+	if parsed.Scheme == "" || parsed.Host == "" {
+		return "", errors.New("invalid resource url: must be absolute")
+	}
+
+	metadata := url.URL{
+		Scheme: parsed.Scheme,
+		Host:   parsed.Host,
+		Path: ProtectedResourceMetadataPath +
+			strings.TrimSuffix(parsed.Path, "/"),
+	}
+
+	return metadata.String(), nil
+}
+
+// Middleware only lets requests with a valid bearer token through, and
+// adds the token claims to their context. Other requests are rejected
+// with a challenge pointing clients at the protected resource metadata,
+// as the MCP authorization spec requires.
+func Middleware(
+	validator TokenValidator,
+	metadataURL string,
+	next http.Handler,
+) http.Handler {
+	challenge := fmt.Sprintf(`Bearer resource_metadata=%q`, metadataURL)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := bearerToken(r)
+		if !ok {
+			w.Header().Set("WWW-Authenticate", challenge)
+			http.Error(w, "missing bearer token", http.StatusUnauthorized)
+			return
+		}
+
+		claims, err := validator.Validate(r.Context(), token)
+		if err != nil {
+			status := http.StatusUnauthorized
+			if !errors.Is(err, ErrInvalidToken) {
+				// The token could not be checked, for example because
+				// the signing keys could not be fetched
+				status = http.StatusServiceUnavailable
+			}
+			w.Header().Set("WWW-Authenticate",
+				challenge+`, error="invalid_token"`)
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithClaims(r.Context(), claims)))
+	})
+}
+
+// ProtectedResourceMetadataHandler serves the protected resource metadata
+// clients use to discover the authorization server
+func ProtectedResourceMetadataHandler(
+	resource string,
+	authorizationServers ...string,
+) http.Handler {
+	metadata := map[string]interface{}{
+		"resource":                 resource,
+		"authorization_servers":    authorizationServers,
+		"bearer_methods_supported": []string{"header"},
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(metadata)
+	})
+}
+
+// bearerToken returns the token of a bearer Authorization header
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
@@ -0,0 +1,162 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMetadataURL = "https://mcp.example.com" +
+	ProtectedResourceMetadataPath + "/mcp"
+
+// validatorFunc adapts a function to the TokenValidator interface
+type validatorFunc func(ctx context.Context, token string) (*Claims, error)
+
+func (f validatorFunc) Validate(
+	ctx context.Context,
+	token string,
+) (*Claims, error) {
+	return f(ctx, token)
+}
+
+func TestMetadataURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		resource    string
+		expected    string
+		expectedErr string
+	}{
+		{
+			name:     "resource with a path",
+			resource: "https://mcp.example.com/mcp",
+			expected: testMetadataURL,
+		},
+		{
+			name:     "resource at the root",
+			resource: "https://mcp.example.com/",
+			expected: "https://mcp.example.com" +
+				ProtectedResourceMetadataPath,
+		},
+		{
+			name:        "relative resource",
+			resource:    "/mcp",
+			expectedErr: "invalid resource url: must be absolute",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			metadataURL, err := MetadataURL(tc.resource)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, metadataURL)
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	validator := validatorFunc(
+		func(ctx context.Context, token string) (*Claims, error) {
+			switch token {
+			case "valid":
+				return &Claims{Subject: "acc_Aa00000000001", Token: token}, nil
+			case "unverifiable":
+				return nil, errors.New("fetching jwks failed")
+			default:
+				return nil, ErrInvalidToken
+			}
+		})
+
+	var claims *Claims
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims = ClaimsFromContext(r.Context())
+	})
+	handler := Middleware(validator, testMetadataURL, next)
+
+	serve := func(authorization string) *httptest.ResponseRecorder {
+		claims = nil
+		r := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		if authorization != "" {
+			r.Header.Set("Authorization", authorization)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("passes the claims of a valid token on", func(t *testing.T) {
+		w := serve("Bearer valid")
+		assert.Equal(t, http.StatusOK, w.Code)
+		require.NotNil(t, claims)
+		assert.Equal(t, "acc_Aa00000000001", claims.Subject)
+	})
+
+	t.Run("challenges requests without a token", func(t *testing.T) {
+		w := serve("")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Equal(t,
+			`Bearer resource_metadata="`+testMetadataURL+`"`,
+			w.Header().Get("WWW-Authenticate"))
+		assert.Nil(t, claims)
+	})
+
+	t.Run("challenges basic auth", func(t *testing.T) {
+		w := serve("Basic a2V5OnNlY3JldA==")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Nil(t, claims)
+	})
+
+	t.Run("rejects invalid tokens", func(t *testing.T) {
+		w := serve("Bearer forged")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Equal(t,
+			`Bearer resource_metadata="`+testMetadataURL+
+				`", error="invalid_token"`,
+			w.Header().Get("WWW-Authenticate"))
+		assert.Nil(t, claims)
+	})
+
+	t.Run("reports tokens that cannot be checked", func(t *testing.T) {
+		w := serve("Bearer unverifiable")
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Nil(t, claims)
+	})
+}
+
+func TestProtectedResourceMetadataHandler(t *testing.T) {
+	handler := ProtectedResourceMetadataHandler(
+		"https://mcp.example.com/mcp", "https://auth.example.com")
+
+	t.Run("serves the metadata", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet,
+			ProtectedResourceMetadataPath+"/mcp", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+		var metadata map[string]interface{}
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&metadata))
+		assert.Equal(t, map[string]interface{}{
+			"resource":                 "https://mcp.example.com/mcp",
+			"authorization_servers":    []interface{}{"https://auth.example.com"},
+			"bearer_methods_supported": []interface{}{"header"},
+		}, metadata)
+	})
+
+	t.Run("rejects other methods", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost,
+			ProtectedResourceMetadataPath, nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+}
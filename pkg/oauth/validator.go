@@ -0,0 +1,294 @@
+package oauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInvalidToken is returned for bearer tokens that fail validation
+var ErrInvalidToken = errors.New("invalid token")
+
+// jwksRefreshInterval is the minimum time between two fetches of the
+// signing keys, which bounds the fetches triggered by unknown key IDs
+const jwksRefreshInterval = time.Minute
+
+// Config configures the validation of bearer tokens
+type Config struct {
+	// Issuer is the expected iss claim, the URL of the authorization server
+	Issuer string
+	// Audience is the expected aud claim, usually the URL of the resource
+	Audience string
+	// JWKSURL serves the keys tokens are signed with. Defaults to
+	// /.well-known/jwks.json under the issuer.
+	JWKSURL string
+	// Leeway is the clock skew allowed when checking exp and nbf
+	Leeway time.Duration
+}
+
+// Claims are the validated claims of a bearer token
+type Claims struct {
+	Issuer    string   `json:"iss"`
+	Subject   string   `json:"sub"`
+	Audience  audience `json:"aud"`
+	ExpiresAt int64    `json:"exp"`
+	NotBefore int64    `json:"nbf"`
+	Scope     string   `json:"scope"`
+
+	// Token is the bearer token the claims were read from
+	Token string `json:"-"`
+}
+
+// audience is the aud claim, which is either a string or a list of strings
+type audience []string
+
+// UnmarshalJSON implements json.Unmarshaler
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return err
+	}
+	*a = multiple
+
+	return nil
+}
+
+// Validator validates RS256 signed JWT bearer tokens against the signing
+// keys published by the authorization server
+type Validator struct {
+	config     Config
+	httpClient *http.Client
+	now        func() time.Time
+
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+// NewValidator creates a new token validator
+func NewValidator(config Config) (*Validator, error) {
+	if config.Issuer == "" {
+		return nil, errors.New("oauth issuer is required")
+	}
+	if config.Audience == "" {
+		return nil, errors.New("oauth audience is required")
+	}
+	if config.JWKSURL == "" {
+		config.JWKSURL = strings.TrimSuffix(config.Issuer, "/") +
+			"/.well-known/jwks.json"
+	}
+
+	return &Validator{
+		config:     config,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		now:        time.Now,
+	}, nil
+}
+
+// Issuer returns the authorization server tokens must be issued by
+func (v *Validator) Issuer() string {
+	return v.config.Issuer
+}
+
+// Validate checks the signature, issuer, audience and lifetime of a
+// bearer token and returns its claims
+func (v *Validator) Validate(
+	ctx context.Context,
+	token string,
+) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidToken)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: malformed header", ErrInvalidToken)
+	}
+	if header.Alg != "RS256" {
+		return nil, fmt.Errorf("%w: unsupported algorithm %q",
+			ErrInvalidToken, header.Alg)
+	}
+
+	key, err := v.signingKey(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalidToken)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	err = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature)
+	if err != nil {
+		return nil, fmt.Errorf("%w: bad signature", ErrInvalidToken)
+	}
+
+	claims := &Claims{Token: token}
+	if err := decodeSegment(parts[1], claims); err != nil {
+		return nil, fmt.Errorf("%w: malformed claims", ErrInvalidToken)
+	}
+	if err := v.validateClaims(claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// validateClaims checks the issuer, audience and lifetime of a token
+func (v *Validator) validateClaims(claims *Claims) error {
+	if claims.Issuer != v.config.Issuer {
+		return fmt.Errorf("%w: unexpected issuer %q",
+			ErrInvalidToken, claims.Issuer)
+	}
+	if !slices.Contains(claims.Audience, v.config.Audience) {
+		return fmt.Errorf("%w: token is not intended for this server",
+			ErrInvalidToken)
+	}
+
+	now := v.now()
+	if claims.ExpiresAt == 0 {
+		return fmt.Errorf("%w: missing expiry", ErrInvalidToken)
+	}
+	if now.After(time.Unix(claims.ExpiresAt, 0).Add(v.config.Leeway)) {
+		return fmt.Errorf("%w: token expired", ErrInvalidToken)
+	}
+	notBefore := time.Unix(claims.NotBefore, 0).Add(-v.config.Leeway)
+	if claims.NotBefore != 0 && now.Before(notBefore) {
+		return fmt.Errorf("%w: token not valid yet", ErrInvalidToken)
+	}
+
+	return nil
+}
+
+// signingKey returns the key with the given ID, fetching the keys again
+// if it is unknown. A token without a key ID may only be signed with the
+// sole published key.
+func (v *Validator) signingKey(
+	ctx context.Context,
+	kid string,
+) (*rsa.PublicKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if key := v.lookupKey(kid); key != nil {
+		return key, nil
+	}
+
+	if v.keys != nil && v.now().Sub(v.fetchedAt) < jwksRefreshInterval {
+		return nil, fmt.Errorf("%w: unknown signing key", ErrInvalidToken)
+	}
+
+	keys, err := v.fetchKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	v.keys = keys
+	v.fetchedAt = v.now()
+
+	if key := v.lookupKey(kid); key != nil {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("%w: unknown signing key", ErrInvalidToken)
+}
+
+// lookupKey returns a cached signing key. The caller must hold the lock.
+func (v *Validator) lookupKey(kid string) *rsa.PublicKey {
+	if kid == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key
+		}
+	}
+
+	return v.keys[kid]
+}
+
+// fetchKeys fetches the RSA signing keys published by the issuer
+func (v *Validator) fetchKeys(
+	ctx context.Context,
+) (map[string]*rsa.PublicKey, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, v.config.JWKSURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating jwks request failed: %w", err)
+	}
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching jwks failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching jwks failed: status %d",
+			resp.StatusCode)
+	}
+
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&jwks); err != nil {
+		return nil, fmt.Errorf("decoding jwks failed: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, jwk := range jwks.Keys {
+		if jwk.Kty != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			continue
+		}
+
+		keys[jwk.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	return keys, nil
+}
+
+// decodeSegment decodes a base64url encoded JSON segment of a token
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,329 @@
+package oauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testIssuer   = "https://auth.example.com"
+	testAudience = "https://mcp.example.com/mcp"
+	testKeyID    = "key-1"
+)
+
+// testAuthServer publishes a signing key and signs tokens with it
+type testAuthServer struct {
+	key     *rsa.PrivateKey
+	server  *httptest.Server
+	fetches atomic.Int32
+}
+
+func newTestAuthServer(t *testing.T) *testAuthServer {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	as := &testAuthServer{key: key}
+	as.server = httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			as.fetches.Add(1)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"keys": []map[string]interface{}{
+					{
+						"kty": "RSA",
+						"kid": testKeyID,
+						"use": "sig",
+						"n": base64.RawURLEncoding.EncodeToString(
+							key.N.Bytes()),
+						"e": base64.RawURLEncoding.EncodeToString(
+							big.NewInt(int64(key.E)).Bytes()),
+					},
+				},
+			})
+		}))
+	t.Cleanup(as.server.Close)
+
+	return as
+}
+
+// sign returns a token with the given header and claims
+func (as *testAuthServer) sign(
+	t *testing.T,
+	header map[string]interface{},
+	claims map[string]interface{},
+) string {
+	t.Helper()
+
+	encode := func(v interface{}) string {
+		data, err := json.Marshal(v)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+
+	signingInput := encode(header) + "." + encode(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(
+		rand.Reader, as.key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+
+	return signingInput + "." +
+		base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// token returns a valid token with the given claims overridden
+func (as *testAuthServer) token(
+	t *testing.T,
+	overrides map[string]interface{},
+) string {
+	t.Helper()
+
+	claims := map[string]interface{}{
+		"iss":   testIssuer,
+		"sub":   "acc_Aa00000000001",
+		"aud":   testAudience,
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"scope": "read_write",
+	}
+	for name, value := range overrides {
+		claims[name] = value
+	}
+
+	header := map[string]interface{}{"alg": "RS256", "kid": testKeyID}
+	return as.sign(t, header, claims)
+}
+
+func (as *testAuthServer) validator(t *testing.T) *Validator {
+	t.Helper()
+
+	validator, err := NewValidator(Config{
+		Issuer:   testIssuer,
+		Audience: testAudience,
+		JWKSURL:  as.server.URL,
+	})
+	require.NoError(t, err)
+
+	return validator
+}
+
+func TestNewValidator(t *testing.T) {
+	t.Run("defaults the jwks url to the issuer", func(t *testing.T) {
+		validator, err := NewValidator(Config{
+			Issuer:   testIssuer + "/",
+			Audience: testAudience,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, testIssuer+"/.well-known/jwks.json",
+			validator.config.JWKSURL)
+		assert.Equal(t, testIssuer+"/", validator.Issuer())
+	})
+
+	t.Run("requires an issuer", func(t *testing.T) {
+		_, err := NewValidator(Config{Audience: testAudience})
+		assert.EqualError(t, err, "oauth issuer is required")
+	})
+
+	t.Run("requires an audience", func(t *testing.T) {
+		_, err := NewValidator(Config{Issuer: testIssuer})
+		assert.EqualError(t, err, "oauth audience is required")
+	})
+}
+
+func TestValidator_Validate(t *testing.T) {
+	as := newTestAuthServer(t)
+
+	t.Run("returns the claims of a valid token", func(t *testing.T) {
+		token := as.token(t, nil)
+
+		claims, err := as.validator(t).Validate(context.Background(), token)
+		require.NoError(t, err)
+		assert.Equal(t, testIssuer, claims.Issuer)
+		assert.Equal(t, "acc_Aa00000000001", claims.Subject)
+		assert.Equal(t, audience{testAudience}, claims.Audience)
+		assert.Equal(t, "read_write", claims.Scope)
+		assert.Equal(t, token, claims.Token)
+	})
+
+	t.Run("accepts an audience list", func(t *testing.T) {
+		token := as.token(t, map[string]interface{}{
+			"aud": []string{"https://other.example.com", testAudience},
+		})
+
+		_, err := as.validator(t).Validate(context.Background(), token)
+		assert.NoError(t, err)
+	})
+
+	tests := []struct {
+		name        string
+		token       func(t *testing.T) string
+		expectedErr string
+	}{
+		{
+			name:        "malformed token",
+			token:       func(t *testing.T) string { return "not-a-token" },
+			expectedErr: "invalid token: malformed token",
+		},
+		{
+			name: "wrong issuer",
+			token: func(t *testing.T) string {
+				return as.token(t, map[string]interface{}{
+					"iss": "https://evil.example.com",
+				})
+			},
+			expectedErr: `invalid token: unexpected issuer ` +
+				`"https://evil.example.com"`,
+		},
+		{
+			name: "wrong audience",
+			token: func(t *testing.T) string {
+				return as.token(t, map[string]interface{}{
+					"aud": "https://other.example.com",
+				})
+			},
+			expectedErr: "invalid token: token is not intended for this " +
+				"server",
+		},
+		{
+			name: "expired token",
+			token: func(t *testing.T) string {
+				return as.token(t, map[string]interface{}{
+					"exp": time.Now().Add(-time.Hour).Unix(),
+				})
+			},
+			expectedErr: "invalid token: token expired",
+		},
+		{
+			name: "token without expiry",
+			token: func(t *testing.T) string {
+				return as.token(t, map[string]interface{}{"exp": 0})
+			},
+			expectedErr: "invalid token: missing expiry",
+		},
+		{
+			name: "token not valid yet",
+			token: func(t *testing.T) string {
+				return as.token(t, map[string]interface{}{
+					"nbf": time.Now().Add(time.Hour).Unix(),
+				})
+			},
+			expectedErr: "invalid token: token not valid yet",
+		},
+		{
+			name: "unsupported algorithm",
+			token: func(t *testing.T) string {
+				return as.sign(t,
+					map[string]interface{}{"alg": "none"},
+					map[string]interface{}{"iss": testIssuer})
+			},
+			expectedErr: `invalid token: unsupported algorithm "none"`,
+		},
+		{
+			name: "tampered claims",
+			token: func(t *testing.T) string {
+				parts := strings.Split(as.token(t, nil), ".")
+				other := strings.Split(as.token(t, map[string]interface{}{
+					"sub": "acc_Bb00000000001",
+				}), ".")
+				return parts[0] + "." + other[1] + "." + parts[2]
+			},
+			expectedErr: "invalid token: bad signature",
+		},
+		{
+			name: "unknown signing key",
+			token: func(t *testing.T) string {
+				return as.sign(t,
+					map[string]interface{}{"alg": "RS256", "kid": "key-2"},
+					map[string]interface{}{"iss": testIssuer})
+			},
+			expectedErr: "invalid token: unknown signing key",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := as.validator(t).Validate(
+				context.Background(), tc.token(t))
+			assert.ErrorIs(t, err, ErrInvalidToken)
+			assert.EqualError(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestValidator_SigningKeys(t *testing.T) {
+	t.Run("caches the signing keys", func(t *testing.T) {
+		as := newTestAuthServer(t)
+		validator := as.validator(t)
+
+		for i := 0; i < 3; i++ {
+			_, err := validator.Validate(context.Background(), as.token(t, nil))
+			require.NoError(t, err)
+		}
+		assert.Equal(t, int32(1), as.fetches.Load())
+	})
+
+	t.Run("limits fetches for unknown keys", func(t *testing.T) {
+		as := newTestAuthServer(t)
+		validator := as.validator(t)
+
+		unknown := as.sign(t,
+			map[string]interface{}{"alg": "RS256", "kid": "key-2"},
+			map[string]interface{}{"iss": testIssuer})
+		for i := 0; i < 3; i++ {
+			_, err := validator.Validate(context.Background(), unknown)
+			require.ErrorIs(t, err, ErrInvalidToken)
+		}
+		assert.Equal(t, int32(1), as.fetches.Load())
+
+		now := time.Now().Add(2 * jwksRefreshInterval)
+		validator.now = func() time.Time { return now }
+		_, err := validator.Validate(context.Background(), unknown)
+		require.ErrorIs(t, err, ErrInvalidToken)
+		assert.Equal(t, int32(2), as.fetches.Load())
+	})
+
+	t.Run("accepts tokens without a key id from a single key",
+		func(t *testing.T) {
+			as := newTestAuthServer(t)
+			token := as.sign(t, map[string]interface{}{"alg": "RS256"},
+				map[string]interface{}{
+					"iss": testIssuer,
+					"aud": testAudience,
+					"exp": time.Now().Add(time.Hour).Unix(),
+				})
+
+			_, err := as.validator(t).Validate(context.Background(), token)
+			assert.NoError(t, err)
+		})
+
+	t.Run("reports unavailable signing keys", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+
+		validator, err := NewValidator(Config{
+			Issuer:   testIssuer,
+			Audience: testAudience,
+			JWKSURL:  server.URL,
+		})
+		require.NoError(t, err)
+
+		as := newTestAuthServer(t)
+		_, err = validator.Validate(context.Background(), as.token(t, nil))
+		assert.EqualError(t, err, "fetching jwks failed: status 404")
+		assert.NotErrorIs(t, err, ErrInvalidToken)
+	})
+}
@@ -0,0 +1,238 @@
+package observability
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// metricsContentType is the content type of the Prometheus text format
+const metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// durationBuckets are the upper bounds, in seconds, of the tool call
+// duration histogram buckets
+var durationBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// Metrics collects the metrics of the server and serves them in the
+// Prometheus text format
+type Metrics struct {
+	mu            sync.Mutex
+	toolCalls     map[toolCallKey]uint64
+	toolDurations map[string]*histogram
+	apiRequests   map[apiRequestKey]uint64
+	// activeSessions returns the number of active sessions, nil if the
+	// transport has no sessions
+	activeSessions func() int
+}
+
+// toolCallKey identifies the tool calls counted together
+type toolCallKey struct {
+	tool   string
+	status string
+}
+
+// apiRequestKey identifies the Razorpay API requests counted together
+type apiRequestKey struct {
+	method string
+	code   string
+}
+
+// histogram counts observations in durationBuckets
+type histogram struct {
+	buckets []uint64
+	sum     float64
+	count   uint64
+}
+
+// NewMetrics creates an empty metrics collector
+func NewMetrics() *Metrics {
+	return &Metrics{
+		toolCalls:     make(map[toolCallKey]uint64),
+		toolDurations: make(map[string]*histogram),
+		apiRequests:   make(map[apiRequestKey]uint64),
+	}
+}
+
+// WithMetrics will set the metrics collector in Deps
+func WithMetrics(m *Metrics) Option {
+	return func(observe *Observability) {
+		observe.Metrics = m
+	}
+}
+
+// ObserveToolCall records a tool call, how long it took and whether it
+// failed
+func (m *Metrics) ObserveToolCall(
+	tool string,
+	duration time.Duration,
+	failed bool,
+) {
+	status := "success"
+	if failed {
+		status = "error"
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.toolCalls[toolCallKey{tool: tool, status: status}]++
+
+	h, ok := m.toolDurations[tool]
+	if !ok {
+		h = &histogram{buckets: make([]uint64, len(durationBuckets))}
+		m.toolDurations[tool] = h
+	}
+	seconds := duration.Seconds()
+	for i, bound := range durationBuckets {
+		if seconds <= bound {
+			h.buckets[i]++
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+// ObserveAPIRequest records a Razorpay API request and the status code of
+// its response, or "error" if it got no response
+func (m *Metrics) ObserveAPIRequest(method string, code string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.apiRequests[apiRequestKey{method: method, code: code}]++
+}
+
+// SetActiveSessionsFunc sets the function that reports the number of
+// active sessions
+func (m *Metrics) SetActiveSessionsFunc(fn func() int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.activeSessions = fn
+}
+
+// Transport returns a round tripper that records the requests sent through
+// next as Razorpay API requests
+func (m *Metrics) Transport(next http.RoundTripper) http.RoundTripper {
+	return &metricsTransport{next: next, metrics: m}
+}
+
+// metricsTransport records the requests it sends
+type metricsTransport struct {
+	next    http.RoundTripper
+	metrics *Metrics
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *metricsTransport) RoundTrip(
+	req *http.Request,
+) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		t.metrics.ObserveAPIRequest(req.Method, "error")
+		return resp, err
+	}
+	t.metrics.ObserveAPIRequest(req.Method, strconv.Itoa(resp.StatusCode))
+	return resp, nil
+}
+
+// Handler returns an http handler that serves the metrics
+func (m *Metrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", metricsContentType)
+		_ = m.write(w)
+	})
+}
+
+// write writes the metrics in the Prometheus text format, with series
+// sorted by their labels
+func (m *Metrics) write(w io.Writer) error {
+	m.mu.Lock()
+	activeSessions := m.activeSessions
+	var b strings.Builder
+
+	writeHeader(&b, "razorpay_mcp_tool_calls_total", "counter",
+		"Tool calls by tool and status.")
+	callKeys := make([]toolCallKey, 0, len(m.toolCalls))
+	for key := range m.toolCalls {
+		callKeys = append(callKeys, key)
+	}
+	sort.Slice(callKeys, func(i, j int) bool {
+		if callKeys[i].tool != callKeys[j].tool {
+			return callKeys[i].tool < callKeys[j].tool
+		}
+		return callKeys[i].status < callKeys[j].status
+	})
+	for _, key := range callKeys {
+		fmt.Fprintf(&b, "razorpay_mcp_tool_calls_total{tool=%s,status=%s} %d\n",
+			quote(key.tool), quote(key.status), m.toolCalls[key])
+	}
+
+	writeHeader(&b, "razorpay_mcp_tool_call_duration_seconds", "histogram",
+		"Duration of tool calls in seconds.")
+	tools := make([]string, 0, len(m.toolDurations))
+	for tool := range m.toolDurations {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
+	for _, tool := range tools {
+		h := m.toolDurations[tool]
+		for i, bound := range durationBuckets {
+			fmt.Fprintf(&b, "razorpay_mcp_tool_call_duration_seconds_bucket"+
+				"{tool=%s,le=%s} %d\n", quote(tool),
+				quote(strconv.FormatFloat(bound, 'g', -1, 64)), h.buckets[i])
+		}
+		fmt.Fprintf(&b, "razorpay_mcp_tool_call_duration_seconds_bucket"+
+			"{tool=%s,le=\"+Inf\"} %d\n", quote(tool), h.count)
+		fmt.Fprintf(&b, "razorpay_mcp_tool_call_duration_seconds_sum"+
+			"{tool=%s} %s\n", quote(tool),
+			strconv.FormatFloat(h.sum, 'g', -1, 64))
+		fmt.Fprintf(&b, "razorpay_mcp_tool_call_duration_seconds_count"+
+			"{tool=%s} %d\n", quote(tool), h.count)
+	}
+
+	writeHeader(&b, "razorpay_mcp_api_requests_total", "counter",
+		"Razorpay API requests by method and response status code, "+
+			"error if no response was received.")
+	requestKeys := make([]apiRequestKey, 0, len(m.apiRequests))
+	for key := range m.apiRequests {
+		requestKeys = append(requestKeys, key)
+	}
+	sort.Slice(requestKeys, func(i, j int) bool {
+		if requestKeys[i].method != requestKeys[j].method {
+			return requestKeys[i].method < requestKeys[j].method
+		}
+		return requestKeys[i].code < requestKeys[j].code
+	})
+	for _, key := range requestKeys {
+		fmt.Fprintf(&b, "razorpay_mcp_api_requests_total{method=%s,code=%s} %d\n",
+			quote(key.method), quote(key.code), m.apiRequests[key])
+	}
+	m.mu.Unlock()
+
+	// The session count is read without holding the lock, since the
+	// function takes locks of its own
+	if activeSessions != nil {
+		writeHeader(&b, "razorpay_mcp_active_sessions", "gauge",
+			"Number of active sessions.")
+		fmt.Fprintf(&b, "razorpay_mcp_active_sessions %d\n", activeSessions())
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeHeader writes the help and type lines of a metric
+func writeHeader(b *strings.Builder, name, metricType, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+// quote returns a label value quoted and escaped for the text format
+func quote(value string) string {
+	return `"` + strings.NewReplacer(
+		`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value) + `"`
+}
@@ -0,0 +1,115 @@
+package observability
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// roundTripFunc is a round tripper made of a function
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// scrape returns the metrics as served by the handler
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	m.Handler().ServeHTTP(recorder,
+		httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, metricsContentType, recorder.Header().Get("Content-Type"))
+
+	return recorder.Body.String()
+}
+
+func TestMetrics(t *testing.T) {
+	t.Run("serves empty metrics", func(t *testing.T) {
+		body := scrape(t, NewMetrics())
+
+		assert.Contains(t, body,
+			"# TYPE razorpay_mcp_tool_calls_total counter\n")
+		assert.NotContains(t, body, "razorpay_mcp_active_sessions")
+	})
+
+	t.Run("records tool calls", func(t *testing.T) {
+		m := NewMetrics()
+		m.ObserveToolCall("fetch_payment", 200*time.Millisecond, false)
+		m.ObserveToolCall("fetch_payment", 2*time.Second, true)
+		m.ObserveToolCall("create_order", 40*time.Millisecond, false)
+
+		body := scrape(t, m)
+
+		assert.Contains(t, body, strings.Join([]string{
+			`razorpay_mcp_tool_calls_total{tool="create_order",status="success"} 1`,
+			`razorpay_mcp_tool_calls_total{tool="fetch_payment",status="error"} 1`,
+			`razorpay_mcp_tool_calls_total{tool="fetch_payment",status="success"} 1`,
+		}, "\n"))
+		for _, series := range []string{
+			`_bucket{tool="fetch_payment",le="0.1"} 0`,
+			`_bucket{tool="fetch_payment",le="0.25"} 1`,
+			`_bucket{tool="fetch_payment",le="2.5"} 2`,
+			`_bucket{tool="fetch_payment",le="+Inf"} 2`,
+			`_sum{tool="fetch_payment"} 2.2`,
+			`_count{tool="fetch_payment"} 2`,
+		} {
+			assert.Contains(t, body,
+				"razorpay_mcp_tool_call_duration_seconds"+series+"\n")
+		}
+	})
+
+	t.Run("records api requests", func(t *testing.T) {
+		m := NewMetrics()
+		transport := m.Transport(roundTripFunc(
+			func(req *http.Request) (*http.Response, error) {
+				if req.URL.Path == "/down" {
+					return nil, errors.New("connection refused")
+				}
+				return &http.Response{StatusCode: http.StatusBadRequest}, nil
+			}))
+
+		for _, path := range []string{"/payments", "/payments", "/down"} {
+			req := httptest.NewRequest(http.MethodPost, path, nil)
+			_, _ = transport.RoundTrip(req)
+		}
+
+		body := scrape(t, m)
+		assert.Contains(t, body,
+			`razorpay_mcp_api_requests_total{method="POST",code="400"} 2`)
+		assert.Contains(t, body,
+			`razorpay_mcp_api_requests_total{method="POST",code="error"} 1`)
+	})
+
+	t.Run("reports active sessions", func(t *testing.T) {
+		m := NewMetrics()
+		m.SetActiveSessionsFunc(func() int { return 3 })
+
+		body := scrape(t, m)
+		assert.Contains(t, body, "# TYPE razorpay_mcp_active_sessions gauge\n"+
+			"razorpay_mcp_active_sessions 3\n")
+	})
+
+	t.Run("escapes label values", func(t *testing.T) {
+		m := NewMetrics()
+		m.ObserveToolCall("bad\"tool\\\n", time.Millisecond, false)
+
+		body := scrape(t, m)
+		assert.Contains(t, body, `{tool="bad\"tool\\\n",status="success"} 1`)
+	})
+}
+
+func TestWithMetrics(t *testing.T) {
+	m := NewMetrics()
+
+	obs := New(WithMetrics(m))
+	assert.Same(t, m, obs.Metrics)
+}
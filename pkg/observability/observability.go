@@ -1,6 +1,8 @@
 package observability
 
 import (
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/razorpay/razorpay-mcp-server/pkg/log"
 )
 
@@ -12,6 +14,13 @@ type Observability struct {
 	// Logger will be passed as dependency to other services
 	// which will help in pushing logs
 	Logger log.Logger
+
+	// Metrics collects the metrics of the server, nil if metrics are not
+	// collected
+	Metrics *Metrics
+
+	// Tracer creates the spans of tool calls, nil if tracing is disabled
+	Tracer trace.Tracer
 }
 
 // New will create a new Observability object and
@@ -0,0 +1,169 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the name of the tracer the spans of the server are
+// created with
+const TracerName = "github.com/razorpay/razorpay-mcp-server"
+
+// serviceName is the default service name of the exported spans
+const serviceName = "razorpay-mcp-server"
+
+// propagator carries the trace context to the Razorpay API in W3C trace
+// context headers
+var propagator = propagation.TraceContext{}
+
+// WithTracer will set the tracer in Deps
+func WithTracer(t trace.Tracer) Option {
+	return func(observe *Observability) {
+		observe.Tracer = t
+	}
+}
+
+// TracingConfigured reports whether an OTLP endpoint is configured in the
+// environment, which enables tracing. OTEL_SDK_DISABLED turns it off.
+func TracingConfigured() bool {
+	disabled, _ := strconv.ParseBool(os.Getenv("OTEL_SDK_DISABLED"))
+	if disabled {
+		return false
+	}
+	return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" ||
+		os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+// NewTracerProvider creates a tracer provider that exports spans over
+// OTLP/HTTP. The exporter is configured with the standard
+// OTEL_EXPORTER_OTLP_* environment variables, and OTEL_SERVICE_NAME and
+// OTEL_RESOURCE_ATTRIBUTES override the resource of the spans.
+func NewTracerProvider(
+	ctx context.Context,
+	version string,
+) (*sdktrace.TracerProvider, error) {
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create otlp exporter: %w", err)
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			semconv.ServiceName(serviceName),
+			semconv.ServiceVersion(version),
+		),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	), nil
+}
+
+// TracingTransport returns a round tripper that sends requests through
+// next in a client span, as children of the span in ctx, and passes the
+// trace context on in the request headers. It is meant for clients that
+// do not send requests with a context of their own, like the Razorpay SDK.
+func TracingTransport(
+	ctx context.Context,
+	next http.RoundTripper,
+) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &tracingTransport{ctx: ctx, next: next}
+}
+
+// tracingTransport sends requests in client spans
+type tracingTransport struct {
+	ctx  context.Context
+	next http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *tracingTransport) RoundTrip(
+	req *http.Request,
+) (*http.Response, error) {
+	tracer := trace.SpanFromContext(t.ctx).TracerProvider().Tracer(TracerName)
+	ctx, span := tracer.Start(t.ctx, req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(req.Method),
+			semconv.ServerAddress(req.URL.Hostname()),
+			// The path identifies the entity, while the query may carry
+			// customer details
+			semconv.URLPath(req.URL.Path),
+		),
+	)
+	defer span.End()
+
+	req = req.Clone(ctx)
+	propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return resp, err
+	}
+
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	return resp, nil
+}
+
+// ToolSpanAttributes returns the attributes of the span of a tool call:
+// the tool name, its toolset, whether it is read-only, and the Razorpay
+// entity IDs among its arguments
+func ToolSpanAttributes(
+	tool string,
+	toolset string,
+	readOnly bool,
+	arguments map[string]interface{},
+) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attribute.String("mcp.method.name", "tools/call"),
+		attribute.String("gen_ai.tool.name", tool),
+		attribute.Bool("mcp.tool.read_only", readOnly),
+	}
+	if toolset != "" {
+		attrs = append(attrs, attribute.String("razorpay.toolset", toolset))
+	}
+
+	for name, value := range arguments {
+		id, ok := value.(string)
+		if !ok || id == "" || !isEntityIDParameter(name) {
+			continue
+		}
+		attrs = append(attrs, attribute.String("razorpay."+name, id))
+	}
+
+	return attrs
+}
+
+// isEntityIDParameter reports whether a tool parameter holds the ID of a
+// Razorpay entity, such as id, payment_id or order_id
+func isEntityIDParameter(name string) bool {
+	return name == "id" ||
+		(strings.HasSuffix(name, "_id") && name != "_id")
+}
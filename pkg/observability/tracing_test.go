@@ -0,0 +1,183 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newTestTracer returns a tracer whose ended spans are recorded
+func newTestTracer() (trace.Tracer, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(recorder))
+	return provider.Tracer(TracerName), recorder
+}
+
+func TestTracingConfigured(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected bool
+	}{
+		{
+			name:     "no endpoint",
+			env:      map[string]string{},
+			expected: false,
+		},
+		{
+			name: "otlp endpoint",
+			env: map[string]string{
+				"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318",
+			},
+			expected: true,
+		},
+		{
+			name: "otlp traces endpoint",
+			env: map[string]string{
+				"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": "http://collector:4318/v1/traces",
+			},
+			expected: true,
+		},
+		{
+			name: "sdk disabled",
+			env: map[string]string{
+				"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318",
+				"OTEL_SDK_DISABLED":           "true",
+			},
+			expected: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, key := range []string{
+				"OTEL_EXPORTER_OTLP_ENDPOINT",
+				"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT",
+				"OTEL_SDK_DISABLED",
+			} {
+				t.Setenv(key, tc.env[key])
+			}
+
+			assert.Equal(t, tc.expected, TracingConfigured())
+		})
+	}
+}
+
+func TestNewTracerProvider(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://127.0.0.1:1")
+	t.Setenv("OTEL_SERVICE_NAME", "payments-mcp")
+
+	provider, err := NewTracerProvider(context.Background(), "1.2.3")
+	require.NoError(t, err)
+	defer func() { _ = provider.Shutdown(context.Background()) }()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider.RegisterSpanProcessor(recorder)
+	_, span := provider.Tracer(TracerName).Start(context.Background(), "test")
+	span.End()
+
+	require.Len(t, recorder.Ended(), 1)
+	attrs := recorder.Ended()[0].Resource().Attributes()
+	assert.Contains(t, attrs, attribute.String("service.name", "payments-mcp"))
+	assert.Contains(t, attrs, attribute.String("service.version", "1.2.3"))
+}
+
+func TestTracingTransport(t *testing.T) {
+	traceparents := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			traceparents <- r.Header.Get("Traceparent")
+			if r.URL.Path == "/v1/payments/pay_missing" {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	defer server.Close()
+
+	t.Run("sends requests in child spans", func(t *testing.T) {
+		tracer, recorder := newTestTracer()
+		ctx, parent := tracer.Start(context.Background(), "tools/call")
+		client := &http.Client{Transport: TracingTransport(ctx, nil)}
+
+		resp, err := client.Get(server.URL + "/v1/payments/pay_1?email=a@b.c")
+		require.NoError(t, err)
+		resp.Body.Close()
+		parent.End()
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 2)
+		span := spans[0]
+		assert.Equal(t, "GET", span.Name())
+		assert.Equal(t, trace.SpanKindClient, span.SpanKind())
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+		assert.Contains(t, span.Attributes(),
+			attribute.String("url.path", "/v1/payments/pay_1"))
+		assert.Contains(t, span.Attributes(),
+			attribute.Int("http.response.status_code", http.StatusOK))
+		assert.Equal(t, codes.Unset, span.Status().Code)
+		traceparent := <-traceparents
+		assert.Contains(t, traceparent, span.SpanContext().TraceID().String())
+		assert.Contains(t, traceparent, span.SpanContext().SpanID().String())
+	})
+
+	t.Run("marks error responses as failed", func(t *testing.T) {
+		tracer, recorder := newTestTracer()
+		ctx, parent := tracer.Start(context.Background(), "tools/call")
+		client := &http.Client{Transport: TracingTransport(ctx, nil)}
+
+		resp, err := client.Get(server.URL + "/v1/payments/pay_missing")
+		require.NoError(t, err)
+		resp.Body.Close()
+		parent.End()
+		<-traceparents
+
+		assert.Equal(t, codes.Error, recorder.Ended()[0].Status().Code)
+	})
+
+	t.Run("marks network errors as failed", func(t *testing.T) {
+		tracer, recorder := newTestTracer()
+		ctx, parent := tracer.Start(context.Background(), "tools/call")
+		transport := TracingTransport(ctx, roundTripFunc(
+			func(*http.Request) (*http.Response, error) {
+				return nil, errors.New("connection refused")
+			}))
+
+		req := httptest.NewRequest(http.MethodPost, "/v1/orders", nil)
+		_, err := transport.RoundTrip(req)
+		assert.Error(t, err)
+		parent.End()
+
+		span := recorder.Ended()[0]
+		assert.Equal(t, codes.Error, span.Status().Code)
+		assert.Equal(t, "connection refused", span.Status().Description)
+	})
+}
+
+func TestToolSpanAttributes(t *testing.T) {
+	attrs := ToolSpanAttributes("capture_payment", "payments", false,
+		map[string]interface{}{
+			"payment_id": "pay_1",
+			"amount":     1000,
+			"order_id":   "",
+			"_id":        "x",
+			"email":      "a@b.c",
+		})
+
+	assert.ElementsMatch(t, []attribute.KeyValue{
+		attribute.String("mcp.method.name", "tools/call"),
+		attribute.String("gen_ai.tool.name", "capture_payment"),
+		attribute.Bool("mcp.tool.read_only", false),
+		attribute.String("razorpay.toolset", "payments"),
+		attribute.String("razorpay.payment_id", "pay_1"),
+	}, attrs)
+}
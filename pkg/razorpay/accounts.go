@@ -0,0 +1,126 @@
+package razorpay
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+)
+
+// accountNamePattern matches account names such as prod_in
+var accountNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// AccountConfig holds the API key of a Razorpay account
+type AccountConfig struct {
+	Key    string `mapstructure:"key"`
+	Secret string `mapstructure:"secret"`
+	// OAuthSubjects are the subjects of the OAuth tokens whose requests
+	// to the HTTP server are made with the account
+	OAuthSubjects []string `mapstructure:"oauth_subjects"`
+}
+
+// validateAccounts checks the accounts and the default account of the
+// config
+func (c Config) validateAccounts() []error {
+	var errs []error
+	subjects := make(map[string]string)
+	for _, name := range sortedKeys(c.Accounts) {
+		if !accountNamePattern.MatchString(name) {
+			errs = append(errs, fmt.Errorf("accounts.%s: name may only "+
+				"contain letters, digits, _ and -", name))
+		}
+		account := c.Accounts[name]
+		if account.Key == "" || account.Secret == "" {
+			errs = append(errs, fmt.Errorf("accounts.%s: key and secret "+
+				"are required", name))
+		}
+		for _, subject := range account.OAuthSubjects {
+			other, mapped := subjects[subject]
+			switch {
+			case subject == "":
+				errs = append(errs, fmt.Errorf("accounts.%s.oauth_subjects: "+
+					"subjects must not be empty", name))
+			case mapped:
+				errs = append(errs, fmt.Errorf("accounts.%s.oauth_subjects: "+
+					"%q is already a subject of account %s", name, subject,
+					other))
+			default:
+				subjects[subject] = name
+			}
+		}
+	}
+	if _, ok := c.Accounts[c.DefaultAccount]; c.DefaultAccount != "" && !ok {
+		errs = append(errs, fmt.Errorf("default_account: %q is not one "+
+			"of the accounts", c.DefaultAccount))
+	}
+	return errs
+}
+
+// OAuthAccounts returns the accounts the requests with OAuth tokens are
+// made with, keyed by the subject of their token
+func (c Config) OAuthAccounts() map[string]AccountConfig {
+	accounts := make(map[string]AccountConfig)
+	for _, account := range c.Accounts {
+		for _, subject := range account.OAuthSubjects {
+			accounts[subject] = account
+		}
+	}
+	return accounts
+}
+
+// accountsOption returns the server option that makes tool calls with the
+// client of the account they select, built like the client the server is
+// created with
+func (c Config) accountsOption(client *rzpsdk.Client) mcpgo.ServerOption {
+	names := sortedKeys(c.Accounts)
+	clients := make(map[string]*rzpsdk.Client, len(c.Accounts))
+	for name, account := range c.Accounts {
+		clients[name] = accountClient(client, account)
+	}
+
+	use := func(ctx context.Context, name string) (context.Context, error) {
+		// Calls with the credentials of their request are not made with
+		// the accounts of the server
+		if contextkey.ClientFromContext(ctx) != nil {
+			if name != "" {
+				return ctx, fmt.Errorf("account cannot be selected by " +
+					"requests with their own credentials")
+			}
+			return ctx, nil
+		}
+
+		if name == "" {
+			name = c.DefaultAccount
+		}
+		if name == "" {
+			return ctx, nil
+		}
+		accountClient, ok := clients[name]
+		if !ok {
+			return ctx, fmt.Errorf("invalid account: %s, must be one of %s",
+				name, strings.Join(names, ", "))
+		}
+		// Results of calls are only shared between calls of the account
+		ctx = contextkey.WithClient(ctx, accountClient)
+		return contextkey.WithCacheScope(ctx, "account:"+name), nil
+	}
+
+	return mcpgo.WithAccounts(names, c.DefaultAccount, use)
+}
+
+// accountClient returns a client that makes requests with the key of the
+// account, and the other settings of the client
+func accountClient(
+	client *rzpsdk.Client,
+	account AccountConfig,
+) *rzpsdk.Client {
+	accountClient := copyClient(client)
+	accountClient.Request.Auth.Key = account.Key
+	accountClient.Request.Auth.Secret = account.Secret
+	return accountClient
+}
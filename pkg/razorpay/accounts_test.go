@@ -0,0 +1,162 @@
+package razorpay
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+)
+
+func TestConfigAccounts(t *testing.T) {
+	// The API returns the key each request was made with as the order id
+	apiServer := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			key, _, _ := r.BasicAuth()
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"id": key})
+		}))
+	defer apiServer.Close()
+
+	accounts := map[string]AccountConfig{
+		"prod_in":   {Key: "rzp_live_in", Secret: "in-secret"},
+		"prod_intl": {Key: "rzp_live_intl", Secret: "intl-secret"},
+	}
+
+	// fetchOrder calls fetch_order on a server with the accounts and the
+	// default account, and returns the key the call was made with
+	fetchOrder := func(
+		t *testing.T,
+		ctx context.Context,
+		defaultAccount string,
+		args map[string]interface{},
+	) (string, *mcp.CallToolResult) {
+		t.Helper()
+
+		client := rzpsdk.NewClient("rzp_test_default", "test-secret")
+		client.Request.BaseURL = apiServer.URL
+		server, err := NewRzpMcpServer(CreateTestObservability(), client,
+			[]string{"orders"}, nil, nil, false, WithConfig(Config{
+				Accounts:       accounts,
+				DefaultAccount: defaultAccount,
+			}))
+		require.NoError(t, err)
+		impl, ok := server.(*mcpgo.Mark3labsImpl)
+		require.True(t, ok)
+
+		tool := impl.McpServer.GetTool("fetch_order")
+		require.NotNil(t, tool)
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := tool.Handler(ctx, request)
+		require.NoError(t, err)
+
+		content, _ := result.StructuredContent.(map[string]interface{})
+		key, _ := content["id"].(string)
+		return key, result
+	}
+
+	t.Run("makes calls with the selected account", func(t *testing.T) {
+		key, _ := fetchOrder(t, context.Background(), "prod_in",
+			map[string]interface{}{
+				"order_id": "order_1", "account": "prod_intl",
+			})
+		assert.Equal(t, "rzp_live_intl", key)
+	})
+
+	t.Run("makes calls with the default account", func(t *testing.T) {
+		key, _ := fetchOrder(t, context.Background(), "prod_in",
+			map[string]interface{}{"order_id": "order_1"})
+		assert.Equal(t, "rzp_live_in", key)
+	})
+
+	t.Run("falls back to the client of the server", func(t *testing.T) {
+		key, _ := fetchOrder(t, context.Background(), "",
+			map[string]interface{}{"order_id": "order_1"})
+		assert.Equal(t, "rzp_test_default", key)
+	})
+
+	t.Run("rejects unknown accounts", func(t *testing.T) {
+		_, result := fetchOrder(t, context.Background(), "",
+			map[string]interface{}{
+				"order_id": "order_1", "account": "prod_us",
+			})
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text,
+			"invalid account: prod_us, must be one of prod_in, prod_intl")
+	})
+
+	t.Run("keeps the credentials of the request", func(t *testing.T) {
+		requestClient := rzpsdk.NewClient("rzp_test_request", "secret")
+		requestClient.Request.BaseURL = apiServer.URL
+		ctx := contextkey.WithClient(context.Background(), requestClient)
+
+		key, _ := fetchOrder(t, ctx, "prod_in",
+			map[string]interface{}{"order_id": "order_1"})
+		assert.Equal(t, "rzp_test_request", key)
+
+		_, result := fetchOrder(t, ctx, "prod_in",
+			map[string]interface{}{
+				"order_id": "order_1", "account": "prod_intl",
+			})
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text,
+			"account cannot be selected by requests with their own "+
+				"credentials")
+	})
+
+	t.Run("rejects invalid accounts", func(t *testing.T) {
+		_, err := newConfiguredServer(t, Config{
+			Accounts: map[string]AccountConfig{
+				"prod in": {Key: "rzp_live_in", Secret: "in-secret"},
+				"prod_us": {Key: "rzp_live_us"},
+			},
+			DefaultAccount: "prod_intl",
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "accounts.prod in: name may only "+
+			"contain letters, digits, _ and -")
+		assert.Contains(t, err.Error(), "accounts.prod_us: key and secret "+
+			"are required")
+		assert.Contains(t, err.Error(), `default_account: "prod_intl" is `+
+			"not one of the accounts")
+	})
+
+	t.Run("maps oauth subjects to their account", func(t *testing.T) {
+		config := Config{Accounts: map[string]AccountConfig{
+			"prod_in": {Key: "rzp_live_in", Secret: "in-secret",
+				OAuthSubjects: []string{"user_1", "user_2"}},
+			"prod_intl": {Key: "rzp_live_intl", Secret: "intl-secret"},
+		}}
+
+		assert.Equal(t, map[string]AccountConfig{
+			"user_1": config.Accounts["prod_in"],
+			"user_2": config.Accounts["prod_in"],
+		}, config.OAuthAccounts())
+	})
+
+	t.Run("rejects oauth subjects of several accounts", func(t *testing.T) {
+		_, err := newConfiguredServer(t, Config{
+			Accounts: map[string]AccountConfig{
+				"prod_in": {Key: "rzp_live_in", Secret: "in-secret",
+					OAuthSubjects: []string{"user_1"}},
+				"prod_intl": {Key: "rzp_live_intl", Secret: "intl-secret",
+					OAuthSubjects: []string{"user_1", ""}},
+			},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "accounts.prod_intl.oauth_subjects: "+
+			`"user_1" is already a subject of account prod_in`)
+		assert.Contains(t, err.Error(), "accounts.prod_intl.oauth_subjects: "+
+			"subjects must not be empty")
+	})
+}
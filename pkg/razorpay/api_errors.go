@@ -0,0 +1,122 @@
+package razorpay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+)
+
+// maxAPIErrorSize is the largest error response body parsed, in bytes
+const maxAPIErrorSize = 64 << 10
+
+// apiErrorBody is the body of an error response of the Razorpay API
+type apiErrorBody struct {
+	Error struct {
+		Code        string `json:"code"`
+		Description string `json:"description"`
+		Source      string `json:"source"`
+		Step        string `json:"step"`
+		Reason      string `json:"reason"`
+		Field       string `json:"field"`
+	} `json:"error"`
+}
+
+// withAPIErrors returns a copy of the client that records the errors of
+// its requests as the structured error of the tool call of ctx, or the
+// client itself if the call does not record errors. The SDK only returns
+// the description of API errors, so the copy reads them from the response.
+func withAPIErrors(ctx context.Context, client *rzpsdk.Client) *rzpsdk.Client {
+	toolErrors := mcpgo.ToolErrorsFromContext(ctx)
+	if toolErrors == nil {
+		return client
+	}
+
+	return withTransport(client, func(
+		next http.RoundTripper,
+	) http.RoundTripper {
+		return &apiErrorTransport{toolErrors: toolErrors, next: next}
+	})
+}
+
+// apiErrorTransport records the errors of the requests it sends
+type apiErrorTransport struct {
+	toolErrors *mcpgo.ToolErrors
+	next       http.RoundTripper
+}
+
+func (t *apiErrorTransport) RoundTrip(
+	req *http.Request,
+) (*http.Response, error) {
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		// Calls that are cancelled or time out fail for that reason
+		if req.Context().Err() == nil {
+			t.toolErrors.Record(mcpgo.ToolError{
+				Code:      mcpgo.NetworkErrorCode,
+				Retryable: true,
+			})
+		}
+		return resp, err
+	}
+	// The SDK treats every status from 300 as an error
+	if resp.StatusCode < http.StatusMultipleChoices {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxAPIErrorSize))
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.toolErrors.Record(apiError(resp.StatusCode, body))
+	return resp, nil
+}
+
+// apiError returns the structured error of an error response
+func apiError(status int, body []byte) mcpgo.ToolError {
+	var errorBody apiErrorBody
+	_ = json.Unmarshal(body, &errorBody)
+	details := errorBody.Error
+
+	code := details.Code
+	if code == "" {
+		code = "BAD_REQUEST_ERROR"
+		if status >= http.StatusInternalServerError {
+			code = "SERVER_ERROR"
+		}
+	}
+
+	return mcpgo.ToolError{
+		Code:       code,
+		Source:     applicable(details.Source),
+		Step:       applicable(details.Step),
+		Reason:     applicable(details.Reason),
+		Field:      applicable(details.Field),
+		HTTPStatus: status,
+		Retryable: status == http.StatusTooManyRequests ||
+			status >= http.StatusInternalServerError ||
+			code == "GATEWAY_ERROR" || code == "SERVER_ERROR",
+	}
+}
+
+// applicable returns the value of an error detail, or an empty string if
+// the API marked it as not applicable
+func applicable(value string) string {
+	if value == "NA" {
+		return ""
+	}
+	return value
+}
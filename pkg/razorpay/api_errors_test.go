@@ -0,0 +1,147 @@
+package razorpay
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/razorpay/razorpay-go/constants"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+)
+
+func TestAPIErrors(t *testing.T) {
+	paymentPath := fmt.Sprintf("/%s%s/pay_MT48CvBhIC98MQ",
+		constants.VERSION_V1, constants.PAYMENT_URL)
+
+	// fetchPayment calls fetch_payment against a server responding with
+	// the status and body, and returns the result and the recorded error
+	fetchPayment := func(
+		t *testing.T,
+		status int,
+		body string,
+	) (*mcpgo.ToolResult, *mcpgo.ToolError) {
+		t.Helper()
+
+		server := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, paymentPath, r.URL.Path)
+				w.WriteHeader(status)
+				_, _ = w.Write([]byte(body))
+			}))
+		defer server.Close()
+
+		client, _ := newMockRzpClient(func() (
+			*http.Client, *httptest.Server) {
+			return server.Client(), server
+		})
+		toolErrors := &mcpgo.ToolErrors{}
+		ctx := mcpgo.ContextWithToolErrors(context.Background(), toolErrors)
+
+		tool := FetchPayment(CreateTestObservability(), client)
+		result, err := tool.GetHandler()(ctx, createMCPRequest(
+			map[string]interface{}{"payment_id": "pay_MT48CvBhIC98MQ"}))
+		require.NoError(t, err)
+
+		return result, toolErrors.Last()
+	}
+
+	t.Run("records the details of API errors", func(t *testing.T) {
+		result, toolErr := fetchPayment(t, http.StatusBadRequest,
+			`{"error": {"code": "BAD_REQUEST_ERROR", `+
+				`"description": "Payment was cancelled by the customer", `+
+				`"source": "customer", "step": "payment_authentication", `+
+				`"reason": "payment_cancelled", "field": "NA"}}`)
+
+		assert.True(t, result.IsError)
+		assert.Equal(t, "fetching payment failed: "+
+			"Payment was cancelled by the customer", result.Text)
+		assert.Equal(t, &mcpgo.ToolError{
+			Code:       "BAD_REQUEST_ERROR",
+			Source:     "customer",
+			Step:       "payment_authentication",
+			Reason:     "payment_cancelled",
+			HTTPStatus: http.StatusBadRequest,
+		}, toolErr)
+	})
+
+	t.Run("records invalid fields", func(t *testing.T) {
+		_, toolErr := fetchPayment(t, http.StatusBadRequest,
+			`{"error": {"code": "BAD_REQUEST_ERROR", `+
+				`"description": "The id provided does not exist", `+
+				`"source": "NA", "step": "NA", "reason": "NA", `+
+				`"field": "id"}}`)
+
+		assert.Equal(t, &mcpgo.ToolError{
+			Code:       "BAD_REQUEST_ERROR",
+			Field:      "id",
+			HTTPStatus: http.StatusBadRequest,
+		}, toolErr)
+	})
+
+	t.Run("marks server errors retryable", func(t *testing.T) {
+		result, toolErr := fetchPayment(t, http.StatusBadGateway,
+			"<html>Bad Gateway</html>")
+
+		assert.True(t, result.IsError)
+		assert.Equal(t, &mcpgo.ToolError{
+			Code:       "SERVER_ERROR",
+			HTTPStatus: http.StatusBadGateway,
+			Retryable:  true,
+		}, toolErr)
+	})
+
+	t.Run("marks rate limited requests retryable", func(t *testing.T) {
+		_, toolErr := fetchPayment(t, http.StatusTooManyRequests,
+			`{"error": {"code": "BAD_REQUEST_ERROR", `+
+				`"description": "Too many requests"}}`)
+
+		assert.True(t, toolErr.Retryable)
+	})
+
+	t.Run("records nothing for successful requests", func(t *testing.T) {
+		result, toolErr := fetchPayment(t, http.StatusOK,
+			`{"id": "pay_MT48CvBhIC98MQ", "entity": "payment"}`)
+
+		assert.False(t, result.IsError)
+		assert.Nil(t, toolErr)
+	})
+
+	t.Run("records network errors", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		client, _ := newMockRzpClient(func() (
+			*http.Client, *httptest.Server) {
+			return server.Client(), server
+		})
+		server.Close()
+		toolErrors := &mcpgo.ToolErrors{}
+		ctx := mcpgo.ContextWithToolErrors(context.Background(), toolErrors)
+
+		tool := FetchPayment(CreateTestObservability(), client)
+		result, err := tool.GetHandler()(ctx, createMCPRequest(
+			map[string]interface{}{"payment_id": "pay_MT48CvBhIC98MQ"}))
+
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Equal(t, &mcpgo.ToolError{
+			Code:      mcpgo.NetworkErrorCode,
+			Retryable: true,
+		}, toolErrors.Last())
+	})
+
+	t.Run("classifies invalid IDs as validation errors", func(t *testing.T) {
+		result := validateID("item_id", "item_1/../payments",
+			itemIDPrefix)
+
+		require.NotNil(t, result)
+		assert.Equal(t, &mcpgo.ToolError{
+			Code:  mcpgo.ValidationErrorCode,
+			Field: "item_id",
+		}, result.Error)
+	})
+}
@@ -0,0 +1,251 @@
+package razorpay
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+)
+
+// maxReferenceScanned is the most payments, and refunds, a bank reference
+// lookup looks at, each
+const maxReferenceScanned = 2000
+
+// referenceFields are the acquirer_data fields that hold each type of bank
+// reference. Any type matches every acquirer_data field.
+var referenceFields = map[string][]string{
+	"rrn":                 {"rrn"},
+	"utr":                 {"upi_transaction_id", "utr"},
+	"bank_transaction_id": {"bank_transaction_id"},
+	"arn":                 {"arn", "authentication_reference_number"},
+}
+
+// referenceMatch tells which field of a payment, or of one of its refunds,
+// holds the bank reference
+type referenceMatch struct {
+	PaymentID string `json:"payment_id"`
+	RefundID  string `json:"refund_id,omitempty"`
+	Field     string `json:"field"`
+}
+
+// FindPaymentByBankReference returns a tool that finds the payments of a
+// window by a bank reference, such as a UPI RRN, a UTR or a refund ARN
+func FindPaymentByBankReference(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"reference",
+			mcpgo.Description("Bank reference the customer shared, such as "+
+				"the RRN or UTR of a UPI payment from their bank statement, "+
+				"or the ARN of a refund"),
+			mcpgo.Required(),
+			mcpgo.Min(1),
+		),
+		mcpgo.WithString(
+			"reference_type",
+			mcpgo.Description("Type of the reference: rrn, utr (UPI "+
+				"transaction ID), bank_transaction_id (netbanking), arn "+
+				"(refunds and card authentications), or any to match every "+
+				"acquirer_data field (default: any)"),
+			mcpgo.Enum("any", "rrn", "utr", "bank_transaction_id", "arn"),
+		),
+		mcpgo.WithNumber(
+			"from",
+			mcpgo.Description("Unix timestamp (in seconds) from when "+
+				"payments, and refunds for an ARN, are to be searched"),
+			mcpgo.Required(),
+			mcpgo.Min(0),
+		),
+		mcpgo.WithNumber(
+			"to",
+			mcpgo.Description("Unix timestamp (in seconds) up till when "+
+				"payments, and refunds for an ARN, are to be searched"),
+			mcpgo.Required(),
+			mcpgo.Min(0),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		window := make(map[string]interface{})
+		params := map[string]interface{}{"reference_type": "any"}
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(params, "reference").
+			ValidateAndAddOptionalString(params, "reference_type").
+			ValidateAndAddRequiredInt(window, "from").
+			ValidateAndAddRequiredInt(window, "to")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		reference := strings.TrimSpace(params["reference"].(string))
+		if reference == "" {
+			return mcpgo.NewToolResultValidationError(
+				"reference must not be empty", "reference"), nil
+		}
+		referenceType := params["reference_type"].(string)
+		fields, ok := referenceFields[referenceType]
+		if !ok && referenceType != "any" {
+			return mcpgo.NewToolResultValidationError(fmt.Sprintf(
+				"invalid reference_type: %s, must be any, rrn, utr, "+
+					"bank_transaction_id or arn", referenceType),
+				"reference_type"), nil
+		}
+		if window["to"].(int64) < window["from"].(int64) {
+			return mcpgo.NewToolResultError(
+				"to must not be before from"), nil
+		}
+
+		var matches []referenceMatch
+		payments, scan, err := scanPages(window, maxReferenceScanned,
+			maxReferenceScanned, func(payment map[string]interface{}) bool {
+				field := referenceField(payment, fields, reference)
+				if field != "" {
+					matches = append(matches, referenceMatch{
+						PaymentID: stringField(payment, "id"),
+						Field:     field,
+					})
+				}
+				return field != ""
+			}, client.Payment.All)
+		if err != nil {
+			return mcpgo.NewToolResultError(fmt.Sprintf(
+				"fetching payments failed: %s", err.Error())), nil
+		}
+
+		result := map[string]interface{}{
+			"entity":    "collection",
+			"reference": reference,
+			"scanned":   scan.scanned,
+		}
+		truncated := !scan.exhausted
+
+		// ARNs are assigned to refunds, which can be of payments made
+		// before the window
+		if referenceType == "arn" || referenceType == "any" {
+			refundMatches, refundScan, err := findRefundsByReference(client,
+				window, fields, reference)
+			if err != nil {
+				return mcpgo.NewToolResultError(fmt.Sprintf(
+					"fetching refunds failed: %s", err.Error())), nil
+			}
+			result["refunds_scanned"] = refundScan.scanned
+			truncated = truncated || !refundScan.exhausted
+
+			found := make(map[string]bool, len(payments))
+			for _, match := range matches {
+				found[match.PaymentID] = true
+			}
+			for _, match := range refundMatches {
+				matches = append(matches, match)
+				if found[match.PaymentID] {
+					continue
+				}
+				payment, err := client.Payment.Fetch(match.PaymentID, nil,
+					nil)
+				if err != nil {
+					return mcpgo.NewToolResultError(fmt.Sprintf(
+						"fetching payment failed: %s", err.Error())), nil
+				}
+				found[match.PaymentID] = true
+				payments = append(payments, payment)
+			}
+		}
+
+		if matches == nil {
+			matches = []referenceMatch{}
+		}
+		result["count"] = len(payments)
+		result["items"] = payments
+		result["matches"] = matches
+		result["truncated"] = truncated
+
+		return mcpgo.NewToolResultJSON(result)
+	}
+
+	return mcpgo.NewTool(
+		"find_payment_by_bank_reference",
+		fmt.Sprintf("Find the payments for a bank reference a customer "+
+			"shared, such as the RRN or UTR of a UPI payment, the bank "+
+			"transaction ID of a netbanking payment, or the ARN of a "+
+			"refund, by matching the acquirer_data of the payments, and "+
+			"of the refunds for ARNs, created between from and to. "+
+			"References are matched ignoring case. matches tells which "+
+			"field matched. At most %d payments and %d refunds are looked "+
+			"at, newest first, and truncated is set when more were "+
+			"created, so narrow the window around when the customer paid",
+			maxReferenceScanned, maxReferenceScanned),
+		parameters,
+		handler,
+	).WithOutputSchema(objectOutputSchema).
+		WithTimeout(scanToolTimeout).
+		WithFieldSelection()
+}
+
+// findRefundsByReference returns the refunds of the window whose
+// acquirer_data holds the reference in one of the fields, or in any field
+// if fields is nil
+func findRefundsByReference(
+	client *rzpsdk.Client,
+	window map[string]interface{},
+	fields []string,
+	reference string,
+) ([]referenceMatch, pageScan, error) {
+	var matches []referenceMatch
+	_, scan, err := scanPages(window, maxReferenceScanned,
+		maxReferenceScanned, func(refund map[string]interface{}) bool {
+			field := referenceField(refund, fields, reference)
+			if field != "" {
+				matches = append(matches, referenceMatch{
+					PaymentID: stringField(refund, "payment_id"),
+					RefundID:  stringField(refund, "id"),
+					Field:     field,
+				})
+			}
+			return field != ""
+		}, client.Refund.All)
+	return matches, scan, err
+}
+
+// referenceField returns the path of the acquirer_data field of the entity
+// that holds the reference, looking at the fields, or at every field if
+// fields is nil. It returns an empty string if none holds it.
+func referenceField(
+	entity map[string]interface{},
+	fields []string,
+	reference string,
+) string {
+	acquirerData, _ := entity["acquirer_data"].(map[string]interface{})
+	if fields == nil {
+		fields = make([]string, 0, len(acquirerData))
+		for field := range acquirerData {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+	}
+
+	for _, field := range fields {
+		value, _ := acquirerData[field].(string)
+		if value != "" &&
+			strings.EqualFold(strings.TrimSpace(value), reference) {
+			return "acquirer_data." + field
+		}
+	}
+	return ""
+}
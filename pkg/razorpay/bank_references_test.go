@@ -0,0 +1,211 @@
+package razorpay
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/razorpay/razorpay-go/constants"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay/mock"
+)
+
+func Test_FindPaymentByBankReference(t *testing.T) {
+	paymentsPath := fmt.Sprintf("/%s%s", constants.VERSION_V1,
+		constants.PAYMENT_URL)
+	refundsPath := fmt.Sprintf("/%s%s", constants.VERSION_V1,
+		constants.REFUND_URL)
+	paymentPath := fmt.Sprintf("/%s%s/%s", constants.VERSION_V1,
+		constants.PAYMENT_URL, "pay_OldCardPayment1")
+
+	upiPayment := map[string]interface{}{
+		"id":     "pay_UpiPayment00001",
+		"method": "upi",
+		"acquirer_data": map[string]interface{}{
+			"rrn":                "412345678901",
+			"upi_transaction_id": "AXB1234567890",
+		},
+	}
+	netbankingPayment := map[string]interface{}{
+		"id":     "pay_NetbankingPay01",
+		"method": "netbanking",
+		"acquirer_data": map[string]interface{}{
+			"bank_transaction_id": "HDF0001234",
+		},
+	}
+	cardPayment := map[string]interface{}{
+		"id":            "pay_OldCardPayment1",
+		"method":        "card",
+		"acquirer_data": map[string]interface{}{"auth_code": "205480"},
+	}
+	refund := map[string]interface{}{
+		"id":            "rfnd_CardRefund0001",
+		"payment_id":    "pay_OldCardPayment1",
+		"acquirer_data": map[string]interface{}{"arn": "74836542512345"},
+	}
+
+	window := func(args map[string]interface{}) map[string]interface{} {
+		args["from"] = float64(1705257000)
+		args["to"] = float64(1705429799)
+		return args
+	}
+	payments := func() (*http.Client, *httptest.Server) {
+		return mock.NewHTTPClient(
+			mock.Endpoint{Path: paymentsPath, Method: "GET",
+				Response: collectionOf(upiPayment, netbankingPayment)},
+			mock.Endpoint{Path: refundsPath, Method: "GET",
+				Response: collectionOf(refund)},
+			mock.Endpoint{Path: paymentPath, Method: "GET",
+				Response: cardPayment},
+		)
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "finds a UPI payment by RRN",
+			Request: window(map[string]interface{}{
+				"reference":      "412345678901",
+				"reference_type": "rrn",
+			}),
+			MockHttpClient: payments,
+			ExpectedResult: map[string]interface{}{
+				"entity":    "collection",
+				"reference": "412345678901",
+				"count":     float64(1),
+				"items":     []interface{}{upiPayment},
+				"matches": []interface{}{
+					map[string]interface{}{
+						"payment_id": "pay_UpiPayment00001",
+						"field":      "acquirer_data.rrn",
+					},
+				},
+				"scanned":   float64(2),
+				"truncated": false,
+			},
+		},
+		{
+			Name: "finds a payment by any reference, ignoring case",
+			Request: window(map[string]interface{}{
+				"reference": " hdf0001234 ",
+			}),
+			MockHttpClient: payments,
+			ExpectedResult: map[string]interface{}{
+				"entity":    "collection",
+				"reference": "hdf0001234",
+				"count":     float64(1),
+				"items":     []interface{}{netbankingPayment},
+				"matches": []interface{}{
+					map[string]interface{}{
+						"payment_id": "pay_NetbankingPay01",
+						"field":      "acquirer_data.bank_transaction_id",
+					},
+				},
+				"scanned":         float64(2),
+				"refunds_scanned": float64(1),
+				"truncated":       false,
+			},
+		},
+		{
+			Name: "finds the payment of a refund by ARN",
+			Request: window(map[string]interface{}{
+				"reference":      "74836542512345",
+				"reference_type": "arn",
+			}),
+			MockHttpClient: payments,
+			ExpectedResult: map[string]interface{}{
+				"entity":    "collection",
+				"reference": "74836542512345",
+				"count":     float64(1),
+				"items":     []interface{}{cardPayment},
+				"matches": []interface{}{
+					map[string]interface{}{
+						"payment_id": "pay_OldCardPayment1",
+						"refund_id":  "rfnd_CardRefund0001",
+						"field":      "acquirer_data.arn",
+					},
+				},
+				"scanned":         float64(2),
+				"refunds_scanned": float64(1),
+				"truncated":       false,
+			},
+		},
+		{
+			Name: "UTR that is only an RRN",
+			Request: window(map[string]interface{}{
+				"reference":      "412345678901",
+				"reference_type": "utr",
+			}),
+			MockHttpClient: payments,
+			ExpectedResult: map[string]interface{}{
+				"entity":    "collection",
+				"reference": "412345678901",
+				"count":     float64(0),
+				"items":     []interface{}{},
+				"matches":   []interface{}{},
+				"scanned":   float64(2),
+				"truncated": false,
+			},
+		},
+		{
+			Name: "fetching payments fails",
+			Request: window(map[string]interface{}{
+				"reference": "412345678901",
+			}),
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{Path: paymentsPath, Method: "GET",
+						Response: map[string]interface{}{
+							"error": map[string]interface{}{
+								"code":        "BAD_REQUEST_ERROR",
+								"description": "from must be an integer",
+							},
+						}},
+				)
+			},
+			ExpectError:    true,
+			ExpectedErrMsg: "fetching payments failed",
+		},
+		{
+			Name: "unknown reference type",
+			Request: window(map[string]interface{}{
+				"reference":      "412345678901",
+				"reference_type": "iban",
+			}),
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid reference_type: iban",
+		},
+		{
+			Name: "blank reference",
+			Request: window(map[string]interface{}{
+				"reference": "  ",
+			}),
+			ExpectError:    true,
+			ExpectedErrMsg: "reference must not be empty",
+		},
+		{
+			Name: "reversed window",
+			Request: map[string]interface{}{
+				"reference": "412345678901",
+				"from":      float64(1705429799),
+				"to":        float64(1705257000),
+			},
+			ExpectError:    true,
+			ExpectedErrMsg: "to must not be before from",
+		},
+		{
+			Name: "missing window",
+			Request: map[string]interface{}{
+				"reference": "412345678901",
+			},
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: from",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FindPaymentByBankReference, "Payments")
+		})
+	}
+}
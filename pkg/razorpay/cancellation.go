@@ -0,0 +1,43 @@
+package razorpay
+
+import (
+	"context"
+	"net/http"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+)
+
+// withCancellation returns a copy of the client whose requests are sent
+// with ctx, so that they are aborted once the tool call is cancelled or
+// times out, or the client itself if ctx is never done. The SDK sends
+// requests without a context, so the copy binds its transport to ctx.
+func withCancellation(
+	ctx context.Context,
+	client *rzpsdk.Client,
+) *rzpsdk.Client {
+	if ctx.Done() == nil {
+		return client
+	}
+
+	return withTransport(client, func(
+		next http.RoundTripper,
+	) http.RoundTripper {
+		return &contextTransport{ctx: ctx, next: next}
+	})
+}
+
+// contextTransport sends requests with the context of a tool call
+type contextTransport struct {
+	ctx  context.Context
+	next http.RoundTripper
+}
+
+func (t *contextTransport) RoundTrip(
+	req *http.Request,
+) (*http.Response, error) {
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return next.RoundTrip(req.WithContext(t.ctx))
+}
@@ -0,0 +1,54 @@
+package razorpay
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
+)
+
+func TestWithCancellation(t *testing.T) {
+	t.Run("returns the client for calls never done", func(t *testing.T) {
+		client := rzpsdk.NewClient("test-key", "test-secret")
+
+		assert.Same(t, client, withCancellation(context.Background(), client))
+	})
+
+	t.Run("aborts requests of cancelled calls", func(t *testing.T) {
+		aborted := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				<-r.Context().Done()
+				close(aborted)
+			}))
+		defer server.Close()
+
+		client := rzpsdk.NewClient("test-key", "test-secret")
+		client.Request.BaseURL = server.URL
+
+		ctx, cancel := context.WithTimeout(context.Background(),
+			10*time.Millisecond)
+		defer cancel()
+		ctx = contextkey.WithClient(ctx, client)
+		bound, err := getClientFromContextOrDefault(ctx, nil)
+		require.NoError(t, err)
+
+		_, err = bound.Payment.Fetch("pay_1", nil, nil)
+		require.Error(t, err)
+		assert.NotSame(t, client.Request, bound.Request)
+		assert.Equal(t, client.Request.Auth, bound.Request.Auth)
+		select {
+		case <-aborted:
+		case <-time.After(time.Second):
+			t.Fatal("request was not aborted")
+		}
+	})
+}
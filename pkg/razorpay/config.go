@@ -0,0 +1,367 @@
+package razorpay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
+	"github.com/razorpay/razorpay-mcp-server/pkg/events"
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+	"github.com/razorpay/razorpay-mcp-server/pkg/toolsets"
+)
+
+// currencyPattern matches ISO 4217 currency codes
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// domainPattern matches domain names such as example.com
+var domainPattern = regexp.MustCompile(
+	`^[A-Za-z0-9]([A-Za-z0-9-]*[A-Za-z0-9])?` +
+		`(\.[A-Za-z0-9]([A-Za-z0-9-]*[A-Za-z0-9])?)+$`)
+
+// Config tunes the toolsets and tools of the server, typically from the
+// toolset_config and tool_config sections of the config file
+type Config struct {
+	// Profiles enables the toolsets of product line profiles, such as
+	// pg-core, along with the toolsets the server is created with
+	Profiles []string
+	// Toolsets holds the settings of toolsets, keyed by toolset name
+	Toolsets map[string]ToolsetConfig
+	// Tools holds the settings of tools, keyed by tool name
+	Tools map[string]ToolConfig
+	// Locale is the language of tool descriptions and guidance, such as
+	// hi. Empty for English.
+	Locale string
+	// Mode limits write tools to keys of a mode, test or live. Empty
+	// allows keys of both modes.
+	Mode string
+	// AmountUnit is the unit of the amounts of calls that pass no
+	// amount_unit, paise or rupees. Empty for paise.
+	AmountUnit string
+	// ConfirmHighRisk asks the user to confirm refunds above
+	// ConfirmRefundAbove, instant settlements and token revocations
+	// before they run
+	ConfirmHighRisk bool
+	// ConfirmRefundAbove is the refund amount, in currency subunits, above
+	// which refunds need confirmation. 0 confirms every refund.
+	ConfirmRefundAbove int64
+	// Policy limits the amounts and currencies of write tools
+	Policy PolicyConfig
+	// Currencies holds the settings of currencies, keyed by ISO 4217
+	// currency code
+	Currencies map[string]CurrencyConfig
+	// GeneratedEmailDomain is the domain of the emails generated for
+	// customers that pay without an email, from their contact number.
+	// Empty for mcp.razorpay.com.
+	GeneratedEmailDomain string
+	// DisableGeneratedEmail creates payments without an email when the
+	// customer gives none, instead of generating one
+	DisableGeneratedEmail bool
+	// Accounts are the Razorpay accounts tool calls can select with the
+	// account parameter, keyed by name
+	Accounts map[string]AccountConfig
+	// DefaultAccount is the account of calls that select none. Empty for
+	// the client the server is created with.
+	DefaultAccount string
+	// Partner lets tool calls select a sub-merchant account to be made on
+	// behalf of, for servers with partner credentials
+	Partner bool
+}
+
+// ToolsetConfig holds the settings of a toolset
+type ToolsetConfig struct {
+	// Enabled enables or disables the toolset regardless of the toolsets
+	// flag, nil keeps the flag's choice
+	Enabled *bool `mapstructure:"enabled"`
+	// ReadOnly registers only the read tools of the toolset
+	ReadOnly bool `mapstructure:"read_only"`
+	// DefaultCurrency is the currency used by tools of the toolset when a
+	// call does not set one
+	DefaultCurrency string `mapstructure:"default_currency"`
+	// DefaultCapture is the payment capture mode, automatic or manual, used
+	// by tools of the toolset when a call does not set one
+	DefaultCapture string `mapstructure:"default_capture"`
+}
+
+// ToolConfig holds the settings of a tool
+type ToolConfig struct {
+	// Description replaces the description of the tool
+	Description string `mapstructure:"description"`
+	// Timeout replaces the timeout of calls of the tool, 0 for no limit.
+	// Nil keeps the server's tool timeout.
+	Timeout *time.Duration `mapstructure:"timeout"`
+}
+
+// configOption is the option value that carries the config of the server
+type configOption struct {
+	config Config
+}
+
+// WithConfig returns a server option that applies the toolset and tool
+// settings of the config. The option is read by NewRzpMcpServer.
+func WithConfig(config Config) mcpgo.ServerOption {
+	return func(s mcpgo.OptionSetter) error {
+		return s.SetOption(configOption{config: config})
+	}
+}
+
+// eventHistoryOption is the option value that carries the history of the
+// webhook events the server receives
+type eventHistoryOption struct {
+	history *events.History
+}
+
+// WithEventHistory returns a server option that lets the tools read the
+// webhook events added to the history, and delivers the entity changes in
+// them to the sessions subscribed to the entities. The option is read by
+// NewRzpMcpServer.
+func WithEventHistory(history *events.History) mcpgo.ServerOption {
+	return func(s mcpgo.OptionSetter) error {
+		return s.SetOption(eventHistoryOption{history: history})
+	}
+}
+
+// configSetter collects the config and event history from server options
+type configSetter struct {
+	config  Config
+	history *events.History
+}
+
+func (s *configSetter) SetOption(option interface{}) error {
+	switch opt := option.(type) {
+	case configOption:
+		s.config = opt.config
+	case eventHistoryOption:
+		s.history = opt.history
+	}
+	return nil
+}
+
+// collectOptions returns the setter the server options were applied to
+func collectOptions(opts []mcpgo.ServerOption) *configSetter {
+	setter := &configSetter{}
+	for _, opt := range opts {
+		_ = opt(setter)
+	}
+	return setter
+}
+
+// configFromOptions returns the config carried by the server options
+func configFromOptions(opts []mcpgo.ServerOption) Config {
+	return collectOptions(opts).config
+}
+
+// eventHistoryFromOptions returns the webhook event history carried by the
+// server options, nil if there is none
+func eventHistoryFromOptions(opts []mcpgo.ServerOption) *events.History {
+	return collectOptions(opts).history
+}
+
+// validate checks the config against the toolsets of the group and
+// reports every problem found
+func (c Config) validate(tg *toolsets.ToolsetGroup) error {
+	var errs []error
+	if _, err := localeCatalog(c.Locale); err != nil {
+		errs = append(errs, err)
+	}
+	if c.Mode != "" && c.Mode != mcpgo.KeyModeTest &&
+		c.Mode != mcpgo.KeyModeLive {
+		errs = append(errs, fmt.Errorf("mode: %q must be test or live",
+			c.Mode))
+	}
+	if c.AmountUnit != "" && c.AmountUnit != mcpgo.AmountUnitPaise &&
+		c.AmountUnit != mcpgo.AmountUnitRupees {
+		errs = append(errs, fmt.Errorf("amount_unit: %q must be paise or "+
+			"rupees", c.AmountUnit))
+	}
+	if c.ConfirmRefundAbove < 0 {
+		errs = append(errs, fmt.Errorf("confirm_refund_above: %d must not "+
+			"be negative", c.ConfirmRefundAbove))
+	}
+	if c.GeneratedEmailDomain != "" &&
+		!domainPattern.MatchString(c.GeneratedEmailDomain) {
+		errs = append(errs, fmt.Errorf("generated_email_domain: %q is not "+
+			"a domain name such as example.com", c.GeneratedEmailDomain))
+	}
+	errs = append(errs, c.Policy.validate()...)
+	errs = append(errs, c.validateCurrencies()...)
+	errs = append(errs, c.validateAccounts()...)
+
+	for _, name := range sortedKeys(c.Toolsets) {
+		if _, exists := tg.Toolsets[name]; !exists {
+			errs = append(errs, fmt.Errorf("toolset_config.%s: toolset "+
+				"does not exist, available toolsets are %s", name,
+				strings.Join(sortedKeys(tg.Toolsets), ", ")))
+			continue
+		}
+
+		toolset := c.Toolsets[name]
+		if toolset.DefaultCurrency != "" &&
+			!currencyPattern.MatchString(toolset.DefaultCurrency) {
+			errs = append(errs, fmt.Errorf("toolset_config.%s."+
+				"default_currency: %q is not a 3 letter uppercase ISO "+
+				"currency code such as INR", name, toolset.DefaultCurrency))
+		}
+		if toolset.DefaultCapture != "" &&
+			toolset.DefaultCapture != "automatic" &&
+			toolset.DefaultCapture != "manual" {
+			errs = append(errs, fmt.Errorf("toolset_config.%s."+
+				"default_capture: %q must be automatic or manual", name,
+				toolset.DefaultCapture))
+		}
+	}
+
+	for _, name := range sortedKeys(c.Tools) {
+		if !tg.HasTool(name) {
+			errs = append(errs, fmt.Errorf("tool_config.%s: tool does "+
+				"not exist", name))
+			continue
+		}
+		tool := c.Tools[name]
+		// A tool may set only its timeout, but not a blank description
+		blank := strings.TrimSpace(tool.Description) == ""
+		if blank && (tool.Description != "" || tool.Timeout == nil) {
+			errs = append(errs, fmt.Errorf("tool_config.%s.description: "+
+				"must not be empty", name))
+		}
+		if tool.Timeout != nil && *tool.Timeout < 0 {
+			errs = append(errs, fmt.Errorf("tool_config.%s.timeout: %s "+
+				"must not be negative", name, *tool.Timeout))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// apply validates the config, applies the toolset settings to the group
+// and returns the server options that apply the tool settings. The mode is
+// checked against the key of the client of each call, which defaults to
+// the given client.
+func (c Config) apply(
+	tg *toolsets.ToolsetGroup,
+	client *rzpsdk.Client,
+) ([]mcpgo.ServerOption, error) {
+	if err := c.validate(tg); err != nil {
+		return nil, err
+	}
+
+	defaults := make(map[string]map[string]interface{})
+	for name, toolset := range c.Toolsets {
+		if toolset.Enabled != nil {
+			var err error
+			if *toolset.Enabled {
+				err = tg.EnableToolset(name)
+			} else {
+				err = tg.DisableToolset(name)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		if toolset.ReadOnly {
+			if err := tg.SetToolsetReadOnly(name); err != nil {
+				return nil, err
+			}
+		}
+
+		toolsetDefaults := make(map[string]interface{})
+		if toolset.DefaultCurrency != "" {
+			toolsetDefaults["currency"] = toolset.DefaultCurrency
+		}
+		if toolset.DefaultCapture != "" {
+			toolsetDefaults["payment_capture"] = toolset.DefaultCapture
+		}
+		if len(toolsetDefaults) > 0 {
+			defaults[name] = toolsetDefaults
+		}
+	}
+
+	// Descriptions of the config take precedence over translated ones
+	translations, err := localeCatalog(c.Locale)
+	if err != nil {
+		return nil, err
+	}
+	descriptions := make(map[string]string,
+		len(translations.Tools)+len(c.Tools))
+	for name, description := range translations.Tools {
+		descriptions[name] = description
+	}
+	timeouts := make(map[string]time.Duration)
+	for name, tool := range c.Tools {
+		if tool.Description != "" {
+			descriptions[name] = tool.Description
+		}
+		if tool.Timeout != nil {
+			timeouts[name] = *tool.Timeout
+		}
+	}
+
+	var confirmations map[string]mcpgo.ConfirmationFunc
+	if c.ConfirmHighRisk {
+		confirmations = highRiskConfirmations(c.ConfirmRefundAbove)
+	}
+
+	var policy mcpgo.PolicyFunc
+	if !c.Policy.isZero() {
+		policy = c.Policy.check
+	}
+
+	return []mcpgo.ServerOption{
+		mcpgo.WithToolDescriptions(descriptions),
+		mcpgo.WithToolTimeouts(timeouts),
+		mcpgo.WithToolsetDefaults(defaults),
+		mcpgo.WithLocale(c.Locale),
+		mcpgo.WithAmountUnit(c.AmountUnit),
+		c.amountChecksOption(),
+		mcpgo.WithKeyMode(c.Mode, func(ctx context.Context) string {
+			return clientKey(ctx, client)
+		}),
+		mcpgo.WithConfirmations(confirmations),
+		mcpgo.WithPolicy(policy),
+		mcpgo.WithCallContext(c.callContext()),
+		c.accountsOption(client),
+		mcpgo.WithPartnerAuth(c.Partner),
+	}, nil
+}
+
+// callContext returns the func that records the domain of generated emails
+// in the context of tool calls, nil if the default domain is used
+func (c Config) callContext() mcpgo.CallContextFunc {
+	if c.GeneratedEmailDomain == "" && !c.DisableGeneratedEmail {
+		return nil
+	}
+
+	domain := c.GeneratedEmailDomain
+	if c.DisableGeneratedEmail {
+		domain = ""
+	}
+	return func(ctx context.Context) context.Context {
+		return contextkey.WithGeneratedEmailDomain(ctx, domain)
+	}
+}
+
+// clientKey returns the API key of the client tool calls are made with,
+// the client of the context or the default client
+func clientKey(ctx context.Context, defaultClient *rzpsdk.Client) string {
+	client, err := getClientFromContextOrDefault(ctx, defaultClient)
+	if err != nil {
+		return ""
+	}
+	return client.Request.Auth.Key
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,349 @@
+package razorpay
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+)
+
+// newConfiguredServer creates a server with every toolset enabled and the
+// given config applied
+func newConfiguredServer(
+	t *testing.T,
+	config Config,
+) (*mcpgo.Mark3labsImpl, error) {
+	t.Helper()
+
+	obs := CreateTestObservability()
+	client := rzpsdk.NewClient("test-key", "test-secret")
+	server, err := NewRzpMcpServer(obs, client, []string{}, nil, nil, false,
+		WithConfig(config))
+	if err != nil {
+		return nil, err
+	}
+
+	impl, ok := server.(*mcpgo.Mark3labsImpl)
+	require.True(t, ok)
+	return impl, nil
+}
+
+func TestConfig(t *testing.T) {
+	enabled := false
+
+	t.Run("applies toolset settings", func(t *testing.T) {
+		server, err := newConfiguredServer(t, Config{
+			Toolsets: map[string]ToolsetConfig{
+				"orders": {
+					ReadOnly:        true,
+					DefaultCurrency: "USD",
+				},
+				"payouts": {Enabled: &enabled},
+				"payment_links": {
+					DefaultCurrency: "USD",
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		tools := server.McpServer.ListTools()
+		assert.Contains(t, tools, "fetch_order")
+		assert.NotContains(t, tools, "create_order")
+		assert.NotContains(t, tools, "fetch_payout")
+
+		tool := tools["create_payment_link"]
+		require.NotNil(t, tool)
+		assert.NotContains(t, tool.Tool.InputSchema.Required, "currency")
+		properties := tool.Tool.InputSchema.Properties
+		property, ok := properties["currency"].(map[string]any)
+		require.True(t, ok)
+		assert.Equal(t, "USD", property["default"])
+	})
+
+	t.Run("applies default capture to create_order", func(t *testing.T) {
+		server, err := newConfiguredServer(t, Config{
+			Toolsets: map[string]ToolsetConfig{
+				"orders": {DefaultCapture: "manual"},
+			},
+		})
+		require.NoError(t, err)
+
+		tool := server.McpServer.GetTool("create_order")
+		require.NotNil(t, tool)
+		properties := tool.Tool.InputSchema.Properties
+		property, ok := properties["payment_capture"].(map[string]any)
+		require.True(t, ok)
+		assert.Equal(t, "manual", property["default"])
+	})
+
+	t.Run("overrides tool descriptions", func(t *testing.T) {
+		server, err := newConfiguredServer(t, Config{
+			Tools: map[string]ToolConfig{
+				"fetch_payment": {Description: "Looks up a payment"},
+			},
+		})
+		require.NoError(t, err)
+
+		tool := server.McpServer.GetTool("fetch_payment")
+		require.NotNil(t, tool)
+		assert.Equal(t, "Looks up a payment", tool.Tool.Description)
+	})
+
+	t.Run("accepts tools with only a timeout", func(t *testing.T) {
+		timeout := time.Minute
+		_, err := newConfiguredServer(t, Config{
+			Tools: map[string]ToolConfig{
+				"fetch_order": {Timeout: &timeout},
+			},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("reports every invalid setting", func(t *testing.T) {
+		negative := -time.Second
+		_, err := newConfiguredServer(t, Config{
+			Toolsets: map[string]ToolsetConfig{
+				"paymnts": {ReadOnly: true},
+				"orders": {
+					DefaultCurrency: "usd",
+					DefaultCapture:  "later",
+				},
+			},
+			Tools: map[string]ToolConfig{
+				"fetch_paymnt":  {Description: "Looks up a payment"},
+				"fetch_payment": {Description: " "},
+				"fetch_order":   {Timeout: &negative},
+			},
+		})
+		require.Error(t, err)
+
+		message := err.Error()
+		assert.Contains(t, message, "invalid config")
+		assert.Contains(t, message, "toolset_config.paymnts: toolset "+
+			"does not exist, available toolsets are customers, disputes")
+		assert.Contains(t, message, `toolset_config.orders.`+
+			`default_currency: "usd" is not a 3 letter uppercase ISO `+
+			`currency code`)
+		assert.Contains(t, message, `toolset_config.orders.`+
+			`default_capture: "later" must be automatic or manual`)
+		assert.Contains(t, message,
+			"tool_config.fetch_paymnt: tool does not exist")
+		assert.Contains(t, message,
+			"tool_config.fetch_payment.description: must not be empty")
+		assert.Contains(t, message,
+			"tool_config.fetch_order.timeout: -1s must not be negative")
+		assert.NotContains(t, message, "fetch_order.description")
+	})
+}
+
+func TestConfigLocale(t *testing.T) {
+	t.Run("serves translated tool descriptions", func(t *testing.T) {
+		server, err := newConfiguredServer(t, Config{
+			Locale: "hi",
+			Tools: map[string]ToolConfig{
+				"fetch_refund": {Description: "Looks up a refund"},
+			},
+		})
+		require.NoError(t, err)
+
+		tool := server.McpServer.GetTool("fetch_order")
+		require.NotNil(t, tool)
+		assert.Equal(t, "किसी ऑर्डर का विवरण उसकी ID से प्राप्त करें",
+			tool.Tool.Description)
+
+		tool = server.McpServer.GetTool("fetch_refund")
+		require.NotNil(t, tool)
+		assert.Equal(t, "Looks up a refund", tool.Tool.Description)
+	})
+
+	t.Run("rejects unsupported locales", func(t *testing.T) {
+		_, err := newConfiguredServer(t, Config{Locale: "xx"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `locale "xx" is not supported, `+
+			"supported locales are en, hi")
+	})
+}
+
+func TestConfigMode(t *testing.T) {
+	// callCreateOrder calls create_order on a server in test mode whose
+	// default client has the key
+	callCreateOrder := func(
+		t *testing.T,
+		ctx context.Context,
+		key string,
+	) *mcp.CallToolResult {
+		t.Helper()
+
+		obs := CreateTestObservability()
+		client := rzpsdk.NewClient(key, "test-secret")
+		server, err := NewRzpMcpServer(obs, client, []string{"orders"},
+			nil, nil, false, WithConfig(Config{Mode: "test"}))
+		require.NoError(t, err)
+		impl, ok := server.(*mcpgo.Mark3labsImpl)
+		require.True(t, ok)
+
+		tool := impl.McpServer.GetTool("create_order")
+		require.NotNil(t, tool)
+		result, err := tool.Handler(ctx, mcp.CallToolRequest{})
+		require.NoError(t, err)
+		return result
+	}
+
+	// errorCode returns the code of a structured error result
+	errorCode := func(result *mcp.CallToolResult) interface{} {
+		content, _ := result.StructuredContent.(map[string]interface{})
+		details, _ := content["error"].(map[string]interface{})
+		return details["code"]
+	}
+
+	t.Run("rejects write tools with a live key", func(t *testing.T) {
+		result := callCreateOrder(t, context.Background(), "rzp_live_abc")
+		assert.True(t, result.IsError)
+		assert.Equal(t, mcpgo.KeyModeErrorCode, errorCode(result))
+	})
+
+	t.Run("allows write tools with a test key", func(t *testing.T) {
+		result := callCreateOrder(t, context.Background(), "rzp_test_abc")
+		assert.NotEqual(t, mcpgo.KeyModeErrorCode, errorCode(result))
+	})
+
+	t.Run("checks the key of the request client", func(t *testing.T) {
+		ctx := contextkey.WithClient(context.Background(),
+			rzpsdk.NewClient("rzp_live_abc", "test-secret"))
+
+		result := callCreateOrder(t, ctx, "rzp_test_abc")
+		assert.True(t, result.IsError)
+		assert.Equal(t, mcpgo.KeyModeErrorCode, errorCode(result))
+	})
+
+	t.Run("rejects unknown modes", func(t *testing.T) {
+		_, err := newConfiguredServer(t, Config{Mode: "staging"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `mode: "staging" must be test or live`)
+	})
+}
+
+func TestConfigAmountUnit(t *testing.T) {
+	t.Run("creates orders in the unit of the config", func(t *testing.T) {
+		var body map[string]interface{}
+		client, _ := newMockRzpClient(func() (
+			*http.Client, *httptest.Server) {
+			server := httptest.NewServer(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					_ = json.NewDecoder(r.Body).Decode(&body)
+					_, _ = w.Write([]byte(`{"id": "order_1"}`))
+				}))
+			t.Cleanup(server.Close)
+			return server.Client(), server
+		})
+		server, err := NewRzpMcpServer(CreateTestObservability(), client,
+			[]string{"orders"}, nil, nil, false,
+			WithConfig(Config{AmountUnit: "rupees"}))
+		require.NoError(t, err)
+		impl, ok := server.(*mcpgo.Mark3labsImpl)
+		require.True(t, ok)
+
+		tool := impl.McpServer.GetTool("create_order")
+		require.NotNil(t, tool)
+		result, err := tool.Handler(context.Background(), mcp.CallToolRequest{
+			Params: mcp.CallToolParams{
+				Name: "create_order",
+				Arguments: map[string]interface{}{
+					"amount":   295.5,
+					"currency": "INR",
+				},
+			},
+		})
+		require.NoError(t, err)
+		assert.False(t, result.IsError)
+		assert.Equal(t, float64(29550), body["amount"])
+	})
+
+	t.Run("rejects unknown units", func(t *testing.T) {
+		_, err := newConfiguredServer(t, Config{AmountUnit: "dollars"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(),
+			`amount_unit: "dollars" must be paise or rupees`)
+	})
+}
+
+func TestConfigGeneratedEmail(t *testing.T) {
+	// domainOf returns the domain of generated emails in calls prepared by
+	// the config
+	domainOf := func(config Config) string {
+		return generatedEmailDomain(
+			config.callContext()(context.Background()))
+	}
+
+	t.Run("keeps the default domain", func(t *testing.T) {
+		assert.Nil(t, Config{}.callContext())
+	})
+
+	t.Run("records the domain", func(t *testing.T) {
+		assert.Equal(t, "example.com",
+			domainOf(Config{GeneratedEmailDomain: "example.com"}))
+	})
+
+	t.Run("disables generated emails", func(t *testing.T) {
+		assert.Empty(t, domainOf(Config{DisableGeneratedEmail: true}))
+		assert.Empty(t, domainOf(Config{
+			GeneratedEmailDomain:  "example.com",
+			DisableGeneratedEmail: true,
+		}))
+	})
+
+	t.Run("rejects invalid domains", func(t *testing.T) {
+		_, err := newConfiguredServer(t, Config{
+			GeneratedEmailDomain: "@example.com",
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `generated_email_domain: `+
+			`"@example.com" is not a domain name such as example.com`)
+	})
+}
+
+func TestConfigConfirmation(t *testing.T) {
+	t.Run("asks to confirm high-risk calls", func(t *testing.T) {
+		server, err := newConfiguredServer(t, Config{
+			ConfirmHighRisk:    true,
+			ConfirmRefundAbove: 10000,
+		})
+		require.NoError(t, err)
+
+		tool := server.McpServer.GetTool("create_refund")
+		require.NotNil(t, tool)
+		result, err := tool.Handler(context.Background(), mcp.CallToolRequest{
+			Params: mcp.CallToolParams{
+				Name: "create_refund",
+				Arguments: map[string]interface{}{
+					"payment_id": "pay_1",
+					"amount":     float64(50000),
+				},
+			},
+		})
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		content, _ := result.StructuredContent.(map[string]interface{})
+		details, _ := content["error"].(map[string]interface{})
+		assert.Equal(t, mcpgo.ConfirmationUnavailableErrorCode,
+			details["code"])
+	})
+
+	t.Run("rejects negative refund amounts", func(t *testing.T) {
+		_, err := newConfiguredServer(t, Config{ConfirmRefundAbove: -1})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(),
+			"confirm_refund_above: -1 must not be negative")
+	})
+}
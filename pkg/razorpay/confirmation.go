@@ -0,0 +1,64 @@
+package razorpay
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+)
+
+// highRiskConfirmations returns the confirmations the user is asked for
+// before high-risk calls run: refunds above refundAmount, in currency
+// subunits, instant settlements and token revocations
+func highRiskConfirmations(
+	refundAmount int64,
+) map[string]mcpgo.ConfirmationFunc {
+	return map[string]mcpgo.ConfirmationFunc{
+		"create_refund": func(args map[string]interface{}) string {
+			return confirmRefund(args, refundAmount)
+		},
+		"create_bulk_refunds": func(args map[string]interface{}) string {
+			refunds, _ := args["refunds"].([]interface{})
+			var questions []string
+			for _, refund := range refunds {
+				refundArgs, _ := refund.(map[string]interface{})
+				if question := confirmRefund(refundArgs,
+					refundAmount); question != "" {
+					questions = append(questions, question)
+				}
+			}
+			return strings.Join(questions, "\n")
+		},
+		"create_instant_settlement": func(args map[string]interface{}) string {
+			if fullBalance, _ := args["settle_full_balance"].(bool); fullBalance {
+				return "Settle the full available balance to the bank account " +
+					"instantly?"
+			}
+			amount, _ := args["amount"].(float64)
+			return fmt.Sprintf("Settle %.0f (in currency subunits) to the "+
+				"bank account instantly?", amount)
+		},
+		"revoke_token": func(args map[string]interface{}) string {
+			return fmt.Sprintf("Revoke token %v of customer %v? It can no "+
+				"longer be used for payments.", args["token_id"],
+				args["customer_id"])
+		},
+	}
+}
+
+// confirmRefund returns the question the user must confirm before a refund
+// runs, or an empty string if its amount is at most refundAmount. Refunds
+// without an amount refund the whole payment, so they are always
+// confirmed.
+func confirmRefund(args map[string]interface{}, refundAmount int64) string {
+	amount, ok := args["amount"].(float64)
+	if !ok {
+		return fmt.Sprintf("Refund the full amount of payment %v?",
+			args["payment_id"])
+	}
+	if amount <= float64(refundAmount) {
+		return ""
+	}
+	return fmt.Sprintf("Refund %.0f (in currency subunits) of payment %v?",
+		amount, args["payment_id"])
+}
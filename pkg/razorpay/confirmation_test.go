@@ -0,0 +1,105 @@
+package razorpay
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHighRiskConfirmations(t *testing.T) {
+	confirmations := highRiskConfirmations(10000)
+
+	tests := []struct {
+		name     string
+		tool     string
+		args     map[string]interface{}
+		question string
+	}{
+		{
+			name: "refund up to the amount",
+			tool: "create_refund",
+			args: map[string]interface{}{
+				"payment_id": "pay_1",
+				"amount":     float64(10000),
+			},
+		},
+		{
+			name: "refund above the amount",
+			tool: "create_refund",
+			args: map[string]interface{}{
+				"payment_id": "pay_1",
+				"amount":     float64(1500000),
+			},
+			question: "Refund 1500000 (in currency subunits) of payment " +
+				"pay_1?",
+		},
+		{
+			name:     "full refund",
+			tool:     "create_refund",
+			args:     map[string]interface{}{"payment_id": "pay_1"},
+			question: "Refund the full amount of payment pay_1?",
+		},
+		{
+			name: "bulk refunds above the amount",
+			tool: "create_bulk_refunds",
+			args: map[string]interface{}{
+				"refunds": []interface{}{
+					map[string]interface{}{
+						"payment_id": "pay_1",
+						"amount":     float64(500),
+					},
+					map[string]interface{}{
+						"payment_id": "pay_2",
+						"amount":     float64(20000),
+					},
+				},
+			},
+			question: "Refund 20000 (in currency subunits) of payment " +
+				"pay_2?",
+		},
+		{
+			name: "bulk refunds up to the amount",
+			tool: "create_bulk_refunds",
+			args: map[string]interface{}{
+				"refunds": []interface{}{
+					map[string]interface{}{
+						"payment_id": "pay_1",
+						"amount":     float64(500),
+					},
+				},
+			},
+		},
+		{
+			name: "instant settlement",
+			tool: "create_instant_settlement",
+			args: map[string]interface{}{"amount": float64(20000)},
+			question: "Settle 20000 (in currency subunits) to the bank " +
+				"account instantly?",
+		},
+		{
+			name: "instant settlement of the full balance",
+			tool: "create_instant_settlement",
+			args: map[string]interface{}{"settle_full_balance": true},
+			question: "Settle the full available balance to the bank " +
+				"account instantly?",
+		},
+		{
+			name: "token revocation",
+			tool: "revoke_token",
+			args: map[string]interface{}{
+				"customer_id": "cust_1",
+				"token_id":    "token_1",
+			},
+			question: "Revoke token token_1 of customer cust_1? It can no " +
+				"longer be used for payments.",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			confirmation, ok := confirmations[tc.tool]
+			assert.True(t, ok)
+			assert.Equal(t, tc.question, confirmation(tc.args))
+		})
+	}
+}
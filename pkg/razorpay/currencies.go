@@ -0,0 +1,102 @@
+package razorpay
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+)
+
+// isoCurrencies holds the active ISO 4217 currency codes
+var isoCurrencies = currencySet("AED AFN ALL AMD ANG AOA ARS AUD AWG AZN " +
+	"BAM BBD BDT BGN BHD BIF BMD BND BOB BRL BSD BTN BWP BYN BZD CAD CDF " +
+	"CHF CLP CNY COP CRC CUP CVE CZK DJF DKK DOP DZD EGP ERN ETB EUR FJD " +
+	"FKP GBP GEL GHS GIP GMD GNF GTQ GYD HKD HNL HTG HUF IDR ILS INR IQD " +
+	"IRR ISK JMD JOD JPY KES KGS KHR KMF KPW KRW KWD KYD KZT LAK LBP LKR " +
+	"LRD LSL LYD MAD MDL MGA MKD MMK MNT MOP MRU MUR MVR MWK MXN MYR MZN " +
+	"NAD NGN NIO NOK NPR NZD OMR PAB PEN PGK PHP PKR PLN PYG QAR RON RSD " +
+	"RUB RWF SAR SBD SCR SDG SEK SGD SHP SLE SLL SOS SRD SSP STN SVC SYP " +
+	"SZL THB TJS TMT TND TOP TRY TTD TWD TZS UAH UGX USD UYU UZS VED VES " +
+	"VND VUV WST XAF XCD XCG XOF XPF YER ZAR ZMW ZWG ZWL")
+
+// currencySet returns the set of the space separated currency codes
+func currencySet(codes string) map[string]bool {
+	set := make(map[string]bool)
+	for _, code := range strings.Fields(codes) {
+		set[code] = true
+	}
+	return set
+}
+
+// defaultMinAmounts are the smallest amounts accepted per currency, in
+// currency subunits, for currencies the config sets none for
+var defaultMinAmounts = map[string]int64{
+	"INR": 100,
+}
+
+// CurrencyConfig holds the settings of a currency
+type CurrencyConfig struct {
+	// MinAmount is the smallest amount accepted in the currency, in
+	// currency subunits. 0 sets no minimum.
+	MinAmount int64 `mapstructure:"min_amount"`
+}
+
+// validateCurrencies reports every invalid currency setting
+func (c Config) validateCurrencies() []error {
+	var errs []error
+	for _, code := range sortedKeys(c.Currencies) {
+		if !isoCurrencies[code] {
+			errs = append(errs, fmt.Errorf("currencies.%s: not an ISO 4217 "+
+				"currency code such as INR", code))
+		}
+		if c.Currencies[code].MinAmount < 0 {
+			errs = append(errs, fmt.Errorf("currencies.%s.min_amount: %d "+
+				"must not be negative", code, c.Currencies[code].MinAmount))
+		}
+	}
+	return errs
+}
+
+// checkCurrency returns the problem of a currency that is not an ISO 4217
+// currency code, listing the currencies the policy allows, if it limits
+// them
+func (c Config) checkCurrency(currency string) string {
+	if isoCurrencies[currency] {
+		return ""
+	}
+	if len(c.Policy.AllowedCurrencies) > 0 {
+		return fmt.Sprintf("%q is not supported, supported currencies are "+
+			"%s", currency, strings.Join(c.Policy.AllowedCurrencies, ", "))
+	}
+	return fmt.Sprintf("%q is not an ISO 4217 currency code, such as INR, "+
+		"USD or EUR", currency)
+}
+
+// checkAmount returns the problem of the amount of a call below the
+// minimum amount of its currency. Only the amount parameter, and the
+// amount fields of objects, such as the orders of a batch, are checked,
+// and only when the call passes their currency.
+func (c Config) checkAmount(name, currency string, amount float64) string {
+	field := name[strings.LastIndex(name, ".")+1:]
+	if field != "amount" || currency == "" {
+		return ""
+	}
+
+	minAmount, ok := defaultMinAmounts[currency]
+	if settings, configured := c.Currencies[currency]; configured {
+		minAmount, ok = settings.MinAmount, true
+	}
+	if !ok || amount >= float64(minAmount) {
+		return ""
+	}
+	return fmt.Sprintf("%s is below the minimum amount of %d for %s, "+
+		"amounts are in currency subunits",
+		strconv.FormatFloat(amount, 'f', -1, 64), minAmount, currency)
+}
+
+// amountChecksOption returns the server option that checks the currency
+// and amounts of calls to tools that declare amounts
+func (c Config) amountChecksOption() mcpgo.ServerOption {
+	return mcpgo.WithAmountChecks(c.checkCurrency, c.checkAmount)
+}
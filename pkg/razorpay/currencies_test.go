@@ -0,0 +1,275 @@
+package razorpay
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+)
+
+func TestCurrencies(t *testing.T) {
+	// callTool calls the tool on a server with the config, in dry-run
+	// mode, and returns the result of the call
+	callTool := func(
+		t *testing.T,
+		config Config,
+		name string,
+		args map[string]interface{},
+	) *mcp.CallToolResult {
+		t.Helper()
+
+		client := rzpsdk.NewClient("rzp_test_abc", "test-secret")
+		server, err := NewRzpMcpServer(CreateTestObservability(), client,
+			[]string{"orders", "refunds", "subscriptions"}, nil, nil, false,
+			WithConfig(config), mcpgo.WithDryRun(true))
+		require.NoError(t, err)
+		impl, ok := server.(*mcpgo.Mark3labsImpl)
+		require.True(t, ok)
+
+		tool := impl.McpServer.GetTool(name)
+		require.NotNil(t, tool)
+		result, err := tool.Handler(context.Background(), mcp.CallToolRequest{
+			Params: mcp.CallToolParams{
+				Name:      name,
+				Arguments: args,
+			},
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	// callCreateOrder calls create_order on a server with the config, and
+	// returns the error result of the call, nil if it passed the checks
+	callCreateOrder := func(
+		t *testing.T,
+		config Config,
+		args map[string]interface{},
+	) *mcp.CallToolResult {
+		t.Helper()
+
+		result := callTool(t, config, "create_order", args)
+		if !result.IsError {
+			return nil
+		}
+		return result
+	}
+
+	t.Run("rejects INR amounts below 100 paise", func(t *testing.T) {
+		result := callCreateOrder(t, Config{}, map[string]interface{}{
+			"amount":   float64(50),
+			"currency": "INR",
+		})
+
+		require.NotNil(t, result)
+		assert.Equal(t, "invalid amount: 50 is below the minimum amount of "+
+			"100 for INR, amounts are in currency subunits",
+			result.Content[0].(mcp.TextContent).Text)
+	})
+
+	t.Run("accepts amounts at the minimum", func(t *testing.T) {
+		result := callCreateOrder(t, Config{}, map[string]interface{}{
+			"amount":   float64(100),
+			"currency": "INR",
+		})
+
+		assert.Nil(t, result)
+	})
+
+	t.Run("applies the minimums of the config", func(t *testing.T) {
+		config := Config{Currencies: map[string]CurrencyConfig{
+			"USD": {MinAmount: 50},
+		}}
+
+		result := callCreateOrder(t, config, map[string]interface{}{
+			"amount":   float64(49),
+			"currency": "USD",
+		})
+		require.NotNil(t, result)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text,
+			"below the minimum amount of 50 for USD")
+
+		result = callCreateOrder(t, config, map[string]interface{}{
+			"amount":   float64(50),
+			"currency": "USD",
+		})
+		assert.Nil(t, result)
+	})
+
+	t.Run("rejects unknown currencies", func(t *testing.T) {
+		result := callCreateOrder(t, Config{}, map[string]interface{}{
+			"amount":   float64(29500),
+			"currency": "RSX",
+		})
+
+		require.NotNil(t, result)
+		assert.Equal(t, `invalid currency: "RSX" is not an ISO 4217 `+
+			"currency code, such as INR, USD or EUR",
+			result.Content[0].(mcp.TextContent).Text)
+	})
+
+	t.Run("lists the currencies the policy allows", func(t *testing.T) {
+		config := Config{Policy: PolicyConfig{
+			AllowedCurrencies: []string{"INR", "USD"},
+		}}
+
+		assert.Equal(t, `"RSX" is not supported, supported currencies are `+
+			"INR, USD", config.checkCurrency("RSX"))
+	})
+
+	t.Run("checks only the amount parameter", func(t *testing.T) {
+		assert.Empty(t, Config{}.checkAmount("first_payment_min_amount",
+			"INR", 1))
+		assert.Empty(t, Config{}.checkAmount("amount", "", 1))
+		assert.Empty(t, Config{}.checkAmount("amount", "JPY", 1))
+	})
+
+	t.Run("converts the amounts of batches and items from rupees",
+		func(t *testing.T) {
+			tests := []struct {
+				tool string
+				args map[string]interface{}
+			}{
+				{"create_orders_batch", map[string]interface{}{
+					"orders": []interface{}{map[string]interface{}{
+						"amount": float64(295), "currency": "INR",
+					}},
+				}},
+				{"create_bulk_refunds", map[string]interface{}{
+					"refunds": []interface{}{map[string]interface{}{
+						"payment_id": "pay_MT48CvBhIC98MQ",
+						"amount":     float64(295),
+					}},
+				}},
+				{"create_plan", map[string]interface{}{
+					"period": "monthly", "interval": float64(1),
+					"item": map[string]interface{}{
+						"name": "Pro", "amount": float64(295),
+						"currency": "INR",
+					},
+				}},
+				{"create_subscription_addon", map[string]interface{}{
+					"subscription_id": "sub_00000000000001",
+					"name":            "Setup fee",
+					"amount":          float64(295),
+				}},
+			}
+
+			for _, tc := range tests {
+				tc.args["amount_unit"] = "rupees"
+				result := callTool(t, Config{}, tc.tool, tc.args)
+
+				text := result.Content[0].(mcp.TextContent).Text
+				require.False(t, result.IsError, text)
+				var dryRun struct {
+					Requests []struct {
+						Body map[string]interface{} `json:"body"`
+					} `json:"requests"`
+				}
+				require.NoError(t, json.Unmarshal([]byte(text), &dryRun))
+				require.Len(t, dryRun.Requests, 1, tc.tool)
+				body := dryRun.Requests[0].Body
+				if item, ok := body["item"].(map[string]interface{}); ok {
+					body = item
+				}
+				assert.Equal(t, float64(29500), body["amount"], tc.tool)
+			}
+		})
+
+	t.Run("rejects batch orders below the minimum", func(t *testing.T) {
+		result := callTool(t, Config{}, "create_orders_batch",
+			map[string]interface{}{
+				"orders": []interface{}{
+					map[string]interface{}{
+						"amount": float64(29500), "currency": "INR",
+					},
+					map[string]interface{}{
+						"amount": float64(50), "currency": "INR",
+					},
+				},
+			})
+
+		require.True(t, result.IsError)
+		assert.Equal(t, "invalid orders[1].amount: 50 is below the minimum "+
+			"amount of 100 for INR, amounts are in currency subunits",
+			result.Content[0].(mcp.TextContent).Text)
+	})
+
+	t.Run("rejects plan items below the minimum", func(t *testing.T) {
+		result := callTool(t, Config{}, "create_plan", map[string]interface{}{
+			"period": "monthly", "interval": float64(1),
+			"item": map[string]interface{}{
+				"name": "Pro", "amount": float64(50), "currency": "INR",
+			},
+		})
+
+		require.True(t, result.IsError)
+		assert.Equal(t, "invalid item.amount: 50 is below the minimum "+
+			"amount of 100 for INR, amounts are in currency subunits",
+			result.Content[0].(mcp.TextContent).Text)
+	})
+
+	t.Run("rejects unknown currencies of batch orders and plan items",
+		func(t *testing.T) {
+			result := callTool(t, Config{}, "create_orders_batch",
+				map[string]interface{}{
+					"orders": []interface{}{map[string]interface{}{
+						"amount": float64(29500), "currency": "RSX",
+					}},
+				})
+
+			require.True(t, result.IsError)
+			assert.Equal(t, `invalid orders[0].currency: "RSX" is not an `+
+				"ISO 4217 currency code, such as INR, USD or EUR",
+				result.Content[0].(mcp.TextContent).Text)
+
+			result = callTool(t, Config{}, "create_plan",
+				map[string]interface{}{
+					"period": "monthly", "interval": float64(1),
+					"item": map[string]interface{}{
+						"name": "Pro", "amount": float64(29500),
+						"currency": "RSX",
+					},
+				})
+
+			require.True(t, result.IsError)
+			assert.Contains(t, result.Content[0].(mcp.TextContent).Text,
+				`invalid item.currency: "RSX" is not an ISO 4217`)
+		})
+
+	t.Run("rejects rupees for batch orders in other currencies",
+		func(t *testing.T) {
+			result := callTool(t, Config{}, "create_orders_batch",
+				map[string]interface{}{
+					"amount_unit": "rupees",
+					"orders": []interface{}{map[string]interface{}{
+						"amount": float64(295), "currency": "USD",
+					}},
+				})
+
+			require.True(t, result.IsError)
+			assert.Contains(t, result.Content[0].(mcp.TextContent).Text,
+				"rupees only apply to INR amounts, pass USD amounts in paise")
+		})
+
+	t.Run("rejects invalid currency settings", func(t *testing.T) {
+		_, err := newConfiguredServer(t, Config{
+			Currencies: map[string]CurrencyConfig{
+				"usd": {MinAmount: 50},
+				"INR": {MinAmount: -1},
+			},
+		})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "currencies.usd: not an ISO 4217 "+
+			"currency code such as INR")
+		assert.Contains(t, err.Error(), "currencies.INR.min_amount: -1 must "+
+			"not be negative")
+	})
+}
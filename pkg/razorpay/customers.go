@@ -0,0 +1,301 @@
+package razorpay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+)
+
+// gstinPattern matches an Indian GST identification number
+const gstinPattern = `^[0-9]{2}[A-Z]{5}[0-9]{4}[A-Z][1-9A-Z]Z[0-9A-Z]$`
+
+// customerDetailParameters returns the parameters describing a customer,
+// shared by the tools that create and edit customers
+func customerDetailParameters() []mcpgo.ToolParameter {
+	return []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"name",
+			mcpgo.Description("Name of the customer"),
+			mcpgo.Max(50),
+		),
+		mcpgo.WithString(
+			"contact",
+			mcpgo.Description("Phone number of the customer, with the "+
+				"country code"),
+		),
+		mcpgo.WithString(
+			"email",
+			mcpgo.Description("Email address of the customer"),
+		),
+		mcpgo.WithString(
+			"gstin",
+			mcpgo.Description("GST identification number of the customer"),
+			mcpgo.Pattern(gstinPattern),
+		),
+		mcpgo.WithObject(
+			"notes",
+			mcpgo.Description("Key-value pairs for additional information "+
+				"(max 15 pairs, 256 chars each)"),
+			mcpgo.MaxProperties(15),
+		),
+	}
+}
+
+// validateCustomerDetails adds the customer detail parameters of a request
+// to data
+func validateCustomerDetails(
+	v *Validator,
+	data map[string]interface{},
+) *Validator {
+	return v.
+		ValidateAndAddOptionalString(data, "name").
+		ValidateAndAddOptionalString(data, "contact").
+		ValidateAndAddOptionalString(data, "email").
+		ValidateAndAddOptionalString(data, "gstin").
+		ValidateAndAddOptionalMap(data, "notes")
+}
+
+// CreateCustomer returns a tool that creates a customer
+func CreateCustomer(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := append(customerDetailParameters(),
+		mcpgo.WithBoolean(
+			"fail_existing",
+			mcpgo.Description("Whether to fail if a customer with the same "+
+				"contact and email already exists. When false, the existing "+
+				"customer is returned instead. Defaults to true"),
+		),
+	)
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+		customerData := make(map[string]interface{})
+
+		validator := validateCustomerDetails(NewValidator(&r), customerData).
+			ValidateAndAddOptionalBool(fields, "fail_existing")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		// The API expects fail_existing as "1" or "0"
+		if failExisting, ok := fields["fail_existing"].(bool); ok {
+			if failExisting {
+				customerData["fail_existing"] = "1"
+			} else {
+				customerData["fail_existing"] = "0"
+			}
+		}
+
+		customer, err := client.Customer.Create(customerData, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("creating customer failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(customer)
+	}
+
+	return mcpgo.NewTool(
+		"create_customer",
+		"Create a customer with their contact details, GSTIN and notes. "+
+			"The customer ID in the response can be used when creating "+
+			"payments, invoices and tokens.",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema)
+}
+
+// FetchCustomer returns a tool that fetches a customer by their ID
+func FetchCustomer(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"customer_id",
+			mcpgo.Description("ID of the customer to be fetched "+
+				"(ID should have a cust_ prefix)."),
+			mcpgo.Required(),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(fields, "customer_id")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		customerID := fields["customer_id"].(string)
+
+		if result := validateID("customer_id", customerID,
+			customerIDPrefix); result != nil {
+			return result, nil
+		}
+
+		customer, err := client.Customer.Fetch(customerID, nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching customer failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(customer)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_customer",
+		"Fetch the details of a customer, including their contact details, "+
+			"GSTIN and notes",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema).
+		WithFieldSelection()
+}
+
+// FetchAllCustomers returns a tool that fetches all customers
+func FetchAllCustomers(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := append([]mcpgo.ToolParameter{
+		mcpgo.WithNumber(
+			"count",
+			mcpgo.Description("Number of customers to be fetched. "+
+				"Default value is 10. Maximum value is 100"),
+			mcpgo.Min(1),
+			mcpgo.Max(100),
+		),
+		mcpgo.WithNumber(
+			"skip",
+			mcpgo.Description("Number of customers to be skipped. "+
+				"Default value is 0. This can be used for pagination"),
+			mcpgo.Min(0),
+		),
+	}, collectionParameters()...)
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		queryParams := make(map[string]interface{})
+		pagination := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddPagination(queryParams).
+			ValidateAndAddCollectionOptions(pagination)
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		customers, err := fetchCollection(queryParams, pagination,
+			client.Customer.All)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching customers failed: %s", err.Error())), nil
+		}
+
+		return collectionResult(ctx, customers, pagination)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_all_customers",
+		"Fetch all customers with pagination",
+		parameters,
+		handler,
+	).WithOutputSchema(entitiesOutputSchema).
+		WithFieldSelection()
+}
+
+// EditCustomer returns a tool that edits the details of a customer
+func EditCustomer(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := append([]mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"customer_id",
+			mcpgo.Description("ID of the customer to be edited "+
+				"(ID should have a cust_ prefix)."),
+			mcpgo.Required(),
+		),
+	}, customerDetailParameters()...)
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+		customerData := make(map[string]interface{})
+
+		validator := validateCustomerDetails(NewValidator(&r).
+			ValidateAndAddRequiredString(fields, "customer_id"), customerData)
+
+		if !validator.HasErrors() && len(customerData) == 0 {
+			validator.addError(errors.New("no fields to update"))
+		}
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		customerID := fields["customer_id"].(string)
+
+		if result := validateID("customer_id", customerID,
+			customerIDPrefix); result != nil {
+			return result, nil
+		}
+
+		customer, err := client.Customer.Edit(customerID, customerData, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("editing customer failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(customer)
+	}
+
+	return mcpgo.NewTool(
+		"edit_customer",
+		"Edit the name, contact details, GSTIN or notes of a customer",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema)
+}
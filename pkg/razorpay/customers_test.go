@@ -0,0 +1,280 @@
+package razorpay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/razorpay/razorpay-go/constants"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay/mock"
+)
+
+var (
+	customersPath = fmt.Sprintf(
+		"/%s%s",
+		constants.VERSION_V1,
+		constants.CUSTOMER_URL,
+	)
+	customerPath = customersPath + "/cust_1Aa00000000003"
+)
+
+var customerResp = map[string]interface{}{
+	"id":      "cust_1Aa00000000003",
+	"entity":  "customer",
+	"name":    "Gaurav Kumar",
+	"email":   "gaurav.kumar@example.com",
+	"contact": "+919000000000",
+	"gstin":   "29XAbbA4369J1PA",
+	"notes": map[string]interface{}{
+		"region": "south",
+	},
+}
+
+var customerErrorResp = map[string]interface{}{
+	"error": map[string]interface{}{
+		"code":        "BAD_REQUEST_ERROR",
+		"description": "Customer already exists for the merchant",
+	},
+}
+
+func Test_CreateCustomer(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful customer creation",
+			Request: map[string]interface{}{
+				"name":    "Gaurav Kumar",
+				"email":   "gaurav.kumar@example.com",
+				"contact": "+919000000000",
+				"gstin":   "29XAbbA4369J1PA",
+				"notes": map[string]interface{}{
+					"region": "south",
+				},
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     customersPath,
+						Method:   "POST",
+						Response: customerResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: customerResp,
+		},
+		{
+			Name: "customer creation fails",
+			Request: map[string]interface{}{
+				"contact": "+919000000000",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     customersPath,
+						Method:   "POST",
+						Response: customerErrorResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "creating customer failed: " +
+				"Customer already exists for the merchant",
+		},
+		{
+			Name: "invalid notes type",
+			Request: map[string]interface{}{
+				"notes": "south",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid parameter type: notes",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, CreateCustomer, "Customer")
+		})
+	}
+
+	t.Run("fail_existing is sent as a flag", func(t *testing.T) {
+		var body map[string]interface{}
+
+		mockClient := func() (*http.Client, *httptest.Server) {
+			server := httptest.NewServer(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					_ = json.NewDecoder(r.Body).Decode(&body)
+					_ = json.NewEncoder(w).Encode(customerResp)
+				}))
+			return server.Client(), server
+		}
+
+		client, server := newMockRzpClient(mockClient)
+		defer server.Close()
+
+		tool := CreateCustomer(CreateTestObservability(), client)
+		result, err := tool.GetHandler()(context.Background(),
+			createMCPRequest(map[string]interface{}{
+				"contact":       "+919000000000",
+				"fail_existing": false,
+			}))
+		require.NoError(t, err)
+		require.False(t, result.IsError, result.Text)
+
+		assert.Equal(t, map[string]interface{}{
+			"contact":       "+919000000000",
+			"fail_existing": "0",
+		}, body)
+	})
+}
+
+func Test_FetchCustomer(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful customer fetch",
+			Request: map[string]interface{}{
+				"customer_id": "cust_1Aa00000000003",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     customerPath,
+						Method:   "GET",
+						Response: customerResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: customerResp,
+		},
+		{
+			Name:           "missing customer_id",
+			Request:        map[string]interface{}{},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: customer_id",
+		},
+		{
+			Name: "customer_id with a path",
+			Request: map[string]interface{}{
+				"customer_id": "cust_1/../../payments",
+			},
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid customer_id: cust_1/../../payments",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchCustomer, "Customer")
+		})
+	}
+}
+
+func Test_FetchAllCustomers(t *testing.T) {
+	customersResp := map[string]interface{}{
+		"entity": "collection",
+		"count":  float64(1),
+		"items":  []interface{}{customerResp},
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful customers fetch",
+			Request: map[string]interface{}{
+				"count": float64(10),
+				"skip":  float64(0),
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     customersPath,
+						Method:   "GET",
+						Response: customersResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: customersResp,
+		},
+		{
+			Name: "invalid count type",
+			Request: map[string]interface{}{
+				"count": "ten",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid parameter type: count",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchAllCustomers, "Customers")
+		})
+	}
+}
+
+func Test_EditCustomer(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful customer edit",
+			Request: map[string]interface{}{
+				"customer_id": "cust_1Aa00000000003",
+				"gstin":       "29XAbbA4369J1PA",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     customerPath,
+						Method:   "PUT",
+						Response: customerResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: customerResp,
+		},
+		{
+			Name: "no fields to update",
+			Request: map[string]interface{}{
+				"customer_id": "cust_1Aa00000000003",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "no fields to update",
+		},
+		{
+			Name: "customer edit fails",
+			Request: map[string]interface{}{
+				"customer_id": "cust_1Aa00000000003",
+				"email":       "gaurav.kumar@example.com",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     customerPath,
+						Method:   "PUT",
+						Response: customerErrorResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "editing customer failed: " +
+				"Customer already exists for the merchant",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, EditCustomer, "Customer")
+		})
+	}
+}
@@ -0,0 +1,525 @@
+package razorpay
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+)
+
+// maxDisputeDocumentSize caps the size of a document downloaded by
+// the fetch_dispute_document tool (10 MB)
+const maxDisputeDocumentSize = 10 << 20
+
+// disputeEvidenceDocumentFields lists the evidence fields of a dispute that
+// hold the IDs of the documents supporting that type of proof
+var disputeEvidenceDocumentFields = []string{
+	"shipping_proof",
+	"billing_proof",
+	"cancellation_proof",
+	"customer_communication",
+	"proof_of_service",
+	"explanation_letter",
+	"refund_confirmation",
+	"access_activity_log",
+	"refund_cancellation_policy",
+	"term_and_conditions",
+}
+
+// FetchDispute returns a tool that fetches a dispute by its ID
+func FetchDispute(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"dispute_id",
+			mcpgo.Description("Unique identifier of the dispute. "+
+				"Must start with 'disp_'"),
+			mcpgo.Required(),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		params := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(params, "dispute_id")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		if result := validateID("dispute_id", params["dispute_id"].(string),
+			disputeIDPrefix); result != nil {
+			return result, nil
+		}
+
+		dispute, err := client.Dispute.Fetch(
+			params["dispute_id"].(string), nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching dispute failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(dispute)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_dispute",
+		"Fetch the details of a dispute (chargeback), including its status, "+
+			"reason, respond_by deadline and submitted evidence",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema).
+		WithFieldSelection()
+}
+
+// FetchAllDisputes returns a tool that fetches all disputes
+func FetchAllDisputes(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := append([]mcpgo.ToolParameter{
+		mcpgo.WithNumber(
+			"count",
+			mcpgo.Description("Number of disputes to be fetched. "+
+				"Default value is 10. Maximum value is 100"),
+			mcpgo.Min(1),
+			mcpgo.Max(100),
+		),
+		mcpgo.WithNumber(
+			"skip",
+			mcpgo.Description("Number of disputes to be skipped. "+
+				"Default value is 0. This can be used for pagination"),
+			mcpgo.Min(0),
+		),
+	}, collectionParameters()...)
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		queryParams := make(map[string]interface{})
+		pagination := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddPagination(queryParams).
+			ValidateAndAddCollectionOptions(pagination)
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		disputes, err := fetchCollection(queryParams, pagination, client.Dispute.All)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching disputes failed: %s", err.Error())), nil
+		}
+
+		return collectionResult(ctx, disputes, pagination)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_all_disputes",
+		"Fetch all disputes (chargebacks) raised against the merchant's "+
+			"payments",
+		parameters,
+		handler,
+	).WithOutputSchema(entitiesOutputSchema).
+		WithFieldSelection()
+}
+
+// AcceptDispute returns a tool that accepts a dispute
+func AcceptDispute(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"dispute_id",
+			mcpgo.Description("Unique identifier of the dispute to accept. "+
+				"Must start with 'disp_'"),
+			mcpgo.Required(),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		params := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(params, "dispute_id")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		if result := validateID("dispute_id", params["dispute_id"].(string),
+			disputeIDPrefix); result != nil {
+			return result, nil
+		}
+
+		dispute, err := client.Dispute.Accept(
+			params["dispute_id"].(string), nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("accepting dispute failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(dispute)
+	}
+
+	return mcpgo.NewTool(
+		"accept_dispute",
+		"Accept a dispute (chargeback). The disputed amount is debited from "+
+			"the merchant and the dispute is closed as lost. "+
+			"This cannot be undone.",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema)
+}
+
+// ContestDispute returns a tool that contests a dispute with evidence
+func ContestDispute(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"dispute_id",
+			mcpgo.Description("Unique identifier of the dispute to contest. "+
+				"Must start with 'disp_'"),
+			mcpgo.Required(),
+		),
+		mcpgo.WithNumber(
+			"amount",
+			mcpgo.Description("Amount being contested, in the smallest "+
+				"currency unit. Defaults to the full disputed amount"),
+			mcpgo.Min(1),
+		),
+		mcpgo.WithString(
+			"summary",
+			mcpgo.Description("Explanation of why the dispute is being "+
+				"contested (max 1000 characters)"),
+			mcpgo.Max(1000),
+		),
+		mcpgo.WithString(
+			"action",
+			mcpgo.Description("'draft' saves the evidence to be completed "+
+				"later, 'submit' sends it for review. Defaults to 'draft'"),
+			mcpgo.Enum("draft", "submit"),
+		),
+		mcpgo.WithArray(
+			"others",
+			mcpgo.Description("Additional evidence not covered by the other "+
+				"fields, as objects with a 'type' and a list of "+
+				"'document_ids'"),
+			mcpgo.Items(map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"type": map[string]interface{}{
+						"type": "string",
+					},
+					"document_ids": map[string]interface{}{
+						"type": "array",
+						"items": map[string]interface{}{
+							"type": "string",
+						},
+					},
+				},
+				"required": []string{"type", "document_ids"},
+			}),
+		),
+	}
+	for _, field := range disputeEvidenceDocumentFields {
+		parameters = append(parameters, mcpgo.WithArray(
+			field,
+			mcpgo.Description(fmt.Sprintf("IDs of the uploaded documents "+
+				"(starting with 'doc_') to submit as %s",
+				strings.ReplaceAll(field, "_", " "))),
+			mcpgo.Items(map[string]interface{}{
+				"type": "string",
+			}),
+		))
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		params := make(map[string]interface{})
+		contestData := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(params, "dispute_id").
+			ValidateAndAddOptionalInt(contestData, "amount").
+			ValidateAndAddOptionalString(contestData, "summary").
+			ValidateAndAddOptionalString(contestData, "action").
+			ValidateAndAddOptionalArray(contestData, "others")
+		for _, field := range disputeEvidenceDocumentFields {
+			validator = validator.ValidateAndAddOptionalArray(contestData, field)
+		}
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		if result := validateID("dispute_id", params["dispute_id"].(string),
+			disputeIDPrefix); result != nil {
+			return result, nil
+		}
+
+		dispute, err := client.Dispute.Contest(
+			params["dispute_id"].(string), contestData, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("contesting dispute failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(dispute)
+	}
+
+	return mcpgo.NewTool(
+		"contest_dispute",
+		"Contest a dispute (chargeback) by submitting evidence. Documents "+
+			"must be uploaded beforehand, such as with upload_document, and "+
+			"referenced by their IDs. Use action='draft' to save progress "+
+			"and action='submit' once all evidence is in place.",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema).WithAmounts("amount")
+}
+
+// FetchDisputeDocument returns a tool that fetches a document attached to
+// a dispute, optionally downloading its content
+func FetchDisputeDocument(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"dispute_id",
+			mcpgo.Description("Unique identifier of the dispute. "+
+				"Must start with 'disp_'"),
+			mcpgo.Required(),
+		),
+		mcpgo.WithString(
+			"document_id",
+			mcpgo.Description("Unique identifier of the document referenced "+
+				"in the dispute evidence. Must start with 'doc_'"),
+			mcpgo.Required(),
+		),
+		mcpgo.WithBoolean(
+			"download",
+			mcpgo.Description("Whether to download the document content. "+
+				"When true, the content is returned base64 encoded"),
+			mcpgo.DefaultValue(false),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		// Get client from context or use default
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		params := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(params, "dispute_id").
+			ValidateAndAddRequiredString(params, "document_id").
+			ValidateAndAddOptionalBool(params, "download")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		disputeID := params["dispute_id"].(string)
+		documentID := params["document_id"].(string)
+
+		if result := validateID("dispute_id", disputeID,
+			disputeIDPrefix); result != nil {
+			return result, nil
+		}
+
+		if result := validateID("document_id", documentID,
+			documentIDPrefix); result != nil {
+			return result, nil
+		}
+
+		dispute, err := client.Dispute.Fetch(disputeID, nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching dispute failed: %s", err.Error())), nil
+		}
+
+		if !referencesDocument(dispute["evidence"], documentID) {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("document %s is not part of dispute %s",
+					documentID, disputeID)), nil
+		}
+
+		document, err := client.Document.Fetch(documentID, nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching document failed: %s", err.Error())), nil
+		}
+
+		response := map[string]interface{}{
+			"dispute_id": disputeID,
+			"document":   document,
+		}
+
+		if params["download"] == true {
+			content, err := downloadDisputeDocument(ctx, client, document)
+			if err != nil {
+				return mcpgo.NewToolResultError(
+					fmt.Sprintf("downloading document failed: %s", err.Error())), nil
+			}
+			response["content_base64"] = base64.StdEncoding.EncodeToString(content)
+		}
+
+		return mcpgo.NewToolResultJSON(response)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_dispute_document",
+		"Fetch the metadata of a document submitted as evidence for a "+
+			"dispute. Set download=true to also return the document content "+
+			"as base64; use the document's mime_type to interpret it.",
+		parameters,
+		handler,
+	).WithOutputSchema(objectOutputSchema)
+}
+
+// referencesDocument reports whether the dispute evidence refers to the
+// given document ID anywhere in its (possibly nested) structure
+func referencesDocument(evidence interface{}, documentID string) bool {
+	switch v := evidence.(type) {
+	case string:
+		return v == documentID
+	case []interface{}:
+		for _, item := range v {
+			if referencesDocument(item, documentID) {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		for _, item := range v {
+			if referencesDocument(item, documentID) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// validateDocumentURL ensures a document is only downloaded over HTTPS from
+// a Razorpay domain, or from the API host the client is configured with
+func validateDocumentURL(rawURL, apiBaseURL string) (*url.URL, error) {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid document URL: %s", err.Error())
+	}
+
+	if apiURL, err := url.Parse(apiBaseURL); err == nil &&
+		parsedURL.Scheme == apiURL.Scheme && parsedURL.Host == apiURL.Host {
+		return parsedURL, nil
+	}
+
+	if parsedURL.Scheme != "https" {
+		return nil, fmt.Errorf("document URL must use HTTPS")
+	}
+
+	host := parsedURL.Hostname()
+	if host != "razorpay.com" && !strings.HasSuffix(host, ".razorpay.com") {
+		return nil, fmt.Errorf("document URL must be from Razorpay domain")
+	}
+
+	return parsedURL, nil
+}
+
+// downloadDisputeDocument downloads the content of a document using the
+// URL present in its metadata
+func downloadDisputeDocument(
+	ctx context.Context,
+	client *rzpsdk.Client,
+	document map[string]interface{},
+) ([]byte, error) {
+	rawURL, ok := document["url"].(string)
+	if !ok || rawURL == "" {
+		return nil, fmt.Errorf("document has no download URL")
+	}
+
+	docURL, err := validateDocumentURL(rawURL, client.Request.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, docURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(client.Request.Auth.Key, client.Request.Auth.Secret)
+
+	resp, err := client.Request.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected HTTP status: %d", resp.StatusCode)
+	}
+
+	content, err := io.ReadAll(
+		io.LimitReader(resp.Body, maxDisputeDocumentSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > maxDisputeDocumentSize {
+		return nil, fmt.Errorf("document exceeds %d bytes",
+			maxDisputeDocumentSize)
+	}
+
+	return content, nil
+}
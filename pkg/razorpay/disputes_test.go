@@ -0,0 +1,544 @@
+package razorpay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/razorpay/razorpay-go/constants"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay/mock"
+)
+
+func Test_FetchDisputeDocument(t *testing.T) {
+	fetchDisputePathFmt := fmt.Sprintf(
+		"/%s%s/%%s",
+		constants.VERSION_V1,
+		constants.DISPUTE,
+	)
+	fetchDocumentPathFmt := fmt.Sprintf(
+		"/%s%s/%%s",
+		constants.VERSION_V1,
+		constants.DOCUMENT,
+	)
+
+	disputeResp := map[string]interface{}{
+		"id":     "disp_AHfqOvkldwsbqt",
+		"entity": "dispute",
+		"status": "under_review",
+		"evidence": map[string]interface{}{
+			"amount":         float64(10000),
+			"shipping_proof": []interface{}{"doc_EFtmUsbwpXwBH9"},
+			"others": []interface{}{
+				map[string]interface{}{
+					"type":         "receipt_signed_by_customer",
+					"document_ids": []interface{}{"doc_EFtmUsbwpXwBG8"},
+				},
+			},
+		},
+	}
+
+	documentResp := map[string]interface{}{
+		"id":        "doc_EFtmUsbwpXwBH9",
+		"entity":    "document",
+		"purpose":   "dispute_evidence",
+		"name":      "shipping_proof.pdf",
+		"mime_type": "application/pdf",
+		"size":      float64(2863),
+	}
+
+	documentNotFoundResp := map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":        "BAD_REQUEST_ERROR",
+			"description": "The id provided does not exist",
+		},
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "metadata only",
+			Request: map[string]interface{}{
+				"dispute_id":  "disp_AHfqOvkldwsbqt",
+				"document_id": "doc_EFtmUsbwpXwBH9",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path: fmt.Sprintf(
+							fetchDisputePathFmt, "disp_AHfqOvkldwsbqt"),
+						Method:   "GET",
+						Response: disputeResp,
+					},
+					mock.Endpoint{
+						Path: fmt.Sprintf(
+							fetchDocumentPathFmt, "doc_EFtmUsbwpXwBH9"),
+						Method:   "GET",
+						Response: documentResp,
+					},
+				)
+			},
+			ExpectError: false,
+			ExpectedResult: map[string]interface{}{
+				"dispute_id": "disp_AHfqOvkldwsbqt",
+				"document":   documentResp,
+			},
+		},
+		{
+			Name: "document not found",
+			Request: map[string]interface{}{
+				"dispute_id":  "disp_AHfqOvkldwsbqt",
+				"document_id": "doc_EFtmUsbwpXwBG8",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path: fmt.Sprintf(
+							fetchDisputePathFmt, "disp_AHfqOvkldwsbqt"),
+						Method:   "GET",
+						Response: disputeResp,
+					},
+					mock.Endpoint{
+						Path: fmt.Sprintf(
+							fetchDocumentPathFmt, "doc_EFtmUsbwpXwBG8"),
+						Method:   "GET",
+						Response: documentNotFoundResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "fetching document failed: " +
+				"The id provided does not exist",
+		},
+		{
+			Name: "document not referenced by dispute",
+			Request: map[string]interface{}{
+				"dispute_id":  "disp_AHfqOvkldwsbqt",
+				"document_id": "doc_unrelated",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path: fmt.Sprintf(
+							fetchDisputePathFmt, "disp_AHfqOvkldwsbqt"),
+						Method:   "GET",
+						Response: disputeResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "document doc_unrelated is not part of " +
+				"dispute disp_AHfqOvkldwsbqt",
+		},
+		{
+			Name: "download rejects non razorpay host",
+			Request: map[string]interface{}{
+				"dispute_id":  "disp_AHfqOvkldwsbqt",
+				"document_id": "doc_EFtmUsbwpXwBH9",
+				"download":    true,
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				doc := map[string]interface{}{
+					"id":  "doc_EFtmUsbwpXwBH9",
+					"url": "https://evil.example.com/doc_EFtmUsbwpXwBH9",
+				}
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path: fmt.Sprintf(
+							fetchDisputePathFmt, "disp_AHfqOvkldwsbqt"),
+						Method:   "GET",
+						Response: disputeResp,
+					},
+					mock.Endpoint{
+						Path: fmt.Sprintf(
+							fetchDocumentPathFmt, "doc_EFtmUsbwpXwBH9"),
+						Method:   "GET",
+						Response: doc,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "downloading document failed: " +
+				"document URL must be from Razorpay domain",
+		},
+		{
+			Name: "missing required parameters",
+			Request: map[string]interface{}{
+				"download": true,
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: dispute_id\n- " +
+				"missing required parameter: document_id",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchDisputeDocument, "Dispute document")
+		})
+	}
+
+	t.Run("download returns base64 content", func(t *testing.T) {
+		contentPath := "/v1/documents/doc_EFtmUsbwpXwBH9/content"
+
+		mockClient := func() (*http.Client, *httptest.Server) {
+			server := httptest.NewServer(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					switch r.URL.Path {
+					case fmt.Sprintf(fetchDisputePathFmt, "disp_AHfqOvkldwsbqt"):
+						_ = json.NewEncoder(w).Encode(disputeResp)
+					case fmt.Sprintf(fetchDocumentPathFmt, "doc_EFtmUsbwpXwBH9"):
+						_ = json.NewEncoder(w).Encode(map[string]interface{}{
+							"id":        "doc_EFtmUsbwpXwBH9",
+							"mime_type": "application/pdf",
+							"url":       "http://" + r.Host + contentPath,
+						})
+					case contentPath:
+						_, _ = w.Write([]byte("%PDF-1.4"))
+					default:
+						w.WriteHeader(http.StatusNotFound)
+					}
+				}))
+			return server.Client(), server
+		}
+
+		client, server := newMockRzpClient(mockClient)
+		defer server.Close()
+
+		tool := FetchDisputeDocument(CreateTestObservability(), client)
+		result, err := tool.GetHandler()(context.Background(),
+			createMCPRequest(map[string]interface{}{
+				"dispute_id":  "disp_AHfqOvkldwsbqt",
+				"document_id": "doc_EFtmUsbwpXwBH9",
+				"download":    true,
+			}))
+		require.NoError(t, err)
+		require.False(t, result.IsError, result.Text)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(result.Text), &response))
+		assert.Equal(t, "JVBERi0xLjQ=", response["content_base64"])
+	})
+}
+
+func Test_validateDocumentURL(t *testing.T) {
+	apiBaseURL := "https://api.razorpay.com"
+
+	valid := []string{
+		"https://api.razorpay.com/v1/documents/doc_1/content",
+		"https://files.razorpay.com/doc_1",
+	}
+	for _, rawURL := range valid {
+		_, err := validateDocumentURL(rawURL, apiBaseURL)
+		assert.NoError(t, err, rawURL)
+	}
+
+	invalid := map[string]string{
+		"http://files.razorpay.com/doc_1":      "document URL must use HTTPS",
+		"https://razorpay.com.evil.com/doc_1":  "must be from Razorpay domain",
+		"https://notrazorpay.com/doc_1":        "must be from Razorpay domain",
+		"https://files.razorpay.com/%zz/doc_1": "invalid document URL",
+	}
+	for rawURL, errMsg := range invalid {
+		_, err := validateDocumentURL(rawURL, apiBaseURL)
+		if assert.Error(t, err, rawURL) {
+			assert.Contains(t, err.Error(), errMsg)
+		}
+	}
+}
+
+func Test_FetchDispute(t *testing.T) {
+	fetchDisputePath := fmt.Sprintf(
+		"/%s%s/disp_AHfqOvkldwsbqt",
+		constants.VERSION_V1,
+		constants.DISPUTE,
+	)
+
+	disputeResp := map[string]interface{}{
+		"id":          "disp_AHfqOvkldwsbqt",
+		"entity":      "dispute",
+		"payment_id":  "pay_EsyWjHrfzb59eR",
+		"amount":      float64(10000),
+		"currency":    "INR",
+		"reason_code": "chargeback",
+		"respond_by":  float64(1590604200),
+		"status":      "open",
+		"phase":       "chargeback",
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful dispute fetch",
+			Request: map[string]interface{}{
+				"dispute_id": "disp_AHfqOvkldwsbqt",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     fetchDisputePath,
+						Method:   "GET",
+						Response: disputeResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: disputeResp,
+		},
+		{
+			Name: "dispute not found",
+			Request: map[string]interface{}{
+				"dispute_id": "disp_AHfqOvkldwsbqt",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:   fetchDisputePath,
+						Method: "GET",
+						Response: map[string]interface{}{
+							"error": map[string]interface{}{
+								"code":        "BAD_REQUEST_ERROR",
+								"description": "The id provided does not exist",
+							},
+						},
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "fetching dispute failed: " +
+				"The id provided does not exist",
+		},
+		{
+			Name:           "missing dispute_id",
+			Request:        map[string]interface{}{},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: dispute_id",
+		},
+		{
+			Name: "dispute_id of another entity",
+			Request: map[string]interface{}{
+				"dispute_id": "pay_MT48CvBhIC98MQ",
+			},
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid dispute_id: pay_MT48CvBhIC98MQ",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchDispute, "Dispute")
+		})
+	}
+}
+
+func Test_FetchAllDisputes(t *testing.T) {
+	fetchAllDisputesPath := fmt.Sprintf(
+		"/%s%s",
+		constants.VERSION_V1,
+		constants.DISPUTE,
+	)
+
+	disputesResp := map[string]interface{}{
+		"entity": "collection",
+		"count":  float64(1),
+		"items": []interface{}{
+			map[string]interface{}{
+				"id":     "disp_AHfqOvkldwsbqt",
+				"entity": "dispute",
+				"status": "open",
+			},
+		},
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful disputes fetch",
+			Request: map[string]interface{}{
+				"count": float64(10),
+				"skip":  float64(0),
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     fetchAllDisputesPath,
+						Method:   "GET",
+						Response: disputesResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: disputesResp,
+		},
+		{
+			Name: "invalid count type",
+			Request: map[string]interface{}{
+				"count": "ten",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid parameter type: count",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchAllDisputes, "Disputes")
+		})
+	}
+}
+
+func Test_AcceptDispute(t *testing.T) {
+	acceptDisputePath := fmt.Sprintf(
+		"/%s%s/disp_AHfqOvkldwsbqt/accept",
+		constants.VERSION_V1,
+		constants.DISPUTE,
+	)
+
+	acceptedResp := map[string]interface{}{
+		"id":     "disp_AHfqOvkldwsbqt",
+		"entity": "dispute",
+		"status": "lost",
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful dispute acceptance",
+			Request: map[string]interface{}{
+				"dispute_id": "disp_AHfqOvkldwsbqt",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     acceptDisputePath,
+						Method:   "POST",
+						Response: acceptedResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: acceptedResp,
+		},
+		{
+			Name:           "missing dispute_id",
+			Request:        map[string]interface{}{},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: dispute_id",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, AcceptDispute, "Dispute")
+		})
+	}
+}
+
+func Test_ContestDispute(t *testing.T) {
+	contestDisputePath := fmt.Sprintf(
+		"/%s%s/disp_AHfqOvkldwsbqt/contest",
+		constants.VERSION_V1,
+		constants.DISPUTE,
+	)
+
+	contestedResp := map[string]interface{}{
+		"id":     "disp_AHfqOvkldwsbqt",
+		"entity": "dispute",
+		"status": "under_review",
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful dispute contest",
+			Request: map[string]interface{}{
+				"dispute_id":     "disp_AHfqOvkldwsbqt",
+				"summary":        "Goods were delivered to the customer",
+				"action":         "submit",
+				"shipping_proof": []interface{}{"doc_EFtmUsbwpXwBH9"},
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     contestDisputePath,
+						Method:   "PATCH",
+						Response: contestedResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: contestedResp,
+		},
+		{
+			Name: "invalid evidence type",
+			Request: map[string]interface{}{
+				"dispute_id":     "disp_AHfqOvkldwsbqt",
+				"shipping_proof": "doc_EFtmUsbwpXwBH9",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid parameter type: shipping_proof",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, ContestDispute, "Dispute")
+		})
+	}
+
+	t.Run("forwards evidence to the API", func(t *testing.T) {
+		var body map[string]interface{}
+
+		mockClient := func() (*http.Client, *httptest.Server) {
+			server := httptest.NewServer(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					assert.Equal(t, http.MethodPatch, r.Method)
+					assert.Equal(t, contestDisputePath, r.URL.Path)
+					_ = json.NewDecoder(r.Body).Decode(&body)
+					_ = json.NewEncoder(w).Encode(contestedResp)
+				}))
+			return server.Client(), server
+		}
+
+		client, server := newMockRzpClient(mockClient)
+		defer server.Close()
+
+		others := []interface{}{
+			map[string]interface{}{
+				"type":         "receipt_signed_by_customer",
+				"document_ids": []interface{}{"doc_EFtmUsbwpXwBG8"},
+			},
+		}
+
+		tool := ContestDispute(CreateTestObservability(), client)
+		result, err := tool.GetHandler()(context.Background(),
+			createMCPRequest(map[string]interface{}{
+				"dispute_id":    "disp_AHfqOvkldwsbqt",
+				"amount":        5000,
+				"action":        "draft",
+				"billing_proof": []interface{}{"doc_EFtmUsbwpXwBH9"},
+				"others":        others,
+				"proof_of_service": []interface{}{
+					"doc_EFtmUsbwpXwBH1", "doc_EFtmUsbwpXwBH2",
+				},
+			}))
+		require.NoError(t, err)
+		require.False(t, result.IsError, result.Text)
+
+		assert.Equal(t, map[string]interface{}{
+			"amount":        float64(5000),
+			"action":        "draft",
+			"billing_proof": []interface{}{"doc_EFtmUsbwpXwBH9"},
+			"others":        others,
+			"proof_of_service": []interface{}{
+				"doc_EFtmUsbwpXwBH1", "doc_EFtmUsbwpXwBH2",
+			},
+		}, body)
+	})
+}
@@ -0,0 +1,135 @@
+package razorpay
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+	"github.com/razorpay/razorpay-go/requests"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+)
+
+// maxUploadDocumentSize caps the size of a document uploaded by the
+// upload_document tool (2 MB), so that its base64 encoded content fits in
+// a request of the default maximum size
+const maxUploadDocumentSize = 2 << 20
+
+// UploadDocument returns a tool that uploads a document, such as dispute
+// evidence or a KYC document
+func UploadDocument(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"file",
+			mcpgo.Description("Content of the document, base64 encoded. "+
+				"At most 2 MB once decoded"),
+			mcpgo.Required(),
+			mcpgo.Base64(maxUploadDocumentSize),
+		),
+		mcpgo.WithString(
+			"file_name",
+			mcpgo.Description("Name of the document with its extension, "+
+				"such as invoice.pdf. Razorpay accepts jpg, jpeg, png and "+
+				"pdf files"),
+			mcpgo.Required(),
+		),
+		mcpgo.WithString(
+			"purpose",
+			mcpgo.Description("What the document is for, such as "+
+				"dispute_evidence"),
+			mcpgo.Required(),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredBase64(fields, "file",
+				maxUploadDocumentSize).
+			ValidateAndAddRequiredString(fields, "file_name").
+			ValidateAndAddRequiredString(fields, "purpose")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		fileName := filepath.Base(fields["file_name"].(string))
+		if fileName == "." || fileName == ".." ||
+			fileName == string(filepath.Separator) {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("invalid file_name: %s", fields["file_name"])), nil
+		}
+
+		// The SDK uploads files from disk, under their name
+		dir, err := os.MkdirTemp("", "razorpay-document-")
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("uploading document failed: %s", err.Error())), nil
+		}
+		defer os.RemoveAll(dir)
+
+		file, err := writeUploadFile(filepath.Join(dir, fileName),
+			fields["file"].([]byte))
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("uploading document failed: %s", err.Error())), nil
+		}
+		defer file.Close()
+
+		document, err := client.Document.Create(requests.FileUploadParams{
+			File: file,
+			Fields: map[string]string{
+				"purpose": fields["purpose"].(string),
+			},
+		}, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("uploading document failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(document)
+	}
+
+	return mcpgo.NewTool(
+		"upload_document",
+		"Upload a document, such as proof of delivery for a dispute, and "+
+			"get its ID (starting with 'doc_') to reference it in dispute "+
+			"evidence or account onboarding",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema)
+}
+
+// writeUploadFile writes the content to a new file at path, and returns it
+// open for reading from the start
+func writeUploadFile(path string, content []byte) (*os.File, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := file.Write(content); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return file, nil
+}
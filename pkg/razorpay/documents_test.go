@@ -0,0 +1,167 @@
+package razorpay
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/razorpay/razorpay-go/constants"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay/mock"
+)
+
+func Test_UploadDocument(t *testing.T) {
+	uploadDocumentPath := fmt.Sprintf(
+		"/%s%s",
+		constants.VERSION_V1,
+		constants.DOCUMENT,
+	)
+
+	documentResp := map[string]interface{}{
+		"id":         "doc_EsyWjHrfzb59Re",
+		"entity":     "document",
+		"purpose":    "dispute_evidence",
+		"name":       "shipping_proof.pdf",
+		"mime_type":  "application/pdf",
+		"size":       float64(9),
+		"created_at": float64(1590604200),
+	}
+
+	content := base64.StdEncoding.EncodeToString([]byte("%PDF-1.4\n"))
+
+	// uploadServer checks the multipart form of the upload before
+	// returning the document
+	uploadServer := func() (*http.Client, *httptest.Server) {
+		server := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				file, header, err := r.FormFile("file")
+				if err != nil || r.URL.Path != uploadDocumentPath ||
+					header.Filename != "shipping_proof.pdf" ||
+					r.FormValue("purpose") != "dispute_evidence" {
+					w.WriteHeader(http.StatusBadRequest)
+					_ = json.NewEncoder(w).Encode(map[string]interface{}{
+						"error": map[string]interface{}{
+							"code":        "BAD_REQUEST_ERROR",
+							"description": "unexpected upload",
+						},
+					})
+					return
+				}
+				defer file.Close()
+				data, _ := io.ReadAll(file)
+				if string(data) != "%PDF-1.4\n" {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(documentResp)
+			}))
+		return server.Client(), server
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful document upload",
+			Request: map[string]interface{}{
+				"file":      content,
+				"file_name": "shipping_proof.pdf",
+				"purpose":   "dispute_evidence",
+			},
+			MockHttpClient: uploadServer,
+			ExpectError:    false,
+			ExpectedResult: documentResp,
+		},
+		{
+			Name: "file name is reduced to its base name",
+			Request: map[string]interface{}{
+				"file":      content,
+				"file_name": "../../shipping_proof.pdf",
+				"purpose":   "dispute_evidence",
+			},
+			MockHttpClient: uploadServer,
+			ExpectError:    false,
+			ExpectedResult: documentResp,
+		},
+		{
+			Name: "document upload fails",
+			Request: map[string]interface{}{
+				"file":      content,
+				"file_name": "shipping_proof.pdf",
+				"purpose":   "dispute_evidence",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:   uploadDocumentPath,
+						Method: "POST",
+						Response: map[string]interface{}{
+							"error": map[string]interface{}{
+								"code":        "BAD_REQUEST_ERROR",
+								"description": "The file type is not supported",
+							},
+						},
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "uploading document failed: " +
+				"The file type is not supported",
+		},
+		{
+			Name: "invalid base64 content",
+			Request: map[string]interface{}{
+				"file":      "not base64!",
+				"file_name": "shipping_proof.pdf",
+				"purpose":   "dispute_evidence",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid file: content is not valid base64",
+		},
+		{
+			Name: "file too large",
+			Request: map[string]interface{}{
+				"file": base64.StdEncoding.EncodeToString(
+					[]byte(strings.Repeat("a", maxUploadDocumentSize+1))),
+				"file_name": "shipping_proof.pdf",
+				"purpose":   "dispute_evidence",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid file: content is larger than 2097152 " +
+				"bytes",
+		},
+		{
+			Name: "invalid file name",
+			Request: map[string]interface{}{
+				"file":      content,
+				"file_name": "..",
+				"purpose":   "dispute_evidence",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid file_name: ..",
+		},
+		{
+			Name: "missing purpose",
+			Request: map[string]interface{}{
+				"file":      content,
+				"file_name": "shipping_proof.pdf",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: purpose",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, UploadDocument, "Document")
+		})
+	}
+}
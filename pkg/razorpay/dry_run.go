@@ -0,0 +1,72 @@
+package razorpay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+)
+
+// errDryRun is returned for requests a dry run records instead of sending
+var errDryRun = errors.New("dry run: request not sent")
+
+// withDryRun returns a copy of the client that sends read requests and
+// records every other request in the dry run instead of sending it
+func withDryRun(client *rzpsdk.Client, dryRun *mcpgo.DryRun) *rzpsdk.Client {
+	return withTransport(client, func(
+		next http.RoundTripper,
+	) http.RoundTripper {
+		return &dryRunTransport{dryRun: dryRun, next: next}
+	})
+}
+
+// dryRunTransport records the requests that would modify data
+type dryRunTransport struct {
+	dryRun *mcpgo.DryRun
+	next   http.RoundTripper
+}
+
+func (t *dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		next := t.next
+		if next == nil {
+			next = http.DefaultTransport
+		}
+		return next.RoundTrip(req)
+	}
+
+	request := mcpgo.DryRunRequest{Method: req.Method, URL: req.URL.String()}
+	if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if len(data) > 0 {
+			var body interface{}
+			if json.Unmarshal(data, &body) == nil {
+				request.Body = body
+			} else {
+				request.Body = string(data)
+			}
+		}
+	}
+	t.dryRun.Record(request)
+
+	return nil, errDryRun
+}
+
+// withDryRunOf returns a copy of the client that records requests in the
+// dry run of ctx, or the client itself if the call is not a dry run
+func withDryRunOf(ctx context.Context, client *rzpsdk.Client) *rzpsdk.Client {
+	dryRun := mcpgo.DryRunFromContext(ctx)
+	if dryRun == nil {
+		return client
+	}
+	return withDryRun(client, dryRun)
+}
@@ -0,0 +1,72 @@
+package razorpay
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+)
+
+func TestDryRun(t *testing.T) {
+	t.Run("records write requests instead of sending them",
+		func(t *testing.T) {
+			sent := false
+			server := httptest.NewServer(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					sent = true
+				}))
+			defer server.Close()
+
+			client := rzpsdk.NewClient("test-key", "test-secret")
+			client.Request.BaseURL = server.URL
+			dryRun := &mcpgo.DryRun{}
+			ctx := mcpgo.ContextWithDryRun(context.Background(), dryRun)
+
+			tool := CreateOrder(CreateTestObservability(), client)
+			result, err := tool.GetHandler()(ctx, createMCPRequest(
+				map[string]interface{}{
+					"amount":   float64(10000),
+					"currency": "INR",
+					"receipt":  "receipt-1",
+				}))
+			require.NoError(t, err)
+			assert.True(t, result.IsError)
+
+			assert.False(t, sent)
+			assert.Equal(t, []mcpgo.DryRunRequest{{
+				Method: http.MethodPost,
+				URL:    server.URL + "/v1/orders",
+				Body: map[string]interface{}{
+					"amount":   float64(10000),
+					"currency": "INR",
+					"receipt":  "receipt-1",
+				},
+			}}, dryRun.Requests())
+		})
+
+	t.Run("records nothing for invalid calls", func(t *testing.T) {
+		client := rzpsdk.NewClient("test-key", "test-secret")
+		dryRun := &mcpgo.DryRun{}
+		ctx := mcpgo.ContextWithDryRun(context.Background(), dryRun)
+
+		tool := CreateOrder(CreateTestObservability(), client)
+		result, err := tool.GetHandler()(ctx, createMCPRequest(
+			map[string]interface{}{"currency": "INR"}))
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Empty(t, dryRun.Requests())
+	})
+
+	t.Run("returns the client outside dry runs", func(t *testing.T) {
+		client := rzpsdk.NewClient("test-key", "test-secret")
+
+		assert.Same(t, client, withDryRunOf(context.Background(), client))
+	})
+}
@@ -0,0 +1,301 @@
+package razorpay
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"unicode/utf8"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+)
+
+// Limits of the notes of entities
+const (
+	// maxNotes is the most key-value pairs the notes of an entity hold
+	maxNotes = 15
+	// maxNoteLength is the longest a note key or value may be
+	maxNoteLength = 256
+	// maxNoteAttempts is how many times a note change is tried when the
+	// notes of the entity change while it is made
+	maxNoteAttempts = 3
+)
+
+// noteEntity is a type of entity whose notes the note tools change
+type noteEntity struct {
+	// name of the entity in errors
+	name     string
+	idPrefix string
+	fetch    func(id string, query map[string]interface{},
+		headers map[string]string) (map[string]interface{}, error)
+	update func(id string, data map[string]interface{},
+		headers map[string]string) (map[string]interface{}, error)
+}
+
+// noteEntities returns the entity types whose notes the note tools change,
+// by their entity_type
+func noteEntities(client *rzpsdk.Client) map[string]noteEntity {
+	return map[string]noteEntity{
+		"payment": {
+			name:     "payment",
+			idPrefix: paymentIDPrefix,
+			fetch:    client.Payment.Fetch,
+			update:   client.Payment.Edit,
+		},
+		"order": {
+			name:     "order",
+			idPrefix: orderIDPrefix,
+			fetch:    client.Order.Fetch,
+			update:   client.Order.Update,
+		},
+		"refund": {
+			name:     "refund",
+			idPrefix: refundIDPrefix,
+			fetch:    client.Refund.Fetch,
+			update:   client.Refund.Update,
+		},
+		"payment_link": {
+			name:     "payment link",
+			idPrefix: paymentLinkIDPrefix,
+			fetch:    client.PaymentLink.Fetch,
+			update:   client.PaymentLink.Update,
+		},
+	}
+}
+
+// noteChange changes the notes, in place, and returns whether it changed
+// them, or the problem that keeps it from changing them
+type noteChange func(notes map[string]interface{}) (bool, error)
+
+// AddEntityNote returns a tool that sets a note of a payment, order, refund
+// or payment link, keeping its other notes
+func AddEntityNote(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := append(entityNoteParameters(),
+		mcpgo.WithString(
+			"value",
+			mcpgo.Description(fmt.Sprintf("Value of the note, at most %d "+
+				"characters", maxNoteLength)),
+			mcpgo.Required(),
+			mcpgo.Max(maxNoteLength),
+		),
+	)
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		params := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(params, "entity_type").
+			ValidateAndAddRequiredString(params, "entity_id").
+			ValidateAndAddRequiredString(params, "key").
+			ValidateAndAddRequiredString(params, "value")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		key, value := params["key"].(string), params["value"].(string)
+		if utf8.RuneCountInString(value) > maxNoteLength {
+			return mcpgo.NewToolResultValidationError(fmt.Sprintf(
+				"value must be at most %d characters", maxNoteLength),
+				"value"), nil
+		}
+
+		return changeEntityNotes(client, params,
+			func(notes map[string]interface{}) (bool, error) {
+				current, exists := notes[key]
+				if exists && fmt.Sprint(current) == value {
+					return false, nil
+				}
+				if !exists && len(notes) >= maxNotes {
+					return false, fmt.Errorf("notes already hold %d "+
+						"key-value pairs, the most allowed, remove one "+
+						"first", maxNotes)
+				}
+				notes[key] = value
+				return true, nil
+			})
+	}
+
+	return mcpgo.NewTool(
+		"add_entity_note",
+		fmt.Sprintf("Set a note of a payment, order, refund or payment "+
+			"link, keeping its other notes, such as to tag it. An existing "+
+			"note with the key is replaced. The current notes are read and "+
+			"written back with the change, and the change is retried when "+
+			"they change in between. Entities hold at most %d notes. "+
+			"Returns the updated entity", maxNotes),
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema)
+}
+
+// RemoveEntityNote returns a tool that removes a note of a payment, order,
+// refund or payment link, keeping its other notes
+func RemoveEntityNote(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := entityNoteParameters()
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		params := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(params, "entity_type").
+			ValidateAndAddRequiredString(params, "entity_id").
+			ValidateAndAddRequiredString(params, "key")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		key := params["key"].(string)
+
+		return changeEntityNotes(client, params,
+			func(notes map[string]interface{}) (bool, error) {
+				if _, exists := notes[key]; !exists {
+					return false, nil
+				}
+				delete(notes, key)
+				return true, nil
+			})
+	}
+
+	return mcpgo.NewTool(
+		"remove_entity_note",
+		"Remove a note of a payment, order, refund or payment link, keeping "+
+			"its other notes. Removing a note the entity does not have "+
+			"changes nothing. The current notes are read and written back "+
+			"without the note, and the change is retried when they change "+
+			"in between. Returns the updated entity",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema)
+}
+
+// entityNoteParameters returns the parameters that name a note of an
+// entity
+func entityNoteParameters() []mcpgo.ToolParameter {
+	return []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"entity_type",
+			mcpgo.Description("Type of the entity"),
+			mcpgo.Required(),
+			mcpgo.Enum("payment", "order", "refund", "payment_link"),
+		),
+		mcpgo.WithString(
+			"entity_id",
+			mcpgo.Description("ID of the entity, such as pay_, order_, rfnd_ "+
+				"or plink_ followed by its unique identifier"),
+			mcpgo.Required(),
+		),
+		mcpgo.WithString(
+			"key",
+			mcpgo.Description(fmt.Sprintf("Key of the note, at most %d "+
+				"characters", maxNoteLength)),
+			mcpgo.Required(),
+			mcpgo.Min(1),
+			mcpgo.Max(maxNoteLength),
+		),
+	}
+}
+
+// changeEntityNotes applies the change to the notes of the entity of the
+// params, and returns the updated entity. The API has no conditional
+// update, so the notes are read again right before they are written, and
+// the change starts over from the newer notes when they changed since
+// they were first read.
+func changeEntityNotes(
+	client *rzpsdk.Client,
+	params map[string]interface{},
+	change noteChange,
+) (*mcpgo.ToolResult, error) {
+	entityType := params["entity_type"].(string)
+	entity, ok := noteEntities(client)[entityType]
+	if !ok {
+		return mcpgo.NewToolResultValidationError(fmt.Sprintf(
+			"invalid entity_type: %s, must be payment, order, refund or "+
+				"payment_link", entityType), "entity_type"), nil
+	}
+	id := params["entity_id"].(string)
+	if result := validateID("entity_id", id, entity.idPrefix); result != nil {
+		return result, nil
+	}
+	key := params["key"].(string)
+	if key == "" || utf8.RuneCountInString(key) > maxNoteLength {
+		return mcpgo.NewToolResultValidationError(fmt.Sprintf(
+			"key must be 1 to %d characters", maxNoteLength), "key"), nil
+	}
+
+	current, err := entity.fetch(id, nil, nil)
+	if err != nil {
+		return mcpgo.NewToolResultError(fmt.Sprintf(
+			"fetching %s failed: %s", entity.name, err.Error())), nil
+	}
+
+	for attempt := 1; ; attempt++ {
+		notes := entityNotes(current)
+		changed, err := change(notes)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+		if !changed {
+			return mcpgo.NewToolResultJSON(current)
+		}
+
+		latest, err := entity.fetch(id, nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(fmt.Sprintf(
+				"fetching %s failed: %s", entity.name, err.Error())), nil
+		}
+		if !reflect.DeepEqual(entityNotes(latest), entityNotes(current)) {
+			if attempt == maxNoteAttempts {
+				return mcpgo.NewToolResultError(fmt.Sprintf(
+					"the notes of %s kept changing while updating them, "+
+						"try again", id)), nil
+			}
+			current = latest
+			continue
+		}
+
+		updated, err := entity.update(id,
+			map[string]interface{}{"notes": notes}, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(fmt.Sprintf(
+				"updating %s failed: %s", entity.name, err.Error())), nil
+		}
+		return mcpgo.NewToolResultJSON(updated)
+	}
+}
+
+// entityNotes returns a copy of the notes of the entity. Entities without
+// notes return them as an empty array, which is an empty map here.
+func entityNotes(entity map[string]interface{}) map[string]interface{} {
+	notes := make(map[string]interface{})
+	current, _ := entity["notes"].(map[string]interface{})
+	for key, value := range current {
+		notes[key] = value
+	}
+	return notes
+}
@@ -0,0 +1,465 @@
+package razorpay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/razorpay/razorpay-go/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay/mock"
+)
+
+func Test_AddEntityNote(t *testing.T) {
+	paymentPath := fmt.Sprintf("/%s%s/%s", constants.VERSION_V1,
+		constants.PAYMENT_URL, "pay_MT48CvBhIC98MQ")
+	linkPath := fmt.Sprintf("/%s%s/%s", constants.VERSION_V1,
+		constants.PaymentLink_URL, "plink_ExjpAUN3gVHrPJ")
+
+	payment := map[string]interface{}{
+		"id":     "pay_MT48CvBhIC98MQ",
+		"entity": "payment",
+		"notes":  map[string]interface{}{"source": "web"},
+	}
+	updatedPayment := map[string]interface{}{
+		"id":     "pay_MT48CvBhIC98MQ",
+		"entity": "payment",
+		"notes": map[string]interface{}{
+			"source": "web",
+			"tag":    "vip",
+		},
+	}
+	link := map[string]interface{}{
+		"id":    "plink_ExjpAUN3gVHrPJ",
+		"notes": []interface{}{},
+	}
+	updatedLink := map[string]interface{}{
+		"id":    "plink_ExjpAUN3gVHrPJ",
+		"notes": map[string]interface{}{"tag": "vip"},
+	}
+	fullNotes := make(map[string]interface{})
+	for i := 0; i < maxNotes; i++ {
+		fullNotes[fmt.Sprintf("key%d", i)] = "value"
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "adds a note to a payment",
+			Request: map[string]interface{}{
+				"entity_type": "payment",
+				"entity_id":   "pay_MT48CvBhIC98MQ",
+				"key":         "tag",
+				"value":       "vip",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     paymentPath,
+						Method:   "GET",
+						Response: payment,
+					},
+					mock.Endpoint{
+						Path:     paymentPath,
+						Method:   "PATCH",
+						Response: updatedPayment,
+					},
+				)
+			},
+			ExpectedResult: updatedPayment,
+		},
+		{
+			Name: "adds a note to a payment link without notes",
+			Request: map[string]interface{}{
+				"entity_type": "payment_link",
+				"entity_id":   "plink_ExjpAUN3gVHrPJ",
+				"key":         "tag",
+				"value":       "vip",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     linkPath,
+						Method:   "GET",
+						Response: link,
+					},
+					mock.Endpoint{
+						Path:     linkPath,
+						Method:   "PATCH",
+						Response: updatedLink,
+					},
+				)
+			},
+			ExpectedResult: updatedLink,
+		},
+		{
+			Name: "note with the same value is not written",
+			Request: map[string]interface{}{
+				"entity_type": "payment",
+				"entity_id":   "pay_MT48CvBhIC98MQ",
+				"key":         "source",
+				"value":       "web",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     paymentPath,
+						Method:   "GET",
+						Response: payment,
+					},
+				)
+			},
+			ExpectedResult: payment,
+		},
+		{
+			Name: "notes that are full",
+			Request: map[string]interface{}{
+				"entity_type": "payment",
+				"entity_id":   "pay_MT48CvBhIC98MQ",
+				"key":         "tag",
+				"value":       "vip",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:   paymentPath,
+						Method: "GET",
+						Response: map[string]interface{}{
+							"id":    "pay_MT48CvBhIC98MQ",
+							"notes": fullNotes,
+						},
+					},
+				)
+			},
+			ExpectError:    true,
+			ExpectedErrMsg: "notes already hold 15 key-value pairs",
+		},
+		{
+			Name: "value that is too long",
+			Request: map[string]interface{}{
+				"entity_type": "payment",
+				"entity_id":   "pay_MT48CvBhIC98MQ",
+				"key":         "tag",
+				"value":       strings.Repeat("a", maxNoteLength+1),
+			},
+			ExpectError:    true,
+			ExpectedErrMsg: "value must be at most 256 characters",
+		},
+		{
+			Name: "entity ID of another entity type",
+			Request: map[string]interface{}{
+				"entity_type": "order",
+				"entity_id":   "pay_MT48CvBhIC98MQ",
+				"key":         "tag",
+				"value":       "vip",
+			},
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid entity_id: pay_MT48CvBhIC98MQ",
+		},
+		{
+			Name: "unknown entity type",
+			Request: map[string]interface{}{
+				"entity_type": "invoice",
+				"entity_id":   "inv_DAweOiQ7amIUVd",
+				"key":         "tag",
+				"value":       "vip",
+			},
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid entity_type: invoice",
+		},
+		{
+			Name: "missing value",
+			Request: map[string]interface{}{
+				"entity_type": "payment",
+				"entity_id":   "pay_MT48CvBhIC98MQ",
+				"key":         "tag",
+			},
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: value",
+		},
+		{
+			Name: "fetch fails",
+			Request: map[string]interface{}{
+				"entity_type": "payment",
+				"entity_id":   "pay_MT48CvBhIC98MQ",
+				"key":         "tag",
+				"value":       "vip",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:   paymentPath,
+						Method: "GET",
+						Response: map[string]interface{}{
+							"error": map[string]interface{}{
+								"code":        "BAD_REQUEST_ERROR",
+								"description": "The id provided does not exist",
+							},
+						},
+					},
+				)
+			},
+			ExpectError:    true,
+			ExpectedErrMsg: "fetching payment failed",
+		},
+		{
+			Name: "update fails",
+			Request: map[string]interface{}{
+				"entity_type": "payment",
+				"entity_id":   "pay_MT48CvBhIC98MQ",
+				"key":         "tag",
+				"value":       "vip",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     paymentPath,
+						Method:   "GET",
+						Response: payment,
+					},
+					mock.Endpoint{
+						Path:   paymentPath,
+						Method: "PATCH",
+						Response: map[string]interface{}{
+							"error": map[string]interface{}{
+								"code":        "BAD_REQUEST_ERROR",
+								"description": "notes is invalid",
+							},
+						},
+					},
+				)
+			},
+			ExpectError:    true,
+			ExpectedErrMsg: "updating payment failed: notes is invalid",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, AddEntityNote, "Entity")
+		})
+	}
+
+	t.Run("writes back the other notes", func(t *testing.T) {
+		var body map[string]interface{}
+
+		mockClient := func() (*http.Client, *httptest.Server) {
+			server := httptest.NewServer(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					if r.Method == http.MethodPatch {
+						_ = json.NewDecoder(r.Body).Decode(&body)
+						_ = json.NewEncoder(w).Encode(updatedPayment)
+						return
+					}
+					_ = json.NewEncoder(w).Encode(payment)
+				}))
+			return server.Client(), server
+		}
+
+		client, server := newMockRzpClient(mockClient)
+		defer server.Close()
+
+		tool := AddEntityNote(CreateTestObservability(), client)
+		result, err := tool.GetHandler()(context.Background(),
+			createMCPRequest(map[string]interface{}{
+				"entity_type": "payment",
+				"entity_id":   "pay_MT48CvBhIC98MQ",
+				"key":         "tag",
+				"value":       "vip",
+			}))
+		require.NoError(t, err)
+		require.False(t, result.IsError, result.Text)
+
+		assert.Equal(t, map[string]interface{}{
+			"notes": map[string]interface{}{
+				"source": "web",
+				"tag":    "vip",
+			},
+		}, body)
+	})
+
+	t.Run("starts over from notes changed in between", func(t *testing.T) {
+		var body map[string]interface{}
+		fetches := 0
+
+		mockClient := func() (*http.Client, *httptest.Server) {
+			server := httptest.NewServer(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					if r.Method == http.MethodPatch {
+						_ = json.NewDecoder(r.Body).Decode(&body)
+						_ = json.NewEncoder(w).Encode(updatedPayment)
+						return
+					}
+					fetches++
+					if fetches == 1 {
+						_ = json.NewEncoder(w).Encode(payment)
+						return
+					}
+					_ = json.NewEncoder(w).Encode(map[string]interface{}{
+						"id": "pay_MT48CvBhIC98MQ",
+						"notes": map[string]interface{}{
+							"source":   "web",
+							"reviewer": "ops",
+						},
+					})
+				}))
+			return server.Client(), server
+		}
+
+		client, server := newMockRzpClient(mockClient)
+		defer server.Close()
+
+		tool := AddEntityNote(CreateTestObservability(), client)
+		result, err := tool.GetHandler()(context.Background(),
+			createMCPRequest(map[string]interface{}{
+				"entity_type": "payment",
+				"entity_id":   "pay_MT48CvBhIC98MQ",
+				"key":         "tag",
+				"value":       "vip",
+			}))
+		require.NoError(t, err)
+		require.False(t, result.IsError, result.Text)
+
+		assert.Equal(t, 3, fetches)
+		assert.Equal(t, map[string]interface{}{
+			"notes": map[string]interface{}{
+				"source":   "web",
+				"reviewer": "ops",
+				"tag":      "vip",
+			},
+		}, body)
+	})
+
+	t.Run("gives up on notes that keep changing", func(t *testing.T) {
+		fetches := 0
+
+		mockClient := func() (*http.Client, *httptest.Server) {
+			server := httptest.NewServer(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					require.Equal(t, http.MethodGet, r.Method)
+					fetches++
+					_ = json.NewEncoder(w).Encode(map[string]interface{}{
+						"id": "pay_MT48CvBhIC98MQ",
+						"notes": map[string]interface{}{
+							"version": fmt.Sprint(fetches),
+						},
+					})
+				}))
+			return server.Client(), server
+		}
+
+		client, server := newMockRzpClient(mockClient)
+		defer server.Close()
+
+		tool := AddEntityNote(CreateTestObservability(), client)
+		result, err := tool.GetHandler()(context.Background(),
+			createMCPRequest(map[string]interface{}{
+				"entity_type": "payment",
+				"entity_id":   "pay_MT48CvBhIC98MQ",
+				"key":         "tag",
+				"value":       "vip",
+			}))
+		require.NoError(t, err)
+		require.True(t, result.IsError)
+
+		assert.Contains(t, result.Text, "kept changing")
+		assert.Equal(t, maxNoteAttempts+1, fetches)
+	})
+}
+
+func Test_RemoveEntityNote(t *testing.T) {
+	orderPath := fmt.Sprintf("/%s%s/%s", constants.VERSION_V1,
+		constants.ORDER_URL, "order_EKwxwAgItmmXdp")
+	refundPath := fmt.Sprintf("/%s%s/%s", constants.VERSION_V1,
+		constants.REFUND_URL, "rfnd_FP8QHiV938haTz")
+
+	order := map[string]interface{}{
+		"id": "order_EKwxwAgItmmXdp",
+		"notes": map[string]interface{}{
+			"source": "web",
+			"tag":    "vip",
+		},
+	}
+	updatedOrder := map[string]interface{}{
+		"id":    "order_EKwxwAgItmmXdp",
+		"notes": map[string]interface{}{"source": "web"},
+	}
+	refund := map[string]interface{}{
+		"id":    "rfnd_FP8QHiV938haTz",
+		"notes": []interface{}{},
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "removes a note of an order",
+			Request: map[string]interface{}{
+				"entity_type": "order",
+				"entity_id":   "order_EKwxwAgItmmXdp",
+				"key":         "tag",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     orderPath,
+						Method:   "GET",
+						Response: order,
+					},
+					mock.Endpoint{
+						Path:     orderPath,
+						Method:   "PATCH",
+						Response: updatedOrder,
+					},
+				)
+			},
+			ExpectedResult: updatedOrder,
+		},
+		{
+			Name: "note the refund does not have is not written",
+			Request: map[string]interface{}{
+				"entity_type": "refund",
+				"entity_id":   "rfnd_FP8QHiV938haTz",
+				"key":         "tag",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     refundPath,
+						Method:   "GET",
+						Response: refund,
+					},
+				)
+			},
+			ExpectedResult: refund,
+		},
+		{
+			Name: "missing key",
+			Request: map[string]interface{}{
+				"entity_type": "order",
+				"entity_id":   "order_EKwxwAgItmmXdp",
+			},
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: key",
+		},
+		{
+			Name: "empty key",
+			Request: map[string]interface{}{
+				"entity_type": "order",
+				"entity_id":   "order_EKwxwAgItmmXdp",
+				"key":         "",
+			},
+			ExpectError:    true,
+			ExpectedErrMsg: "key must be 1 to 256 characters",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, RemoveEntityNote, "Entity")
+		})
+	}
+}
@@ -0,0 +1,493 @@
+package razorpay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
+	"github.com/razorpay/razorpay-mcp-server/pkg/events"
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+)
+
+// eventsLogger is the logger of the logging notifications events are
+// delivered in
+const eventsLogger = "razorpay.events"
+
+// eventPollInterval is how often the entities sessions subscribed to are
+// fetched to find their changes. Tests shorten it.
+var eventPollInterval = 15 * time.Second
+
+// eventEntity is an entity sessions can subscribe to the changes of
+type eventEntity struct {
+	idPrefix string
+	fetch    func(client *rzpsdk.Client, id string) (
+		map[string]interface{}, error)
+	// final are the statuses after which the entity does not change
+	final []string
+}
+
+// eventEntities are the entities sessions can subscribe to, by name
+var eventEntities = map[string]eventEntity{
+	"payment": {
+		idPrefix: paymentIDPrefix,
+		fetch: func(client *rzpsdk.Client, id string) (
+			map[string]interface{}, error) {
+			return client.Payment.Fetch(id, nil, nil)
+		},
+		final: defaultPaymentTerminalStatuses,
+	},
+	"order": {
+		idPrefix: orderIDPrefix,
+		fetch: func(client *rzpsdk.Client, id string) (
+			map[string]interface{}, error) {
+			return client.Order.Fetch(id, nil, nil)
+		},
+		final: []string{"paid"},
+	},
+}
+
+// eventNotifier delivers events to the sessions of the server it is bound
+// to, as logging notifications
+type eventNotifier struct {
+	server *mcpgo.Mark3labsImpl
+}
+
+func (n *eventNotifier) notify(sessionID string, event events.Event) error {
+	err := n.server.SendLogMessage(sessionID, eventsLogger, event)
+	if errors.Is(err, mcpgo.ErrSessionNotFound) {
+		return events.ErrSessionNotFound
+	}
+	return err
+}
+
+// eventBusFromContext returns the event bus of the server of a tool call
+func eventBusFromContext(ctx context.Context) (*events.Bus, error) {
+	bus, ok := contextkey.EventBusFromContext(ctx).(*events.Bus)
+	if !ok {
+		return nil, errors.New("event notifications are not available")
+	}
+	return bus, nil
+}
+
+// eventParameters returns the entity and id parameters of the event tools
+func eventParameters(action string) []mcpgo.ToolParameter {
+	return []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"entity",
+			mcpgo.Description(fmt.Sprintf("Type of the entity to %s the "+
+				"changes of", action)),
+			mcpgo.Required(),
+			mcpgo.Enum("payment", "order"),
+		),
+		mcpgo.WithString(
+			"id",
+			mcpgo.Description("ID of the entity, such as pay_29QQoUBi66xm2f "+
+				"or order_9A33XWu170gUtm"),
+			mcpgo.Required(),
+		),
+	}
+}
+
+// validateEventParameters validates the entity and id parameters of the
+// event tools, and returns the entity
+func validateEventParameters(
+	r mcpgo.CallToolRequest,
+	params map[string]interface{},
+) (eventEntity, *mcpgo.ToolResult, error) {
+	validator := NewValidator(&r).
+		ValidateAndAddRequiredString(params, "entity").
+		ValidateAndAddRequiredString(params, "id")
+
+	if result, err := validator.HandleErrorsIfAny(); result != nil {
+		return eventEntity{}, result, err
+	}
+
+	entity, ok := eventEntities[params["entity"].(string)]
+	if !ok {
+		return eventEntity{}, mcpgo.NewToolResultError(fmt.Sprintf(
+			"invalid entity: %s, must be payment or order",
+			params["entity"])), nil
+	}
+	if result := validateID("id", params["id"].(string),
+		entity.idPrefix); result != nil {
+		return eventEntity{}, result, nil
+	}
+	return entity, nil, nil
+}
+
+// SubscribeEvents returns a tool that subscribes the session to the status
+// changes of a payment or order
+func SubscribeEvents(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		params := make(map[string]interface{})
+		entity, result, err := validateEventParameters(r, params)
+		if result != nil {
+			return result, err
+		}
+
+		bus, err := eventBusFromContext(ctx)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+		sessionID := mcpgo.SessionID(ctx)
+		if sessionID == "" {
+			return mcpgo.NewToolResultError("subscribing to events " +
+				"needs a session to send notifications to"), nil
+		}
+
+		// The subscription outlives the call, so its polls are not
+		// cancelled with it
+		pollClient, err := getClientFromContextOrDefault(
+			context.WithoutCancel(ctx), client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		name, id := params["entity"].(string), params["id"].(string)
+		current, err := entity.fetch(pollClient, id)
+		if err != nil {
+			return mcpgo.NewToolResultError(fmt.Sprintf(
+				"fetching %s failed: %s", name, err.Error())), nil
+		}
+		status, _ := current["status"].(string)
+
+		response := map[string]interface{}{
+			"entity": name,
+			"id":     id,
+			"status": status,
+		}
+		if slices.Contains(entity.final, status) {
+			response["subscribed"] = false
+			response["message"] = fmt.Sprintf("The %s is %s and will not "+
+				"change, so no events will follow.", name, status)
+			return mcpgo.NewToolResultJSON(response)
+		}
+
+		bus.Subscribe(sessionID, events.Watch{
+			Entity: name,
+			ID:     id,
+			Status: status,
+			Fetch: func(context.Context) (map[string]interface{}, error) {
+				return entity.fetch(pollClient, id)
+			},
+			Final: func(status string) bool {
+				return slices.Contains(entity.final, status)
+			},
+		})
+
+		response["subscribed"] = true
+		response["message"] = fmt.Sprintf("Status changes of the %s are "+
+			"sent as logging notifications of the %s logger until it is %s "+
+			"or unsubscribe_events is called.", name, eventsLogger,
+			strings.Join(entity.final, " or "))
+		return mcpgo.NewToolResultJSON(response)
+	}
+
+	return mcpgo.NewTool(
+		"subscribe_events",
+		"Subscribe to the status changes of a payment or order, such as a "+
+			"payment being captured after a UPI collect request. Changes "+
+			"are pushed to this session as MCP logging notifications until "+
+			"the entity reaches a final status, instead of polling with "+
+			"wait_for_payment_status.",
+		eventParameters("subscribe to"),
+		handler,
+	).WithOutputSchema(objectOutputSchema).WithoutCache()
+}
+
+// UnsubscribeEvents returns a tool that ends the subscription of the
+// session to the changes of a payment or order
+func UnsubscribeEvents(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		params := make(map[string]interface{})
+		if _, result, err := validateEventParameters(r, params); result != nil {
+			return result, err
+		}
+
+		bus, err := eventBusFromContext(ctx)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		name, id := params["entity"].(string), params["id"].(string)
+		unsubscribed := bus.Unsubscribe(mcpgo.SessionID(ctx), name, id)
+
+		return mcpgo.NewToolResultJSON(map[string]interface{}{
+			"entity":       name,
+			"id":           id,
+			"unsubscribed": unsubscribed,
+		})
+	}
+
+	return mcpgo.NewTool(
+		"unsubscribe_events",
+		"Stop the status change notifications of a payment or order that "+
+			"this session subscribed to with subscribe_events",
+		eventParameters("stop receiving"),
+		handler,
+	).WithOutputSchema(objectOutputSchema).WithoutCache()
+}
+
+// Bounds of the count parameter of fetch_recent_events
+const (
+	defaultRecentEventsCount = 20
+	maxRecentEventsCount     = 100
+)
+
+// eventHistoryFromContext returns the webhook event history of the server
+// of a tool call. The history holds the events of every account the
+// webhook is set up for, so calls made with other credentials than those
+// of the server, such as those of their request, an OAuth subject or an
+// account, cannot read it.
+func eventHistoryFromContext(ctx context.Context) (*events.History, error) {
+	history, ok := contextkey.EventHistoryFromContext(ctx).(*events.History)
+	if !ok {
+		return nil, errors.New("webhook events are not available, the " +
+			"server receives them when it runs with http transport and " +
+			"--webhook-secret")
+	}
+	if contextkey.ClientFromContext(ctx) != nil {
+		return nil, errors.New("webhook events are only available to " +
+			"calls made with the credentials of the server")
+	}
+	return history, nil
+}
+
+// eventFilterParameters returns the parameters that select webhook events
+func eventFilterParameters() []mcpgo.ToolParameter {
+	return []mcpgo.ToolParameter{
+		mcpgo.WithArray(
+			"events",
+			mcpgo.Description("Optional: Types of the events to select, "+
+				"such as payment.captured or order.paid. A type ending in .* "+
+				"selects every event of the entity, such as payment.*"),
+			mcpgo.Items(map[string]interface{}{
+				"type": "string",
+			}),
+		),
+		mcpgo.WithString(
+			"entity_id",
+			mcpgo.Description("Optional: Select only the events about the "+
+				"entity, such as pay_29QQoUBi66xm2f or order_9A33XWu170gUtm"),
+		),
+		mcpgo.WithNumber(
+			"after_seq",
+			mcpgo.Description("Optional: Select only the events received "+
+				"after the event with the seq, such as the last_seq of a "+
+				"previous call"),
+			mcpgo.Min(0),
+		),
+	}
+}
+
+// validateEventFilter validates and adds the parameters that select
+// webhook events, and returns the filter they describe
+func validateEventFilter(
+	validator *Validator,
+	params map[string]interface{},
+) events.Filter {
+	validator.ValidateAndAddOptionalArray(params, "events").
+		ValidateAndAddOptionalString(params, "entity_id").
+		ValidateAndAddOptionalInt(params, "after_seq")
+
+	filter := events.Filter{}
+	if types, ok := params["events"].([]interface{}); ok {
+		for _, t := range types {
+			value, ok := t.(string)
+			if !ok || value == "" {
+				validator.addError(errors.New(
+					"events must be a list of event types"))
+				break
+			}
+			filter.Events = append(filter.Events, value)
+		}
+	}
+	if entityID, ok := params["entity_id"].(string); ok {
+		filter.EntityID = entityID
+	}
+	if afterSeq, ok := params["after_seq"].(int64); ok {
+		if afterSeq < 0 {
+			validator.addError(errors.New("after_seq must not be negative"))
+		}
+		filter.AfterSeq = afterSeq
+	}
+	return filter
+}
+
+// FetchRecentEvents returns a tool that fetches the latest webhook events
+// the server received
+func FetchRecentEvents(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := append(eventFilterParameters(),
+		mcpgo.WithNumber(
+			"count",
+			mcpgo.Description(fmt.Sprintf("Optional: Number of events to "+
+				"return, newest first (default: %d, max: %d)",
+				defaultRecentEventsCount, maxRecentEventsCount)),
+			mcpgo.Min(1),
+			mcpgo.Max(maxRecentEventsCount),
+		),
+	)
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		params := make(map[string]interface{})
+		validator := NewValidator(&r)
+		filter := validateEventFilter(validator, params)
+		validator.ValidateAndAddOptionalInt(params, "count")
+
+		count := int64(defaultRecentEventsCount)
+		if value, ok := params["count"].(int64); ok {
+			if value < 1 || value > maxRecentEventsCount {
+				validator.addError(fmt.Errorf(
+					"count must be between 1 and %d", maxRecentEventsCount))
+			}
+			count = value
+		}
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		history, err := eventHistoryFromContext(ctx)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+		// Partners only read the events of the sub-merchant they act for
+		filter.AccountID = contextkey.MerchantAccountFromContext(ctx)
+
+		// Read the last seq first, so that no event is missed by a call
+		// with it as after_seq
+		lastSeq := history.LastSeq()
+		items := history.Recent(filter, int(count))
+		return mcpgo.NewToolResultJSON(map[string]interface{}{
+			"count":    len(items),
+			"last_seq": lastSeq,
+			"items":    items,
+		})
+	}
+
+	return mcpgo.NewTool(
+		"fetch_recent_events",
+		"Fetch the latest webhook events Razorpay sent to this server, such "+
+			"as payment.captured, newest first. Pass the returned last_seq "+
+			"as after_seq of wait_for_event to wait for the next event.",
+		parameters,
+		handler,
+	).WithOutputSchema(objectOutputSchema).
+		WithFieldSelection().
+		// Each call must read the events received since the last one
+		WithoutCache()
+}
+
+// WaitForEvent returns a tool that waits for a webhook event to be
+// received
+func WaitForEvent(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := append(eventFilterParameters(),
+		mcpgo.WithNumber(
+			"timeout",
+			mcpgo.Description(fmt.Sprintf("Seconds to wait before giving up "+
+				"(default: %d, max: %d)", defaultPollTimeout, maxPollTimeout)),
+			mcpgo.Min(1),
+			mcpgo.Max(maxPollTimeout),
+		),
+	)
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		params := make(map[string]interface{})
+		validator := NewValidator(&r)
+		filter := validateEventFilter(validator, params)
+		validator.ValidateAndAddOptionalInt(params, "timeout")
+
+		timeout := int64(defaultPollTimeout)
+		if value, ok := params["timeout"].(int64); ok {
+			if value < 1 || value > maxPollTimeout {
+				validator.addError(fmt.Errorf(
+					"timeout must be between 1 and %d", maxPollTimeout))
+			}
+			timeout = value
+		}
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		history, err := eventHistoryFromContext(ctx)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+		// Partners only read the events of the sub-merchant they act for
+		filter.AccountID = contextkey.MerchantAccountFromContext(ctx)
+
+		// Without after_seq, only the events received from now on are
+		// waited for
+		if _, ok := params["after_seq"]; !ok {
+			filter.AfterSeq = history.LastSeq()
+		}
+
+		waitCtx, cancel := context.WithTimeout(ctx,
+			time.Duration(timeout)*pollUnit)
+		defer cancel()
+
+		event, err := history.Wait(waitCtx, filter)
+		if err != nil && ctx.Err() != nil {
+			return mcpgo.NewToolResultError(fmt.Sprintf(
+				"waiting for event failed: %s", ctx.Err().Error())), nil
+		}
+		if err != nil {
+			return mcpgo.NewToolResultJSON(map[string]interface{}{
+				"received": false,
+				"last_seq": history.LastSeq(),
+			})
+		}
+		return mcpgo.NewToolResultJSON(map[string]interface{}{
+			"received": true,
+			"last_seq": event.Seq,
+			"event":    event,
+		})
+	}
+
+	return mcpgo.NewTool(
+		"wait_for_event",
+		"Wait for Razorpay to send a webhook event to this server, such as "+
+			"payment.captured for a payment link or UPI collect request, "+
+			"and return it. Use it to find out when the customer pays. "+
+			"received is false when the timeout passed first, in which case "+
+			"the event can be waited for again with the returned last_seq "+
+			"as after_seq",
+		parameters,
+		handler,
+	).WithOutputSchema(objectOutputSchema).
+		WithTimeout(pollToolTimeout).
+		// Each call must wait for new events
+		WithoutCache()
+}
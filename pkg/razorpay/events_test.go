@@ -0,0 +1,431 @@
+package razorpay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+	"github.com/razorpay/razorpay-go/constants"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
+	"github.com/razorpay/razorpay-mcp-server/pkg/events"
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+)
+
+// eventSession is a client session whose notifications can be read
+type eventSession struct {
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *eventSession) SessionID() string { return "session-1" }
+func (s *eventSession) Initialize()       {}
+func (s *eventSession) Initialized() bool { return true }
+func (s *eventSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+
+func Test_SubscribeEvents(t *testing.T) {
+	paymentPath := fmt.Sprintf("/%s%s/pay_1", constants.VERSION_V1,
+		constants.PAYMENT_URL)
+
+	// The payment is authorized on the first fetch and captured after
+	var mu sync.Mutex
+	fetches := 0
+	apiServer := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			status := "authorized"
+			if fetches > 0 {
+				status = "captured"
+			}
+			fetches++
+			assert.Equal(t, paymentPath, r.URL.Path)
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"id": "pay_1", "status": status,
+			})
+		}))
+	defer apiServer.Close()
+
+	defaultInterval := eventPollInterval
+	eventPollInterval = 10 * time.Millisecond
+	defer func() { eventPollInterval = defaultInterval }()
+
+	client := rzpsdk.NewClient("test-key", "test-secret")
+	client.Request.BaseURL = apiServer.URL
+	server, err := NewRzpMcpServer(CreateTestObservability(), client,
+		[]string{"payments"}, nil, nil, false)
+	require.NoError(t, err)
+	impl, ok := server.(*mcpgo.Mark3labsImpl)
+	require.True(t, ok)
+
+	session := &eventSession{
+		notifications: make(chan mcp.JSONRPCNotification, 10),
+	}
+	ctx := context.Background()
+	require.NoError(t, impl.McpServer.RegisterSession(ctx, session))
+	ctx = impl.McpServer.WithContext(ctx, session)
+
+	// callTool calls a tool of the server in the session
+	callTool := func(
+		ctx context.Context,
+		name string,
+		args map[string]interface{},
+	) *mcp.CallToolResult {
+		tool := impl.McpServer.GetTool(name)
+		require.NotNil(t, tool)
+		request := mcp.CallToolRequest{}
+		request.Params.Name = name
+		request.Params.Arguments = args
+		result, err := tool.Handler(ctx, request)
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("notifies the session of changes", func(t *testing.T) {
+		result := callTool(ctx, "subscribe_events", map[string]interface{}{
+			"entity": "payment", "id": "pay_1",
+		})
+		require.False(t, result.IsError)
+		assert.Equal(t, map[string]interface{}{
+			"entity":     "payment",
+			"id":         "pay_1",
+			"status":     "authorized",
+			"subscribed": true,
+			"message": "Status changes of the payment are sent as logging " +
+				"notifications of the razorpay.events logger until it is " +
+				"captured or failed or unsubscribe_events is called.",
+		}, result.StructuredContent)
+
+		select {
+		case notification := <-session.notifications:
+			assert.Equal(t, "notifications/message", notification.Method)
+			fields := notification.Params.AdditionalFields
+			assert.Equal(t, eventsLogger, fields["logger"])
+			event, ok := fields["data"].(events.Event)
+			require.True(t, ok)
+			assert.Equal(t, "payment.captured", event.Type)
+			assert.Equal(t, "authorized", event.PreviousStatus)
+			assert.True(t, event.Final)
+		case <-time.After(time.Second):
+			t.Fatal("no notification was sent")
+		}
+	})
+
+	t.Run("does not subscribe to final entities", func(t *testing.T) {
+		result := callTool(ctx, "subscribe_events", map[string]interface{}{
+			"entity": "payment", "id": "pay_1",
+		})
+		require.False(t, result.IsError)
+		content, _ := result.StructuredContent.(map[string]interface{})
+		assert.Equal(t, "captured", content["status"])
+		assert.Equal(t, false, content["subscribed"])
+	})
+
+	t.Run("unsubscribes", func(t *testing.T) {
+		result := callTool(ctx, "unsubscribe_events", map[string]interface{}{
+			"entity": "order", "id": "order_1",
+		})
+		require.False(t, result.IsError)
+		assert.Equal(t, map[string]interface{}{
+			"entity":       "order",
+			"id":           "order_1",
+			"unsubscribed": false,
+		}, result.StructuredContent)
+	})
+
+	t.Run("needs a session", func(t *testing.T) {
+		result := callTool(context.Background(), "subscribe_events",
+			map[string]interface{}{"entity": "order", "id": "order_1"})
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text,
+			"subscribing to events needs a session to send notifications to")
+	})
+}
+
+func Test_SubscribeEvents_validation(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name:           "missing entity",
+			Request:        map[string]interface{}{"id": "pay_1"},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: entity",
+		},
+		{
+			Name: "unknown entity",
+			Request: map[string]interface{}{
+				"entity": "refund", "id": "rfnd_1",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid entity: refund, must be payment or order",
+		},
+		{
+			Name: "id of another entity",
+			Request: map[string]interface{}{
+				"entity": "payment", "id": "order_1",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid id: order_1",
+		},
+		{
+			Name: "server without events",
+			Request: map[string]interface{}{
+				"entity": "payment", "id": "pay_1",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "event notifications are not available",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, SubscribeEvents, "Subscription")
+			runToolTest(t, tc, UnsubscribeEvents, "Subscription")
+		})
+	}
+}
+
+// callEventTool calls the tool with the webhook event history in the
+// context, and returns its structured result
+func callEventTool(
+	t *testing.T,
+	history *events.History,
+	toolCreator func(*observability.Observability, *rzpsdk.Client) mcpgo.Tool,
+	args map[string]interface{},
+) map[string]interface{} {
+	tool := toolCreator(CreateTestObservability(), nil)
+	ctx := contextkey.WithEventHistory(context.Background(), history)
+	result, err := tool.GetHandler()(ctx, createMCPRequest(args))
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Text)
+
+	var content map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(result.Text), &content))
+	return content
+}
+
+// webhookEvent returns a webhook event about a payment
+func webhookEvent(id, eventType, paymentID string) events.WebhookEvent {
+	return events.WebhookEvent{
+		ID:    id,
+		Event: eventType,
+		Payload: map[string]interface{}{
+			"payment": map[string]interface{}{
+				"entity": map[string]interface{}{"id": paymentID},
+			},
+		},
+	}
+}
+
+func Test_FetchRecentEvents(t *testing.T) {
+	history := events.NewHistory(10)
+	history.Add(webhookEvent("evt_1", "payment.authorized", "pay_1"))
+	history.Add(webhookEvent("evt_2", "payment.captured", "pay_1"))
+	history.Add(webhookEvent("evt_3", "payment.captured", "pay_2"))
+
+	// ids returns the ids of the events in a result
+	ids := func(content map[string]interface{}) []interface{} {
+		result := []interface{}{}
+		items, _ := content["items"].([]interface{})
+		for _, item := range items {
+			event, _ := item.(map[string]interface{})
+			result = append(result, event["id"])
+		}
+		return result
+	}
+
+	t.Run("returns the latest events", func(t *testing.T) {
+		content := callEventTool(t, history, FetchRecentEvents,
+			map[string]interface{}{})
+		assert.Equal(t, float64(3), content["count"])
+		assert.Equal(t, float64(3), content["last_seq"])
+		assert.Equal(t, []interface{}{"evt_3", "evt_2", "evt_1"},
+			ids(content))
+	})
+
+	t.Run("filters events", func(t *testing.T) {
+		content := callEventTool(t, history, FetchRecentEvents,
+			map[string]interface{}{
+				"events":    []interface{}{"payment.captured"},
+				"entity_id": "pay_1",
+			})
+		assert.Equal(t, []interface{}{"evt_2"}, ids(content))
+
+		content = callEventTool(t, history, FetchRecentEvents,
+			map[string]interface{}{"after_seq": 1, "count": 1})
+		assert.Equal(t, []interface{}{"evt_3"}, ids(content))
+	})
+
+	t.Run("returns the events of the sub-merchant to partners",
+		func(t *testing.T) {
+			history := events.NewHistory(10)
+			for _, account := range []string{"acc_1", "acc_2"} {
+				event := webhookEvent("evt_"+account, "payment.captured",
+					"pay_1")
+				event.AccountID = account
+				history.Add(event)
+			}
+
+			tool := FetchRecentEvents(CreateTestObservability(), nil)
+			ctx := contextkey.WithEventHistory(context.Background(), history)
+			ctx = contextkey.WithMerchantAccount(ctx, "acc_1")
+			result, err := tool.GetHandler()(ctx,
+				createMCPRequest(map[string]interface{}{}))
+			require.NoError(t, err)
+			require.False(t, result.IsError, result.Text)
+
+			var content map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(result.Text), &content))
+			assert.Equal(t, []interface{}{"evt_acc_1"}, ids(content))
+		})
+
+	t.Run("refuses calls with other credentials", func(t *testing.T) {
+		for _, toolCreator := range []func(*observability.Observability,
+			*rzpsdk.Client) mcpgo.Tool{FetchRecentEvents, WaitForEvent} {
+			tool := toolCreator(CreateTestObservability(), nil)
+			ctx := contextkey.WithEventHistory(context.Background(), history)
+			ctx = contextkey.WithClient(ctx,
+				rzpsdk.NewClient("other-key", "other-secret"))
+			result, err := tool.GetHandler()(ctx,
+				createMCPRequest(map[string]interface{}{}))
+			require.NoError(t, err)
+
+			assert.True(t, result.IsError)
+			assert.Equal(t, "webhook events are only available to calls "+
+				"made with the credentials of the server", result.Text)
+		}
+	})
+
+	t.Run("reads the history of the server", func(t *testing.T) {
+		server, err := NewRzpMcpServer(CreateTestObservability(),
+			rzpsdk.NewClient("test-key", "test-secret"),
+			[]string{"webhooks"}, nil, nil, true, WithEventHistory(history))
+		require.NoError(t, err)
+		impl, ok := server.(*mcpgo.Mark3labsImpl)
+		require.True(t, ok)
+
+		tool := impl.McpServer.GetTool("fetch_recent_events")
+		require.NotNil(t, tool)
+		request := mcp.CallToolRequest{}
+		request.Params.Name = "fetch_recent_events"
+		request.Params.Arguments = map[string]interface{}{}
+		result, err := tool.Handler(context.Background(), request)
+		require.NoError(t, err)
+		require.False(t, result.IsError)
+		content, _ := result.StructuredContent.(map[string]interface{})
+		assert.Equal(t, float64(3), content["count"])
+	})
+}
+
+func Test_WaitForEvent(t *testing.T) {
+	defaultUnit := pollUnit
+	pollUnit = 10 * time.Millisecond
+	defer func() { pollUnit = defaultUnit }()
+
+	t.Run("waits for a new event", func(t *testing.T) {
+		history := events.NewHistory(10)
+		history.Add(webhookEvent("evt_1", "payment.captured", "pay_1"))
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			history.Add(webhookEvent("evt_2", "payment.captured", "pay_2"))
+		}()
+
+		content := callEventTool(t, history, WaitForEvent,
+			map[string]interface{}{
+				"events":  []interface{}{"payment.*"},
+				"timeout": 100,
+			})
+		assert.Equal(t, true, content["received"])
+		assert.Equal(t, float64(2), content["last_seq"])
+		event, _ := content["event"].(map[string]interface{})
+		assert.Equal(t, "evt_2", event["id"])
+	})
+
+	t.Run("returns kept events after after_seq", func(t *testing.T) {
+		history := events.NewHistory(10)
+		history.Add(webhookEvent("evt_1", "payment.captured", "pay_1"))
+
+		content := callEventTool(t, history, WaitForEvent,
+			map[string]interface{}{"after_seq": 0, "timeout": 1})
+		assert.Equal(t, true, content["received"])
+		event, _ := content["event"].(map[string]interface{})
+		assert.Equal(t, "evt_1", event["id"])
+	})
+
+	t.Run("gives up after the timeout", func(t *testing.T) {
+		history := events.NewHistory(10)
+		history.Add(webhookEvent("evt_1", "payment.captured", "pay_1"))
+
+		content := callEventTool(t, history, WaitForEvent,
+			map[string]interface{}{"entity_id": "pay_2", "timeout": 1})
+		assert.Equal(t, map[string]interface{}{
+			"received": false,
+			"last_seq": float64(1),
+		}, content)
+	})
+}
+
+func Test_EventHistoryTools_validation(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "event types that are not strings",
+			Request: map[string]interface{}{
+				"events": []interface{}{1},
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "events must be a list of event types",
+		},
+		{
+			Name:           "negative after_seq",
+			Request:        map[string]interface{}{"after_seq": -1},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "after_seq must not be negative",
+		},
+		{
+			Name:           "server without webhooks",
+			Request:        map[string]interface{}{},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "webhook events are not available",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchRecentEvents, "Events")
+			runToolTest(t, tc, WaitForEvent, "Event")
+		})
+	}
+
+	runToolTest(t, RazorpayToolTestCase{
+		Name:           "count above the maximum",
+		Request:        map[string]interface{}{"count": 101},
+		ExpectError:    true,
+		ExpectedErrMsg: "count must be between 1 and 100",
+	}, FetchRecentEvents, "Events")
+	runToolTest(t, RazorpayToolTestCase{
+		Name:           "timeout above the maximum",
+		Request:        map[string]interface{}{"timeout": 301},
+		ExpectError:    true,
+		ExpectedErrMsg: "timeout must be between 1 and 300",
+	}, WaitForEvent, "Event")
+}
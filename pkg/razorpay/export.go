@@ -0,0 +1,302 @@
+package razorpay
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+)
+
+// exportToolTimeout is how long calls of the tools that export every page
+// of a report may take
+const exportToolTimeout = 5 * time.Minute
+
+// Formats of the collections fetch all tools return
+const (
+	collectionFormatJSON   = "json"
+	collectionFormatCSV    = "csv"
+	collectionFormatNDJSON = "ndjson"
+)
+
+// collectionFormatParameter returns the parameter that selects the format
+// of the collection a fetch all tool returns
+func collectionFormatParameter() mcpgo.ToolParameter {
+	return mcpgo.WithString(
+		"format",
+		mcpgo.Description("Format of the result: json, the collection as "+
+			"the API returns it (default), csv, a header row and a row per "+
+			"record, or ndjson, a JSON line per record. csv and ndjson "+
+			"flatten nested objects into fields named by their path, such "+
+			"as notes.key, for spreadsheets and data pipelines, and keep "+
+			"only the fields selected by fields, if set"),
+		mcpgo.Enum(collectionFormatJSON, collectionFormatCSV,
+			collectionFormatNDJSON),
+	)
+}
+
+// ValidateAndAddCollectionFormat validates and adds the format of the
+// collection to return
+func (v *Validator) ValidateAndAddCollectionFormat(
+	params map[string]interface{},
+) *Validator {
+	v.ValidateAndAddOptionalString(params, "format")
+
+	switch params["format"] {
+	case nil, collectionFormatJSON, collectionFormatCSV,
+		collectionFormatNDJSON:
+		return v
+	}
+	return v.addError(fmt.Errorf("format must be %s, %s or %s",
+		collectionFormatJSON, collectionFormatCSV, collectionFormatNDJSON))
+}
+
+// collectionResult returns the collection in the format set in params: as
+// JSON, or its items flattened, as CSV or NDJSON text. Field selection
+// does not prune text, so the items are pruned to the fields the call
+// selects before they are flattened.
+func collectionResult(
+	ctx context.Context,
+	collection map[string]interface{},
+	params map[string]interface{},
+) (*mcpgo.ToolResult, error) {
+	format, _ := params["format"].(string)
+	if format == "" || format == collectionFormatJSON {
+		return mcpgo.NewToolResultJSON(collection)
+	}
+
+	paths := mcpgo.SelectedFieldsFromContext(ctx)
+	items, _ := collection["items"].([]interface{})
+	records := make([]interface{}, len(items))
+	for i, item := range items {
+		record, _ := item.(map[string]interface{})
+		if len(paths) > 0 {
+			record = mcpgo.SelectFields(record, paths)
+		}
+		records[i] = flattenRecord(record)
+	}
+
+	var text strings.Builder
+	if format == collectionFormatCSV {
+		err := encodeCSV(&text, csvColumns(records, []string{"id"}), records)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		encoder := json.NewEncoder(&text)
+		for _, record := range records {
+			if err := encoder.Encode(record); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return mcpgo.NewToolResultRawText(text.String()), nil
+}
+
+// flattenRecord returns the fields of the record with nested objects
+// replaced by their fields, named by their path, such as notes.key.
+// Arrays are kept as they are.
+func flattenRecord(record map[string]interface{}) map[string]interface{} {
+	flat := make(map[string]interface{}, len(record))
+	flattenFields(flat, "", record)
+	return flat
+}
+
+// flattenFields adds the fields to flat, with the prefix before their names
+func flattenFields(
+	flat map[string]interface{},
+	prefix string,
+	fields map[string]interface{},
+) {
+	for name, value := range fields {
+		if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+			flattenFields(flat, prefix+name+".", nested)
+			continue
+		}
+		flat[prefix+name] = value
+	}
+}
+
+// resolveExportPath resolves a user supplied export file path against the
+// current working directory. Absolute paths and paths escaping the working
+// directory are rejected to prevent writing to arbitrary locations.
+func resolveExportPath(path string) (string, error) {
+	if path == "" {
+		return "", fmt.Errorf("output_file must not be empty")
+	}
+
+	if filepath.IsAbs(path) {
+		return "", fmt.Errorf("output_file must be a relative path")
+	}
+
+	cleaned := filepath.Clean(path)
+	if cleaned == "." || cleaned == ".." ||
+		strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf(
+			"output_file must point to a file inside the working directory")
+	}
+
+	baseDir, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("resolving working directory: %s", err.Error())
+	}
+
+	return filepath.Join(baseDir, cleaned), nil
+}
+
+// writeNDJSON writes each record as a single JSON line to the file at path,
+// replacing any existing content. Missing parent directories are created.
+// It returns the number of rows written.
+func writeNDJSON(path string, records []interface{}) (int, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return 0, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		return 0, err
+	}
+
+	return len(records), file.Close()
+}
+
+// csvColumns returns the columns of a CSV export of the records: the
+// leading columns first, then the other fields of the records in
+// alphabetical order
+func csvColumns(records []interface{}, leading []string) []string {
+	columns := append([]string{}, leading...)
+	seen := make(map[string]bool, len(leading))
+	for _, column := range leading {
+		seen[column] = true
+	}
+
+	var extra []string
+	for _, record := range records {
+		fields, _ := record.(map[string]interface{})
+		for field := range fields {
+			if !seen[field] {
+				seen[field] = true
+				extra = append(extra, field)
+			}
+		}
+	}
+	sort.Strings(extra)
+
+	return append(columns, extra...)
+}
+
+// encodeCSV writes a header row with the columns, then a row per record.
+// Nested values are written as JSON and missing or null fields as empty
+// cells.
+func encodeCSV(w io.Writer, columns []string, records []interface{}) error {
+	writer := csv.NewWriter(w)
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = escapeCSVFormula(column)
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	row := make([]string, len(columns))
+	for _, record := range records {
+		fields, _ := record.(map[string]interface{})
+		for i, column := range columns {
+			cell, err := csvCell(fields[column])
+			if err != nil {
+				return err
+			}
+			row[i] = cell
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvCell formats a field value as a CSV cell. Numbers are written without
+// exponents, so that amounts and timestamps read back unchanged.
+func csvCell(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return escapeCSVFormula(v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(encoded), nil
+	}
+}
+
+// escapeCSVFormula returns the text of a CSV cell with a ' before it if
+// spreadsheets would run it as a formula, such as a customer name
+// starting with =. Notes and names are set by customers, so their cells
+// must not run in the spreadsheets of merchants.
+func escapeCSVFormula(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
+
+// writeCSV writes the records as CSV to the file at path, replacing any
+// existing content. Missing parent directories are created. It returns the
+// number of rows written, not counting the header.
+func writeCSV(
+	path string,
+	columns []string,
+	records []interface{},
+) (int, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return 0, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	if err := encodeCSV(writer, columns, records); err != nil {
+		return 0, err
+	}
+	if err := writer.Flush(); err != nil {
+		return 0, err
+	}
+
+	return len(records), file.Close()
+}
@@ -0,0 +1,179 @@
+package razorpay
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/razorpay/razorpay-go/constants"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay/mock"
+)
+
+func TestCollectionResult(t *testing.T) {
+	ctx := context.Background()
+	collection := collectionOf(
+		map[string]interface{}{
+			"id":     "pay_1",
+			"amount": float64(50000),
+			"notes":  map[string]interface{}{"order": "A-1, B-2"},
+			"acquirer_data": map[string]interface{}{
+				"rrn": "123", "upi": map[string]interface{}{"vpa": "a@upi"},
+			},
+			"offers": []interface{}{"offer_1"},
+		},
+		map[string]interface{}{
+			"id":     "pay_2",
+			"amount": float64(1e10),
+			"notes":  []interface{}{},
+		},
+	)
+
+	t.Run("returns json by default", func(t *testing.T) {
+		result, err := collectionResult(ctx, collection,
+			map[string]interface{}{})
+		require.NoError(t, err)
+
+		assert.False(t, result.Raw)
+		assert.Contains(t, result.Text, `"entity":"collection"`)
+	})
+
+	t.Run("flattens records into csv", func(t *testing.T) {
+		result, err := collectionResult(ctx, collection,
+			map[string]interface{}{"format": "csv"})
+		require.NoError(t, err)
+
+		assert.True(t, result.Raw)
+		assert.Equal(t,
+			"id,acquirer_data.rrn,acquirer_data.upi.vpa,amount,notes,"+
+				"notes.order,offers\n"+
+				`pay_1,123,a@upi,50000,,"A-1, B-2","[""offer_1""]"`+"\n"+
+				"pay_2,,,10000000000,[],,\n",
+			result.Text)
+	})
+
+	t.Run("flattens records into ndjson", func(t *testing.T) {
+		result, err := collectionResult(ctx, collection,
+			map[string]interface{}{"format": "ndjson"})
+		require.NoError(t, err)
+
+		assert.True(t, result.Raw)
+		assert.Equal(t,
+			`{"acquirer_data.rrn":"123","acquirer_data.upi.vpa":"a@upi",`+
+				`"amount":50000,"id":"pay_1","notes.order":"A-1, B-2",`+
+				`"offers":["offer_1"]}`+"\n"+
+				`{"amount":10000000000,"id":"pay_2","notes":[]}`+"\n",
+			result.Text)
+	})
+
+	t.Run("keeps the selected fields", func(t *testing.T) {
+		ctx := mcpgo.ContextWithSelectedFields(ctx,
+			[]string{"amount", "acquirer_data.upi"})
+
+		result, err := collectionResult(ctx, collection,
+			map[string]interface{}{"format": "csv"})
+		require.NoError(t, err)
+		assert.Equal(t,
+			"id,acquirer_data.upi.vpa,amount\n"+
+				"pay_1,a@upi,50000\n"+
+				"pay_2,,10000000000\n",
+			result.Text)
+
+		result, err = collectionResult(ctx, collection,
+			map[string]interface{}{"format": "ndjson"})
+		require.NoError(t, err)
+		assert.Equal(t,
+			`{"acquirer_data.upi.vpa":"a@upi","amount":50000,"id":"pay_1"}`+
+				"\n"+`{"amount":10000000000,"id":"pay_2"}`+"\n",
+			result.Text)
+	})
+
+	t.Run("escapes csv cells spreadsheets run as formulas",
+		func(t *testing.T) {
+			result, err := collectionResult(ctx, collectionOf(
+				map[string]interface{}{
+					"id":     "cust_1",
+					"amount": float64(-100),
+					"=f":     "g",
+					"notes": map[string]interface{}{
+						"a": "=HYPERLINK(\"x\")", "b": "+1", "c": "-1",
+						"d": "@SUM(A1)", "e": "a=b",
+					},
+				},
+			), map[string]interface{}{"format": "csv"})
+			require.NoError(t, err)
+
+			assert.Equal(t,
+				"id,'=f,amount,notes.a,notes.b,notes.c,notes.d,notes.e\n"+
+					`cust_1,g,-100,"'=HYPERLINK(""x"")",'+1,'-1,'@SUM(A1),`+
+					"a=b\n",
+				result.Text)
+		})
+
+	t.Run("returns csv from fetch all tools", func(t *testing.T) {
+		paymentsPath := fmt.Sprintf("/%s%s", constants.VERSION_V1,
+			constants.PAYMENT_URL)
+		client, server := newMockRzpClient(func() (
+			*http.Client, *httptest.Server) {
+			return mock.NewHTTPClient(mock.Endpoint{
+				Path: paymentsPath, Method: "GET", Response: collection,
+			})
+		})
+		defer server.Close()
+
+		tool := FetchAllPayments(CreateTestObservability(), client)
+		result, err := tool.GetHandler()(context.Background(),
+			createMCPRequest(map[string]interface{}{"format": "csv"}))
+		require.NoError(t, err)
+
+		assert.False(t, result.IsError, result.Text)
+		assert.True(t, result.Raw)
+		assert.Contains(t, result.Text, "pay_2,,,10000000000,[],,\n")
+	})
+}
+
+func TestValidator_ValidateAndAddCollectionFormat(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      map[string]interface{}
+		want      map[string]interface{}
+		wantError bool
+	}{
+		{
+			name: "adds the format",
+			args: map[string]interface{}{"format": "ndjson"},
+			want: map[string]interface{}{"format": "ndjson"},
+		},
+		{
+			name: "leaves out a missing format",
+			args: map[string]interface{}{},
+			want: map[string]interface{}{},
+		},
+		{
+			name:      "rejects unknown formats",
+			args:      map[string]interface{}{"format": "xlsx"},
+			wantError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			request := createMCPRequest(tc.args)
+			params := make(map[string]interface{})
+
+			validator := NewValidator(&request).
+				ValidateAndAddCollectionFormat(params)
+
+			assert.Equal(t, tc.wantError, validator.HasErrors())
+			if !tc.wantError {
+				assert.Equal(t, tc.want, params)
+			}
+		})
+	}
+}
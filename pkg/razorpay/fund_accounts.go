@@ -0,0 +1,364 @@
+package razorpay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+	"github.com/razorpay/razorpay-go/constants"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+)
+
+// buildFundAccountDetails moves the flattened bank account or VPA
+// parameters of a create_fund_account request into the nested object the
+// API expects for the account type
+func buildFundAccountDetails(
+	accountType string,
+	fields map[string]interface{},
+	fundAccountData map[string]interface{},
+) error {
+	switch accountType {
+	case "bank_account":
+		details := make(map[string]interface{})
+		for _, field := range []string{"name", "ifsc", "account_number"} {
+			value, ok := fields[field]
+			if !ok {
+				return fmt.Errorf(
+					"%s is required for bank_account fund accounts", field)
+			}
+			details[field] = value
+		}
+		fundAccountData["bank_account"] = details
+	case "vpa":
+		address, ok := fields["vpa"]
+		if !ok {
+			return errors.New("vpa is required for vpa fund accounts")
+		}
+		fundAccountData["vpa"] = map[string]interface{}{"address": address}
+	default:
+		return errors.New("account_type must be either 'bank_account' or 'vpa'")
+	}
+
+	return nil
+}
+
+// CreateFundAccount returns a tool that creates a fund account for a
+// customer or a RazorpayX contact
+func CreateFundAccount(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"customer_id",
+			mcpgo.Description("ID of the customer the fund account belongs "+
+				"to (ID should have a cust_ prefix). Set either customer_id "+
+				"or contact_id"),
+		),
+		mcpgo.WithString(
+			"contact_id",
+			mcpgo.Description("ID of the RazorpayX contact the fund account "+
+				"belongs to, for payouts (ID should have a cont_ prefix). Set "+
+				"either customer_id or contact_id"),
+		),
+		mcpgo.WithString(
+			"account_type",
+			mcpgo.Description("Type of the fund account"),
+			mcpgo.Required(),
+			mcpgo.Enum("bank_account", "vpa"),
+		),
+		mcpgo.WithString(
+			"name",
+			mcpgo.Description("Name of the bank account holder. Required "+
+				"for bank_account"),
+		),
+		mcpgo.WithString(
+			"ifsc",
+			mcpgo.Description("IFSC of the bank branch. Required for "+
+				"bank_account"),
+		),
+		mcpgo.WithString(
+			"account_number",
+			mcpgo.Description("Bank account number. Required for "+
+				"bank_account"),
+		),
+		mcpgo.WithString(
+			"vpa",
+			mcpgo.Description("UPI ID of the customer, for example "+
+				"gaurav.kumar@exampleupi. Required for vpa"),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+		fundAccountData := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddOptionalString(fundAccountData, "customer_id").
+			ValidateAndAddOptionalString(fundAccountData, "contact_id").
+			ValidateAndAddRequiredString(fundAccountData, "account_type").
+			ValidateAndAddOptionalString(fields, "name").
+			ValidateAndAddOptionalString(fields, "ifsc").
+			ValidateAndAddOptionalString(fields, "account_number").
+			ValidateAndAddOptionalString(fields, "vpa")
+
+		_, hasCustomer := fundAccountData["customer_id"]
+		_, hasContact := fundAccountData["contact_id"]
+		if hasCustomer == hasContact {
+			validator.addError(errors.New(
+				"exactly one of customer_id or contact_id is required"))
+		}
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		err = buildFundAccountDetails(
+			fundAccountData["account_type"].(string), fields, fundAccountData)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fundAccount, err := client.FundAccount.Create(fundAccountData, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("creating fund account failed: %s",
+					err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(fundAccount)
+	}
+
+	return mcpgo.NewTool(
+		"create_fund_account",
+		"Create a fund account holding the bank account or UPI ID of a "+
+			"customer, for refunds to bank, or of a RazorpayX contact, for "+
+			"payouts",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema)
+}
+
+// FetchAllFundAccounts returns a tool that fetches the fund accounts of a
+// customer
+func FetchAllFundAccounts(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"customer_id",
+			mcpgo.Description("ID of the customer whose fund accounts are "+
+				"to be fetched (ID should have a cust_ prefix)."),
+			mcpgo.Required(),
+		),
+		collectionFormatParameter(),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		queryParams := make(map[string]interface{})
+		options := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(queryParams, "customer_id").
+			ValidateAndAddCollectionFormat(options)
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		fundAccounts, err := client.FundAccount.All(queryParams, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching fund accounts failed: %s",
+					err.Error())), nil
+		}
+
+		return collectionResult(ctx, fundAccounts, options)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_all_fund_accounts",
+		"Fetch the fund accounts of a customer",
+		parameters,
+		handler,
+	).WithOutputSchema(entitiesOutputSchema).
+		WithFieldSelection()
+}
+
+// ValidateBankAccount returns a tool that starts a validation of a fund
+// account by depositing a small amount into it
+func ValidateBankAccount(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"account_number",
+			mcpgo.Description("RazorpayX account number the validation "+
+				"amount is debited from. For example, 7878780080316316"),
+			mcpgo.Required(),
+		),
+		mcpgo.WithString(
+			"fund_account_id",
+			mcpgo.Description("ID of the fund account to validate "+
+				"(ID should have an fa_ prefix)."),
+			mcpgo.Required(),
+		),
+		mcpgo.WithNumber(
+			"amount",
+			mcpgo.Description("Amount in paise deposited into the account "+
+				"to validate it. Defaults to 100"),
+			mcpgo.Min(100),
+			mcpgo.DefaultValue(100),
+		),
+		mcpgo.WithString(
+			"currency",
+			mcpgo.Description("ISO code for the currency. Defaults to INR"),
+		),
+		mcpgo.WithObject(
+			"notes",
+			mcpgo.Description("Key-value pairs for additional information "+
+				"(max 15 pairs, 256 chars each)"),
+			mcpgo.MaxProperties(15),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+		validationData := map[string]interface{}{
+			"amount":   100,
+			"currency": "INR",
+		}
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(validationData, "account_number").
+			ValidateAndAddRequiredString(fields, "fund_account_id").
+			ValidateAndAddOptionalInt(validationData, "amount").
+			ValidateAndAddOptionalString(validationData, "currency").
+			ValidateAndAddOptionalMap(validationData, "notes")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		validationData["fund_account"] = map[string]interface{}{
+			"id": fields["fund_account_id"],
+		}
+
+		url := fmt.Sprintf("/%s%s/validations",
+			constants.VERSION_V1, constants.FUND_ACCOUNT_URL)
+
+		validation, err := client.Request.Post(url, validationData, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("validating bank account failed: %s",
+					err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(validation)
+	}
+
+	return mcpgo.NewTool(
+		"validate_bank_account",
+		"Validate a fund account by depositing a small amount into it. "+
+			"Validation is asynchronous: poll "+
+			"fetch_bank_account_validation with the returned ID until its "+
+			"status is 'completed' or 'failed', then read the account "+
+			"status and registered name from its results.",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema).WithAmounts("amount")
+}
+
+// FetchBankAccountValidation returns a tool that fetches the status of a
+// fund account validation
+func FetchBankAccountValidation(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"validation_id",
+			mcpgo.Description("ID of the validation to be fetched "+
+				"(ID should have a fav_ prefix)."),
+			mcpgo.Required(),
+			mcpgo.Pattern(mcpgo.IDPattern(fundAccountValidationIDPrefix)),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(fields, "validation_id")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		validationID := fields["validation_id"].(string)
+		if result := validateID("validation_id",
+			validationID, fundAccountValidationIDPrefix); result != nil {
+			return result, nil
+		}
+
+		url := fmt.Sprintf("/%s%s/validations/%s",
+			constants.VERSION_V1, constants.FUND_ACCOUNT_URL, validationID)
+
+		validation, err := client.Request.Get(url, nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching bank account validation failed: %s",
+					err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(validation)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_bank_account_validation",
+		"Fetch the status of a bank account validation. While the status "+
+			"is 'created' the validation is still in progress; once "+
+			"'completed', results contain the account status and the name "+
+			"registered with the bank.",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema).
+		WithFieldSelection()
+}
@@ -0,0 +1,373 @@
+package razorpay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/razorpay/razorpay-go/constants"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay/mock"
+)
+
+var (
+	fundAccountsPath = fmt.Sprintf(
+		"/%s%s",
+		constants.VERSION_V1,
+		constants.FUND_ACCOUNT_URL,
+	)
+	fundAccountValidationsPath = fundAccountsPath + "/validations"
+)
+
+var fundAccountResp = map[string]interface{}{
+	"id":           "fa_Aa00000000001",
+	"entity":       "fund_account",
+	"customer_id":  "cust_Aa000000000001",
+	"account_type": "bank_account",
+	"bank_account": map[string]interface{}{
+		"name":           "Gaurav Kumar",
+		"ifsc":           "HDFC0000053",
+		"account_number": "765432123456789",
+	},
+	"active": true,
+}
+
+var fundAccountValidationResp = map[string]interface{}{
+	"id":     "fav_00000000000001",
+	"entity": "fund_account.validation",
+	"fund_account": map[string]interface{}{
+		"id": "fa_Aa00000000001",
+	},
+	"status": "completed",
+	"amount": float64(100),
+	"results": map[string]interface{}{
+		"account_status":  "active",
+		"registered_name": "Gaurav Kumar",
+	},
+}
+
+var fundAccountErrorResp = map[string]interface{}{
+	"error": map[string]interface{}{
+		"code":        "BAD_REQUEST_ERROR",
+		"description": "The id provided does not exist",
+	},
+}
+
+func Test_CreateFundAccount(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful bank account creation",
+			Request: map[string]interface{}{
+				"customer_id":    "cust_Aa000000000001",
+				"account_type":   "bank_account",
+				"name":           "Gaurav Kumar",
+				"ifsc":           "HDFC0000053",
+				"account_number": "765432123456789",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     fundAccountsPath,
+						Method:   "POST",
+						Response: fundAccountResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: fundAccountResp,
+		},
+		{
+			Name: "bank account without ifsc",
+			Request: map[string]interface{}{
+				"customer_id":    "cust_Aa000000000001",
+				"account_type":   "bank_account",
+				"name":           "Gaurav Kumar",
+				"account_number": "765432123456789",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "ifsc is required for bank_account fund accounts",
+		},
+		{
+			Name: "vpa without address",
+			Request: map[string]interface{}{
+				"customer_id":  "cust_Aa000000000001",
+				"account_type": "vpa",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "vpa is required for vpa fund accounts",
+		},
+		{
+			Name: "unsupported account type",
+			Request: map[string]interface{}{
+				"customer_id":  "cust_Aa000000000001",
+				"account_type": "card",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "account_type must be either 'bank_account' or 'vpa'",
+		},
+		{
+			Name: "vpa fund account for a contact",
+			Request: map[string]interface{}{
+				"contact_id":   "cont_00000000000001",
+				"account_type": "vpa",
+				"vpa":          "gaurav.kumar@exampleupi",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     fundAccountsPath,
+						Method:   "POST",
+						Response: fundAccountResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: fundAccountResp,
+		},
+		{
+			Name: "neither customer_id nor contact_id",
+			Request: map[string]interface{}{
+				"account_type": "vpa",
+				"vpa":          "gaurav.kumar@exampleupi",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "exactly one of customer_id or contact_id is " +
+				"required",
+		},
+		{
+			Name: "both customer_id and contact_id",
+			Request: map[string]interface{}{
+				"customer_id":  "cust_Aa000000000001",
+				"contact_id":   "cont_00000000000001",
+				"account_type": "vpa",
+				"vpa":          "gaurav.kumar@exampleupi",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "exactly one of customer_id or contact_id is " +
+				"required",
+		},
+		{
+			Name: "fund account creation fails",
+			Request: map[string]interface{}{
+				"customer_id":  "cust_Aa000000000001",
+				"account_type": "vpa",
+				"vpa":          "gaurav.kumar@exampleupi",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     fundAccountsPath,
+						Method:   "POST",
+						Response: fundAccountErrorResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "creating fund account failed: " +
+				"The id provided does not exist",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, CreateFundAccount, "Fund account")
+		})
+	}
+
+	t.Run("vpa is nested under its account type", func(t *testing.T) {
+		var body map[string]interface{}
+
+		mockClient := func() (*http.Client, *httptest.Server) {
+			server := httptest.NewServer(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					_ = json.NewDecoder(r.Body).Decode(&body)
+					_ = json.NewEncoder(w).Encode(fundAccountResp)
+				}))
+			return server.Client(), server
+		}
+
+		client, server := newMockRzpClient(mockClient)
+		defer server.Close()
+
+		tool := CreateFundAccount(CreateTestObservability(), client)
+		result, err := tool.GetHandler()(context.Background(),
+			createMCPRequest(map[string]interface{}{
+				"customer_id":  "cust_Aa000000000001",
+				"account_type": "vpa",
+				"vpa":          "gaurav.kumar@exampleupi",
+			}))
+		require.NoError(t, err)
+		require.False(t, result.IsError, result.Text)
+
+		assert.Equal(t, map[string]interface{}{
+			"customer_id":  "cust_Aa000000000001",
+			"account_type": "vpa",
+			"vpa": map[string]interface{}{
+				"address": "gaurav.kumar@exampleupi",
+			},
+		}, body)
+	})
+}
+
+func Test_FetchAllFundAccounts(t *testing.T) {
+	fundAccountsResp := map[string]interface{}{
+		"entity": "collection",
+		"count":  float64(1),
+		"items":  []interface{}{fundAccountResp},
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful fund accounts fetch",
+			Request: map[string]interface{}{
+				"customer_id": "cust_Aa000000000001",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     fundAccountsPath,
+						Method:   "GET",
+						Response: fundAccountsResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: fundAccountsResp,
+		},
+		{
+			Name:           "missing customer_id",
+			Request:        map[string]interface{}{},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: customer_id",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchAllFundAccounts, "Fund accounts")
+		})
+	}
+}
+
+func Test_ValidateBankAccount(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "validation fails",
+			Request: map[string]interface{}{
+				"account_number":  "7878780080316316",
+				"fund_account_id": "fa_Aa00000000001",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     fundAccountValidationsPath,
+						Method:   "POST",
+						Response: fundAccountErrorResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "validating bank account failed: " +
+				"The id provided does not exist",
+		},
+		{
+			Name: "missing fund_account_id",
+			Request: map[string]interface{}{
+				"account_number": "7878780080316316",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: fund_account_id",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, ValidateBankAccount, "Validation")
+		})
+	}
+
+	t.Run("defaults amount and currency", func(t *testing.T) {
+		var body map[string]interface{}
+
+		mockClient := func() (*http.Client, *httptest.Server) {
+			server := httptest.NewServer(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					_ = json.NewDecoder(r.Body).Decode(&body)
+					_ = json.NewEncoder(w).Encode(fundAccountValidationResp)
+				}))
+			return server.Client(), server
+		}
+
+		client, server := newMockRzpClient(mockClient)
+		defer server.Close()
+
+		tool := ValidateBankAccount(CreateTestObservability(), client)
+		result, err := tool.GetHandler()(context.Background(),
+			createMCPRequest(map[string]interface{}{
+				"account_number":  "7878780080316316",
+				"fund_account_id": "fa_Aa00000000001",
+			}))
+		require.NoError(t, err)
+		require.False(t, result.IsError, result.Text)
+
+		assert.Equal(t, map[string]interface{}{
+			"account_number": "7878780080316316",
+			"amount":         float64(100),
+			"currency":       "INR",
+			"fund_account": map[string]interface{}{
+				"id": "fa_Aa00000000001",
+			},
+		}, body)
+	})
+}
+
+func Test_FetchBankAccountValidation(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful validation fetch",
+			Request: map[string]interface{}{
+				"validation_id": "fav_00000000000001",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path: fundAccountValidationsPath +
+							"/fav_00000000000001",
+						Method:   "GET",
+						Response: fundAccountValidationResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: fundAccountValidationResp,
+		},
+		{
+			Name: "malformed validation_id",
+			Request: map[string]interface{}{
+				"validation_id": "fav_1/../../payments",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid validation_id: fav_1/../../payments",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchBankAccountValidation, "Validation")
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package razorpay
+
+import (
+	"fmt"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+)
+
+// Prefixes of the IDs of Razorpay entities
+const (
+	accountIDPrefix               = mcpgo.AccountIDPrefix
+	customerIDPrefix              = "cust_"
+	disputeIDPrefix               = "disp_"
+	documentIDPrefix              = "doc_"
+	downtimeIDPrefix              = "down_"
+	fundAccountValidationIDPrefix = "fav_"
+	instantSettlementIDPrefix     = "setlod_"
+	invoiceIDPrefix               = "inv_"
+	itemIDPrefix                  = "item_"
+	offerIDPrefix                 = "offer_"
+	orderIDPrefix                 = "order_"
+	paymentIDPrefix               = "pay_"
+	paymentLinkIDPrefix           = "plink_"
+	payoutIDPrefix                = "pout_"
+	payoutLinkIDPrefix            = "poutlk_"
+	planIDPrefix                  = "plan_"
+	productIDPrefix               = "acc_prd_"
+	qrCodeIDPrefix                = "qr_"
+	refundIDPrefix                = "rfnd_"
+	settlementIDPrefix            = "setl_"
+	subscriptionIDPrefix          = "sub_"
+	tokenIDPrefix                 = "token_"
+	transferIDPrefix              = "trf_"
+	virtualAccountIDPrefix        = "va_"
+	// webhookIDPrefix is empty, as webhook IDs have no prefix
+	webhookIDPrefix = ""
+)
+
+// validateID returns an error tool result if the ID passed for the named
+// parameter is not an ID with the prefix. The SDK builds URLs without
+// escaping the IDs in their paths, so IDs must be checked before use.
+func validateID(name, id, prefix string) *mcpgo.ToolResult {
+	if !mcpgo.IsID(id, prefix) {
+		return mcpgo.NewToolResultValidationError(
+			fmt.Sprintf("invalid %s: %s", name, id), name)
+	}
+	return nil
+}
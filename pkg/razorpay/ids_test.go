@@ -0,0 +1,51 @@
+package razorpay
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+)
+
+func Test_validateID(t *testing.T) {
+	tests := []struct {
+		name   string
+		id     string
+		prefix string
+		valid  bool
+	}{
+		{name: "ID with the prefix", id: "pay_MT48CvBhIC98MQ",
+			prefix: paymentIDPrefix, valid: true},
+		{name: "ID without a prefix", id: "HK890egfiItP3H",
+			prefix: webhookIDPrefix, valid: true},
+		{name: "ID with another prefix", id: "order_MT48BXknWaJFRw",
+			prefix: paymentIDPrefix},
+		{name: "prefix alone", id: "pay_", prefix: paymentIDPrefix},
+		{name: "empty ID", id: "", prefix: webhookIDPrefix},
+		{name: "ID with a path", id: "pay_1/../../orders",
+			prefix: paymentIDPrefix},
+		{name: "ID with a query", id: "pay_1?expand[]=card",
+			prefix: paymentIDPrefix},
+		{name: "product ID as account ID", id: "acc_prd_HEgNpywUFctQ9e",
+			prefix: accountIDPrefix},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := validateID("id", tt.id, tt.prefix)
+			if tt.valid {
+				assert.Nil(t, result)
+				return
+			}
+
+			require.NotNil(t, result)
+			assert.Equal(t, "invalid id: "+tt.id, result.Text)
+			assert.Equal(t, &mcpgo.ToolError{
+				Code:  mcpgo.ValidationErrorCode,
+				Field: "id",
+			}, result.Error)
+		})
+	}
+}
@@ -0,0 +1,673 @@
+package razorpay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+	"github.com/razorpay/razorpay-go/constants"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+)
+
+// invoiceIDParameter returns the invoice_id parameter shared by the tools
+// that operate on a single invoice
+func invoiceIDParameter(description string) mcpgo.ToolParameter {
+	return mcpgo.WithString(
+		"invoice_id",
+		mcpgo.Description(description+" (ID should have an inv_ prefix)."),
+		mcpgo.Required(),
+		mcpgo.Pattern(mcpgo.IDPattern(invoiceIDPrefix)),
+	)
+}
+
+// CreateInvoice returns a tool that creates an invoice
+func CreateInvoice(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithArray(
+			"line_items",
+			mcpgo.Description("Items billed in the invoice. Each item either "+
+				"references an existing item with 'item_id', or has a 'name' "+
+				"and an 'amount' in the smallest currency unit, with an "+
+				"optional 'description' and 'quantity'"),
+			mcpgo.Required(),
+			mcpgo.Min(1),
+			mcpgo.Items(map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"item_id": map[string]interface{}{
+						"type": "string",
+					},
+					"name": map[string]interface{}{
+						"type": "string",
+					},
+					"description": map[string]interface{}{
+						"type": "string",
+					},
+					"amount": map[string]interface{}{
+						"type":    "number",
+						"minimum": 1,
+					},
+					"quantity": map[string]interface{}{
+						"type":    "number",
+						"minimum": 1,
+					},
+				},
+			}),
+		),
+		mcpgo.WithString(
+			"customer_id",
+			mcpgo.Description("ID of an existing customer to bill. "+
+				"Either customer_id or customer must be provided"),
+		),
+		mcpgo.WithObject(
+			"customer",
+			mcpgo.Description("Details of the customer to bill, with "+
+				"'name', 'email' and 'contact'. Used when the customer "+
+				"does not exist yet"),
+		),
+		mcpgo.WithString(
+			"description",
+			mcpgo.Description("Brief description of the invoice"),
+		),
+		mcpgo.WithString(
+			"currency",
+			mcpgo.Description("ISO code for the currency of the invoice. "+
+				"Defaults to INR"),
+		),
+		mcpgo.WithString(
+			"receipt",
+			mcpgo.Description("Unique receipt number for internal reference"),
+		),
+		mcpgo.WithNumber(
+			"expire_by",
+			mcpgo.Description("Unix timestamp (in seconds) after which the "+
+				"invoice can no longer be paid"),
+		),
+		mcpgo.WithBoolean(
+			"sms_notify",
+			mcpgo.Description("Whether the customer is notified by SMS "+
+				"when the invoice is issued"),
+		),
+		mcpgo.WithBoolean(
+			"email_notify",
+			mcpgo.Description("Whether the customer is notified by email "+
+				"when the invoice is issued"),
+		),
+		mcpgo.WithBoolean(
+			"partial_payment",
+			mcpgo.Description("Whether the customer can pay the invoice in "+
+				"parts"),
+		),
+		mcpgo.WithBoolean(
+			"draft",
+			mcpgo.Description("Create the invoice as a draft, to be issued "+
+				"later with issue_invoice. Defaults to false, which issues "+
+				"the invoice right away"),
+			mcpgo.DefaultValue(false),
+		),
+		mcpgo.WithObject(
+			"notes",
+			mcpgo.Description("Key-value pairs for additional information "+
+				"(max 15 pairs, 256 chars each)"),
+			mcpgo.MaxProperties(15),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		invoiceData := make(map[string]interface{})
+		options := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredArray(invoiceData, "line_items").
+			ValidateAndAddOptionalString(invoiceData, "customer_id").
+			ValidateAndAddOptionalMap(invoiceData, "customer").
+			ValidateAndAddOptionalString(invoiceData, "description").
+			ValidateAndAddOptionalString(invoiceData, "currency").
+			ValidateAndAddOptionalString(invoiceData, "receipt").
+			ValidateAndAddOptionalInt(invoiceData, "expire_by").
+			ValidateAndAddOptionalBool(invoiceData, "sms_notify").
+			ValidateAndAddOptionalBool(invoiceData, "email_notify").
+			ValidateAndAddOptionalBool(invoiceData, "partial_payment").
+			ValidateAndAddOptionalBool(options, "draft").
+			ValidateAndAddOptionalMap(invoiceData, "notes")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		_, hasCustomerID := invoiceData["customer_id"]
+		_, hasCustomer := invoiceData["customer"]
+		if !hasCustomerID && !hasCustomer {
+			return mcpgo.NewToolResultError(
+				"either customer_id or customer must be provided"), nil
+		}
+
+		invoiceData["type"] = "invoice"
+		if options["draft"] == true {
+			invoiceData["draft"] = "1"
+		}
+
+		invoice, err := client.Invoice.Create(invoiceData, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("creating invoice failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(invoice)
+	}
+
+	return mcpgo.NewTool(
+		"create_invoice",
+		"Create an invoice for a customer with the given line items. "+
+			"Set draft=true to review it before issuing it with issue_invoice.",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema).WithAmounts("line_items.amount")
+}
+
+// FetchInvoice returns a tool that fetches an invoice by its ID
+func FetchInvoice(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		invoiceIDParameter("ID of the invoice to be fetched"),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(fields, "invoice_id")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		invoiceID := fields["invoice_id"].(string)
+		if result := validateID(
+			"invoice_id", invoiceID, invoiceIDPrefix); result != nil {
+			return result, nil
+		}
+
+		invoice, err := client.Invoice.Fetch(invoiceID, nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching invoice failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(invoice)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_invoice",
+		"Fetch the details of an invoice, including its status, line "+
+			"items and payment short URL",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema).
+		WithFieldSelection()
+}
+
+// FetchAllInvoices returns a tool that fetches all invoices with optional
+// filtering
+func FetchAllInvoices(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := append([]mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"customer_id",
+			mcpgo.Description("Optional: Filter by the customer the "+
+				"invoices were issued to"),
+		),
+		mcpgo.WithString(
+			"payment_id",
+			mcpgo.Description("Optional: Filter by the payment made "+
+				"against the invoice"),
+		),
+		mcpgo.WithString(
+			"receipt",
+			mcpgo.Description("Optional: Filter by receipt number"),
+		),
+		mcpgo.WithNumber(
+			"count",
+			mcpgo.Description("Number of invoices to be fetched. "+
+				"Default value is 10. Maximum value is 100"),
+			mcpgo.Min(1),
+			mcpgo.Max(100),
+		),
+		mcpgo.WithNumber(
+			"skip",
+			mcpgo.Description("Number of invoices to be skipped. "+
+				"Default value is 0. This can be used for pagination"),
+			mcpgo.Min(0),
+		),
+	}, collectionParameters()...)
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		queryParams := make(map[string]interface{})
+		pagination := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddOptionalString(queryParams, "customer_id").
+			ValidateAndAddOptionalString(queryParams, "payment_id").
+			ValidateAndAddOptionalString(queryParams, "receipt").
+			ValidateAndAddPagination(queryParams).
+			ValidateAndAddCollectionOptions(pagination)
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		queryParams["type"] = "invoice"
+
+		invoices, err := fetchCollection(queryParams, pagination, client.Invoice.All)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching invoices failed: %s", err.Error())), nil
+		}
+
+		return collectionResult(ctx, invoices, pagination)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_all_invoices",
+		"Fetch all invoices with optional filtering by customer, payment "+
+			"or receipt",
+		parameters,
+		handler,
+	).WithOutputSchema(entitiesOutputSchema).
+		WithFieldSelection()
+}
+
+// IssueInvoice returns a tool that issues a draft invoice
+func IssueInvoice(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		invoiceIDParameter("ID of the draft invoice to be issued"),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(fields, "invoice_id")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		invoiceID := fields["invoice_id"].(string)
+		if result := validateID(
+			"invoice_id", invoiceID, invoiceIDPrefix); result != nil {
+			return result, nil
+		}
+
+		invoice, err := client.Invoice.Issue(invoiceID, nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("issuing invoice failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(invoice)
+	}
+
+	return mcpgo.NewTool(
+		"issue_invoice",
+		"Issue a draft invoice, making it payable and notifying the "+
+			"customer as configured",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema)
+}
+
+// CancelInvoice returns a tool that cancels an issued invoice
+func CancelInvoice(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		invoiceIDParameter("ID of the invoice to be cancelled"),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(fields, "invoice_id")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		invoiceID := fields["invoice_id"].(string)
+		if result := validateID(
+			"invoice_id", invoiceID, invoiceIDPrefix); result != nil {
+			return result, nil
+		}
+
+		invoice, err := client.Invoice.Cancel(invoiceID, nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("cancelling invoice failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(invoice)
+	}
+
+	return mcpgo.NewTool(
+		"cancel_invoice",
+		"Cancel an issued invoice that has not been paid. "+
+			"A cancelled invoice can no longer be paid.",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema)
+}
+
+// NotifyInvoice returns a tool that sends an invoice notification to the
+// customer via SMS or email
+func NotifyInvoice(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		invoiceIDParameter("ID of the invoice for which to send notification"),
+		mcpgo.WithString(
+			"medium",
+			mcpgo.Description("Medium through which to send the "+
+				"notification. Must be either 'sms' or 'email'."),
+			mcpgo.Required(),
+			mcpgo.Enum("sms", "email"),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(fields, "invoice_id").
+			ValidateAndAddRequiredString(fields, "medium")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		invoiceID := fields["invoice_id"].(string)
+		if result := validateID(
+			"invoice_id", invoiceID, invoiceIDPrefix); result != nil {
+			return result, nil
+		}
+
+		medium := fields["medium"].(string)
+		if medium != "sms" && medium != "email" {
+			return mcpgo.NewToolResultError(
+				"medium must be either 'sms' or 'email'"), nil
+		}
+
+		response, err := client.Invoice.Notify(invoiceID, medium, nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("sending notification failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(response)
+	}
+
+	return mcpgo.NewTool(
+		"notify_invoice",
+		"Send or resend the notification for an invoice via SMS or email",
+		parameters,
+		handler,
+	).WithOutputSchema(objectOutputSchema)
+}
+
+// maxInvoicePDFSize caps the size of an invoice PDF downloaded by
+// fetch_invoice_pdf
+const maxInvoicePDFSize = 10 << 20
+
+// paymentInvoiceID returns the ID of the invoice a payment was made for
+func paymentInvoiceID(client *rzpsdk.Client, paymentID string) (string, error) {
+	payment, err := client.Payment.Fetch(paymentID, nil, nil)
+	if err != nil {
+		return "", fmt.Errorf("fetching payment failed: %s", err.Error())
+	}
+
+	invoiceID := stringField(payment, "invoice_id")
+	if invoiceID == "" {
+		return "", fmt.Errorf("payment %s was not made for an invoice, so "+
+			"it has no receipt", paymentID)
+	}
+	return invoiceID, nil
+}
+
+// downloadInvoicePDF downloads the PDF of an invoice from the API
+func downloadInvoicePDF(
+	ctx context.Context,
+	client *rzpsdk.Client,
+	invoiceID string,
+) ([]byte, error) {
+	pdfURL := fmt.Sprintf("%s/%s%s/%s/pdf", client.Request.BaseURL,
+		constants.VERSION_V1, constants.INVOICE_URL, invoiceID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pdfURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(client.Request.Auth.Key, client.Request.Auth.Secret)
+
+	resp, err := client.Request.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected HTTP status: %d", resp.StatusCode)
+	}
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxInvoicePDFSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > maxInvoicePDFSize {
+		return nil, fmt.Errorf("PDF exceeds %d bytes", maxInvoicePDFSize)
+	}
+	if !bytes.HasPrefix(content, []byte("%PDF")) {
+		return nil, fmt.Errorf("response is not a PDF")
+	}
+
+	return content, nil
+}
+
+// invoicePDFResult downloads the PDF of an invoice and returns it as an
+// embedded resource, after the details in response
+func invoicePDFResult(
+	ctx context.Context,
+	client *rzpsdk.Client,
+	invoiceID string,
+	response map[string]interface{},
+) (*mcpgo.ToolResult, error) {
+	pdf, err := downloadInvoicePDF(ctx, client, invoiceID)
+	if err != nil {
+		return mcpgo.NewToolResultError(
+			fmt.Sprintf("downloading invoice PDF failed: %s", err.Error())), nil
+	}
+	response["mime_type"] = "application/pdf"
+	response["size"] = len(pdf)
+
+	text, err := json.Marshal(response)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcpgo.NewToolResultContent(string(text),
+		mcpgo.NewBlobResource(
+			fmt.Sprintf("razorpay://invoices/%s.pdf", invoiceID),
+			"application/pdf", pdf)), nil
+}
+
+// FetchInvoicePdf returns a tool that returns the PDF of an invoice, which
+// is also the receipt of the payment made for it
+func FetchInvoicePdf(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"invoice_id",
+			mcpgo.Description("ID of the invoice, starting with 'inv_'. "+
+				"Pass either invoice_id or payment_id"),
+			mcpgo.Pattern(mcpgo.IDPattern(invoiceIDPrefix)),
+		),
+		mcpgo.WithString(
+			"payment_id",
+			mcpgo.Description("ID of a payment made for an invoice, starting "+
+				"with 'pay_', to fetch its receipt"),
+		),
+		mcpgo.WithString(
+			"format",
+			mcpgo.Description("'url' returns the link to the hosted invoice, "+
+				"which the customer can open and download; 'pdf' returns the "+
+				"PDF itself as an embedded resource (default: url)"),
+			mcpgo.Enum("url", "pdf"),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		params := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddOptionalString(params, "invoice_id").
+			ValidateAndAddOptionalString(params, "payment_id").
+			ValidateAndAddOptionalString(params, "format")
+
+		invoiceID, _ := params["invoice_id"].(string)
+		paymentID, _ := params["payment_id"].(string)
+		if (invoiceID == "") == (paymentID == "") {
+			validator.addError(fmt.Errorf(
+				"exactly one of invoice_id or payment_id is required"))
+		}
+		format, _ := params["format"].(string)
+		if format == "" {
+			format = "url"
+		}
+		if format != "url" && format != "pdf" {
+			validator.addError(fmt.Errorf(
+				"format must be either 'url' or 'pdf'"))
+		}
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		if paymentID != "" {
+			invoiceID, err = paymentInvoiceID(client, paymentID)
+			if err != nil {
+				return mcpgo.NewToolResultError(err.Error()), nil
+			}
+		}
+		if result := validateID(
+			"invoice_id", invoiceID, invoiceIDPrefix); result != nil {
+			return result, nil
+		}
+
+		invoice, err := client.Invoice.Fetch(invoiceID, nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching invoice failed: %s", err.Error())), nil
+		}
+
+		response := map[string]interface{}{
+			"invoice_id": invoiceID,
+			"status":     invoice["status"],
+			"receipt":    invoice["receipt"],
+			"url":        invoice["short_url"],
+		}
+		if paymentID != "" {
+			response["payment_id"] = paymentID
+		}
+
+		if format == "url" {
+			return mcpgo.NewToolResultJSON(response)
+		}
+
+		return invoicePDFResult(ctx, client, invoiceID, response)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_invoice_pdf",
+		"Fetch the PDF of an invoice, or the receipt of a payment made for "+
+			"an invoice, to hand to the customer. Returns the link to the "+
+			"hosted invoice, or with format 'pdf' the PDF file itself",
+		parameters,
+		handler,
+	).WithOutputSchema(objectOutputSchema)
+}
@@ -0,0 +1,533 @@
+package razorpay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/razorpay/razorpay-go/constants"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay/mock"
+)
+
+var (
+	invoicesPath = fmt.Sprintf(
+		"/%s%s",
+		constants.VERSION_V1,
+		constants.INVOICE_URL,
+	)
+	invoicePath = invoicesPath + "/inv_DAweOiQ7amIUVd"
+)
+
+var invoiceResp = map[string]interface{}{
+	"id":          "inv_DAweOiQ7amIUVd",
+	"entity":      "invoice",
+	"type":        "invoice",
+	"status":      "issued",
+	"customer_id": "cust_E7q0trFqXgExmT",
+	"amount":      float64(100000),
+	"currency":    "INR",
+	"short_url":   "https://rzp.io/i/2wxV8Xs",
+}
+
+var invoiceErrorResp = map[string]interface{}{
+	"error": map[string]interface{}{
+		"code":        "BAD_REQUEST_ERROR",
+		"description": "The id provided does not exist",
+	},
+}
+
+func Test_CreateInvoice(t *testing.T) {
+	lineItems := []interface{}{
+		map[string]interface{}{
+			"name":     "Master Cloud Computing in 30 Days",
+			"amount":   float64(100000),
+			"quantity": float64(1),
+		},
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful invoice creation",
+			Request: map[string]interface{}{
+				"customer_id": "cust_E7q0trFqXgExmT",
+				"line_items":  lineItems,
+				"currency":    "INR",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     invoicesPath,
+						Method:   "POST",
+						Response: invoiceResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: invoiceResp,
+		},
+		{
+			Name: "missing customer",
+			Request: map[string]interface{}{
+				"line_items": lineItems,
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "either customer_id or customer must be provided",
+		},
+		{
+			Name: "missing line_items",
+			Request: map[string]interface{}{
+				"customer_id": "cust_E7q0trFqXgExmT",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: line_items",
+		},
+		{
+			Name: "invoice creation fails",
+			Request: map[string]interface{}{
+				"customer_id": "cust_E7q0trFqXgExmT",
+				"line_items":  lineItems,
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     invoicesPath,
+						Method:   "POST",
+						Response: invoiceErrorResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "creating invoice failed: " +
+				"The id provided does not exist",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, CreateInvoice, "Invoice")
+		})
+	}
+
+	t.Run("draft invoice is sent with type and draft flag", func(t *testing.T) {
+		var body map[string]interface{}
+
+		mockClient := func() (*http.Client, *httptest.Server) {
+			server := httptest.NewServer(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					_ = json.NewDecoder(r.Body).Decode(&body)
+					_ = json.NewEncoder(w).Encode(invoiceResp)
+				}))
+			return server.Client(), server
+		}
+
+		client, server := newMockRzpClient(mockClient)
+		defer server.Close()
+
+		tool := CreateInvoice(CreateTestObservability(), client)
+		result, err := tool.GetHandler()(context.Background(),
+			createMCPRequest(map[string]interface{}{
+				"customer": map[string]interface{}{
+					"name":  "Gaurav Kumar",
+					"email": "gaurav.kumar@example.com",
+				},
+				"line_items": lineItems,
+				"draft":      true,
+			}))
+		require.NoError(t, err)
+		require.False(t, result.IsError, result.Text)
+
+		assert.Equal(t, "invoice", body["type"])
+		assert.Equal(t, "1", body["draft"])
+		assert.Equal(t, lineItems, body["line_items"])
+	})
+}
+
+func Test_FetchInvoice(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful invoice fetch",
+			Request: map[string]interface{}{
+				"invoice_id": "inv_DAweOiQ7amIUVd",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     invoicePath,
+						Method:   "GET",
+						Response: invoiceResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: invoiceResp,
+		},
+		{
+			Name: "invoice not found",
+			Request: map[string]interface{}{
+				"invoice_id": "inv_DAweOiQ7amIUVd",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     invoicePath,
+						Method:   "GET",
+						Response: invoiceErrorResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "fetching invoice failed: " +
+				"The id provided does not exist",
+		},
+		{
+			Name: "malformed invoice_id",
+			Request: map[string]interface{}{
+				"invoice_id": "inv_123/../../payments",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid invoice_id: inv_123/../../payments",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchInvoice, "Invoice")
+		})
+	}
+}
+
+func Test_FetchAllInvoices(t *testing.T) {
+	invoicesResp := map[string]interface{}{
+		"entity": "collection",
+		"count":  float64(1),
+		"items":  []interface{}{invoiceResp},
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful invoices fetch",
+			Request: map[string]interface{}{
+				"customer_id": "cust_E7q0trFqXgExmT",
+				"count":       float64(10),
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     invoicesPath,
+						Method:   "GET",
+						Response: invoicesResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: invoicesResp,
+		},
+		{
+			Name: "invalid receipt type",
+			Request: map[string]interface{}{
+				"receipt": 123,
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid parameter type: receipt",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchAllInvoices, "Invoices")
+		})
+	}
+}
+
+func Test_IssueInvoice(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful invoice issue",
+			Request: map[string]interface{}{
+				"invoice_id": "inv_DAweOiQ7amIUVd",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     invoicePath + "/issue",
+						Method:   "POST",
+						Response: invoiceResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: invoiceResp,
+		},
+		{
+			Name:           "missing invoice_id",
+			Request:        map[string]interface{}{},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: invoice_id",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, IssueInvoice, "Invoice")
+		})
+	}
+}
+
+func Test_CancelInvoice(t *testing.T) {
+	cancelledResp := map[string]interface{}{
+		"id":     "inv_DAweOiQ7amIUVd",
+		"entity": "invoice",
+		"status": "cancelled",
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful invoice cancellation",
+			Request: map[string]interface{}{
+				"invoice_id": "inv_DAweOiQ7amIUVd",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     invoicePath + "/cancel",
+						Method:   "POST",
+						Response: cancelledResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: cancelledResp,
+		},
+		{
+			Name: "invoice cancellation fails",
+			Request: map[string]interface{}{
+				"invoice_id": "inv_DAweOiQ7amIUVd",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     invoicePath + "/cancel",
+						Method:   "POST",
+						Response: invoiceErrorResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "cancelling invoice failed: " +
+				"The id provided does not exist",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, CancelInvoice, "Invoice")
+		})
+	}
+}
+
+func Test_NotifyInvoice(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful notification",
+			Request: map[string]interface{}{
+				"invoice_id": "inv_DAweOiQ7amIUVd",
+				"medium":     "sms",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:   invoicePath + "/notify_by/sms",
+						Method: "POST",
+						Response: map[string]interface{}{
+							"success": true,
+						},
+					},
+				)
+			},
+			ExpectError: false,
+			ExpectedResult: map[string]interface{}{
+				"success": true,
+			},
+		},
+		{
+			Name: "invalid medium",
+			Request: map[string]interface{}{
+				"invoice_id": "inv_DAweOiQ7amIUVd",
+				"medium":     "whatsapp",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "medium must be either 'sms' or 'email'",
+		},
+		{
+			Name: "missing medium",
+			Request: map[string]interface{}{
+				"invoice_id": "inv_DAweOiQ7amIUVd",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: medium",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, NotifyInvoice, "Invoice")
+		})
+	}
+}
+
+func Test_FetchInvoicePdf(t *testing.T) {
+	paymentPath := fmt.Sprintf("/%s%s/%s", constants.VERSION_V1,
+		constants.PAYMENT_URL, "pay_DAweOiQ7amIUVe")
+	pdf := "%PDF-1.4 invoice"
+
+	invoiceClient := func(
+		endpoints ...mock.Endpoint,
+	) func() (*http.Client, *httptest.Server) {
+		return func() (*http.Client, *httptest.Server) {
+			return mock.NewHTTPClient(append([]mock.Endpoint{
+				{Path: invoicePath, Method: "GET", Response: invoiceResp},
+				{Path: invoicePath + "/pdf", Method: "GET", Response: pdf},
+			}, endpoints...)...)
+		}
+	}
+
+	invoiceURL := map[string]interface{}{
+		"invoice_id": "inv_DAweOiQ7amIUVd",
+		"status":     "issued",
+		"receipt":    nil,
+		"url":        "https://rzp.io/i/2wxV8Xs",
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "invoice link",
+			Request: map[string]interface{}{
+				"invoice_id": "inv_DAweOiQ7amIUVd",
+			},
+			MockHttpClient: invoiceClient(),
+			ExpectError:    false,
+			ExpectedResult: invoiceURL,
+		},
+		{
+			Name: "receipt of a payment",
+			Request: map[string]interface{}{
+				"payment_id": "pay_DAweOiQ7amIUVe",
+			},
+			MockHttpClient: invoiceClient(mock.Endpoint{
+				Path:   paymentPath,
+				Method: "GET",
+				Response: map[string]interface{}{
+					"id":         "pay_DAweOiQ7amIUVe",
+					"invoice_id": "inv_DAweOiQ7amIUVd",
+				},
+			}),
+			ExpectError: false,
+			ExpectedResult: map[string]interface{}{
+				"invoice_id": "inv_DAweOiQ7amIUVd",
+				"payment_id": "pay_DAweOiQ7amIUVe",
+				"status":     "issued",
+				"receipt":    nil,
+				"url":        "https://rzp.io/i/2wxV8Xs",
+			},
+		},
+		{
+			Name: "payment without invoice",
+			Request: map[string]interface{}{
+				"payment_id": "pay_DAweOiQ7amIUVe",
+			},
+			MockHttpClient: invoiceClient(mock.Endpoint{
+				Path:     paymentPath,
+				Method:   "GET",
+				Response: map[string]interface{}{"id": "pay_DAweOiQ7amIUVe"},
+			}),
+			ExpectError: true,
+			ExpectedErrMsg: "payment pay_DAweOiQ7amIUVe was not made for an " +
+				"invoice, so it has no receipt",
+		},
+		{
+			Name: "both invoice and payment",
+			Request: map[string]interface{}{
+				"invoice_id": "inv_DAweOiQ7amIUVd",
+				"payment_id": "pay_DAweOiQ7amIUVe",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "exactly one of invoice_id or payment_id is required",
+		},
+		{
+			Name: "invalid format",
+			Request: map[string]interface{}{
+				"invoice_id": "inv_DAweOiQ7amIUVd",
+				"format":     "html",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "format must be either 'url' or 'pdf'",
+		},
+		{
+			Name: "response is not a PDF",
+			Request: map[string]interface{}{
+				"invoice_id": "inv_DAweOiQ7amIUVd",
+				"format":     "pdf",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     invoicePath,
+						Method:   "GET",
+						Response: invoiceResp,
+					},
+					mock.Endpoint{
+						Path:     invoicePath + "/pdf",
+						Method:   "GET",
+						Response: "<html></html>",
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "downloading invoice PDF failed: response is not " +
+				"a PDF",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchInvoicePdf, "Invoice PDF")
+		})
+	}
+
+	t.Run("returns the PDF as an embedded resource", func(t *testing.T) {
+		client, server := newMockRzpClient(invoiceClient())
+		defer server.Close()
+
+		tool := FetchInvoicePdf(CreateTestObservability(), client)
+		result, err := tool.GetHandler()(context.Background(),
+			createMCPRequest(map[string]interface{}{
+				"invoice_id": "inv_DAweOiQ7amIUVd",
+				"format":     "pdf",
+			}))
+		require.NoError(t, err)
+		require.False(t, result.IsError, result.Text)
+
+		assert.Contains(t, result.Text, `"mime_type":"application/pdf"`)
+		assert.Equal(t, []interface{}{
+			mcpgo.NewBlobResource("razorpay://invoices/inv_DAweOiQ7amIUVd.pdf",
+				"application/pdf", []byte(pdf)),
+		}, result.Content)
+	})
+}
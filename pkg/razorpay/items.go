@@ -0,0 +1,366 @@
+package razorpay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+)
+
+// itemIDParameter returns the item_id parameter shared by the tools that
+// operate on a single item
+func itemIDParameter(description string) mcpgo.ToolParameter {
+	return mcpgo.WithString(
+		"item_id",
+		mcpgo.Description(description+" (ID should have an item_ prefix)."),
+		mcpgo.Required(),
+		mcpgo.Pattern(mcpgo.IDPattern(itemIDPrefix)),
+	)
+}
+
+// CreateItem returns a tool that creates an item in the catalog
+func CreateItem(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"name",
+			mcpgo.Description("Name of the item"),
+			mcpgo.Required(),
+		),
+		mcpgo.WithNumber(
+			"amount",
+			mcpgo.Description("Price of the item in the smallest currency "+
+				"unit"),
+			mcpgo.Required(),
+			mcpgo.Min(0),
+		),
+		mcpgo.WithString(
+			"currency",
+			mcpgo.Description("ISO code for the currency of the item"),
+			mcpgo.Required(),
+		),
+		mcpgo.WithString(
+			"description",
+			mcpgo.Description("Description of the item"),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		itemData := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(itemData, "name").
+			ValidateAndAddRequiredInt(itemData, "amount").
+			ValidateAndAddRequiredString(itemData, "currency").
+			ValidateAndAddOptionalString(itemData, "description")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		item, err := client.Item.Create(itemData, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("creating item failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(item)
+	}
+
+	return mcpgo.NewTool(
+		"create_item",
+		"Create a catalog item that can be reused as a line item in "+
+			"invoices and payment links",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema).WithAmounts("amount")
+}
+
+// FetchItem returns a tool that fetches an item by its ID
+func FetchItem(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		itemIDParameter("ID of the item to be fetched"),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(fields, "item_id")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		itemID := fields["item_id"].(string)
+		if result := validateID("item_id", itemID, itemIDPrefix); result != nil {
+			return result, nil
+		}
+
+		item, err := client.Item.Fetch(itemID, nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching item failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(item)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_item",
+		"Fetch the details of a catalog item",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema).
+		WithFieldSelection()
+}
+
+// FetchAllItems returns a tool that fetches all items in the catalog
+func FetchAllItems(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := append([]mcpgo.ToolParameter{
+		mcpgo.WithBoolean(
+			"active",
+			mcpgo.Description("Optional: Only fetch active (true) or "+
+				"inactive (false) items"),
+		),
+		mcpgo.WithNumber(
+			"from",
+			mcpgo.Description("Unix timestamp (in seconds) from when "+
+				"items are to be fetched"),
+			mcpgo.Min(0),
+		),
+		mcpgo.WithNumber(
+			"to",
+			mcpgo.Description("Unix timestamp (in seconds) up till when "+
+				"items are to be fetched"),
+			mcpgo.Min(0),
+		),
+		mcpgo.WithNumber(
+			"count",
+			mcpgo.Description("Number of items to be fetched. "+
+				"Default value is 10. Maximum value is 100"),
+			mcpgo.Min(1),
+			mcpgo.Max(100),
+		),
+		mcpgo.WithNumber(
+			"skip",
+			mcpgo.Description("Number of items to be skipped. "+
+				"Default value is 0. This can be used for pagination"),
+			mcpgo.Min(0),
+		),
+	}, collectionParameters()...)
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		queryParams := make(map[string]interface{})
+		pagination := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddOptionalBool(queryParams, "active").
+			ValidateAndAddOptionalInt(queryParams, "from").
+			ValidateAndAddOptionalInt(queryParams, "to").
+			ValidateAndAddPagination(queryParams).
+			ValidateAndAddCollectionOptions(pagination)
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		// The API expects active as 1 or 0
+		if active, ok := queryParams["active"].(bool); ok {
+			if active {
+				queryParams["active"] = 1
+			} else {
+				queryParams["active"] = 0
+			}
+		}
+
+		items, err := fetchCollection(queryParams, pagination, client.Item.All)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching items failed: %s", err.Error())), nil
+		}
+
+		return collectionResult(ctx, items, pagination)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_all_items",
+		"Fetch all catalog items with optional filtering by status and "+
+			"creation date",
+		parameters,
+		handler,
+	).WithOutputSchema(entitiesOutputSchema).
+		WithFieldSelection()
+}
+
+// UpdateItem returns a tool that updates an item in the catalog
+func UpdateItem(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		itemIDParameter("ID of the item to be updated"),
+		mcpgo.WithString(
+			"name",
+			mcpgo.Description("New name of the item"),
+		),
+		mcpgo.WithNumber(
+			"amount",
+			mcpgo.Description("New price of the item in the smallest "+
+				"currency unit"),
+			mcpgo.Min(0),
+		),
+		mcpgo.WithString(
+			"currency",
+			mcpgo.Description("New ISO code for the currency of the item"),
+		),
+		mcpgo.WithString(
+			"description",
+			mcpgo.Description("New description of the item"),
+		),
+		mcpgo.WithBoolean(
+			"active",
+			mcpgo.Description("Whether the item can be used in new "+
+				"invoices and payment links"),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+		itemData := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(fields, "item_id").
+			ValidateAndAddOptionalString(itemData, "name").
+			ValidateAndAddOptionalInt(itemData, "amount").
+			ValidateAndAddOptionalString(itemData, "currency").
+			ValidateAndAddOptionalString(itemData, "description").
+			ValidateAndAddOptionalBool(itemData, "active")
+
+		if !validator.HasErrors() && len(itemData) == 0 {
+			validator.addError(errors.New("no fields to update"))
+		}
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		itemID := fields["item_id"].(string)
+		if result := validateID("item_id", itemID, itemIDPrefix); result != nil {
+			return result, nil
+		}
+
+		item, err := client.Item.Update(itemID, itemData, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("updating item failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(item)
+	}
+
+	return mcpgo.NewTool(
+		"update_item",
+		"Update the name, price, currency, description or status of a "+
+			"catalog item",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema).WithAmounts("amount")
+}
+
+// DeleteItem returns a tool that deletes an item from the catalog
+func DeleteItem(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		itemIDParameter("ID of the item to be deleted"),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(fields, "item_id")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		itemID := fields["item_id"].(string)
+		if result := validateID("item_id", itemID, itemIDPrefix); result != nil {
+			return result, nil
+		}
+
+		_, err = client.Item.Delete(itemID, nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("deleting item failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(map[string]interface{}{
+			"item_id": itemID,
+			"deleted": true,
+		})
+	}
+
+	return mcpgo.NewTool(
+		"delete_item",
+		"Delete a catalog item. Invoices and payment links that already "+
+			"use the item are not affected.",
+		parameters,
+		handler,
+	).WithOutputSchema(objectOutputSchema)
+}
@@ -0,0 +1,285 @@
+package razorpay
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/razorpay/razorpay-go/constants"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay/mock"
+)
+
+var (
+	itemsPath = fmt.Sprintf(
+		"/%s%s",
+		constants.VERSION_V1,
+		constants.ITEM_URL,
+	)
+	itemPath = itemsPath + "/item_7Oxp4hmm6T4SCn"
+)
+
+var itemResp = map[string]interface{}{
+	"id":          "item_7Oxp4hmm6T4SCn",
+	"active":      true,
+	"name":        "Book / English August",
+	"description": "An indian story, Booker prize winner.",
+	"amount":      float64(20000),
+	"currency":    "INR",
+}
+
+var itemErrorResp = map[string]interface{}{
+	"error": map[string]interface{}{
+		"code":        "BAD_REQUEST_ERROR",
+		"description": "The id provided does not exist",
+	},
+}
+
+func Test_CreateItem(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful item creation",
+			Request: map[string]interface{}{
+				"name":        "Book / English August",
+				"description": "An indian story, Booker prize winner.",
+				"amount":      20000,
+				"currency":    "INR",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     itemsPath,
+						Method:   "POST",
+						Response: itemResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: itemResp,
+		},
+		{
+			Name: "missing amount",
+			Request: map[string]interface{}{
+				"name":     "Book / English August",
+				"currency": "INR",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: amount",
+		},
+		{
+			Name: "item creation fails",
+			Request: map[string]interface{}{
+				"name":     "Book / English August",
+				"amount":   20000,
+				"currency": "INR",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     itemsPath,
+						Method:   "POST",
+						Response: itemErrorResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "creating item failed: " +
+				"The id provided does not exist",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, CreateItem, "Item")
+		})
+	}
+}
+
+func Test_FetchItem(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful item fetch",
+			Request: map[string]interface{}{
+				"item_id": "item_7Oxp4hmm6T4SCn",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     itemPath,
+						Method:   "GET",
+						Response: itemResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: itemResp,
+		},
+		{
+			Name: "malformed item_id",
+			Request: map[string]interface{}{
+				"item_id": "item_1/../../payments",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid item_id: item_1/../../payments",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchItem, "Item")
+		})
+	}
+}
+
+func Test_FetchAllItems(t *testing.T) {
+	itemsResp := map[string]interface{}{
+		"entity": "collection",
+		"count":  float64(1),
+		"items":  []interface{}{itemResp},
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful items fetch",
+			Request: map[string]interface{}{
+				"active": true,
+				"count":  float64(10),
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     itemsPath,
+						Method:   "GET",
+						Response: itemsResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: itemsResp,
+		},
+		{
+			Name: "invalid active type",
+			Request: map[string]interface{}{
+				"active": "yes",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid parameter type: active",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchAllItems, "Items")
+		})
+	}
+}
+
+func Test_UpdateItem(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful item update",
+			Request: map[string]interface{}{
+				"item_id": "item_7Oxp4hmm6T4SCn",
+				"amount":  20000,
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     itemPath,
+						Method:   "PATCH",
+						Response: itemResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: itemResp,
+		},
+		{
+			Name: "no fields to update",
+			Request: map[string]interface{}{
+				"item_id": "item_7Oxp4hmm6T4SCn",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "no fields to update",
+		},
+		{
+			Name: "item update fails",
+			Request: map[string]interface{}{
+				"item_id": "item_7Oxp4hmm6T4SCn",
+				"active":  false,
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     itemPath,
+						Method:   "PATCH",
+						Response: itemErrorResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "updating item failed: " +
+				"The id provided does not exist",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, UpdateItem, "Item")
+		})
+	}
+}
+
+func Test_DeleteItem(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful item deletion",
+			Request: map[string]interface{}{
+				"item_id": "item_7Oxp4hmm6T4SCn",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     itemPath,
+						Method:   "DELETE",
+						Response: "[]",
+					},
+				)
+			},
+			ExpectError: false,
+			ExpectedResult: map[string]interface{}{
+				"item_id": "item_7Oxp4hmm6T4SCn",
+				"deleted": true,
+			},
+		},
+		{
+			Name: "item deletion fails",
+			Request: map[string]interface{}{
+				"item_id": "item_7Oxp4hmm6T4SCn",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     itemPath,
+						Method:   "DELETE",
+						Response: itemErrorResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "deleting item failed: " +
+				"The id provided does not exist",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, DeleteItem, "Item")
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package razorpay
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
+)
+
+// defaultLocale is the language tools are written in
+const defaultLocale = "en"
+
+// localeFiles holds a catalog of translations per locale, named after the
+// locale, such as locales/hi.json
+//
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// catalog holds the translations of a locale. Entries missing from the
+// catalog fall back to English.
+type catalog struct {
+	// Tools holds tool descriptions, keyed by tool name
+	Tools map[string]string `json:"tools"`
+	// Messages holds guidance returned by tools, keyed by the English
+	// message. Format verbs such as %v must be kept in the translation.
+	Messages map[string]string `json:"messages"`
+}
+
+// loadCatalogs parses the embedded catalogs once, keyed by locale
+var loadCatalogs = sync.OnceValues(func() (map[string]catalog, error) {
+	entries, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		return nil, err
+	}
+
+	catalogs := make(map[string]catalog, len(entries))
+	for _, entry := range entries {
+		data, err := localeFiles.ReadFile(path.Join("locales", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var c catalog
+		if err := json.Unmarshal(data, &c); err != nil {
+			return nil, fmt.Errorf("parsing catalog %s: %w", entry.Name(), err)
+		}
+		catalogs[strings.TrimSuffix(entry.Name(), ".json")] = c
+	}
+	return catalogs, nil
+})
+
+// supportedLocales returns the locales tools can be served in, in sorted
+// order
+func supportedLocales() []string {
+	catalogs, _ := loadCatalogs()
+	locales := append(sortedKeys(catalogs), defaultLocale)
+	sort.Strings(locales)
+	return locales
+}
+
+// localeCatalog returns the catalog of a locale. The default locale has an
+// empty catalog.
+func localeCatalog(locale string) (catalog, error) {
+	if locale == "" || locale == defaultLocale {
+		return catalog{}, nil
+	}
+
+	catalogs, err := loadCatalogs()
+	if err != nil {
+		return catalog{}, err
+	}
+	c, ok := catalogs[locale]
+	if !ok {
+		return catalog{}, fmt.Errorf("locale %q is not supported, "+
+			"supported locales are %s", locale,
+			strings.Join(supportedLocales(), ", "))
+	}
+	return c, nil
+}
+
+// localize returns the translation of a guidance message in the locale of
+// the tool call, or the message itself if it has no translation
+func localize(ctx context.Context, message string) string {
+	c, err := localeCatalog(contextkey.LocaleFromContext(ctx))
+	if err != nil {
+		return message
+	}
+	if translation, ok := c.Messages[message]; ok {
+		return translation
+	}
+	return message
+}
@@ -0,0 +1,82 @@
+package razorpay
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
+)
+
+func TestLocalize(t *testing.T) {
+	message := "OTP verified successfully."
+	hindi := contextkey.WithLocale(context.Background(), "hi")
+
+	t.Run("keeps English without a locale", func(t *testing.T) {
+		assert.Equal(t, message, localize(context.Background(), message))
+	})
+
+	t.Run("keeps English for the default locale", func(t *testing.T) {
+		ctx := contextkey.WithLocale(context.Background(), "en")
+		assert.Equal(t, message, localize(ctx, message))
+	})
+
+	t.Run("translates messages of the catalog", func(t *testing.T) {
+		assert.Equal(t, "OTP सफलतापूर्वक सत्यापित हुआ।",
+			localize(hindi, message))
+	})
+
+	t.Run("falls back to English for missing messages", func(t *testing.T) {
+		assert.Equal(t, "Not translated", localize(hindi, "Not translated"))
+	})
+
+	t.Run("falls back to English for unknown locales", func(t *testing.T) {
+		ctx := contextkey.WithLocale(context.Background(), "xx")
+		assert.Equal(t, message, localize(ctx, message))
+	})
+
+	t.Run("translates next actions", func(t *testing.T) {
+		response, _ := buildInitiatePaymentResponse(hindi, nil, "pay_123",
+			[]map[string]interface{}{
+				{"action": "otp_generate", "url": ""},
+			})
+
+		assert.Equal(t, "भुगतान शुरू किया गया। OTP प्रमाणीकरण उपलब्ध है। "+
+			"प्रमाणीकरण के लिए ग्राहक को मिला OTP 'submit_otp' टूल से जमा करें।",
+			response["message"])
+		assert.Equal(t, "OTP दोबारा बनाने के लिए 'resend_otp' या OTP दर्ज "+
+			"करने के लिए 'submit_otp' का उपयोग करें।", response["next_step"])
+	})
+}
+
+func TestLocaleCatalogs(t *testing.T) {
+	catalogs, err := loadCatalogs()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"en", "hi"}, supportedLocales())
+
+	obs := CreateTestObservability()
+	client := rzpsdk.NewClient("test-key", "test-secret")
+	toolsets, err := NewToolSets(obs, client, []string{}, false)
+	require.NoError(t, err)
+
+	// Format verbs and quoted tool names must survive translation
+	placeholders := regexp.MustCompile(`%[a-z]|'[a-z_]+'`)
+	for locale, c := range catalogs {
+		for name, description := range c.Tools {
+			assert.True(t, toolsets.HasTool(name),
+				"%s catalog describes unknown tool %s", locale, name)
+			assert.NotEmpty(t, description)
+		}
+		for message, translation := range c.Messages {
+			assert.ElementsMatch(t,
+				placeholders.FindAllString(message, -1),
+				placeholders.FindAllString(translation, -1),
+				"%s translation of %q", locale, message)
+		}
+	}
+}
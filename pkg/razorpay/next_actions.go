@@ -0,0 +1,113 @@
+package razorpay
+
+import "context"
+
+// Action types used in NextAction
+const (
+	nextActionCallTool  = "call_tool"
+	nextActionRedirect  = "redirect"
+	nextActionUPIIntent = "upi_intent"
+	nextActionOTPSubmit = "otp_submit"
+)
+
+// otpPlaceholder is the value clients must replace with the OTP entered
+// by the customer before calling submit_otp
+const otpPlaceholder = "{OTP_CODE_FROM_USER}"
+
+// NextAction describes a follow-up step a client can take after an
+// interactive payment tool call. Actions of type call_tool carry the tool
+// name and its params, all other actions carry the URL to open.
+type NextAction struct {
+	Action      string                 `json:"action"`
+	Description string                 `json:"description"`
+	Tool        string                 `json:"tool,omitempty"`
+	Params      map[string]interface{} `json:"params,omitempty"`
+	URL         string                 `json:"url,omitempty"`
+}
+
+// toolNextAction returns a NextAction suggesting a call to the given tool
+func toolNextAction(
+	tool string,
+	description string,
+	params map[string]interface{},
+) NextAction {
+	return NextAction{
+		Action:      nextActionCallTool,
+		Description: description,
+		Tool:        tool,
+		Params:      params,
+	}
+}
+
+// urlNextAction returns a NextAction pointing the customer to a URL
+func urlNextAction(action, description, url string) NextAction {
+	return NextAction{
+		Action:      action,
+		Description: description,
+		URL:         url,
+	}
+}
+
+// otpNextActions returns the actions available while a payment awaits OTP
+// authentication: regenerating the OTP and submitting it
+func otpNextActions(
+	ctx context.Context,
+	paymentID string,
+	resendDescription string,
+) []NextAction {
+	return []NextAction{
+		toolNextAction("resend_otp", resendDescription,
+			map[string]interface{}{
+				"payment_id": paymentID,
+			}),
+		submitOtpNextAction(ctx, paymentID, ""),
+	}
+}
+
+// submitOtpNextAction returns the action to submit the customer's OTP,
+// passing on the otp_submit URL of the payment if it is known
+func submitOtpNextAction(
+	ctx context.Context,
+	paymentID string,
+	otpSubmitURL string,
+) NextAction {
+	params := map[string]interface{}{
+		"payment_id": paymentID,
+		"otp_string": otpPlaceholder,
+	}
+	if otpSubmitURL != "" {
+		params["otp_submit_url"] = otpSubmitURL
+	}
+	return toolNextAction("submit_otp",
+		localize(ctx, "Use 'submit_otp' tool with the OTP code received "+
+			"from user to complete payment authentication."),
+		params)
+}
+
+// fetchPaymentNextAction returns the action to check a payment's status
+func fetchPaymentNextAction(paymentID, description string) NextAction {
+	return toolNextAction("fetch_payment", description,
+		map[string]interface{}{
+			"payment_id": paymentID,
+		})
+}
+
+// setNextActions adds the actions to the response as next_actions. The
+// first tool action is also exposed through the next_step, next_tool and
+// next_tool_params fields that existing clients rely on.
+func setNextActions(response map[string]interface{}, actions ...NextAction) {
+	if len(actions) == 0 {
+		return
+	}
+
+	response["next_actions"] = actions
+	for _, action := range actions {
+		if action.Action != nextActionCallTool {
+			continue
+		}
+		response["next_step"] = action.Description
+		response["next_tool"] = action.Tool
+		response["next_tool_params"] = action.Params
+		return
+	}
+}
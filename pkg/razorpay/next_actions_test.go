@@ -0,0 +1,214 @@
+package razorpay
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Descriptions of the next actions returned by the payment tools
+const (
+	resendOtpStep = "Use 'resend_otp' to regenerate OTP or " +
+		"'submit_otp' to proceed to enter OTP."
+	resendOtpFallbackStep = "Use 'resend_otp' to regenerate OTP or " +
+		"'submit_otp' to proceed to enter OTP if " +
+		"OTP authentication is required."
+	submitOtpStep = "Use 'submit_otp' tool with the OTP code received " +
+		"from user to complete payment authentication."
+	otpSubmitStep = "URL at which the OTP can be submitted directly."
+	redirectStep  = "Redirect the customer to this URL to complete " +
+		"authentication."
+	upiIntentStep       = "Open this URL in a UPI app to complete the payment."
+	upiFetchPaymentStep = "Use 'fetch_payment' to check the payment status " +
+		"once the customer approves the payment in their UPI app."
+	submitOtpFetchPaymentStep = "Use 'fetch_payment' to check the final " +
+		"status of the payment."
+)
+
+// expectedToolAction returns the JSON form of a call_tool next action
+func expectedToolAction(
+	tool string,
+	description string,
+	params map[string]interface{},
+) map[string]interface{} {
+	return map[string]interface{}{
+		"action":      "call_tool",
+		"description": description,
+		"tool":        tool,
+		"params":      params,
+	}
+}
+
+// expectedURLAction returns the JSON form of a next action with a URL
+func expectedURLAction(action, description, url string) map[string]interface{} {
+	return map[string]interface{}{
+		"action":      action,
+		"description": description,
+		"url":         url,
+	}
+}
+
+// expectedSubmitOtpAction returns the JSON form of the submit_otp action
+func expectedSubmitOtpAction(paymentID string) map[string]interface{} {
+	return expectedToolAction("submit_otp", submitOtpStep,
+		map[string]interface{}{
+			"payment_id": paymentID,
+			"otp_string": "{OTP_CODE_FROM_USER}",
+		})
+}
+
+// expectedSubmitOtpURLAction returns the JSON form of the submit_otp action
+// passing on the otp_submit URL
+func expectedSubmitOtpURLAction(
+	paymentID string,
+	otpSubmitURL string,
+) map[string]interface{} {
+	return expectedToolAction("submit_otp", submitOtpStep,
+		map[string]interface{}{
+			"payment_id":     paymentID,
+			"otp_string":     "{OTP_CODE_FROM_USER}",
+			"otp_submit_url": otpSubmitURL,
+		})
+}
+
+// expectedFetchPaymentAction returns the JSON form of the fetch_payment
+// action
+func expectedFetchPaymentAction(
+	paymentID string,
+	description string,
+) map[string]interface{} {
+	return expectedToolAction("fetch_payment", description,
+		map[string]interface{}{
+			"payment_id": paymentID,
+		})
+}
+
+// expectedOtpNextActions returns the JSON form of the actions available
+// while a payment awaits OTP authentication
+func expectedOtpNextActions(paymentID, resendStep string) []interface{} {
+	return []interface{}{
+		expectedToolAction("resend_otp", resendStep,
+			map[string]interface{}{
+				"payment_id": paymentID,
+			}),
+		expectedSubmitOtpAction(paymentID),
+	}
+}
+
+func Test_setNextActions(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name     string
+		actions  []NextAction
+		expected map[string]interface{}
+	}{
+		{
+			name:     "no actions leaves response untouched",
+			actions:  nil,
+			expected: map[string]interface{}{},
+		},
+		{
+			name:    "otp flow exposes resend_otp as legacy next tool",
+			actions: otpNextActions(ctx, "pay_123", resendOtpStep),
+			expected: map[string]interface{}{
+				"next_actions": otpNextActions(ctx, "pay_123", resendOtpStep),
+				"next_step":    resendOtpStep,
+				"next_tool":    "resend_otp",
+				"next_tool_params": map[string]interface{}{
+					"payment_id": "pay_123",
+				},
+			},
+		},
+		{
+			name: "redirect flow has no legacy next tool",
+			actions: []NextAction{
+				urlNextAction(nextActionRedirect, redirectStep,
+					"https://api.razorpay.com/v1/payments/pay_123/authenticate"),
+			},
+			expected: map[string]interface{}{
+				"next_actions": []NextAction{
+					{
+						Action:      "redirect",
+						Description: redirectStep,
+						URL: "https://api.razorpay.com/v1/payments/" +
+							"pay_123/authenticate",
+					},
+				},
+			},
+		},
+		{
+			name:    "first tool action after url actions is legacy next tool",
+			actions: upiNextActions(ctx, "pay_123", "upi://pay?pa=test@upi"),
+			expected: map[string]interface{}{
+				"next_actions": []NextAction{
+					{
+						Action:      "upi_intent",
+						Description: upiIntentStep,
+						URL:         "upi://pay?pa=test@upi",
+					},
+					{
+						Action:      "call_tool",
+						Description: upiFetchPaymentStep,
+						Tool:        "fetch_payment",
+						Params: map[string]interface{}{
+							"payment_id": "pay_123",
+						},
+					},
+				},
+				"next_step": upiFetchPaymentStep,
+				"next_tool": "fetch_payment",
+				"next_tool_params": map[string]interface{}{
+					"payment_id": "pay_123",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := map[string]interface{}{}
+			setNextActions(response, tt.actions...)
+			assert.Equal(t, tt.expected, response)
+		})
+	}
+}
+
+func Test_upiNextActions(t *testing.T) {
+	t.Run("collect flow only fetches payment", func(t *testing.T) {
+		actions := upiNextActions(context.Background(), "pay_123", "")
+		assert.Len(t, actions, 1)
+		assert.Equal(t, "fetch_payment", actions[0].Tool)
+	})
+
+	t.Run("no payment id and no url yields no actions", func(t *testing.T) {
+		assert.Empty(t, upiNextActions(context.Background(), "", ""))
+	})
+}
+
+func Test_redirectNextActions(t *testing.T) {
+	assert.Nil(t, redirectNextActions(context.Background(), ""))
+
+	actions := redirectNextActions(context.Background(),
+		"https://example.com/auth")
+	assert.Equal(t, []NextAction{
+		{
+			Action:      "redirect",
+			Description: redirectStep,
+			URL:         "https://example.com/auth",
+		},
+	}, actions)
+}
+
+func Test_gatewayActionURL(t *testing.T) {
+	actions := []map[string]interface{}{
+		{"action": "upi_collect"},
+		{"action": "redirect", "url": 42},
+		{"action": "redirect", "url": "https://example.com/auth"},
+	}
+
+	assert.Equal(t, "https://example.com/auth",
+		gatewayActionURL(actions, "redirect"))
+	assert.Equal(t, "", gatewayActionURL(actions, "upi_collect"))
+	assert.Equal(t, "", gatewayActionURL(actions, "upi_intent"))
+}
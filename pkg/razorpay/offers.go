@@ -0,0 +1,131 @@
+package razorpay
+
+import (
+	"context"
+	"fmt"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+	"github.com/razorpay/razorpay-go/constants"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+)
+
+// offersPath is the path of the Offers API, which the SDK does not cover
+var offersPath = fmt.Sprintf("/%s/offers", constants.VERSION_V1)
+
+// FetchOffer returns a tool that fetches an offer by its ID
+func FetchOffer(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"offer_id",
+			mcpgo.Description("ID of the offer, starting with 'offer_'"),
+			mcpgo.Required(),
+			mcpgo.Pattern(mcpgo.IDPattern(offerIDPrefix)),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		params := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(params, "offer_id")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		offerID := params["offer_id"].(string)
+		if result := validateID("offer_id", offerID, offerIDPrefix); result != nil {
+			return result, nil
+		}
+
+		offer, err := client.Request.Get(offersPath+"/"+offerID, nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching offer failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(offer)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_offer",
+		"Fetch an offer, such as an EMI or cashback offer, with its payment "+
+			"method, discount and validity",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema).
+		WithFieldSelection()
+}
+
+// FetchAllOffers returns a tool that fetches the offers of the account
+func FetchAllOffers(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithNumber(
+			"count",
+			mcpgo.Description("Number of offers to fetch "+
+				"(default: 10, max: 100)"),
+			mcpgo.Min(1),
+			mcpgo.Max(100),
+		),
+		mcpgo.WithNumber(
+			"skip",
+			mcpgo.Description("Number of offers to skip (default: 0)"),
+			mcpgo.Min(0),
+		),
+		collectionFormatParameter(),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		queryParams := make(map[string]interface{})
+		options := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddPagination(queryParams).
+			ValidateAndAddCollectionFormat(options)
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		offers, err := client.Request.Get(offersPath, queryParams, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching offers failed: %s", err.Error())), nil
+		}
+
+		return collectionResult(ctx, offers, options)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_all_offers",
+		"Fetch the offers of the account, such as EMI and cashback offers. "+
+			"Apply one to an order with the offer_id of create_order",
+		parameters,
+		handler,
+	).WithOutputSchema(entitiesOutputSchema).
+		WithFieldSelection()
+}
@@ -0,0 +1,113 @@
+package razorpay
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay/mock"
+)
+
+var offerResp = map[string]interface{}{
+	"id":             "offer_JHD834hjbxzhd38d",
+	"entity":         "offer",
+	"name":           "HDFC EMI cashback",
+	"payment_method": "emi",
+	"issuer":         "HDFC",
+	"type":           "instant",
+	"active":         true,
+}
+
+func Test_FetchOffer(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful fetch",
+			Request: map[string]interface{}{
+				"offer_id": "offer_JHD834hjbxzhd38d",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     offersPath + "/offer_JHD834hjbxzhd38d",
+						Method:   "GET",
+						Response: offerResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: offerResp,
+		},
+		{
+			Name: "offer not found",
+			Request: map[string]interface{}{
+				"offer_id": "offer_JHD834hjbxzhd38d",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:   offersPath + "/offer_JHD834hjbxzhd38d",
+						Method: "GET",
+						Response: map[string]interface{}{
+							"error": map[string]interface{}{
+								"code":        "BAD_REQUEST_ERROR",
+								"description": "The id provided does not exist",
+							},
+						},
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "fetching offer failed: The id provided does " +
+				"not exist",
+		},
+		{
+			Name: "invalid offer ID",
+			Request: map[string]interface{}{
+				"offer_id": "offer_1/../payments",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid offer_id: offer_1/../payments",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchOffer, "Offer")
+		})
+	}
+}
+
+func Test_FetchAllOffers(t *testing.T) {
+	offersResp := map[string]interface{}{
+		"entity": "collection",
+		"count":  float64(1),
+		"items":  []interface{}{offerResp},
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful fetch",
+			Request: map[string]interface{}{
+				"count": float64(10),
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     offersPath,
+						Method:   "GET",
+						Response: offersResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: offersResp,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchAllOffers, "Offers")
+		})
+	}
+}
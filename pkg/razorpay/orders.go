@@ -3,6 +3,7 @@ package razorpay
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	rzpsdk "github.com/razorpay/razorpay-go"
 
@@ -77,10 +78,45 @@ func CreateOrder(
 						"description": "ISO currency code",
 						"pattern":     "^[A-Z]{3}$",
 					},
+					"notes": map[string]interface{}{
+						"type":        "object",
+						"description": "Key-value pairs for the transfer",
+					},
+					"linked_account_notes": map[string]interface{}{
+						"type": "array",
+						"description": "Keys of notes to show to the " +
+							"linked account",
+						"items": map[string]interface{}{"type": "string"},
+					},
+					"on_hold": map[string]interface{}{
+						"type": "boolean",
+						"description": "Whether the settlement of the " +
+							"transfer is put on hold",
+					},
+					"on_hold_until": map[string]interface{}{
+						"type": "number",
+						"description": "Unix timestamp until which the " +
+							"settlement is on hold",
+					},
 				},
 				"required": []interface{}{"account", "amount", "currency"},
 			}),
 		),
+		mcpgo.WithString(
+			"offer_id",
+			mcpgo.Description("ID of an offer to apply to the order, "+
+				"starting with 'offer_'. The offer is shown at checkout and "+
+				"applied if the customer pays with an eligible method"),
+			mcpgo.Pattern(mcpgo.IDPattern(offerIDPrefix)),
+		),
+		mcpgo.WithString(
+			"payment_capture",
+			mcpgo.Description("Whether payments of the order are captured "+
+				"automatically once authorized, or have to be captured "+
+				"manually with capture_payment. Uses the account settings "+
+				"if not set"),
+			mcpgo.Enum("automatic", "manual"),
+		),
 		mcpgo.WithString(
 			"method",
 			mcpgo.Description("Payment method for mandate orders. "+
@@ -127,6 +163,7 @@ func CreateOrder(
 			ValidateAndAddOptionalMap(payload, "notes").
 			ValidateAndAddOptionalBool(payload, "partial_payment").
 			ValidateAndAddOptionalArray(payload, "transfers").
+			ValidateAndAddOptionalString(payload, "offer_id").
 			ValidateAndAddOptionalString(payload, "method").
 			ValidateAndAddOptionalString(payload, "customer_id").
 			ValidateAndAddToken(payload, "token")
@@ -136,6 +173,20 @@ func CreateOrder(
 			validator.ValidateAndAddOptionalFloat(payload, "first_payment_min_amount")
 		}
 
+		if err := validateOrderAmounts(payload); err != nil {
+			validator.addError(err)
+		}
+
+		capture := make(map[string]interface{})
+		validator.ValidateAndAddOptionalString(capture, "payment_capture")
+		if mode, ok := capture["payment_capture"].(string); ok {
+			if mode != "automatic" && mode != "manual" {
+				validator.addError(fmt.Errorf(
+					"payment_capture must be automatic or manual"))
+			}
+			payload["payment"] = map[string]interface{}{"capture": mode}
+		}
+
 		if result, err := validator.HandleErrorsIfAny(); result != nil {
 			return result, err
 		}
@@ -155,7 +206,10 @@ func CreateOrder(
 		"Create a new order in Razorpay. Supports both regular orders and "+
 			"mandate orders. "+
 			"\n\nFor REGULAR ORDERS: Provide amount, currency, and optional "+
-			"receipt/notes. "+
+			"receipt/notes/offer_id/payment_capture. Set partial_payment "+
+			"(with an optional "+
+			"first_payment_min_amount) to accept the amount in parts, and "+
+			"transfers to split the payment among linked accounts (Route). "+
 			"\n\nFor MANDATE ORDERS (recurring payments): You MUST provide ALL "+
 			"of these fields: "+
 			"amount, currency, method='upi', customer_id (starts with 'cust_'), "+
@@ -175,7 +229,55 @@ func CreateOrder(
 			`"receipt": "Receipt No. 1", "notes": {"key": "value"}}`,
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(orderOutputSchema).
+		WithAmounts("amount", "first_payment_min_amount", "transfers.amount",
+			"token.max_amount")
+}
+
+// validateOrderAmounts checks the partial payment and transfers of an order
+// against its amount, so that inconsistent orders are rejected before they
+// reach the API. Each transfer needs a linked account, a positive amount
+// and the currency of the order, and the transfers can not exceed the
+// order amount.
+func validateOrderAmounts(order map[string]interface{}) error {
+	amount, ok := order["amount"].(float64)
+	if !ok {
+		return nil
+	}
+
+	if minAmount, ok := order["first_payment_min_amount"].(float64); ok &&
+		minAmount > amount {
+		return fmt.Errorf("first_payment_min_amount can not exceed amount")
+	}
+
+	transfers, _ := order["transfers"].([]interface{})
+	total := float64(0)
+	for i, item := range transfers {
+		transfer, ok := item.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("transfers[%d] must be an object", i)
+		}
+		if account, _ := transfer["account"].(string); !mcpgo.IsID(
+			account, accountIDPrefix) {
+			return fmt.Errorf("transfers[%d].account must be a linked "+
+				"account ID starting with 'acc_'", i)
+		}
+		transferAmount, _ := transfer["amount"].(float64)
+		if transferAmount <= 0 {
+			return fmt.Errorf("transfers[%d].amount must be positive", i)
+		}
+		if transfer["currency"] != order["currency"] {
+			return fmt.Errorf("transfers[%d].currency must match the "+
+				"order currency", i)
+		}
+		total += transferAmount
+	}
+	if total > amount {
+		return fmt.Errorf("transfers total %.0f, more than the order "+
+			"amount %.0f", total, amount)
+	}
+
+	return nil
 }
 
 // FetchOrder returns a tool to fetch order details by ID
@@ -210,6 +312,11 @@ func FetchOrder(
 			return result, err
 		}
 
+		if result := validateID("order_id", payload["order_id"].(string),
+			orderIDPrefix); result != nil {
+			return result, nil
+		}
+
 		order, err := client.Order.Fetch(payload["order_id"].(string), nil, nil)
 		if err != nil {
 			return mcpgo.NewToolResultError(
@@ -225,7 +332,8 @@ func FetchOrder(
 		"Fetch an order's details using its ID",
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(orderOutputSchema).
+		WithFieldSelection()
 }
 
 // FetchAllOrders returns a tool to fetch all orders with optional filtering
@@ -233,7 +341,7 @@ func FetchAllOrders(
 	obs *observability.Observability,
 	client *rzpsdk.Client,
 ) mcpgo.Tool {
-	parameters := []mcpgo.ToolParameter{
+	parameters := append([]mcpgo.ToolParameter{
 		mcpgo.WithNumber(
 			"count",
 			mcpgo.Description("Number of orders to be fetched "+
@@ -285,7 +393,7 @@ func FetchAllOrders(
 				},
 			}),
 		),
-	}
+	}, collectionParameters()...)
 
 	handler := func(
 		ctx context.Context,
@@ -298,9 +406,11 @@ func FetchAllOrders(
 		}
 
 		queryParams := make(map[string]interface{})
+		pagination := make(map[string]interface{})
 
 		validator := NewValidator(&r).
 			ValidateAndAddPagination(queryParams).
+			ValidateAndAddCollectionOptions(pagination).
 			ValidateAndAddOptionalInt(queryParams, "from").
 			ValidateAndAddOptionalInt(queryParams, "to").
 			ValidateAndAddOptionalInt(queryParams, "authorized").
@@ -311,14 +421,14 @@ func FetchAllOrders(
 			return result, err
 		}
 
-		orders, err := client.Order.All(queryParams, nil)
+		orders, err := fetchCollection(queryParams, pagination, client.Order.All)
 		if err != nil {
 			return mcpgo.NewToolResultError(
 				fmt.Sprintf("fetching orders failed: %s", err.Error()),
 			), nil
 		}
 
-		return mcpgo.NewToolResultJSON(orders)
+		return collectionResult(ctx, orders, pagination)
 	}
 
 	return mcpgo.NewTool(
@@ -326,7 +436,65 @@ func FetchAllOrders(
 		"Fetch all orders with optional filtering and pagination",
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(ordersOutputSchema).
+		WithFieldSelection()
+}
+
+// FetchOrderByReceipt returns a tool that finds the orders created with a
+// receipt, the merchant's own reference for an order
+func FetchOrderByReceipt(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"receipt",
+			mcpgo.Description("Receipt the order was created with, such as "+
+				"the merchant's own order or invoice number"),
+			mcpgo.Required(),
+			mcpgo.Max(40),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		queryParams := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(queryParams, "receipt")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		// Receipts are meant to be unique, but the API does not enforce it,
+		// so every match is returned
+		queryParams["count"] = maxPageSize
+		orders, err := client.Order.All(queryParams, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching orders failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(orders)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_order_by_receipt",
+		"Find the orders created with a receipt, the merchant's own "+
+			"reference, when the Razorpay order_id is not known. Returns an "+
+			"empty collection when no order has the receipt",
+		parameters,
+		handler,
+	).WithOutputSchema(ordersOutputSchema).
+		WithFieldSelection()
 }
 
 // FetchOrderPayments returns a tool to fetch all payments for a specific order
@@ -342,6 +510,13 @@ func FetchOrderPayments(
 					" be retrieved. Order id should start with `order_`"),
 			mcpgo.Required(),
 		),
+		mcpgo.WithBoolean(
+			"summary",
+			mcpgo.Description("Also return a summary of the order: whether "+
+				"it is paid, partially paid or unpaid, the amounts paid and "+
+				"due, and the payment attempts by status (default: false)"),
+			mcpgo.DefaultValue(false),
+		),
 	}
 
 	handler := func(
@@ -357,12 +532,18 @@ func FetchOrderPayments(
 		orderPaymentsReq := make(map[string]interface{})
 
 		validator := NewValidator(&r).
-			ValidateAndAddRequiredString(orderPaymentsReq, "order_id")
+			ValidateAndAddRequiredString(orderPaymentsReq, "order_id").
+			ValidateAndAddOptionalBool(orderPaymentsReq, "summary")
 
 		if result, err := validator.HandleErrorsIfAny(); result != nil {
 			return result, err
 		}
 
+		if result := validateID("order_id", orderPaymentsReq["order_id"].(string),
+			orderIDPrefix); result != nil {
+			return result, nil
+		}
+
 		// Fetch payments for the order using Razorpay SDK
 		// Note: Using the Order.Payments method from SDK
 		orderID := orderPaymentsReq["order_id"].(string)
@@ -376,16 +557,72 @@ func FetchOrderPayments(
 			), nil
 		}
 
+		if orderPaymentsReq["summary"] == true {
+			order, err := client.Order.Fetch(orderID, nil, nil)
+			if err != nil {
+				return mcpgo.NewToolResultError(
+					fmt.Sprintf("fetching order failed: %s", err.Error())), nil
+			}
+			payments["summary"] = orderPaymentSummary(order, payments)
+		}
+
 		// Return the result as JSON
 		return mcpgo.NewToolResultJSON(payments)
 	}
 
 	return mcpgo.NewTool(
 		"fetch_order_payments",
-		"Fetch all payments made for a specific order in Razorpay",
+		"Fetch all payments made for a specific order in Razorpay. Set "+
+			"summary to also see whether the order is paid, partially paid "+
+			"or unpaid, and how its payment attempts ended",
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(paymentsOutputSchema).
+		WithFieldSelection()
+}
+
+// orderPaymentSummary summarizes the payments made for an order: whether
+// the order is paid, partially paid or unpaid, and how many attempts
+// ended in each payment status
+func orderPaymentSummary(
+	order map[string]interface{},
+	payments map[string]interface{},
+) map[string]interface{} {
+	amount, _ := order["amount"].(float64)
+	amountPaid, _ := order["amount_paid"].(float64)
+
+	state := "unpaid"
+	switch {
+	case amountPaid > 0 && amountPaid >= amount:
+		state = "paid"
+	case amountPaid > 0:
+		state = "partially_paid"
+	}
+
+	items, _ := payments["items"].([]interface{})
+	byStatus := make(map[string]int)
+	capturedAmount := float64(0)
+	for _, item := range items {
+		payment, _ := item.(map[string]interface{})
+		status := stringField(payment, "status")
+		byStatus[status]++
+		if status == "captured" || status == "refunded" {
+			paymentAmount, _ := payment["amount"].(float64)
+			capturedAmount += paymentAmount
+		}
+	}
+
+	return map[string]interface{}{
+		"order_status":    order["status"],
+		"payment_state":   state,
+		"amount":          order["amount"],
+		"amount_paid":     order["amount_paid"],
+		"amount_due":      order["amount_due"],
+		"currency":        order["currency"],
+		"attempts":        len(items),
+		"by_status":       byStatus,
+		"captured_amount": capturedAmount,
+	}
 }
 
 // UpdateOrder returns a tool to update an order
@@ -430,6 +667,11 @@ func UpdateOrder(
 			return result, err
 		}
 
+		if result := validateID("order_id", orderUpdateReq["order_id"].(string),
+			orderIDPrefix); result != nil {
+			return result, nil
+		}
+
 		data["notes"] = orderUpdateReq["notes"]
 		orderID := orderUpdateReq["order_id"].(string)
 
@@ -448,5 +690,168 @@ func UpdateOrder(
 			"Only the notes field can be modified.",
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(orderOutputSchema)
+}
+
+// createOrdersBatchConcurrency bounds the number of orders that are created
+// in parallel by the create_orders_batch tool
+const createOrdersBatchConcurrency = 5
+
+// CreateOrdersBatch returns a tool that creates multiple orders in one call.
+// Orders are created with bounded concurrency and the outcome of each order
+// is reported individually, so one bad spec does not fail the whole batch.
+func CreateOrdersBatch(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithArray(
+			"orders",
+			mcpgo.Description("Array of order specs to create. Each spec "+
+				"accepts the same fields as create_order: amount (in the "+
+				"smallest currency sub-unit), currency (ISO code), and "+
+				"optional receipt, notes, partial_payment and "+
+				"first_payment_min_amount"),
+			mcpgo.Required(),
+			mcpgo.Min(1),
+			mcpgo.Max(50),
+			mcpgo.Items(map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"amount": map[string]interface{}{
+						"type":        "number",
+						"description": "Order amount in currency subunits",
+						"minimum":     100,
+					},
+					"currency": map[string]interface{}{
+						"type":        "string",
+						"description": "ISO currency code",
+						"pattern":     "^[A-Z]{3}$",
+					},
+					"receipt": map[string]interface{}{
+						"type":        "string",
+						"description": "Receipt number for internal reference",
+						"maxLength":   40,
+					},
+					"notes": map[string]interface{}{
+						"type":        "object",
+						"description": "Key-value pairs for additional information",
+					},
+				},
+				"required": []interface{}{"amount", "currency"},
+			}),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		// Get client from context or use default
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		payload := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredArray(payload, "orders")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		specs := payload["orders"].([]interface{})
+		if len(specs) == 0 {
+			return mcpgo.NewToolResultError(
+				"orders must contain at least one order"), nil
+		}
+
+		results := make([]map[string]interface{}, len(specs))
+		sem := make(chan struct{}, createOrdersBatchConcurrency)
+		var wg sync.WaitGroup
+
+		for i, spec := range specs {
+			wg.Add(1)
+			go func(i int, spec interface{}) {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				results[i] = createBatchOrder(client, i, spec)
+			}(i, spec)
+		}
+		wg.Wait()
+
+		succeeded := 0
+		for _, result := range results {
+			if result["status"] == "created" {
+				succeeded++
+			}
+		}
+
+		return mcpgo.NewToolResultJSON(map[string]interface{}{
+			"count":     len(results),
+			"succeeded": succeeded,
+			"failed":    len(results) - succeeded,
+			"results":   results,
+		})
+	}
+
+	return mcpgo.NewTool(
+		"create_orders_batch",
+		"Create multiple orders in Razorpay in a single call. Each entry in "+
+			"'orders' is validated and created independently; the response "+
+			"contains one result per entry (in the same order) with either "+
+			"the created order_id or the error for that entry.",
+		parameters,
+		handler,
+	).WithOutputSchema(objectOutputSchema).
+		WithoutTimeout().
+		WithAmounts("orders.amount", "orders.first_payment_min_amount")
+}
+
+// createBatchOrder validates a single order spec from a batch and creates
+// the order, returning the per-item result
+func createBatchOrder(
+	client *rzpsdk.Client,
+	index int,
+	spec interface{},
+) map[string]interface{} {
+	result := map[string]interface{}{
+		"index": index,
+	}
+
+	specReq := mcpgo.CallToolRequest{Arguments: spec}
+	payload := make(map[string]interface{})
+
+	validator := NewValidator(&specReq).
+		ValidateAndAddRequiredFloat(payload, "amount").
+		ValidateAndAddRequiredString(payload, "currency").
+		ValidateAndAddOptionalString(payload, "receipt").
+		ValidateAndAddOptionalMap(payload, "notes").
+		ValidateAndAddOptionalBool(payload, "partial_payment")
+
+	if payload["partial_payment"] == true {
+		validator.ValidateAndAddOptionalFloat(payload, "first_payment_min_amount")
+	}
+
+	if errResult, _ := validator.HandleErrorsIfAny(); errResult != nil {
+		result["status"] = "failed"
+		result["error"] = errResult.Text
+		return result
+	}
+
+	order, err := client.Order.Create(payload, nil)
+	if err != nil {
+		result["status"] = "failed"
+		result["error"] = fmt.Sprintf("creating order failed: %s", err.Error())
+		return result
+	}
+
+	result["status"] = "created"
+	result["order_id"] = order["id"]
+	result["order"] = order
+	return result
 }
@@ -1,11 +1,17 @@
 package razorpay
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
 	"github.com/razorpay/razorpay-go/constants"
 
 	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay/mock"
@@ -40,6 +46,14 @@ func Test_CreateOrder(t *testing.T) {
 		"status":   "created",
 	}
 
+	orderWithOfferResp := map[string]interface{}{
+		"id":       "order_EKwxwAgItmmXdp",
+		"amount":   float64(10000),
+		"currency": "INR",
+		"offer_id": "offer_JHD834hjbxzhd38d",
+		"status":   "created",
+	}
+
 	errorResp := map[string]interface{}{
 		"error": map[string]interface{}{
 			"code":        "BAD_REQUEST_ERROR",
@@ -73,6 +87,25 @@ func Test_CreateOrder(t *testing.T) {
 			ExpectError:    false,
 			ExpectedResult: orderWithAllParamsResp,
 		},
+		{
+			Name: "successful order creation with an offer",
+			Request: map[string]interface{}{
+				"amount":   float64(10000),
+				"currency": "INR",
+				"offer_id": "offer_JHD834hjbxzhd38d",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     createOrderPath,
+						Method:   "POST",
+						Response: orderWithOfferResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: orderWithOfferResp,
+		},
 		{
 			Name: "successful order creation with required params only",
 			Request: map[string]interface{}{
@@ -118,6 +151,133 @@ func Test_CreateOrder(t *testing.T) {
 			ExpectedErrMsg: "Validation errors:\n- " +
 				"invalid parameter type: first_payment_min_amount",
 		},
+		{
+			Name: "order with transfers",
+			Request: map[string]interface{}{
+				"amount":   float64(10000),
+				"currency": "INR",
+				"transfers": []interface{}{
+					map[string]interface{}{
+						"account":  "acc_CPRsN1LkFccllA",
+						"amount":   float64(6000),
+						"currency": "INR",
+						"on_hold":  true,
+					},
+					map[string]interface{}{
+						"account":  "acc_CNo3jSI8OkFJJJ",
+						"amount":   float64(4000),
+						"currency": "INR",
+					},
+				},
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     createOrderPath,
+						Method:   "POST",
+						Response: orderWithRequiredParamsResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: orderWithRequiredParamsResp,
+		},
+		{
+			Name: "transfers above the order amount",
+			Request: map[string]interface{}{
+				"amount":   float64(10000),
+				"currency": "INR",
+				"transfers": []interface{}{
+					map[string]interface{}{
+						"account":  "acc_CPRsN1LkFccllA",
+						"amount":   float64(12000),
+						"currency": "INR",
+					},
+				},
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "transfers total 12000, more than the order " +
+				"amount 10000",
+		},
+		{
+			Name: "successful order creation with manual capture",
+			Request: map[string]interface{}{
+				"amount":          float64(10000),
+				"currency":        "INR",
+				"payment_capture": "manual",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     createOrderPath,
+						Method:   "POST",
+						Response: orderWithRequiredParamsResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: orderWithRequiredParamsResp,
+		},
+		{
+			Name: "invalid payment capture mode",
+			Request: map[string]interface{}{
+				"amount":          float64(10000),
+				"currency":        "INR",
+				"payment_capture": "later",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "payment_capture must be automatic or manual",
+		},
+		{
+			Name: "transfer without linked account",
+			Request: map[string]interface{}{
+				"amount":   float64(10000),
+				"currency": "INR",
+				"transfers": []interface{}{
+					map[string]interface{}{
+						"account":  "cust_CPRsN1LkFccllA",
+						"amount":   float64(1000),
+						"currency": "INR",
+					},
+				},
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "transfers[0].account must be a linked account " +
+				"ID starting with 'acc_'",
+		},
+		{
+			Name: "transfer in another currency",
+			Request: map[string]interface{}{
+				"amount":   float64(10000),
+				"currency": "INR",
+				"transfers": []interface{}{
+					map[string]interface{}{
+						"account":  "acc_CPRsN1LkFccllA",
+						"amount":   float64(1000),
+						"currency": "USD",
+					},
+				},
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "transfers[0].currency must match the order " +
+				"currency",
+		},
+		{
+			Name: "first payment above the order amount",
+			Request: map[string]interface{}{
+				"amount":                   float64(10000),
+				"currency":                 "INR",
+				"partial_payment":          true,
+				"first_payment_min_amount": float64(20000),
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "first_payment_min_amount can not exceed amount",
+		},
 		{
 			Name: "order creation fails",
 			Request: map[string]interface{}{
@@ -838,6 +998,58 @@ func Test_FetchOrderPayments(t *testing.T) {
 			ExpectError:    false,
 			ExpectedResult: paymentsResp,
 		},
+		{
+			Name: "payments with a summary",
+			Request: map[string]interface{}{
+				"order_id": "order_N8FRN5zTm5S3wx",
+				"summary":  true,
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path: fmt.Sprintf(
+							fetchOrderPaymentsPathFmt,
+							"order_N8FRN5zTm5S3wx",
+						),
+						Method:   "GET",
+						Response: paymentsResp,
+					},
+					mock.Endpoint{
+						Path: fmt.Sprintf("/%s%s/%s", constants.VERSION_V1,
+							constants.ORDER_URL, "order_N8FRN5zTm5S3wx"),
+						Method: "GET",
+						Response: map[string]interface{}{
+							"id":          "order_N8FRN5zTm5S3wx",
+							"amount":      float64(300),
+							"amount_paid": float64(100),
+							"amount_due":  float64(200),
+							"currency":    "INR",
+							"status":      "attempted",
+						},
+					},
+				)
+			},
+			ExpectError: false,
+			ExpectedResult: map[string]interface{}{
+				"entity": paymentsResp["entity"],
+				"count":  paymentsResp["count"],
+				"items":  paymentsResp["items"],
+				"summary": map[string]interface{}{
+					"order_status":  "attempted",
+					"payment_state": "partially_paid",
+					"amount":        float64(300),
+					"amount_paid":   float64(100),
+					"amount_due":    float64(200),
+					"currency":      "INR",
+					"attempts":      float64(2),
+					"by_status": map[string]interface{}{
+						"failed":   float64(1),
+						"captured": float64(1),
+					},
+					"captured_amount": float64(100),
+				},
+			},
+		},
 		{
 			Name: "order not found",
 			Request: map[string]interface{}{
@@ -952,7 +1164,7 @@ func Test_UpdateOrder(t *testing.T) {
 		{
 			Name: "order not found",
 			Request: map[string]interface{}{
-				"order_id": "order_invalid_id",
+				"order_id": "order_NotFound00001",
 				"notes": map[string]interface{}{
 					"customer_name": "updated-customer",
 					"product_name":  "updated-product",
@@ -961,7 +1173,7 @@ func Test_UpdateOrder(t *testing.T) {
 			MockHttpClient: func() (*http.Client, *httptest.Server) {
 				return mock.NewHTTPClient(
 					mock.Endpoint{
-						Path:     fmt.Sprintf(updateOrderPathFmt, "order_invalid_id"),
+						Path:     fmt.Sprintf(updateOrderPathFmt, "order_NotFound00001"),
 						Method:   "PATCH",
 						Response: orderNotFoundResp,
 					},
@@ -978,3 +1190,213 @@ func Test_UpdateOrder(t *testing.T) {
 		})
 	}
 }
+
+func Test_CreateOrdersBatch(t *testing.T) {
+	createOrderPath := fmt.Sprintf(
+		"/%s%s",
+		constants.VERSION_V1,
+		constants.ORDER_URL,
+	)
+
+	orderResp := map[string]interface{}{
+		"id":       "order_EKwxwAgItmmXdp",
+		"amount":   float64(10000),
+		"currency": "INR",
+		"status":   "created",
+	}
+
+	errorResp := map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":        "BAD_REQUEST_ERROR",
+			"description": "Razorpay API error: Bad request",
+		},
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "partial success with one invalid spec",
+			Request: map[string]interface{}{
+				"orders": []interface{}{
+					map[string]interface{}{
+						"amount":   float64(10000),
+						"currency": "INR",
+					},
+					map[string]interface{}{
+						"amount": float64(10000),
+					},
+					map[string]interface{}{
+						"amount":   float64(10000),
+						"currency": "INR",
+						"receipt":  "receipt-2",
+					},
+				},
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     createOrderPath,
+						Method:   "POST",
+						Response: orderResp,
+					},
+				)
+			},
+			ExpectError: false,
+			ExpectedResult: map[string]interface{}{
+				"count":     float64(3),
+				"succeeded": float64(2),
+				"failed":    float64(1),
+				"results": []interface{}{
+					map[string]interface{}{
+						"index":    float64(0),
+						"status":   "created",
+						"order_id": "order_EKwxwAgItmmXdp",
+						"order":    orderResp,
+					},
+					map[string]interface{}{
+						"index":  float64(1),
+						"status": "failed",
+						"error": "Validation errors:\n- " +
+							"missing required parameter: currency",
+					},
+					map[string]interface{}{
+						"index":    float64(2),
+						"status":   "created",
+						"order_id": "order_EKwxwAgItmmXdp",
+						"order":    orderResp,
+					},
+				},
+			},
+		},
+		{
+			Name: "api failure is reported per order",
+			Request: map[string]interface{}{
+				"orders": []interface{}{
+					map[string]interface{}{
+						"amount":   float64(10000),
+						"currency": "INR",
+					},
+				},
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     createOrderPath,
+						Method:   "POST",
+						Response: errorResp,
+					},
+				)
+			},
+			ExpectError: false,
+			ExpectedResult: map[string]interface{}{
+				"count":     float64(1),
+				"succeeded": float64(0),
+				"failed":    float64(1),
+				"results": []interface{}{
+					map[string]interface{}{
+						"index":  float64(0),
+						"status": "failed",
+						"error": "creating order failed: " +
+							"Razorpay API error: Bad request",
+					},
+				},
+			},
+		},
+		{
+			Name:           "missing orders parameter",
+			Request:        map[string]interface{}{},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: orders",
+		},
+		{
+			Name: "empty orders array",
+			Request: map[string]interface{}{
+				"orders": []interface{}{},
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "orders must contain at least one order",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, CreateOrdersBatch, "Order batch")
+		})
+	}
+}
+
+func Test_FetchOrderByReceipt(t *testing.T) {
+	ordersPath := fmt.Sprintf("/%s%s", constants.VERSION_V1,
+		constants.ORDER_URL)
+
+	ordersResp := map[string]interface{}{
+		"entity": "collection",
+		"count":  float64(1),
+		"items": []interface{}{
+			map[string]interface{}{
+				"id":       "order_EKzX2WiEWbMxmx",
+				"entity":   "order",
+				"amount":   float64(1234),
+				"currency": "INR",
+				"receipt":  "INV-1001",
+				"status":   "paid",
+			},
+		},
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name:    "order found",
+			Request: map[string]interface{}{"receipt": "INV-1001"},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     ordersPath,
+						Method:   "GET",
+						Response: ordersResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: ordersResp,
+		},
+		{
+			Name:           "missing receipt",
+			Request:        map[string]interface{}{},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: receipt",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchOrderByReceipt, "Orders")
+		})
+	}
+
+	t.Run("filters by receipt", func(t *testing.T) {
+		var query url.Values
+		mockClient := func() (*http.Client, *httptest.Server) {
+			server := httptest.NewServer(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					query = r.URL.Query()
+					_ = json.NewEncoder(w).Encode(ordersResp)
+				}))
+			return server.Client(), server
+		}
+
+		client, server := newMockRzpClient(mockClient)
+		defer server.Close()
+
+		tool := FetchOrderByReceipt(CreateTestObservability(), client)
+		result, err := tool.GetHandler()(context.Background(),
+			createMCPRequest(map[string]interface{}{"receipt": "INV-1001"}))
+		require.NoError(t, err)
+		require.False(t, result.IsError, result.Text)
+
+		assert.Equal(t, "INV-1001", query.Get("receipt"))
+		assert.Equal(t, "100", query.Get("count"))
+	})
+}
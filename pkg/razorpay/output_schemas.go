@@ -0,0 +1,115 @@
+//nolint:lll
+package razorpay
+
+import (
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+)
+
+// Payment is a payment made by a customer, as returned by the payments API
+type Payment struct {
+	ID             string      `json:"id" description:"Unique identifier of the payment, prefixed with pay_"`
+	Entity         string      `json:"entity" description:"Always payment"`
+	Amount         int64       `json:"amount" description:"Payment amount in the smallest currency sub-unit"`
+	Currency       string      `json:"currency" description:"ISO 4217 currency code of the payment"`
+	Status         string      `json:"status" description:"One of created, authorized, captured, refunded or failed"`
+	Method         string      `json:"method" description:"Payment method, such as card, netbanking, wallet or upi"`
+	OrderID        *string     `json:"order_id" description:"Order the payment was made for"`
+	InvoiceID      *string     `json:"invoice_id" description:"Invoice the payment was made for"`
+	International  bool        `json:"international" description:"Whether an international card was used"`
+	AmountRefunded int64       `json:"amount_refunded" description:"Amount refunded so far"`
+	RefundStatus   *string     `json:"refund_status" description:"partial or full once the payment is refunded"`
+	Captured       bool        `json:"captured" description:"Whether the payment has been captured"`
+	Description    *string     `json:"description" description:"Description of the payment"`
+	CardID         *string     `json:"card_id" description:"Card used for card payments"`
+	Bank           *string     `json:"bank" description:"Bank code for netbanking payments"`
+	Wallet         *string     `json:"wallet" description:"Wallet used for wallet payments"`
+	VPA            *string     `json:"vpa" description:"Customer UPI address for UPI payments"`
+	Email          *string     `json:"email" description:"Customer email address"`
+	Contact        *string     `json:"contact" description:"Customer phone number"`
+	Notes          interface{} `json:"notes" description:"Key-value pairs of additional information"`
+	Fee            *int64      `json:"fee" description:"Fee charged by Razorpay, including tax"`
+	Tax            *int64      `json:"tax" description:"Tax included in the fee"`
+	ErrorCode      *string     `json:"error_code" description:"Error code of a failed payment"`
+	ErrorReason    *string     `json:"error_description" description:"Description of the error of a failed payment"`
+	AcquirerData   interface{} `json:"acquirer_data" description:"Reference numbers from the acquirer, such as rrn"`
+	CreatedAt      int64       `json:"created_at" description:"Unix timestamp of when the payment was created"`
+}
+
+// Order is an order that payments are made against, as returned by the
+// orders API
+type Order struct {
+	ID         string      `json:"id" description:"Unique identifier of the order, prefixed with order_"`
+	Entity     string      `json:"entity" description:"Always order"`
+	Amount     int64       `json:"amount" description:"Order amount in the smallest currency sub-unit"`
+	AmountPaid int64       `json:"amount_paid" description:"Amount paid against the order so far"`
+	AmountDue  int64       `json:"amount_due" description:"Amount still to be paid against the order"`
+	Currency   string      `json:"currency" description:"ISO 4217 currency code of the order"`
+	Receipt    *string     `json:"receipt" description:"Merchant's own reference for the order"`
+	OfferID    *string     `json:"offer_id" description:"Offer applied to the order"`
+	Status     string      `json:"status" description:"One of created, attempted or paid"`
+	Attempts   int64       `json:"attempts" description:"Number of payment attempts made against the order"`
+	Notes      interface{} `json:"notes" description:"Key-value pairs of additional information"`
+	CreatedAt  int64       `json:"created_at" description:"Unix timestamp of when the order was created"`
+}
+
+// Refund is a full or partial refund of a payment, as returned by the
+// refunds API
+type Refund struct {
+	ID             string      `json:"id" description:"Unique identifier of the refund, prefixed with rfnd_"`
+	Entity         string      `json:"entity" description:"Always refund"`
+	Amount         int64       `json:"amount" description:"Refund amount in the smallest currency sub-unit"`
+	Currency       string      `json:"currency" description:"ISO 4217 currency code of the refund"`
+	PaymentID      string      `json:"payment_id" description:"Payment the refund was made for"`
+	Receipt        *string     `json:"receipt" description:"Merchant's own reference for the refund"`
+	Status         string      `json:"status" description:"One of pending, processed or failed"`
+	SpeedRequested *string     `json:"speed_requested" description:"Speed the refund was requested at"`
+	SpeedProcessed *string     `json:"speed_processed" description:"Speed the refund was processed at"`
+	BatchID        *string     `json:"batch_id" description:"Batch the refund was created in"`
+	AcquirerData   interface{} `json:"acquirer_data" description:"Reference numbers from the acquirer, such as arn"`
+	Notes          interface{} `json:"notes" description:"Key-value pairs of additional information"`
+	CreatedAt      int64       `json:"created_at" description:"Unix timestamp of when the refund was created"`
+}
+
+// Settlement is a transfer of collected funds to the merchant's bank
+// account, as returned by the settlements API
+type Settlement struct {
+	ID        string  `json:"id" description:"Unique identifier of the settlement, prefixed with setl_"`
+	Entity    string  `json:"entity" description:"Always settlement"`
+	Amount    int64   `json:"amount" description:"Amount settled in the smallest currency sub-unit"`
+	Status    string  `json:"status" description:"One of created, processed or failed"`
+	Fees      int64   `json:"fees" description:"Fees deducted for the settlement, including tax"`
+	Tax       int64   `json:"tax" description:"Tax included in the fees"`
+	UTR       *string `json:"utr" description:"Bank transfer reference, used to match bank statement entries"`
+	CreatedAt int64   `json:"created_at" description:"Unix timestamp of when the settlement was created"`
+}
+
+// Entity holds the fields common to all Razorpay entities
+type Entity struct {
+	ID     string `json:"id" description:"Unique identifier of the entity"`
+	Entity string `json:"entity" description:"Type of the entity"`
+}
+
+// Collection is a page of entities, as returned by the fetch all APIs
+type Collection[T any] struct {
+	Entity string `json:"entity" description:"Always collection"`
+	Count  int64  `json:"count" description:"Number of entities in items"`
+	Items  []T    `json:"items" description:"Entities in the page"`
+}
+
+// Output schemas of the tools, built from the entity types
+var (
+	paymentOutputSchema    = mcpgo.SchemaFromStruct(Payment{})
+	orderOutputSchema      = mcpgo.SchemaFromStruct(Order{})
+	refundOutputSchema     = mcpgo.SchemaFromStruct(Refund{})
+	settlementOutputSchema = mcpgo.SchemaFromStruct(Settlement{})
+	entityOutputSchema     = mcpgo.SchemaFromStruct(Entity{})
+
+	paymentsOutputSchema    = mcpgo.SchemaFromStruct(Collection[Payment]{})
+	ordersOutputSchema      = mcpgo.SchemaFromStruct(Collection[Order]{})
+	refundsOutputSchema     = mcpgo.SchemaFromStruct(Collection[Refund]{})
+	settlementsOutputSchema = mcpgo.SchemaFromStruct(Collection[Settlement]{})
+	entitiesOutputSchema    = mcpgo.SchemaFromStruct(Collection[Entity]{})
+
+	// objectOutputSchema is used by tools whose results are not entities
+	objectOutputSchema = map[string]interface{}{"type": "object"}
+)
@@ -0,0 +1,59 @@
+package razorpay
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+)
+
+func TestToolOutputSchemas(t *testing.T) {
+	t.Run("declares an output schema for every tool", func(t *testing.T) {
+		obs := CreateTestObservability()
+		client := rzpsdk.NewClient("test-key", "test-secret")
+
+		server, err := NewRzpMcpServer(obs, client, []string{}, nil, nil, false)
+		require.NoError(t, err)
+
+		impl, ok := server.(*mcpgo.Mark3labsImpl)
+		require.True(t, ok)
+
+		tools := impl.McpServer.ListTools()
+		require.NotEmpty(t, tools)
+		for name, tool := range tools {
+			assert.NotEmpty(t, tool.Tool.RawOutputSchema,
+				"tool %s has no output schema", name)
+		}
+	})
+
+	t.Run("describes entity fields", func(t *testing.T) {
+		schema := paymentOutputSchema["properties"]
+		properties, ok := schema.(map[string]interface{})
+		require.True(t, ok)
+
+		assert.Equal(t, map[string]interface{}{
+			"type":        "integer",
+			"description": "Payment amount in the smallest currency sub-unit",
+		}, properties["amount"])
+		assert.Equal(t, map[string]interface{}{
+			"type":        []string{"string", "null"},
+			"description": "Order the payment was made for",
+		}, properties["order_id"])
+	})
+
+	t.Run("describes collection items", func(t *testing.T) {
+		schema := settlementsOutputSchema["properties"]
+		properties, ok := schema.(map[string]interface{})
+		require.True(t, ok)
+
+		assert.Equal(t, map[string]interface{}{
+			"type":        "array",
+			"items":       settlementOutputSchema,
+			"description": "Entities in the page",
+		}, properties["items"])
+	})
+}
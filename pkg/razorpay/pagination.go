@@ -0,0 +1,172 @@
+package razorpay
+
+import (
+	"errors"
+	"time"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+)
+
+const (
+	// maxPageSize is the largest count the fetch all APIs accept
+	maxPageSize = 100
+
+	// defaultMaxResults is the number of records auto pagination collects
+	// when max_results is not set
+	defaultMaxResults = 500
+
+	// maxMaxResults caps the number of records auto pagination collects, to
+	// bound the number of API calls and the size of the result
+	maxMaxResults = 1000
+
+	// scanToolTimeout is how long calls of the tools that look through
+	// many pages of records, or make many API calls, may take
+	scanToolTimeout = 5 * time.Minute
+)
+
+// pageFetcher fetches a page of a collection with the given query
+// parameters, as the All methods of the Razorpay SDK do
+type pageFetcher func(
+	queryParams map[string]interface{},
+	extraHeaders map[string]string,
+) (map[string]interface{}, error)
+
+// autoPaginationParameters returns the parameters that let a fetch all tool
+// follow the pages of a collection itself
+func autoPaginationParameters() []mcpgo.ToolParameter {
+	return []mcpgo.ToolParameter{
+		mcpgo.WithBoolean(
+			"auto_paginate",
+			mcpgo.Description("Fetch pages of up to 100 records, starting "+
+				"at skip, until max_results records are collected or no "+
+				"records are left, and return them as one collection. "+
+				"count is ignored when set"),
+		),
+		mcpgo.WithNumber(
+			"max_results",
+			mcpgo.Description("Maximum number of records to collect when "+
+				"auto_paginate is set (default: 500, max: 1000)"),
+			mcpgo.Min(1),
+			mcpgo.Max(maxMaxResults),
+		),
+	}
+}
+
+// collectionParameters returns the auto pagination parameters and the
+// format parameter of a fetch all tool
+func collectionParameters() []mcpgo.ToolParameter {
+	return append(autoPaginationParameters(), collectionFormatParameter())
+}
+
+// ValidateAndAddCollectionOptions validates and adds the auto pagination
+// parameters and the format of a fetch all tool
+func (v *Validator) ValidateAndAddCollectionOptions(
+	params map[string]interface{},
+) *Validator {
+	return v.ValidateAndAddAutoPagination(params).
+		ValidateAndAddCollectionFormat(params)
+}
+
+// ValidateAndAddAutoPagination validates and adds the auto pagination
+// parameters (auto_paginate and max_results)
+func (v *Validator) ValidateAndAddAutoPagination(
+	params map[string]interface{},
+) *Validator {
+	v.ValidateAndAddOptionalBool(params, "auto_paginate").
+		ValidateAndAddOptionalInt(params, "max_results")
+
+	if maxResults, ok := params["max_results"].(int64); ok &&
+		(maxResults < 1 || maxResults > maxMaxResults) {
+		return v.addError(
+			errors.New("max_results must be between 1 and 1000"))
+	}
+	return v
+}
+
+// fetchCollection fetches a collection with the query parameters. With
+// auto_paginate set in pagination, it fetches page after page from skip on
+// until max_results records are collected or a page comes back short, and
+// merges the items of the pages into one collection.
+func fetchCollection(
+	queryParams map[string]interface{},
+	pagination map[string]interface{},
+	fetch pageFetcher,
+) (map[string]interface{}, error) {
+	if autoPaginate, _ := pagination["auto_paginate"].(bool); !autoPaginate {
+		return fetch(queryParams, nil)
+	}
+
+	maxResults := int64(defaultMaxResults)
+	if value, ok := pagination["max_results"].(int64); ok {
+		maxResults = value
+	}
+
+	items, _, err := scanPages(queryParams, maxResults, maxResults, nil, fetch)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"entity": "collection",
+		"count":  len(items),
+		"items":  items,
+	}, nil
+}
+
+// pageScan is the outcome of scanning the pages of a collection
+type pageScan struct {
+	// scanned is the number of records looked at
+	scanned int64
+	// exhausted is set when a page came back short, so no records are left
+	exhausted bool
+}
+
+// scanPages fetches pages of up to 100 records from skip on and collects
+// the items that match, or all items if match is nil. It stops once
+// maxMatches items are collected, maxScanned records are looked at or a
+// page comes back short.
+func scanPages(
+	queryParams map[string]interface{},
+	maxMatches int64,
+	maxScanned int64,
+	match func(item map[string]interface{}) bool,
+	fetch pageFetcher,
+) ([]interface{}, pageScan, error) {
+	skip, _ := queryParams["skip"].(int64)
+	items := make([]interface{}, 0)
+	var scan pageScan
+	for scan.scanned < maxScanned {
+		count := min(maxScanned-scan.scanned, maxPageSize)
+
+		pageParams := make(map[string]interface{}, len(queryParams))
+		for key, value := range queryParams {
+			pageParams[key] = value
+		}
+		pageParams["count"] = count
+		pageParams["skip"] = skip + scan.scanned
+
+		page, err := fetch(pageParams, nil)
+		if err != nil {
+			return nil, scan, err
+		}
+
+		pageItems, _ := page["items"].([]interface{})
+		for _, pageItem := range pageItems {
+			scan.scanned++
+			item, _ := pageItem.(map[string]interface{})
+			if match == nil || match(item) {
+				items = append(items, pageItem)
+			}
+			if int64(len(items)) >= maxMatches {
+				return items, scan, nil
+			}
+		}
+
+		if int64(len(pageItems)) < count {
+			scan.exhausted = true
+			break
+		}
+	}
+
+	return items, scan, nil
+}
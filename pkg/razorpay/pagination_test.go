@@ -0,0 +1,170 @@
+package razorpay
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPagedFetcher returns a page fetcher over total records, together with
+// the query parameters of every page it was asked for
+func newPagedFetcher(total int64) (pageFetcher, *[]map[string]interface{}) {
+	var requests []map[string]interface{}
+	fetch := func(
+		queryParams map[string]interface{},
+		extraHeaders map[string]string,
+	) (map[string]interface{}, error) {
+		requests = append(requests, queryParams)
+
+		count, _ := queryParams["count"].(int64)
+		skip, _ := queryParams["skip"].(int64)
+		items := make([]interface{}, 0)
+		for i := skip; i < total && i < skip+count; i++ {
+			items = append(items, map[string]interface{}{"index": i})
+		}
+		return map[string]interface{}{
+			"entity": "collection",
+			"count":  len(items),
+			"items":  items,
+		}, nil
+	}
+	return fetch, &requests
+}
+
+func TestFetchCollection(t *testing.T) {
+	t.Run("fetches a single page without auto pagination", func(t *testing.T) {
+		fetch, requests := newPagedFetcher(1000)
+		queryParams := map[string]interface{}{"count": int64(10)}
+
+		collection, err := fetchCollection(
+			queryParams, map[string]interface{}{}, fetch)
+		require.NoError(t, err)
+
+		assert.Equal(t, 10, collection["count"])
+		assert.Equal(t, []map[string]interface{}{queryParams}, *requests)
+	})
+
+	t.Run("collects max_results records from skip", func(t *testing.T) {
+		fetch, requests := newPagedFetcher(1000)
+		queryParams := map[string]interface{}{
+			"from":  int64(1700000000),
+			"count": int64(10),
+			"skip":  int64(20),
+		}
+		pagination := map[string]interface{}{
+			"auto_paginate": true,
+			"max_results":   int64(250),
+		}
+
+		collection, err := fetchCollection(queryParams, pagination, fetch)
+		require.NoError(t, err)
+
+		assert.Equal(t, "collection", collection["entity"])
+		assert.Equal(t, 250, collection["count"])
+		items := collection["items"].([]interface{})
+		require.Len(t, items, 250)
+		assert.Equal(t, map[string]interface{}{"index": int64(20)}, items[0])
+		assert.Equal(t, map[string]interface{}{"index": int64(269)}, items[249])
+
+		require.Len(t, *requests, 3)
+		for i, want := range []struct{ count, skip int64 }{
+			{100, 20}, {100, 120}, {50, 220},
+		} {
+			assert.Equal(t, want.count, (*requests)[i]["count"])
+			assert.Equal(t, want.skip, (*requests)[i]["skip"])
+			assert.Equal(t, int64(1700000000), (*requests)[i]["from"])
+		}
+		assert.Equal(t, int64(10), queryParams["count"],
+			"query parameters should not be modified")
+	})
+
+	t.Run("stops when the records run out", func(t *testing.T) {
+		fetch, requests := newPagedFetcher(130)
+		pagination := map[string]interface{}{"auto_paginate": true}
+
+		collection, err := fetchCollection(
+			map[string]interface{}{}, pagination, fetch)
+		require.NoError(t, err)
+
+		assert.Equal(t, 130, collection["count"])
+		assert.Len(t, *requests, 2)
+	})
+
+	t.Run("defaults max_results", func(t *testing.T) {
+		fetch, requests := newPagedFetcher(10000)
+		pagination := map[string]interface{}{"auto_paginate": true}
+
+		collection, err := fetchCollection(
+			map[string]interface{}{}, pagination, fetch)
+		require.NoError(t, err)
+
+		assert.Equal(t, defaultMaxResults, collection["count"])
+		assert.Len(t, *requests, defaultMaxResults/maxPageSize)
+	})
+
+	t.Run("returns the error of a page", func(t *testing.T) {
+		fetch := func(
+			queryParams map[string]interface{},
+			extraHeaders map[string]string,
+		) (map[string]interface{}, error) {
+			return nil, errors.New("rate limited")
+		}
+		pagination := map[string]interface{}{"auto_paginate": true}
+
+		_, err := fetchCollection(map[string]interface{}{}, pagination, fetch)
+		assert.EqualError(t, err, "rate limited")
+	})
+}
+
+func TestValidator_ValidateAndAddAutoPagination(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      map[string]interface{}
+		want      map[string]interface{}
+		wantError bool
+	}{
+		{
+			name: "adds auto pagination parameters",
+			args: map[string]interface{}{
+				"auto_paginate": true,
+				"max_results":   float64(300),
+			},
+			want: map[string]interface{}{
+				"auto_paginate": true,
+				"max_results":   int64(300),
+			},
+		},
+		{
+			name: "leaves out missing parameters",
+			args: map[string]interface{}{},
+			want: map[string]interface{}{},
+		},
+		{
+			name:      "rejects max_results above the cap",
+			args:      map[string]interface{}{"max_results": float64(1001)},
+			wantError: true,
+		},
+		{
+			name:      "rejects non positive max_results",
+			args:      map[string]interface{}{"max_results": float64(0)},
+			wantError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			request := createMCPRequest(tc.args)
+			params := make(map[string]interface{})
+
+			validator := NewValidator(&request).
+				ValidateAndAddAutoPagination(params)
+
+			assert.Equal(t, tc.wantError, validator.HasErrors())
+			if !tc.wantError {
+				assert.Equal(t, tc.want, params)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package razorpay
+
+import (
+	"context"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
+)
+
+// MerchantAccountHeader is the header partner requests name the
+// sub-merchant account they are made on behalf of in
+const MerchantAccountHeader = "X-Razorpay-Account"
+
+// withMerchantAccount returns a client that makes requests on behalf of
+// the sub-merchant account recorded in ctx, or the client itself if ctx
+// records none
+func withMerchantAccount(
+	ctx context.Context,
+	client *rzpsdk.Client,
+) *rzpsdk.Client {
+	account := contextkey.MerchantAccountFromContext(ctx)
+	if account == "" {
+		return client
+	}
+
+	headers := make(map[string]string, len(client.Request.Headers)+1)
+	for key, value := range client.Request.Headers {
+		headers[key] = value
+	}
+	headers[MerchantAccountHeader] = account
+
+	merchantClient := copyClient(client)
+	merchantClient.Request.Headers = headers
+	return merchantClient
+}
@@ -0,0 +1,102 @@
+package razorpay
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+)
+
+func TestWithMerchantAccount(t *testing.T) {
+	client := rzpsdk.NewClient("partner-key", "partner-secret")
+	client.AddHeaders(map[string]string{"X-Custom": "value"})
+
+	t.Run("keeps the client without an account", func(t *testing.T) {
+		assert.Same(t, client,
+			withMerchantAccount(context.Background(), client))
+	})
+
+	t.Run("names the account in a header", func(t *testing.T) {
+		ctx := contextkey.WithMerchantAccount(context.Background(),
+			"acc_Ef7ArAsdU5t0XL")
+
+		merchantClient := withMerchantAccount(ctx, client)
+		assert.Equal(t, map[string]string{
+			"X-Custom":            "value",
+			MerchantAccountHeader: "acc_Ef7ArAsdU5t0XL",
+		}, merchantClient.Request.Headers)
+		assert.Equal(t, "partner-key", merchantClient.Request.Auth.Key)
+		assert.NotContains(t, client.Request.Headers, MerchantAccountHeader)
+	})
+}
+
+func TestConfigPartner(t *testing.T) {
+	// The API returns the account each request was made on behalf of as
+	// the order id
+	apiServer := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"id": r.Header.Get(MerchantAccountHeader),
+			})
+		}))
+	defer apiServer.Close()
+
+	// fetchOrder calls fetch_order on a server with partner auth set as
+	// given, and returns the account the call was made on behalf of
+	fetchOrder := func(
+		t *testing.T,
+		partner bool,
+		args map[string]interface{},
+	) interface{} {
+		t.Helper()
+
+		client := rzpsdk.NewClient("partner-key", "partner-secret")
+		client.Request.BaseURL = apiServer.URL
+		server, err := NewRzpMcpServer(CreateTestObservability(), client,
+			[]string{"orders"}, nil, nil, false,
+			WithConfig(Config{Partner: partner}))
+		require.NoError(t, err)
+		impl, ok := server.(*mcpgo.Mark3labsImpl)
+		require.True(t, ok)
+
+		tool := impl.McpServer.GetTool("fetch_order")
+		require.NotNil(t, tool)
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := tool.Handler(context.Background(), request)
+		require.NoError(t, err)
+		require.False(t, result.IsError)
+
+		content, _ := result.StructuredContent.(map[string]interface{})
+		return content["id"]
+	}
+
+	t.Run("makes calls on behalf of the account", func(t *testing.T) {
+		assert.Equal(t, "acc_Ef7ArAsdU5t0XL", fetchOrder(t, true,
+			map[string]interface{}{
+				"order_id":            "order_1",
+				"merchant_account_id": "acc_Ef7ArAsdU5t0XL",
+			}))
+		assert.Equal(t, "", fetchOrder(t, true,
+			map[string]interface{}{"order_id": "order_1"}))
+	})
+
+	t.Run("ignores the account without partner auth", func(t *testing.T) {
+		assert.Equal(t, "", fetchOrder(t, false,
+			map[string]interface{}{
+				"order_id":            "order_1",
+				"merchant_account_id": "acc_Ef7ArAsdU5t0XL",
+			}))
+	})
+}
@@ -0,0 +1,177 @@
+package razorpay
+
+import (
+	"context"
+	"fmt"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+)
+
+// filterDowntimes keeps the downtimes in a collection response whose fields
+// match every given filter, and updates the count to match
+func filterDowntimes(
+	downtimes map[string]interface{},
+	filters map[string]interface{},
+) map[string]interface{} {
+	items, ok := downtimes["items"].([]interface{})
+	if !ok || len(filters) == 0 {
+		return downtimes
+	}
+
+	matched := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		downtime, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		keep := true
+		for field, value := range filters {
+			if downtime[field] != value {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			matched = append(matched, downtime)
+		}
+	}
+
+	downtimes["items"] = matched
+	downtimes["count"] = len(matched)
+	return downtimes
+}
+
+// FetchPaymentDowntimes returns a tool that fetches the downtimes reported
+// for payment methods
+func FetchPaymentDowntimes(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"method",
+			mcpgo.Description("Optional: Only return downtimes for this "+
+				"payment method"),
+			mcpgo.Enum("card", "netbanking", "upi", "wallet"),
+		),
+		mcpgo.WithString(
+			"status",
+			mcpgo.Description("Optional: Only return downtimes with this "+
+				"status. Use 'started' to find ongoing downtimes"),
+			mcpgo.Enum("scheduled", "started", "resolved", "cancelled"),
+		),
+		mcpgo.WithString(
+			"severity",
+			mcpgo.Description("Optional: Only return downtimes with this "+
+				"severity"),
+			mcpgo.Enum("high", "medium", "low"),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		filters := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddOptionalString(filters, "method").
+			ValidateAndAddOptionalString(filters, "status").
+			ValidateAndAddOptionalString(filters, "severity")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		downtimes, err := client.Payment.FetchPaymentDowntime(nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching payment downtimes failed: %s",
+					err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(filterDowntimes(downtimes, filters))
+	}
+
+	return mcpgo.NewTool(
+		"fetch_payment_downtimes",
+		"Fetch scheduled, ongoing and resolved downtimes of payment methods "+
+			"such as UPI, netbanking, cards and wallets. Check this before "+
+			"initiating or retrying a payment to avoid degraded methods, "+
+			"banks or card networks.",
+		parameters,
+		handler,
+	).WithOutputSchema(entitiesOutputSchema).
+		WithFieldSelection()
+}
+
+// FetchPaymentDowntimeByID returns a tool that fetches a payment downtime
+// by its ID
+func FetchPaymentDowntimeByID(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"downtime_id",
+			mcpgo.Description("ID of the downtime to be fetched "+
+				"(ID should have a down_ prefix)."),
+			mcpgo.Required(),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(fields, "downtime_id")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		downtimeID := fields["downtime_id"].(string)
+
+		if result := validateID("downtime_id", downtimeID,
+			downtimeIDPrefix); result != nil {
+			return result, nil
+		}
+
+		downtime, err := client.Payment.FetchPaymentDowntimeById(
+			downtimeID, nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching payment downtime failed: %s",
+					err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(downtime)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_payment_downtime_by_id",
+		"Fetch the details of a payment downtime, including the affected "+
+			"method and instrument, its severity and when it started and "+
+			"ended",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema).
+		WithFieldSelection()
+}
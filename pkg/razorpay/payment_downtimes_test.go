@@ -0,0 +1,201 @@
+package razorpay
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/razorpay/razorpay-go/constants"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay/mock"
+)
+
+var paymentDowntimesPath = fmt.Sprintf(
+	"/%s%s/downtimes",
+	constants.VERSION_V1,
+	constants.PAYMENT_URL,
+)
+
+var upiDowntime = map[string]interface{}{
+	"id":       "down_F7LroRQAAFuswd",
+	"entity":   "payment.downtime",
+	"method":   "upi",
+	"status":   "started",
+	"severity": "high",
+	"begin":    float64(1606945800),
+	"instrument": map[string]interface{}{
+		"psp": "googlepay",
+	},
+}
+
+var netbankingDowntime = map[string]interface{}{
+	"id":       "down_F1cxDoHWD4fkQt",
+	"entity":   "payment.downtime",
+	"method":   "netbanking",
+	"status":   "resolved",
+	"severity": "medium",
+	"begin":    float64(1606945300),
+	"end":      float64(1606945900),
+	"instrument": map[string]interface{}{
+		"bank": "SBIN",
+	},
+}
+
+func Test_FetchPaymentDowntimes(t *testing.T) {
+	downtimesResp := func() map[string]interface{} {
+		return map[string]interface{}{
+			"entity": "collection",
+			"count":  float64(2),
+			"items":  []interface{}{upiDowntime, netbankingDowntime},
+		}
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name:    "successful downtimes fetch",
+			Request: map[string]interface{}{},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     paymentDowntimesPath,
+						Method:   "GET",
+						Response: downtimesResp(),
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: downtimesResp(),
+		},
+		{
+			Name: "downtimes filtered by method and status",
+			Request: map[string]interface{}{
+				"method": "upi",
+				"status": "started",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     paymentDowntimesPath,
+						Method:   "GET",
+						Response: downtimesResp(),
+					},
+				)
+			},
+			ExpectError: false,
+			ExpectedResult: map[string]interface{}{
+				"entity": "collection",
+				"count":  float64(1),
+				"items":  []interface{}{upiDowntime},
+			},
+		},
+		{
+			Name: "no downtimes match the filters",
+			Request: map[string]interface{}{
+				"method": "card",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     paymentDowntimesPath,
+						Method:   "GET",
+						Response: downtimesResp(),
+					},
+				)
+			},
+			ExpectError: false,
+			ExpectedResult: map[string]interface{}{
+				"entity": "collection",
+				"count":  float64(0),
+				"items":  []interface{}{},
+			},
+		},
+		{
+			Name:    "downtimes fetch fails",
+			Request: map[string]interface{}{},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:   paymentDowntimesPath,
+						Method: "GET",
+						Response: map[string]interface{}{
+							"error": map[string]interface{}{
+								"code":        "BAD_REQUEST_ERROR",
+								"description": "Authentication failed",
+							},
+						},
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "fetching payment downtimes failed: " +
+				"Authentication failed",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchPaymentDowntimes, "Downtimes")
+		})
+	}
+}
+
+func Test_FetchPaymentDowntimeByID(t *testing.T) {
+	downtimePath := paymentDowntimesPath + "/down_F7LroRQAAFuswd"
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful downtime fetch",
+			Request: map[string]interface{}{
+				"downtime_id": "down_F7LroRQAAFuswd",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     downtimePath,
+						Method:   "GET",
+						Response: upiDowntime,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: upiDowntime,
+		},
+		{
+			Name: "downtime not found",
+			Request: map[string]interface{}{
+				"downtime_id": "down_F7LroRQAAFuswd",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:   downtimePath,
+						Method: "GET",
+						Response: map[string]interface{}{
+							"error": map[string]interface{}{
+								"code":        "BAD_REQUEST_ERROR",
+								"description": "The id provided does not exist",
+							},
+						},
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "fetching payment downtime failed: " +
+				"The id provided does not exist",
+		},
+		{
+			Name:           "missing downtime_id",
+			Request:        map[string]interface{}{},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: downtime_id",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchPaymentDowntimeByID, "Downtime")
+		})
+	}
+}
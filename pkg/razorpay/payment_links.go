@@ -149,7 +149,8 @@ func CreatePaymentLink(
 		"Create a new standard payment link in Razorpay with a specified amount",
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(entityOutputSchema).
+		WithAmounts("amount", "first_min_partial_amount")
 }
 
 // CreateUpiPaymentLink returns a tool that creates payment links in Razorpay
@@ -292,7 +293,8 @@ func CreateUpiPaymentLink(
 		"Create a new UPI payment link in Razorpay with a specified amount and additional options.", // nolint:lll
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(entityOutputSchema).
+		WithAmounts("amount", "first_min_partial_amount")
 }
 
 // FetchPaymentLink returns a tool that fetches payment link details using
@@ -331,6 +333,11 @@ func FetchPaymentLink(
 
 		paymentLinkId := fields["payment_link_id"].(string)
 
+		if result := validateID("payment_link_id", paymentLinkId,
+			paymentLinkIDPrefix); result != nil {
+			return result, nil
+		}
+
 		paymentLink, err := client.PaymentLink.Fetch(paymentLinkId, nil, nil)
 		if err != nil {
 			return mcpgo.NewToolResultError(
@@ -347,7 +354,8 @@ func FetchPaymentLink(
 			"The link could be of any type(standard or UPI)",
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(entityOutputSchema).
+		WithFieldSelection()
 }
 
 // ResendPaymentLinkNotification returns a tool that sends/resends notifications
@@ -394,6 +402,11 @@ func ResendPaymentLinkNotification(
 		paymentLinkId := fields["payment_link_id"].(string)
 		medium := fields["medium"].(string)
 
+		if result := validateID("payment_link_id", paymentLinkId,
+			paymentLinkIDPrefix); result != nil {
+			return result, nil
+		}
+
 		// Call the SDK function
 		response, err := client.PaymentLink.NotifyBy(paymentLinkId, medium, nil, nil)
 		if err != nil {
@@ -409,7 +422,7 @@ func ResendPaymentLinkNotification(
 		"Send or resend notification for a payment link via SMS or email.", // nolint:lll
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(objectOutputSchema)
 }
 
 // UpdatePaymentLink returns a tool that updates an existing payment link
@@ -475,6 +488,11 @@ func UpdatePaymentLink(
 
 		paymentLinkId := otherFields["payment_link_id"].(string)
 
+		if result := validateID("payment_link_id", paymentLinkId,
+			paymentLinkIDPrefix); result != nil {
+			return result, nil
+		}
+
 		// Ensure we have at least one field to update
 		if len(plUpdateReq) == 0 {
 			return mcpgo.NewToolResultError(
@@ -497,7 +515,65 @@ func UpdatePaymentLink(
 			"expiry date, or notes.",
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(entityOutputSchema)
+}
+
+// CancelPaymentLink returns a tool that cancels a payment link
+func CancelPaymentLink(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"payment_link_id",
+			mcpgo.Description("ID of the payment link to cancel "+
+				"(ID should have a plink_ prefix)."),
+			mcpgo.Required(),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(fields, "payment_link_id")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		paymentLinkId := fields["payment_link_id"].(string)
+
+		if result := validateID("payment_link_id", paymentLinkId,
+			paymentLinkIDPrefix); result != nil {
+			return result, nil
+		}
+
+		paymentLink, err := client.PaymentLink.Cancel(paymentLinkId, nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("cancelling payment link failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(paymentLink)
+	}
+
+	return mcpgo.NewTool(
+		"cancel_payment_link",
+		"Cancel a standard or UPI payment link so that it can no longer be "+
+			"paid. Only links in the created or partially paid state can be "+
+			"cancelled.",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema)
 }
 
 // FetchAllPaymentLinks returns a tool that fetches all payment links
@@ -559,5 +635,151 @@ func FetchAllPaymentLinks(
 			"You can specify the upi_link parameter to filter by link type.",
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(objectOutputSchema).
+		WithFieldSelection()
+}
+
+// upiTransactionScanPageSize and upiTransactionScanMaxPages bound the
+// number of payments scanned while looking up a UPI transaction
+const (
+	upiTransactionScanPageSize = 100
+	upiTransactionScanMaxPages = 10
+)
+
+// FindPaymentLinkByUPITransaction returns a tool that locates the payment
+// link and payment associated with a UPI transaction ID
+func FindPaymentLinkByUPITransaction(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"upi_transaction_id",
+			mcpgo.Description("UPI transaction ID (UTR) of the payment, as "+
+				"shown in the customer's UPI app or the bank statement"),
+			mcpgo.Required(),
+		),
+		mcpgo.WithNumber(
+			"from",
+			mcpgo.Description("Optional: Unix timestamp (in seconds) from when "+
+				"payments are to be searched"),
+			mcpgo.Min(0),
+		),
+		mcpgo.WithNumber(
+			"to",
+			mcpgo.Description("Optional: Unix timestamp (in seconds) up till "+
+				"when payments are to be searched"),
+			mcpgo.Min(0),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+		paymentListReq := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(fields, "upi_transaction_id").
+			ValidateAndAddOptionalInt(paymentListReq, "from").
+			ValidateAndAddOptionalInt(paymentListReq, "to")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		upiTransactionID := fields["upi_transaction_id"].(string)
+
+		payment, err := findPaymentByUPITransaction(
+			client, paymentListReq, upiTransactionID)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching payments failed: %s", err.Error())), nil
+		}
+		if payment == nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("no payment found for UPI transaction ID %s",
+					upiTransactionID)), nil
+		}
+
+		paymentID, _ := payment["id"].(string)
+		response, err := client.PaymentLink.All(
+			map[string]interface{}{"payment_id": paymentID}, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching payment links failed: %s", err.Error())), nil
+		}
+
+		links, _ := response["payment_links"].([]interface{})
+		if len(links) == 0 {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("payment %s for UPI transaction ID %s is not "+
+					"associated with a payment link",
+					paymentID, upiTransactionID)), nil
+		}
+
+		return mcpgo.NewToolResultJSON(map[string]interface{}{
+			"upi_transaction_id": upiTransactionID,
+			"payment":            payment,
+			"payment_link":       links[0],
+		})
+	}
+
+	return mcpgo.NewTool(
+		"fetch_payment_link_upi_transaction",
+		"Find the payment link and payment associated with a UPI transaction "+
+			"ID, for reconciling UPI payment link collections. Narrow the "+
+			"search with from and to when the payment is not recent.",
+		parameters,
+		handler,
+	).WithOutputSchema(objectOutputSchema).
+		WithFieldSelection()
+}
+
+// findPaymentByUPITransaction pages through the payments matching the list
+// request and returns the one with the given UPI transaction ID, or nil if
+// none of the scanned payments match
+func findPaymentByUPITransaction(
+	client *rzpsdk.Client,
+	listReq map[string]interface{},
+	upiTransactionID string,
+) (map[string]interface{}, error) {
+	listReq["count"] = upiTransactionScanPageSize
+
+	for page := 0; page < upiTransactionScanMaxPages; page++ {
+		listReq["skip"] = page * upiTransactionScanPageSize
+
+		payments, err := client.Payment.All(listReq, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		items, _ := payments["items"].([]interface{})
+		for _, item := range items {
+			payment, ok := item.(map[string]interface{})
+			if ok && paymentUPITransactionID(payment) == upiTransactionID {
+				return payment, nil
+			}
+		}
+
+		if len(items) < upiTransactionScanPageSize {
+			break
+		}
+	}
+
+	return nil, nil
+}
+
+// paymentUPITransactionID returns the UPI transaction ID of a payment,
+// which is reported as part of the acquirer data
+func paymentUPITransactionID(payment map[string]interface{}) string {
+	acquirerData, _ := payment["acquirer_data"].(map[string]interface{})
+	upiTransactionID, _ := acquirerData["upi_transaction_id"].(string)
+	return upiTransactionID
 }
@@ -571,6 +571,85 @@ func Test_UpdatePaymentLink(t *testing.T) {
 	}
 }
 
+func Test_CancelPaymentLink(t *testing.T) {
+	cancelPaymentLinkPathFmt := fmt.Sprintf(
+		"/%s%s/%%s/cancel",
+		constants.VERSION_V1,
+		constants.PaymentLink_URL,
+	)
+
+	cancelledPaymentLinkResp := map[string]interface{}{
+		"id":       "plink_FL5HCrWEO112OW",
+		"amount":   float64(1000),
+		"currency": "INR",
+		"status":   "cancelled",
+	}
+
+	invalidStateResp := map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":        "BAD_REQUEST_ERROR",
+			"description": "Payment link cannot be cancelled in paid state",
+		},
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful cancellation",
+			Request: map[string]interface{}{
+				"payment_link_id": "plink_FL5HCrWEO112OW",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path: fmt.Sprintf(
+							cancelPaymentLinkPathFmt,
+							"plink_FL5HCrWEO112OW",
+						),
+						Method:   "POST",
+						Response: cancelledPaymentLinkResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: cancelledPaymentLinkResp,
+		},
+		{
+			Name:           "missing payment_link_id parameter",
+			Request:        map[string]interface{}{},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: payment_link_id",
+		},
+		{
+			Name: "cancelling a paid payment link",
+			Request: map[string]interface{}{
+				"payment_link_id": "plink_FL5HCrWEO112OW",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path: fmt.Sprintf(
+							cancelPaymentLinkPathFmt,
+							"plink_FL5HCrWEO112OW",
+						),
+						Method:   "POST",
+						Response: invalidStateResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "cancelling payment link failed: " +
+				"Payment link cannot be cancelled in paid state",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, CancelPaymentLink, "Payment Link Cancel")
+		})
+	}
+}
+
 func Test_FetchAllPaymentLinks(t *testing.T) {
 	fetchAllPaymentLinksPath := fmt.Sprintf(
 		"/%s%s",
@@ -650,3 +729,146 @@ func Test_FetchAllPaymentLinks(t *testing.T) {
 		})
 	}
 }
+
+func Test_FindPaymentLinkByUPITransaction(t *testing.T) {
+	fetchAllPaymentsPath := fmt.Sprintf(
+		"/%s%s",
+		constants.VERSION_V1,
+		constants.PAYMENT_URL,
+	)
+	fetchAllPaymentLinksPath := fmt.Sprintf(
+		"/%s%s",
+		constants.VERSION_V1,
+		constants.PaymentLink_URL,
+	)
+
+	upiPayment := map[string]interface{}{
+		"id":     "pay_UPI000000001",
+		"entity": "payment",
+		"amount": float64(10000),
+		"status": "captured",
+		"method": "upi",
+		"acquirer_data": map[string]interface{}{
+			"rrn":                "412345678901",
+			"upi_transaction_id": "AXI4a3b2c1d0e9f8g7h6",
+		},
+	}
+
+	paymentsResp := map[string]interface{}{
+		"entity": "collection",
+		"count":  float64(2),
+		"items": []interface{}{
+			map[string]interface{}{
+				"id":     "pay_CARD00000001",
+				"entity": "payment",
+				"method": "card",
+				"acquirer_data": map[string]interface{}{
+					"auth_code": "123456",
+				},
+			},
+			upiPayment,
+		},
+	}
+
+	paymentLink := map[string]interface{}{
+		"id":       "plink_UPI0000001",
+		"amount":   float64(10000),
+		"status":   "paid",
+		"upi_link": true,
+		"payments": []interface{}{
+			map[string]interface{}{
+				"payment_id": "pay_UPI000000001",
+				"method":     "upi",
+				"status":     "captured",
+			},
+		},
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "payment link found for UPI transaction",
+			Request: map[string]interface{}{
+				"upi_transaction_id": "AXI4a3b2c1d0e9f8g7h6",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     fetchAllPaymentsPath,
+						Method:   "GET",
+						Response: paymentsResp,
+					},
+					mock.Endpoint{
+						Path:   fetchAllPaymentLinksPath,
+						Method: "GET",
+						Response: map[string]interface{}{
+							"payment_links": []interface{}{paymentLink},
+						},
+					},
+				)
+			},
+			ExpectError: false,
+			ExpectedResult: map[string]interface{}{
+				"upi_transaction_id": "AXI4a3b2c1d0e9f8g7h6",
+				"payment":            upiPayment,
+				"payment_link":       paymentLink,
+			},
+		},
+		{
+			Name: "no payment for UPI transaction",
+			Request: map[string]interface{}{
+				"upi_transaction_id": "HDF0000000000000000",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     fetchAllPaymentsPath,
+						Method:   "GET",
+						Response: paymentsResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "no payment found for UPI transaction ID " +
+				"HDF0000000000000000",
+		},
+		{
+			Name: "payment not associated with a payment link",
+			Request: map[string]interface{}{
+				"upi_transaction_id": "AXI4a3b2c1d0e9f8g7h6",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     fetchAllPaymentsPath,
+						Method:   "GET",
+						Response: paymentsResp,
+					},
+					mock.Endpoint{
+						Path:   fetchAllPaymentLinksPath,
+						Method: "GET",
+						Response: map[string]interface{}{
+							"payment_links": []interface{}{},
+						},
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "payment pay_UPI000000001 for UPI transaction ID " +
+				"AXI4a3b2c1d0e9f8g7h6 is not associated with a payment link",
+		},
+		{
+			Name:           "missing upi_transaction_id",
+			Request:        map[string]interface{}{},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: upi_transaction_id",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(
+				t, tc, FindPaymentLinkByUPITransaction, "Payment Link Lookup")
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package razorpay
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+)
+
+// cardBINPattern matches the BIN (IIN) of a card, its first 6 digits. Only
+// the BIN is accepted, so that full card numbers are not sent or logged.
+var cardBINPattern = regexp.MustCompile(`^[0-9]{6}$`)
+
+// vpaPattern matches UPI IDs (VPAs), such as gaurav.kumar@exampleupi
+var vpaPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{2,256}@[A-Za-z]{2,64}$`)
+
+// emiPlans flattens the emi_plans of the payment methods response, keyed
+// by issuer, into a list of issuers sorted by code, each with its plans
+// sorted by duration. Issuers whose minimum amount is above amount are
+// left out when amount is set.
+func emiPlans(
+	methods map[string]interface{},
+	issuer string,
+	amount int64,
+) []interface{} {
+	byIssuer, _ := methods["emi_plans"].(map[string]interface{})
+
+	codes := make([]string, 0, len(byIssuer))
+	for code := range byIssuer {
+		if issuer == "" || strings.EqualFold(code, issuer) {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+
+	issuers := make([]interface{}, 0, len(codes))
+	for _, code := range codes {
+		details, _ := byIssuer[code].(map[string]interface{})
+		minAmount, _ := details["min_amount"].(float64)
+		if amount > 0 && int64(minAmount) > amount {
+			continue
+		}
+
+		rates, _ := details["plans"].(map[string]interface{})
+		plans := make([]interface{}, 0, len(rates))
+		for duration, rate := range rates {
+			months, err := strconv.Atoi(duration)
+			if err != nil {
+				continue
+			}
+			plans = append(plans, map[string]interface{}{
+				"duration":      months,
+				"interest_rate": rate,
+			})
+		}
+		sort.Slice(plans, func(i, j int) bool {
+			return plans[i].(map[string]interface{})["duration"].(int) <
+				plans[j].(map[string]interface{})["duration"].(int)
+		})
+
+		issuers = append(issuers, map[string]interface{}{
+			"issuer":     code,
+			"min_amount": details["min_amount"],
+			"plans":      plans,
+		})
+	}
+
+	return issuers
+}
+
+// FetchEmiPlans returns a tool that fetches the EMI plans offered on the
+// account, by card issuer
+func FetchEmiPlans(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"issuer",
+			mcpgo.Description("Optional: Only return the plans of this "+
+				"issuer, by its code, such as HDFC or ICIC"),
+		),
+		mcpgo.WithNumber(
+			"amount",
+			mcpgo.Description("Optional: Amount of the payment in paise. "+
+				"Only issuers that offer EMI on this amount are returned"),
+			mcpgo.Min(0),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		filters := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddOptionalString(filters, "issuer").
+			ValidateAndAddOptionalInt(filters, "amount")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		methods, err := client.Payment.FetchMethods(nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching EMI plans failed: %s", err.Error())), nil
+		}
+
+		issuer, _ := filters["issuer"].(string)
+		amount, _ := filters["amount"].(int64)
+		issuers := emiPlans(methods, issuer, amount)
+
+		return mcpgo.NewToolResultJSON(map[string]interface{}{
+			"emi_enabled": methods["emi"],
+			"count":       len(issuers),
+			"issuers":     issuers,
+		})
+	}
+
+	return mcpgo.NewTool(
+		"fetch_emi_plans",
+		"Fetch the card EMI plans offered on the account, with the "+
+			"durations in months, interest rates and minimum amount of each "+
+			"issuer. Use it to tell a customer which EMI options apply "+
+			"before a payment",
+		parameters,
+		handler,
+	).WithOutputSchema(objectOutputSchema).
+		WithFieldSelection()
+}
+
+// LookupCardBin returns a tool that looks up the network, type and issuer
+// of a card by its BIN
+func LookupCardBin(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"bin",
+			mcpgo.Description("The first 6 digits of the card number. Never "+
+				"pass the full card number"),
+			mcpgo.Required(),
+			mcpgo.Pattern(cardBINPattern.String()),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		params := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(params, "bin")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		bin := params["bin"].(string)
+		if !cardBINPattern.MatchString(bin) {
+			return mcpgo.NewToolResultError(
+				"bin must be the first 6 digits of the card number"), nil
+		}
+
+		iin, err := client.Iin.Fetch(bin, nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("looking up card BIN failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(iin)
+	}
+
+	return mcpgo.NewTool(
+		"lookup_card_bin",
+		"Look up a card by its BIN, the first 6 digits of the card number: "+
+			"its network, type, issuer, whether it is international, and "+
+			"whether it supports EMI and recurring payments",
+		parameters,
+		handler,
+	).WithOutputSchema(objectOutputSchema)
+}
+
+// ValidateVpa returns a tool that checks that a UPI ID (VPA) exists
+func ValidateVpa(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"vpa",
+			mcpgo.Description("The UPI ID (VPA) to validate, such as "+
+				"gaurav.kumar@exampleupi"),
+			mcpgo.Required(),
+			mcpgo.Pattern(vpaPattern.String()),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		params := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(params, "vpa")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		vpa := params["vpa"].(string)
+		if !vpaPattern.MatchString(vpa) {
+			return mcpgo.NewToolResultError(fmt.Sprintf(
+				"invalid vpa: %s, must be a UPI ID such as "+
+					"gaurav.kumar@exampleupi", vpa)), nil
+		}
+
+		validation, err := client.Payment.ValidateVpa(params, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("validating VPA failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(validation)
+	}
+
+	return mcpgo.NewTool(
+		"validate_vpa",
+		"Check that a customer's UPI ID (VPA) exists before sending a UPI "+
+			"collect request with initiate_payment, and get the name of its "+
+			"holder to confirm it with the customer. The call fails, or "+
+			"success is false, for a VPA that does not exist",
+		parameters,
+		handler,
+	).WithOutputSchema(objectOutputSchema)
+}
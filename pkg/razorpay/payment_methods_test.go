@@ -0,0 +1,243 @@
+package razorpay
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/razorpay/razorpay-go/constants"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay/mock"
+)
+
+func Test_FetchEmiPlans(t *testing.T) {
+	methodsPath := fmt.Sprintf("/%s%s", constants.VERSION_V1,
+		constants.METHODS_URL)
+
+	methodsResp := map[string]interface{}{
+		"entity": "methods",
+		"card":   true,
+		"emi":    true,
+		"emi_plans": map[string]interface{}{
+			"UTIB": map[string]interface{}{
+				"min_amount": float64(300000),
+				"plans": map[string]interface{}{
+					"12": float64(14),
+					"3":  float64(12),
+				},
+			},
+			"HDFC": map[string]interface{}{
+				"min_amount": float64(500000),
+				"plans": map[string]interface{}{
+					"6": float64(13),
+				},
+			},
+		},
+	}
+
+	methodsClient := func() (*http.Client, *httptest.Server) {
+		return mock.NewHTTPClient(
+			mock.Endpoint{
+				Path:     methodsPath,
+				Method:   "GET",
+				Response: methodsResp,
+			},
+		)
+	}
+
+	utibPlans := map[string]interface{}{
+		"issuer":     "UTIB",
+		"min_amount": float64(300000),
+		"plans": []interface{}{
+			map[string]interface{}{
+				"duration":      float64(3),
+				"interest_rate": float64(12),
+			},
+			map[string]interface{}{
+				"duration":      float64(12),
+				"interest_rate": float64(14),
+			},
+		},
+	}
+	hdfcPlans := map[string]interface{}{
+		"issuer":     "HDFC",
+		"min_amount": float64(500000),
+		"plans": []interface{}{
+			map[string]interface{}{
+				"duration":      float64(6),
+				"interest_rate": float64(13),
+			},
+		},
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name:           "all issuers",
+			Request:        map[string]interface{}{},
+			MockHttpClient: methodsClient,
+			ExpectError:    false,
+			ExpectedResult: map[string]interface{}{
+				"emi_enabled": true,
+				"count":       float64(2),
+				"issuers":     []interface{}{hdfcPlans, utibPlans},
+			},
+		},
+		{
+			Name:           "issuer filter",
+			Request:        map[string]interface{}{"issuer": "utib"},
+			MockHttpClient: methodsClient,
+			ExpectError:    false,
+			ExpectedResult: map[string]interface{}{
+				"emi_enabled": true,
+				"count":       float64(1),
+				"issuers":     []interface{}{utibPlans},
+			},
+		},
+		{
+			Name:           "amount below the minimum of an issuer",
+			Request:        map[string]interface{}{"amount": float64(400000)},
+			MockHttpClient: methodsClient,
+			ExpectError:    false,
+			ExpectedResult: map[string]interface{}{
+				"emi_enabled": true,
+				"count":       float64(1),
+				"issuers":     []interface{}{utibPlans},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchEmiPlans, "EMI plans")
+		})
+	}
+}
+
+func Test_LookupCardBin(t *testing.T) {
+	iinPath := fmt.Sprintf("/%s%s/%s", constants.VERSION_V1, constants.IIN,
+		"412345")
+
+	iinResp := map[string]interface{}{
+		"iin":           "412345",
+		"entity":        "iin",
+		"network":       "Visa",
+		"type":          "credit",
+		"issuer_code":   "HDFC",
+		"issuer_name":   "HDFC Bank",
+		"international": false,
+		"emi": map[string]interface{}{
+			"available": true,
+		},
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name:    "successful lookup",
+			Request: map[string]interface{}{"bin": "412345"},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     iinPath,
+						Method:   "GET",
+						Response: iinResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: iinResp,
+		},
+		{
+			Name:           "full card number",
+			Request:        map[string]interface{}{"bin": "4111111111111111"},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "bin must be the first 6 digits of the card " +
+				"number",
+		},
+		{
+			Name:           "missing bin",
+			Request:        map[string]interface{}{},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: bin",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, LookupCardBin, "Card BIN")
+		})
+	}
+}
+
+func Test_ValidateVpa(t *testing.T) {
+	validateVpaPath := fmt.Sprintf("/%s%s/validate/vpa",
+		constants.VERSION_V1, constants.PAYMENT_URL)
+
+	validResp := map[string]interface{}{
+		"vpa":           "gaurav.kumar@exampleupi",
+		"success":       true,
+		"customer_name": "Gaurav Kumar",
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name:    "existing vpa",
+			Request: map[string]interface{}{"vpa": "gaurav.kumar@exampleupi"},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     validateVpaPath,
+						Method:   "POST",
+						Response: validResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: validResp,
+		},
+		{
+			Name:    "vpa that does not exist",
+			Request: map[string]interface{}{"vpa": "nobody@exampleupi"},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:   validateVpaPath,
+						Method: "POST",
+						Response: map[string]interface{}{
+							"error": map[string]interface{}{
+								"code": "BAD_REQUEST_ERROR",
+								"description": "Invalid VPA. Please enter a " +
+									"valid Virtual Payment Address",
+							},
+						},
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "validating VPA failed: Invalid VPA. Please " +
+				"enter a valid Virtual Payment Address",
+		},
+		{
+			Name:           "malformed vpa",
+			Request:        map[string]interface{}{"vpa": "9876543210"},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid vpa: 9876543210",
+		},
+		{
+			Name:           "missing vpa",
+			Request:        map[string]interface{}{},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: vpa",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, ValidateVpa, "VPA")
+		})
+	}
+}
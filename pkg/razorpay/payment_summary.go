@@ -0,0 +1,279 @@
+package razorpay
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+)
+
+// Limits of payment and refund summaries
+const (
+	// maxSummaryDays is the longest window a summary may span
+	maxSummaryDays = 31
+	// maxSummaryPayments is the most payments a summary looks at
+	maxSummaryPayments = 2000
+	// maxSummaryRefunds is the most refunds a summary looks at
+	maxSummaryRefunds = 2000
+)
+
+// summaryTotal is the number and amount of a group of payments or refunds
+type summaryTotal struct {
+	Count  int   `json:"count"`
+	Amount int64 `json:"amount"`
+}
+
+// add counts a payment or refund of the amount
+func (t *summaryTotal) add(amount int64) {
+	t.Count++
+	t.Amount += amount
+}
+
+// paymentDay holds the payments created on a day, in IST
+type paymentDay struct {
+	Date     string       `json:"date"`
+	Count    int          `json:"count"`
+	Captured summaryTotal `json:"captured"`
+	Failed   int          `json:"failed"`
+}
+
+// paymentSummary aggregates the payments of a window
+type paymentSummary struct {
+	From     int64  `json:"from"`
+	To       int64  `json:"to"`
+	Currency string `json:"currency"`
+	Count    int    `json:"count"`
+	// OtherCurrencies counts the payments in other currencies, which are
+	// left out of the summary
+	OtherCurrencies   int                      `json:"other_currencies"`
+	ByStatus          map[string]*summaryTotal `json:"by_status"`
+	ByMethod          map[string]*summaryTotal `json:"by_method"`
+	Captured          summaryTotal             `json:"captured"`
+	AverageTicketSize int64                    `json:"average_ticket_size"`
+	FailureRate       float64                  `json:"failure_rate"`
+	Daily             []*paymentDay            `json:"daily"`
+	Truncated         bool                     `json:"truncated"`
+}
+
+// SummarizePayments returns a tool that aggregates the payments of a
+// window by status, method and day
+func SummarizePayments(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := summaryWindowParameters("payments")
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		window := make(map[string]interface{})
+		params := map[string]interface{}{"currency": "INR"}
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredInt(window, "from").
+			ValidateAndAddRequiredInt(window, "to").
+			ValidateAndAddOptionalString(params, "currency")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		from, to := window["from"].(int64), window["to"].(int64)
+		if result := checkSummaryWindow(from, to); result != nil {
+			return result, nil
+		}
+
+		// One payment more than a summary looks at tells whether any were
+		// left out
+		payments, _, err := scanPages(window, maxSummaryPayments+1,
+			maxSummaryPayments+1, nil, client.Payment.All)
+		if err != nil {
+			return mcpgo.NewToolResultError(fmt.Sprintf(
+				"fetching payments failed: %s", err.Error())), nil
+		}
+		truncated := len(payments) > maxSummaryPayments
+		if truncated {
+			payments = payments[:maxSummaryPayments]
+		}
+
+		summary := summarizePayments(payments, from, to,
+			params["currency"].(string))
+		summary.Truncated = truncated
+
+		return mcpgo.NewToolResultJSON(summary)
+	}
+
+	return mcpgo.NewTool(
+		"summarize_payments",
+		fmt.Sprintf("Summarize the payments created in a window of up to "+
+			"%d days, instead of fetching them: the number and amount of "+
+			"payments by status and by method, the captured total, the "+
+			"average ticket size of captured payments, the failure rate of "+
+			"completed attempts, and a daily series in IST. Amounts are in "+
+			"currency subunits. At most %d payments, the newest, are "+
+			"looked at, and truncated is set when more were created",
+			maxSummaryDays, maxSummaryPayments),
+		parameters,
+		handler,
+	).WithOutputSchema(objectOutputSchema).
+		WithTimeout(scanToolTimeout)
+}
+
+// summarizePayments aggregates the payments in the currency. Refunded
+// payments were captured, so they count as captured. The failure rate is
+// the share of failed payments among the payments that failed or got
+// authorized, leaving out payments the customer did not complete.
+func summarizePayments(
+	payments []interface{},
+	from, to int64,
+	currency string,
+) *paymentSummary {
+	summary := &paymentSummary{
+		From:     from,
+		To:       to,
+		Currency: currency,
+		ByStatus: make(map[string]*summaryTotal),
+		ByMethod: make(map[string]*summaryTotal),
+		Daily:    summaryDays(from, to),
+	}
+	days := make(map[string]*paymentDay, len(summary.Daily))
+	for _, day := range summary.Daily {
+		days[day.Date] = day
+	}
+
+	failed, completed := 0, 0
+	for _, item := range payments {
+		payment, _ := item.(map[string]interface{})
+		if stringField(payment, "currency") != currency {
+			summary.OtherCurrencies++
+			continue
+		}
+
+		amountValue, _ := payment["amount"].(float64)
+		amount := int64(amountValue)
+		status := stringField(payment, "status")
+		summary.Count++
+		addSummaryTotal(summary.ByStatus, status, amount)
+		addSummaryTotal(summary.ByMethod, stringField(payment, "method"),
+			amount)
+
+		createdAt, _ := payment["created_at"].(float64)
+		day := days[time.Unix(int64(createdAt), 0).In(settlementLocation).
+			Format(time.DateOnly)]
+		if day != nil {
+			day.Count++
+		}
+
+		switch status {
+		case "captured", "refunded":
+			summary.Captured.add(amount)
+			if day != nil {
+				day.Captured.add(amount)
+			}
+			completed++
+		case "authorized":
+			completed++
+		case "failed":
+			if day != nil {
+				day.Failed++
+			}
+			failed++
+			completed++
+		}
+	}
+
+	if summary.Captured.Count > 0 {
+		summary.AverageTicketSize = int64(math.Round(
+			float64(summary.Captured.Amount) /
+				float64(summary.Captured.Count)))
+	}
+	if completed > 0 {
+		summary.FailureRate = math.Round(
+			float64(failed)/float64(completed)*10000) / 10000
+	}
+
+	return summary
+}
+
+// addSummaryTotal counts a payment or refund in the total of its group,
+// "unknown" if it has none
+func addSummaryTotal(
+	totals map[string]*summaryTotal,
+	group string,
+	amount int64,
+) {
+	if group == "" {
+		group = "unknown"
+	}
+	if totals[group] == nil {
+		totals[group] = &summaryTotal{}
+	}
+	totals[group].add(amount)
+}
+
+// summaryWindowParameters returns the parameters of the window and the
+// currency of a summary of the entities
+func summaryWindowParameters(entities string) []mcpgo.ToolParameter {
+	return []mcpgo.ToolParameter{
+		mcpgo.WithNumber(
+			"from",
+			mcpgo.Description("Unix timestamp (in seconds) of the start of "+
+				"the window"),
+			mcpgo.Required(),
+			mcpgo.Min(0),
+		),
+		mcpgo.WithNumber(
+			"to",
+			mcpgo.Description(fmt.Sprintf("Unix timestamp (in seconds) of "+
+				"the end of the window, at most %d days after from",
+				maxSummaryDays)),
+			mcpgo.Required(),
+			mcpgo.Min(0),
+		),
+		mcpgo.WithString(
+			"currency",
+			mcpgo.Description(fmt.Sprintf("ISO code of the currency of the "+
+				"%s to summarize, others are only counted (default: INR)",
+				entities)),
+			mcpgo.Pattern("^[A-Z]{3}$"),
+		),
+	}
+}
+
+// checkSummaryWindow returns the error result of a window that is reversed
+// or longer than a summary may span, nil if the window is valid
+func checkSummaryWindow(from, to int64) *mcpgo.ToolResult {
+	if to < from {
+		return mcpgo.NewToolResultError("to must not be before from")
+	}
+	if to-from > maxSummaryDays*24*60*60 {
+		return mcpgo.NewToolResultError(fmt.Sprintf(
+			"window must not span more than %d days", maxSummaryDays))
+	}
+	return nil
+}
+
+// summaryDays returns the days of the window, in IST, in order
+func summaryDays(from, to int64) []*paymentDay {
+	first := time.Unix(from, 0).In(settlementLocation)
+	last := time.Unix(to, 0).In(settlementLocation)
+	first = time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0,
+		settlementLocation)
+
+	var days []*paymentDay
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		days = append(days, &paymentDay{Date: day.Format(time.DateOnly)})
+	}
+	return days
+}
@@ -0,0 +1,175 @@
+package razorpay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/razorpay/razorpay-go/constants"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay/mock"
+)
+
+func Test_SummarizePayments(t *testing.T) {
+	paymentsPath := fmt.Sprintf("/%s%s", constants.VERSION_V1,
+		constants.PAYMENT_URL)
+
+	// payment returns a payment created at the time, in INR
+	payment := func(
+		status, method string,
+		amount, createdAt float64,
+	) map[string]interface{} {
+		return map[string]interface{}{
+			"id": "pay_" + status, "status": status, "method": method,
+			"amount": amount, "currency": "INR", "created_at": createdAt,
+		}
+	}
+
+	// A window from 2024-01-15 to 2024-01-16 in IST
+	window := map[string]interface{}{
+		"from": float64(1705257000),
+		"to":   float64(1705429799),
+	}
+
+	t.Run("aggregates payments by status, method and day", func(t *testing.T) {
+		client, server := newMockRzpClient(func() (
+			*http.Client, *httptest.Server) {
+			return mock.NewHTTPClient(
+				mock.Endpoint{Path: paymentsPath, Method: "GET",
+					Response: collectionOf(
+						payment("captured", "upi", 1000, 1705300000),
+						payment("captured", "card", 3000, 1705400000),
+						payment("refunded", "upi", 2000, 1705400000),
+						payment("failed", "card", 4000, 1705400000),
+						payment("created", "upi", 5000, 1705400000),
+						map[string]interface{}{
+							"id": "pay_usd", "status": "captured",
+							"amount": float64(100), "currency": "USD",
+						},
+					)},
+			)
+		})
+		defer server.Close()
+
+		tool := SummarizePayments(CreateTestObservability(), client)
+		result, err := tool.GetHandler()(
+			context.Background(), createMCPRequest(window))
+		require.NoError(t, err)
+		require.False(t, result.IsError, result.Text)
+
+		var summary map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(result.Text), &summary))
+
+		assert.Equal(t, "INR", summary["currency"])
+		assert.Equal(t, float64(5), summary["count"])
+		assert.Equal(t, float64(1), summary["other_currencies"])
+		assert.Equal(t, map[string]interface{}{
+			"captured": map[string]interface{}{
+				"count": float64(2), "amount": float64(4000),
+			},
+			"refunded": map[string]interface{}{
+				"count": float64(1), "amount": float64(2000),
+			},
+			"failed": map[string]interface{}{
+				"count": float64(1), "amount": float64(4000),
+			},
+			"created": map[string]interface{}{
+				"count": float64(1), "amount": float64(5000),
+			},
+		}, summary["by_status"])
+		assert.Equal(t, map[string]interface{}{
+			"upi": map[string]interface{}{
+				"count": float64(3), "amount": float64(8000),
+			},
+			"card": map[string]interface{}{
+				"count": float64(2), "amount": float64(7000),
+			},
+		}, summary["by_method"])
+		assert.Equal(t, map[string]interface{}{
+			"count": float64(3), "amount": float64(6000),
+		}, summary["captured"])
+		assert.Equal(t, float64(2000), summary["average_ticket_size"])
+		assert.Equal(t, 0.25, summary["failure_rate"])
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{
+				"date":  "2024-01-15",
+				"count": float64(1),
+				"captured": map[string]interface{}{
+					"count": float64(1), "amount": float64(1000),
+				},
+				"failed": float64(0),
+			},
+			map[string]interface{}{
+				"date":  "2024-01-16",
+				"count": float64(4),
+				"captured": map[string]interface{}{
+					"count": float64(2), "amount": float64(5000),
+				},
+				"failed": float64(1),
+			},
+		}, summary["daily"])
+		assert.Equal(t, false, summary["truncated"])
+	})
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name:           "missing window",
+			Request:        map[string]interface{}{"from": float64(1705257000)},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: to",
+		},
+		{
+			Name: "reversed window",
+			Request: map[string]interface{}{
+				"from": float64(1705429799),
+				"to":   float64(1705257000),
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "to must not be before from",
+		},
+		{
+			Name: "window too long",
+			Request: map[string]interface{}{
+				"from": float64(1705257000),
+				"to":   float64(1705257000 + 32*24*60*60),
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "window must not span more than 31 days",
+		},
+		{
+			Name:    "api error",
+			Request: window,
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:   paymentsPath,
+						Method: "GET",
+						Response: map[string]interface{}{
+							"error": map[string]interface{}{
+								"code":        "BAD_REQUEST_ERROR",
+								"description": "invalid request",
+							},
+						},
+					},
+				)
+			},
+			ExpectError:    true,
+			ExpectedErrMsg: "fetching payments failed: invalid request",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, SummarizePayments, "Payment summary")
+		})
+	}
+}
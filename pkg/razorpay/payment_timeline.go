@@ -0,0 +1,301 @@
+package razorpay
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+)
+
+// Limits of payment timelines
+const (
+	// maxTimelineRecords is the most refunds, disputes, and settlement
+	// recon entries of a day, a timeline looks at, each
+	maxTimelineRecords = 1000
+	// maxTimelineSettlementDays is how many days after a payment was made
+	// a timeline looks for its settlement
+	maxTimelineSettlementDays = 7
+)
+
+// Settlement statuses of payment timelines
+const (
+	// timelineNotCaptured is the settlement status of payments that were
+	// not captured, which do not settle
+	timelineNotCaptured = "not_captured"
+	// timelineSettled is the settlement status of payments in a
+	// settlement recon report
+	timelineSettled = "settled"
+	// timelinePending is the settlement status of recent captured payments
+	// that are not settled yet
+	timelinePending = "pending"
+	// timelineNotFound is the settlement status of captured payments that
+	// are not in the recon reports of the days after they were made
+	timelineNotFound = "not_found"
+)
+
+// timelineEvent is a step in the history of a payment
+type timelineEvent struct {
+	At int64 `json:"at"`
+	// Time is At in IST, for reading
+	Time     string      `json:"time"`
+	Event    string      `json:"event"`
+	EntityID string      `json:"entity_id"`
+	Status   string      `json:"status,omitempty"`
+	Amount   interface{} `json:"amount,omitempty"`
+	Detail   string      `json:"detail,omitempty"`
+}
+
+// timelineSettlement is the settlement status of a payment
+type timelineSettlement struct {
+	Status       string `json:"status"`
+	SettlementID string `json:"settlement_id,omitempty"`
+	SettledAt    int64  `json:"settled_at,omitempty"`
+	UTR          string `json:"utr,omitempty"`
+}
+
+// paymentTimeline is what happened to a payment, and the entities it
+// involves
+type paymentTimeline struct {
+	PaymentID  string                 `json:"payment_id"`
+	Status     string                 `json:"status"`
+	Payment    map[string]interface{} `json:"payment"`
+	Order      map[string]interface{} `json:"order,omitempty"`
+	Refunds    []interface{}          `json:"refunds"`
+	Disputes   []interface{}          `json:"disputes"`
+	Settlement timelineSettlement     `json:"settlement"`
+	Timeline   []*timelineEvent       `json:"timeline"`
+	Truncated  bool                   `json:"truncated"`
+}
+
+// FetchPaymentTimeline returns a tool that composes a payment, its order,
+// refunds, disputes and settlement into one chronological view
+func FetchPaymentTimeline(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"payment_id",
+			mcpgo.Description("Unique identifier of the payment, starting "+
+				"with 'pay_'"),
+			mcpgo.Required(),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		params := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(params, "payment_id")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		paymentID := params["payment_id"].(string)
+		if result := validateID("payment_id", paymentID,
+			paymentIDPrefix); result != nil {
+			return result, nil
+		}
+
+		payment, err := client.Payment.Fetch(paymentID, nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(fmt.Sprintf(
+				"fetching payment failed: %s", err.Error())), nil
+		}
+
+		timeline := &paymentTimeline{
+			PaymentID: paymentID,
+			Status:    stringField(payment, "status"),
+			Payment:   payment,
+		}
+
+		if orderID := stringField(payment, "order_id"); orderID != "" {
+			timeline.Order, err = client.Order.Fetch(orderID, nil, nil)
+			if err != nil {
+				return mcpgo.NewToolResultError(fmt.Sprintf(
+					"fetching order failed: %s", err.Error())), nil
+			}
+		}
+
+		refunds, refundScan, err := scanPages(map[string]interface{}{},
+			maxTimelineRecords, maxTimelineRecords, nil,
+			func(query map[string]interface{}, headers map[string]string) (
+				map[string]interface{}, error) {
+				return client.Payment.FetchMultipleRefund(paymentID, query,
+					headers)
+			})
+		if err != nil {
+			return mcpgo.NewToolResultError(fmt.Sprintf(
+				"fetching refunds failed: %s", err.Error())), nil
+		}
+		timeline.Refunds = refunds
+
+		// Disputes can only be listed by time, and are raised after the
+		// payment was made
+		createdAt, _ := payment["created_at"].(float64)
+		disputes, disputeScan, err := scanPages(map[string]interface{}{
+			"from": int64(createdAt),
+		}, maxTimelineRecords, maxTimelineRecords,
+			func(dispute map[string]interface{}) bool {
+				return stringField(dispute, "payment_id") == paymentID
+			}, client.Dispute.All)
+		if err != nil {
+			return mcpgo.NewToolResultError(fmt.Sprintf(
+				"fetching disputes failed: %s", err.Error())), nil
+		}
+		timeline.Disputes = disputes
+		timeline.Truncated = !refundScan.exhausted || !disputeScan.exhausted
+
+		settlement, truncated, err := findPaymentSettlement(client, payment)
+		if err != nil {
+			return mcpgo.NewToolResultError(fmt.Sprintf(
+				"fetching settlement reconciliation report failed: %s",
+				err.Error())), nil
+		}
+		timeline.Settlement = settlement
+		timeline.Truncated = timeline.Truncated || truncated
+
+		timeline.Timeline = timelineEvents(timeline)
+
+		return mcpgo.NewToolResultJSON(timeline)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_payment_timeline",
+		fmt.Sprintf("Find out what happened to a payment in one call: the "+
+			"payment, its order, refunds, disputes and settlement status, "+
+			"and a timeline of their events in chronological order, with "+
+			"times in IST. The settlement is looked up in the settlement "+
+			"reconciliation reports of the %d days after the payment was "+
+			"made: not_captured payments do not settle, pending ones may "+
+			"still settle, and not_found ones did not settle in that time. "+
+			"At most %d refunds and disputes are looked at, and truncated "+
+			"is set when more may exist",
+			maxTimelineSettlementDays, maxTimelineRecords),
+		parameters,
+		handler,
+	).WithOutputSchema(objectOutputSchema).
+		WithTimeout(scanToolTimeout).
+		WithFieldSelection()
+}
+
+// findPaymentSettlement returns the settlement status of the payment, and
+// whether a recon report was too long to look through
+func findPaymentSettlement(
+	client *rzpsdk.Client,
+	payment map[string]interface{},
+) (timelineSettlement, bool, error) {
+	captured, _ := payment["captured"].(bool)
+	if !captured {
+		return timelineSettlement{Status: timelineNotCaptured}, false, nil
+	}
+
+	paymentID := stringField(payment, "id")
+	createdAt, _ := payment["created_at"].(float64)
+	first := startOfDay(time.Unix(int64(createdAt), 0))
+	last := first.AddDate(0, 0, maxTimelineSettlementDays)
+	today := startOfDay(now())
+	if last.After(today) {
+		last = today
+	}
+
+	truncated := false
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		entries, scan, err := scanPages(map[string]interface{}{
+			"year":  day.Year(),
+			"month": int(day.Month()),
+			"day":   day.Day(),
+		}, 1, maxTimelineRecords, func(entry map[string]interface{}) bool {
+			return stringField(entry, "type") == "payment" &&
+				stringField(entry, "entity_id") == paymentID
+		}, client.Settlement.Reports)
+		if err != nil {
+			return timelineSettlement{}, false, err
+		}
+		if len(entries) > 0 {
+			entry, _ := entries[0].(map[string]interface{})
+			settledAt, _ := entry["settled_at"].(float64)
+			return timelineSettlement{
+				Status:       timelineSettled,
+				SettlementID: stringField(entry, "settlement_id"),
+				SettledAt:    int64(settledAt),
+				UTR:          stringField(entry, "settlement_utr"),
+			}, truncated, nil
+		}
+		truncated = truncated || !scan.exhausted
+	}
+
+	if last.Equal(today) {
+		return timelineSettlement{Status: timelinePending}, truncated, nil
+	}
+	return timelineSettlement{Status: timelineNotFound}, truncated, nil
+}
+
+// timelineEvents returns the events of the entities of the timeline, in
+// chronological order. Events at the same time keep the order of the
+// order, payment, refunds, disputes and settlement.
+func timelineEvents(timeline *paymentTimeline) []*timelineEvent {
+	events := make([]*timelineEvent, 0)
+	add := func(entity map[string]interface{}, field, event, detail string) {
+		value, _ := entity[field].(float64)
+		if value == 0 {
+			return
+		}
+		events = append(events, &timelineEvent{
+			At:       int64(value),
+			Event:    event,
+			EntityID: stringField(entity, "id"),
+			Status:   stringField(entity, "status"),
+			Amount:   entity["amount"],
+			Detail:   detail,
+		})
+	}
+
+	if timeline.Order != nil {
+		add(timeline.Order, "created_at", "order.created", "")
+	}
+	add(timeline.Payment, "created_at", "payment.created",
+		stringField(timeline.Payment, "error_description"))
+	for _, item := range timeline.Refunds {
+		refund, _ := item.(map[string]interface{})
+		add(refund, "created_at", "refund.created",
+			stringField(refund, "speed_processed"))
+	}
+	for _, item := range timeline.Disputes {
+		dispute, _ := item.(map[string]interface{})
+		add(dispute, "created_at", "dispute.created",
+			stringField(dispute, "reason_code"))
+		if stringField(dispute, "status") == "open" {
+			add(dispute, "respond_by", "dispute.respond_by", "")
+		}
+	}
+	if timeline.Settlement.Status == timelineSettled {
+		add(map[string]interface{}{
+			"id":         timeline.Settlement.SettlementID,
+			"settled_at": float64(timeline.Settlement.SettledAt),
+		}, "settled_at", "payment.settled", timeline.Settlement.UTR)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].At < events[j].At
+	})
+	for _, event := range events {
+		event.Time = time.Unix(event.At, 0).In(settlementLocation).
+			Format(time.RFC3339)
+	}
+	return events
+}
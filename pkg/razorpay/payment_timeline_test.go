@@ -0,0 +1,253 @@
+package razorpay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/razorpay/razorpay-go/constants"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay/mock"
+)
+
+func Test_FetchPaymentTimeline(t *testing.T) {
+	paymentPath := fmt.Sprintf("/%s%s/%s", constants.VERSION_V1,
+		constants.PAYMENT_URL, "pay_MT48CvBhIC98MQ")
+	refundsPath := paymentPath + "/refunds"
+	orderPath := fmt.Sprintf("/%s%s/%s", constants.VERSION_V1,
+		constants.ORDER_URL, "order_MT48BXknWaJFRw")
+	disputesPath := fmt.Sprintf("/%s%s", constants.VERSION_V1,
+		constants.DISPUTE)
+	reconPath := fmt.Sprintf("/%s%s/recon/combined", constants.VERSION_V1,
+		constants.SETTLEMENT_URL)
+
+	origNow := now
+	defer func() { now = origNow }()
+	// Wednesday, 2024-01-17 12:00 IST
+	now = func() time.Time {
+		return time.Date(2024, 1, 17, 6, 30, 0, 0, time.UTC)
+	}
+
+	// payment returns a payment made at the time
+	payment := func(
+		status string,
+		captured bool,
+		createdAt float64,
+	) map[string]interface{} {
+		return map[string]interface{}{
+			"id": "pay_MT48CvBhIC98MQ", "status": status,
+			"amount": float64(50000), "captured": captured,
+			"order_id": "order_MT48BXknWaJFRw", "created_at": createdAt,
+		}
+	}
+	order := map[string]interface{}{
+		"id": "order_MT48BXknWaJFRw", "status": "paid",
+		"amount": float64(50000), "created_at": float64(1705292900),
+	}
+	refund := map[string]interface{}{
+		"id": "rfnd_MT4RmpyVgbPd3v", "status": "processed",
+		"amount": float64(10000), "speed_processed": "normal",
+		"created_at": float64(1705380000),
+	}
+	dispute := map[string]interface{}{
+		"id": "disp_MT4Uj8sHY5uo2v", "payment_id": "pay_MT48CvBhIC98MQ",
+		"status": "open", "amount": float64(40000),
+		"reason_code": "chargeback", "created_at": float64(1705390000),
+		"respond_by": float64(1706000000),
+	}
+	otherDispute := map[string]interface{}{
+		"id": "disp_Other00000001", "payment_id": "pay_Other000000001",
+		"status": "open", "created_at": float64(1705390000),
+	}
+	reconEntry := map[string]interface{}{
+		"entity_id": "pay_MT48CvBhIC98MQ", "type": "payment",
+		"settlement_id": "setl_MT5Ek2vNaREZzM", "settlement_utr": "UTR0001",
+		"settled_at": float64(1705466000),
+	}
+
+	// timelineOf runs the tool for the payment against the API, and
+	// returns its result
+	timelineOf := func(
+		t *testing.T,
+		payment map[string]interface{},
+		reconEntries ...interface{},
+	) (map[string]interface{}, int) {
+		reconCalls := 0
+		mockClient := func() (*http.Client, *httptest.Server) {
+			server := httptest.NewServer(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					var response interface{}
+					switch r.URL.Path {
+					case paymentPath:
+						response = payment
+					case orderPath:
+						response = order
+					case refundsPath:
+						response = collectionOf(refund)
+					case disputesPath:
+						response = collectionOf(dispute, otherDispute)
+					case reconPath:
+						reconCalls++
+						response = collectionOf(reconEntries...)
+					default:
+						http.NotFound(w, r)
+						return
+					}
+					_ = json.NewEncoder(w).Encode(response)
+				}))
+			return server.Client(), server
+		}
+
+		client, server := newMockRzpClient(mockClient)
+		defer server.Close()
+
+		tool := FetchPaymentTimeline(CreateTestObservability(), client)
+		result, err := tool.GetHandler()(context.Background(),
+			createMCPRequest(map[string]interface{}{
+				"payment_id": "pay_MT48CvBhIC98MQ",
+			}))
+		require.NoError(t, err)
+		require.False(t, result.IsError, result.Text)
+
+		var timeline map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(result.Text), &timeline))
+		return timeline, reconCalls
+	}
+
+	// eventsOf returns the event names of the timeline, in order
+	eventsOf := func(timeline map[string]interface{}) []string {
+		var events []string
+		items, _ := timeline["timeline"].([]interface{})
+		for _, item := range items {
+			event, _ := item.(map[string]interface{})
+			events = append(events, fmt.Sprintf("%s %s",
+				event["event"], event["entity_id"]))
+		}
+		return events
+	}
+
+	t.Run("composes a settled payment", func(t *testing.T) {
+		// Monday, 2024-01-15 10:00 IST
+		timeline, reconCalls := timelineOf(t,
+			payment("captured", true, 1705293000), reconEntry)
+
+		assert.Equal(t, "captured", timeline["status"])
+		assert.Equal(t, order, timeline["order"])
+		assert.Equal(t, []interface{}{refund}, timeline["refunds"])
+		assert.Equal(t, []interface{}{dispute}, timeline["disputes"])
+		assert.Equal(t, map[string]interface{}{
+			"status":        "settled",
+			"settlement_id": "setl_MT5Ek2vNaREZzM",
+			"settled_at":    float64(1705466000),
+			"utr":           "UTR0001",
+		}, timeline["settlement"])
+		assert.Equal(t, false, timeline["truncated"])
+		assert.Equal(t, 1, reconCalls)
+
+		assert.Equal(t, []string{
+			"order.created order_MT48BXknWaJFRw",
+			"payment.created pay_MT48CvBhIC98MQ",
+			"refund.created rfnd_MT4RmpyVgbPd3v",
+			"dispute.created disp_MT4Uj8sHY5uo2v",
+			"payment.settled setl_MT5Ek2vNaREZzM",
+			"dispute.respond_by disp_MT4Uj8sHY5uo2v",
+		}, eventsOf(timeline))
+
+		events := timeline["timeline"].([]interface{})
+		assert.Equal(t, map[string]interface{}{
+			"at":        float64(1705293000),
+			"time":      "2024-01-15T10:00:00+05:30",
+			"event":     "payment.created",
+			"entity_id": "pay_MT48CvBhIC98MQ",
+			"status":    "captured",
+			"amount":    float64(50000),
+		}, events[1])
+	})
+
+	t.Run("recent payment that is not settled is pending",
+		func(t *testing.T) {
+			timeline, reconCalls := timelineOf(t,
+				payment("captured", true, 1705293000))
+
+			assert.Equal(t, map[string]interface{}{"status": "pending"},
+				timeline["settlement"])
+			// The recon reports of the days from the payment to today
+			assert.Equal(t, 3, reconCalls)
+		})
+
+	t.Run("old payment that is not settled is not found",
+		func(t *testing.T) {
+			// 2023-12-01 10:00 IST
+			timeline, reconCalls := timelineOf(t,
+				payment("captured", true, 1701405000))
+
+			assert.Equal(t, map[string]interface{}{"status": "not_found"},
+				timeline["settlement"])
+			assert.Equal(t, maxTimelineSettlementDays+1, reconCalls)
+		})
+
+	t.Run("payment that is not captured does not settle",
+		func(t *testing.T) {
+			failed := payment("failed", false, 1705293000)
+			failed["error_description"] = "Payment was declined by the bank"
+
+			timeline, reconCalls := timelineOf(t, failed)
+
+			assert.Equal(t, map[string]interface{}{"status": "not_captured"},
+				timeline["settlement"])
+			assert.Equal(t, 0, reconCalls)
+
+			events := timeline["timeline"].([]interface{})
+			assert.Equal(t, "Payment was declined by the bank",
+				events[1].(map[string]interface{})["detail"])
+		})
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name:           "missing payment ID",
+			Request:        map[string]interface{}{},
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: payment_id",
+		},
+		{
+			Name: "invalid payment ID",
+			Request: map[string]interface{}{
+				"payment_id": "order_MT48BXknWaJFRw",
+			},
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid payment_id: order_MT48BXknWaJFRw",
+		},
+		{
+			Name: "fetching the payment fails",
+			Request: map[string]interface{}{
+				"payment_id": "pay_MT48CvBhIC98MQ",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{Path: paymentPath, Method: "GET",
+						Response: map[string]interface{}{
+							"error": map[string]interface{}{
+								"code":        "BAD_REQUEST_ERROR",
+								"description": "The id provided does not exist",
+							},
+						}},
+				)
+			},
+			ExpectError:    true,
+			ExpectedErrMsg: "fetching payment failed",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchPaymentTimeline, "Payment timeline")
+		})
+	}
+}
@@ -1,15 +1,21 @@
 package razorpay
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strings"
-	"time"
+	"sync"
 
 	rzpsdk "github.com/razorpay/razorpay-go"
+	"github.com/razorpay/razorpay-go/constants"
 
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
 	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
 	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
 )
@@ -26,6 +32,16 @@ func FetchPayment(
 				"of the payment to be retrieved."),
 			mcpgo.Required(),
 		),
+		mcpgo.WithArray(
+			"expand",
+			mcpgo.Description("Optional: Details of the payment method to "+
+				"include in the response: card, emi, offers or upi. Each "+
+				"only applies to payments made with that method"),
+			mcpgo.Items(map[string]interface{}{
+				"type": "string",
+				"enum": []interface{}{"card", "emi", "offers", "upi"},
+			}),
+		),
 	}
 
 	handler := func(
@@ -39,17 +55,35 @@ func FetchPayment(
 		}
 
 		params := make(map[string]interface{})
+		queryParams := make(map[string]interface{})
 
 		validator := NewValidator(&r).
-			ValidateAndAddRequiredString(params, "payment_id")
+			ValidateAndAddRequiredString(params, "payment_id").
+			ValidateAndAddOptionalArray(params, "expand")
 
 		if result, err := validator.HandleErrorsIfAny(); result != nil {
 			return result, err
 		}
 
+		if result := validateID("payment_id", params["payment_id"].(string),
+			paymentIDPrefix); result != nil {
+			return result, nil
+		}
+
+		// ValidateAndAddExpand keeps a single value, while a payment can be
+		// expanded with several. The SDK sends every value of a string
+		// slice, as expand[]=card&expand[]=offers.
+		if expand, ok := params["expand"].([]interface{}); ok {
+			values := make([]string, 0, len(expand))
+			for _, value := range expand {
+				values = append(values, fmt.Sprint(value))
+			}
+			queryParams["expand[]"] = values
+		}
+
 		paymentId := params["payment_id"].(string)
 
-		payment, err := client.Payment.Fetch(paymentId, nil, nil)
+		payment, err := client.Payment.Fetch(paymentId, queryParams, nil)
 		if err != nil {
 			return mcpgo.NewToolResultError(
 				fmt.Sprintf("fetching payment failed: %s", err.Error())), nil
@@ -64,7 +98,117 @@ func FetchPayment(
 			"using its id. Amount returned is in paisa",
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(paymentOutputSchema).
+		WithFieldSelection()
+}
+
+// defaultPaymentTerminalStatuses are the statuses wait_for_payment_status
+// stops at by default. A UPI collect request that expires fails the payment.
+var defaultPaymentTerminalStatuses = []string{"captured", "failed"}
+
+// WaitForPaymentStatus returns a tool that polls a payment until it reaches
+// one of the terminal statuses
+func WaitForPaymentStatus(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := append([]mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"payment_id",
+			mcpgo.Description("ID of the payment to wait for, starting with "+
+				"'pay_'"),
+			mcpgo.Required(),
+		),
+		mcpgo.WithArray(
+			"terminal_statuses",
+			mcpgo.Description("Optional: Statuses to stop waiting at "+
+				"(default: captured, failed). Add authorized when payments "+
+				"are not captured automatically"),
+			mcpgo.Items(map[string]interface{}{
+				"type": "string",
+				"enum": []interface{}{"authorized", "captured", "refunded",
+					"failed"},
+			}),
+		),
+	}, pollingParameters()...)
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		params := make(map[string]interface{})
+		polling := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(params, "payment_id").
+			ValidateAndAddOptionalArray(params, "terminal_statuses").
+			ValidateAndAddPolling(polling)
+
+		terminal := defaultPaymentTerminalStatuses
+		if statuses, ok := params["terminal_statuses"].([]interface{}); ok &&
+			len(statuses) > 0 {
+			terminal = make([]string, 0, len(statuses))
+			for _, status := range statuses {
+				value, ok := status.(string)
+				if !ok || value == "" {
+					validator.addError(fmt.Errorf(
+						"terminal_statuses must be a list of payment statuses"))
+					break
+				}
+				terminal = append(terminal, value)
+			}
+		}
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		paymentID := params["payment_id"].(string)
+
+		if result := validateID("payment_id", paymentID,
+			paymentIDPrefix); result != nil {
+			return result, nil
+		}
+
+		outcome, err := poll(ctx, polling,
+			func() (map[string]interface{}, error) {
+				return client.Payment.Fetch(paymentID, nil, nil)
+			},
+			statusIn(terminal...),
+		)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("waiting for payment failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(map[string]interface{}{
+			"payment_id": paymentID,
+			"status":     stringField(outcome.entity, "status"),
+			"completed":  outcome.done,
+			"polls":      outcome.polls,
+			"payment":    outcome.entity,
+		})
+	}
+
+	return mcpgo.NewTool(
+		"wait_for_payment_status",
+		"Wait for a payment to reach one of the terminal statuses, checking "+
+			"its status every poll_interval seconds, and return its final "+
+			"state. Use it after initiate_payment with a UPI collect request "+
+			"instead of calling fetch_payment repeatedly. completed is false "+
+			"when the timeout passed first, in which case the customer has "+
+			"not acted yet and the payment can be waited for again",
+		parameters,
+		handler,
+	).WithOutputSchema(objectOutputSchema).
+		WithTimeout(pollToolTimeout).
+		// Each call must poll the live status of the payment
+		WithoutCache()
 }
 
 // FetchPaymentCardDetails returns a tool that fetches card details
@@ -103,6 +247,11 @@ func FetchPaymentCardDetails(
 
 		paymentId := params["payment_id"].(string)
 
+		if result := validateID("payment_id", paymentId,
+			paymentIDPrefix); result != nil {
+			return result, nil
+		}
+
 		cardDetails, err := client.Payment.FetchCardDetails(
 			paymentId, nil, nil)
 
@@ -120,7 +269,8 @@ func FetchPaymentCardDetails(
 			"Only works for payments made using a card.",
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(entityOutputSchema).
+		WithFieldSelection()
 }
 
 // UpdatePayment returns a tool that updates the notes for a payment
@@ -166,6 +316,11 @@ func UpdatePayment(
 
 		paymentId := params["payment_id"].(string)
 
+		if result := validateID("payment_id", paymentId,
+			paymentIDPrefix); result != nil {
+			return result, nil
+		}
+
 		// Update the payment
 		updatedPayment, err := client.Payment.Edit(paymentId, paymentUpdateReq, nil)
 		if err != nil {
@@ -182,7 +337,7 @@ func UpdatePayment(
 			"key-value pairs that can be used to store additional information.", //nolint:lll
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(paymentOutputSchema)
 }
 
 // CapturePayment returns a tool that captures an authorized payment
@@ -199,7 +354,8 @@ func CapturePayment(
 		mcpgo.WithNumber(
 			"amount",
 			mcpgo.Description("The amount to be captured (in paisa). "+
-				"Should be equal to the authorized amount"),
+				"Should be equal to the authorized amount, or lower if "+
+				"partial capture is enabled on the account"),
 			mcpgo.Required(),
 		),
 		mcpgo.WithString(
@@ -233,6 +389,12 @@ func CapturePayment(
 		}
 
 		paymentId := params["payment_id"].(string)
+
+		if result := validateID("payment_id", paymentId,
+			paymentIDPrefix); result != nil {
+			return result, nil
+		}
+
 		amount := int(params["amount"].(int64))
 
 		// Capture the payment
@@ -243,8 +405,8 @@ func CapturePayment(
 			nil,
 		)
 		if err != nil {
-			return mcpgo.NewToolResultError(
-				fmt.Sprintf("capturing payment failed: %s", err.Error())), nil
+			return captureFailureResult(client, paymentId,
+				params["amount"].(int64), err), nil
 		}
 
 		return mcpgo.NewToolResultJSON(payment)
@@ -255,7 +417,252 @@ func CapturePayment(
 		"Use this tool to capture a previously authorized payment. Only payments with 'authorized' status can be captured", //nolint:lll
 		parameters,
 		handler,
+	).WithOutputSchema(paymentOutputSchema).WithAmounts("amount")
+}
+
+// captureFailureResult returns the error result of a failed capture. The
+// payment is fetched so that the error carries its status and authorized
+// amount, which explain most failures, such as a capture amount that does
+// not match. The plain error is returned if the payment cannot be fetched.
+func captureFailureResult(
+	client *rzpsdk.Client,
+	paymentID string,
+	amount int64,
+	captureErr error,
+) *mcpgo.ToolResult {
+	message := fmt.Sprintf("capturing payment failed: %s", captureErr.Error())
+
+	payment, err := client.Payment.Fetch(paymentID, nil, nil)
+	if err != nil {
+		return mcpgo.NewToolResultError(message)
+	}
+
+	authorized, _ := payment["amount"].(float64)
+	details := map[string]interface{}{
+		"error":             message,
+		"payment_id":        paymentID,
+		"status":            payment["status"],
+		"authorized_amount": payment["amount"],
+		"requested_amount":  amount,
+		"currency":          payment["currency"],
+	}
+
+	switch status := stringField(payment, "status"); {
+	case status == "captured":
+		details["hint"] = "The payment is already captured"
+	case status == "refunded" || status == "failed":
+		details["hint"] = "The payment can no longer be captured. " +
+			"Payments authorized late, after the capture window of their " +
+			"order, are refunded automatically unless captured in time"
+	case float64(amount) > authorized:
+		details["hint"] = fmt.Sprintf("The capture amount exceeds the "+
+			"authorized amount of %.0f", authorized)
+	case float64(amount) < authorized:
+		details["hint"] = fmt.Sprintf("Capturing less than the authorized "+
+			"amount needs partial capture enabled on the account. Capture "+
+			"the authorized amount of %.0f instead", authorized)
+	}
+
+	text, err := json.Marshal(details)
+	if err != nil {
+		return mcpgo.NewToolResultError(message)
+	}
+	return mcpgo.NewToolResultError(string(text))
+}
+
+const (
+	// bulkCaptureConcurrency bounds the number of payments that are
+	// captured in parallel by the bulk_capture_payments tool
+	bulkCaptureConcurrency = 5
+
+	// maxBulkCapturePayments caps the number of payments captured in one
+	// call of the bulk_capture_payments tool
+	maxBulkCapturePayments = 50
+)
+
+// BulkCapturePayments returns a tool that captures many authorized payments
+// in one call. The outcome of each payment is reported individually, so one
+// failed capture does not fail the whole batch.
+func BulkCapturePayments(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithArray(
+			"payment_ids",
+			mcpgo.Description("IDs of the payments to capture, each "+
+				"starting with 'pay_'. Each payment is captured for its "+
+				"full authorized amount. Leave empty to capture the "+
+				"authorized payments between from and to instead"),
+			mcpgo.Min(1),
+			mcpgo.Max(maxBulkCapturePayments),
+			mcpgo.Items(map[string]interface{}{
+				"type":    "string",
+				"pattern": "^pay_",
+			}),
+		),
+		mcpgo.WithNumber(
+			"from",
+			mcpgo.Description("Unix timestamp (in seconds) from when "+
+				"authorized payments are to be captured"),
+			mcpgo.Min(0),
+		),
+		mcpgo.WithNumber(
+			"to",
+			mcpgo.Description("Unix timestamp (in seconds) up till when "+
+				"authorized payments are to be captured"),
+			mcpgo.Min(0),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		payload := make(map[string]interface{})
+		queryParams := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddOptionalArray(payload, "payment_ids").
+			ValidateAndAddOptionalInt(queryParams, "from").
+			ValidateAndAddOptionalInt(queryParams, "to")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		paymentIDs, _ := payload["payment_ids"].([]interface{})
+		_, hasFrom := queryParams["from"]
+		_, hasTo := queryParams["to"]
+
+		var targets []interface{}
+		switch {
+		case len(paymentIDs) > 0 && (hasFrom || hasTo):
+			return mcpgo.NewToolResultError(
+				"provide either payment_ids or from and to, not both"), nil
+		case len(paymentIDs) > maxBulkCapturePayments:
+			return mcpgo.NewToolResultError(fmt.Sprintf(
+				"payment_ids must contain at most %d payments",
+				maxBulkCapturePayments)), nil
+		case len(paymentIDs) > 0:
+			targets = paymentIDs
+		case hasFrom && hasTo:
+			targets, _, err = scanPages(queryParams, maxBulkCapturePayments,
+				maxSearchScanned,
+				func(payment map[string]interface{}) bool {
+					return payment["status"] == "authorized"
+				},
+				client.Payment.All)
+			if err != nil {
+				return mcpgo.NewToolResultError(fmt.Sprintf(
+					"fetching authorized payments failed: %s",
+					err.Error())), nil
+			}
+		default:
+			return mcpgo.NewToolResultError(
+				"provide payment_ids, or both from and to"), nil
+		}
+
+		results := make([]map[string]interface{}, len(targets))
+		sem := make(chan struct{}, bulkCaptureConcurrency)
+		var wg sync.WaitGroup
+
+		for i, target := range targets {
+			wg.Add(1)
+			go func(i int, target interface{}) {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				results[i] = captureBulkPayment(client, target)
+			}(i, target)
+		}
+		wg.Wait()
+
+		succeeded := 0
+		for _, result := range results {
+			if result["status"] == "captured" {
+				succeeded++
+			}
+		}
+
+		return mcpgo.NewToolResultJSON(map[string]interface{}{
+			"count":     len(results),
+			"succeeded": succeeded,
+			"failed":    len(results) - succeeded,
+			"results":   results,
+		})
+	}
+
+	return mcpgo.NewTool(
+		"bulk_capture_payments",
+		"Capture many authorized payments in a single call, either the "+
+			"payments listed in payment_ids or the authorized payments "+
+			"created between from and to (at most 50). Each payment is "+
+			"captured for its full authorized amount, and the response "+
+			"contains one result per payment with the captured payment or "+
+			"the error for that payment.",
+		parameters,
+		handler,
+	).WithOutputSchema(objectOutputSchema).
+		WithoutTimeout()
+}
+
+// captureBulkPayment captures a single payment of a bulk capture, given
+// either its ID or the payment itself, returning the per-payment result
+func captureBulkPayment(
+	client *rzpsdk.Client,
+	target interface{},
+) map[string]interface{} {
+	result := make(map[string]interface{})
+	fail := func(err string) map[string]interface{} {
+		result["status"] = "failed"
+		result["error"] = err
+		return result
+	}
+
+	payment, ok := target.(map[string]interface{})
+	if !ok {
+		paymentID, ok := target.(string)
+		if !ok || !mcpgo.IsID(paymentID, paymentIDPrefix) {
+			result["payment_id"] = target
+			return fail("invalid payment ID: must start with 'pay_'")
+		}
+		result["payment_id"] = paymentID
+
+		var err error
+		payment, err = client.Payment.Fetch(paymentID, nil, nil)
+		if err != nil {
+			return fail(fmt.Sprintf("fetching payment failed: %s", err.Error()))
+		}
+	}
+	result["payment_id"] = payment["id"]
+
+	if status := payment["status"]; status != "authorized" {
+		return fail(fmt.Sprintf("payment is %v, only authorized payments "+
+			"can be captured", status))
+	}
+
+	paymentID, _ := payment["id"].(string)
+	amount, _ := payment["amount"].(float64)
+	captured, err := client.Payment.Capture(
+		paymentID,
+		int(amount),
+		map[string]interface{}{"currency": payment["currency"]},
+		nil,
 	)
+	if err != nil {
+		return fail(fmt.Sprintf("capturing payment failed: %s", err.Error()))
+	}
+
+	result["status"] = "captured"
+	result["payment"] = captured
+	return result
 }
 
 // FetchAllPayments returns a tool to fetch multiple payments with filtering and pagination
@@ -265,7 +672,7 @@ func FetchAllPayments(
 	obs *observability.Observability,
 	client *rzpsdk.Client,
 ) mcpgo.Tool {
-	parameters := []mcpgo.ToolParameter{
+	parameters := append([]mcpgo.ToolParameter{
 		// Pagination parameters
 		mcpgo.WithNumber(
 			"count",
@@ -292,7 +699,7 @@ func FetchAllPayments(
 				"payments are to be fetched"),
 			mcpgo.Min(0),
 		),
-	}
+	}, collectionParameters()...)
 
 	handler := func(
 		ctx context.Context,
@@ -306,9 +713,11 @@ func FetchAllPayments(
 
 		// Create query parameters map
 		paymentListOptions := make(map[string]interface{})
+		pagination := make(map[string]interface{})
 
 		validator := NewValidator(&r).
 			ValidateAndAddPagination(paymentListOptions).
+			ValidateAndAddCollectionOptions(pagination).
 			ValidateAndAddOptionalInt(paymentListOptions, "from").
 			ValidateAndAddOptionalInt(paymentListOptions, "to")
 
@@ -317,13 +726,14 @@ func FetchAllPayments(
 		}
 
 		// Fetch all payments using Razorpay SDK
-		payments, err := client.Payment.All(paymentListOptions, nil)
+		payments, err := fetchCollection(paymentListOptions, pagination,
+			client.Payment.All)
 		if err != nil {
 			return mcpgo.NewToolResultError(
 				fmt.Sprintf("fetching payments failed: %s", err.Error())), nil
 		}
 
-		return mcpgo.NewToolResultJSON(payments)
+		return collectionResult(ctx, payments, pagination)
 	}
 
 	return mcpgo.NewTool(
@@ -331,7 +741,235 @@ func FetchAllPayments(
 		"Fetch all payments with optional filtering and pagination",
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(paymentsOutputSchema).
+		WithFieldSelection()
+}
+
+const (
+	// defaultSearchResults is the number of matching payments a search
+	// returns when max_results is not set
+	defaultSearchResults = 25
+
+	// maxSearchScanned caps the number of payments a search looks at, to
+	// bound the number of API calls it makes
+	maxSearchScanned = 1000
+)
+
+// SearchPayments returns a tool to search payments by customer, status,
+// method, order, notes and amount
+func SearchPayments(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithNumber(
+			"from",
+			mcpgo.Description("Unix timestamp (in seconds) from when "+
+				"payments are to be searched"),
+			mcpgo.Min(0),
+		),
+		mcpgo.WithNumber(
+			"to",
+			mcpgo.Description("Unix timestamp (in seconds) up till when "+
+				"payments are to be searched"),
+			mcpgo.Min(0),
+		),
+		mcpgo.WithString(
+			"email",
+			mcpgo.Description("Email address of the customer, matched "+
+				"case-insensitively"),
+		),
+		mcpgo.WithString(
+			"contact",
+			mcpgo.Description("Phone number of the customer. Matches "+
+				"contacts ending with the same digits, so the country code "+
+				"can be left out"),
+		),
+		mcpgo.WithString(
+			"status",
+			mcpgo.Description("Status of the payment"),
+			mcpgo.Enum("created", "authorized", "captured", "refunded",
+				"failed"),
+		),
+		mcpgo.WithString(
+			"method",
+			mcpgo.Description("Payment method"),
+			mcpgo.Enum("card", "netbanking", "wallet", "emi", "upi",
+				"cardless_emi", "paylater", "bank_transfer"),
+		),
+		mcpgo.WithString(
+			"order_id",
+			mcpgo.Description("ID of the order the payment was made for "+
+				"(ID should have an order_ prefix)"),
+		),
+		mcpgo.WithObject(
+			"notes",
+			mcpgo.Description("Key-value pairs the notes of the payment "+
+				"must contain"),
+		),
+		mcpgo.WithNumber(
+			"min_amount",
+			mcpgo.Description("Minimum amount in the smallest currency "+
+				"sub-unit, inclusive"),
+			mcpgo.Min(0),
+		),
+		mcpgo.WithNumber(
+			"max_amount",
+			mcpgo.Description("Maximum amount in the smallest currency "+
+				"sub-unit, inclusive"),
+			mcpgo.Min(0),
+		),
+		mcpgo.WithNumber(
+			"skip",
+			mcpgo.Description("Number of payments to skip before searching. "+
+				"Set it to the next_skip of a previous search to continue "+
+				"that search (default: 0)"),
+			mcpgo.Min(0),
+		),
+		mcpgo.WithNumber(
+			"max_results",
+			mcpgo.Description("Maximum number of matching payments to "+
+				"return (default: 25, max: 100)"),
+			mcpgo.Min(1),
+			mcpgo.Max(100),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		queryParams := make(map[string]interface{})
+		filters := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddOptionalInt(queryParams, "from").
+			ValidateAndAddOptionalInt(queryParams, "to").
+			ValidateAndAddOptionalInt(queryParams, "skip").
+			ValidateAndAddOptionalString(filters, "email").
+			ValidateAndAddOptionalString(filters, "contact").
+			ValidateAndAddOptionalString(filters, "status").
+			ValidateAndAddOptionalString(filters, "method").
+			ValidateAndAddOptionalString(filters, "order_id").
+			ValidateAndAddOptionalMap(filters, "notes").
+			ValidateAndAddOptionalInt(filters, "min_amount").
+			ValidateAndAddOptionalInt(filters, "max_amount").
+			ValidateAndAddOptionalInt(filters, "max_results")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		maxResults := int64(defaultSearchResults)
+		if value, ok := filters["max_results"].(int64); ok {
+			maxResults = value
+		}
+
+		payments, scan, err := scanPages(queryParams, maxResults,
+			maxSearchScanned, paymentFilter(filters), client.Payment.All)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("searching payments failed: %s", err.Error())), nil
+		}
+
+		result := map[string]interface{}{
+			"entity":  "collection",
+			"count":   len(payments),
+			"items":   payments,
+			"scanned": scan.scanned,
+		}
+		if !scan.exhausted {
+			skip, _ := queryParams["skip"].(int64)
+			result["next_skip"] = skip + scan.scanned
+		}
+
+		return mcpgo.NewToolResultJSON(result)
+	}
+
+	return mcpgo.NewTool(
+		"search_payments",
+		"Search payments by customer email or contact, status, method, "+
+			"order, notes and amount range. Payments are fetched page by "+
+			"page, newest first, and filtered until max_results payments "+
+			"match or 1000 payments are looked at. When next_skip is "+
+			"returned, more payments are left to search",
+		parameters,
+		handler,
+	).WithOutputSchema(paymentsOutputSchema).
+		WithFieldSelection()
+}
+
+// paymentFilter returns a function that reports whether a payment matches
+// the search filters
+func paymentFilter(
+	filters map[string]interface{},
+) func(payment map[string]interface{}) bool {
+	return func(payment map[string]interface{}) bool {
+		if email, ok := filters["email"].(string); ok &&
+			!strings.EqualFold(stringField(payment, "email"), email) {
+			return false
+		}
+		if contact, ok := filters["contact"].(string); ok &&
+			!contactMatches(stringField(payment, "contact"), contact) {
+			return false
+		}
+		for _, key := range []string{"status", "method", "order_id"} {
+			if value, ok := filters[key].(string); ok &&
+				stringField(payment, key) != value {
+				return false
+			}
+		}
+
+		amount, _ := payment["amount"].(float64)
+		if minAmount, ok := filters["min_amount"].(int64); ok &&
+			amount < float64(minAmount) {
+			return false
+		}
+		if maxAmount, ok := filters["max_amount"].(int64); ok &&
+			amount > float64(maxAmount) {
+			return false
+		}
+
+		notes, _ := filters["notes"].(map[string]interface{})
+		paymentNotes, _ := payment["notes"].(map[string]interface{})
+		for key, value := range notes {
+			noteValue, ok := paymentNotes[key]
+			if !ok || fmt.Sprint(noteValue) != fmt.Sprint(value) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// stringField returns a string field of an entity, or "" if it is missing
+// or null
+func stringField(entity map[string]interface{}, key string) string {
+	value, _ := entity[key].(string)
+	return value
+}
+
+// contactMatches reports whether a contact ends with the digits of the
+// searched contact, ignoring formatting such as "+" and spaces
+func contactMatches(contact, search string) bool {
+	digits := func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return r
+			}
+			return -1
+		}, s)
+	}
+
+	searchDigits := digits(search)
+	return searchDigits != "" &&
+		strings.HasSuffix(digits(contact), searchDigits)
 }
 
 // extractPaymentID extracts the payment ID from the payment response
@@ -359,54 +997,48 @@ func extractNextActions(
 	return actions
 }
 
-// OTPResponse represents the response from OTP generation API
-
-// sendOtp sends an OTP to the customer and returns the response
-func sendOtp(otpUrl string) error {
+// validateOtpURL checks that an OTP URL is safe to call: it must use HTTPS
+// and point to razorpay.com or one of its subdomains
+func validateOtpURL(otpUrl string) (*url.URL, error) {
 	if otpUrl == "" {
-		return fmt.Errorf("OTP URL is empty")
+		return nil, fmt.Errorf("OTP URL is empty")
 	}
-	// Validate URL is safe and from Razorpay domain for security
 	parsedURL, err := url.Parse(otpUrl)
 	if err != nil {
-		return fmt.Errorf("invalid OTP URL: %s", err.Error())
+		return nil, fmt.Errorf("invalid OTP URL: %s", err.Error())
 	}
 
 	if parsedURL.Scheme != "https" {
-		return fmt.Errorf("OTP URL must use HTTPS")
+		return nil, fmt.Errorf("OTP URL must use HTTPS")
 	}
 
-	if !strings.Contains(parsedURL.Host, "razorpay.com") {
-		return fmt.Errorf("OTP URL must be from Razorpay domain")
+	host := parsedURL.Hostname()
+	if host != "razorpay.com" && !strings.HasSuffix(host, ".razorpay.com") {
+		return nil, fmt.Errorf("OTP URL must be from Razorpay domain")
 	}
 
-	// Create a secure HTTP client with timeout
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
+	return parsedURL, nil
+}
 
-	req, err := http.NewRequest("POST", otpUrl, nil)
+// sendOtp sends an OTP to the customer. Like generate_otp, the request is
+// sent to the API host of the client with the path of the URL, once the URL
+// is validated.
+func sendOtp(client *rzpsdk.Client, otpUrl string) error {
+	// Validate URL is safe and from Razorpay domain for security
+	parsedURL, err := validateOtpURL(otpUrl)
 	if err != nil {
-		return fmt.Errorf("failed to create OTP request: %s", err.Error())
+		return err
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := client.Do(req)
-	if err != nil {
+	if _, err := postToOtpURL(client, parsedURL, nil); err != nil {
 		return fmt.Errorf("OTP generation failed: %s", err.Error())
 	}
-	defer resp.Body.Close()
-
-	// Validate HTTP response status
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("OTP generation failed with HTTP status: %d",
-			resp.StatusCode)
-	}
 	return nil
 }
 
 // buildInitiatePaymentResponse constructs the response for initiate payment
 func buildInitiatePaymentResponse(
+	ctx context.Context,
 	payment map[string]interface{},
 	paymentID string,
 	actions []map[string]interface{},
@@ -415,8 +1047,8 @@ func buildInitiatePaymentResponse(
 		"razorpay_payment_id": paymentID,
 		"payment_details":     payment,
 		"status":              "payment_initiated",
-		"message": "Payment initiated successfully using " +
-			"S2S JSON v1 flow",
+		"message": localize(ctx, "Payment initiated successfully using "+
+			"S2S JSON v1 flow"),
 	}
 	otpUrl := ""
 
@@ -455,66 +1087,143 @@ func buildInitiatePaymentResponse(
 
 		switch {
 		case hasOTP:
-			response["message"] = "Payment initiated. OTP authentication is " +
-				"available. " +
-				"Use the 'submit_otp' tool to submit OTP received by the customer " +
-				"for authentication."
-			addNextStepInstructions(response, paymentID)
+			response["message"] = localize(ctx, "Payment initiated. OTP "+
+				"authentication is available. Use the 'submit_otp' tool to "+
+				"submit OTP received by the customer for authentication.")
+			addNextStepInstructions(ctx, response, paymentID)
 		case hasRedirect:
-			response["message"] = "Payment initiated. Redirect authentication is " +
-				"available. Use the redirect URL provided in available_actions."
+			response["message"] = localize(ctx, "Payment initiated. Redirect "+
+				"authentication is available. Use the redirect URL provided "+
+				"in available_actions.")
+			setNextActions(response, redirectNextActions(ctx,
+				gatewayActionURL(actions, "redirect"))...)
 		case hasUPICollect:
-			response["message"] = fmt.Sprintf(
-				"Payment initiated. Available actions: %v", actionTypes)
+			response["message"] = availableActionsMessage(ctx, actionTypes)
+			setNextActions(response, upiNextActions(ctx, paymentID, "")...)
 		case hasUPIIntent:
-			response["message"] = fmt.Sprintf(
-				"Payment initiated. Available actions: %v", actionTypes)
+			response["message"] = availableActionsMessage(ctx, actionTypes)
+			setNextActions(response, upiNextActions(ctx,
+				paymentID, gatewayActionURL(actions, "upi_intent"))...)
 		default:
-			response["message"] = fmt.Sprintf(
-				"Payment initiated. Available actions: %v", actionTypes)
+			response["message"] = availableActionsMessage(ctx, actionTypes)
 		}
 	} else {
-		addFallbackNextStepInstructions(response, paymentID)
+		addFallbackNextStepInstructions(ctx, response, paymentID)
 	}
 
 	return response, otpUrl
 }
 
+// availableActionsMessage returns the message of a payment initiated with
+// the given gateway actions
+func availableActionsMessage(
+	ctx context.Context,
+	actionTypes []string,
+) string {
+	return fmt.Sprintf(localize(ctx,
+		"Payment initiated. Available actions: %v"), actionTypes)
+}
+
 // addNextStepInstructions adds next step guidance to the response
 func addNextStepInstructions(
+	ctx context.Context,
 	response map[string]interface{},
 	paymentID string,
 ) {
 	if paymentID != "" {
-		response["next_step"] = "Use 'resend_otp' to regenerate OTP or " +
-			"'submit_otp' to proceed to enter OTP."
-		response["next_tool"] = "resend_otp"
-		response["next_tool_params"] = map[string]interface{}{
-			"payment_id": paymentID,
-		}
+		setNextActions(response, otpNextActions(ctx, paymentID,
+			localize(ctx, "Use 'resend_otp' to regenerate OTP or "+
+				"'submit_otp' to proceed to enter OTP."))...)
 	}
 }
 
 // addFallbackNextStepInstructions adds fallback next step guidance
 func addFallbackNextStepInstructions(
+	ctx context.Context,
 	response map[string]interface{},
 	paymentID string,
 ) {
 	if paymentID != "" {
-		response["next_step"] = "Use 'resend_otp' to regenerate OTP or " +
-			"'submit_otp' to proceed to enter OTP if " +
-			"OTP authentication is required."
-		response["next_tool"] = "resend_otp"
-		response["next_tool_params"] = map[string]interface{}{
-			"payment_id": paymentID,
+		setNextActions(response, otpNextActions(ctx, paymentID,
+			localize(ctx, "Use 'resend_otp' to regenerate OTP or "+
+				"'submit_otp' to proceed to enter OTP if "+
+				"OTP authentication is required."))...)
+	}
+}
+
+// gatewayActionURL returns the URL of the first gateway action of the
+// given type, or an empty string if there is none
+func gatewayActionURL(
+	actions []map[string]interface{},
+	actionType string,
+) string {
+	for _, action := range actions {
+		if action["action"] == actionType {
+			if actionURL, ok := action["url"].(string); ok {
+				return actionURL
+			}
 		}
 	}
+	return ""
+}
+
+// redirectNextActions returns the action for redirect authentication
+func redirectNextActions(
+	ctx context.Context,
+	redirectURL string,
+) []NextAction {
+	if redirectURL == "" {
+		return nil
+	}
+	return []NextAction{
+		urlNextAction(nextActionRedirect, localize(ctx,
+			"Redirect the customer to this URL to complete authentication."),
+			redirectURL),
+	}
+}
+
+// upiNextActions returns the actions for a UPI payment awaiting approval
+// by the customer, either through a collect request or an intent URL
+func upiNextActions(
+	ctx context.Context,
+	paymentID string,
+	intentURL string,
+) []NextAction {
+	var actions []NextAction
+	if intentURL != "" {
+		actions = append(actions, urlNextAction(nextActionUPIIntent,
+			localize(ctx, "Open this URL in a UPI app to complete the "+
+				"payment."),
+			intentURL))
+	}
+	if paymentID != "" {
+		actions = append(actions, fetchPaymentNextAction(paymentID,
+			localize(ctx, "Use 'fetch_payment' to check the payment status "+
+				"once the customer approves the payment in their UPI app.")))
+	}
+	return actions
+}
+
+// defaultGeneratedEmailDomain is the domain of the emails generated for
+// customers from their contact number
+const defaultGeneratedEmailDomain = "mcp.razorpay.com"
+
+// generatedEmailDomain returns the domain of the emails generated for
+// customers in tool calls with ctx, empty if emails are not generated
+func generatedEmailDomain(ctx context.Context) string {
+	if domain, ok := contextkey.GeneratedEmailDomainFromContext(ctx); ok {
+		return domain
+	}
+	return defaultGeneratedEmailDomain
 }
 
-// addContactAndEmailToPaymentData adds contact and email to payment data
+// addContactAndEmailToPaymentData adds contact and email to payment data.
+// Without an email, one is generated from the contact in emailDomain,
+// unless emailDomain is empty.
 func addContactAndEmailToPaymentData(
 	paymentData map[string]interface{},
 	params map[string]interface{},
+	emailDomain string,
 ) {
 	// Add contact if provided
 	if contact, exists := params["contact"]; exists && contact != "" {
@@ -524,8 +1233,9 @@ func addContactAndEmailToPaymentData(
 	// Add email if provided, otherwise generate from contact
 	if email, exists := params["email"]; exists && email != "" {
 		paymentData["email"] = email
-	} else if contact, exists := paymentData["contact"]; exists && contact != "" {
-		paymentData["email"] = contact.(string) + "@mcp.razorpay.com"
+	} else if contact, exists := paymentData["contact"]; exists &&
+		contact != "" && emailDomain != "" {
+		paymentData["email"] = contact.(string) + "@" + emailDomain
 	}
 }
 
@@ -552,6 +1262,13 @@ func addAdditionalPaymentParameters(
 		paymentData["recurring"] = recurring
 	}
 
+	// Add the bank of netbanking and the wallet of wallet payments
+	for _, key := range []string{"bank", "wallet"} {
+		if value, exists := params[key]; exists && value != "" {
+			paymentData[key] = value
+		}
+	}
+
 	// Add UPI parameters if provided
 	if upiParams, exists := params["upi"]; exists && upiParams != nil {
 		if upiMap, ok := upiParams.(map[string]interface{}); ok {
@@ -560,6 +1277,52 @@ func addAdditionalPaymentParameters(
 	}
 }
 
+// validatePaymentMethod checks that the method of a payment is supported
+// and comes with the details it needs: the bank code for netbanking, the
+// wallet for wallet, a saved card token for card and a VPA or the intent
+// flow for UPI payments
+func validatePaymentMethod(params map[string]interface{}) error {
+	method, _ := params["method"].(string)
+	bank, _ := params["bank"].(string)
+	wallet, _ := params["wallet"].(string)
+	token, _ := params["token"].(string)
+	vpa, _ := params["vpa"].(string)
+	upiIntent, _ := params["upi_intent"].(bool)
+
+	if (vpa != "" || upiIntent) && method != "" && method != "upi" {
+		return fmt.Errorf(
+			"method %s cannot be combined with vpa or upi_intent", method)
+	}
+
+	switch method {
+	case "", "card", "netbanking", "wallet", "upi":
+	default:
+		return fmt.Errorf("invalid method: %s, must be one of card, "+
+			"netbanking, wallet or upi", method)
+	}
+
+	if method == "upi" && vpa == "" && !upiIntent {
+		return errors.New("vpa or upi_intent is required for method upi")
+	}
+	if method == "card" && token == "" {
+		return errors.New("token of a saved card is required for method card")
+	}
+	if method == "netbanking" && bank == "" {
+		return errors.New("bank is required for method netbanking")
+	}
+	if method != "netbanking" && bank != "" {
+		return errors.New("bank is only supported for method netbanking")
+	}
+	if method == "wallet" && wallet == "" {
+		return errors.New("wallet is required for method wallet")
+	}
+	if method != "wallet" && wallet != "" {
+		return errors.New("wallet is only supported for method wallet")
+	}
+
+	return nil
+}
+
 // processUPIParameters handles VPA and UPI intent parameter processing
 func processUPIParameters(params map[string]interface{}) {
 	vpa, hasVPA := params["vpa"]
@@ -621,6 +1384,7 @@ func buildPaymentData(
 	params map[string]interface{},
 	currency string,
 	customerId string,
+	emailDomain string,
 ) *map[string]interface{} {
 	paymentData := map[string]interface{}{
 		"amount":   params["amount"],
@@ -638,7 +1402,7 @@ func buildPaymentData(
 	}
 
 	// Add contact and email parameters
-	addContactAndEmailToPaymentData(paymentData, params)
+	addContactAndEmailToPaymentData(paymentData, params, emailDomain)
 
 	// Add additional parameters for UPI collect and other flows
 	addAdditionalPaymentParameters(paymentData, params)
@@ -649,11 +1413,27 @@ func buildPaymentData(
 		paymentData["force_terminal_id"] = terminalID
 	}
 
+	// Add callback_url if provided, Razorpay sends the customer there
+	// after redirect authentication
+	if callbackURL, exists := params["callback_url"]; exists &&
+		callbackURL != "" {
+		paymentData["callback_url"] = callbackURL
+	}
+
+	// Ask for authentication in the customer's browser in redirect mode
+	if redirect, ok := params["redirect"].(bool); ok && redirect {
+		paymentData["authentication"] = map[string]interface{}{
+			"authentication_channel": "browser",
+		}
+	}
+
 	return &paymentData
 }
 
 // processPaymentResult processes the payment creation result
 func processPaymentResult(
+	ctx context.Context,
+	client *rzpsdk.Client,
 	payment map[string]interface{},
 ) (map[string]interface{}, error) {
 	// Extract payment ID and next actions from the response
@@ -661,11 +1441,12 @@ func processPaymentResult(
 	actions := extractNextActions(payment)
 
 	// Build structured response using the helper function
-	response, otpUrl := buildInitiatePaymentResponse(payment, paymentID, actions)
+	response, otpUrl := buildInitiatePaymentResponse(
+		ctx, payment, paymentID, actions)
 
 	// Only send OTP if there's an OTP URL
 	if otpUrl != "" {
-		err := sendOtp(otpUrl)
+		err := sendOtp(client, otpUrl)
 		if err != nil {
 			return nil, fmt.Errorf("OTP generation failed: %s", err.Error())
 		}
@@ -674,15 +1455,100 @@ func processPaymentResult(
 	return response, nil
 }
 
+// processRedirectPaymentResult processes the result of a payment created
+// in redirect mode. The customer authenticates on the page of the
+// redirect action instead of with an OTP, so no OTP is generated.
+// Payments without a redirect action are processed as usual.
+func processRedirectPaymentResult(
+	ctx context.Context,
+	client *rzpsdk.Client,
+	payment map[string]interface{},
+	callbackURL string,
+) (map[string]interface{}, error) {
+	paymentID := extractPaymentID(payment)
+	actions := extractNextActions(payment)
+
+	authenticateURL := gatewayActionURL(actions, "redirect")
+	if authenticateURL == "" {
+		return processPaymentResult(ctx, client, payment)
+	}
+
+	response := map[string]interface{}{
+		"razorpay_payment_id": paymentID,
+		"payment_details":     payment,
+		"status":              "payment_initiated",
+		"message": localize(ctx, "Payment initiated. Redirect the "+
+			"customer to authenticate_url to complete authentication."),
+		"available_actions": actions,
+		"authenticate_url":  authenticateURL,
+	}
+	if callbackURL != "" {
+		response["callback_url"] = callbackURL
+	}
+	if form := redirectForm(actions); form != nil {
+		response["redirect_form"] = form
+	}
+	if html, ok := payment["html"].(string); ok && html != "" {
+		response["redirect_html"] = html
+	}
+
+	nextActions := redirectNextActions(ctx, authenticateURL)
+	if paymentID != "" {
+		nextActions = append(nextActions, fetchPaymentNextAction(paymentID,
+			localize(ctx, "Use 'fetch_payment' to check the payment status "+
+				"once the customer is back on the callback URL.")))
+	}
+	setNextActions(response, nextActions...)
+
+	return response, nil
+}
+
+// redirectForm returns the form the customer must be redirected with when
+// the redirect action is not a plain GET, or nil if there is none
+func redirectForm(actions []map[string]interface{}) map[string]interface{} {
+	for _, action := range actions {
+		if action["action"] != "redirect" {
+			continue
+		}
+		method, _ := action["method"].(string)
+		data, _ := action["data"].(map[string]interface{})
+		if data == nil && (method == "" || strings.EqualFold(method, "get")) {
+			return nil
+		}
+		if method == "" {
+			method = "post"
+		}
+		return map[string]interface{}{
+			"url":    action["url"],
+			"method": strings.ToLower(method),
+			"data":   data,
+		}
+	}
+	return nil
+}
+
+// validateCallbackURL checks that the callback URL of a payment is an
+// absolute http or https URL
+func validateCallbackURL(callbackURL string) error {
+	parsed, err := url.Parse(callbackURL)
+	if err != nil || parsed.Host == "" ||
+		(parsed.Scheme != "https" && parsed.Scheme != "http") {
+		return fmt.Errorf("invalid callback_url: %s, must be an absolute "+
+			"http or https URL", callbackURL)
+	}
+	return nil
+}
+
 // createPaymentWithParams creates a payment using the appropriate API
 // based on the parameters provided
 func createPaymentWithParams(
 	client *rzpsdk.Client,
 	params map[string]interface{},
-	currency, customerID string,
+	currency, customerID, emailDomain string,
 ) (map[string]interface{}, error) {
 	// Build payment data
-	paymentDataPtr := buildPaymentData(params, currency, customerID)
+	paymentDataPtr := buildPaymentData(params, currency, customerID,
+		emailDomain)
 	paymentData := *paymentDataPtr
 
 	// Determine if recurring payment API should be used
@@ -757,7 +1623,8 @@ func InitiatePayment(
 			"vpa",
 			mcpgo.Description("Virtual Payment Address (VPA) for UPI payment. "+
 				"When provided, automatically sets method='upi' and UPI parameters "+
-				"with flow='collect' and expiry_time='6' (e.g., '9876543210@ptsbi')"),
+				"with flow='collect' and expiry_time='6' (e.g., '9876543210@ptsbi'). "+
+				"Check that it exists with validate_vpa first"),
 		),
 		mcpgo.WithBoolean(
 			"upi_intent",
@@ -775,6 +1642,35 @@ func InitiatePayment(
 			mcpgo.Description("Terminal ID to be passed in case of single block "+
 				"multiple debit order."),
 		),
+		mcpgo.WithString(
+			"method",
+			mcpgo.Description("Payment method. Set vpa or upi_intent "+
+				"instead for UPI, and token for saved cards"),
+			mcpgo.Enum("card", "netbanking", "wallet", "upi"),
+		),
+		mcpgo.WithString(
+			"bank",
+			mcpgo.Description("Bank code for netbanking payments, such as "+
+				"HDFC or SBIN. Required if method is netbanking"),
+		),
+		mcpgo.WithString(
+			"wallet",
+			mcpgo.Description("Wallet for wallet payments, such as "+
+				"mobikwik or amazonpay. Required if method is wallet"),
+		),
+		mcpgo.WithString(
+			"callback_url",
+			mcpgo.Description("URL Razorpay sends the customer to after "+
+				"redirect authentication, such as 3DS for cards. "+
+				"Required if redirect is true"),
+		),
+		mcpgo.WithBoolean(
+			"redirect",
+			mcpgo.Description("Authenticate the payment by redirecting the "+
+				"customer to the bank's page instead of with an OTP. The "+
+				"response then has the authenticate_url to open, and the "+
+				"redirect_form to submit if the page must be posted to"),
+		),
 	}
 
 	handler := func(
@@ -801,12 +1697,33 @@ func InitiatePayment(
 			ValidateAndAddOptionalString(params, "vpa").
 			ValidateAndAddOptionalBool(params, "upi_intent").
 			ValidateAndAddOptionalBool(params, "recurring").
-			ValidateAndAddOptionalString(params, "force_terminal_id")
+			ValidateAndAddOptionalString(params, "force_terminal_id").
+			ValidateAndAddOptionalString(params, "method").
+			ValidateAndAddOptionalString(params, "bank").
+			ValidateAndAddOptionalString(params, "wallet").
+			ValidateAndAddOptionalString(params, "callback_url").
+			ValidateAndAddOptionalBool(params, "redirect")
 
 		if result, err := validator.HandleErrorsIfAny(); result != nil {
 			return result, err
 		}
 
+		if err := validatePaymentMethod(params); err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		callbackURL, _ := params["callback_url"].(string)
+		redirect, _ := params["redirect"].(bool)
+		if redirect && callbackURL == "" {
+			return mcpgo.NewToolResultError(
+				"callback_url is required when redirect is true"), nil
+		}
+		if callbackURL != "" {
+			if err := validateCallbackURL(callbackURL); err != nil {
+				return mcpgo.NewToolResultError(err.Error()), nil
+			}
+		}
+
 		// Set default currency
 		currency := "INR"
 		if c, exists := params["currency"]; exists && c != "" {
@@ -834,14 +1751,21 @@ func InitiatePayment(
 		}
 
 		// Create payment
-		payment, err := createPaymentWithParams(client, params, currency, customerID)
+		payment, err := createPaymentWithParams(client, params, currency,
+			customerID, generatedEmailDomain(ctx))
 		if err != nil {
 			return mcpgo.NewToolResultError(
 				fmt.Sprintf("initiating payment failed: %s", err.Error())), nil
 		}
 
 		// Process payment result
-		response, err := processPaymentResult(payment)
+		var response map[string]interface{}
+		if redirect {
+			response, err = processRedirectPaymentResult(ctx, client,
+				payment, callbackURL)
+		} else {
+			response, err = processPaymentResult(ctx, client, payment)
+		}
 		if err != nil {
 			return mcpgo.NewToolResultError(err.Error()), nil
 		}
@@ -858,12 +1782,155 @@ func InitiatePayment(
 			"which automatically sets UPI with flow='collect' and expiry_time='6'. "+
 			"For UPI intent flow, set 'upi_intent=true' parameter "+
 			"which automatically sets UPI with flow='intent' and API returns UPI URL. "+
+			"For netbanking, set method='netbanking' with the 'bank' code, "+
+			"and for wallets, method='wallet' with the 'wallet'. "+
+			"For 3DS and other redirect authentication, set 'redirect=true' "+
+			"with a 'callback_url' to get the authenticate_url to send the "+
+			"customer to. "+
 			"Supports additional parameters like customer_id, email, "+
 			"contact, save, and recurring. "+
 			"Returns payment details including next action steps if required.",
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(objectOutputSchema).WithAmounts("amount")
+}
+
+// GenerateOtp returns a tool that generates the OTP of a payment through
+// its otp_generate action
+func GenerateOtp(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"payment_id",
+			mcpgo.Description("Unique identifier of the payment for which "+
+				"OTP needs to be generated. Must start with 'pay_'"),
+			mcpgo.Required(),
+		),
+		mcpgo.WithString(
+			"otp_generate_url",
+			mcpgo.Description("URL of the otp_generate action in the "+
+				"available_actions returned by initiate_payment. Looked up "+
+				"from the payment if not provided"),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		// Get client from context or use default
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		params := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(params, "payment_id").
+			ValidateAndAddOptionalString(params, "otp_generate_url")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		paymentID := params["payment_id"].(string)
+
+		if result := validateID("payment_id", paymentID,
+			paymentIDPrefix); result != nil {
+			return result, nil
+		}
+
+		// Discover the OTP generate URL from the payment if not provided
+		otpURL, _ := params["otp_generate_url"].(string)
+		if otpURL == "" {
+			payment, err := client.Payment.Fetch(paymentID, nil, nil)
+			if err != nil {
+				return mcpgo.NewToolResultError(
+					fmt.Sprintf("fetching payment failed: %s",
+						err.Error())), nil
+			}
+			otpURL = gatewayActionURL(extractNextActions(payment),
+				"otp_generate")
+			if otpURL == "" {
+				return mcpgo.NewToolResultError(fmt.Sprintf(
+					"payment %s has no otp_generate action, pass the "+
+						"otp_generate_url returned by initiate_payment",
+					paymentID)), nil
+			}
+		}
+
+		parsedURL, err := otpGenerateURL(otpURL, paymentID)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		// Send the request through the client, so that it is authenticated
+		// and routed like every other Razorpay API request
+		otpResponse, err := client.Request.Post(
+			parsedURL.RequestURI(), nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("OTP generation failed: %s", err.Error())), nil
+		}
+
+		// Extract OTP submit URL from response
+		otpSubmitURL := extractOtpSubmitURL(otpResponse)
+
+		response := map[string]interface{}{
+			"payment_id": paymentID,
+			"status":     "success",
+			"message": localize(ctx, "OTP sent successfully. Please enter "+
+				"the OTP received on your mobile number to complete the "+
+				"payment."),
+			"response_data": otpResponse,
+		}
+
+		nextActions := []NextAction{
+			submitOtpNextAction(ctx, paymentID, otpSubmitURL),
+		}
+		if otpSubmitURL != "" {
+			response["otp_submit_url"] = otpSubmitURL
+			nextActions = append(nextActions, urlNextAction(nextActionOTPSubmit,
+				localize(ctx, "URL at which the OTP can be submitted directly."),
+				otpSubmitURL))
+		}
+		setNextActions(response, nextActions...)
+
+		return mcpgo.NewToolResultJSON(response)
+	}
+
+	return mcpgo.NewTool(
+		"generate_otp",
+		"Generate the OTP of a payment initiated with initiate_payment "+
+			"that needs OTP authentication, sending it to the customer's "+
+			"mobile number. Returns the otp_submit_url and the next step "+
+			"to submit the OTP with submit_otp. Use resend_otp if the OTP "+
+			"was already generated but not received.",
+		parameters,
+		handler,
+	).WithOutputSchema(objectOutputSchema)
+}
+
+// otpGenerateURL validates the otp_generate URL of a payment. Besides
+// being a Razorpay URL, it must point to the OTP generate endpoint of the
+// payment, since the request is sent with the merchant's credentials.
+func otpGenerateURL(otpURL, paymentID string) (*url.URL, error) {
+	parsedURL, err := validateOtpURL(otpURL)
+	if err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("/%s%s/%s/otp_generate",
+		constants.VERSION_V1, constants.PAYMENT_URL, paymentID)
+	if parsedURL.EscapedPath() != path {
+		return nil, fmt.Errorf("OTP URL is not the otp_generate URL "+
+			"of payment %s", paymentID)
+	}
+
+	return parsedURL, nil
 }
 
 // ResendOtp returns a tool that sends OTP for payment authentication
@@ -902,6 +1969,11 @@ func ResendOtp(
 
 		paymentID := params["payment_id"].(string)
 
+		if result := validateID("payment_id", paymentID,
+			paymentIDPrefix); result != nil {
+			return result, nil
+		}
+
 		// Resend OTP using Razorpay SDK
 		otpResponse, err := client.Payment.OtpResend(paymentID, nil, nil)
 		if err != nil {
@@ -916,30 +1988,23 @@ func ResendOtp(
 		response := map[string]interface{}{
 			"payment_id": paymentID,
 			"status":     "success",
-			"message": "OTP sent successfully. Please enter the OTP received on your " +
-				"mobile number to complete the payment.",
+			"message": localize(ctx, "OTP sent successfully. Please enter "+
+				"the OTP received on your mobile number to complete the "+
+				"payment."),
 			"response_data": otpResponse,
 		}
 
-		// Add next step instructions if OTP submit URL is available
+		// Add next step instructions, including the OTP submit URL if available
+		nextActions := []NextAction{
+			submitOtpNextAction(ctx, paymentID, otpSubmitURL),
+		}
 		if otpSubmitURL != "" {
 			response["otp_submit_url"] = otpSubmitURL
-			response["next_step"] = "Use 'submit_otp' tool with the OTP code received " +
-				"from user to complete payment authentication."
-			response["next_tool"] = "submit_otp"
-			response["next_tool_params"] = map[string]interface{}{
-				"payment_id": paymentID,
-				"otp_string": "{OTP_CODE_FROM_USER}",
-			}
-		} else {
-			response["next_step"] = "Use 'submit_otp' tool with the OTP code received " +
-				"from user to complete payment authentication."
-			response["next_tool"] = "submit_otp"
-			response["next_tool_params"] = map[string]interface{}{
-				"payment_id": paymentID,
-				"otp_string": "{OTP_CODE_FROM_USER}",
-			}
+			nextActions = append(nextActions, urlNextAction(nextActionOTPSubmit,
+				localize(ctx, "URL at which the OTP can be submitted directly."),
+				otpSubmitURL))
 		}
+		setNextActions(response, nextActions...)
 
 		result, err := mcpgo.NewToolResultJSON(response)
 		if err != nil {
@@ -955,7 +2020,7 @@ func ResendOtp(
 			"OTP was not received or has expired.",
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(objectOutputSchema)
 }
 
 // SubmitOtp returns a tool that submits OTP for payment verification
@@ -975,6 +2040,12 @@ func SubmitOtp(
 				"OTP needs to be submitted. Must start with 'pay_'"),
 			mcpgo.Required(),
 		),
+		mcpgo.WithString(
+			"otp_submit_url",
+			mcpgo.Description("otp_submit URL returned by generate_otp or "+
+				"resend_otp. The OTP is posted to it if the payment's "+
+				"gateway does not accept OTPs submitted through the API"),
+		),
 	}
 
 	handler := func(
@@ -991,18 +2062,32 @@ func SubmitOtp(
 
 		validator := NewValidator(&r).
 			ValidateAndAddRequiredString(params, "otp_string").
-			ValidateAndAddRequiredString(params, "payment_id")
+			ValidateAndAddRequiredString(params, "payment_id").
+			ValidateAndAddOptionalString(params, "otp_submit_url")
 
 		if result, err := validator.HandleErrorsIfAny(); result != nil {
 			return result, err
 		}
 
 		paymentID := params["payment_id"].(string)
+
+		if result := validateID("payment_id", paymentID,
+			paymentIDPrefix); result != nil {
+			return result, nil
+		}
+
 		data := map[string]interface{}{
 			"otp": params["otp_string"].(string),
 		}
 		otpResponse, err := client.Payment.OtpSubmit(paymentID, data, nil)
 
+		// Fall back to the otp_submit URL of the payment, which works for
+		// payments authenticated by the issuer's native OTP
+		submitURL, _ := params["otp_submit_url"].(string)
+		if err != nil && submitURL != "" {
+			otpResponse, err = submitOtpToURL(client, submitURL,
+				paymentID, data)
+		}
 		if err != nil {
 			return mcpgo.NewToolResultError(
 				fmt.Sprintf("OTP verification failed: %s", err.Error())), nil
@@ -1012,9 +2097,12 @@ func SubmitOtp(
 		response := map[string]interface{}{
 			"payment_id":    paymentID,
 			"status":        "success",
-			"message":       "OTP verified successfully.",
+			"message":       localize(ctx, "OTP verified successfully."),
 			"response_data": otpResponse,
 		}
+		setNextActions(response, fetchPaymentNextAction(paymentID,
+			localize(ctx, "Use 'fetch_payment' to check the final status "+
+				"of the payment.")))
 		result, err := mcpgo.NewToolResultJSON(response)
 		if err != nil {
 			return mcpgo.NewToolResultError(
@@ -1026,10 +2114,141 @@ func SubmitOtp(
 	return mcpgo.NewTool(
 		"submit_otp",
 		"Verify and submit the OTP received by the customer to complete "+
-			"the payment authentication process.",
+			"the payment authentication process. Pass the otp_submit_url "+
+			"returned by generate_otp or resend_otp, so that OTPs of "+
+			"issuers that authenticate natively can be submitted as well.",
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(objectOutputSchema)
+}
+
+// otpAttemptsError is returned for OTPs the otp_submit URL rejected. It
+// carries the number of attempts left if Razorpay returned it.
+type otpAttemptsError struct {
+	description  string
+	attemptsLeft interface{}
+}
+
+func (e *otpAttemptsError) Error() string {
+	if e.attemptsLeft == nil {
+		return e.description
+	}
+	return fmt.Sprintf("%s, attempts left: %v", e.description, e.attemptsLeft)
+}
+
+// submitOtpToURL posts the OTP to the otp_submit URL of a payment. Like
+// generate_otp, the request is sent to the API host of the client with the
+// path of the URL, once the URL is validated.
+func submitOtpToURL(
+	client *rzpsdk.Client,
+	submitURL string,
+	paymentID string,
+	data map[string]interface{},
+) (map[string]interface{}, error) {
+	parsedURL, err := validateOtpURL(submitURL)
+	if err != nil {
+		return nil, err
+	}
+	prefix := fmt.Sprintf("/%s%s/%s/",
+		constants.VERSION_V1, constants.PAYMENT_URL, paymentID)
+	rest, ok := strings.CutPrefix(parsedURL.EscapedPath(), prefix)
+	if !ok || (!strings.HasPrefix(rest, "otp_submit") &&
+		!strings.HasPrefix(rest, "otp/submit")) {
+		return nil, fmt.Errorf("OTP URL is not the otp_submit URL "+
+			"of payment %s", paymentID)
+	}
+
+	return postToOtpURL(client, parsedURL, data)
+}
+
+// postToOtpURL posts the data to the path of the OTP URL on the API host of
+// the client. The SDK only returns the description of API errors, so the
+// error response is read from the transport to return its attempts left.
+func postToOtpURL(
+	client *rzpsdk.Client,
+	otpURL *url.URL,
+	data map[string]interface{},
+) (map[string]interface{}, error) {
+	recorder := &errorResponseTransport{}
+	recording := withTransport(client, func(
+		next http.RoundTripper,
+	) http.RoundTripper {
+		recorder.next = next
+		return recorder
+	})
+
+	response, err := recording.Request.Post(otpURL.RequestURI(), data, nil)
+	if recorder.status == 0 {
+		return response, err
+	}
+
+	errorResponse := make(map[string]interface{})
+	_ = json.Unmarshal(recorder.body, &errorResponse)
+	description := fmt.Sprintf("HTTP status %d", recorder.status)
+	if apiErr, ok := errorResponse["error"].(map[string]interface{}); ok {
+		if d, ok := apiErr["description"].(string); ok && d != "" {
+			description = d
+		}
+	}
+	return nil, &otpAttemptsError{
+		description:  description,
+		attemptsLeft: otpAttemptsLeft(errorResponse),
+	}
+}
+
+// errorResponseTransport keeps the status and body of the error response
+// of the request it sends
+type errorResponseTransport struct {
+	next   http.RoundTripper
+	status int
+	body   []byte
+}
+
+func (t *errorResponseTransport) RoundTrip(
+	req *http.Request,
+) (*http.Response, error) {
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	resp, err := next.RoundTrip(req)
+	// The SDK treats every status from 300 as an error
+	if err != nil || resp.StatusCode < http.StatusMultipleChoices {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxAPIErrorSize))
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.status, t.body = resp.StatusCode, body
+	return resp, nil
+}
+
+// otpAttemptsLeft returns the number of OTP attempts left in a response of
+// the otp_submit URL, or nil if it has none. It is looked up at the top
+// level, in the error and in the metadata of the error.
+func otpAttemptsLeft(response map[string]interface{}) interface{} {
+	candidates := []map[string]interface{}{response}
+	if apiErr, ok := response["error"].(map[string]interface{}); ok {
+		candidates = append(candidates, apiErr)
+		if metadata, ok := apiErr["metadata"].(map[string]interface{}); ok {
+			candidates = append(candidates, metadata)
+		}
+	}
+
+	for _, candidate := range candidates {
+		for _, key := range []string{"attempts_left", "remaining_attempts"} {
+			if value, ok := candidate[key]; ok && value != nil {
+				return value
+			}
+		}
+	}
+	return nil
 }
 
 // extractOtpSubmitURL extracts the OTP submit URL from the payment response
@@ -8,8 +8,13 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
 	"github.com/razorpay/razorpay-go/constants"
 
+	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
 	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
 	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay/mock"
 )
@@ -83,6 +88,138 @@ func Test_FetchPayment(t *testing.T) {
 			runToolTest(t, tc, FetchPayment, "Payment")
 		})
 	}
+
+	t.Run("sends every expand value", func(t *testing.T) {
+		var expand []string
+		mockClient := func() (*http.Client, *httptest.Server) {
+			server := httptest.NewServer(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					expand = r.URL.Query()["expand[]"]
+					_, _ = w.Write([]byte(`{"id":"pay_MT48CvBhIC98MQ"}`))
+				}))
+			return server.Client(), server
+		}
+
+		client, server := newMockRzpClient(mockClient)
+		defer server.Close()
+
+		tool := FetchPayment(CreateTestObservability(), client)
+		result, err := tool.GetHandler()(context.Background(),
+			createMCPRequest(map[string]interface{}{
+				"payment_id": "pay_MT48CvBhIC98MQ",
+				"expand":     []interface{}{"card", "offers"},
+			}))
+		assert.NoError(t, err)
+		assert.False(t, result.IsError, result.Text)
+		assert.Equal(t, []string{"card", "offers"}, expand)
+	})
+}
+
+func Test_WaitForPaymentStatus(t *testing.T) {
+	shortenPollUnit(t)
+
+	paymentPath := fmt.Sprintf("/%s%s/%s", constants.VERSION_V1,
+		constants.PAYMENT_URL, "pay_MT48CvBhIC98MQ")
+
+	payment := func(status string) map[string]interface{} {
+		return map[string]interface{}{
+			"id":     "pay_MT48CvBhIC98MQ",
+			"entity": "payment",
+			"amount": float64(1000),
+			"method": "upi",
+			"status": status,
+		}
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "collect request approved",
+			Request: map[string]interface{}{
+				"payment_id":    "pay_MT48CvBhIC98MQ",
+				"poll_interval": float64(1),
+			},
+			MockHttpClient: sequenceClient(paymentPath, payment("created"),
+				payment("authorized"), payment("captured")),
+			ExpectError: false,
+			ExpectedResult: map[string]interface{}{
+				"payment_id": "pay_MT48CvBhIC98MQ",
+				"status":     "captured",
+				"completed":  true,
+				"polls":      float64(3),
+				"payment":    payment("captured"),
+			},
+		},
+		{
+			Name: "custom terminal statuses",
+			Request: map[string]interface{}{
+				"payment_id":        "pay_MT48CvBhIC98MQ",
+				"poll_interval":     float64(1),
+				"terminal_statuses": []interface{}{"authorized", "failed"},
+			},
+			MockHttpClient: sequenceClient(paymentPath, payment("created"),
+				payment("authorized"), payment("captured")),
+			ExpectError: false,
+			ExpectedResult: map[string]interface{}{
+				"payment_id": "pay_MT48CvBhIC98MQ",
+				"status":     "authorized",
+				"completed":  true,
+				"polls":      float64(2),
+				"payment":    payment("authorized"),
+			},
+		},
+		{
+			Name: "invalid terminal statuses",
+			Request: map[string]interface{}{
+				"payment_id":        "pay_MT48CvBhIC98MQ",
+				"terminal_statuses": []interface{}{float64(1)},
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "terminal_statuses must be a list of payment " +
+				"statuses",
+		},
+		{
+			Name: "poll interval below the minimum",
+			Request: map[string]interface{}{
+				"payment_id":    "pay_MT48CvBhIC98MQ",
+				"poll_interval": float64(0),
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "poll_interval must be between 1 and 60",
+		},
+		{
+			Name:           "missing payment_id",
+			Request:        map[string]interface{}{},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: payment_id",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, WaitForPaymentStatus, "Payment")
+		})
+	}
+
+	t.Run("returns the pending payment at the timeout", func(t *testing.T) {
+		client, server := newMockRzpClient(
+			sequenceClient(paymentPath, payment("created")))
+		defer server.Close()
+
+		tool := WaitForPaymentStatus(CreateTestObservability(), client)
+		result, err := tool.GetHandler()(context.Background(),
+			createMCPRequest(map[string]interface{}{
+				"payment_id":    "pay_MT48CvBhIC98MQ",
+				"poll_interval": float64(5),
+				"timeout":       float64(20),
+			}))
+		assert.NoError(t, err)
+		assert.False(t, result.IsError, result.Text)
+		assert.Contains(t, result.Text, `"completed":false`)
+		assert.Contains(t, result.Text, `"status":"created"`)
+	})
 }
 
 func Test_FetchPaymentCardDetails(t *testing.T) {
@@ -215,6 +352,32 @@ func Test_CapturePayment(t *testing.T) {
 		},
 	}
 
+	fetchPaymentPath := fmt.Sprintf("/%s%s/%s", constants.VERSION_V1,
+		constants.PAYMENT_URL, "pay_G3P9vcIhRs3NV4")
+
+	authorizedPaymentResp := map[string]interface{}{
+		"id":       "pay_G3P9vcIhRs3NV4",
+		"amount":   float64(1000),
+		"currency": "INR",
+		"status":   "authorized",
+	}
+
+	amountMismatchResp := map[string]interface{}{
+		"error": map[string]interface{}{
+			"code": "BAD_REQUEST_ERROR",
+			"description": "Capture amount must be equal to the amount " +
+				"authorized",
+		},
+	}
+
+	notAuthorizedResp := map[string]interface{}{
+		"error": map[string]interface{}{
+			"code": "BAD_REQUEST_ERROR",
+			"description": "Only payments which have been authorized " +
+				"and not yet captured can be captured",
+		},
+	}
+
 	tests := []RazorpayToolTestCase{
 		{
 			Name: "successful payment capture",
@@ -255,6 +418,69 @@ func Test_CapturePayment(t *testing.T) {
 			ExpectedErrMsg: "capturing payment failed: This payment has already been " +
 				"captured",
 		},
+		{
+			Name: "partial capture not enabled",
+			Request: map[string]interface{}{
+				"payment_id": "pay_G3P9vcIhRs3NV4",
+				"amount":     float64(500),
+				"currency":   "INR",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     fmt.Sprintf(capturePaymentPathFmt, "pay_G3P9vcIhRs3NV4"),
+						Method:   "POST",
+						Response: amountMismatchResp,
+					},
+					mock.Endpoint{
+						Path:     fetchPaymentPath,
+						Method:   "GET",
+						Response: authorizedPaymentResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: `"authorized_amount":1000,` +
+				`"currency":"INR",` +
+				`"error":"capturing payment failed: Capture amount must be ` +
+				`equal to the amount authorized",` +
+				`"hint":"Capturing less than the authorized amount needs ` +
+				`partial capture enabled on the account. Capture the ` +
+				`authorized amount of 1000 instead",` +
+				`"payment_id":"pay_G3P9vcIhRs3NV4",` +
+				`"requested_amount":500,"status":"authorized"`,
+		},
+		{
+			Name: "late authorized payment already refunded",
+			Request: map[string]interface{}{
+				"payment_id": "pay_G3P9vcIhRs3NV4",
+				"amount":     float64(1000),
+				"currency":   "INR",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				refundedPaymentResp := map[string]interface{}{
+					"id":       "pay_G3P9vcIhRs3NV4",
+					"amount":   float64(1000),
+					"currency": "INR",
+					"status":   "refunded",
+				}
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     fmt.Sprintf(capturePaymentPathFmt, "pay_G3P9vcIhRs3NV4"),
+						Method:   "POST",
+						Response: notAuthorizedResp,
+					},
+					mock.Endpoint{
+						Path:     fetchPaymentPath,
+						Method:   "GET",
+						Response: refundedPaymentResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: `"hint":"The payment can no longer be ` +
+				`captured. Payments authorized late`,
+		},
 		{
 			Name: "missing payment_id parameter",
 			Request: map[string]interface{}{
@@ -445,6 +671,193 @@ func Test_UpdatePayment(t *testing.T) {
 	}
 }
 
+func Test_BulkCapturePayments(t *testing.T) {
+	paymentsPath := fmt.Sprintf(
+		"/%s%s",
+		constants.VERSION_V1,
+		constants.PAYMENT_URL,
+	)
+	paymentPathFmt := paymentsPath + "/%s"
+	capturePathFmt := paymentsPath + "/%s/capture"
+
+	authorizedPayment := map[string]interface{}{
+		"id":       "pay_G3P9vcIhRs3NV4",
+		"entity":   "payment",
+		"amount":   float64(1000),
+		"currency": "INR",
+		"status":   "authorized",
+	}
+	capturedPayment := map[string]interface{}{
+		"id":       "pay_G3P9vcIhRs3NV4",
+		"entity":   "payment",
+		"amount":   float64(1000),
+		"currency": "INR",
+		"status":   "captured",
+		"captured": true,
+	}
+	failedPayment := map[string]interface{}{
+		"id":       "pay_G3P9vcIhRs3NV5",
+		"entity":   "payment",
+		"amount":   float64(2000),
+		"currency": "INR",
+		"status":   "failed",
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "captures payments by ID with per-payment results",
+			Request: map[string]interface{}{
+				"payment_ids": []interface{}{
+					"pay_G3P9vcIhRs3NV4",
+					"pay_G3P9vcIhRs3NV5",
+					"order_G3P9vcIhRs3NV6",
+				},
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     fmt.Sprintf(paymentPathFmt, "pay_G3P9vcIhRs3NV4"),
+						Method:   "GET",
+						Response: authorizedPayment,
+					},
+					mock.Endpoint{
+						Path:     fmt.Sprintf(paymentPathFmt, "pay_G3P9vcIhRs3NV5"),
+						Method:   "GET",
+						Response: failedPayment,
+					},
+					mock.Endpoint{
+						Path:     fmt.Sprintf(capturePathFmt, "pay_G3P9vcIhRs3NV4"),
+						Method:   "POST",
+						Response: capturedPayment,
+					},
+				)
+			},
+			ExpectedResult: map[string]interface{}{
+				"count":     float64(3),
+				"succeeded": float64(1),
+				"failed":    float64(2),
+				"results": []interface{}{
+					map[string]interface{}{
+						"payment_id": "pay_G3P9vcIhRs3NV4",
+						"status":     "captured",
+						"payment":    capturedPayment,
+					},
+					map[string]interface{}{
+						"payment_id": "pay_G3P9vcIhRs3NV5",
+						"status":     "failed",
+						"error": "payment is failed, only authorized " +
+							"payments can be captured",
+					},
+					map[string]interface{}{
+						"payment_id": "order_G3P9vcIhRs3NV6",
+						"status":     "failed",
+						"error":      "invalid payment ID: must start with 'pay_'",
+					},
+				},
+			},
+		},
+		{
+			Name: "captures the authorized payments of a window",
+			Request: map[string]interface{}{
+				"from": float64(1605871400),
+				"to":   float64(1605871500),
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:   paymentsPath,
+						Method: "GET",
+						Response: map[string]interface{}{
+							"entity": "collection",
+							"count":  float64(2),
+							"items": []interface{}{
+								authorizedPayment,
+								failedPayment,
+							},
+						},
+					},
+					mock.Endpoint{
+						Path:     fmt.Sprintf(capturePathFmt, "pay_G3P9vcIhRs3NV4"),
+						Method:   "POST",
+						Response: capturedPayment,
+					},
+				)
+			},
+			ExpectedResult: map[string]interface{}{
+				"count":     float64(1),
+				"succeeded": float64(1),
+				"failed":    float64(0),
+				"results": []interface{}{
+					map[string]interface{}{
+						"payment_id": "pay_G3P9vcIhRs3NV4",
+						"status":     "captured",
+						"payment":    capturedPayment,
+					},
+				},
+			},
+		},
+		{
+			Name: "reports capture errors",
+			Request: map[string]interface{}{
+				"payment_ids": []interface{}{"pay_G3P9vcIhRs3NV4"},
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     fmt.Sprintf(paymentPathFmt, "pay_G3P9vcIhRs3NV4"),
+						Method:   "GET",
+						Response: authorizedPayment,
+					},
+					mock.Endpoint{
+						Path:   fmt.Sprintf(capturePathFmt, "pay_G3P9vcIhRs3NV4"),
+						Method: "POST",
+						Response: map[string]interface{}{
+							"error": map[string]interface{}{
+								"code":        "BAD_REQUEST_ERROR",
+								"description": "Capture amount must be equal",
+							},
+						},
+					},
+				)
+			},
+			ExpectedResult: map[string]interface{}{
+				"count":     float64(1),
+				"succeeded": float64(0),
+				"failed":    float64(1),
+				"results": []interface{}{
+					map[string]interface{}{
+						"payment_id": "pay_G3P9vcIhRs3NV4",
+						"status":     "failed",
+						"error": "capturing payment failed: " +
+							"Capture amount must be equal",
+					},
+				},
+			},
+		},
+		{
+			Name: "rejects payment IDs together with a window",
+			Request: map[string]interface{}{
+				"payment_ids": []interface{}{"pay_G3P9vcIhRs3NV4"},
+				"from":        float64(1605871400),
+			},
+			ExpectError:    true,
+			ExpectedErrMsg: "provide either payment_ids or from and to, not both",
+		},
+		{
+			Name:           "requires payment IDs or a window",
+			Request:        map[string]interface{}{"from": float64(1605871400)},
+			ExpectError:    true,
+			ExpectedErrMsg: "provide payment_ids, or both from and to",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, BulkCapturePayments, "Bulk Capture")
+		})
+	}
+}
+
 func Test_FetchAllPayments(t *testing.T) {
 	fetchAllPaymentsPath := fmt.Sprintf(
 		"/%s%s",
@@ -579,6 +992,34 @@ func Test_FetchAllPayments(t *testing.T) {
 			ExpectedErrMsg: "fetching payments failed: from must be between " +
 				"946684800 and 4765046400",
 		},
+		{
+			Name: "auto paginated payments fetch stops at a short page",
+			Request: map[string]interface{}{
+				"auto_paginate": true,
+				"max_results":   float64(5),
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     fetchAllPaymentsPath,
+						Method:   "GET",
+						Response: paymentsListResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: paymentsListResp,
+		},
+		{
+			Name: "auto paginated payments fetch with too many results",
+			Request: map[string]interface{}{
+				"auto_paginate": true,
+				"max_results":   float64(5000),
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "max_results must be between 1 and 1000",
+		},
 		{
 			Name: "multiple validation errors with wrong types",
 			Request: map[string]interface{}{
@@ -604,22 +1045,182 @@ func Test_FetchAllPayments(t *testing.T) {
 	}
 }
 
-func Test_InitiatePayment(t *testing.T) {
-	initiatePaymentPath := fmt.Sprintf(
-		"/%s%s/create/json",
+func Test_SearchPayments(t *testing.T) {
+	fetchAllPaymentsPath := fmt.Sprintf(
+		"/%s%s",
 		constants.VERSION_V1,
 		constants.PAYMENT_URL,
 	)
 
-	recurringPaymentPath := fmt.Sprintf(
-		"/%s%s/create/recurring",
-		constants.VERSION_V1,
-		constants.PAYMENT_URL,
-	)
+	upiPayment := map[string]interface{}{
+		"id":       "pay_KbCEDHh1IrU4RJ",
+		"entity":   "payment",
+		"amount":   float64(50000),
+		"currency": "INR",
+		"status":   "captured",
+		"method":   "upi",
+		"order_id": "order_KbCE5ndGPoCBsZ",
+		"email":    "Gaurav.Kumar@example.com",
+		"contact":  "+919000090000",
+		"notes": map[string]interface{}{
+			"plan": "gold",
+		},
+	}
+	cardPayment := map[string]interface{}{
+		"id":       "pay_KbCFyQ0t9Lmi1n",
+		"entity":   "payment",
+		"amount":   float64(1000),
+		"currency": "INR",
+		"status":   "failed",
+		"method":   "card",
+		"order_id": nil,
+		"email":    "saanvi@example.com",
+		"contact":  "+919876543210",
+		"notes":    []interface{}{},
+	}
+	paymentsListResp := map[string]interface{}{
+		"entity": "collection",
+		"count":  float64(2),
+		"items":  []interface{}{upiPayment, cardPayment},
+	}
 
-	createCustomerPath := fmt.Sprintf(
-		"/%s%s",
-		constants.VERSION_V1,
+	mockPayments := func() (*http.Client, *httptest.Server) {
+		return mock.NewHTTPClient(
+			mock.Endpoint{
+				Path:     fetchAllPaymentsPath,
+				Method:   "GET",
+				Response: paymentsListResp,
+			},
+		)
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "search by customer and notes",
+			Request: map[string]interface{}{
+				"email":   "gaurav.kumar@example.com",
+				"contact": "9000090000",
+				"notes":   map[string]interface{}{"plan": "gold"},
+			},
+			MockHttpClient: mockPayments,
+			ExpectedResult: map[string]interface{}{
+				"entity":  "collection",
+				"count":   float64(1),
+				"items":   []interface{}{upiPayment},
+				"scanned": float64(2),
+			},
+		},
+		{
+			Name: "search by status, method and amount range",
+			Request: map[string]interface{}{
+				"status":     "failed",
+				"method":     "card",
+				"min_amount": float64(500),
+				"max_amount": float64(1000),
+			},
+			MockHttpClient: mockPayments,
+			ExpectedResult: map[string]interface{}{
+				"entity":  "collection",
+				"count":   float64(1),
+				"items":   []interface{}{cardPayment},
+				"scanned": float64(2),
+			},
+		},
+		{
+			Name: "search returns next_skip when stopped at max_results",
+			Request: map[string]interface{}{
+				"order_id":    "order_KbCE5ndGPoCBsZ",
+				"skip":        float64(10),
+				"max_results": float64(1),
+			},
+			MockHttpClient: mockPayments,
+			ExpectedResult: map[string]interface{}{
+				"entity":    "collection",
+				"count":     float64(1),
+				"items":     []interface{}{upiPayment},
+				"scanned":   float64(1),
+				"next_skip": float64(11),
+			},
+		},
+		{
+			Name: "search without matches",
+			Request: map[string]interface{}{
+				"min_amount": float64(100000),
+			},
+			MockHttpClient: mockPayments,
+			ExpectedResult: map[string]interface{}{
+				"entity":  "collection",
+				"count":   float64(0),
+				"items":   []interface{}{},
+				"scanned": float64(2),
+			},
+		},
+		{
+			Name: "search with invalid filter types",
+			Request: map[string]interface{}{
+				"notes":      "plan=gold",
+				"min_amount": "500",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "Validation errors:\n- " +
+				"invalid parameter type: notes\n- " +
+				"invalid parameter type: min_amount",
+		},
+		{
+			Name:    "search with API error",
+			Request: map[string]interface{}{"status": "captured"},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:   fetchAllPaymentsPath,
+						Method: "GET",
+						Response: map[string]interface{}{
+							"error": map[string]interface{}{
+								"code":        "BAD_REQUEST_ERROR",
+								"description": "The api key provided is invalid",
+							},
+						},
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "searching payments failed: " +
+				"The api key provided is invalid",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, SearchPayments, "Payments Search")
+		})
+	}
+}
+
+func Test_contactMatches(t *testing.T) {
+	assert.True(t, contactMatches("+919000090000", "9000090000"))
+	assert.True(t, contactMatches("+919000090000", "+91 90000 90000"))
+	assert.False(t, contactMatches("+919000090000", "9000090001"))
+	assert.False(t, contactMatches("+919000090000", "+"))
+	assert.False(t, contactMatches("", "9000090000"))
+}
+
+func Test_InitiatePayment(t *testing.T) {
+	initiatePaymentPath := fmt.Sprintf(
+		"/%s%s/create/json",
+		constants.VERSION_V1,
+		constants.PAYMENT_URL,
+	)
+
+	recurringPaymentPath := fmt.Sprintf(
+		"/%s%s/create/recurring",
+		constants.VERSION_V1,
+		constants.PAYMENT_URL,
+	)
+
+	createCustomerPath := fmt.Sprintf(
+		"/%s%s",
+		constants.VERSION_V1,
 		constants.CUSTOMER_URL,
 	)
 
@@ -696,13 +1297,13 @@ func Test_InitiatePayment(t *testing.T) {
 				"status":              "payment_initiated",
 				"message": "Payment initiated successfully using " +
 					"S2S JSON v1 flow",
-				"next_step": "Use 'resend_otp' to regenerate OTP or " +
-					"'submit_otp' to proceed to enter OTP if " +
-					"OTP authentication is required.",
+				"next_step": resendOtpFallbackStep,
 				"next_tool": "resend_otp",
 				"next_tool_params": map[string]interface{}{
 					"payment_id": "pay_MT48CvBhIC98MQ",
 				},
+				"next_actions": expectedOtpNextActions(
+					"pay_MT48CvBhIC98MQ", resendOtpFallbackStep),
 			},
 		},
 		{
@@ -728,6 +1329,11 @@ func Test_InitiatePayment(t *testing.T) {
 				"status":              "payment_initiated",
 				"message": "Payment initiated. Redirect authentication is available. " +
 					"Use the redirect URL provided in available_actions.",
+				"next_actions": []interface{}{
+					expectedURLAction("redirect", redirectStep,
+						"https://api.razorpay.com/v1/payments/"+
+							"pay_MT48CvBhIC98MQ/authenticate"),
+				},
 				"available_actions": []interface{}{
 					map[string]interface{}{
 						"action": "redirect",
@@ -766,13 +1372,13 @@ func Test_InitiatePayment(t *testing.T) {
 				"status":              "payment_initiated",
 				"message": "Payment initiated successfully using " +
 					"S2S JSON v1 flow",
-				"next_step": "Use 'resend_otp' to regenerate OTP or " +
-					"'submit_otp' to proceed to enter OTP if " +
-					"OTP authentication is required.",
+				"next_step": resendOtpFallbackStep,
 				"next_tool": "resend_otp",
 				"next_tool_params": map[string]interface{}{
 					"payment_id": "pay_MT48CvBhIC98MQ",
 				},
+				"next_actions": expectedOtpNextActions(
+					"pay_MT48CvBhIC98MQ", resendOtpFallbackStep),
 			},
 		},
 		{
@@ -898,8 +1504,17 @@ func Test_InitiatePayment(t *testing.T) {
 						},
 					},
 				},
-				"status":  "payment_initiated",
-				"message": "Payment initiated. Available actions: [upi_collect]",
+				"status":    "payment_initiated",
+				"message":   "Payment initiated. Available actions: [upi_collect]",
+				"next_step": upiFetchPaymentStep,
+				"next_tool": "fetch_payment",
+				"next_tool_params": map[string]interface{}{
+					"payment_id": "pay_MT48CvBhIC98MQ",
+				},
+				"next_actions": []interface{}{
+					expectedFetchPaymentAction(
+						"pay_MT48CvBhIC98MQ", upiFetchPaymentStep),
+				},
 				"available_actions": []interface{}{
 					map[string]interface{}{
 						"action": "upi_collect",
@@ -953,13 +1568,13 @@ func Test_InitiatePayment(t *testing.T) {
 				"status": "payment_initiated",
 				"message": "Payment initiated successfully using " +
 					"S2S JSON v1 flow",
-				"next_step": "Use 'resend_otp' to regenerate OTP or " +
-					"'submit_otp' to proceed to enter OTP if " +
-					"OTP authentication is required.",
+				"next_step": resendOtpFallbackStep,
 				"next_tool": "resend_otp",
 				"next_tool_params": map[string]interface{}{
 					"payment_id": "pay_MT48CvBhIC98MQ",
 				},
+				"next_actions": expectedOtpNextActions(
+					"pay_MT48CvBhIC98MQ", resendOtpFallbackStep),
 			},
 		},
 		{
@@ -1010,13 +1625,13 @@ func Test_InitiatePayment(t *testing.T) {
 				"status": "payment_initiated",
 				"message": "Payment initiated successfully using " +
 					"S2S JSON v1 flow",
-				"next_step": "Use 'resend_otp' to regenerate OTP or " +
-					"'submit_otp' to proceed to enter OTP if " +
-					"OTP authentication is required.",
+				"next_step": resendOtpFallbackStep,
 				"next_tool": "resend_otp",
 				"next_tool_params": map[string]interface{}{
 					"payment_id": "pay_MT48CvBhIC98MQ",
 				},
+				"next_actions": expectedOtpNextActions(
+					"pay_MT48CvBhIC98MQ", resendOtpFallbackStep),
 			},
 		},
 		{
@@ -1104,8 +1719,20 @@ func Test_InitiatePayment(t *testing.T) {
 						},
 					},
 				},
-				"status":  "payment_initiated",
-				"message": "Payment initiated. Available actions: [upi_intent]",
+				"status":    "payment_initiated",
+				"message":   "Payment initiated. Available actions: [upi_intent]",
+				"next_step": upiFetchPaymentStep,
+				"next_tool": "fetch_payment",
+				"next_tool_params": map[string]interface{}{
+					"payment_id": "pay_INTENT123",
+				},
+				"next_actions": []interface{}{
+					expectedURLAction("upi_intent", upiIntentStep,
+						"https://api.razorpay.com/v1/payments/"+
+							"pay_INTENT123/upi_intent"),
+					expectedFetchPaymentAction(
+						"pay_INTENT123", upiFetchPaymentStep),
+				},
 				"available_actions": []interface{}{
 					map[string]interface{}{
 						"action": "upi_intent",
@@ -1169,15 +1796,15 @@ func Test_InitiatePayment(t *testing.T) {
 					"method":              "upi",
 					"force_terminal_id":   "term_ABCD1234256732",
 				},
-				"status":  "payment_initiated",
-				"message": "Payment initiated successfully using S2S JSON v1 flow",
-				"next_step": "Use 'resend_otp' to regenerate OTP or " +
-					"'submit_otp' to proceed to enter OTP if " +
-					"OTP authentication is required.",
+				"status":    "payment_initiated",
+				"message":   "Payment initiated successfully using S2S JSON v1 flow",
+				"next_step": resendOtpFallbackStep,
 				"next_tool": "resend_otp",
 				"next_tool_params": map[string]interface{}{
 					"payment_id": "pay_MT48CvBhIC98MQ",
 				},
+				"next_actions": expectedOtpNextActions(
+					"pay_MT48CvBhIC98MQ", resendOtpFallbackStep),
 			},
 		},
 		{
@@ -1224,15 +1851,15 @@ func Test_InitiatePayment(t *testing.T) {
 					"method":              "card",
 					"force_terminal_id":   "term_ABCD1234256732",
 				},
-				"status":  "payment_initiated",
-				"message": "Payment initiated successfully using S2S JSON v1 flow",
-				"next_step": "Use 'resend_otp' to regenerate OTP or " +
-					"'submit_otp' to proceed to enter OTP if " +
-					"OTP authentication is required.",
+				"status":    "payment_initiated",
+				"message":   "Payment initiated successfully using S2S JSON v1 flow",
+				"next_step": resendOtpFallbackStep,
 				"next_tool": "resend_otp",
 				"next_tool_params": map[string]interface{}{
 					"payment_id": "pay_MT48CvBhIC98MQ",
 				},
+				"next_actions": expectedOtpNextActions(
+					"pay_MT48CvBhIC98MQ", resendOtpFallbackStep),
 			},
 		},
 		{
@@ -1272,15 +1899,15 @@ func Test_InitiatePayment(t *testing.T) {
 					"currency":            "USD",
 					"order_id":            "order_129837127313912",
 				},
-				"status":  "payment_initiated",
-				"message": "Payment initiated successfully using S2S JSON v1 flow",
-				"next_step": "Use 'resend_otp' to regenerate OTP or " +
-					"'submit_otp' to proceed to enter OTP if " +
-					"OTP authentication is required.",
+				"status":    "payment_initiated",
+				"message":   "Payment initiated successfully using S2S JSON v1 flow",
+				"next_step": resendOtpFallbackStep,
 				"next_tool": "resend_otp",
 				"next_tool_params": map[string]interface{}{
 					"payment_id": "pay_MT48CvBhIC98MQ",
 				},
+				"next_actions": expectedOtpNextActions(
+					"pay_MT48CvBhIC98MQ", resendOtpFallbackStep),
 			},
 		},
 		{
@@ -1346,7 +1973,111 @@ func Test_SubmitOtp(t *testing.T) {
 		},
 	}
 
+	// The payment is authenticated by the issuer's native OTP, so only its
+	// otp_submit URL accepts the OTP
+	nativeSubmitPath := fmt.Sprintf("/%s%s/pay_MT48CvBhIC98MQ/otp_submit/"+
+		"ac2d415a8be7595d", constants.VERSION_V1, constants.PAYMENT_URL)
+	nativeSubmitURL := "https://api.razorpay.com" + nativeSubmitPath +
+		"?key_id=rzp_test_key"
+	nativeSubmitFailedResp := map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":        "BAD_REQUEST_ERROR",
+			"description": "Invalid OTP provided",
+			"metadata": map[string]interface{}{
+				"attempts_left": float64(2),
+			},
+		},
+	}
+
 	tests := []RazorpayToolTestCase{
+		{
+			Name: "OTP submitted to the otp_submit url after the api fails",
+			Request: map[string]interface{}{
+				"payment_id":     "pay_MT48CvBhIC98MQ",
+				"otp_string":     "123456",
+				"otp_submit_url": nativeSubmitURL,
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     fmt.Sprintf(submitOtpPathFmt, "pay_MT48CvBhIC98MQ"),
+						Method:   "POST",
+						Response: paymentNotFoundResp,
+					},
+					mock.Endpoint{
+						Path:     nativeSubmitPath,
+						Method:   "POST",
+						Response: successOtpSubmitResp,
+					},
+				)
+			},
+			ExpectError: false,
+			ExpectedResult: map[string]interface{}{
+				"payment_id":    "pay_MT48CvBhIC98MQ",
+				"status":        "success",
+				"message":       "OTP verified successfully.",
+				"response_data": successOtpSubmitResp,
+				"next_step":     submitOtpFetchPaymentStep,
+				"next_tool":     "fetch_payment",
+				"next_tool_params": map[string]interface{}{
+					"payment_id": "pay_MT48CvBhIC98MQ",
+				},
+				"next_actions": []interface{}{
+					expectedFetchPaymentAction(
+						"pay_MT48CvBhIC98MQ", submitOtpFetchPaymentStep),
+				},
+			},
+		},
+		{
+			Name: "attempts left of OTPs the otp_submit url rejects",
+			Request: map[string]interface{}{
+				"payment_id":     "pay_MT48CvBhIC98MQ",
+				"otp_string":     "000000",
+				"otp_submit_url": nativeSubmitURL,
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     nativeSubmitPath,
+						Method:   "POST",
+						Response: nativeSubmitFailedResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "OTP verification failed: Invalid OTP provided, " +
+				"attempts left: 2",
+		},
+		{
+			Name: "otp_submit url of another payment",
+			Request: map[string]interface{}{
+				"payment_id": "pay_MT48CvBhIC98MQ",
+				"otp_string": "123456",
+				"otp_submit_url": "https://api.razorpay.com/v1/payments/" +
+					"pay_OTHER/otp_submit/ac2d415a8be7595d",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient()
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "OTP verification failed: OTP URL is not the " +
+				"otp_submit URL of payment pay_MT48CvBhIC98MQ",
+		},
+		{
+			Name: "otp_submit url outside the razorpay domain",
+			Request: map[string]interface{}{
+				"payment_id": "pay_MT48CvBhIC98MQ",
+				"otp_string": "123456",
+				"otp_submit_url": "https://razorpay.com.example.org" +
+					nativeSubmitPath,
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient()
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "OTP verification failed: OTP URL must be from " +
+				"Razorpay domain",
+		},
 		{
 			Name: "successful OTP submission",
 			Request: map[string]interface{}{
@@ -1368,6 +2099,15 @@ func Test_SubmitOtp(t *testing.T) {
 				"status":        "success",
 				"message":       "OTP verified successfully.",
 				"response_data": successOtpSubmitResp,
+				"next_step":     submitOtpFetchPaymentStep,
+				"next_tool":     "fetch_payment",
+				"next_tool_params": map[string]interface{}{
+					"payment_id": "pay_MT48CvBhIC98MQ",
+				},
+				"next_actions": []interface{}{
+					expectedFetchPaymentAction(
+						"pay_MT48CvBhIC98MQ", submitOtpFetchPaymentStep),
+				},
 			},
 		},
 		{
@@ -1460,22 +2200,8 @@ func Test_SubmitOtp(t *testing.T) {
 				"payment_id": "",
 				"otp_string": "123456",
 			},
-			MockHttpClient: func() (*http.Client, *httptest.Server) {
-				return mock.NewHTTPClient(
-					mock.Endpoint{
-						Path:   fmt.Sprintf(submitOtpPathFmt, ""),
-						Method: "POST",
-						Response: map[string]interface{}{
-							"error": map[string]interface{}{
-								"code":        "BAD_REQUEST_ERROR",
-								"description": "",
-							},
-						},
-					},
-				)
-			},
 			ExpectError:    true,
-			ExpectedErrMsg: "OTP verification failed:",
+			ExpectedErrMsg: "invalid payment_id: ",
 		},
 	}
 
@@ -1583,8 +2309,17 @@ func Test_InitiatePaymentWithVPA(t *testing.T) {
 						},
 					},
 				},
-				"status":  "payment_initiated",
-				"message": "Payment initiated. Available actions: [upi_collect]",
+				"status":    "payment_initiated",
+				"message":   "Payment initiated. Available actions: [upi_collect]",
+				"next_step": upiFetchPaymentStep,
+				"next_tool": "fetch_payment",
+				"next_tool_params": map[string]interface{}{
+					"payment_id": "pay_MT48CvBhIC98MQ",
+				},
+				"next_actions": []interface{}{
+					expectedFetchPaymentAction(
+						"pay_MT48CvBhIC98MQ", upiFetchPaymentStep),
+				},
 				"available_actions": []interface{}{
 					map[string]interface{}{
 						"action": "upi_collect",
@@ -1643,13 +2378,13 @@ func Test_InitiatePaymentWithVPA(t *testing.T) {
 				"status": "payment_initiated",
 				"message": "Payment initiated successfully using " +
 					"S2S JSON v1 flow",
-				"next_step": "Use 'resend_otp' to regenerate OTP or " +
-					"'submit_otp' to proceed to enter OTP if " +
-					"OTP authentication is required.",
+				"next_step": resendOtpFallbackStep,
 				"next_tool": "resend_otp",
 				"next_tool_params": map[string]interface{}{
 					"payment_id": "pay_ABC123XYZ456",
 				},
+				"next_actions": expectedOtpNextActions(
+					"pay_ABC123XYZ456", resendOtpFallbackStep),
 			},
 		},
 		{
@@ -1688,13 +2423,13 @@ func Test_InitiatePaymentWithVPA(t *testing.T) {
 				"status": "payment_initiated",
 				"message": "Payment initiated successfully using " +
 					"S2S JSON v1 flow",
-				"next_step": "Use 'resend_otp' to regenerate OTP or " +
-					"'submit_otp' to proceed to enter OTP if " +
-					"OTP authentication is required.",
+				"next_step": resendOtpFallbackStep,
 				"next_tool": "resend_otp",
 				"next_tool_params": map[string]interface{}{
 					"payment_id": "pay_MT48CvBhIC98MQ",
 				},
+				"next_actions": expectedOtpNextActions(
+					"pay_MT48CvBhIC98MQ", resendOtpFallbackStep),
 			},
 		},
 		{
@@ -1733,96 +2468,486 @@ func Test_InitiatePaymentWithVPA(t *testing.T) {
 					},
 				)
 			},
-			ExpectError: false,
-			ExpectedResult: map[string]interface{}{
-				"razorpay_payment_id": "pay_OVERRIDE123",
-				"payment_details": map[string]interface{}{
-					"razorpay_payment_id": "pay_OVERRIDE123",
-					"status":              "created",
-					"amount":              float64(15000),
-					"currency":            "INR",
-					"order_id":            "order_OVERRIDE123",
-					"method":              "upi",
-					"upi": map[string]interface{}{
-						"flow":        "collect",
-						"expiry_time": "6",
-						"vpa":         "new@upi",
-					},
-					"next": []interface{}{
-						map[string]interface{}{
-							"action": "upi_collect",
-							"url": "https://api.razorpay.com/v1/payments/" +
-								"pay_OVERRIDE123/otp_generate",
-						},
-					},
-				},
-				"status":  "payment_initiated",
-				"message": "Payment initiated. Available actions: [upi_collect]",
-				"available_actions": []interface{}{
-					map[string]interface{}{
-						"action": "upi_collect",
-						"url": "https://api.razorpay.com/v1/payments/" +
-							"pay_OVERRIDE123/otp_generate",
-					},
-				},
+			ExpectError: false,
+			ExpectedResult: map[string]interface{}{
+				"razorpay_payment_id": "pay_OVERRIDE123",
+				"payment_details": map[string]interface{}{
+					"razorpay_payment_id": "pay_OVERRIDE123",
+					"status":              "created",
+					"amount":              float64(15000),
+					"currency":            "INR",
+					"order_id":            "order_OVERRIDE123",
+					"method":              "upi",
+					"upi": map[string]interface{}{
+						"flow":        "collect",
+						"expiry_time": "6",
+						"vpa":         "new@upi",
+					},
+					"next": []interface{}{
+						map[string]interface{}{
+							"action": "upi_collect",
+							"url": "https://api.razorpay.com/v1/payments/" +
+								"pay_OVERRIDE123/otp_generate",
+						},
+					},
+				},
+				"status":    "payment_initiated",
+				"message":   "Payment initiated. Available actions: [upi_collect]",
+				"next_step": upiFetchPaymentStep,
+				"next_tool": "fetch_payment",
+				"next_tool_params": map[string]interface{}{
+					"payment_id": "pay_OVERRIDE123",
+				},
+				"next_actions": []interface{}{
+					expectedFetchPaymentAction(
+						"pay_OVERRIDE123", upiFetchPaymentStep),
+				},
+				"available_actions": []interface{}{
+					map[string]interface{}{
+						"action": "upi_collect",
+						"url": "https://api.razorpay.com/v1/payments/" +
+							"pay_OVERRIDE123/otp_generate",
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, InitiatePayment, "Payment")
+		})
+	}
+}
+
+// Test helper functions for better coverage
+func Test_extractPaymentID(t *testing.T) {
+	tests := []struct {
+		name     string
+		payment  map[string]interface{}
+		expected string
+	}{
+		{
+			name: "valid payment ID",
+			payment: map[string]interface{}{
+				"razorpay_payment_id": "pay_MT48CvBhIC98MQ",
+				"status":              "created",
+			},
+			expected: "pay_MT48CvBhIC98MQ",
+		},
+		{
+			name: "missing payment ID",
+			payment: map[string]interface{}{
+				"status": "created",
+			},
+			expected: "",
+		},
+		{
+			name: "nil payment ID",
+			payment: map[string]interface{}{
+				"razorpay_payment_id": nil,
+				"status":              "created",
+			},
+			expected: "",
+		},
+		{
+			name:     "empty payment map",
+			payment:  map[string]interface{}{},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := extractPaymentID(tt.payment)
+			if result != tt.expected {
+				t.Errorf("extractPaymentID() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func Test_InitiatePayment_Redirect(t *testing.T) {
+	initiatePaymentPath := fmt.Sprintf(
+		"/%s%s/create/json",
+		constants.VERSION_V1,
+		constants.PAYMENT_URL,
+	)
+	authenticateURL := "https://api.razorpay.com/v1/payments/" +
+		"pay_MT48CvBhIC98MQ/authenticate"
+	callbackURL := "https://example.com/payments/callback"
+	fetchStep := "Use 'fetch_payment' to check the payment status once " +
+		"the customer is back on the callback URL."
+
+	// The otp_generate action must be ignored in redirect mode, an OTP
+	// request to it would fail the call
+	paymentResp := map[string]interface{}{
+		"razorpay_payment_id": "pay_MT48CvBhIC98MQ",
+		"next": []interface{}{
+			map[string]interface{}{
+				"action": "otp_generate",
+				"url": "https://api.razorpay.com/v1/payments/" +
+					"pay_MT48CvBhIC98MQ/otp_generate",
+			},
+			map[string]interface{}{
+				"action": "redirect",
+				"url":    authenticateURL,
+			},
+		},
+	}
+	formPaymentResp := map[string]interface{}{
+		"razorpay_payment_id": "pay_MT48CvBhIC98MQ",
+		"next": []interface{}{
+			map[string]interface{}{
+				"action": "redirect",
+				"url":    authenticateURL,
+				"method": "POST",
+				"data": map[string]interface{}{
+					"PaReq": "eJxVUk1vgkAQ",
+				},
+			},
+		},
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "returns the authenticate url without generating an OTP",
+			Request: map[string]interface{}{
+				"amount":       10000,
+				"token":        "token_MT48CvBhIC98MQ",
+				"order_id":     "order_129837127313912",
+				"callback_url": callbackURL,
+				"redirect":     true,
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     initiatePaymentPath,
+						Method:   "POST",
+						Response: paymentResp,
+					},
+				)
+			},
+			ExpectError: false,
+			ExpectedResult: map[string]interface{}{
+				"razorpay_payment_id": "pay_MT48CvBhIC98MQ",
+				"payment_details":     paymentResp,
+				"status":              "payment_initiated",
+				"message": "Payment initiated. Redirect the customer to " +
+					"authenticate_url to complete authentication.",
+				"available_actions": paymentResp["next"],
+				"authenticate_url":  authenticateURL,
+				"callback_url":      callbackURL,
+				"next_step":         fetchStep,
+				"next_tool":         "fetch_payment",
+				"next_tool_params": map[string]interface{}{
+					"payment_id": "pay_MT48CvBhIC98MQ",
+				},
+				"next_actions": []interface{}{
+					expectedURLAction("redirect", redirectStep,
+						authenticateURL),
+					expectedFetchPaymentAction(
+						"pay_MT48CvBhIC98MQ", fetchStep),
+				},
+			},
+		},
+		{
+			Name: "returns the redirect form",
+			Request: map[string]interface{}{
+				"amount":       10000,
+				"token":        "token_MT48CvBhIC98MQ",
+				"order_id":     "order_129837127313912",
+				"callback_url": callbackURL,
+				"redirect":     true,
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     initiatePaymentPath,
+						Method:   "POST",
+						Response: formPaymentResp,
+					},
+				)
+			},
+			ExpectError: false,
+			ExpectedResult: map[string]interface{}{
+				"razorpay_payment_id": "pay_MT48CvBhIC98MQ",
+				"payment_details":     formPaymentResp,
+				"status":              "payment_initiated",
+				"message": "Payment initiated. Redirect the customer to " +
+					"authenticate_url to complete authentication.",
+				"available_actions": formPaymentResp["next"],
+				"authenticate_url":  authenticateURL,
+				"callback_url":      callbackURL,
+				"redirect_form": map[string]interface{}{
+					"url":    authenticateURL,
+					"method": "post",
+					"data": map[string]interface{}{
+						"PaReq": "eJxVUk1vgkAQ",
+					},
+				},
+				"next_step": fetchStep,
+				"next_tool": "fetch_payment",
+				"next_tool_params": map[string]interface{}{
+					"payment_id": "pay_MT48CvBhIC98MQ",
+				},
+				"next_actions": []interface{}{
+					expectedURLAction("redirect", redirectStep,
+						authenticateURL),
+					expectedFetchPaymentAction(
+						"pay_MT48CvBhIC98MQ", fetchStep),
+				},
+			},
+		},
+		{
+			Name: "redirect without callback url",
+			Request: map[string]interface{}{
+				"amount":   10000,
+				"order_id": "order_129837127313912",
+				"redirect": true,
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "callback_url is required when redirect is true",
+		},
+		{
+			Name: "relative callback url",
+			Request: map[string]interface{}{
+				"amount":       10000,
+				"order_id":     "order_129837127313912",
+				"callback_url": "/payments/callback",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid callback_url: /payments/callback, " +
+				"must be an absolute http or https URL",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, InitiatePayment, "Payment Initiation")
+		})
+	}
+}
+
+func Test_InitiatePayment_NetbankingAndWallet(t *testing.T) {
+	initiatePaymentPath := fmt.Sprintf(
+		"/%s%s/create/json",
+		constants.VERSION_V1,
+		constants.PAYMENT_URL,
+	)
+	authenticateURL := "https://api.razorpay.com/v1/payments/" +
+		"pay_MT48CvBhIC98MQ/authenticate"
+
+	redirectResp := map[string]interface{}{
+		"razorpay_payment_id": "pay_MT48CvBhIC98MQ",
+		"next": []interface{}{
+			map[string]interface{}{
+				"action": "redirect",
+				"url":    authenticateURL,
+			},
+		},
+	}
+	expectedResult := map[string]interface{}{
+		"razorpay_payment_id": "pay_MT48CvBhIC98MQ",
+		"payment_details":     redirectResp,
+		"status":              "payment_initiated",
+		"message": "Payment initiated. Redirect authentication is " +
+			"available. Use the redirect URL provided in available_actions.",
+		"next_actions": []interface{}{
+			expectedURLAction("redirect", redirectStep, authenticateURL),
+		},
+		"available_actions": redirectResp["next"],
+	}
+	redirectClient := func() (*http.Client, *httptest.Server) {
+		return mock.NewHTTPClient(
+			mock.Endpoint{
+				Path:     initiatePaymentPath,
+				Method:   "POST",
+				Response: redirectResp,
+			},
+		)
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "netbanking payment",
+			Request: map[string]interface{}{
+				"amount":   10000,
+				"order_id": "order_129837127313912",
+				"method":   "netbanking",
+				"bank":     "HDFC",
+			},
+			MockHttpClient: redirectClient,
+			ExpectError:    false,
+			ExpectedResult: expectedResult,
+		},
+		{
+			Name: "wallet payment",
+			Request: map[string]interface{}{
+				"amount":   10000,
+				"order_id": "order_129837127313912",
+				"method":   "wallet",
+				"wallet":   "mobikwik",
 			},
+			MockHttpClient: redirectClient,
+			ExpectError:    false,
+			ExpectedResult: expectedResult,
+		},
+		{
+			Name: "netbanking payment without bank",
+			Request: map[string]interface{}{
+				"amount":   10000,
+				"order_id": "order_129837127313912",
+				"method":   "netbanking",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "bank is required for method netbanking",
 		},
 	}
 
-	for _, tc := range testCases {
+	for _, tc := range tests {
 		t.Run(tc.Name, func(t *testing.T) {
-			runToolTest(t, tc, InitiatePayment, "Payment")
+			runToolTest(t, tc, InitiatePayment, "Payment Initiation")
 		})
 	}
 }
 
-// Test helper functions for better coverage
-func Test_extractPaymentID(t *testing.T) {
+func Test_validatePaymentMethod(t *testing.T) {
 	tests := []struct {
-		name     string
-		payment  map[string]interface{}
-		expected string
+		name        string
+		params      map[string]interface{}
+		expectedErr string
 	}{
 		{
-			name: "valid payment ID",
-			payment: map[string]interface{}{
-				"razorpay_payment_id": "pay_MT48CvBhIC98MQ",
-				"status":              "created",
+			name:   "no method",
+			params: map[string]interface{}{"token": "token_123"},
+		},
+		{
+			name: "netbanking with bank",
+			params: map[string]interface{}{
+				"method": "netbanking",
+				"bank":   "SBIN",
 			},
-			expected: "pay_MT48CvBhIC98MQ",
 		},
 		{
-			name: "missing payment ID",
-			payment: map[string]interface{}{
-				"status": "created",
+			name: "wallet with wallet",
+			params: map[string]interface{}{
+				"method": "wallet",
+				"wallet": "amazonpay",
 			},
-			expected: "",
 		},
 		{
-			name: "nil payment ID",
-			payment: map[string]interface{}{
-				"razorpay_payment_id": nil,
-				"status":              "created",
+			name: "upi with vpa",
+			params: map[string]interface{}{
+				"method": "upi",
+				"vpa":    "test@upi",
 			},
-			expected: "",
 		},
 		{
-			name:     "empty payment map",
-			payment:  map[string]interface{}{},
-			expected: "",
+			name:   "unsupported method",
+			params: map[string]interface{}{"method": "cod"},
+			expectedErr: "invalid method: cod, must be one of card, " +
+				"netbanking, wallet or upi",
+		},
+		{
+			name:        "card without token",
+			params:      map[string]interface{}{"method": "card"},
+			expectedErr: "token of a saved card is required for method card",
+		},
+		{
+			name:        "upi without vpa",
+			params:      map[string]interface{}{"method": "upi"},
+			expectedErr: "vpa or upi_intent is required for method upi",
+		},
+		{
+			name:        "wallet without wallet",
+			params:      map[string]interface{}{"method": "wallet"},
+			expectedErr: "wallet is required for method wallet",
+		},
+		{
+			name: "bank with another method",
+			params: map[string]interface{}{
+				"method": "wallet",
+				"wallet": "mobikwik",
+				"bank":   "HDFC",
+			},
+			expectedErr: "bank is only supported for method netbanking",
+		},
+		{
+			name: "wallet without method",
+			params: map[string]interface{}{
+				"wallet": "mobikwik",
+			},
+			expectedErr: "wallet is only supported for method wallet",
+		},
+		{
+			name: "vpa with another method",
+			params: map[string]interface{}{
+				"method": "netbanking",
+				"bank":   "HDFC",
+				"vpa":    "test@upi",
+			},
+			expectedErr: "method netbanking cannot be combined with vpa " +
+				"or upi_intent",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := extractPaymentID(tt.payment)
-			if result != tt.expected {
-				t.Errorf("extractPaymentID() = %v, want %v", result, tt.expected)
+			err := validatePaymentMethod(tt.params)
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
 			}
+			assert.EqualError(t, err, tt.expectedErr)
 		})
 	}
 }
 
+func Test_buildPaymentData_redirect(t *testing.T) {
+	params := map[string]interface{}{
+		"amount":   10000,
+		"order_id": "order_123",
+		"redirect": true,
+	}
+
+	result := *buildPaymentData(params, "INR", "",
+		defaultGeneratedEmailDomain)
+	assert.Equal(t, map[string]interface{}{
+		"authentication_channel": "browser",
+	}, result["authentication"])
+
+	params["redirect"] = false
+	result = *buildPaymentData(params, "INR", "",
+		defaultGeneratedEmailDomain)
+	assert.NotContains(t, result, "authentication")
+}
+
+func Test_buildPaymentData_netbankingAndWallet(t *testing.T) {
+	result := *buildPaymentData(map[string]interface{}{
+		"amount":   10000,
+		"order_id": "order_123",
+		"method":   "netbanking",
+		"bank":     "HDFC",
+	}, "INR", "", defaultGeneratedEmailDomain)
+	assert.Equal(t, "netbanking", result["method"])
+	assert.Equal(t, "HDFC", result["bank"])
+	assert.NotContains(t, result, "wallet")
+
+	result = *buildPaymentData(map[string]interface{}{
+		"amount":   10000,
+		"order_id": "order_123",
+		"method":   "wallet",
+		"wallet":   "mobikwik",
+	}, "INR", "", defaultGeneratedEmailDomain)
+	assert.Equal(t, "wallet", result["method"])
+	assert.Equal(t, "mobikwik", result["wallet"])
+	assert.NotContains(t, result, "bank")
+}
+
 func Test_buildInitiatePaymentResponse(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -1945,7 +3070,7 @@ func Test_buildInitiatePaymentResponse(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			response, otpURL := buildInitiatePaymentResponse(
-				tt.payment, tt.paymentID, tt.actions)
+				context.Background(), tt.payment, tt.paymentID, tt.actions)
 
 			// Check basic response structure
 			if response["razorpay_payment_id"] != tt.paymentID {
@@ -2005,7 +3130,7 @@ func Test_addNextStepInstructions(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			response := make(map[string]interface{})
-			addNextStepInstructions(response, tt.paymentID)
+			addNextStepInstructions(context.Background(), response, tt.paymentID)
 
 			if tt.expected {
 				if _, exists := response["next_step"]; !exists {
@@ -2038,6 +3163,84 @@ func Test_addNextStepInstructions(t *testing.T) {
 	}
 }
 
+// newFailingOtpClient returns a client whose API has no endpoints, so
+// that the OTP requests it sends fail
+func newFailingOtpClient(t *testing.T) *rzpsdk.Client {
+	client, server := newMockRzpClient(
+		func() (*http.Client, *httptest.Server) {
+			return mock.NewHTTPClient()
+		})
+	t.Cleanup(server.Close)
+	return client
+}
+
+func Test_otpURLRequests(t *testing.T) {
+	// otpClient returns a client acting for a sub-merchant, and the
+	// requests its API receives
+	otpClient := func(t *testing.T) (*rzpsdk.Client, *[]*http.Request) {
+		var requests []*http.Request
+		client, server := newMockRzpClient(
+			func() (*http.Client, *httptest.Server) {
+				server := httptest.NewServer(http.HandlerFunc(
+					func(w http.ResponseWriter, r *http.Request) {
+						requests = append(requests, r)
+						_, _ = w.Write([]byte(`{"razorpay_payment_id":` +
+							`"pay_MT48CvBhIC98MQ"}`))
+					}))
+				return server.Client(), server
+			})
+		t.Cleanup(server.Close)
+		client.Request.AddHeaders(map[string]string{
+			MerchantAccountHeader: "acc_Ef7ArAsdU5t0XL",
+		})
+		return client, &requests
+	}
+
+	t.Run("OTPs are generated through the client", func(t *testing.T) {
+		client, requests := otpClient(t)
+
+		err := sendOtp(client, "https://api.razorpay.com/v1/payments/"+
+			"pay_MT48CvBhIC98MQ/otp_generate")
+		require.NoError(t, err)
+
+		require.Len(t, *requests, 1)
+		r := (*requests)[0]
+		assert.Equal(t, "/v1/payments/pay_MT48CvBhIC98MQ/otp_generate",
+			r.URL.Path)
+		assert.Equal(t, "acc_Ef7ArAsdU5t0XL", r.Header.Get(
+			MerchantAccountHeader))
+		key, _, _ := r.BasicAuth()
+		assert.Equal(t, "sample_key", key)
+	})
+
+	t.Run("OTPs are submitted through the client", func(t *testing.T) {
+		client, requests := otpClient(t)
+
+		response, err := submitOtpToURL(client, "https://api.razorpay.com/"+
+			"v1/payments/pay_MT48CvBhIC98MQ/otp_submit/ac2d415a8be7595d"+
+			"?key_id=rzp_test_key", "pay_MT48CvBhIC98MQ",
+			map[string]interface{}{"otp": "123456"})
+		require.NoError(t, err)
+		assert.Equal(t, "pay_MT48CvBhIC98MQ",
+			response["razorpay_payment_id"])
+
+		require.Len(t, *requests, 1)
+		r := (*requests)[0]
+		assert.Equal(t, "/v1/payments/pay_MT48CvBhIC98MQ/otp_submit/"+
+			"ac2d415a8be7595d", r.URL.Path)
+		assert.Equal(t, "rzp_test_key", r.URL.Query().Get("key_id"))
+		assert.Equal(t, "acc_Ef7ArAsdU5t0XL", r.Header.Get(
+			MerchantAccountHeader))
+	})
+
+	t.Run("OTP generation fails with the API error", func(t *testing.T) {
+		err := sendOtp(newFailingOtpClient(t), "https://api.razorpay.com/"+
+			"v1/payments/pay_MT48CvBhIC98MQ/otp_generate")
+		assert.EqualError(t, err, "OTP generation failed: No mock for POST "+
+			"/v1/payments/pay_MT48CvBhIC98MQ/otp_generate")
+	})
+}
+
 func Test_sendOtp_validation(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -2073,7 +3276,7 @@ func Test_sendOtp_validation(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := sendOtp(tt.otpURL)
+			err := sendOtp(newFailingOtpClient(t), tt.otpURL)
 			if err == nil {
 				t.Error("Expected error but got nil")
 				return
@@ -2324,6 +3527,16 @@ func Test_ResendOtp(t *testing.T) {
 				"next_tool_params": map[string]interface{}{
 					"payment_id": "pay_MT48CvBhIC98MQ",
 					"otp_string": "{OTP_CODE_FROM_USER}",
+					"otp_submit_url": "https://api.razorpay.com/v1/payments/" +
+						"pay_MT48CvBhIC98MQ/otp/submit",
+				},
+				"next_actions": []interface{}{
+					expectedSubmitOtpURLAction("pay_MT48CvBhIC98MQ",
+						"https://api.razorpay.com/v1/payments/"+
+							"pay_MT48CvBhIC98MQ/otp/submit"),
+					expectedURLAction("otp_submit", otpSubmitStep,
+						"https://api.razorpay.com/v1/payments/"+
+							"pay_MT48CvBhIC98MQ/otp/submit"),
 				},
 				"otp_submit_url": "https://api.razorpay.com/v1/payments/" +
 					"pay_MT48CvBhIC98MQ/otp/submit",
@@ -2386,6 +3599,9 @@ func Test_ResendOtp(t *testing.T) {
 					"payment_id": "pay_MT48CvBhIC98MQ",
 					"otp_string": "{OTP_CODE_FROM_USER}",
 				},
+				"next_actions": []interface{}{
+					expectedSubmitOtpAction("pay_MT48CvBhIC98MQ"),
+				},
 				"response_data": map[string]interface{}{
 					"razorpay_payment_id": "pay_MT48CvBhIC98MQ",
 					"status":              "created",
@@ -2401,6 +3617,177 @@ func Test_ResendOtp(t *testing.T) {
 	}
 }
 
+func Test_GenerateOtp(t *testing.T) {
+	paymentPath := fmt.Sprintf("/%s%s/pay_MT48CvBhIC98MQ",
+		constants.VERSION_V1, constants.PAYMENT_URL)
+	generatePath := paymentPath + "/otp_generate"
+	otpGenerateURL := "https://api.razorpay.com" + generatePath
+	otpSubmitURL := "https://api.razorpay.com/v1/payments/" +
+		"pay_MT48CvBhIC98MQ/otp/submit"
+
+	generateResp := map[string]interface{}{
+		"razorpay_payment_id": "pay_MT48CvBhIC98MQ",
+		"next": []interface{}{
+			map[string]interface{}{
+				"action": "otp_submit",
+				"url":    otpSubmitURL,
+			},
+		},
+	}
+	expectedResult := map[string]interface{}{
+		"payment_id": "pay_MT48CvBhIC98MQ",
+		"status":     "success",
+		"message": "OTP sent successfully. Please enter the OTP received " +
+			"on your mobile number to complete the payment.",
+		"next_step": "Use 'submit_otp' tool with the OTP code received " +
+			"from user to complete payment authentication.",
+		"next_tool": "submit_otp",
+		"next_tool_params": map[string]interface{}{
+			"payment_id":     "pay_MT48CvBhIC98MQ",
+			"otp_string":     "{OTP_CODE_FROM_USER}",
+			"otp_submit_url": otpSubmitURL,
+		},
+		"next_actions": []interface{}{
+			expectedSubmitOtpURLAction("pay_MT48CvBhIC98MQ", otpSubmitURL),
+			expectedURLAction("otp_submit", otpSubmitStep, otpSubmitURL),
+		},
+		"otp_submit_url": otpSubmitURL,
+		"response_data":  generateResp,
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "generates OTP with the given url",
+			Request: map[string]interface{}{
+				"payment_id":       "pay_MT48CvBhIC98MQ",
+				"otp_generate_url": otpGenerateURL,
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     generatePath,
+						Method:   "POST",
+						Response: generateResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: expectedResult,
+		},
+		{
+			Name: "discovers the url from the payment",
+			Request: map[string]interface{}{
+				"payment_id": "pay_MT48CvBhIC98MQ",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:   paymentPath,
+						Method: "GET",
+						Response: map[string]interface{}{
+							"id": "pay_MT48CvBhIC98MQ",
+							"next": []interface{}{
+								map[string]interface{}{
+									"action": "otp_generate",
+									"url":    otpGenerateURL,
+								},
+							},
+						},
+					},
+					mock.Endpoint{
+						Path:     generatePath,
+						Method:   "POST",
+						Response: generateResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: expectedResult,
+		},
+		{
+			Name: "payment without otp_generate action",
+			Request: map[string]interface{}{
+				"payment_id": "pay_MT48CvBhIC98MQ",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:   paymentPath,
+						Method: "GET",
+						Response: map[string]interface{}{
+							"id":     "pay_MT48CvBhIC98MQ",
+							"status": "captured",
+						},
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "payment pay_MT48CvBhIC98MQ has no otp_generate " +
+				"action, pass the otp_generate_url returned by " +
+				"initiate_payment",
+		},
+		{
+			Name: "rejects urls outside the razorpay domain",
+			Request: map[string]interface{}{
+				"payment_id": "pay_MT48CvBhIC98MQ",
+				"otp_generate_url": "https://api.razorpay.com.evil.test" +
+					generatePath,
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "OTP URL must be from Razorpay domain",
+		},
+		{
+			Name: "rejects urls of other endpoints",
+			Request: map[string]interface{}{
+				"payment_id": "pay_MT48CvBhIC98MQ",
+				"otp_generate_url": "https://api.razorpay.com/v1/payments/" +
+					"pay_MT48CvBhIC98MQ/refund",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "OTP URL is not the otp_generate URL of " +
+				"payment pay_MT48CvBhIC98MQ",
+		},
+		{
+			Name: "OTP generation failure",
+			Request: map[string]interface{}{
+				"payment_id":       "pay_MT48CvBhIC98MQ",
+				"otp_generate_url": otpGenerateURL,
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:   generatePath,
+						Method: "POST",
+						Response: map[string]interface{}{
+							"error": map[string]interface{}{
+								"code":        "BAD_REQUEST_ERROR",
+								"description": "Payment not found",
+							},
+						},
+					},
+				)
+			},
+			ExpectError:    true,
+			ExpectedErrMsg: "OTP generation failed: Payment not found",
+		},
+		{
+			Name:           "missing payment_id parameter",
+			Request:        map[string]interface{}{},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: payment_id",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, GenerateOtp, "OTP Generate")
+		})
+	}
+}
+
 // Test_sendOtp_additionalCases tests additional cases for sendOtp function
 func Test_sendOtp_additionalCases(t *testing.T) {
 	tests := []struct {
@@ -2424,7 +3811,7 @@ func Test_sendOtp_additionalCases(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := sendOtp(tt.otpURL)
+			err := sendOtp(newFailingOtpClient(t), tt.otpURL)
 			if err == nil {
 				t.Error("Expected error but got nil")
 				return
@@ -2526,11 +3913,27 @@ func Test_buildPaymentData_edgeCases(t *testing.T) {
 				"save":        true,
 			},
 		},
+		{
+			name: "payment data with callback url in redirect mode",
+			params: map[string]interface{}{
+				"amount":       10000,
+				"order_id":     "order_123",
+				"token":        "token_123",
+				"callback_url": "https://example.com/callback",
+				"redirect":     true,
+			},
+			currency:   "INR",
+			customerID: "",
+			shouldContain: map[string]interface{}{
+				"callback_url": "https://example.com/callback",
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := buildPaymentData(tt.params, tt.currency, tt.customerID)
+			result := buildPaymentData(tt.params, tt.currency, tt.customerID,
+				defaultGeneratedEmailDomain)
 
 			if result == nil {
 				t.Error("Expected result but got nil")
@@ -3059,7 +4462,8 @@ func Test_addContactAndEmailToPaymentData_scenarios(t *testing.T) {
 				paymentData[k] = v
 			}
 
-			addContactAndEmailToPaymentData(paymentData, tt.params)
+			addContactAndEmailToPaymentData(paymentData, tt.params,
+				defaultGeneratedEmailDomain)
 
 			for key, expectedValue := range tt.expectedResult {
 				actualValue, exists := paymentData[key]
@@ -3084,6 +4488,33 @@ func Test_addContactAndEmailToPaymentData_scenarios(t *testing.T) {
 	}
 }
 
+func Test_addContactAndEmailToPaymentData_emailDomain(t *testing.T) {
+	params := map[string]interface{}{"contact": "9876543210"}
+
+	t.Run("generates emails in the domain", func(t *testing.T) {
+		paymentData := map[string]interface{}{}
+		addContactAndEmailToPaymentData(paymentData, params, "example.com")
+		assert.Equal(t, "9876543210@example.com", paymentData["email"])
+	})
+
+	t.Run("empty domain generates no email", func(t *testing.T) {
+		paymentData := map[string]interface{}{}
+		addContactAndEmailToPaymentData(paymentData, params, "")
+		assert.Equal(t, "9876543210", paymentData["contact"])
+		assert.NotContains(t, paymentData, "email")
+	})
+}
+
+func Test_generatedEmailDomain(t *testing.T) {
+	assert.Equal(t, defaultGeneratedEmailDomain,
+		generatedEmailDomain(context.Background()))
+	assert.Equal(t, "example.com", generatedEmailDomain(
+		contextkey.WithGeneratedEmailDomain(context.Background(),
+			"example.com")))
+	assert.Empty(t, generatedEmailDomain(
+		contextkey.WithGeneratedEmailDomain(context.Background(), "")))
+}
+
 // Test_processPaymentResult_edgeCases
 // tests edge cases for processPaymentResult function
 func Test_processPaymentResult_edgeCases(t *testing.T) {
@@ -3130,7 +4561,8 @@ func Test_processPaymentResult_edgeCases(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := processPaymentResult(tt.payment)
+			result, err := processPaymentResult(context.Background(),
+				newFailingOtpClient(t), tt.payment)
 
 			if tt.expectedError != "" {
 				if err == nil {
@@ -3166,7 +4598,7 @@ func Test_processPaymentResult_edgeCases(t *testing.T) {
 // Test for sendOtp function - comprehensive coverage
 func TestSendOtp(t *testing.T) {
 	t.Run("empty OTP URL", func(t *testing.T) {
-		err := sendOtp("")
+		err := sendOtp(newFailingOtpClient(t), "")
 		if err == nil {
 			t.Error("Expected error for empty OTP URL")
 		}
@@ -3176,7 +4608,7 @@ func TestSendOtp(t *testing.T) {
 	})
 
 	t.Run("invalid URL format", func(t *testing.T) {
-		err := sendOtp("invalid-url")
+		err := sendOtp(newFailingOtpClient(t), "invalid-url")
 		if err == nil {
 			t.Error("Expected error for invalid URL")
 		}
@@ -3187,7 +4619,8 @@ func TestSendOtp(t *testing.T) {
 	})
 
 	t.Run("non-HTTPS URL", func(t *testing.T) {
-		err := sendOtp("http://api.razorpay.com/v1/payments/otp")
+		err := sendOtp(newFailingOtpClient(t),
+			"http://api.razorpay.com/v1/payments/otp")
 		if err == nil {
 			t.Error("Expected error for non-HTTPS URL")
 		}
@@ -3197,7 +4630,8 @@ func TestSendOtp(t *testing.T) {
 	})
 
 	t.Run("non-Razorpay domain", func(t *testing.T) {
-		err := sendOtp("https://example.com/otp")
+		err := sendOtp(newFailingOtpClient(t),
+			"https://example.com/otp")
 		if err == nil {
 			t.Error("Expected error for non-Razorpay domain")
 		}
@@ -3210,7 +4644,7 @@ func TestSendOtp(t *testing.T) {
 	t.Run("successful OTP request", func(t *testing.T) {
 		// Since we can't actually call external APIs in tests, we'll test the
 		// validation logic by testing with a URL that would fail at HTTP call stage
-		err := sendOtp(
+		err := sendOtp(newFailingOtpClient(t),
 			"https://api.razorpay.com/v1/payments/invalid-endpoint-for-test")
 		if err == nil {
 			t.Error("Expected error for invalid endpoint")
@@ -3224,7 +4658,8 @@ func TestSendOtp(t *testing.T) {
 	t.Run("HTTP request creation failure", func(t *testing.T) {
 		// Test with invalid characters that would cause http.NewRequest to fail
 		// This is difficult to trigger in practice, so we'll test URL validation
-		err := sendOtp("https://api.razorpay.com/v1/payments\x00/otp")
+		err := sendOtp(newFailingOtpClient(t),
+			"https://api.razorpay.com/v1/payments\x00/otp")
 		if err == nil {
 			t.Error("Expected error for invalid URL characters")
 		}
@@ -3346,7 +4781,7 @@ func TestAddNextStepInstructions(t *testing.T) {
 		result := make(map[string]interface{})
 		paymentID := "pay_test123"
 
-		addNextStepInstructions(result, paymentID)
+		addNextStepInstructions(context.Background(), result, paymentID)
 
 		nextStep, exists := result["next_step"]
 		if !exists {
@@ -3392,7 +4827,7 @@ func TestAddNextStepInstructions(t *testing.T) {
 	t.Run("empty payment ID", func(t *testing.T) {
 		result := make(map[string]interface{})
 
-		addNextStepInstructions(result, "")
+		addNextStepInstructions(context.Background(), result, "")
 
 		// Should not add anything when payment ID is empty
 		if len(result) != 0 {
@@ -3506,7 +4941,8 @@ func TestBuildPaymentData(t *testing.T) {
 		currency := "INR"
 		customerId := "cust_test123"
 
-		result := buildPaymentData(params, currency, customerId)
+		result := buildPaymentData(params, currency, customerId,
+			defaultGeneratedEmailDomain)
 
 		if (*result)["amount"] != 1000 {
 			t.Errorf("Expected amount to be 1000, got %v", (*result)["amount"])
@@ -3528,7 +4964,8 @@ func TestBuildPaymentData(t *testing.T) {
 		currency := "INR"
 		customerId := ""
 
-		result := buildPaymentData(params, currency, customerId)
+		result := buildPaymentData(params, currency, customerId,
+			defaultGeneratedEmailDomain)
 
 		if (*result)["amount"] != 1000 {
 			t.Errorf("Expected amount to be 1000, got %v", (*result)["amount"])
@@ -3558,7 +4995,8 @@ func TestProcessPaymentResult(t *testing.T) {
 			},
 		}
 
-		result, err := processPaymentResult(paymentResult)
+		result, err := processPaymentResult(context.Background(),
+			newFailingOtpClient(t), paymentResult)
 
 		if err != nil {
 			t.Errorf("Expected no error, got %v", err)
@@ -3583,7 +5021,8 @@ func TestProcessPaymentResult(t *testing.T) {
 			},
 		}
 
-		result, err := processPaymentResult(paymentResult)
+		result, err := processPaymentResult(context.Background(),
+			newFailingOtpClient(t), paymentResult)
 
 		// The function should handle this gracefully
 		if err != nil && result == nil {
@@ -3926,7 +5365,7 @@ func TestPayments100PercentCoverage_InitiatePayment(t *testing.T) {
 		// Replace domain to pass validation
 		testURL := strings.Replace(
 			server.URL, server.URL[8:], "api.razorpay.com/v1/payments/otp", 1)
-		err := sendOtp(testURL)
+		err := sendOtp(newFailingOtpClient(t), testURL)
 		if err == nil {
 			t.Error("Expected error for HTTP error status")
 		}
@@ -3938,7 +5377,8 @@ func TestPayments100PercentCoverage_InitiatePayment(t *testing.T) {
 	// More aggressive tests - hitting every error path!
 	t.Run("sendOtp - request creation error", func(t *testing.T) {
 		// Test with malformed URL that passes parsing but fails request creation
-		err := sendOtp("https://api.razorpay.com:99999/invalid")
+		err := sendOtp(newFailingOtpClient(t),
+			"https://api.razorpay.com:99999/invalid")
 		if err == nil {
 			t.Error("Expected error for malformed URL")
 		}
@@ -3952,7 +5392,7 @@ func TestPayments100PercentCoverage_InitiatePayment(t *testing.T) {
 	t.Run("sendOtp - extreme URL", func(t *testing.T) {
 		longPath := strings.Repeat("a", 10000)
 		testURL := "https://api.razorpay.com/v1/payments/" + longPath + "/otp"
-		err := sendOtp(testURL)
+		err := sendOtp(newFailingOtpClient(t), testURL)
 		if err == nil {
 			t.Error("Expected error for extreme URL")
 		}
@@ -3991,7 +5431,8 @@ func TestPayments100PercentCoverage_ContextErrors(t *testing.T) {
 	// Test sendOtp with actual HTTP client failure
 	t.Run("sendOtp - HTTP client failure", func(t *testing.T) {
 		// Test with a URL that will fail at the HTTP client level
-		err := sendOtp("https://api.razorpay.com:99999/invalid/path/that/will/fail")
+		err := sendOtp(newFailingOtpClient(t),
+			"https://api.razorpay.com:99999/invalid/path/that/will/fail")
 		if err == nil {
 			t.Error("Expected error for HTTP client failure")
 		}
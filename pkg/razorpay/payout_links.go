@@ -0,0 +1,286 @@
+package razorpay
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+	"github.com/razorpay/razorpay-go/constants"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+)
+
+// payoutLinksPath is the path of the Payout Links API, which the SDK does
+// not cover
+var payoutLinksPath = fmt.Sprintf("/%s/payout-links", constants.VERSION_V1)
+
+// CreatePayoutLink returns a tool that creates a payout link, which lets
+// the recipient choose the account the payout is made to
+func CreatePayoutLink(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"account_number",
+			mcpgo.Description("The RazorpayX account number to pay out from. "+
+				"For example, 7878780080316316"),
+			mcpgo.Required(),
+		),
+		mcpgo.WithString(
+			"contact_id",
+			mcpgo.Description("ID of an existing contact to send the link "+
+				"to, starting with 'cont_'. Set either contact_id or "+
+				"contact_name"),
+		),
+		mcpgo.WithString(
+			"contact_name",
+			mcpgo.Description("Name of a new contact to send the link to"),
+		),
+		mcpgo.WithString(
+			"contact_email",
+			mcpgo.Description("Email address of the new contact"),
+		),
+		mcpgo.WithString(
+			"contact_phone",
+			mcpgo.Description("Phone number of the new contact"),
+		),
+		mcpgo.WithString(
+			"contact_type",
+			mcpgo.Description("Type of the new contact"),
+			mcpgo.Enum("vendor", "customer", "employee", "self"),
+		),
+		mcpgo.WithNumber(
+			"amount",
+			mcpgo.Description("Amount to pay out in paise"),
+			mcpgo.Required(),
+			mcpgo.Min(100),
+		),
+		mcpgo.WithString(
+			"currency",
+			mcpgo.Description("Currency of the payout (default: INR)"),
+		),
+		mcpgo.WithString(
+			"purpose",
+			mcpgo.Description("Purpose of the payout, one of "+
+				strings.Join(payoutPurposes, ", ")+
+				", or a custom purpose created on the dashboard"),
+			mcpgo.Required(),
+		),
+		mcpgo.WithString(
+			"description",
+			mcpgo.Description("Description shown to the recipient"),
+			mcpgo.Required(),
+		),
+		mcpgo.WithString(
+			"receipt",
+			mcpgo.Description("Your reference for the payout link "+
+				"(max 40 chars)"),
+			mcpgo.Max(40),
+		),
+		mcpgo.WithBoolean(
+			"send_sms",
+			mcpgo.Description("Whether Razorpay sends the link to the "+
+				"contact by SMS"),
+		),
+		mcpgo.WithBoolean(
+			"send_email",
+			mcpgo.Description("Whether Razorpay sends the link to the "+
+				"contact by email"),
+		),
+		mcpgo.WithNumber(
+			"expire_by",
+			mcpgo.Description("Unix timestamp (in seconds) after which the "+
+				"link can no longer be used"),
+		),
+		mcpgo.WithObject(
+			"notes",
+			mcpgo.Description("Key-value pairs for additional information "+
+				"(max 15 pairs, 256 chars each)"),
+			mcpgo.MaxProperties(15),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		linkData := map[string]interface{}{"currency": "INR"}
+		contact := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(linkData, "account_number").
+			ValidateAndAddOptionalStringToPath(contact, "contact_id", "id").
+			ValidateAndAddOptionalStringToPath(contact, "contact_name",
+				"name").
+			ValidateAndAddOptionalStringToPath(contact, "contact_email",
+				"email").
+			ValidateAndAddOptionalStringToPath(contact, "contact_phone",
+				"contact").
+			ValidateAndAddOptionalStringToPath(contact, "contact_type",
+				"type").
+			ValidateAndAddRequiredInt(linkData, "amount").
+			ValidateAndAddOptionalString(linkData, "currency").
+			ValidateAndAddRequiredString(linkData, "purpose").
+			ValidateAndAddRequiredString(linkData, "description").
+			ValidateAndAddOptionalString(linkData, "receipt").
+			ValidateAndAddOptionalBool(linkData, "send_sms").
+			ValidateAndAddOptionalBool(linkData, "send_email").
+			ValidateAndAddOptionalInt(linkData, "expire_by").
+			ValidateAndAddOptionalMap(linkData, "notes")
+
+		_, hasID := contact["id"]
+		_, hasName := contact["name"]
+		if hasID == hasName {
+			validator.addError(fmt.Errorf(
+				"exactly one of contact_id or contact_name is required"))
+		}
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		linkData["contact"] = contact
+
+		link, err := client.Request.Post(payoutLinksPath, linkData, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("creating payout link failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(link)
+	}
+
+	return mcpgo.NewTool(
+		"create_payout_link",
+		"Create a payout link to send money to a contact without collecting "+
+			"their bank details first. The contact opens the link and "+
+			"enters the bank account or UPI ID to be paid to",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema).WithAmounts("amount")
+}
+
+// FetchPayoutLink returns a tool that fetches a payout link by its ID
+func FetchPayoutLink(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"payout_link_id",
+			mcpgo.Description("ID of the payout link, starting with "+
+				"'poutlk_'"),
+			mcpgo.Required(),
+			mcpgo.Pattern(mcpgo.IDPattern(payoutLinkIDPrefix)),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		params := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(params, "payout_link_id")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		linkID := params["payout_link_id"].(string)
+		if result := validateID("payout_link_id", linkID,
+			payoutLinkIDPrefix); result != nil {
+			return result, nil
+		}
+
+		link, err := client.Request.Get(payoutLinksPath+"/"+linkID, nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching payout link failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(link)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_payout_link",
+		"Fetch a payout link, including its status and, once the contact "+
+			"has used it, the payouts made",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema).
+		WithFieldSelection()
+}
+
+// CancelPayoutLink returns a tool that cancels a payout link the contact
+// has not used yet
+func CancelPayoutLink(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"payout_link_id",
+			mcpgo.Description("ID of the payout link to cancel, starting "+
+				"with 'poutlk_'"),
+			mcpgo.Required(),
+			mcpgo.Pattern(mcpgo.IDPattern(payoutLinkIDPrefix)),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		params := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(params, "payout_link_id")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		linkID := params["payout_link_id"].(string)
+		if result := validateID("payout_link_id", linkID,
+			payoutLinkIDPrefix); result != nil {
+			return result, nil
+		}
+
+		link, err := client.Request.Post(
+			payoutLinksPath+"/"+linkID+"/cancel", nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("cancelling payout link failed: %s",
+					err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(link)
+	}
+
+	return mcpgo.NewTool(
+		"cancel_payout_link",
+		"Cancel a payout link that is issued but not yet used by the contact",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema)
+}
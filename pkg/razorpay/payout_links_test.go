@@ -0,0 +1,224 @@
+package razorpay
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay/mock"
+)
+
+var payoutLinkResp = map[string]interface{}{
+	"id":          "poutlk_00000000000001",
+	"entity":      "payout_link",
+	"contact_id":  "cont_00000000000001",
+	"amount":      float64(1000),
+	"currency":    "INR",
+	"purpose":     "refund",
+	"description": "Refund for order 42",
+	"status":      "issued",
+}
+
+func Test_CreatePayoutLink(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "payout link for an existing contact",
+			Request: map[string]interface{}{
+				"account_number": "7878780080316316",
+				"contact_id":     "cont_00000000000001",
+				"amount":         float64(1000),
+				"purpose":        "refund",
+				"description":    "Refund for order 42",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     payoutLinksPath,
+						Method:   "POST",
+						Response: payoutLinkResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: payoutLinkResp,
+		},
+		{
+			Name: "neither contact_id nor contact_name",
+			Request: map[string]interface{}{
+				"account_number": "7878780080316316",
+				"amount":         float64(1000),
+				"purpose":        "refund",
+				"description":    "Refund for order 42",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "exactly one of contact_id or contact_name is " +
+				"required",
+		},
+		{
+			Name: "missing description",
+			Request: map[string]interface{}{
+				"account_number": "7878780080316316",
+				"contact_id":     "cont_00000000000001",
+				"amount":         float64(1000),
+				"purpose":        "refund",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: description",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, CreatePayoutLink, "Payout link")
+		})
+	}
+
+	t.Run("new contact is nested in the request", func(t *testing.T) {
+		var body map[string]interface{}
+
+		mockClient := func() (*http.Client, *httptest.Server) {
+			server := httptest.NewServer(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					_ = json.NewDecoder(r.Body).Decode(&body)
+					_ = json.NewEncoder(w).Encode(payoutLinkResp)
+				}))
+			return server.Client(), server
+		}
+
+		client, server := newMockRzpClient(mockClient)
+		defer server.Close()
+
+		tool := CreatePayoutLink(CreateTestObservability(), client)
+		result, err := tool.GetHandler()(context.Background(),
+			createMCPRequest(map[string]interface{}{
+				"account_number": "7878780080316316",
+				"contact_name":   "Gaurav Kumar",
+				"contact_email":  "gaurav.kumar@example.com",
+				"contact_type":   "customer",
+				"amount":         float64(1000),
+				"purpose":        "refund",
+				"description":    "Refund for order 42",
+				"send_email":     true,
+			}))
+		require.NoError(t, err)
+		require.False(t, result.IsError, result.Text)
+
+		assert.Equal(t, map[string]interface{}{
+			"account_number": "7878780080316316",
+			"contact": map[string]interface{}{
+				"name":  "Gaurav Kumar",
+				"email": "gaurav.kumar@example.com",
+				"type":  "customer",
+			},
+			"amount":      float64(1000),
+			"currency":    "INR",
+			"purpose":     "refund",
+			"description": "Refund for order 42",
+			"send_email":  true,
+		}, body)
+	})
+}
+
+func Test_FetchPayoutLink(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful fetch",
+			Request: map[string]interface{}{
+				"payout_link_id": "poutlk_00000000000001",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     payoutLinksPath + "/poutlk_00000000000001",
+						Method:   "GET",
+						Response: payoutLinkResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: payoutLinkResp,
+		},
+		{
+			Name: "invalid payout link ID",
+			Request: map[string]interface{}{
+				"payout_link_id": "poutlk_1/cancel",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid payout_link_id: poutlk_1/cancel",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchPayoutLink, "Payout link")
+		})
+	}
+}
+
+func Test_CancelPayoutLink(t *testing.T) {
+	cancelledResp := map[string]interface{}{
+		"id":     "poutlk_00000000000001",
+		"entity": "payout_link",
+		"status": "cancelled",
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful cancellation",
+			Request: map[string]interface{}{
+				"payout_link_id": "poutlk_00000000000001",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path: payoutLinksPath +
+							"/poutlk_00000000000001/cancel",
+						Method:   "POST",
+						Response: cancelledResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: cancelledResp,
+		},
+		{
+			Name: "link already processed",
+			Request: map[string]interface{}{
+				"payout_link_id": "poutlk_00000000000001",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path: payoutLinksPath +
+							"/poutlk_00000000000001/cancel",
+						Method: "POST",
+						Response: map[string]interface{}{
+							"error": map[string]interface{}{
+								"code": "BAD_REQUEST_ERROR",
+								"description": "Payout link cannot be " +
+									"cancelled",
+							},
+						},
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "cancelling payout link failed: Payout link " +
+				"cannot be cancelled",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, CancelPayoutLink, "Payout link")
+		})
+	}
+}
@@ -2,14 +2,36 @@ package razorpay
 
 import (
 	"context"
+	"crypto/rand"
 	"fmt"
+	"strings"
 
 	rzpsdk "github.com/razorpay/razorpay-go"
+	"github.com/razorpay/razorpay-go/constants"
 
 	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
 	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
 )
 
+// contactsPath is the path of the RazorpayX Contacts API, which the SDK
+// does not cover
+var contactsPath = fmt.Sprintf("/%s/contacts", constants.VERSION_V1)
+
+// payoutIdempotencyHeader carries the idempotency key RazorpayX requires
+// to create a payout, so that a retried request does not pay out twice
+const payoutIdempotencyHeader = "X-Payout-Idempotency"
+
+// payoutModes are the modes a payout can be made with
+var payoutModes = []interface{}{
+	"NEFT", "RTGS", "IMPS", "UPI", "card", "amazonpay",
+}
+
+// payoutPurposes are the predefined purposes of a payout. Custom purposes
+// created on the dashboard can be used too.
+var payoutPurposes = []string{
+	"refund", "cashback", "payout", "salary", "utility bill", "vendor bill",
+}
+
 // FetchPayoutByID returns a tool that fetches a payout by its ID
 func FetchPayout(
 	obs *observability.Observability,
@@ -43,6 +65,12 @@ func FetchPayout(
 			return result, err
 		}
 
+		if result := validateID("payout_id",
+			FetchPayoutOptions["payout_id"].(string),
+			payoutIDPrefix); result != nil {
+			return result, nil
+		}
+
 		payout, err := client.Payout.Fetch(
 			FetchPayoutOptions["payout_id"].(string),
 			nil,
@@ -61,7 +89,8 @@ func FetchPayout(
 		"Fetch a payout's details using its ID",
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(entityOutputSchema).
+		WithFieldSelection()
 }
 
 // FetchAllPayouts returns a tool that fetches all payouts
@@ -69,7 +98,7 @@ func FetchAllPayouts(
 	obs *observability.Observability,
 	client *rzpsdk.Client,
 ) mcpgo.Tool {
-	parameters := []mcpgo.ToolParameter{
+	parameters := append([]mcpgo.ToolParameter{
 		mcpgo.WithString(
 			"account_number",
 			mcpgo.Description("The account from which the payouts were done."+
@@ -89,7 +118,25 @@ func FetchAllPayouts(
 				"This can be used for pagination, in combination with count"),
 			mcpgo.Min(0),
 		),
-	}
+		mcpgo.WithNumber(
+			"from",
+			mcpgo.Description("Unix timestamp (in seconds) from when "+
+				"payouts are to be fetched"),
+			mcpgo.Min(0),
+		),
+		mcpgo.WithNumber(
+			"to",
+			mcpgo.Description("Unix timestamp (in seconds) up till when "+
+				"payouts are to be fetched"),
+			mcpgo.Min(0),
+		),
+		mcpgo.WithString(
+			"output_file",
+			mcpgo.Description("Optional relative path of a file to write the "+
+				"fetched payouts to as NDJSON (one payout per line) for "+
+				"reconciliation. When set, only a summary is returned"),
+		),
+	}, collectionParameters()...)
 
 	handler := func(
 		ctx context.Context,
@@ -101,28 +148,345 @@ func FetchAllPayouts(
 		}
 
 		FetchAllPayoutsOptions := make(map[string]interface{})
+		pagination := make(map[string]interface{})
+		exportOptions := make(map[string]interface{})
 
 		validator := NewValidator(&r).
 			ValidateAndAddRequiredString(FetchAllPayoutsOptions, "account_number").
-			ValidateAndAddPagination(FetchAllPayoutsOptions)
+			ValidateAndAddPagination(FetchAllPayoutsOptions).
+			ValidateAndAddCollectionOptions(pagination).
+			ValidateAndAddOptionalInt(FetchAllPayoutsOptions, "from").
+			ValidateAndAddOptionalInt(FetchAllPayoutsOptions, "to").
+			ValidateAndAddOptionalString(exportOptions, "output_file")
 
 		if result, err := validator.HandleErrorsIfAny(); result != nil {
 			return result, err
 		}
 
-		payout, err := client.Payout.All(FetchAllPayoutsOptions, nil)
+		var outputPath string
+		if outputFile, ok := exportOptions["output_file"].(string); ok {
+			outputPath, err = resolveExportPath(outputFile)
+			if err != nil {
+				return mcpgo.NewToolResultError(err.Error()), nil
+			}
+		}
+
+		payout, err := fetchCollection(FetchAllPayoutsOptions, pagination,
+			client.Payout.All)
 		if err != nil {
 			return mcpgo.NewToolResultError(
 				fmt.Sprintf("fetching payouts failed: %s", err.Error())), nil
 		}
 
-		return mcpgo.NewToolResultJSON(payout)
+		if outputPath == "" {
+			return collectionResult(ctx, payout, pagination)
+		}
+
+		items, _ := payout["items"].([]interface{})
+		rows, err := writeNDJSON(outputPath, items)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("exporting payouts failed: %s", err.Error())), nil
+		}
+
+		summary := map[string]interface{}{
+			"account_number": FetchAllPayoutsOptions["account_number"],
+			"output_file":    outputPath,
+			"format":         "ndjson",
+			"rows":           rows,
+		}
+		for _, key := range []string{"from", "to"} {
+			if value, ok := FetchAllPayoutsOptions[key]; ok {
+				summary[key] = value
+			}
+		}
+
+		return mcpgo.NewToolResultJSON(summary)
 	}
 
 	return mcpgo.NewTool(
 		"fetch_all_payouts",
-		"Fetch all payouts for a bank account number",
+		"Fetch all payouts for a bank account number, optionally within a "+
+			"date range. Set output_file to export the payouts as NDJSON "+
+			"for balance reconciliation",
+		parameters,
+		handler,
+	).WithOutputSchema(objectOutputSchema).
+		WithFieldSelection().
+		WithTimeout(exportToolTimeout).
+		// Exports write a file, which a cached result would skip
+		WithoutCache()
+}
+
+// CreateContact returns a tool that creates a RazorpayX contact, the
+// recipient of payouts
+func CreateContact(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"name",
+			mcpgo.Description("Name of the contact"),
+			mcpgo.Required(),
+			mcpgo.Min(3),
+			mcpgo.Max(50),
+		),
+		mcpgo.WithString(
+			"email",
+			mcpgo.Description("Email address of the contact"),
+		),
+		mcpgo.WithString(
+			"contact",
+			mcpgo.Description("Phone number of the contact"),
+		),
+		mcpgo.WithString(
+			"type",
+			mcpgo.Description("Type of the contact"),
+			mcpgo.Enum("vendor", "customer", "employee", "self"),
+		),
+		mcpgo.WithString(
+			"reference_id",
+			mcpgo.Description("Your reference for the contact, such as an "+
+				"employee ID (max 40 chars)"),
+			mcpgo.Max(40),
+		),
+		mcpgo.WithObject(
+			"notes",
+			mcpgo.Description("Key-value pairs for additional information "+
+				"(max 15 pairs, 256 chars each)"),
+			mcpgo.MaxProperties(15),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		contactData := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(contactData, "name").
+			ValidateAndAddOptionalString(contactData, "email").
+			ValidateAndAddOptionalString(contactData, "contact").
+			ValidateAndAddOptionalString(contactData, "type").
+			ValidateAndAddOptionalString(contactData, "reference_id").
+			ValidateAndAddOptionalMap(contactData, "notes")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		contact, err := client.Request.Post(contactsPath, contactData, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("creating contact failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(contact)
+	}
+
+	return mcpgo.NewTool(
+		"create_contact",
+		"Create a RazorpayX contact, such as a vendor or employee, to make "+
+			"payouts to. Add a fund account to the contact with "+
+			"create_fund_account before paying out",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema)
+}
+
+// CreatePayout returns a tool that pays out from a RazorpayX account to a
+// fund account
+func CreatePayout(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"account_number",
+			mcpgo.Description("The RazorpayX account number to pay out from. "+
+				"For example, 7878780080316316"),
+			mcpgo.Required(),
+		),
+		mcpgo.WithString(
+			"fund_account_id",
+			mcpgo.Description("ID of the fund account to pay out to, "+
+				"starting with 'fa_'"),
+			mcpgo.Required(),
+		),
+		mcpgo.WithNumber(
+			"amount",
+			mcpgo.Description("Amount to pay out in paise"),
+			mcpgo.Required(),
+			mcpgo.Min(100),
+		),
+		mcpgo.WithString(
+			"currency",
+			mcpgo.Description("Currency of the payout (default: INR)"),
+		),
+		mcpgo.WithString(
+			"mode",
+			mcpgo.Description("Mode of the payout. UPI is only available for "+
+				"VPA fund accounts"),
+			mcpgo.Required(),
+			mcpgo.Enum(payoutModes...),
+		),
+		mcpgo.WithString(
+			"purpose",
+			mcpgo.Description("Purpose of the payout, one of "+
+				strings.Join(payoutPurposes, ", ")+
+				", or a custom purpose created on the dashboard"),
+			mcpgo.Required(),
+		),
+		mcpgo.WithBoolean(
+			"queue_if_low_balance",
+			mcpgo.Description("Whether the payout is queued instead of "+
+				"failing when the balance is too low (default: false)"),
+		),
+		mcpgo.WithString(
+			"reference_id",
+			mcpgo.Description("Your reference for the payout (max 40 chars)"),
+			mcpgo.Max(40),
+		),
+		mcpgo.WithString(
+			"narration",
+			mcpgo.Description("Note on the bank statement of the recipient "+
+				"(max 30 alphanumeric chars)"),
+			mcpgo.Max(30),
+		),
+		mcpgo.WithObject(
+			"notes",
+			mcpgo.Description("Key-value pairs for additional information "+
+				"(max 15 pairs, 256 chars each)"),
+			mcpgo.MaxProperties(15),
+		),
+		mcpgo.WithString(
+			"idempotency_key",
+			mcpgo.Description("Key that makes retries of the payout safe. "+
+				"Reuse the key of a request that failed without a response "+
+				"to retry it. A random key is used if empty"),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		payoutData := map[string]interface{}{"currency": "INR"}
+		headers := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(payoutData, "account_number").
+			ValidateAndAddRequiredString(payoutData, "fund_account_id").
+			ValidateAndAddRequiredInt(payoutData, "amount").
+			ValidateAndAddOptionalString(payoutData, "currency").
+			ValidateAndAddRequiredString(payoutData, "mode").
+			ValidateAndAddRequiredString(payoutData, "purpose").
+			ValidateAndAddOptionalBool(payoutData, "queue_if_low_balance").
+			ValidateAndAddOptionalString(payoutData, "reference_id").
+			ValidateAndAddOptionalString(payoutData, "narration").
+			ValidateAndAddOptionalMap(payoutData, "notes").
+			ValidateAndAddOptionalString(headers, "idempotency_key")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		idempotencyKey, _ := headers["idempotency_key"].(string)
+		if idempotencyKey == "" {
+			idempotencyKey = rand.Text()
+		}
+
+		payoutsPath := fmt.Sprintf("/%s%s", constants.VERSION_V1,
+			constants.PAYOUT_URL)
+		payout, err := client.Request.Post(payoutsPath, payoutData,
+			map[string]string{payoutIdempotencyHeader: idempotencyKey})
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("creating payout failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(payout)
+	}
+
+	return mcpgo.NewTool(
+		"create_payout",
+		"Pay out from a RazorpayX account to a fund account of a contact. "+
+			"Create the recipient with create_contact and "+
+			"create_fund_account first",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema).WithAmounts("amount")
+}
+
+// CancelQueuedPayout returns a tool that cancels a payout that is queued,
+// for example for low balance
+func CancelQueuedPayout(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"payout_id",
+			mcpgo.Description("ID of the queued payout to cancel, starting "+
+				"with 'pout_'"),
+			mcpgo.Required(),
+			mcpgo.Pattern(mcpgo.IDPattern(payoutIDPrefix)),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		params := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(params, "payout_id")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		payoutID := params["payout_id"].(string)
+
+		if result := validateID("payout_id", payoutID,
+			payoutIDPrefix); result != nil {
+			return result, nil
+		}
+
+		cancelPath := fmt.Sprintf("/%s%s/%s/cancel", constants.VERSION_V1,
+			constants.PAYOUT_URL, payoutID)
+		payout, err := client.Request.Post(cancelPath, nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("cancelling payout failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(payout)
+	}
+
+	return mcpgo.NewTool(
+		"cancel_queued_payout",
+		"Cancel a payout that is queued, for example for low balance. "+
+			"Payouts that are already processing cannot be cancelled",
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(entityOutputSchema)
 }
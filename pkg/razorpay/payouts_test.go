@@ -1,13 +1,22 @@
 package razorpay
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
 	"github.com/razorpay/razorpay-go/constants"
 
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
 	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay/mock"
 )
 
@@ -257,3 +266,332 @@ func Test_FetchAllPayouts(t *testing.T) {
 		})
 	}
 }
+
+func Test_FetchAllPayouts_DateRangeAndExport(t *testing.T) {
+	fetchAllPayoutsPath := fmt.Sprintf(
+		"/%s%s",
+		constants.VERSION_V1,
+		constants.PAYOUT_URL,
+	)
+
+	payoutsResp := map[string]interface{}{
+		"entity": "collection",
+		"count":  float64(2),
+		"items": []interface{}{
+			map[string]interface{}{"id": "pout_1", "amount": float64(100000)},
+			map[string]interface{}{"id": "pout_2", "amount": float64(200000)},
+		},
+	}
+
+	var receivedQuery map[string]string
+	mockHttpClient := func() (*http.Client, *httptest.Server) {
+		server := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != fetchAllPayoutsPath {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				receivedQuery = map[string]string{
+					"account_number": r.URL.Query().Get("account_number"),
+					"from":           r.URL.Query().Get("from"),
+					"to":             r.URL.Query().Get("to"),
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(payoutsResp)
+			}))
+		return server.Client(), server
+	}
+
+	callTool := func(
+		args map[string]interface{},
+	) (map[string]interface{}, string) {
+		client, server := newMockRzpClient(mockHttpClient)
+		defer server.Close()
+
+		tool := FetchAllPayouts(CreateTestObservability(), client)
+		result, err := tool.GetHandler()(
+			context.Background(), createMCPRequest(args))
+		require.NoError(t, err)
+		if result.IsError {
+			return nil, result.Text
+		}
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(result.Text), &response))
+		return response, ""
+	}
+
+	t.Run("forwards date range filter", func(t *testing.T) {
+		response, errMsg := callTool(map[string]interface{}{
+			"account_number": "409002173420",
+			"from":           float64(1704067200),
+			"to":             float64(1706745599),
+		})
+		require.Empty(t, errMsg)
+		assert.Equal(t, payoutsResp, response)
+		assert.Equal(t, map[string]string{
+			"account_number": "409002173420",
+			"from":           "1704067200",
+			"to":             "1706745599",
+		}, receivedQuery)
+	})
+
+	t.Run("exports payouts as ndjson", func(t *testing.T) {
+		t.Chdir(t.TempDir())
+
+		response, errMsg := callTool(map[string]interface{}{
+			"account_number": "409002173420",
+			"from":           float64(1704067200),
+			"output_file":    "exports/payouts.ndjson",
+		})
+		require.Empty(t, errMsg)
+		assert.Equal(t, float64(2), response["rows"])
+		assert.Equal(t, "ndjson", response["format"])
+		assert.Equal(t, float64(1704067200), response["from"])
+
+		wd, err := os.Getwd()
+		require.NoError(t, err)
+		outputPath := filepath.Join(wd, "exports", "payouts.ndjson")
+		assert.Equal(t, outputPath, response["output_file"])
+
+		file, err := os.Open(outputPath)
+		require.NoError(t, err)
+		defer file.Close()
+
+		var ids []string
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var row map[string]interface{}
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &row))
+			ids = append(ids, row["id"].(string))
+		}
+		assert.Equal(t, []string{"pout_1", "pout_2"}, ids)
+	})
+
+	t.Run("rejects path traversal", func(t *testing.T) {
+		for _, path := range []string{
+			"../payouts.ndjson",
+			"exports/../../payouts.ndjson",
+			"/tmp/payouts.ndjson",
+		} {
+			_, errMsg := callTool(map[string]interface{}{
+				"account_number": "409002173420",
+				"output_file":    path,
+			})
+			assert.NotEmpty(t, errMsg, path)
+		}
+	})
+}
+
+func Test_CreateContact(t *testing.T) {
+	contactResp := map[string]interface{}{
+		"id":     "cont_00000000000001",
+		"entity": "contact",
+		"name":   "Gaurav Kumar",
+		"type":   "vendor",
+		"active": true,
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful contact creation",
+			Request: map[string]interface{}{
+				"name":         "Gaurav Kumar",
+				"email":        "gaurav.kumar@example.com",
+				"type":         "vendor",
+				"reference_id": "vendor_42",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     contactsPath,
+						Method:   "POST",
+						Response: contactResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: contactResp,
+		},
+		{
+			Name:           "missing name",
+			Request:        map[string]interface{}{"type": "vendor"},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: name",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, CreateContact, "Contact")
+		})
+	}
+}
+
+func Test_CreatePayout(t *testing.T) {
+	payoutsPath := fmt.Sprintf(
+		"/%s%s",
+		constants.VERSION_V1,
+		constants.PAYOUT_URL,
+	)
+
+	payoutResp := map[string]interface{}{
+		"id":              "pout_00000000000001",
+		"entity":          "payout",
+		"fund_account_id": "fa_00000000000001",
+		"amount":          float64(100000),
+		"status":          "processing",
+	}
+
+	var body map[string]interface{}
+	var idempotencyKey string
+	mockHttpClient := func() (*http.Client, *httptest.Server) {
+		server := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != payoutsPath || r.Method != http.MethodPost {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				idempotencyKey = r.Header.Get(payoutIdempotencyHeader)
+				_ = json.NewDecoder(r.Body).Decode(&body)
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(payoutResp)
+			}))
+		return server.Client(), server
+	}
+
+	callTool := func(args map[string]interface{}) *mcpgo.ToolResult {
+		client, server := newMockRzpClient(mockHttpClient)
+		defer server.Close()
+
+		tool := CreatePayout(CreateTestObservability(), client)
+		result, err := tool.GetHandler()(
+			context.Background(), createMCPRequest(args))
+		require.NoError(t, err)
+		return result
+	}
+
+	args := map[string]interface{}{
+		"account_number":       "7878780080316316",
+		"fund_account_id":      "fa_00000000000001",
+		"amount":               float64(100000),
+		"mode":                 "IMPS",
+		"purpose":              "refund",
+		"queue_if_low_balance": true,
+	}
+
+	t.Run("sends the payout with an idempotency key", func(t *testing.T) {
+		result := callTool(args)
+		require.False(t, result.IsError, result.Text)
+
+		assert.Equal(t, map[string]interface{}{
+			"account_number":       "7878780080316316",
+			"fund_account_id":      "fa_00000000000001",
+			"amount":               float64(100000),
+			"currency":             "INR",
+			"mode":                 "IMPS",
+			"purpose":              "refund",
+			"queue_if_low_balance": true,
+		}, body)
+		assert.NotEmpty(t, idempotencyKey)
+	})
+
+	t.Run("reuses the given idempotency key", func(t *testing.T) {
+		withKey := map[string]interface{}{"idempotency_key": "payout-42"}
+		for name, value := range args {
+			withKey[name] = value
+		}
+
+		result := callTool(withKey)
+		require.False(t, result.IsError, result.Text)
+		assert.Equal(t, "payout-42", idempotencyKey)
+		assert.NotContains(t, body, "idempotency_key")
+	})
+
+	t.Run("missing fund account", func(t *testing.T) {
+		result := callTool(map[string]interface{}{
+			"account_number": "7878780080316316",
+			"amount":         float64(100000),
+			"mode":           "IMPS",
+			"purpose":        "refund",
+		})
+		require.True(t, result.IsError)
+		assert.Contains(t, result.Text,
+			"missing required parameter: fund_account_id")
+	})
+}
+
+func Test_CancelQueuedPayout(t *testing.T) {
+	cancelPath := fmt.Sprintf(
+		"/%s%s/%s/cancel",
+		constants.VERSION_V1,
+		constants.PAYOUT_URL,
+		"pout_00000000000001",
+	)
+
+	cancelledResp := map[string]interface{}{
+		"id":     "pout_00000000000001",
+		"entity": "payout",
+		"status": "cancelled",
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful cancellation",
+			Request: map[string]interface{}{
+				"payout_id": "pout_00000000000001",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     cancelPath,
+						Method:   "POST",
+						Response: cancelledResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: cancelledResp,
+		},
+		{
+			Name: "payout not queued",
+			Request: map[string]interface{}{
+				"payout_id": "pout_00000000000001",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:   cancelPath,
+						Method: "POST",
+						Response: map[string]interface{}{
+							"error": map[string]interface{}{
+								"code": "BAD_REQUEST_ERROR",
+								"description": "Only queued payouts can " +
+									"be cancelled",
+							},
+						},
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "cancelling payout failed: Only queued payouts " +
+				"can be cancelled",
+		},
+		{
+			Name: "invalid payout ID",
+			Request: map[string]interface{}{
+				"payout_id": "pout_1/../../payments",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid payout_id: pout_1/../../payments",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, CancelQueuedPayout, "Payout")
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package razorpay
+
+import (
+	"context"
+	"fmt"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+)
+
+// planItemFields are the keys the item of a plan must contain
+var planItemFields = []string{"name", "amount", "currency"}
+
+// CreatePlan returns a tool that creates a plan for subscriptions
+func CreatePlan(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"period",
+			mcpgo.Description("Unit of the billing cycle, used together "+
+				"with interval"),
+			mcpgo.Required(),
+			mcpgo.Enum("daily", "weekly", "monthly", "quarterly", "yearly"),
+		),
+		mcpgo.WithNumber(
+			"interval",
+			mcpgo.Description("Number of periods in a billing cycle. For "+
+				"example, period 'monthly' with interval 2 bills every two "+
+				"months. Daily plans need an interval of at least 7"),
+			mcpgo.Required(),
+			mcpgo.Min(1),
+		),
+		mcpgo.WithObject(
+			"item",
+			mcpgo.Description("What the customer is charged each billing "+
+				"cycle, with 'name', 'amount' (in the smallest currency "+
+				"unit), 'currency' and an optional 'description'"),
+			mcpgo.Required(),
+		),
+		mcpgo.WithObject(
+			"notes",
+			mcpgo.Description("Key-value pairs for additional information "+
+				"(max 15 pairs, 256 chars each)"),
+			mcpgo.MaxProperties(15),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		planData := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(planData, "period").
+			ValidateAndAddRequiredInt(planData, "interval").
+			ValidateAndAddRequiredMap(planData, "item").
+			ValidateAndAddOptionalMap(planData, "notes")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		item := planData["item"].(map[string]interface{})
+		for _, field := range planItemFields {
+			if _, ok := item[field]; !ok {
+				return mcpgo.NewToolResultError(
+					fmt.Sprintf("missing required item field: %s", field)), nil
+			}
+		}
+
+		if planData["period"] == "daily" && planData["interval"].(int64) < 7 {
+			return mcpgo.NewToolResultError(
+				"interval must be at least 7 for daily plans"), nil
+		}
+
+		plan, err := client.Plan.Create(planData, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("creating plan failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(plan)
+	}
+
+	return mcpgo.NewTool(
+		"create_plan",
+		"Create a plan that defines how much and how often a customer is "+
+			"charged. Use the plan ID in the response with "+
+			"create_subscription.",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema).WithAmounts("item.amount")
+}
+
+// FetchPlan returns a tool that fetches a plan by its ID
+func FetchPlan(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"plan_id",
+			mcpgo.Description("ID of the plan to be fetched "+
+				"(ID should have a plan_ prefix)."),
+			mcpgo.Required(),
+			mcpgo.Pattern(mcpgo.IDPattern(planIDPrefix)),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(fields, "plan_id")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		planID := fields["plan_id"].(string)
+		if result := validateID("plan_id", planID, planIDPrefix); result != nil {
+			return result, nil
+		}
+
+		plan, err := client.Plan.Fetch(planID, nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching plan failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(plan)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_plan",
+		"Fetch the details of a plan, including its billing period, "+
+			"interval and item",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema).
+		WithFieldSelection()
+}
+
+// FetchAllPlans returns a tool that fetches all plans with optional
+// filtering
+func FetchAllPlans(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := append([]mcpgo.ToolParameter{
+		mcpgo.WithNumber(
+			"from",
+			mcpgo.Description("Unix timestamp (in seconds) from when "+
+				"plans are to be fetched"),
+			mcpgo.Min(0),
+		),
+		mcpgo.WithNumber(
+			"to",
+			mcpgo.Description("Unix timestamp (in seconds) up till when "+
+				"plans are to be fetched"),
+			mcpgo.Min(0),
+		),
+		mcpgo.WithNumber(
+			"count",
+			mcpgo.Description("Number of plans to be fetched. "+
+				"Default value is 10. Maximum value is 100"),
+			mcpgo.Min(1),
+			mcpgo.Max(100),
+		),
+		mcpgo.WithNumber(
+			"skip",
+			mcpgo.Description("Number of plans to be skipped. "+
+				"Default value is 0. This can be used for pagination"),
+			mcpgo.Min(0),
+		),
+	}, collectionParameters()...)
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		queryParams := make(map[string]interface{})
+		pagination := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddOptionalInt(queryParams, "from").
+			ValidateAndAddOptionalInt(queryParams, "to").
+			ValidateAndAddPagination(queryParams).
+			ValidateAndAddCollectionOptions(pagination)
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		plans, err := fetchCollection(queryParams, pagination, client.Plan.All)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching plans failed: %s", err.Error())), nil
+		}
+
+		return collectionResult(ctx, plans, pagination)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_all_plans",
+		"Fetch all plans with optional filtering by creation date",
+		parameters,
+		handler,
+	).WithOutputSchema(entitiesOutputSchema).
+		WithFieldSelection()
+}
@@ -0,0 +1,230 @@
+package razorpay
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/razorpay/razorpay-go/constants"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay/mock"
+)
+
+var (
+	plansPath = fmt.Sprintf(
+		"/%s%s",
+		constants.VERSION_V1,
+		constants.PLAN_URL,
+	)
+	planPath = plansPath + "/plan_00000000000001"
+)
+
+var planResp = map[string]interface{}{
+	"id":       "plan_00000000000001",
+	"entity":   "plan",
+	"interval": float64(1),
+	"period":   "weekly",
+	"item": map[string]interface{}{
+		"name":     "Test plan - Weekly",
+		"amount":   float64(69900),
+		"currency": "INR",
+	},
+}
+
+var planErrorResp = map[string]interface{}{
+	"error": map[string]interface{}{
+		"code":        "BAD_REQUEST_ERROR",
+		"description": "The id provided does not exist",
+	},
+}
+
+func Test_CreatePlan(t *testing.T) {
+	item := map[string]interface{}{
+		"name":     "Test plan - Weekly",
+		"amount":   69900,
+		"currency": "INR",
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful plan creation",
+			Request: map[string]interface{}{
+				"period":   "weekly",
+				"interval": 1,
+				"item":     item,
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     plansPath,
+						Method:   "POST",
+						Response: planResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: planResp,
+		},
+		{
+			Name: "missing item",
+			Request: map[string]interface{}{
+				"period":   "weekly",
+				"interval": 1,
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: item",
+		},
+		{
+			Name: "item without currency",
+			Request: map[string]interface{}{
+				"period":   "weekly",
+				"interval": 1,
+				"item": map[string]interface{}{
+					"name":   "Test plan - Weekly",
+					"amount": 69900,
+				},
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required item field: currency",
+		},
+		{
+			Name: "daily plan with short interval",
+			Request: map[string]interface{}{
+				"period":   "daily",
+				"interval": 3,
+				"item":     item,
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "interval must be at least 7 for daily plans",
+		},
+		{
+			Name: "plan creation fails",
+			Request: map[string]interface{}{
+				"period":   "monthly",
+				"interval": 1,
+				"item":     item,
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     plansPath,
+						Method:   "POST",
+						Response: planErrorResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "creating plan failed: " +
+				"The id provided does not exist",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, CreatePlan, "Plan")
+		})
+	}
+}
+
+func Test_FetchPlan(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful plan fetch",
+			Request: map[string]interface{}{
+				"plan_id": "plan_00000000000001",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     planPath,
+						Method:   "GET",
+						Response: planResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: planResp,
+		},
+		{
+			Name: "plan not found",
+			Request: map[string]interface{}{
+				"plan_id": "plan_00000000000001",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     planPath,
+						Method:   "GET",
+						Response: planErrorResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "fetching plan failed: " +
+				"The id provided does not exist",
+		},
+		{
+			Name: "malformed plan_id",
+			Request: map[string]interface{}{
+				"plan_id": "plan_1/../../payments",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid plan_id: plan_1/../../payments",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchPlan, "Plan")
+		})
+	}
+}
+
+func Test_FetchAllPlans(t *testing.T) {
+	plansResp := map[string]interface{}{
+		"entity": "collection",
+		"count":  float64(1),
+		"items":  []interface{}{planResp},
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful plans fetch",
+			Request: map[string]interface{}{
+				"count": float64(10),
+				"skip":  float64(0),
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     plansPath,
+						Method:   "GET",
+						Response: plansResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: plansResp,
+		},
+		{
+			Name: "invalid to type",
+			Request: map[string]interface{}{
+				"to": "tomorrow",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid parameter type: to",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchAllPlans, "Plans")
+		})
+	}
+}
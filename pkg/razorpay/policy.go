@@ -0,0 +1,177 @@
+package razorpay
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// PolicyConfig limits the amounts and currencies write tools can be called
+// with, typically from the policy section of the config file. Amounts are
+// in currency subunits, and 0 sets no limit.
+type PolicyConfig struct {
+	// MaxRefundAmount limits the amount of each refund
+	MaxRefundAmount int64 `mapstructure:"max_refund_amount"`
+	// MaxCaptureAmount limits the amount of each payment capture
+	MaxCaptureAmount int64 `mapstructure:"max_capture_amount"`
+	// MaxInstantSettlementAmount limits the amount of instant settlements
+	MaxInstantSettlementAmount int64 `mapstructure:"max_instant_settlement_amount"` //nolint:lll
+	// AllowedCurrencies limits the currencies passed to write tools, empty
+	// allows every currency
+	AllowedCurrencies []string `mapstructure:"allowed_currencies"`
+}
+
+// isZero reports whether the policy sets no limit
+func (p PolicyConfig) isZero() bool {
+	return p.MaxRefundAmount == 0 && p.MaxCaptureAmount == 0 &&
+		p.MaxInstantSettlementAmount == 0 && len(p.AllowedCurrencies) == 0
+}
+
+// validate reports every invalid limit of the policy
+func (p PolicyConfig) validate() []error {
+	var errs []error
+	limits := []struct {
+		name  string
+		value int64
+	}{
+		{"max_refund_amount", p.MaxRefundAmount},
+		{"max_capture_amount", p.MaxCaptureAmount},
+		{"max_instant_settlement_amount", p.MaxInstantSettlementAmount},
+	}
+	for _, limit := range limits {
+		if limit.value < 0 {
+			errs = append(errs, fmt.Errorf("policy.%s: %d must not be "+
+				"negative", limit.name, limit.value))
+		}
+	}
+	for _, currency := range p.AllowedCurrencies {
+		if !currencyPattern.MatchString(currency) {
+			errs = append(errs, fmt.Errorf("policy.allowed_currencies: %q "+
+				"is not a 3 letter uppercase ISO currency code such as INR",
+				currency))
+		}
+	}
+	return errs
+}
+
+// check returns the violation of the policy by a call to a write tool, or
+// an empty string if the call complies
+func (p PolicyConfig) check(
+	toolName string,
+	args map[string]interface{},
+) string {
+	if violation := p.checkCurrency(args); violation != "" {
+		return violation
+	}
+
+	switch toolName {
+	case "create_refund":
+		return checkRefundAmount(args, p.MaxRefundAmount)
+	case "create_bulk_refunds":
+		refunds, _ := args["refunds"].([]interface{})
+		for i, refund := range refunds {
+			refundArgs, _ := refund.(map[string]interface{})
+			violation := checkRefundAmount(refundArgs, p.MaxRefundAmount)
+			if violation != "" {
+				return fmt.Sprintf("refunds[%d]: %s", i, violation)
+			}
+		}
+	case "capture_payment":
+		return checkAmount(args, "capture", p.MaxCaptureAmount)
+	case "bulk_capture_payments":
+		if p.MaxCaptureAmount > 0 {
+			return fmt.Sprintf("payments are captured at their authorized "+
+				"amount, which cannot be checked against the maximum "+
+				"capture amount of %d, capture payments one by one with "+
+				"capture_payment", p.MaxCaptureAmount)
+		}
+	case "create_instant_settlement":
+		fullBalance, _ := args["settle_full_balance"].(bool)
+		if fullBalance && p.MaxInstantSettlementAmount > 0 {
+			return fmt.Sprintf("settling the full balance cannot be "+
+				"checked against the maximum instant settlement amount of "+
+				"%d, pass an amount instead", p.MaxInstantSettlementAmount)
+		}
+		return checkAmount(args, "instant settlement",
+			p.MaxInstantSettlementAmount)
+	}
+	return ""
+}
+
+// checkCurrency returns the violation of the currency allowlist by the
+// currencies of a call, if any. The currencies of the objects of a call,
+// such as the orders of a batch, the item of a plan or the line items of
+// an invoice, are checked too.
+func (p PolicyConfig) checkCurrency(args map[string]interface{}) string {
+	if len(p.AllowedCurrencies) == 0 {
+		return ""
+	}
+	return p.checkCurrencies("", args)
+}
+
+// checkCurrencies returns the violation of the currency allowlist by the
+// currency fields in the value, named by their path from the name, if any.
+// Notes hold free-form values, so their fields are not currencies.
+func (p PolicyConfig) checkCurrencies(name string, value interface{}) string {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, key := range sortedKeys(v) {
+			if key == "notes" {
+				continue
+			}
+			field := key
+			if name != "" {
+				field = name + "." + key
+			}
+			if key != "currency" {
+				if violation := p.checkCurrencies(field,
+					v[key]); violation != "" {
+					return violation
+				}
+				continue
+			}
+			currency, ok := v[key].(string)
+			if ok && !slices.Contains(p.AllowedCurrencies, currency) {
+				return fmt.Sprintf("%s %s is not allowed, allowed "+
+					"currencies are %s", field, currency,
+					strings.Join(p.AllowedCurrencies, ", "))
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			violation := p.checkCurrencies(fmt.Sprintf("%s[%d]", name, i),
+				item)
+			if violation != "" {
+				return violation
+			}
+		}
+	}
+	return ""
+}
+
+// checkRefundAmount returns the violation of the maximum refund amount by
+// a refund, if any. Refunds without an amount refund the whole payment,
+// whose amount is unknown, so they violate any maximum.
+func checkRefundAmount(args map[string]interface{}, maxAmount int64) string {
+	if _, ok := args["amount"]; !ok && maxAmount > 0 {
+		return fmt.Sprintf("a refund without an amount refunds the whole "+
+			"payment, which cannot be checked against the maximum refund "+
+			"amount of %d, pass an amount instead", maxAmount)
+	}
+	return checkAmount(args, "refund", maxAmount)
+}
+
+// checkAmount returns the violation of the maximum amount by the amount
+// argument of a call, if any
+func checkAmount(
+	args map[string]interface{},
+	operation string,
+	maxAmount int64,
+) string {
+	amount, ok := args["amount"].(float64)
+	if !ok || maxAmount == 0 || amount <= float64(maxAmount) {
+		return ""
+	}
+	return fmt.Sprintf("%s amount %.0f exceeds the maximum %s amount of %d",
+		operation, amount, operation, maxAmount)
+}
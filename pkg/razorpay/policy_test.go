@@ -0,0 +1,250 @@
+package razorpay
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+)
+
+func TestPolicyConfig_check(t *testing.T) {
+	policy := PolicyConfig{
+		MaxRefundAmount:            10000,
+		MaxCaptureAmount:           20000,
+		MaxInstantSettlementAmount: 30000,
+		AllowedCurrencies:          []string{"INR", "USD"},
+	}
+
+	tests := []struct {
+		name      string
+		tool      string
+		args      map[string]interface{}
+		violation string
+	}{
+		{
+			name: "refund up to the maximum",
+			tool: "create_refund",
+			args: map[string]interface{}{
+				"payment_id": "pay_1",
+				"amount":     float64(10000),
+			},
+		},
+		{
+			name: "refund above the maximum",
+			tool: "create_refund",
+			args: map[string]interface{}{
+				"payment_id": "pay_1",
+				"amount":     float64(10001),
+			},
+			violation: "refund amount 10001 exceeds the maximum refund " +
+				"amount of 10000",
+		},
+		{
+			name: "full refund",
+			tool: "create_refund",
+			args: map[string]interface{}{"payment_id": "pay_1"},
+			violation: "a refund without an amount refunds the whole " +
+				"payment, which cannot be checked against the maximum " +
+				"refund amount of 10000, pass an amount instead",
+		},
+		{
+			name: "bulk refund above the maximum",
+			tool: "create_bulk_refunds",
+			args: map[string]interface{}{
+				"refunds": []interface{}{
+					map[string]interface{}{"amount": float64(500)},
+					map[string]interface{}{"amount": float64(50000)},
+				},
+			},
+			violation: "refunds[1]: refund amount 50000 exceeds the " +
+				"maximum refund amount of 10000",
+		},
+		{
+			name: "capture above the maximum",
+			tool: "capture_payment",
+			args: map[string]interface{}{
+				"amount":   float64(25000),
+				"currency": "INR",
+			},
+			violation: "capture amount 25000 exceeds the maximum capture " +
+				"amount of 20000",
+		},
+		{
+			name: "bulk capture",
+			tool: "bulk_capture_payments",
+			args: map[string]interface{}{},
+			violation: "payments are captured at their authorized amount, " +
+				"which cannot be checked against the maximum capture " +
+				"amount of 20000, capture payments one by one with " +
+				"capture_payment",
+		},
+		{
+			name: "instant settlement up to the maximum",
+			tool: "create_instant_settlement",
+			args: map[string]interface{}{"amount": float64(30000)},
+		},
+		{
+			name: "instant settlement of the full balance",
+			tool: "create_instant_settlement",
+			args: map[string]interface{}{"settle_full_balance": true},
+			violation: "settling the full balance cannot be checked " +
+				"against the maximum instant settlement amount of 30000, " +
+				"pass an amount instead",
+		},
+		{
+			name: "currency not allowed",
+			tool: "create_payment_link",
+			args: map[string]interface{}{
+				"amount":   float64(1000000),
+				"currency": "EUR",
+			},
+			violation: "currency EUR is not allowed, allowed currencies " +
+				"are INR, USD",
+		},
+		{
+			name: "currency of a batch order not allowed",
+			tool: "create_orders_batch",
+			args: map[string]interface{}{
+				"orders": []interface{}{
+					map[string]interface{}{
+						"amount":   float64(1000),
+						"currency": "INR",
+					},
+					map[string]interface{}{
+						"amount":   float64(1000),
+						"currency": "EUR",
+					},
+				},
+			},
+			violation: "orders[1].currency EUR is not allowed, allowed " +
+				"currencies are INR, USD",
+		},
+		{
+			name: "currency of a plan item not allowed",
+			tool: "create_plan",
+			args: map[string]interface{}{
+				"period":   "monthly",
+				"interval": float64(1),
+				"item": map[string]interface{}{
+					"name":     "Pro",
+					"amount":   float64(1000),
+					"currency": "EUR",
+				},
+			},
+			violation: "item.currency EUR is not allowed, allowed " +
+				"currencies are INR, USD",
+		},
+		{
+			name: "currency of an invoice line item not allowed",
+			tool: "create_invoice",
+			args: map[string]interface{}{
+				"line_items": []interface{}{
+					map[string]interface{}{
+						"name":     "Pro",
+						"amount":   float64(1000),
+						"currency": "EUR",
+					},
+				},
+			},
+			violation: "line_items[0].currency EUR is not allowed, " +
+				"allowed currencies are INR, USD",
+		},
+		{
+			name: "currency in notes",
+			tool: "create_order",
+			args: map[string]interface{}{
+				"amount":   float64(1000),
+				"currency": "INR",
+				"notes":    map[string]interface{}{"currency": "EUR"},
+			},
+		},
+		{
+			name: "allowed currency",
+			tool: "create_payment_link",
+			args: map[string]interface{}{
+				"amount":   float64(1000000),
+				"currency": "USD",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.violation, policy.check(tc.tool, tc.args))
+		})
+	}
+
+	t.Run("sets no limit by default", func(t *testing.T) {
+		assert.True(t, PolicyConfig{}.isZero())
+		assert.Empty(t, PolicyConfig{}.check("create_refund",
+			map[string]interface{}{"payment_id": "pay_1"}))
+	})
+}
+
+func TestConfigPolicy(t *testing.T) {
+	t.Run("rejects write calls that violate the policy",
+		func(t *testing.T) {
+			server, err := newConfiguredServer(t, Config{
+				Policy: PolicyConfig{MaxRefundAmount: 10000},
+			})
+			require.NoError(t, err)
+
+			tool := server.McpServer.GetTool("create_refund")
+			require.NotNil(t, tool)
+			result, err := tool.Handler(context.Background(),
+				mcp.CallToolRequest{Params: mcp.CallToolParams{
+					Name: "create_refund",
+					Arguments: map[string]interface{}{
+						"payment_id": "pay_1",
+						"amount":     float64(50000),
+					},
+				}})
+			require.NoError(t, err)
+			assert.True(t, result.IsError)
+			content, _ := result.StructuredContent.(map[string]interface{})
+			details, _ := content["error"].(map[string]interface{})
+			assert.Equal(t, mcpgo.PolicyViolationErrorCode, details["code"])
+		})
+
+	t.Run("rejects batches with currencies the policy does not allow",
+		func(t *testing.T) {
+			server, err := newConfiguredServer(t, Config{
+				Policy: PolicyConfig{AllowedCurrencies: []string{"INR"}},
+			})
+			require.NoError(t, err)
+
+			tool := server.McpServer.GetTool("create_orders_batch")
+			require.NotNil(t, tool)
+			result, err := tool.Handler(context.Background(),
+				mcp.CallToolRequest{Params: mcp.CallToolParams{
+					Name: "create_orders_batch",
+					Arguments: map[string]interface{}{
+						"orders": []interface{}{map[string]interface{}{
+							"amount":   float64(29500),
+							"currency": "USD",
+						}},
+					},
+				}})
+			require.NoError(t, err)
+			assert.True(t, result.IsError)
+			content, _ := result.StructuredContent.(map[string]interface{})
+			details, _ := content["error"].(map[string]interface{})
+			assert.Equal(t, mcpgo.PolicyViolationErrorCode, details["code"])
+		})
+
+	t.Run("rejects invalid limits", func(t *testing.T) {
+		_, err := newConfiguredServer(t, Config{Policy: PolicyConfig{
+			MaxCaptureAmount:  -1,
+			AllowedCurrencies: []string{"inr"},
+		}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(),
+			"policy.max_capture_amount: -1 must not be negative")
+		assert.Contains(t, err.Error(), `policy.allowed_currencies: "inr" `+
+			"is not a 3 letter uppercase ISO currency code such as INR")
+	})
+}
@@ -0,0 +1,142 @@
+package razorpay
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+)
+
+// Bounds of the poll interval and timeout of the wait tools, in seconds
+const (
+	defaultPollInterval = 5
+	minPollInterval     = 1
+	maxPollInterval     = 60
+	defaultPollTimeout  = 60
+	maxPollTimeout      = 300
+)
+
+// pollToolTimeout is how long calls of the wait tools may take, enough for
+// their longest timeout
+const pollToolTimeout = maxPollTimeout*time.Second + time.Minute
+
+// pollUnit is the unit of poll intervals and timeouts. Tests shorten it.
+var pollUnit = time.Second
+
+// pollingParameters returns the poll_interval and timeout parameters of a
+// wait tool
+func pollingParameters() []mcpgo.ToolParameter {
+	return []mcpgo.ToolParameter{
+		mcpgo.WithNumber(
+			"poll_interval",
+			mcpgo.Description(fmt.Sprintf("Seconds between two checks "+
+				"(default: %d, min: %d, max: %d)", defaultPollInterval,
+				minPollInterval, maxPollInterval)),
+			mcpgo.Min(minPollInterval),
+			mcpgo.Max(maxPollInterval),
+		),
+		mcpgo.WithNumber(
+			"timeout",
+			mcpgo.Description(fmt.Sprintf("Seconds to wait before giving up "+
+				"and returning the current status (default: %d, max: %d)",
+				defaultPollTimeout, maxPollTimeout)),
+			mcpgo.Min(1),
+			mcpgo.Max(maxPollTimeout),
+		),
+	}
+}
+
+// ValidateAndAddPolling validates and adds the polling parameters
+// (poll_interval and timeout)
+func (v *Validator) ValidateAndAddPolling(
+	params map[string]interface{},
+) *Validator {
+	v.ValidateAndAddOptionalInt(params, "poll_interval").
+		ValidateAndAddOptionalInt(params, "timeout")
+
+	if interval, ok := params["poll_interval"].(int64); ok &&
+		(interval < minPollInterval || interval > maxPollInterval) {
+		v.addError(fmt.Errorf("poll_interval must be between %d and %d",
+			minPollInterval, maxPollInterval))
+	}
+	if timeout, ok := params["timeout"].(int64); ok &&
+		(timeout < 1 || timeout > maxPollTimeout) {
+		v.addError(fmt.Errorf("timeout must be between 1 and %d",
+			maxPollTimeout))
+	}
+
+	return v
+}
+
+// pollOutcome is the result of polling an entity
+type pollOutcome struct {
+	// entity is the last fetched state of the entity
+	entity map[string]interface{}
+	// done is set when the entity reached a terminal state
+	done bool
+	// polls is the number of times the entity was fetched
+	polls int
+}
+
+// poll fetches an entity every interval until done reports that it reached
+// a terminal state, the timeout passes or ctx is done. The entity is
+// fetched one last time once the timeout passes.
+func poll(
+	ctx context.Context,
+	polling map[string]interface{},
+	fetch func() (map[string]interface{}, error),
+	done func(entity map[string]interface{}) bool,
+) (pollOutcome, error) {
+	interval := int64(defaultPollInterval)
+	if value, ok := polling["poll_interval"].(int64); ok {
+		interval = value
+	}
+	timeout := int64(defaultPollTimeout)
+	if value, ok := polling["timeout"].(int64); ok {
+		timeout = value
+	}
+
+	deadline := time.NewTimer(time.Duration(timeout) * pollUnit)
+	defer deadline.Stop()
+	ticker := time.NewTicker(time.Duration(interval) * pollUnit)
+	defer ticker.Stop()
+
+	var outcome pollOutcome
+	for {
+		entity, err := fetch()
+		if err != nil {
+			return outcome, err
+		}
+		outcome.entity = entity
+		outcome.polls++
+		if done(entity) {
+			outcome.done = true
+			return outcome, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return outcome, ctx.Err()
+		case <-deadline.C:
+			return outcome, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// statusIn returns a function that reports whether the status of an
+// entity is one of the statuses
+func statusIn(
+	statuses ...string,
+) func(entity map[string]interface{}) bool {
+	return func(entity map[string]interface{}) bool {
+		status := stringField(entity, "status")
+		for _, terminal := range statuses {
+			if status == terminal {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -0,0 +1,101 @@
+package razorpay
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// shortenPollUnit makes the poll intervals and timeouts of the test
+// milliseconds instead of seconds
+func shortenPollUnit(t *testing.T) {
+	origUnit := pollUnit
+	pollUnit = time.Millisecond
+	t.Cleanup(func() { pollUnit = origUnit })
+}
+
+// sequenceClient returns a mock client that answers GET requests to path
+// with the responses in order, repeating the last one
+func sequenceClient(
+	path string,
+	responses ...map[string]interface{},
+) func() (*http.Client, *httptest.Server) {
+	return func() (*http.Client, *httptest.Server) {
+		var mu sync.Mutex
+		calls := 0
+		server := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet || r.URL.Path != path {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+
+				mu.Lock()
+				response := responses[min(calls, len(responses)-1)]
+				calls++
+				mu.Unlock()
+
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(response)
+			}))
+		return server.Client(), server
+	}
+}
+
+func TestPoll(t *testing.T) {
+	shortenPollUnit(t)
+
+	statuses := func(
+		values ...string,
+	) func() (map[string]interface{}, error) {
+		calls := 0
+		return func() (map[string]interface{}, error) {
+			status := values[min(calls, len(values)-1)]
+			calls++
+			return map[string]interface{}{"status": status}, nil
+		}
+	}
+
+	t.Run("stops at a terminal status", func(t *testing.T) {
+		outcome, err := poll(context.Background(),
+			map[string]interface{}{"poll_interval": int64(1)},
+			statuses("pending", "pending", "processed"),
+			statusIn("processed", "failed"))
+		require.NoError(t, err)
+
+		assert.True(t, outcome.done)
+		assert.Equal(t, 3, outcome.polls)
+		assert.Equal(t, "processed", outcome.entity["status"])
+	})
+
+	t.Run("gives up at the timeout", func(t *testing.T) {
+		outcome, err := poll(context.Background(),
+			map[string]interface{}{
+				"poll_interval": int64(1),
+				"timeout":       int64(20),
+			},
+			statuses("pending"),
+			statusIn("processed", "failed"))
+		require.NoError(t, err)
+
+		assert.False(t, outcome.done)
+		assert.GreaterOrEqual(t, outcome.polls, 1)
+		assert.Equal(t, "pending", outcome.entity["status"])
+	})
+
+	t.Run("stops when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := poll(ctx, map[string]interface{}{"poll_interval": int64(1)},
+			statuses("pending"), statusIn("processed", "failed"))
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -0,0 +1,73 @@
+package razorpay
+
+import (
+	"fmt"
+	"strings"
+)
+
+// profiles are the toolsets of the Razorpay product lines, keyed by
+// profile name
+var profiles = map[string][]string{
+	// pg-core covers accepting payments through the payment gateway
+	"pg-core": {
+		"payments", "orders", "refunds", "payment_links", "qr_codes",
+		"settlements", "disputes", "customers", "webhooks",
+	},
+	// subscriptions covers recurring billing
+	"subscriptions": {
+		"subscriptions", "invoices", "items", "customers", "payments",
+	},
+	// marketplace covers splitting payments between linked accounts
+	"marketplace": {
+		"transfers", "payments", "orders", "refunds", "settlements",
+	},
+	// banking covers RazorpayX payouts and virtual accounts
+	"banking": {
+		"payouts", "fund_accounts", "virtual_accounts", "customers",
+	},
+}
+
+// Profiles returns the names of the profiles in sorted order
+func Profiles() []string {
+	return sortedKeys(profiles)
+}
+
+// toolsetsOfProfile returns the toolsets of a profile
+func toolsetsOfProfile(name string) ([]string, error) {
+	toolsets, exists := profiles[name]
+	if !exists {
+		return nil, fmt.Errorf("profile %s does not exist, available "+
+			"profiles are %s", name, describeProfiles())
+	}
+	return toolsets, nil
+}
+
+// profilesToolsets returns the toolsets of the profiles, without
+// duplicates
+func profilesToolsets(names []string) ([]string, error) {
+	var toolsets []string
+	seen := make(map[string]bool)
+	for _, name := range names {
+		profileToolsets, err := toolsetsOfProfile(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, toolset := range profileToolsets {
+			if !seen[toolset] {
+				seen[toolset] = true
+				toolsets = append(toolsets, toolset)
+			}
+		}
+	}
+	return toolsets, nil
+}
+
+// describeProfiles lists the profiles with their toolsets
+func describeProfiles() string {
+	descriptions := make([]string, 0, len(profiles))
+	for _, name := range Profiles() {
+		descriptions = append(descriptions, fmt.Sprintf("%s (%s)", name,
+			strings.Join(profiles[name], ", ")))
+	}
+	return strings.Join(descriptions, ", ")
+}
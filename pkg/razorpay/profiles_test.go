@@ -0,0 +1,84 @@
+package razorpay
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+)
+
+func TestProfiles(t *testing.T) {
+	t.Run("maps only to existing toolsets", func(t *testing.T) {
+		tg, err := NewToolSets(CreateTestObservability(),
+			rzpsdk.NewClient("test-key", "test-secret"), []string{}, false)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			[]string{"banking", "marketplace", "pg-core", "subscriptions"},
+			Profiles())
+		for name, toolsets := range profiles {
+			for _, toolset := range toolsets {
+				assert.Contains(t, tg.Toolsets, toolset,
+					"profile %s", name)
+			}
+		}
+	})
+
+	t.Run("enables the toolsets of the profiles", func(t *testing.T) {
+		server, err := newConfiguredServer(t, Config{
+			Profiles: []string{"banking", "subscriptions"},
+		})
+		require.NoError(t, err)
+
+		tools := server.McpServer.ListTools()
+		assert.Contains(t, tools, "fetch_payout_with_id")
+		assert.Contains(t, tools, "fetch_subscription")
+		assert.NotContains(t, tools, "fetch_order")
+		assert.NotContains(t, tools, "fetch_transfer")
+	})
+
+	t.Run("adds toolsets to those of the profiles", func(t *testing.T) {
+		obs := CreateTestObservability()
+		client := rzpsdk.NewClient("test-key", "test-secret")
+		server, err := NewRzpMcpServer(obs, client, []string{"orders"},
+			nil, nil, false, WithConfig(Config{
+				Profiles: []string{"banking"},
+			}))
+		require.NoError(t, err)
+
+		impl, ok := server.(*mcpgo.Mark3labsImpl)
+		require.True(t, ok)
+		tools := impl.McpServer.ListTools()
+		assert.Contains(t, tools, "fetch_order")
+		assert.Contains(t, tools, "fetch_payout_with_id")
+		assert.NotContains(t, tools, "fetch_payment")
+	})
+
+	t.Run("rejects unknown profiles", func(t *testing.T) {
+		_, err := newConfiguredServer(t, Config{
+			Profiles: []string{"pg_core"},
+		})
+		require.Error(t, err)
+
+		assert.Contains(t, err.Error(), "invalid config: profile pg_core "+
+			"does not exist, available profiles are banking (payouts, "+
+			"fund_accounts, virtual_accounts, customers), marketplace")
+	})
+
+	t.Run("rejects unknown toolsets", func(t *testing.T) {
+		obs := CreateTestObservability()
+		client := rzpsdk.NewClient("test-key", "test-secret")
+		_, err := NewRzpMcpServer(obs, client, []string{"paymnts"},
+			nil, nil, false)
+		require.Error(t, err)
+
+		message := err.Error()
+		assert.Contains(t, message, "toolset paymnts does not exist, "+
+			"available toolsets are customers, disputes")
+		assert.Contains(t, message, "available profiles are banking")
+	})
+}
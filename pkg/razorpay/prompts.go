@@ -0,0 +1,243 @@
+package razorpay
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+	"github.com/razorpay/razorpay-mcp-server/pkg/toolsets"
+)
+
+// settlementLocation is the time zone settlement days are counted in
+var settlementLocation = time.FixedZone("IST", 5*60*60+30*60)
+
+// now returns the current time, and is replaced in tests
+var now = time.Now
+
+// workflowPrompt is a prompt together with the tools its workflow calls
+type workflowPrompt struct {
+	prompt mcpgo.Prompt
+	tools  []string
+}
+
+// NewPrompts creates the prompts for common payment workflows. A prompt is
+// only created if all the tools its workflow calls are registered.
+func NewPrompts(toolsetGroup *toolsets.ToolsetGroup) []mcpgo.Prompt {
+	workflows := []workflowPrompt{
+		{
+			prompt: CollectUpiPayment(),
+			tools:  []string{"payment_link_upi_create", "fetch_payment_link"},
+		},
+		{
+			prompt: RefundCustomer(),
+			tools: []string{
+				"fetch_payment",
+				"fetch_multiple_refunds_for_payment",
+				"create_refund",
+			},
+		},
+		{
+			prompt: ReconcileSettlements(),
+			tools: []string{
+				"fetch_all_settlements",
+				"fetch_settlement_recon_details",
+			},
+		},
+	}
+
+	var prompts []mcpgo.Prompt
+	for _, workflow := range workflows {
+		if allToolsRegistered(toolsetGroup, workflow.tools) {
+			prompts = append(prompts, workflow.prompt)
+		}
+	}
+	return prompts
+}
+
+// allToolsRegistered reports whether all the tools are registered
+func allToolsRegistered(
+	toolsetGroup *toolsets.ToolsetGroup,
+	tools []string,
+) bool {
+	for _, tool := range tools {
+		if !toolsetGroup.ToolRegistered(tool) {
+			return false
+		}
+	}
+	return true
+}
+
+// CollectUpiPayment returns a prompt that collects a payment over UPI with a
+// payment link
+func CollectUpiPayment() mcpgo.Prompt {
+	arguments := []mcpgo.PromptArgument{
+		{
+			Name:        "amount",
+			Description: "Amount to collect in rupees, for example 499.50",
+			Required:    true,
+		},
+		{
+			Name:        "purpose",
+			Description: "What the customer is paying for",
+		},
+		{
+			Name:        "customer_contact",
+			Description: "Phone number of the customer to send the link to",
+		},
+	}
+
+	handler := func(
+		ctx context.Context,
+		arguments map[string]string,
+	) (string, error) {
+		var text strings.Builder
+		fmt.Fprintf(&text, "Collect a payment of ₹%s from a customer "+
+			"over UPI.\n\n", arguments["amount"])
+		text.WriteString("1. Create a UPI payment link with the " +
+			"`payment_link_upi_create` tool. Convert the amount to paise, " +
+			"and use INR as the currency.")
+		if purpose := arguments["purpose"]; purpose != "" {
+			fmt.Fprintf(&text, " Use %q as the description.", purpose)
+		}
+		if contact := arguments["customer_contact"]; contact != "" {
+			fmt.Fprintf(&text, " Set customer_contact to %q and "+
+				"notify_sms to true so that the link is sent to the "+
+				"customer.", contact)
+		}
+		text.WriteString("\n2. Share the short_url of the link, which " +
+			"opens the customer's UPI app.\n" +
+			"3. When asked whether the customer has paid, check the " +
+			"status of the link with the `fetch_payment_link` tool.")
+
+		return text.String(), nil
+	}
+
+	return mcpgo.NewPrompt(
+		"collect_upi_payment",
+		"Collect a payment from a customer over UPI with a payment link",
+		arguments,
+		handler,
+	)
+}
+
+// RefundCustomer returns a prompt that refunds a payment after checking
+// how much of it can still be refunded
+func RefundCustomer() mcpgo.Prompt {
+	arguments := []mcpgo.PromptArgument{
+		{
+			Name:        "payment_id",
+			Description: "ID of the payment to refund, prefixed with pay_",
+			Required:    true,
+		},
+		{
+			Name: "amount",
+			Description: "Amount to refund in rupees, the full refundable " +
+				"amount if empty",
+		},
+		{
+			Name:        "reason",
+			Description: "Why the customer is being refunded",
+		},
+	}
+
+	handler := func(
+		ctx context.Context,
+		arguments map[string]string,
+	) (string, error) {
+		paymentID := arguments["payment_id"]
+
+		var text strings.Builder
+		fmt.Fprintf(&text, "Refund the customer of payment %s.\n\n",
+			paymentID)
+		text.WriteString("1. Fetch the payment with the `fetch_payment` " +
+			"tool. Only captured payments can be refunded, so stop and " +
+			"explain why if the payment is not captured.\n" +
+			"2. Fetch the earlier refunds of the payment with the " +
+			"`fetch_multiple_refunds_for_payment` tool, and work out the " +
+			"amount that can still be refunded.\n")
+		if amount := arguments["amount"]; amount != "" {
+			fmt.Fprintf(&text, "3. Refund ₹%s, converted to paise, with "+
+				"the `create_refund` tool. Stop if it is more than the "+
+				"refundable amount.", amount)
+		} else {
+			text.WriteString("3. Refund the whole refundable amount with " +
+				"the `create_refund` tool.")
+		}
+		if reason := arguments["reason"]; reason != "" {
+			fmt.Fprintf(&text, " Record %q as the reason in the notes "+
+				"of the refund.", reason)
+		}
+		text.WriteString("\n4. Confirm the refund before creating it, " +
+			"then report its ID and status.")
+
+		return text.String(), nil
+	}
+
+	return mcpgo.NewPrompt(
+		"refund_customer",
+		"Refund a customer's payment, fully or partially",
+		arguments,
+		handler,
+	)
+}
+
+// ReconcileSettlements returns a prompt that reconciles the settlements of
+// a day against the transactions they settle
+func ReconcileSettlements() mcpgo.Prompt {
+	arguments := []mcpgo.PromptArgument{
+		{
+			Name: "date",
+			Description: "Day to reconcile in YYYY-MM-DD format, " +
+				"yesterday if empty",
+		},
+	}
+
+	handler := func(
+		ctx context.Context,
+		arguments map[string]string,
+	) (string, error) {
+		day := now().In(settlementLocation).AddDate(0, 0, -1)
+		if date := arguments["date"]; date != "" {
+			var err error
+			day, err = time.ParseInLocation("2006-01-02", date,
+				settlementLocation)
+			if err != nil {
+				return "", fmt.Errorf(
+					"invalid date %q, expected YYYY-MM-DD", date)
+			}
+		}
+
+		start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0,
+			settlementLocation)
+		end := start.AddDate(0, 0, 1).Add(-time.Second)
+
+		var text strings.Builder
+		fmt.Fprintf(&text, "Reconcile the settlements of %s (IST).\n\n",
+			start.Format("2006-01-02"))
+		fmt.Fprintf(&text, "1. Fetch the settlements of the day with the "+
+			"`fetch_all_settlements` tool, with from set to %d and to "+
+			"set to %d, and auto_paginate set to true so that all "+
+			"settlements of the day are fetched.\n",
+			start.Unix(), end.Unix())
+		fmt.Fprintf(&text, "2. Fetch the reconciliation report of the "+
+			"day with the `fetch_settlement_recon_details` tool, with "+
+			"year %d, month %d and day %d.\n",
+			start.Year(), start.Month(), start.Day())
+		text.WriteString("3. For each settlement, check that its amount " +
+			"matches the credits of its transactions in the report, less " +
+			"fees and tax.\n" +
+			"4. Summarize the settlements with their UTRs and amounts, and " +
+			"list any settlement that is not processed or does not match.")
+
+		return text.String(), nil
+	}
+
+	return mcpgo.NewPrompt(
+		"reconcile_settlements",
+		"Reconcile a day's settlements against the transactions they settle",
+		arguments,
+		handler,
+	)
+}
@@ -0,0 +1,167 @@
+package razorpay
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+)
+
+// promptNames returns the names of the prompts
+func promptNames(prompts []mcpgo.Prompt) []string {
+	names := make([]string, 0, len(prompts))
+	for _, prompt := range prompts {
+		names = append(names, prompt.GetName())
+	}
+	return names
+}
+
+func TestNewPrompts(t *testing.T) {
+	obs := CreateTestObservability()
+	client := &rzpsdk.Client{}
+
+	t.Run("creates all prompts", func(t *testing.T) {
+		toolsetGroup, err := NewToolSets(obs, client, []string{}, false)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{
+			"collect_upi_payment",
+			"refund_customer",
+			"reconcile_settlements",
+		}, promptNames(NewPrompts(toolsetGroup)))
+	})
+
+	t.Run("leaves out prompts of write workflows when read-only",
+		func(t *testing.T) {
+			toolsetGroup, err := NewToolSets(obs, client, []string{}, true)
+			require.NoError(t, err)
+
+			assert.Equal(t, []string{"reconcile_settlements"},
+				promptNames(NewPrompts(toolsetGroup)))
+		})
+
+	t.Run("leaves out prompts of disabled toolsets", func(t *testing.T) {
+		toolsetGroup, err := NewToolSets(
+			obs, client, []string{"payments", "refunds"}, false)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"refund_customer"},
+			promptNames(NewPrompts(toolsetGroup)))
+	})
+}
+
+// renderPrompt renders the prompt with the given arguments
+func renderPrompt(
+	prompt mcpgo.Prompt,
+	arguments map[string]string,
+) (string, error) {
+	return prompt.GetHandler()(context.Background(), arguments)
+}
+
+func TestCollectUpiPayment(t *testing.T) {
+	t.Run("renders required arguments", func(t *testing.T) {
+		text, err := renderPrompt(CollectUpiPayment(),
+			map[string]string{"amount": "499.50"})
+		require.NoError(t, err)
+
+		assert.Contains(t, text, "payment of ₹499.50")
+		assert.Contains(t, text, "`payment_link_upi_create`")
+		assert.NotContains(t, text, "customer_contact")
+	})
+
+	t.Run("renders optional arguments", func(t *testing.T) {
+		text, err := renderPrompt(CollectUpiPayment(), map[string]string{
+			"amount":           "100",
+			"purpose":          "Consultation fee",
+			"customer_contact": "+919876543210",
+		})
+		require.NoError(t, err)
+
+		assert.Contains(t, text, `Use "Consultation fee" as the description`)
+		assert.Contains(t, text, `Set customer_contact to "+919876543210"`)
+	})
+}
+
+func TestRefundCustomer(t *testing.T) {
+	t.Run("refunds full amount by default", func(t *testing.T) {
+		text, err := renderPrompt(RefundCustomer(),
+			map[string]string{"payment_id": "pay_MT48CvBhIC98MQ"})
+		require.NoError(t, err)
+
+		assert.Contains(t, text, "payment pay_MT48CvBhIC98MQ")
+		assert.Contains(t, text, "Refund the whole refundable amount")
+	})
+
+	t.Run("refunds given amount with reason", func(t *testing.T) {
+		text, err := renderPrompt(RefundCustomer(), map[string]string{
+			"payment_id": "pay_MT48CvBhIC98MQ",
+			"amount":     "250",
+			"reason":     "Item out of stock",
+		})
+		require.NoError(t, err)
+
+		assert.Contains(t, text, "Refund ₹250, converted to paise")
+		assert.Contains(t, text, `Record "Item out of stock" as the reason`)
+	})
+}
+
+func TestReconcileSettlements(t *testing.T) {
+	origNow := now
+	defer func() { now = origNow }()
+	// 2024-03-16 01:00 IST, still 2024-03-15 in UTC
+	now = func() time.Time {
+		return time.Date(2024, 3, 15, 19, 30, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name          string
+		arguments     map[string]string
+		expectedTexts []string
+		expectedError string
+	}{
+		{
+			name:      "defaults to yesterday in IST",
+			arguments: map[string]string{},
+			expectedTexts: []string{
+				"settlements of 2024-03-15 (IST)",
+				"from set to 1710441000 and to set to 1710527399",
+				"year 2024, month 3 and day 15",
+			},
+		},
+		{
+			name:      "uses the given date",
+			arguments: map[string]string{"date": "2024-01-31"},
+			expectedTexts: []string{
+				"settlements of 2024-01-31 (IST)",
+				"from set to 1706639400 and to set to 1706725799",
+				"year 2024, month 1 and day 31",
+			},
+		},
+		{
+			name:          "rejects invalid date",
+			arguments:     map[string]string{"date": "31-01-2024"},
+			expectedError: `invalid date "31-01-2024", expected YYYY-MM-DD`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			text, err := renderPrompt(ReconcileSettlements(), tc.arguments)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			for _, expected := range tc.expectedTexts {
+				assert.Contains(t, text, expected)
+			}
+		})
+	}
+}
@@ -2,7 +2,14 @@ package razorpay
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
 
 	rzpsdk "github.com/razorpay/razorpay-go"
 
@@ -132,7 +139,7 @@ func CreateQRCode(
 		"Create a new QR code in Razorpay that can be used to accept UPI payments",
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(entityOutputSchema).WithAmounts("payment_amount")
 }
 
 // FetchQRCode returns a tool that fetches a specific QR code by ID
@@ -168,6 +175,11 @@ func FetchQRCode(
 		}
 		qrCodeID := params["qr_code_id"].(string)
 
+		if result := validateID("qr_code_id", qrCodeID,
+			qrCodeIDPrefix); result != nil {
+			return result, nil
+		}
+
 		// Fetch QR code by ID using Razorpay SDK
 		qrCode, err := client.QrCode.Fetch(qrCodeID, nil, nil)
 		if err != nil {
@@ -183,7 +195,181 @@ func FetchQRCode(
 		"Fetch a QR code's details using it's ID",
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(entityOutputSchema).
+		WithFieldSelection()
+}
+
+// maxQRCodeImageSize caps the size of a QR code image downloaded by
+// get_qr_code_image
+const maxQRCodeImageSize = 2 << 20
+
+// validateQRCodeImageURL ensures a QR code image is only downloaded over
+// HTTPS from Razorpay, including its rzp.io short links, or from the API
+// host the client is configured with
+func validateQRCodeImageURL(imageURL *url.URL, apiBaseURL string) error {
+	if apiURL, err := url.Parse(apiBaseURL); err == nil &&
+		imageURL.Scheme == apiURL.Scheme && imageURL.Host == apiURL.Host {
+		return nil
+	}
+
+	if imageURL.Scheme != "https" {
+		return fmt.Errorf("QR code image URL must use HTTPS")
+	}
+
+	host := imageURL.Hostname()
+	for _, domain := range []string{"razorpay.com", "rzp.io"} {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return nil
+		}
+	}
+	return fmt.Errorf("QR code image URL must be from Razorpay domain")
+}
+
+// downloadQRCodeImage downloads the image of a QR code, following only
+// redirects that stay on Razorpay domains. It returns the image and its
+// MIME type.
+func downloadQRCodeImage(
+	ctx context.Context,
+	client *rzpsdk.Client,
+	rawURL string,
+) ([]byte, string, error) {
+	imageURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid QR code image URL: %s",
+			err.Error())
+	}
+	if err := validateQRCodeImageURL(imageURL,
+		client.Request.BaseURL); err != nil {
+		return nil, "", err
+	}
+
+	httpClient := http.DefaultClient
+	if client.Request.HTTPClient != nil {
+		httpClient = client.Request.HTTPClient
+	}
+	checked := *httpClient
+	checked.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return fmt.Errorf("stopped after 10 redirects")
+		}
+		return validateQRCodeImageURL(req.URL, client.Request.BaseURL)
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, imageURL.String(), nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	resp, err := checked.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, "", fmt.Errorf("unexpected HTTP status: %d",
+			resp.StatusCode)
+	}
+
+	image, err := io.ReadAll(io.LimitReader(resp.Body, maxQRCodeImageSize+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(image) > maxQRCodeImageSize {
+		return nil, "", fmt.Errorf("image exceeds %d bytes",
+			maxQRCodeImageSize)
+	}
+
+	mimeType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mimeType, "image/") {
+		mimeType, _, _ = mime.ParseMediaType(http.DetectContentType(image))
+	}
+	if !strings.HasPrefix(mimeType, "image/") {
+		return nil, "", fmt.Errorf("QR code image URL returned %s, "+
+			"not an image", mimeType)
+	}
+
+	return image, mimeType, nil
+}
+
+// GetQRCodeImage returns a tool that downloads the image of a QR code, so
+// that it can be shown to the customer
+func GetQRCodeImage(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"qr_code_id",
+			mcpgo.Description("ID of the QR code, starting with 'qr_'"),
+			mcpgo.Required(),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		params := make(map[string]interface{})
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(params, "qr_code_id")
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+		qrCodeID := params["qr_code_id"].(string)
+
+		if result := validateID("qr_code_id", qrCodeID,
+			qrCodeIDPrefix); result != nil {
+			return result, nil
+		}
+
+		qrCode, err := client.QrCode.Fetch(qrCodeID, nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching QR code failed: %s", err.Error())), nil
+		}
+
+		imageURL := stringField(qrCode, "image_url")
+		if imageURL == "" {
+			return mcpgo.NewToolResultError(
+				"QR code has no image_url"), nil
+		}
+
+		image, mimeType, err := downloadQRCodeImage(ctx, client, imageURL)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("downloading QR code image failed: %s",
+					err.Error())), nil
+		}
+
+		text, err := json.Marshal(map[string]interface{}{
+			"qr_code_id": qrCodeID,
+			"status":     qrCode["status"],
+			"image_url":  imageURL,
+			"mime_type":  mimeType,
+			"size":       len(image),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return mcpgo.NewToolResultImage(string(text),
+			base64.StdEncoding.EncodeToString(image), mimeType), nil
+	}
+
+	return mcpgo.NewTool(
+		"get_qr_code_image",
+		"Download the image of a QR code and return it as image content, "+
+			"to display the QR code to the customer in the chat",
+		parameters,
+		handler,
+	).WithOutputSchema(objectOutputSchema)
 }
 
 // FetchAllQRCodes returns a tool that fetches all QR codes
@@ -192,7 +378,7 @@ func FetchAllQRCodes(
 	obs *observability.Observability,
 	client *rzpsdk.Client,
 ) mcpgo.Tool {
-	parameters := []mcpgo.ToolParameter{
+	parameters := append([]mcpgo.ToolParameter{
 		mcpgo.WithNumber(
 			"from",
 			mcpgo.Description(
@@ -222,7 +408,7 @@ func FetchAllQRCodes(
 			),
 			mcpgo.Min(0),
 		),
-	}
+	}, collectionParameters()...)
 
 	handler := func(
 		ctx context.Context,
@@ -234,24 +420,26 @@ func FetchAllQRCodes(
 		}
 
 		fetchQROptions := make(map[string]interface{})
+		pagination := make(map[string]interface{})
 
 		validator := NewValidator(&r).
 			ValidateAndAddOptionalInt(fetchQROptions, "from").
 			ValidateAndAddOptionalInt(fetchQROptions, "to").
-			ValidateAndAddPagination(fetchQROptions)
+			ValidateAndAddPagination(fetchQROptions).
+			ValidateAndAddCollectionOptions(pagination)
 
 		if result, err := validator.HandleErrorsIfAny(); result != nil {
 			return result, err
 		}
 
 		// Fetch QR codes using Razorpay SDK
-		qrCodes, err := client.QrCode.All(fetchQROptions, nil)
+		qrCodes, err := fetchCollection(fetchQROptions, pagination, client.QrCode.All)
 		if err != nil {
 			return mcpgo.NewToolResultError(
 				fmt.Sprintf("fetching QR codes failed: %s", err.Error())), nil
 		}
 
-		return mcpgo.NewToolResultJSON(qrCodes)
+		return collectionResult(ctx, qrCodes, pagination)
 	}
 
 	return mcpgo.NewTool(
@@ -259,7 +447,8 @@ func FetchAllQRCodes(
 		"Fetch all QR codes with optional filtering and pagination",
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(entitiesOutputSchema).
+		WithFieldSelection()
 }
 
 // FetchQRCodesByCustomerID returns a tool that fetches QR codes
@@ -311,7 +500,8 @@ func FetchQRCodesByCustomerID(
 		"Fetch all QR codes for a specific customer",
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(entitiesOutputSchema).
+		WithFieldSelection()
 }
 
 // FetchQRCodesByPaymentID returns a tool that fetches QR codes
@@ -364,7 +554,8 @@ func FetchQRCodesByPaymentID(
 		"Fetch all QR codes for a specific payment",
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(entitiesOutputSchema).
+		WithFieldSelection()
 }
 
 // FetchPaymentsForQRCode returns a tool that fetches payments made on a QR code
@@ -437,6 +628,11 @@ func FetchPaymentsForQRCode(
 
 		qrCodeID := params["qr_code_id"].(string)
 
+		if result := validateID("qr_code_id", qrCodeID,
+			qrCodeIDPrefix); result != nil {
+			return result, nil
+		}
+
 		// Fetch payments for QR code using Razorpay SDK
 		payments, err := client.QrCode.FetchPayments(qrCodeID, fetchQROptions, nil)
 		if err != nil {
@@ -452,7 +648,8 @@ func FetchPaymentsForQRCode(
 		"Fetch all payments made on a QR code",
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(paymentsOutputSchema).
+		WithFieldSelection()
 }
 
 // CloseQRCode returns a tool that closes a specific QR code
@@ -488,6 +685,11 @@ func CloseQRCode(
 		}
 		qrCodeID := params["qr_code_id"].(string)
 
+		if result := validateID("qr_code_id", qrCodeID,
+			qrCodeIDPrefix); result != nil {
+			return result, nil
+		}
+
 		// Close QR code by ID using Razorpay SDK
 		qrCode, err := client.QrCode.Close(qrCodeID, nil, nil)
 		if err != nil {
@@ -503,5 +705,5 @@ func CloseQRCode(
 		"Close a QR Code that's no longer needed",
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(entityOutputSchema)
 }
@@ -1,13 +1,21 @@
 package razorpay
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
 	"github.com/razorpay/razorpay-go/constants"
 
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
 	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay/mock"
 )
 
@@ -861,3 +869,157 @@ func TestCloseQRCode(t *testing.T) {
 		})
 	}
 }
+
+func Test_GetQRCodeImage(t *testing.T) {
+	qrCodePath := fmt.Sprintf("/%s%s/%s", constants.VERSION_V1,
+		constants.QRCODE_URL, "qr_HO2jGkWReVBMNu")
+	png := []byte("\x89PNG\r\n\x1a\nimage")
+
+	// qrCodeServer serves the QR code, with an image_url on the server
+	// itself, and its image with the given content type
+	qrCodeServer := func(
+		contentType string,
+		image []byte,
+	) func() (*http.Client, *httptest.Server) {
+		return func() (*http.Client, *httptest.Server) {
+			mux := http.NewServeMux()
+			server := httptest.NewServer(mux)
+			mux.HandleFunc(qrCodePath,
+				func(w http.ResponseWriter, r *http.Request) {
+					_ = json.NewEncoder(w).Encode(map[string]interface{}{
+						"id":        "qr_HO2jGkWReVBMNu",
+						"entity":    "qr_code",
+						"status":    "active",
+						"image_url": server.URL + "/i/BWcUVrLp",
+					})
+				})
+			mux.HandleFunc("/i/BWcUVrLp",
+				func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Content-Type", contentType)
+					_, _ = w.Write(image)
+				})
+			return server.Client(), server
+		}
+	}
+
+	callTool := func(
+		t *testing.T,
+		mockClient func() (*http.Client, *httptest.Server),
+	) *mcpgo.ToolResult {
+		t.Helper()
+
+		client, server := newMockRzpClient(mockClient)
+		defer server.Close()
+
+		tool := GetQRCodeImage(CreateTestObservability(), client)
+		result, err := tool.GetHandler()(context.Background(),
+			createMCPRequest(map[string]interface{}{
+				"qr_code_id": "qr_HO2jGkWReVBMNu",
+			}))
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("returns the image", func(t *testing.T) {
+		result := callTool(t, qrCodeServer("image/png", png))
+		require.False(t, result.IsError, result.Text)
+
+		var details map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(result.Text), &details))
+		assert.Equal(t, "qr_HO2jGkWReVBMNu", details["qr_code_id"])
+		assert.Equal(t, "active", details["status"])
+		assert.Equal(t, "image/png", details["mime_type"])
+		assert.Equal(t, float64(len(png)), details["size"])
+
+		assert.Equal(t, []interface{}{
+			mcpgo.ImageContent{
+				Data:     base64.StdEncoding.EncodeToString(png),
+				MIMEType: "image/png",
+			},
+		}, result.Content)
+	})
+
+	t.Run("detects the type of untyped images", func(t *testing.T) {
+		result := callTool(t, qrCodeServer("application/octet-stream", png))
+		require.False(t, result.IsError, result.Text)
+		assert.Contains(t, result.Text, `"mime_type":"image/png"`)
+	})
+
+	t.Run("rejects content that is not an image", func(t *testing.T) {
+		result := callTool(t, qrCodeServer("text/html",
+			[]byte("<html></html>")))
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Text,
+			"downloading QR code image failed: QR code image URL returned "+
+				"text/html, not an image")
+	})
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "image outside Razorpay",
+			Request: map[string]interface{}{
+				"qr_code_id": "qr_HO2jGkWReVBMNu",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:   qrCodePath,
+						Method: "GET",
+						Response: map[string]interface{}{
+							"id":        "qr_HO2jGkWReVBMNu",
+							"image_url": "https://example.com/qr.png",
+						},
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "downloading QR code image failed: QR code image " +
+				"URL must be from Razorpay domain",
+		},
+		{
+			Name:           "missing qr_code_id",
+			Request:        map[string]interface{}{},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: qr_code_id",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, GetQRCodeImage, "QR Code Image")
+		})
+	}
+}
+
+func Test_validateQRCodeImageURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr string
+	}{
+		{url: "https://rzp.io/i/BWcUVrLp"},
+		{url: "https://cdn.razorpay.com/qrcodes/qr.png"},
+		{
+			url:     "http://rzp.io/i/BWcUVrLp",
+			wantErr: "QR code image URL must use HTTPS",
+		},
+		{
+			url:     "https://rzp.io.example.com/i/BWcUVrLp",
+			wantErr: "QR code image URL must be from Razorpay domain",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.url, func(t *testing.T) {
+			imageURL, err := url.Parse(tc.url)
+			require.NoError(t, err)
+
+			err = validateQRCodeImageURL(imageURL, "https://api.razorpay.com")
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.wantErr)
+			}
+		})
+	}
+}
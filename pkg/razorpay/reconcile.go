@@ -0,0 +1,302 @@
+package razorpay
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+)
+
+// Limits of period reconciliations
+const (
+	// maxReconcileDays is the longest window a reconciliation may span
+	maxReconcileDays = 31
+	// maxReconcileRecords is the most orders, payments, refunds or
+	// settlement entries a reconciliation looks at, each
+	maxReconcileRecords = 1000
+)
+
+// Groups of reconciliation findings
+const (
+	reconUnmatched = "unmatched"
+	reconFailed    = "failed"
+	reconPending   = "pending"
+)
+
+// reconciliation collects the findings of a period reconciliation, grouped
+// by kind and then by entity type
+type reconciliation map[string]map[string][]interface{}
+
+// add records a finding about an entity
+func (r reconciliation) add(
+	group string,
+	entityType string,
+	entity map[string]interface{},
+	reason string,
+) {
+	r[group][entityType] = append(r[group][entityType],
+		reconFinding(entity, reason))
+}
+
+// reconFinding summarizes an entity for a reconciliation finding
+func reconFinding(
+	entity map[string]interface{},
+	reason string,
+) map[string]interface{} {
+	finding := map[string]interface{}{"reason": reason}
+	for _, field := range []string{
+		"id", "entity_id", "type", "amount", "currency", "status",
+		"order_id", "payment_id", "settlement_id", "created_at",
+		"error_description",
+	} {
+		if value, ok := entity[field]; ok && value != nil {
+			finding[field] = value
+		}
+	}
+	return finding
+}
+
+// ReconcilePeriod returns a tool that reconciles the orders, payments,
+// refunds and settlements of a window
+func ReconcilePeriod(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithNumber(
+			"from",
+			mcpgo.Description("Unix timestamp (in seconds) of the start of "+
+				"the window"),
+			mcpgo.Required(),
+			mcpgo.Min(0),
+		),
+		mcpgo.WithNumber(
+			"to",
+			mcpgo.Description(fmt.Sprintf("Unix timestamp (in seconds) of "+
+				"the end of the window, at most %d days after from",
+				maxReconcileDays)),
+			mcpgo.Required(),
+			mcpgo.Min(0),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		window := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredInt(window, "from").
+			ValidateAndAddRequiredInt(window, "to")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		from, to := window["from"].(int64), window["to"].(int64)
+		if to < from {
+			return mcpgo.NewToolResultError("to must not be before from"), nil
+		}
+		if to-from > maxReconcileDays*24*60*60 {
+			return mcpgo.NewToolResultError(fmt.Sprintf(
+				"window must not span more than %d days",
+				maxReconcileDays)), nil
+		}
+
+		truncated := false
+		fetchWindow := func(
+			entityType string,
+			fetch pageFetcher,
+		) ([]interface{}, error) {
+			items, err := fetchReconcileRecords(
+				map[string]interface{}{"from": from, "to": to}, fetch)
+			if err != nil {
+				return nil, fmt.Errorf("fetching %s failed: %w", entityType, err)
+			}
+			if len(items) > maxReconcileRecords {
+				truncated = true
+				items = items[:maxReconcileRecords]
+			}
+			return items, nil
+		}
+
+		orders, err := fetchWindow("orders", client.Order.All)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+		payments, err := fetchWindow("payments", client.Payment.All)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+		refunds, err := fetchWindow("refunds", client.Refund.All)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		var settlementEntries []interface{}
+		first := time.Unix(from, 0).In(settlementLocation)
+		last := time.Unix(to, 0).In(settlementLocation)
+		first = time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0,
+			settlementLocation)
+		for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+			items, err := fetchReconcileRecords(map[string]interface{}{
+				"year":  day.Year(),
+				"month": int(day.Month()),
+				"day":   day.Day(),
+			}, client.Settlement.Reports)
+			if err != nil {
+				return mcpgo.NewToolResultError(fmt.Sprintf(
+					"fetching settlement reconciliation report failed: %s",
+					err.Error())), nil
+			}
+			settlementEntries = append(settlementEntries, items...)
+			if len(settlementEntries) > maxReconcileRecords {
+				truncated = true
+				settlementEntries = settlementEntries[:maxReconcileRecords]
+				break
+			}
+		}
+
+		findings := reconcile(orders, payments, refunds, settlementEntries)
+
+		return mcpgo.NewToolResultJSON(map[string]interface{}{
+			"from": from,
+			"to":   to,
+			"counts": map[string]interface{}{
+				"orders":             len(orders),
+				"payments":           len(payments),
+				"refunds":            len(refunds),
+				"settlement_entries": len(settlementEntries),
+			},
+			reconUnmatched: findings[reconUnmatched],
+			reconFailed:    findings[reconFailed],
+			reconPending:   findings[reconPending],
+			"truncated":    truncated,
+		})
+	}
+
+	return mcpgo.NewTool(
+		"reconcile_period",
+		fmt.Sprintf("Reconcile the orders, payments and refunds created in "+
+			"a window of up to %d days against each other and against the "+
+			"settlements of the days of the window. Returns the unmatched, "+
+			"failed and pending entries grouped by type. Payments captured "+
+			"near the end of the window may be settled after it, and show "+
+			"as pending settlement. At most %d records of each type are "+
+			"looked at", maxReconcileDays, maxReconcileRecords),
+		parameters,
+		handler,
+	).WithOutputSchema(objectOutputSchema).
+		WithTimeout(scanToolTimeout)
+}
+
+// fetchReconcileRecords fetches all pages of a collection, up to one record
+// more than a reconciliation looks at, to tell whether any were left out
+func fetchReconcileRecords(
+	queryParams map[string]interface{},
+	fetch pageFetcher,
+) ([]interface{}, error) {
+	items, _, err := scanPages(queryParams, maxReconcileRecords+1,
+		maxReconcileRecords+1, nil, fetch)
+	return items, err
+}
+
+// reconcile joins orders, payments, refunds and settlement recon entries
+// by ID and reports the entries that do not add up
+func reconcile(
+	orders, payments, refunds, settlementEntries []interface{},
+) reconciliation {
+	findings := reconciliation{
+		reconUnmatched: {},
+		reconFailed:    {},
+		reconPending:   {},
+	}
+
+	paymentsByID := make(map[string]map[string]interface{})
+	capturedByOrder := make(map[string]bool)
+	for _, item := range payments {
+		payment, _ := item.(map[string]interface{})
+		paymentsByID[stringField(payment, "id")] = payment
+		if stringField(payment, "status") == "captured" ||
+			stringField(payment, "status") == "refunded" {
+			capturedByOrder[stringField(payment, "order_id")] = true
+		}
+	}
+
+	settledPayments := make(map[string]bool)
+	for _, item := range settlementEntries {
+		entry, _ := item.(map[string]interface{})
+		if stringField(entry, "type") != "payment" {
+			continue
+		}
+		paymentID := stringField(entry, "entity_id")
+		settledPayments[paymentID] = true
+
+		payment, ok := paymentsByID[paymentID]
+		if !ok {
+			continue
+		}
+		if entry["amount"] != payment["amount"] {
+			findings.add(reconUnmatched, "settlement_entries", entry,
+				"settled amount differs from the payment amount")
+		}
+	}
+
+	for _, item := range orders {
+		order, _ := item.(map[string]interface{})
+		switch stringField(order, "status") {
+		case "paid":
+			if !capturedByOrder[stringField(order, "id")] {
+				findings.add(reconUnmatched, "orders", order,
+					"paid order has no captured payment in the window")
+			}
+		case "created", "attempted":
+			findings.add(reconPending, "orders", order, "order is not paid")
+		}
+	}
+
+	for _, item := range payments {
+		payment, _ := item.(map[string]interface{})
+		switch stringField(payment, "status") {
+		case "failed":
+			findings.add(reconFailed, "payments", payment, "payment failed")
+		case "created", "authorized":
+			findings.add(reconPending, "payments", payment,
+				"payment is not captured")
+		case "captured":
+			if !settledPayments[stringField(payment, "id")] {
+				findings.add(reconPending, "payments", payment,
+					"captured payment is not settled in the window")
+			}
+		}
+	}
+
+	for _, item := range refunds {
+		refund, _ := item.(map[string]interface{})
+		switch stringField(refund, "status") {
+		case "failed":
+			findings.add(reconFailed, "refunds", refund, "refund failed")
+		case "pending":
+			findings.add(reconPending, "refunds", refund,
+				"refund is not processed")
+		case "processed":
+			payment, ok := paymentsByID[stringField(refund, "payment_id")]
+			if ok && payment["amount_refunded"] == float64(0) {
+				findings.add(reconUnmatched, "refunds", refund,
+					"refunded payment shows no refunded amount")
+			}
+		}
+	}
+
+	return findings
+}
@@ -0,0 +1,202 @@
+package razorpay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/razorpay/razorpay-go/constants"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay/mock"
+)
+
+func collectionOf(items ...interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"entity": "collection",
+		"count":  len(items),
+		"items":  items,
+	}
+}
+
+func Test_ReconcilePeriod(t *testing.T) {
+	ordersPath := fmt.Sprintf("/%s%s", constants.VERSION_V1,
+		constants.ORDER_URL)
+	paymentsPath := fmt.Sprintf("/%s%s", constants.VERSION_V1,
+		constants.PAYMENT_URL)
+	refundsPath := fmt.Sprintf("/%s%s", constants.VERSION_V1,
+		constants.REFUND_URL)
+	reconPath := fmt.Sprintf("/%s%s/recon/combined", constants.VERSION_V1,
+		constants.SETTLEMENT_URL)
+
+	orders := collectionOf(
+		map[string]interface{}{"id": "order_paid", "status": "paid"},
+		map[string]interface{}{"id": "order_orphan", "status": "paid"},
+		map[string]interface{}{"id": "order_open", "status": "attempted"},
+	)
+	payments := collectionOf(
+		map[string]interface{}{
+			"id": "pay_settled", "status": "captured", "amount": float64(1000),
+			"order_id": "order_paid",
+		},
+		map[string]interface{}{
+			"id": "pay_short", "status": "captured", "amount": float64(2000),
+		},
+		map[string]interface{}{
+			"id": "pay_unsettled", "status": "captured",
+			"amount": float64(3000),
+		},
+		map[string]interface{}{
+			"id": "pay_failed", "status": "failed", "amount": float64(4000),
+			"error_description": "Payment was declined by the bank",
+		},
+		map[string]interface{}{
+			"id": "pay_authorized", "status": "authorized",
+			"amount": float64(5000),
+		},
+	)
+	refunds := collectionOf(
+		map[string]interface{}{
+			"id": "rfnd_failed", "status": "failed", "payment_id": "pay_x",
+		},
+		map[string]interface{}{
+			"id": "rfnd_pending", "status": "pending", "payment_id": "pay_y",
+		},
+	)
+	recon := collectionOf(
+		map[string]interface{}{
+			"entity_id": "pay_settled", "type": "payment",
+			"amount": float64(1000), "settlement_id": "setl_1",
+		},
+		map[string]interface{}{
+			"entity_id": "pay_short", "type": "payment",
+			"amount": float64(1500), "settlement_id": "setl_1",
+		},
+	)
+
+	// A window within 2024-01-15 in IST, so the recon of one day is fetched
+	window := map[string]interface{}{
+		"from": float64(1705257000),
+		"to":   float64(1705343399),
+	}
+
+	t.Run("groups unmatched, failed and pending entries", func(t *testing.T) {
+		client, server := newMockRzpClient(func() (
+			*http.Client, *httptest.Server) {
+			return mock.NewHTTPClient(
+				mock.Endpoint{Path: ordersPath, Method: "GET",
+					Response: orders},
+				mock.Endpoint{Path: paymentsPath, Method: "GET",
+					Response: payments},
+				mock.Endpoint{Path: refundsPath, Method: "GET",
+					Response: refunds},
+				mock.Endpoint{Path: reconPath, Method: "GET",
+					Response: recon},
+			)
+		})
+		defer server.Close()
+
+		tool := ReconcilePeriod(CreateTestObservability(), client)
+		result, err := tool.GetHandler()(
+			context.Background(), createMCPRequest(window))
+		require.NoError(t, err)
+		require.False(t, result.IsError, result.Text)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(result.Text), &response))
+
+		ids := func(group, entityType string) []string {
+			entries, _ := response[group].(map[string]interface{})
+			var ids []string
+			items, _ := entries[entityType].([]interface{})
+			for _, item := range items {
+				entry := item.(map[string]interface{})
+				id, ok := entry["id"].(string)
+				if !ok {
+					id = entry["entity_id"].(string)
+				}
+				ids = append(ids, id)
+			}
+			return ids
+		}
+
+		assert.Equal(t, []string{"order_orphan"}, ids("unmatched", "orders"))
+		assert.Equal(t, []string{"pay_short"},
+			ids("unmatched", "settlement_entries"))
+		assert.Equal(t, []string{"pay_failed"}, ids("failed", "payments"))
+		assert.Equal(t, []string{"rfnd_failed"}, ids("failed", "refunds"))
+		assert.Equal(t, []string{"order_open"}, ids("pending", "orders"))
+		assert.Equal(t, []string{"pay_unsettled", "pay_authorized"},
+			ids("pending", "payments"))
+		assert.Equal(t, []string{"rfnd_pending"}, ids("pending", "refunds"))
+
+		assert.Equal(t, map[string]interface{}{
+			"orders":             float64(3),
+			"payments":           float64(5),
+			"refunds":            float64(2),
+			"settlement_entries": float64(2),
+		}, response["counts"])
+		assert.Equal(t, false, response["truncated"])
+	})
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name:           "missing window",
+			Request:        map[string]interface{}{"from": float64(1705257000)},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: to",
+		},
+		{
+			Name: "reversed window",
+			Request: map[string]interface{}{
+				"from": float64(1705343399),
+				"to":   float64(1705257000),
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "to must not be before from",
+		},
+		{
+			Name: "window too long",
+			Request: map[string]interface{}{
+				"from": float64(1705257000),
+				"to":   float64(1705257000 + 32*24*60*60),
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "window must not span more than 31 days",
+		},
+		{
+			Name:    "api error",
+			Request: window,
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:   ordersPath,
+						Method: "GET",
+						Response: map[string]interface{}{
+							"error": map[string]interface{}{
+								"code":        "BAD_REQUEST_ERROR",
+								"description": "invalid request",
+							},
+						},
+					},
+				)
+			},
+			ExpectError:    true,
+			ExpectedErrMsg: "fetching orders failed",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, ReconcilePeriod, "Reconciliation")
+		})
+	}
+}
@@ -0,0 +1,411 @@
+package razorpay
+
+import (
+	"context"
+	"fmt"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+	"github.com/razorpay/razorpay-go/constants"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+)
+
+// registrationLinksPath is the path of the Registration Links API, which
+// the SDK does not cover
+var registrationLinksPath = fmt.Sprintf(
+	"/%s/subscription_registration/auth_links", constants.VERSION_V1)
+
+// mandateFrequencies are the debit frequencies a UPI Autopay mandate can
+// be registered with
+var mandateFrequencies = []interface{}{
+	"daily", "weekly", "monthly", "yearly", "as_presented",
+}
+
+// CreateUpiAutopayRegistrationLink returns a tool that creates the
+// authorization transaction of a UPI Autopay mandate as a registration
+// link, which Razorpay sends to the customer to approve the mandate
+func CreateUpiAutopayRegistrationLink(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"customer_name",
+			mcpgo.Description("Name of the customer"),
+			mcpgo.Required(),
+		),
+		mcpgo.WithString(
+			"customer_email",
+			mcpgo.Description("Email address of the customer"),
+			mcpgo.Required(),
+		),
+		mcpgo.WithString(
+			"customer_contact",
+			mcpgo.Description("Phone number of the customer"),
+			mcpgo.Required(),
+		),
+		mcpgo.WithNumber(
+			"amount",
+			mcpgo.Description("Amount of the authorization payment in paise. "+
+				"It is charged when the customer approves the mandate"),
+			mcpgo.Required(),
+			mcpgo.Min(100),
+		),
+		mcpgo.WithString(
+			"description",
+			mcpgo.Description("Description shown to the customer on the "+
+				"registration link"),
+			mcpgo.Required(),
+		),
+		mcpgo.WithNumber(
+			"max_amount",
+			mcpgo.Description("Maximum amount in paise that can be debited "+
+				"in a single recurring payment"),
+			mcpgo.Required(),
+			mcpgo.Min(100),
+		),
+		mcpgo.WithString(
+			"frequency",
+			mcpgo.Description("How often the customer can be debited"),
+			mcpgo.Required(),
+			mcpgo.Enum(mandateFrequencies...),
+		),
+		mcpgo.WithNumber(
+			"expire_at",
+			mcpgo.Description("Unix timestamp (in seconds) when the mandate "+
+				"expires. Defaults to 10 years after it is approved"),
+		),
+		mcpgo.WithNumber(
+			"expire_by",
+			mcpgo.Description("Unix timestamp (in seconds) after which the "+
+				"registration link can no longer be used"),
+		),
+		mcpgo.WithString(
+			"receipt",
+			mcpgo.Description("Receipt number for internal reference "+
+				"(max 40 chars, must be unique)"),
+			mcpgo.Max(40),
+		),
+		mcpgo.WithBoolean(
+			"sms_notify",
+			mcpgo.Description("Whether Razorpay sends the link to the "+
+				"customer by SMS. Defaults to true"),
+		),
+		mcpgo.WithBoolean(
+			"email_notify",
+			mcpgo.Description("Whether Razorpay sends the link to the "+
+				"customer by email. Defaults to true"),
+		),
+		mcpgo.WithObject(
+			"notes",
+			mcpgo.Description("Key-value pairs for additional information "+
+				"(max 15 pairs, 256 chars each)"),
+			mcpgo.MaxProperties(15),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		linkData := map[string]interface{}{
+			"type":     "link",
+			"currency": "INR",
+		}
+		customer := make(map[string]interface{})
+		registration := map[string]interface{}{"method": "upi"}
+
+		validator := NewValidator(&r).
+			ValidateAndAddOptionalStringToPath(customer, "customer_name", "name").
+			ValidateAndAddOptionalStringToPath(customer, "customer_email",
+				"email").
+			ValidateAndAddOptionalStringToPath(customer, "customer_contact",
+				"contact").
+			ValidateAndAddRequiredInt(linkData, "amount").
+			ValidateAndAddRequiredString(linkData, "description").
+			ValidateAndAddRequiredInt(registration, "max_amount").
+			ValidateAndAddRequiredString(registration, "frequency").
+			ValidateAndAddOptionalInt(registration, "expire_at").
+			ValidateAndAddOptionalInt(linkData, "expire_by").
+			ValidateAndAddOptionalString(linkData, "receipt").
+			ValidateAndAddOptionalBool(linkData, "sms_notify").
+			ValidateAndAddOptionalBool(linkData, "email_notify").
+			ValidateAndAddOptionalMap(linkData, "notes")
+
+		for _, name := range []string{"name", "email", "contact"} {
+			if customer[name] == nil || customer[name] == "" {
+				validator.addError(fmt.Errorf(
+					"missing required parameter: customer_%s", name))
+			}
+		}
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		linkData["customer"] = customer
+		linkData["subscription_registration"] = registration
+
+		link, err := client.Request.Post(registrationLinksPath, linkData, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(fmt.Sprintf(
+				"creating registration link failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(link)
+	}
+
+	return mcpgo.NewTool(
+		"create_upi_autopay_registration_link",
+		"Start a UPI Autopay mandate by creating a registration link. "+
+			"Razorpay sends the link to the customer, who pays the "+
+			"authorization amount and approves the mandate in their UPI app. "+
+			"The approved mandate becomes a recurring token of the customer, "+
+			"listed by fetch_customer_tokens and charged with "+
+			"create_recurring_payment. The response contains the short_url "+
+			"of the link and the order_id of the authorization transaction.",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema).WithAmounts("amount", "max_amount")
+}
+
+// FetchCustomerTokens returns a tool that fetches the saved payment tokens
+// of a customer, such as the mandates of recurring payments
+func FetchCustomerTokens(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"customer_id",
+			mcpgo.Description("ID of the customer whose tokens are fetched "+
+				"(ID should have a cust_ prefix)."),
+			mcpgo.Required(),
+			mcpgo.Pattern(mcpgo.IDPattern(customerIDPrefix)),
+		),
+		mcpgo.WithBoolean(
+			"recurring_only",
+			mcpgo.Description("Only return tokens that can be charged for "+
+				"recurring payments, such as approved UPI Autopay mandates"),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(fields, "customer_id").
+			ValidateAndAddOptionalBool(fields, "recurring_only")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		customerID := fields["customer_id"].(string)
+		if result := validateID("customer_id",
+			customerID, customerIDPrefix); result != nil {
+			return result, nil
+		}
+
+		tokens, err := client.Token.All(customerID, nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching tokens failed: %s", err.Error())), nil
+		}
+
+		if fields["recurring_only"] == true {
+			tokens = recurringTokens(tokens)
+		}
+
+		return mcpgo.NewToolResultJSON(tokens)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_customer_tokens",
+		"Fetch the saved payment tokens of a customer, including cards and "+
+			"UPI Autopay mandates. Each token has a recurring flag and, for "+
+			"mandates, recurring_details with the mandate status. Tokens "+
+			"whose mandate is confirmed can be charged with "+
+			"create_recurring_payment.",
+		parameters,
+		handler,
+	).WithOutputSchema(entitiesOutputSchema).
+		WithFieldSelection()
+}
+
+// recurringTokens returns the token collection with only the tokens that
+// can be charged for recurring payments
+func recurringTokens(
+	tokens map[string]interface{},
+) map[string]interface{} {
+	items, _ := tokens["items"].([]interface{})
+
+	recurring := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		token, ok := item.(map[string]interface{})
+		if ok && token["recurring"] == true {
+			recurring = append(recurring, token)
+		}
+	}
+
+	filtered := make(map[string]interface{}, len(tokens))
+	for key, value := range tokens {
+		filtered[key] = value
+	}
+	filtered["items"] = recurring
+	filtered["count"] = len(recurring)
+	return filtered
+}
+
+// CreateRecurringPayment returns a tool that charges a customer with a
+// recurring token, such as a saved card mandate or an approved UPI
+// Autopay mandate
+func CreateRecurringPayment(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"order_id",
+			mcpgo.Description("ID of the order created for this charge with "+
+				"create_order, for the same amount "+
+				"(ID should have an order_ prefix)."),
+			mcpgo.Required(),
+		),
+		mcpgo.WithString(
+			"customer_id",
+			mcpgo.Description("ID of the customer the token belongs to "+
+				"(ID should have a cust_ prefix)."),
+			mcpgo.Required(),
+		),
+		mcpgo.WithString(
+			"token",
+			mcpgo.Description("ID of the recurring card or UPI token to "+
+				"charge (ID should have a token_ prefix)."),
+			mcpgo.Required(),
+		),
+		mcpgo.WithNumber(
+			"amount",
+			mcpgo.Description("Amount to charge in paise. It must match "+
+				"the order amount and cannot exceed the max_amount of the "+
+				"mandate"),
+			mcpgo.Required(),
+			mcpgo.Min(100),
+		),
+		mcpgo.WithString(
+			"currency",
+			mcpgo.Description("Currency of the payment. Defaults to INR"),
+		),
+		mcpgo.WithString(
+			"email",
+			mcpgo.Description("Email address of the customer"),
+			mcpgo.Required(),
+		),
+		mcpgo.WithString(
+			"contact",
+			mcpgo.Description("Phone number of the customer"),
+			mcpgo.Required(),
+		),
+		mcpgo.WithString(
+			"description",
+			mcpgo.Description("Description of the payment"),
+		),
+		mcpgo.WithObject(
+			"notes",
+			mcpgo.Description("Key-value pairs for additional information "+
+				"(max 15 pairs, 256 chars each)"),
+			mcpgo.MaxProperties(15),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		paymentData := map[string]interface{}{
+			"currency":  "INR",
+			"recurring": "1",
+		}
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(paymentData, "order_id").
+			ValidateAndAddRequiredString(paymentData, "customer_id").
+			ValidateAndAddRequiredString(paymentData, "token").
+			ValidateAndAddRequiredInt(paymentData, "amount").
+			ValidateAndAddOptionalString(paymentData, "currency").
+			ValidateAndAddRequiredString(paymentData, "email").
+			ValidateAndAddRequiredString(paymentData, "contact").
+			ValidateAndAddOptionalString(paymentData, "description").
+			ValidateAndAddOptionalMap(paymentData, "notes")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		payment, err := client.Payment.CreateRecurringPayment(paymentData, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(fmt.Sprintf(
+				"creating recurring payment failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(
+			buildRecurringPaymentResponse(ctx, payment))
+	}
+
+	return mcpgo.NewTool(
+		"create_recurring_payment",
+		"Charge a customer with a recurring token, such as a saved card "+
+			"mandate or an approved UPI Autopay mandate, without the "+
+			"customer taking part. Create an order for the amount with "+
+			"create_order first. The response contains the "+
+			"razorpay_payment_id and next_actions: debits complete "+
+			"asynchronously, so check the payment with fetch_payment. Cancel "+
+			"a mandate with revoke_token.",
+		parameters,
+		handler,
+	).WithOutputSchema(objectOutputSchema).WithAmounts("amount")
+}
+
+// buildRecurringPaymentResponse adds the next actions of a recurring
+// payment to the response of the API. Recurring payments need no customer
+// authentication, unless the issuer asks for it with a redirect.
+func buildRecurringPaymentResponse(
+	ctx context.Context,
+	payment map[string]interface{},
+) map[string]interface{} {
+	response := make(map[string]interface{}, len(payment)+1)
+	for key, value := range payment {
+		response[key] = value
+	}
+
+	paymentID := extractPaymentID(payment)
+	actions := redirectNextActions(ctx,
+		gatewayActionURL(extractNextActions(payment), "redirect"))
+	if paymentID != "" {
+		actions = append(actions, fetchPaymentNextAction(paymentID,
+			localize(ctx, "Use 'fetch_payment' to check whether the "+
+				"recurring payment was captured.")))
+	}
+	setNextActions(response, actions...)
+
+	return response
+}
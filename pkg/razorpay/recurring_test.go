@@ -0,0 +1,350 @@
+package razorpay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/razorpay/razorpay-go/constants"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay/mock"
+)
+
+func Test_CreateUpiAutopayRegistrationLink(t *testing.T) {
+	linkResp := map[string]interface{}{
+		"id":        "inv_00000000000001",
+		"entity":    "invoice",
+		"order_id":  "order_00000000000001",
+		"short_url": "https://rzp.io/i/VSriCfn",
+		"status":    "issued",
+	}
+
+	t.Run("customer and mandate are nested", func(t *testing.T) {
+		var path string
+		var body map[string]interface{}
+		mockClient := func() (*http.Client, *httptest.Server) {
+			server := httptest.NewServer(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					path = r.Method + " " + r.URL.Path
+					_ = json.NewDecoder(r.Body).Decode(&body)
+					_ = json.NewEncoder(w).Encode(linkResp)
+				}))
+			return server.Client(), server
+		}
+		client, server := newMockRzpClient(mockClient)
+		defer server.Close()
+
+		tool := CreateUpiAutopayRegistrationLink(
+			CreateTestObservability(), client)
+		result, err := tool.GetHandler()(context.Background(),
+			createMCPRequest(map[string]interface{}{
+				"customer_name":    "Gaurav Kumar",
+				"customer_email":   "gaurav.kumar@example.com",
+				"customer_contact": "9123456780",
+				"amount":           100,
+				"description":      "Monthly milk delivery",
+				"max_amount":       500000,
+				"frequency":        "monthly",
+				"expire_at":        1893456000,
+			}))
+		require.NoError(t, err)
+		require.False(t, result.IsError, result.Text)
+
+		assert.Equal(t, "POST /v1/subscription_registration/auth_links", path)
+		assert.Equal(t, map[string]interface{}{
+			"type":     "link",
+			"amount":   float64(100),
+			"currency": "INR",
+			"customer": map[string]interface{}{
+				"name":    "Gaurav Kumar",
+				"email":   "gaurav.kumar@example.com",
+				"contact": "9123456780",
+			},
+			"description": "Monthly milk delivery",
+			"subscription_registration": map[string]interface{}{
+				"method":     "upi",
+				"max_amount": float64(500000),
+				"frequency":  "monthly",
+				"expire_at":  float64(1893456000),
+			},
+		}, body)
+	})
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "missing customer contact",
+			Request: map[string]interface{}{
+				"customer_name":  "Gaurav Kumar",
+				"customer_email": "gaurav.kumar@example.com",
+				"amount":         100,
+				"description":    "Monthly milk delivery",
+				"max_amount":     500000,
+				"frequency":      "monthly",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: customer_contact",
+		},
+		{
+			Name: "registration link creation fails",
+			Request: map[string]interface{}{
+				"customer_name":    "Gaurav Kumar",
+				"customer_email":   "gaurav.kumar@example.com",
+				"customer_contact": "9123456780",
+				"amount":           100,
+				"description":      "Monthly milk delivery",
+				"max_amount":       500000,
+				"frequency":        "monthly",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:   registrationLinksPath,
+						Method: "POST",
+						Response: map[string]interface{}{
+							"error": map[string]interface{}{
+								"code":        "BAD_REQUEST_ERROR",
+								"description": "UPI Autopay is not enabled",
+							},
+						},
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "creating registration link failed: " +
+				"UPI Autopay is not enabled",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, CreateUpiAutopayRegistrationLink,
+				"Registration Link")
+		})
+	}
+}
+
+func Test_FetchCustomerTokens(t *testing.T) {
+	tokensPath := fmt.Sprintf("/%s%s/cust_00000000000001/tokens",
+		constants.VERSION_V1, constants.CUSTOMER_URL)
+
+	cardToken := map[string]interface{}{
+		"id":        "token_00000000000001",
+		"entity":    "token",
+		"method":    "card",
+		"recurring": false,
+	}
+	mandateToken := map[string]interface{}{
+		"id":        "token_00000000000002",
+		"entity":    "token",
+		"method":    "upi",
+		"recurring": true,
+		"recurring_details": map[string]interface{}{
+			"status": "confirmed",
+		},
+	}
+	tokensResp := map[string]interface{}{
+		"entity": "collection",
+		"count":  float64(2),
+		"items":  []interface{}{cardToken, mandateToken},
+	}
+
+	tokensClient := func() (*http.Client, *httptest.Server) {
+		return mock.NewHTTPClient(
+			mock.Endpoint{
+				Path:     tokensPath,
+				Method:   "GET",
+				Response: tokensResp,
+			},
+		)
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "all tokens",
+			Request: map[string]interface{}{
+				"customer_id": "cust_00000000000001",
+			},
+			MockHttpClient: tokensClient,
+			ExpectError:    false,
+			ExpectedResult: tokensResp,
+		},
+		{
+			Name: "recurring tokens only",
+			Request: map[string]interface{}{
+				"customer_id":    "cust_00000000000001",
+				"recurring_only": true,
+			},
+			MockHttpClient: tokensClient,
+			ExpectError:    false,
+			ExpectedResult: map[string]interface{}{
+				"entity": "collection",
+				"count":  float64(1),
+				"items":  []interface{}{mandateToken},
+			},
+		},
+		{
+			Name: "invalid customer id",
+			Request: map[string]interface{}{
+				"customer_id": "cust_1/../../payments",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid customer_id: cust_1/../../payments",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchCustomerTokens, "Tokens")
+		})
+	}
+}
+
+func Test_CreateRecurringPayment(t *testing.T) {
+	recurringPaymentPath := fmt.Sprintf("/%s%s/create/recurring",
+		constants.VERSION_V1, constants.PAYMENT_URL)
+
+	paymentResp := map[string]interface{}{
+		"razorpay_payment_id": "pay_00000000000001",
+		"razorpay_order_id":   "order_00000000000001",
+		"razorpay_signature":  "9ef4dffbfd84f1318f6739a3ce19f9d85851857ae648f114",
+	}
+
+	redirectResp := map[string]interface{}{
+		"razorpay_payment_id": "pay_00000000000001",
+		"next": []interface{}{
+			map[string]interface{}{
+				"action": "redirect",
+				"url": "https://api.razorpay.com/v1/payments/" +
+					"pay_00000000000001/authenticate",
+			},
+		},
+	}
+
+	fetchRecurringPaymentAction := map[string]interface{}{
+		"action": "call_tool",
+		"description": "Use 'fetch_payment' to check whether the " +
+			"recurring payment was captured.",
+		"tool": "fetch_payment",
+		"params": map[string]interface{}{
+			"payment_id": "pay_00000000000001",
+		},
+	}
+
+	request := map[string]interface{}{
+		"order_id":    "order_00000000000001",
+		"customer_id": "cust_00000000000001",
+		"token":       "token_00000000000002",
+		"amount":      50000,
+		"email":       "gaurav.kumar@example.com",
+		"contact":     "9123456780",
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name:    "successful recurring payment",
+			Request: request,
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     recurringPaymentPath,
+						Method:   "POST",
+						Response: paymentResp,
+					},
+				)
+			},
+			ExpectError: false,
+			ExpectedResult: map[string]interface{}{
+				"razorpay_payment_id": "pay_00000000000001",
+				"razorpay_order_id":   "order_00000000000001",
+				"razorpay_signature":  paymentResp["razorpay_signature"],
+				"next_actions":        []interface{}{fetchRecurringPaymentAction},
+				"next_step":           fetchRecurringPaymentAction["description"],
+				"next_tool":           "fetch_payment",
+				"next_tool_params": map[string]interface{}{
+					"payment_id": "pay_00000000000001",
+				},
+			},
+		},
+		{
+			Name:    "card payment the issuer asks to authenticate",
+			Request: request,
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     recurringPaymentPath,
+						Method:   "POST",
+						Response: redirectResp,
+					},
+				)
+			},
+			ExpectError: false,
+			ExpectedResult: map[string]interface{}{
+				"razorpay_payment_id": "pay_00000000000001",
+				"next":                redirectResp["next"],
+				"next_actions": []interface{}{
+					map[string]interface{}{
+						"action": "redirect",
+						"description": "Redirect the customer to this URL " +
+							"to complete authentication.",
+						"url": "https://api.razorpay.com/v1/payments/" +
+							"pay_00000000000001/authenticate",
+					},
+					fetchRecurringPaymentAction,
+				},
+				"next_step": fetchRecurringPaymentAction["description"],
+				"next_tool": "fetch_payment",
+				"next_tool_params": map[string]interface{}{
+					"payment_id": "pay_00000000000001",
+				},
+			},
+		},
+		{
+			Name: "missing token",
+			Request: map[string]interface{}{
+				"order_id":    "order_00000000000001",
+				"customer_id": "cust_00000000000001",
+				"amount":      50000,
+				"email":       "gaurav.kumar@example.com",
+				"contact":     "9123456780",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: token",
+		},
+		{
+			Name:    "amount over the mandate limit",
+			Request: request,
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:   recurringPaymentPath,
+						Method: "POST",
+						Response: map[string]interface{}{
+							"error": map[string]interface{}{
+								"code":        "BAD_REQUEST_ERROR",
+								"description": "Amount exceeds the max amount",
+							},
+						},
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "creating recurring payment failed: " +
+				"Amount exceeds the max amount",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, CreateRecurringPayment, "Recurring Payment")
+		})
+	}
+}
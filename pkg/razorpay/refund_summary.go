@@ -0,0 +1,155 @@
+package razorpay
+
+import (
+	"context"
+	"fmt"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+)
+
+// refundBucketBounds are the lower bounds of the amount buckets of refund
+// summaries, in currency subunits
+var refundBucketBounds = []int64{0, 10000, 100000, 1000000, 10000000}
+
+// refundBucket holds the refunds with an amount from Min up to, but not
+// including, Max. The last bucket has no Max.
+type refundBucket struct {
+	Min int64 `json:"min"`
+	Max int64 `json:"max,omitempty"`
+	summaryTotal
+}
+
+// refundSummary aggregates the refunds of a window
+type refundSummary struct {
+	From     int64  `json:"from"`
+	To       int64  `json:"to"`
+	Currency string `json:"currency"`
+	// OtherCurrencies counts the refunds in other currencies, which are
+	// left out of the summary
+	OtherCurrencies int                      `json:"other_currencies"`
+	Total           summaryTotal             `json:"total"`
+	ByStatus        map[string]*summaryTotal `json:"by_status"`
+	BySpeed         map[string]*summaryTotal `json:"by_speed_processed"`
+	ByAmount        []*refundBucket          `json:"by_amount"`
+	Truncated       bool                     `json:"truncated"`
+}
+
+// SummarizeRefunds returns a tool that aggregates the refunds of a window
+// by status, processing speed and amount
+func SummarizeRefunds(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := summaryWindowParameters("refunds")
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		window := make(map[string]interface{})
+		params := map[string]interface{}{"currency": "INR"}
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredInt(window, "from").
+			ValidateAndAddRequiredInt(window, "to").
+			ValidateAndAddOptionalString(params, "currency")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		from, to := window["from"].(int64), window["to"].(int64)
+		if result := checkSummaryWindow(from, to); result != nil {
+			return result, nil
+		}
+
+		// One refund more than a summary looks at tells whether any were
+		// left out
+		refunds, _, err := scanPages(window, maxSummaryRefunds+1,
+			maxSummaryRefunds+1, nil, client.Refund.All)
+		if err != nil {
+			return mcpgo.NewToolResultError(fmt.Sprintf(
+				"fetching refunds failed: %s", err.Error())), nil
+		}
+		truncated := len(refunds) > maxSummaryRefunds
+		if truncated {
+			refunds = refunds[:maxSummaryRefunds]
+		}
+
+		summary := summarizeRefunds(refunds, from, to,
+			params["currency"].(string))
+		summary.Truncated = truncated
+
+		return mcpgo.NewToolResultJSON(summary)
+	}
+
+	return mcpgo.NewTool(
+		"summarize_refunds",
+		fmt.Sprintf("Summarize the refunds created in a window of up to %d "+
+			"days, instead of fetching them: the number and amount of "+
+			"refunds in total, by status, by the speed they were processed "+
+			"at (normal or instant), and by amount bucket. Amounts are in "+
+			"currency subunits. At most %d refunds, the newest, are looked "+
+			"at, and truncated is set when more were created",
+			maxSummaryDays, maxSummaryRefunds),
+		parameters,
+		handler,
+	).WithOutputSchema(objectOutputSchema).
+		WithTimeout(scanToolTimeout)
+}
+
+// summarizeRefunds aggregates the refunds in the currency. Refunds that
+// are not processed yet have no speed_processed, so they count as unknown.
+func summarizeRefunds(
+	refunds []interface{},
+	from, to int64,
+	currency string,
+) *refundSummary {
+	summary := &refundSummary{
+		From:     from,
+		To:       to,
+		Currency: currency,
+		ByStatus: make(map[string]*summaryTotal),
+		BySpeed:  make(map[string]*summaryTotal),
+	}
+	for i, bound := range refundBucketBounds {
+		bucket := &refundBucket{Min: bound}
+		if i+1 < len(refundBucketBounds) {
+			bucket.Max = refundBucketBounds[i+1]
+		}
+		summary.ByAmount = append(summary.ByAmount, bucket)
+	}
+
+	for _, item := range refunds {
+		refund, _ := item.(map[string]interface{})
+		if stringField(refund, "currency") != currency {
+			summary.OtherCurrencies++
+			continue
+		}
+
+		amountValue, _ := refund["amount"].(float64)
+		amount := int64(amountValue)
+		summary.Total.add(amount)
+		addSummaryTotal(summary.ByStatus, stringField(refund, "status"),
+			amount)
+		addSummaryTotal(summary.BySpeed,
+			stringField(refund, "speed_processed"), amount)
+
+		for i := len(summary.ByAmount) - 1; i >= 0; i-- {
+			if amount >= summary.ByAmount[i].Min {
+				summary.ByAmount[i].add(amount)
+				break
+			}
+		}
+	}
+
+	return summary
+}
@@ -0,0 +1,170 @@
+package razorpay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/razorpay/razorpay-go/constants"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay/mock"
+)
+
+func Test_SummarizeRefunds(t *testing.T) {
+	refundsPath := fmt.Sprintf("/%s%s", constants.VERSION_V1,
+		constants.REFUND_URL)
+
+	// refund returns a refund in INR
+	refund := func(
+		status, speed string,
+		amount float64,
+	) map[string]interface{} {
+		refund := map[string]interface{}{
+			"id": "rfnd_" + status, "status": status, "amount": amount,
+			"currency": "INR", "created_at": float64(1705300000),
+		}
+		if speed != "" {
+			refund["speed_processed"] = speed
+		}
+		return refund
+	}
+
+	window := map[string]interface{}{
+		"from": float64(1705257000),
+		"to":   float64(1705429799),
+	}
+
+	t.Run("aggregates refunds by status, speed and amount",
+		func(t *testing.T) {
+			client, server := newMockRzpClient(func() (
+				*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{Path: refundsPath, Method: "GET",
+						Response: collectionOf(
+							refund("processed", "normal", 5000),
+							refund("processed", "instant", 50000),
+							refund("processed", "instant", 20000000),
+							refund("failed", "normal", 10000),
+							refund("pending", "", 150000),
+							map[string]interface{}{
+								"id": "rfnd_usd", "status": "processed",
+								"amount": float64(100), "currency": "USD",
+							},
+						)},
+				)
+			})
+			defer server.Close()
+
+			tool := SummarizeRefunds(CreateTestObservability(), client)
+			result, err := tool.GetHandler()(
+				context.Background(), createMCPRequest(window))
+			require.NoError(t, err)
+			require.False(t, result.IsError, result.Text)
+
+			var summary map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(result.Text), &summary))
+
+			assert.Equal(t, "INR", summary["currency"])
+			assert.Equal(t, float64(1), summary["other_currencies"])
+			assert.Equal(t, map[string]interface{}{
+				"count": float64(5), "amount": float64(20215000),
+			}, summary["total"])
+			assert.Equal(t, map[string]interface{}{
+				"processed": map[string]interface{}{
+					"count": float64(3), "amount": float64(20055000),
+				},
+				"failed": map[string]interface{}{
+					"count": float64(1), "amount": float64(10000),
+				},
+				"pending": map[string]interface{}{
+					"count": float64(1), "amount": float64(150000),
+				},
+			}, summary["by_status"])
+			assert.Equal(t, map[string]interface{}{
+				"normal": map[string]interface{}{
+					"count": float64(2), "amount": float64(15000),
+				},
+				"instant": map[string]interface{}{
+					"count": float64(2), "amount": float64(20050000),
+				},
+				"unknown": map[string]interface{}{
+					"count": float64(1), "amount": float64(150000),
+				},
+			}, summary["by_speed_processed"])
+			assert.Equal(t, []interface{}{
+				map[string]interface{}{
+					"min": float64(0), "max": float64(10000),
+					"count": float64(1), "amount": float64(5000),
+				},
+				map[string]interface{}{
+					"min": float64(10000), "max": float64(100000),
+					"count": float64(2), "amount": float64(60000),
+				},
+				map[string]interface{}{
+					"min": float64(100000), "max": float64(1000000),
+					"count": float64(1), "amount": float64(150000),
+				},
+				map[string]interface{}{
+					"min": float64(1000000), "max": float64(10000000),
+					"count": float64(0), "amount": float64(0),
+				},
+				map[string]interface{}{
+					"min":   float64(10000000),
+					"count": float64(1), "amount": float64(20000000),
+				},
+			}, summary["by_amount"])
+			assert.Equal(t, false, summary["truncated"])
+		})
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name:           "missing window",
+			Request:        map[string]interface{}{"to": float64(1705429799)},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: from",
+		},
+		{
+			Name: "window too long",
+			Request: map[string]interface{}{
+				"from": float64(1705257000),
+				"to":   float64(1705257000 + 32*24*60*60),
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "window must not span more than 31 days",
+		},
+		{
+			Name:    "api error",
+			Request: window,
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:   refundsPath,
+						Method: "GET",
+						Response: map[string]interface{}{
+							"error": map[string]interface{}{
+								"code":        "BAD_REQUEST_ERROR",
+								"description": "invalid request",
+							},
+						},
+					},
+				)
+			},
+			ExpectError:    true,
+			ExpectedErrMsg: "fetching refunds failed: invalid request",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, SummarizeRefunds, "Refund summary")
+		})
+	}
+}
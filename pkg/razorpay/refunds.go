@@ -3,6 +3,7 @@ package razorpay
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	rzpsdk "github.com/razorpay/razorpay-go"
 
@@ -71,6 +72,11 @@ func CreateRefund(
 			return result, err
 		}
 
+		if result := validateID("payment_id", payload["payment_id"].(string),
+			paymentIDPrefix); result != nil {
+			return result, nil
+		}
+
 		refund, err := client.Payment.Refund(
 			payload["payment_id"].(string),
 			int(payload["amount"].(float64)), data, nil)
@@ -89,7 +95,187 @@ func CreateRefund(
 			"(e.g., for ₹295, use 29500)",
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(refundOutputSchema).WithAmounts("amount")
+}
+
+// createBulkRefundsConcurrency bounds the number of refunds that are
+// created in parallel by the create_bulk_refunds tool
+const createBulkRefundsConcurrency = 5
+
+// CreateBulkRefunds returns a tool that refunds multiple payments in one
+// call. Refunds are created with bounded concurrency and the outcome of each
+// refund is reported individually, so one failed refund does not fail the
+// whole batch.
+func CreateBulkRefunds(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithArray(
+			"refunds",
+			mcpgo.Description("Array of refunds to create. Each refund "+
+				"accepts the same fields as create_refund: payment_id, "+
+				"amount (in the smallest currency unit), and optional "+
+				"speed, receipt and notes"),
+			mcpgo.Required(),
+			mcpgo.Min(1),
+			mcpgo.Max(50),
+			mcpgo.Items(map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"payment_id": map[string]interface{}{
+						"type":        "string",
+						"description": "ID of the payment to refund",
+						"pattern":     "^pay_",
+					},
+					"amount": map[string]interface{}{
+						"type":        "number",
+						"description": "Refund amount in currency subunits",
+						"minimum":     100,
+					},
+					"speed": map[string]interface{}{
+						"type":        "string",
+						"description": "Refund speed, normal or optimum",
+						"enum":        []interface{}{"normal", "optimum"},
+					},
+					"receipt": map[string]interface{}{
+						"type":        "string",
+						"description": "Receipt number for internal reference",
+					},
+					"notes": map[string]interface{}{
+						"type":        "object",
+						"description": "Key-value pairs for additional information",
+					},
+				},
+				"required": []interface{}{"payment_id", "amount"},
+			}),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		// Get client from context or use default
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		payload := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredArray(payload, "refunds")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		specs := payload["refunds"].([]interface{})
+		if len(specs) == 0 {
+			return mcpgo.NewToolResultError(
+				"refunds must contain at least one refund"), nil
+		}
+
+		results := make([]map[string]interface{}, len(specs))
+		sem := make(chan struct{}, createBulkRefundsConcurrency)
+		var wg sync.WaitGroup
+
+		for i, spec := range specs {
+			wg.Add(1)
+			go func(i int, spec interface{}) {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				results[i] = createBulkRefund(client, i, spec)
+			}(i, spec)
+		}
+		wg.Wait()
+
+		succeeded := 0
+		var amountRefunded float64
+		for _, result := range results {
+			if result["status"] == "created" {
+				succeeded++
+				amountRefunded += result["amount"].(float64)
+			}
+		}
+
+		return mcpgo.NewToolResultJSON(map[string]interface{}{
+			"count":           len(results),
+			"succeeded":       succeeded,
+			"failed":          len(results) - succeeded,
+			"amount_refunded": amountRefunded,
+			"results":         results,
+		})
+	}
+
+	return mcpgo.NewTool(
+		"create_bulk_refunds",
+		"Refund multiple payments in a single call. Each entry in "+
+			"'refunds' is validated and refunded independently; the response "+
+			"contains one result per entry (in the same order) with either "+
+			"the created refund_id or the error for that entry.",
+		parameters,
+		handler,
+	).WithOutputSchema(objectOutputSchema).
+		WithoutTimeout().
+		WithAmounts("refunds.amount")
+}
+
+// createBulkRefund validates a single refund spec from a batch and creates
+// the refund, returning the per-item result
+func createBulkRefund(
+	client *rzpsdk.Client,
+	index int,
+	spec interface{},
+) map[string]interface{} {
+	result := map[string]interface{}{
+		"index": index,
+	}
+
+	specReq := mcpgo.CallToolRequest{Arguments: spec}
+	payload := make(map[string]interface{})
+	data := make(map[string]interface{})
+
+	validator := NewValidator(&specReq).
+		ValidateAndAddRequiredString(payload, "payment_id").
+		ValidateAndAddRequiredFloat(payload, "amount").
+		ValidateAndAddOptionalString(data, "speed").
+		ValidateAndAddOptionalString(data, "receipt").
+		ValidateAndAddOptionalMap(data, "notes")
+
+	if paymentID, ok := payload["payment_id"].(string); ok {
+		result["payment_id"] = paymentID
+	}
+
+	if errResult, _ := validator.HandleErrorsIfAny(); errResult != nil {
+		result["status"] = "failed"
+		result["error"] = errResult.Text
+		return result
+	}
+	if errResult := validateID("payment_id", payload["payment_id"].(string),
+		paymentIDPrefix); errResult != nil {
+		result["status"] = "failed"
+		result["error"] = errResult.Text
+		return result
+	}
+
+	refund, err := client.Payment.Refund(
+		payload["payment_id"].(string),
+		int(payload["amount"].(float64)), data, nil)
+	if err != nil {
+		result["status"] = "failed"
+		result["error"] = fmt.Sprintf("creating refund failed: %s", err.Error())
+		return result
+	}
+
+	result["status"] = "created"
+	result["amount"] = payload["amount"]
+	result["refund_id"] = refund["id"]
+	result["refund"] = refund
+	return result
 }
 
 // FetchRefund returns a tool that fetches a refund by ID
@@ -126,6 +312,11 @@ func FetchRefund(
 			return result, err
 		}
 
+		if result := validateID("refund_id", payload["refund_id"].(string),
+			refundIDPrefix); result != nil {
+			return result, nil
+		}
+
 		refund, err := client.Refund.Fetch(payload["refund_id"].(string), nil, nil)
 		if err != nil {
 			return mcpgo.NewToolResultError(
@@ -140,7 +331,8 @@ func FetchRefund(
 		"Use this tool to retrieve the details of a specific refund using its id.",
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(refundOutputSchema).
+		WithFieldSelection()
 }
 
 // UpdateRefund returns a tool that updates a refund's notes
@@ -185,6 +377,11 @@ func UpdateRefund(
 			return result, err
 		}
 
+		if result := validateID("refund_id", payload["refund_id"].(string),
+			refundIDPrefix); result != nil {
+			return result, nil
+		}
+
 		refund, err := client.Refund.Update(payload["refund_id"].(string), data, nil)
 		if err != nil {
 			return mcpgo.NewToolResultError(
@@ -200,7 +397,7 @@ func UpdateRefund(
 			"Only the notes field can be modified.",
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(refundOutputSchema)
 }
 
 // FetchMultipleRefundsForPayment returns a tool that fetches multiple refunds
@@ -256,6 +453,11 @@ func FetchMultipleRefundsForPayment(
 			return result, err
 		}
 
+		if result := validateID("payment_id", fetchReq["payment_id"].(string),
+			paymentIDPrefix); result != nil {
+			return result, nil
+		}
+
 		refunds, err := client.Payment.FetchMultipleRefund(
 			fetchReq["payment_id"].(string), fetchOptions, nil)
 		if err != nil {
@@ -273,7 +475,8 @@ func FetchMultipleRefundsForPayment(
 			"By default, only the last 10 refunds are returned.",
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(refundsOutputSchema).
+		WithFieldSelection()
 }
 
 // FetchSpecificRefundForPayment returns a tool that fetches a specific refund
@@ -316,6 +519,16 @@ func FetchSpecificRefundForPayment(
 			return result, err
 		}
 
+		if result := validateID("payment_id", params["payment_id"].(string),
+			paymentIDPrefix); result != nil {
+			return result, nil
+		}
+
+		if result := validateID("refund_id", params["refund_id"].(string),
+			refundIDPrefix); result != nil {
+			return result, nil
+		}
+
 		refund, err := client.Payment.FetchRefund(
 			params["payment_id"].(string),
 			params["refund_id"].(string),
@@ -334,7 +547,8 @@ func FetchSpecificRefundForPayment(
 		"Use this tool to retrieve details of a specific refund made for a payment.",
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(refundOutputSchema).
+		WithFieldSelection()
 }
 
 // FetchAllRefunds returns a tool that fetches all refunds with pagination
@@ -343,7 +557,7 @@ func FetchAllRefunds(
 	obs *observability.Observability,
 	client *rzpsdk.Client,
 ) mcpgo.Tool {
-	parameters := []mcpgo.ToolParameter{
+	parameters := append([]mcpgo.ToolParameter{
 		mcpgo.WithNumber(
 			"from",
 			mcpgo.Description("Unix timestamp at which the refunds were created"),
@@ -361,7 +575,7 @@ func FetchAllRefunds(
 			"skip",
 			mcpgo.Description("The number of refunds to be skipped"),
 		),
-	}
+	}, collectionParameters()...)
 
 	handler := func(
 		ctx context.Context,
@@ -373,23 +587,25 @@ func FetchAllRefunds(
 		}
 
 		queryParams := make(map[string]interface{})
+		pagination := make(map[string]interface{})
 
 		validator := NewValidator(&r).
 			ValidateAndAddOptionalInt(queryParams, "from").
 			ValidateAndAddOptionalInt(queryParams, "to").
-			ValidateAndAddPagination(queryParams)
+			ValidateAndAddPagination(queryParams).
+			ValidateAndAddCollectionOptions(pagination)
 
 		if result, err := validator.HandleErrorsIfAny(); result != nil {
 			return result, err
 		}
 
-		refunds, err := client.Refund.All(queryParams, nil)
+		refunds, err := fetchCollection(queryParams, pagination, client.Refund.All)
 		if err != nil {
 			return mcpgo.NewToolResultError(
 				fmt.Sprintf("fetching refunds failed: %s", err.Error())), nil
 		}
 
-		return mcpgo.NewToolResultJSON(refunds)
+		return collectionResult(ctx, refunds, pagination)
 	}
 
 	return mcpgo.NewTool(
@@ -398,5 +614,82 @@ func FetchAllRefunds(
 			"By default, only the last 10 refunds are returned.",
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(refundsOutputSchema).
+		WithFieldSelection()
+}
+
+// WaitForRefundCompletion returns a tool that polls a refund until it is
+// processed or failed
+func WaitForRefundCompletion(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := append([]mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"refund_id",
+			mcpgo.Description("ID of the refund to wait for, starting with "+
+				"'rfnd_'"),
+			mcpgo.Required(),
+		),
+	}, pollingParameters()...)
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		params := make(map[string]interface{})
+		polling := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(params, "refund_id").
+			ValidateAndAddPolling(polling)
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		refundID := params["refund_id"].(string)
+
+		if result := validateID("refund_id", refundID,
+			refundIDPrefix); result != nil {
+			return result, nil
+		}
+
+		outcome, err := poll(ctx, polling,
+			func() (map[string]interface{}, error) {
+				return client.Refund.Fetch(refundID, nil, nil)
+			},
+			statusIn("processed", "failed"),
+		)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("waiting for refund failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(map[string]interface{}{
+			"refund_id": refundID,
+			"status":    stringField(outcome.entity, "status"),
+			"completed": outcome.done,
+			"polls":     outcome.polls,
+			"refund":    outcome.entity,
+		})
+	}
+
+	return mcpgo.NewTool(
+		"wait_for_refund_completion",
+		"Wait for a refund to be processed or to fail, checking its status "+
+			"every poll_interval seconds, and return its final state. "+
+			"completed is false when the timeout passed first, in which case "+
+			"the refund is still pending and can be waited for again",
+		parameters,
+		handler,
+	).WithOutputSchema(objectOutputSchema).
+		WithTimeout(pollToolTimeout).
+		// Each call must poll the live status of the refund
+		WithoutCache()
 }
@@ -141,6 +141,120 @@ func Test_CreateRefund(t *testing.T) {
 	}
 }
 
+func Test_CreateBulkRefunds(t *testing.T) {
+	createRefundPathFmt := fmt.Sprintf(
+		"/%s%s/%%s/refund",
+		constants.VERSION_V1,
+		constants.PAYMENT_URL,
+	)
+
+	refundResp := map[string]interface{}{
+		"id":         "rfnd_FP8QHiV938haTz",
+		"entity":     "refund",
+		"amount":     float64(5000),
+		"currency":   "INR",
+		"payment_id": "pay_29QQoUBi66xm2f",
+		"status":     "processed",
+	}
+
+	errorResp := map[string]interface{}{
+		"error": map[string]interface{}{
+			"code": "BAD_REQUEST_ERROR",
+			"description": "The refund amount provided is greater than " +
+				"amount captured",
+		},
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "partial success with per-refund results",
+			Request: map[string]interface{}{
+				"refunds": []interface{}{
+					map[string]interface{}{
+						"payment_id": "pay_29QQoUBi66xm2f",
+						"amount":     float64(5000),
+						"speed":      "optimum",
+						"notes":      map[string]interface{}{"ticket": "T-1"},
+					},
+					map[string]interface{}{
+						"payment_id": "pay_29QQoUBi66xm2g",
+						"amount":     float64(900000),
+					},
+					map[string]interface{}{
+						"amount": float64(5000),
+					},
+				},
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     fmt.Sprintf(createRefundPathFmt, "pay_29QQoUBi66xm2f"),
+						Method:   "POST",
+						Response: refundResp,
+					},
+					mock.Endpoint{
+						Path:     fmt.Sprintf(createRefundPathFmt, "pay_29QQoUBi66xm2g"),
+						Method:   "POST",
+						Response: errorResp,
+					},
+				)
+			},
+			ExpectError: false,
+			ExpectedResult: map[string]interface{}{
+				"count":           float64(3),
+				"succeeded":       float64(1),
+				"failed":          float64(2),
+				"amount_refunded": float64(5000),
+				"results": []interface{}{
+					map[string]interface{}{
+						"index":      float64(0),
+						"payment_id": "pay_29QQoUBi66xm2f",
+						"status":     "created",
+						"amount":     float64(5000),
+						"refund_id":  "rfnd_FP8QHiV938haTz",
+						"refund":     refundResp,
+					},
+					map[string]interface{}{
+						"index":      float64(1),
+						"payment_id": "pay_29QQoUBi66xm2g",
+						"status":     "failed",
+						"error": "creating refund failed: The refund amount " +
+							"provided is greater than amount captured",
+					},
+					map[string]interface{}{
+						"index":  float64(2),
+						"status": "failed",
+						"error": "Validation errors:\n- " +
+							"missing required parameter: payment_id",
+					},
+				},
+			},
+		},
+		{
+			Name: "empty refunds array",
+			Request: map[string]interface{}{
+				"refunds": []interface{}{},
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "refunds must contain at least one refund",
+		},
+		{
+			Name:           "missing refunds parameter",
+			Request:        map[string]interface{}{},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: refunds",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, CreateBulkRefunds, "Bulk Refunds")
+		})
+	}
+}
+
 func Test_FetchRefund(t *testing.T) {
 	fetchRefundPathFmt := fmt.Sprintf(
 		"/%s%s/%%s",
@@ -730,3 +844,101 @@ func Test_FetchAllRefunds(t *testing.T) {
 		})
 	}
 }
+
+func Test_WaitForRefundCompletion(t *testing.T) {
+	shortenPollUnit(t)
+
+	refundPath := fmt.Sprintf("/%s%s/%s", constants.VERSION_V1,
+		constants.REFUND_URL, "rfnd_DfjjhJC6eDvUAi")
+
+	refund := func(status string) map[string]interface{} {
+		return map[string]interface{}{
+			"id":         "rfnd_DfjjhJC6eDvUAi",
+			"entity":     "refund",
+			"amount":     float64(6000),
+			"payment_id": "pay_EpkFDYRirena0f",
+			"status":     status,
+		}
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "refund processed after polling",
+			Request: map[string]interface{}{
+				"refund_id":     "rfnd_DfjjhJC6eDvUAi",
+				"poll_interval": float64(1),
+			},
+			MockHttpClient: sequenceClient(refundPath, refund("pending"),
+				refund("pending"), refund("processed")),
+			ExpectError: false,
+			ExpectedResult: map[string]interface{}{
+				"refund_id": "rfnd_DfjjhJC6eDvUAi",
+				"status":    "processed",
+				"completed": true,
+				"polls":     float64(3),
+				"refund":    refund("processed"),
+			},
+		},
+		{
+			Name: "refund failed",
+			Request: map[string]interface{}{
+				"refund_id": "rfnd_DfjjhJC6eDvUAi",
+			},
+			MockHttpClient: sequenceClient(refundPath, refund("failed")),
+			ExpectError:    false,
+			ExpectedResult: map[string]interface{}{
+				"refund_id": "rfnd_DfjjhJC6eDvUAi",
+				"status":    "failed",
+				"completed": true,
+				"polls":     float64(1),
+				"refund":    refund("failed"),
+			},
+		},
+		{
+			Name: "refund not found",
+			Request: map[string]interface{}{
+				"refund_id": "rfnd_DfjjhJC6eDvUAi",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:   refundPath,
+						Method: "GET",
+						Response: map[string]interface{}{
+							"error": map[string]interface{}{
+								"code":        "BAD_REQUEST_ERROR",
+								"description": "The id provided does not exist",
+							},
+						},
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "waiting for refund failed: The id provided does " +
+				"not exist",
+		},
+		{
+			Name: "timeout above the maximum",
+			Request: map[string]interface{}{
+				"refund_id": "rfnd_DfjjhJC6eDvUAi",
+				"timeout":   float64(3600),
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "timeout must be between 1 and 300",
+		},
+		{
+			Name:           "missing refund_id",
+			Request:        map[string]interface{}{},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: refund_id",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, WaitForRefundCompletion, "Refund")
+		})
+	}
+}
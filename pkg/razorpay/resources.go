@@ -0,0 +1,78 @@
+package razorpay
+
+import (
+	"embed"
+	"fmt"
+	"path"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+	"github.com/razorpay/razorpay-mcp-server/pkg/toolsets"
+)
+
+// schemaFiles holds the JSON schemas of the Razorpay entities
+//
+//go:embed schemas/*.json
+var schemaFiles embed.FS
+
+// entitySchemas lists the entities whose schemas are exposed as resources
+var entitySchemas = []string{
+	"payment",
+	"order",
+	"refund",
+	"settlement",
+	"payment_link",
+	"customer",
+}
+
+// SchemaResourceURI returns the URI of the schema resource of an entity
+func SchemaResourceURI(entity string) string {
+	return "razorpay://schemas/" + entity
+}
+
+// ToolsetDocResourceURI returns the URI of the documentation resource of a
+// toolset
+func ToolsetDocResourceURI(toolset string) string {
+	return "razorpay://docs/toolsets/" + toolset
+}
+
+// NewResources creates the resources that ground the model with entity
+// field descriptions and with documentation of the enabled toolsets
+func NewResources(
+	toolsetGroup *toolsets.ToolsetGroup,
+) ([]mcpgo.Resource, error) {
+	var resources []mcpgo.Resource
+
+	for _, entity := range entitySchemas {
+		schema, err := schemaFiles.ReadFile(
+			path.Join("schemas", entity+".json"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s schema: %w", entity, err)
+		}
+
+		resources = append(resources, mcpgo.NewStaticResource(
+			SchemaResourceURI(entity),
+			entity+" schema",
+			fmt.Sprintf("JSON schema of the Razorpay %s entity, with "+
+				"descriptions of its fields", entity),
+			"application/schema+json",
+			string(schema),
+		))
+	}
+
+	for _, name := range toolsetGroup.EnabledToolsets() {
+		doc, err := toolsetGroup.Documentation(name)
+		if err != nil {
+			return nil, err
+		}
+
+		resources = append(resources, mcpgo.NewStaticResource(
+			ToolsetDocResourceURI(name),
+			name+" toolset",
+			fmt.Sprintf("Documentation of the %s toolset and its tools", name),
+			"text/markdown",
+			doc,
+		))
+	}
+
+	return resources, nil
+}
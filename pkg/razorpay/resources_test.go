@@ -0,0 +1,65 @@
+package razorpay
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+)
+
+func TestNewResources(t *testing.T) {
+	obs := CreateTestObservability()
+	client := &rzpsdk.Client{}
+
+	resourceURIs := func(resources []mcpgo.Resource) []string {
+		uris := make([]string, 0, len(resources))
+		for _, resource := range resources {
+			uris = append(uris, resource.GetURI())
+		}
+		return uris
+	}
+
+	t.Run("exposes entity schemas", func(t *testing.T) {
+		toolsetGroup, err := NewToolSets(obs, client, []string{}, false)
+		require.NoError(t, err)
+
+		resources, err := NewResources(toolsetGroup)
+		require.NoError(t, err)
+
+		uris := resourceURIs(resources)
+		for _, entity := range entitySchemas {
+			assert.Contains(t, uris, SchemaResourceURI(entity))
+		}
+	})
+
+	t.Run("embeds valid schemas", func(t *testing.T) {
+		for _, entity := range entitySchemas {
+			schema, err := schemaFiles.ReadFile("schemas/" + entity + ".json")
+			require.NoError(t, err)
+
+			var parsed map[string]interface{}
+			assert.NoError(t, json.Unmarshal(schema, &parsed), entity)
+			assert.Contains(t, parsed, "properties", entity)
+		}
+	})
+
+	t.Run("documents only enabled toolsets", func(t *testing.T) {
+		toolsetGroup, err := NewToolSets(
+			obs, client, []string{"payments", "orders"}, false)
+		require.NoError(t, err)
+
+		resources, err := NewResources(toolsetGroup)
+		require.NoError(t, err)
+
+		uris := resourceURIs(resources)
+		assert.Len(t, uris, len(entitySchemas)+2)
+		assert.Contains(t, uris, ToolsetDocResourceURI("payments"))
+		assert.Contains(t, uris, ToolsetDocResourceURI("orders"))
+		assert.NotContains(t, uris, ToolsetDocResourceURI("refunds"))
+	})
+}
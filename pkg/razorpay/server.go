@@ -3,10 +3,12 @@ package razorpay
 import (
 	"context"
 	"fmt"
+	"net/http"
 
 	rzpsdk "github.com/razorpay/razorpay-go"
 
 	"github.com/razorpay/razorpay-mcp-server/pkg/contextkey"
+	"github.com/razorpay/razorpay-mcp-server/pkg/events"
 	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
 	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
 )
@@ -15,6 +17,8 @@ func NewRzpMcpServer(
 	obs *observability.Observability,
 	client *rzpsdk.Client,
 	enabledToolsets []string,
+	enabledTools []string,
+	disabledTools []string,
 	readOnly bool,
 	mcpOpts ...mcpgo.ServerOption,
 ) (mcpgo.Server, error) {
@@ -31,36 +35,112 @@ func NewRzpMcpServer(
 		mcpgo.WithLogging(),
 		mcpgo.WithResourceCapabilities(true, true),
 		mcpgo.WithToolCapabilities(true),
+		mcpgo.WithPromptCapabilities(true),
 		mcpgo.WithHooks(mcpgo.SetupHooks(obs)),
+		mcpgo.WithToolCatalog(),
+		// Reject write tool calls at call time as well, in case a write
+		// tool is registered despite read-only mode
+		mcpgo.WithReadOnly(readOnly),
 	}
+	if obs.Metrics != nil {
+		defaultOpts = append(defaultOpts, mcpgo.WithMetrics(obs.Metrics))
+	}
+	if obs.Tracer != nil {
+		defaultOpts = append(defaultOpts, mcpgo.WithTracer(obs.Tracer))
+	}
+
+	// Enable the toolsets of the profiles of the config as well
+	config := configFromOptions(mcpOpts)
+	if len(config.Profiles) > 0 {
+		profileToolsets, err := profilesToolsets(config.Profiles)
+		if err != nil {
+			return nil, fmt.Errorf("invalid config: %w", err)
+		}
+		enabledToolsets = append(append([]string{}, enabledToolsets...),
+			profileToolsets...)
+	}
+
+	// Create the Razorpay toolsets
+	toolsets, err := NewToolSets(obs, client, enabledToolsets, readOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create toolsets: %w", err)
+	}
+
+	// Apply the toolset and tool settings of the config
+	configOpts, err := config.apply(toolsets, client)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	if err := toolsets.EnableTools(enabledTools); err != nil {
+		return nil, fmt.Errorf("failed to enable tools: %w", err)
+	}
+	if err := toolsets.DisableTools(disabledTools); err != nil {
+		return nil, fmt.Errorf("failed to disable tools: %w", err)
+	}
+
+	// Deliver the changes of the entities sessions subscribe to, through
+	// the server once it is created
+	notifier := &eventNotifier{}
+	bus := events.NewBus(eventPollInterval, notifier.notify)
+	defaultOpts = append(defaultOpts, mcpgo.WithCallContext(
+		func(ctx context.Context) context.Context {
+			return contextkey.WithEventBus(ctx, bus)
+		}))
+
+	// Let the tools read the webhook events the server receives, and
+	// deliver the changes in them to the subscribed sessions
+	if history := eventHistoryFromOptions(mcpOpts); history != nil {
+		history.Listen(func(event events.WebhookEvent) {
+			for _, change := range event.Changes() {
+				bus.Publish(change)
+			}
+		})
+		defaultOpts = append(defaultOpts, mcpgo.WithCallContext(
+			func(ctx context.Context) context.Context {
+				return contextkey.WithEventHistory(ctx, history)
+			}))
+	}
+
 	// Merge with user-provided options
-	mcpOpts = append(defaultOpts, mcpOpts...)
+	mcpOpts = append(append(defaultOpts, configOpts...), mcpOpts...)
 
 	// Create server
 	server := mcpgo.NewMcpServer("razorpay-mcp-server", "1.0.0", mcpOpts...)
+	notifier.server = server
 
 	// Register Razorpay tools
-	toolsets, err := NewToolSets(obs, client, enabledToolsets, readOnly)
+	toolsets.RegisterTools(server)
+
+	// Register resources describing entities and the enabled toolsets
+	resources, err := NewResources(toolsets)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create toolsets: %w", err)
+		return nil, fmt.Errorf("failed to create resources: %w", err)
 	}
-	toolsets.RegisterTools(server)
+	server.AddResources(resources...)
+
+	// Register prompts for the workflows the registered tools support
+	server.AddPrompts(NewPrompts(toolsets)...)
 
 	return server, nil
 }
 
-// getClientFromContextOrDefault returns either the provided default
-// client or gets one from context.
+// getClientFromContextOrDefault returns the client carried by the request
+// context, such as one built from per-request credentials, and falls back
+// to the provided default client. The client sends its requests with ctx,
+// so that they are aborted when the tool call is cancelled or times out,
+// on behalf of the sub-merchant account of the call if it selects one, and
+// traces them as part of the call if the call is traced. The errors of its
+// requests are recorded as the structured error of the call.
 func getClientFromContextOrDefault(
 	ctx context.Context,
 	defaultClient *rzpsdk.Client,
 ) (*rzpsdk.Client, error) {
-	if defaultClient != nil {
-		return defaultClient, nil
-	}
-
 	clientInterface := contextkey.ClientFromContext(ctx)
 	if clientInterface == nil {
+		if defaultClient != nil {
+			return callClient(ctx, defaultClient), nil
+		}
 		return nil, fmt.Errorf("no client found in context")
 	}
 
@@ -69,5 +149,39 @@ func getClientFromContextOrDefault(
 		return nil, fmt.Errorf("invalid client type in context")
 	}
 
-	return client, nil
+	return callClient(ctx, client), nil
+}
+
+// callClient returns a copy of the client set up for the tool call of ctx
+func callClient(ctx context.Context, client *rzpsdk.Client) *rzpsdk.Client {
+	return withDryRunOf(ctx, withTracing(ctx, withCancellation(ctx,
+		withAPIErrors(ctx, withMerchantAccount(ctx, client)))))
+}
+
+// copyClient returns a copy of the client whose Request can be changed
+// without changing the client. The API resources of a new client share
+// its Request, so replacing it keeps the settings of the original client.
+func copyClient(client *rzpsdk.Client) *rzpsdk.Client {
+	copied := rzpsdk.NewClient("", "")
+	*copied.Request = *client.Request
+	return copied
+}
+
+// withTransport returns a copy of the client whose requests are sent
+// through the transport wrap returns for the transport of the client, nil
+// for the default transport
+func withTransport(
+	client *rzpsdk.Client,
+	wrap func(next http.RoundTripper) http.RoundTripper,
+) *rzpsdk.Client {
+	httpClient := &http.Client{}
+	if client.Request.HTTPClient != nil {
+		clone := *client.Request.HTTPClient
+		httpClient = &clone
+	}
+	httpClient.Transport = wrap(httpClient.Transport)
+
+	copied := copyClient(client)
+	copied.Request.HTTPClient = httpClient
+	return copied
 }
@@ -2,7 +2,9 @@ package razorpay
 
 import (
 	"context"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -16,7 +18,7 @@ func TestNewRzpMcpServer(t *testing.T) {
 		obs := CreateTestObservability()
 		client := rzpsdk.NewClient("test-key", "test-secret")
 
-		server, err := NewRzpMcpServer(obs, client, []string{}, false)
+		server, err := NewRzpMcpServer(obs, client, []string{}, nil, nil, false)
 		assert.NoError(t, err)
 		assert.NotNil(t, server)
 	})
@@ -24,7 +26,7 @@ func TestNewRzpMcpServer(t *testing.T) {
 	t.Run("returns error with nil observability", func(t *testing.T) {
 		client := rzpsdk.NewClient("test-key", "test-secret")
 
-		server, err := NewRzpMcpServer(nil, client, []string{}, false)
+		server, err := NewRzpMcpServer(nil, client, []string{}, nil, nil, false)
 		assert.Error(t, err)
 		assert.Nil(t, server)
 		assert.Contains(t, err.Error(), "observability is required")
@@ -33,7 +35,7 @@ func TestNewRzpMcpServer(t *testing.T) {
 	t.Run("returns error with nil client", func(t *testing.T) {
 		obs := CreateTestObservability()
 
-		server, err := NewRzpMcpServer(obs, nil, []string{}, false)
+		server, err := NewRzpMcpServer(obs, nil, []string{}, nil, nil, false)
 		assert.Error(t, err)
 		assert.Nil(t, server)
 		assert.Contains(t, err.Error(), "razorpay client is required")
@@ -44,16 +46,50 @@ func TestNewRzpMcpServer(t *testing.T) {
 		client := rzpsdk.NewClient("test-key", "test-secret")
 
 		server, err := NewRzpMcpServer(
-			obs, client, []string{"payments", "orders"}, false)
+			obs, client, []string{"payments", "orders"}, nil, nil, false)
 		assert.NoError(t, err)
 		assert.NotNil(t, server)
 	})
 
+	t.Run("creates server with enabled and disabled tools",
+		func(t *testing.T) {
+			obs := CreateTestObservability()
+			client := rzpsdk.NewClient("test-key", "test-secret")
+
+			server, err := NewRzpMcpServer(obs, client, []string{},
+				[]string{"fetch_payment", "create_payment_link"},
+				[]string{"create_payment_link"}, false)
+			assert.NoError(t, err)
+			assert.NotNil(t, server)
+		})
+
+	t.Run("returns error for unknown enabled tool", func(t *testing.T) {
+		obs := CreateTestObservability()
+		client := rzpsdk.NewClient("test-key", "test-secret")
+
+		server, err := NewRzpMcpServer(obs, client, []string{},
+			[]string{"fetch_everything"}, nil, false)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "tool fetch_everything does not exist")
+		assert.Nil(t, server)
+	})
+
+	t.Run("returns error for unknown disabled tool", func(t *testing.T) {
+		obs := CreateTestObservability()
+		client := rzpsdk.NewClient("test-key", "test-secret")
+
+		server, err := NewRzpMcpServer(obs, client, []string{},
+			nil, []string{"fetch_everything"}, false)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to disable tools")
+		assert.Nil(t, server)
+	})
+
 	t.Run("creates server in read-only mode", func(t *testing.T) {
 		obs := CreateTestObservability()
 		client := rzpsdk.NewClient("test-key", "test-secret")
 
-		server, err := NewRzpMcpServer(obs, client, []string{}, true)
+		server, err := NewRzpMcpServer(obs, client, []string{}, nil, nil, true)
 		assert.NoError(t, err)
 		assert.NotNil(t, server)
 	})
@@ -62,7 +98,7 @@ func TestNewRzpMcpServer(t *testing.T) {
 		obs := CreateTestObservability()
 		client := rzpsdk.NewClient("test-key", "test-secret")
 
-		server, err := NewRzpMcpServer(obs, client, []string{}, false)
+		server, err := NewRzpMcpServer(obs, client, []string{}, nil, nil, false)
 		assert.NoError(t, err)
 		assert.NotNil(t, server)
 	})
@@ -109,7 +145,7 @@ func TestGetClientFromContextOrDefault(t *testing.T) {
 			assert.Contains(t, err.Error(), "invalid client type in context")
 		})
 
-	t.Run("prefers default client over context client", func(t *testing.T) {
+	t.Run("prefers context client over default client", func(t *testing.T) {
 		ctx := context.Background()
 		defaultClient := rzpsdk.NewClient("default-key", "default-secret")
 		contextClient := rzpsdk.NewClient("context-key", "context-secret")
@@ -117,7 +153,35 @@ func TestGetClientFromContextOrDefault(t *testing.T) {
 
 		result, err := getClientFromContextOrDefault(ctx, defaultClient)
 		assert.NoError(t, err)
-		assert.Equal(t, defaultClient, result)
-		assert.NotEqual(t, contextClient, result)
+		assert.Equal(t, contextClient, result)
+		assert.NotEqual(t, defaultClient, result)
 	})
 }
+
+func TestWithTransport(t *testing.T) {
+	transport := &http.Transport{}
+	client := rzpsdk.NewClient("test-key", "test-secret")
+	client.Request.HTTPClient = &http.Client{
+		Timeout:   time.Minute,
+		Transport: transport,
+	}
+	original := client.Request.HTTPClient
+
+	var wrapped http.RoundTripper
+	copied := withTransport(client, func(
+		next http.RoundTripper,
+	) http.RoundTripper {
+		wrapped = next
+		return &contextTransport{ctx: context.Background(), next: next}
+	})
+
+	assert.Same(t, transport, wrapped)
+	assert.IsType(t, &contextTransport{}, copied.Request.HTTPClient.Transport)
+	assert.Equal(t, time.Minute, copied.Request.HTTPClient.Timeout)
+	assert.Equal(t, "test-key", copied.Request.Auth.Key)
+	// The API resources of the copy use its Request
+	assert.Same(t, copied.Request, copied.Payment.Request)
+	// The original client keeps its transport
+	assert.Same(t, original, client.Request.HTTPClient)
+	assert.Same(t, transport, client.Request.HTTPClient.Transport)
+}
@@ -0,0 +1,281 @@
+package razorpay
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+)
+
+// Limits and defaults of settlement forecasts
+const (
+	// defaultForecastLookbackDays is how many days back a forecast looks
+	// for unsettled payments by default
+	defaultForecastLookbackDays = 7
+	// maxForecastLookbackDays is the furthest back a forecast may look
+	maxForecastLookbackDays = 31
+	// defaultSettlementDays is the settlement cycle of payments, in working
+	// days after their capture, by default
+	defaultSettlementDays = 2
+	// maxSettlementDays is the longest settlement cycle a forecast takes
+	maxSettlementDays = 30
+	// maxForecastRecords is the most payments, and settlement recon
+	// entries, a forecast looks at, each
+	maxForecastRecords = 2000
+)
+
+// forecastTotal is the number, amount, fees and tax of unsettled payments,
+// and the net amount that is expected to settle for them
+type forecastTotal struct {
+	Count  int   `json:"count"`
+	Amount int64 `json:"amount"`
+	Fee    int64 `json:"fee"`
+	Tax    int64 `json:"tax"`
+	Net    int64 `json:"net"`
+}
+
+// add counts an unsettled payment
+func (t *forecastTotal) add(amount, fee, tax int64) {
+	t.Count++
+	t.Amount += amount
+	t.Fee += fee
+	t.Tax += tax
+	t.Net += amount - fee
+}
+
+// forecastDay is the expected settlement of a day, in IST
+type forecastDay struct {
+	Date string `json:"date"`
+	forecastTotal
+}
+
+// settlementForecast is the expected settlement of the captured payments
+// that are not settled yet
+type settlementForecast struct {
+	From           int64 `json:"from"`
+	To             int64 `json:"to"`
+	SettlementDays int   `json:"settlement_days"`
+	// OtherCurrencies counts the unsettled payments in currencies other
+	// than INR, which are left out of the forecast
+	OtherCurrencies int            `json:"other_currencies"`
+	Total           forecastTotal  `json:"total"`
+	Days            []*forecastDay `json:"days"`
+	// Overdue holds the payments that were expected to settle before
+	// today, and are not settled yet
+	Overdue   forecastTotal `json:"overdue"`
+	Truncated bool          `json:"truncated"`
+}
+
+// ForecastSettlements returns a tool that estimates the settlements of the
+// coming days from the captured payments that are not settled yet
+func ForecastSettlements(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithNumber(
+			"lookback_days",
+			mcpgo.Description(fmt.Sprintf("How many days back to look for "+
+				"captured payments that are not settled yet (default: %d)",
+				defaultForecastLookbackDays)),
+			mcpgo.Min(1),
+			mcpgo.Max(maxForecastLookbackDays),
+		),
+		mcpgo.WithNumber(
+			"settlement_days",
+			mcpgo.Description(fmt.Sprintf("Settlement cycle of the account, "+
+				"in working days after capture, such as 2 for T+2 "+
+				"(default: %d)", defaultSettlementDays)),
+			mcpgo.Min(0),
+			mcpgo.Max(maxSettlementDays),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		params := map[string]interface{}{
+			"lookback_days":   int64(defaultForecastLookbackDays),
+			"settlement_days": int64(defaultSettlementDays),
+		}
+
+		validator := NewValidator(&r).
+			ValidateAndAddOptionalInt(params, "lookback_days").
+			ValidateAndAddOptionalInt(params, "settlement_days")
+
+		lookbackDays := params["lookback_days"].(int64)
+		if lookbackDays < 1 || lookbackDays > maxForecastLookbackDays {
+			validator.addError(fmt.Errorf(
+				"lookback_days must be between 1 and %d",
+				maxForecastLookbackDays))
+		}
+		settlementDays := params["settlement_days"].(int64)
+		if settlementDays < 0 || settlementDays > maxSettlementDays {
+			validator.addError(fmt.Errorf(
+				"settlement_days must be between 0 and %d",
+				maxSettlementDays))
+		}
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		current := now()
+		today := startOfDay(current)
+		first := today.AddDate(0, 0, -int(lookbackDays))
+		from, to := first.Unix(), current.Unix()
+
+		truncated := false
+		payments, _, err := scanPages(map[string]interface{}{
+			"from": from,
+			"to":   to,
+		}, maxForecastRecords+1, maxForecastRecords+1, nil,
+			client.Payment.All)
+		if err != nil {
+			return mcpgo.NewToolResultError(fmt.Sprintf(
+				"fetching payments failed: %s", err.Error())), nil
+		}
+		if len(payments) > maxForecastRecords {
+			truncated = true
+			payments = payments[:maxForecastRecords]
+		}
+
+		// Payments captured in the window settle on its days or later, so
+		// the recon reports of the days up to today list the settled ones
+		var settlementEntries []interface{}
+		for day := first; !day.After(today); day = day.AddDate(0, 0, 1) {
+			items, _, err := scanPages(map[string]interface{}{
+				"year":  day.Year(),
+				"month": int(day.Month()),
+				"day":   day.Day(),
+			}, maxForecastRecords+1, maxForecastRecords+1, nil,
+				client.Settlement.Reports)
+			if err != nil {
+				return mcpgo.NewToolResultError(fmt.Sprintf(
+					"fetching settlement reconciliation report failed: %s",
+					err.Error())), nil
+			}
+			settlementEntries = append(settlementEntries, items...)
+			if len(settlementEntries) > maxForecastRecords {
+				truncated = true
+				settlementEntries = settlementEntries[:maxForecastRecords]
+				break
+			}
+		}
+
+		forecast := forecastSettlements(payments, settlementEntries, today,
+			int(settlementDays))
+		forecast.From, forecast.To = from, to
+		forecast.Truncated = truncated
+
+		return mcpgo.NewToolResultJSON(forecast)
+	}
+
+	return mcpgo.NewTool(
+		"forecast_settlements",
+		fmt.Sprintf("Estimate the settlements of the coming days from the "+
+			"captured INR payments of the last days that are not in a "+
+			"settlement reconciliation report yet. Each payment is expected "+
+			"to settle its amount less its fee, which includes tax, "+
+			"settlement_days working days after it was made. Returns the "+
+			"expected settlement per day in IST, and the overdue payments "+
+			"that should have settled before today. Weekends are skipped, "+
+			"bank holidays, refunds and adjustments are not accounted for. "+
+			"Amounts are in paise. At most %d payments and settlement "+
+			"entries are looked at", maxForecastRecords),
+		parameters,
+		handler,
+	).WithOutputSchema(objectOutputSchema).
+		WithTimeout(scanToolTimeout)
+}
+
+// forecastSettlements joins the captured payments with the settlement
+// recon entries, and sums the payments that are not settled by the day
+// they are expected to settle on. Payments are taken as captured when
+// created, as the payment entity has no capture time.
+func forecastSettlements(
+	payments, settlementEntries []interface{},
+	today time.Time,
+	settlementDays int,
+) *settlementForecast {
+	settled := make(map[string]bool)
+	for _, item := range settlementEntries {
+		entry, _ := item.(map[string]interface{})
+		if stringField(entry, "type") == "payment" {
+			settled[stringField(entry, "entity_id")] = true
+		}
+	}
+
+	forecast := &settlementForecast{
+		SettlementDays: settlementDays,
+		Days:           []*forecastDay{},
+	}
+	days := make(map[string]*forecastDay)
+	for _, item := range payments {
+		payment, _ := item.(map[string]interface{})
+		if stringField(payment, "status") != "captured" ||
+			settled[stringField(payment, "id")] {
+			continue
+		}
+		if stringField(payment, "currency") != "INR" {
+			forecast.OtherCurrencies++
+			continue
+		}
+
+		amount, _ := payment["amount"].(float64)
+		fee, _ := payment["fee"].(float64)
+		tax, _ := payment["tax"].(float64)
+		createdAt, _ := payment["created_at"].(float64)
+
+		forecast.Total.add(int64(amount), int64(fee), int64(tax))
+		expected := addWorkingDays(
+			startOfDay(time.Unix(int64(createdAt), 0)), settlementDays)
+		if expected.Before(today) {
+			forecast.Overdue.add(int64(amount), int64(fee), int64(tax))
+			continue
+		}
+
+		date := expected.Format(time.DateOnly)
+		if days[date] == nil {
+			days[date] = &forecastDay{Date: date}
+			forecast.Days = append(forecast.Days, days[date])
+		}
+		days[date].add(int64(amount), int64(fee), int64(tax))
+	}
+
+	sort.Slice(forecast.Days, func(i, j int) bool {
+		return forecast.Days[i].Date < forecast.Days[j].Date
+	})
+
+	return forecast
+}
+
+// startOfDay returns the start of the day of the time, in IST
+func startOfDay(t time.Time) time.Time {
+	t = t.In(settlementLocation)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0,
+		settlementLocation)
+}
+
+// addWorkingDays returns the day the number of working days after the
+// day, skipping weekends
+func addWorkingDays(day time.Time, days int) time.Time {
+	for days > 0 {
+		day = day.AddDate(0, 0, 1)
+		if day.Weekday() != time.Saturday && day.Weekday() != time.Sunday {
+			days--
+		}
+	}
+	return day
+}
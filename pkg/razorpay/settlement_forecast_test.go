@@ -0,0 +1,169 @@
+package razorpay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/razorpay/razorpay-go/constants"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay/mock"
+)
+
+func Test_ForecastSettlements(t *testing.T) {
+	paymentsPath := fmt.Sprintf("/%s%s", constants.VERSION_V1,
+		constants.PAYMENT_URL)
+	reconPath := fmt.Sprintf("/%s%s/recon/combined", constants.VERSION_V1,
+		constants.SETTLEMENT_URL)
+
+	origNow := now
+	defer func() { now = origNow }()
+	// Wednesday, 2024-01-17 12:00 IST
+	now = func() time.Time {
+		return time.Date(2024, 1, 17, 6, 30, 0, 0, time.UTC)
+	}
+
+	// payment returns a captured INR payment made at the time
+	payment := func(
+		id string,
+		amount, fee, tax, createdAt float64,
+	) map[string]interface{} {
+		return map[string]interface{}{
+			"id": id, "status": "captured", "amount": amount,
+			"currency": "INR", "fee": fee, "tax": tax,
+			"created_at": createdAt,
+		}
+	}
+
+	t.Run("forecasts unsettled payments by day", func(t *testing.T) {
+		client, server := newMockRzpClient(func() (
+			*http.Client, *httptest.Server) {
+			return mock.NewHTTPClient(
+				mock.Endpoint{Path: paymentsPath, Method: "GET",
+					Response: collectionOf(
+						// Friday, settles on Tuesday, before today
+						payment("pay_overdue", 5000, 118, 18, 1705033800),
+						// Monday
+						payment("pay_settled", 7000, 165, 25, 1705293000),
+						payment("pay_monday", 3000, 70, 10, 1705293000),
+						// Tuesday
+						payment("pay_tuesday", 10000, 236, 36, 1705379400),
+						// Wednesday
+						payment("pay_today", 20000, 472, 72, 1705465800),
+						map[string]interface{}{
+							"id": "pay_failed", "status": "failed",
+							"amount": float64(9000), "currency": "INR",
+						},
+						map[string]interface{}{
+							"id": "pay_usd", "status": "captured",
+							"amount": float64(100), "currency": "USD",
+						},
+					)},
+				mock.Endpoint{Path: reconPath, Method: "GET",
+					Response: collectionOf(map[string]interface{}{
+						"entity_id": "pay_settled", "type": "payment",
+					})},
+			)
+		})
+		defer server.Close()
+
+		tool := ForecastSettlements(CreateTestObservability(), client)
+		result, err := tool.GetHandler()(context.Background(),
+			createMCPRequest(map[string]interface{}{
+				"lookback_days": float64(5),
+			}))
+		require.NoError(t, err)
+		require.False(t, result.IsError, result.Text)
+
+		var forecast map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(result.Text), &forecast))
+
+		assert.Equal(t, float64(1704997800), forecast["from"])
+		assert.Equal(t, float64(1705473000), forecast["to"])
+		assert.Equal(t, float64(2), forecast["settlement_days"])
+		assert.Equal(t, float64(1), forecast["other_currencies"])
+		assert.Equal(t, map[string]interface{}{
+			"count": float64(4), "amount": float64(38000),
+			"fee": float64(896), "tax": float64(136), "net": float64(37104),
+		}, forecast["total"])
+		assert.Equal(t, map[string]interface{}{
+			"count": float64(1), "amount": float64(5000),
+			"fee": float64(118), "tax": float64(18), "net": float64(4882),
+		}, forecast["overdue"])
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{
+				"date": "2024-01-17", "count": float64(1),
+				"amount": float64(3000), "fee": float64(70),
+				"tax": float64(10), "net": float64(2930),
+			},
+			map[string]interface{}{
+				"date": "2024-01-18", "count": float64(1),
+				"amount": float64(10000), "fee": float64(236),
+				"tax": float64(36), "net": float64(9764),
+			},
+			map[string]interface{}{
+				"date": "2024-01-19", "count": float64(1),
+				"amount": float64(20000), "fee": float64(472),
+				"tax": float64(72), "net": float64(19528),
+			},
+		}, forecast["days"])
+		assert.Equal(t, false, forecast["truncated"])
+	})
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "lookback too long",
+			Request: map[string]interface{}{
+				"lookback_days": float64(40),
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "lookback_days must be between 1 and 31",
+		},
+		{
+			Name: "negative settlement days",
+			Request: map[string]interface{}{
+				"settlement_days": float64(-1),
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "settlement_days must be between 0 and 30",
+		},
+		{
+			Name:    "settlement recon error",
+			Request: map[string]interface{}{},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{Path: paymentsPath, Method: "GET",
+						Response: collectionOf()},
+					mock.Endpoint{
+						Path:   reconPath,
+						Method: "GET",
+						Response: map[string]interface{}{
+							"error": map[string]interface{}{
+								"code":        "BAD_REQUEST_ERROR",
+								"description": "invalid request",
+							},
+						},
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "fetching settlement reconciliation report " +
+				"failed: invalid request",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, ForecastSettlements, "Settlement forecast")
+		})
+	}
+}
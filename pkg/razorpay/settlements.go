@@ -1,8 +1,10 @@
 package razorpay
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"time"
 
 	rzpsdk "github.com/razorpay/razorpay-go"
 
@@ -45,6 +47,12 @@ func FetchSettlement(
 		}
 
 		settlementID := fetchSettlementOptions["settlement_id"].(string)
+
+		if result := validateID("settlement_id", settlementID,
+			settlementIDPrefix); result != nil {
+			return result, nil
+		}
+
 		settlement, err := client.Settlement.Fetch(settlementID, nil, nil)
 		if err != nil {
 			return mcpgo.NewToolResultError(
@@ -59,7 +67,8 @@ func FetchSettlement(
 		"Fetch details of a specific settlement using its ID",
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(settlementOutputSchema).
+		WithFieldSelection()
 }
 
 // FetchSettlementRecon returns a tool that fetches settlement
@@ -135,7 +144,190 @@ func FetchSettlementRecon(
 		"Fetch settlement reconciliation report for a specific time period",
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(objectOutputSchema).
+		WithFieldSelection()
+}
+
+// Limits of settlement recon exports
+const (
+	// maxReconExportDays is the longest date range an export may span
+	maxReconExportDays = 31
+	// maxReconExportRows is the most transactions an export holds
+	maxReconExportRows = 10000
+)
+
+// reconCSVColumns are the leading columns of a settlement recon export, in
+// the order of the reports on the dashboard
+var reconCSVColumns = []string{
+	"entity_id", "type", "debit", "credit", "amount", "currency", "fee",
+	"tax", "on_hold", "settled", "created_at", "settled_at",
+	"settlement_id", "settlement_utr", "description", "notes",
+	"payment_id", "order_id", "order_receipt", "method", "card_network",
+	"card_issuer", "card_type", "dispute_id",
+}
+
+// ExportSettlementReconCSV returns a tool that exports the settlement recon
+// report of a date range as CSV
+func ExportSettlementReconCSV(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"from_date",
+			mcpgo.Description("First day of the report in YYYY-MM-DD format"),
+			mcpgo.Required(),
+		),
+		mcpgo.WithString(
+			"to_date",
+			mcpgo.Description(fmt.Sprintf("Optional: Last day of the report "+
+				"in YYYY-MM-DD format, at most %d days after from_date "+
+				"(default: from_date)", maxReconExportDays-1)),
+		),
+		mcpgo.WithString(
+			"output_file",
+			mcpgo.Description("Optional relative path of a file to write the "+
+				"CSV to. When set, only a summary is returned instead of the "+
+				"CSV"),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		options := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(options, "from_date").
+			ValidateAndAddOptionalString(options, "to_date").
+			ValidateAndAddOptionalString(options, "output_file")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		fromDate := options["from_date"].(string)
+		toDate, ok := options["to_date"].(string)
+		if !ok {
+			toDate = fromDate
+		}
+		from, to, err := parseReconDateRange(fromDate, toDate)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		var outputPath string
+		if outputFile, ok := options["output_file"].(string); ok {
+			outputPath, err = resolveExportPath(outputFile)
+			if err != nil {
+				return mcpgo.NewToolResultError(err.Error()), nil
+			}
+		}
+
+		// One more row than the limit is fetched to tell whether any were
+		// left out
+		records := make([]interface{}, 0)
+		for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+			dayParams := map[string]interface{}{
+				"year":  day.Year(),
+				"month": int(day.Month()),
+				"day":   day.Day(),
+			}
+			remaining := int64(maxReconExportRows + 1 - len(records))
+			items, _, err := scanPages(dayParams, remaining, remaining, nil,
+				client.Settlement.Reports)
+			if err != nil {
+				return mcpgo.NewToolResultError(
+					fmt.Sprintf("fetching settlement reconciliation report "+
+						"failed: %s", err.Error())), nil
+			}
+			records = append(records, items...)
+			if len(records) > maxReconExportRows {
+				break
+			}
+		}
+
+		truncated := len(records) > maxReconExportRows
+		if truncated {
+			records = records[:maxReconExportRows]
+		}
+		columns := csvColumns(records, reconCSVColumns)
+
+		summary := map[string]interface{}{
+			"from_date": fromDate,
+			"to_date":   toDate,
+			"format":    "csv",
+			"rows":      len(records),
+			"truncated": truncated,
+		}
+
+		if outputPath == "" {
+			var buf bytes.Buffer
+			if err := encodeCSV(&buf, columns, records); err != nil {
+				return mcpgo.NewToolResultError(
+					fmt.Sprintf("exporting settlement reconciliation report "+
+						"failed: %s", err.Error())), nil
+			}
+			summary["csv"] = buf.String()
+			return mcpgo.NewToolResultJSON(summary)
+		}
+
+		if _, err := writeCSV(outputPath, columns, records); err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("exporting settlement reconciliation report "+
+					"failed: %s", err.Error())), nil
+		}
+		summary["output_file"] = outputPath
+
+		return mcpgo.NewToolResultJSON(summary)
+	}
+
+	return mcpgo.NewTool(
+		"export_settlement_recon_csv",
+		fmt.Sprintf("Export the settlement reconciliation report of up to "+
+			"%d days as CSV, fetching every page of every day. The CSV is "+
+			"returned, or written to output_file", maxReconExportDays),
+		parameters,
+		handler,
+	).WithOutputSchema(objectOutputSchema).
+		WithTimeout(exportToolTimeout).
+		// Exports write a file, which a cached result would skip
+		WithoutCache()
+}
+
+// parseReconDateRange parses the first and last days of a recon export,
+// checking that the range is in order and not too long
+func parseReconDateRange(
+	fromDate, toDate string,
+) (time.Time, time.Time, error) {
+	from, err := time.ParseInLocation("2006-01-02", fromDate,
+		settlementLocation)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf(
+			"invalid from_date %q, expected YYYY-MM-DD", fromDate)
+	}
+	to, err := time.ParseInLocation("2006-01-02", toDate, settlementLocation)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf(
+			"invalid to_date %q, expected YYYY-MM-DD", toDate)
+	}
+
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf(
+			"to_date must not be before from_date")
+	}
+	if to.After(from.AddDate(0, 0, maxReconExportDays-1)) {
+		return time.Time{}, time.Time{}, fmt.Errorf(
+			"date range must not span more than %d days", maxReconExportDays)
+	}
+
+	return from, to, nil
 }
 
 // FetchAllSettlements returns a tool to fetch multiple settlements with
@@ -144,7 +336,7 @@ func FetchAllSettlements(
 	obs *observability.Observability,
 	client *rzpsdk.Client,
 ) mcpgo.Tool {
-	parameters := []mcpgo.ToolParameter{
+	parameters := append([]mcpgo.ToolParameter{
 		// Pagination parameters
 		mcpgo.WithNumber(
 			"count",
@@ -171,7 +363,7 @@ func FetchAllSettlements(
 				"settlements are to be fetched"),
 			mcpgo.Min(0),
 		),
-	}
+	}, collectionParameters()...)
 
 	handler := func(
 		ctx context.Context,
@@ -184,10 +376,12 @@ func FetchAllSettlements(
 
 		// Create parameters map to collect validated parameters
 		fetchAllSettlementsOptions := make(map[string]interface{})
+		pagination := make(map[string]interface{})
 
 		// Validate using fluent validator
 		validator := NewValidator(&r).
 			ValidateAndAddPagination(fetchAllSettlementsOptions).
+			ValidateAndAddCollectionOptions(pagination).
 			ValidateAndAddOptionalInt(fetchAllSettlementsOptions, "from").
 			ValidateAndAddOptionalInt(fetchAllSettlementsOptions, "to")
 
@@ -196,13 +390,14 @@ func FetchAllSettlements(
 		}
 
 		// Fetch all settlements using Razorpay SDK
-		settlements, err := client.Settlement.All(fetchAllSettlementsOptions, nil)
+		settlements, err := fetchCollection(fetchAllSettlementsOptions, pagination,
+			client.Settlement.All)
 		if err != nil {
 			return mcpgo.NewToolResultError(
 				fmt.Sprintf("fetching settlements failed: %s", err.Error())), nil
 		}
 
-		return mcpgo.NewToolResultJSON(settlements)
+		return collectionResult(ctx, settlements, pagination)
 	}
 
 	return mcpgo.NewTool(
@@ -210,7 +405,8 @@ func FetchAllSettlements(
 		"Fetch all settlements with optional filtering and pagination",
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(settlementsOutputSchema).
+		WithFieldSelection()
 }
 
 // CreateInstantSettlement returns a tool that creates an instant settlement
@@ -286,7 +482,7 @@ func CreateInstantSettlement(
 		"Create an instant settlement to get funds transferred to your bank account", // nolint:lll
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(entityOutputSchema).WithAmounts("amount")
 }
 
 // FetchAllInstantSettlements returns a tool to fetch all instant settlements
@@ -295,7 +491,7 @@ func FetchAllInstantSettlements(
 	obs *observability.Observability,
 	client *rzpsdk.Client,
 ) mcpgo.Tool {
-	parameters := []mcpgo.ToolParameter{
+	parameters := append([]mcpgo.ToolParameter{
 		// Pagination parameters
 		mcpgo.WithNumber(
 			"count",
@@ -333,7 +529,7 @@ func FetchAllInstantSettlements(
 				"enum": []interface{}{"ondemand_payouts"},
 			}),
 		),
-	}
+	}, collectionParameters()...)
 
 	handler := func(
 		ctx context.Context,
@@ -346,10 +542,12 @@ func FetchAllInstantSettlements(
 
 		// Create parameters map to collect validated parameters
 		options := make(map[string]interface{})
+		pagination := make(map[string]interface{})
 
 		// Validate using fluent validator
 		validator := NewValidator(&r).
 			ValidateAndAddPagination(options).
+			ValidateAndAddCollectionOptions(pagination).
 			ValidateAndAddExpand(options).
 			ValidateAndAddOptionalInt(options, "from").
 			ValidateAndAddOptionalInt(options, "to")
@@ -359,13 +557,14 @@ func FetchAllInstantSettlements(
 		}
 
 		// Fetch all instant settlements using Razorpay SDK
-		settlements, err := client.Settlement.FetchAllOnDemandSettlement(options, nil)
+		settlements, err := fetchCollection(options, pagination,
+			client.Settlement.FetchAllOnDemandSettlement)
 		if err != nil {
 			return mcpgo.NewToolResultError(
 				fmt.Sprintf("fetching instant settlements failed: %s", err.Error())), nil
 		}
 
-		return mcpgo.NewToolResultJSON(settlements)
+		return collectionResult(ctx, settlements, pagination)
 	}
 
 	return mcpgo.NewTool(
@@ -373,7 +572,8 @@ func FetchAllInstantSettlements(
 		"Fetch all instant settlements with optional filtering, pagination, and payout details", //nolint:lll
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(entitiesOutputSchema).
+		WithFieldSelection()
 }
 
 // FetchInstantSettlement returns a tool that fetches instant settlement by ID
@@ -412,6 +612,11 @@ func FetchInstantSettlement(
 
 		settlementID := params["settlement_id"].(string)
 
+		if result := validateID("settlement_id", settlementID,
+			instantSettlementIDPrefix); result != nil {
+			return result, nil
+		}
+
 		// Fetch the instant settlement by ID using SDK
 		settlement, err := client.Settlement.FetchOnDemandSettlementById(
 			settlementID, nil, nil)
@@ -428,5 +633,6 @@ func FetchInstantSettlement(
 		"Fetch details of a specific instant settlement using its ID",
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(entityOutputSchema).
+		WithFieldSelection()
 }
@@ -1,11 +1,20 @@
 package razorpay
 
 import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
 	"github.com/razorpay/razorpay-go/constants"
 
 	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay/mock"
@@ -191,6 +200,199 @@ func Test_FetchSettlementRecon(t *testing.T) {
 	}
 }
 
+func Test_ExportSettlementReconCSV(t *testing.T) {
+	reconPath := fmt.Sprintf(
+		"/%s%s/recon/combined",
+		constants.VERSION_V1,
+		constants.SETTLEMENT_URL,
+	)
+
+	// The report of each day holds one payment, and the 16th a refund too
+	itemsByDay := map[string][]interface{}{
+		"15": {
+			map[string]interface{}{
+				"entity_id":     "pay_1",
+				"type":          "payment",
+				"amount":        float64(10000),
+				"settled":       true,
+				"created_at":    float64(1665800000),
+				"settlement_id": "setl_1",
+				"notes":         map[string]interface{}{"ref": "a,b"},
+			},
+		},
+		"16": {
+			map[string]interface{}{
+				"entity_id": "pay_2",
+				"type":      "payment",
+				"amount":    float64(20000),
+				"settled":   false,
+			},
+			map[string]interface{}{
+				"entity_id":    "rfnd_1",
+				"type":         "refund",
+				"amount":       float64(5000),
+				"refund_speed": "normal",
+			},
+		},
+	}
+
+	var requestedDays []string
+	mockHttpClient := func() (*http.Client, *httptest.Server) {
+		server := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != reconPath {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				query := r.URL.Query()
+				requestedDays = append(requestedDays, fmt.Sprintf("%s-%s-%s",
+					query.Get("year"), query.Get("month"), query.Get("day")))
+				items := itemsByDay[query.Get("day")]
+				if items == nil {
+					items = []interface{}{}
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{
+					"entity": "collection",
+					"count":  len(items),
+					"items":  items,
+				})
+			}))
+		return server.Client(), server
+	}
+
+	callTool := func(
+		args map[string]interface{},
+	) (map[string]interface{}, string) {
+		requestedDays = nil
+		client, server := newMockRzpClient(mockHttpClient)
+		defer server.Close()
+
+		tool := ExportSettlementReconCSV(CreateTestObservability(), client)
+		result, err := tool.GetHandler()(
+			context.Background(), createMCPRequest(args))
+		require.NoError(t, err)
+		if result.IsError {
+			return nil, result.Text
+		}
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(result.Text), &response))
+		return response, ""
+	}
+
+	t.Run("returns the csv of every day in the range", func(t *testing.T) {
+		response, errMsg := callTool(map[string]interface{}{
+			"from_date": "2022-10-15",
+			"to_date":   "2022-10-17",
+		})
+		require.Empty(t, errMsg)
+		assert.Equal(t,
+			[]string{"2022-10-15", "2022-10-16", "2022-10-17"}, requestedDays)
+		assert.Equal(t, float64(3), response["rows"])
+		assert.Equal(t, false, response["truncated"])
+
+		records, err := csv.NewReader(
+			strings.NewReader(response["csv"].(string))).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 4)
+
+		header := records[0]
+		assert.Equal(t, reconCSVColumns, header[:len(reconCSVColumns)])
+		assert.Equal(t, []string{"refund_speed"},
+			header[len(reconCSVColumns):])
+
+		row := make(map[string]string)
+		for i, column := range header {
+			row[column] = records[1][i]
+		}
+		assert.Equal(t, "pay_1", row["entity_id"])
+		assert.Equal(t, "10000", row["amount"])
+		assert.Equal(t, "true", row["settled"])
+		assert.Equal(t, "1665800000", row["created_at"])
+		assert.Equal(t, `{"ref":"a,b"}`, row["notes"])
+		assert.Equal(t, "", row["fee"])
+		assert.Equal(t, "rfnd_1", records[3][0])
+	})
+
+	t.Run("defaults to a single day", func(t *testing.T) {
+		response, errMsg := callTool(map[string]interface{}{
+			"from_date": "2022-10-16",
+		})
+		require.Empty(t, errMsg)
+		assert.Equal(t, []string{"2022-10-16"}, requestedDays)
+		assert.Equal(t, float64(2), response["rows"])
+	})
+
+	t.Run("writes the csv to output_file", func(t *testing.T) {
+		t.Chdir(t.TempDir())
+
+		response, errMsg := callTool(map[string]interface{}{
+			"from_date":   "2022-10-15",
+			"to_date":     "2022-10-16",
+			"output_file": "exports/recon.csv",
+		})
+		require.Empty(t, errMsg)
+		assert.Equal(t, float64(3), response["rows"])
+		assert.Equal(t, "csv", response["format"])
+		assert.NotContains(t, response, "csv")
+
+		wd, err := os.Getwd()
+		require.NoError(t, err)
+		outputPath := filepath.Join(wd, "exports", "recon.csv")
+		assert.Equal(t, outputPath, response["output_file"])
+
+		file, err := os.Open(outputPath)
+		require.NoError(t, err)
+		defer file.Close()
+
+		records, err := csv.NewReader(file).ReadAll()
+		require.NoError(t, err)
+		assert.Len(t, records, 4)
+	})
+
+	t.Run("rejects invalid ranges", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			args   map[string]interface{}
+			errMsg string
+		}{
+			"missing from_date": {
+				args:   map[string]interface{}{},
+				errMsg: "missing required parameter: from_date",
+			},
+			"malformed date": {
+				args:   map[string]interface{}{"from_date": "15-10-2022"},
+				errMsg: "invalid from_date",
+			},
+			"reversed range": {
+				args: map[string]interface{}{
+					"from_date": "2022-10-16",
+					"to_date":   "2022-10-15",
+				},
+				errMsg: "to_date must not be before from_date",
+			},
+			"range too long": {
+				args: map[string]interface{}{
+					"from_date": "2022-10-01",
+					"to_date":   "2022-11-01",
+				},
+				errMsg: "must not span more than 31 days",
+			},
+			"path traversal": {
+				args: map[string]interface{}{
+					"from_date":   "2022-10-15",
+					"output_file": "../recon.csv",
+				},
+				errMsg: "output_file must point to a file inside",
+			},
+		} {
+			_, errMsg := callTool(tc.args)
+			assert.Contains(t, errMsg, tc.errMsg, name)
+			assert.Empty(t, requestedDays, name)
+		}
+	})
+}
+
 func Test_FetchAllSettlements(t *testing.T) {
 	fetchAllSettlementsPath := fmt.Sprintf(
 		"/%s%s",
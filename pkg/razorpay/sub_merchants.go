@@ -0,0 +1,722 @@
+package razorpay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+)
+
+// subMerchantAccountIDParameter returns the account_id parameter shared by
+// the tools that operate on a single sub-merchant account
+func subMerchantAccountIDParameter(description string) mcpgo.ToolParameter {
+	return mcpgo.WithString(
+		"account_id",
+		mcpgo.Description(description+" (ID should have an acc_ prefix)."),
+		mcpgo.Required(),
+		mcpgo.Pattern(mcpgo.IDPattern(accountIDPrefix)),
+	)
+}
+
+// productIDParameter returns the product_id parameter shared by the tools
+// that operate on a single product configuration
+func productIDParameter(description string) mcpgo.ToolParameter {
+	return mcpgo.WithString(
+		"product_id",
+		mcpgo.Description(description+" (ID should have an acc_prd_ "+
+			"prefix)."),
+		mcpgo.Required(),
+		mcpgo.Pattern(mcpgo.IDPattern(productIDPrefix)),
+	)
+}
+
+// accountDetailParameters returns the parameters of the account details
+// that can be set when creating a sub-merchant account and updated later
+func accountDetailParameters() []mcpgo.ToolParameter {
+	return []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"customer_facing_business_name",
+			mcpgo.Description("Name of the business shown to customers"),
+		),
+		mcpgo.WithObject(
+			"profile",
+			mcpgo.Description("Business profile, with the 'category' and "+
+				"'subcategory' of the business and its 'addresses', such as "+
+				"{'category': 'healthcare', 'subcategory': 'clinic', "+
+				"'addresses': {'registered': {'street1': '507, Koramangala', "+
+				"'street2': '1st block', 'city': 'Bengaluru', "+
+				"'state': 'KARNATAKA', 'postal_code': 560034, "+
+				"'country': 'IN'}}}"),
+		),
+		mcpgo.WithObject(
+			"legal_info",
+			mcpgo.Description("Legal details of the business, with its "+
+				"'pan', 'gst' and 'cin'"),
+		),
+		mcpgo.WithObject(
+			"brand",
+			mcpgo.Description("Branding of the checkout, such as "+
+				"{'color': '000000'}"),
+		),
+		mcpgo.WithObject(
+			"contact_info",
+			mcpgo.Description("Contacts for 'chargeback', 'refund' and "+
+				"'support' queries, each with an 'email', 'phone' and "+
+				"'policy_url'"),
+		),
+		mcpgo.WithObject(
+			"notes",
+			mcpgo.Description("Key-value pairs for storing additional "+
+				"information about the account"),
+		),
+	}
+}
+
+// validateAccountDetails validates the account detail parameters and adds
+// those passed to accountData
+func validateAccountDetails(
+	validator *Validator,
+	accountData map[string]interface{},
+) *Validator {
+	return validator.
+		ValidateAndAddOptionalString(accountData,
+			"customer_facing_business_name").
+		ValidateAndAddOptionalMap(accountData, "profile").
+		ValidateAndAddOptionalMap(accountData, "legal_info").
+		ValidateAndAddOptionalMap(accountData, "brand").
+		ValidateAndAddOptionalMap(accountData, "contact_info").
+		ValidateAndAddOptionalMap(accountData, "notes")
+}
+
+// CreateAccount returns a tool that creates a sub-merchant account
+func CreateAccount(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := append([]mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"email",
+			mcpgo.Description("Email address of the business"),
+			mcpgo.Required(),
+		),
+		mcpgo.WithString(
+			"phone",
+			mcpgo.Description("Phone number of the business"),
+			mcpgo.Required(),
+		),
+		mcpgo.WithString(
+			"legal_business_name",
+			mcpgo.Description("Name of the business as registered"),
+			mcpgo.Required(),
+		),
+		mcpgo.WithString(
+			"business_type",
+			mcpgo.Description("Type of the business, such as "+
+				"proprietorship, partnership, private_limited, "+
+				"public_limited, llp, trust, society or individual"),
+			mcpgo.Required(),
+		),
+		mcpgo.WithString(
+			"contact_name",
+			mcpgo.Description("Name of the contact person of the business"),
+			mcpgo.Required(),
+		),
+		mcpgo.WithString(
+			"reference_id",
+			mcpgo.Description("Partner's own reference for the account"),
+		),
+	}, accountDetailParameters()...)
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		accountData := make(map[string]interface{})
+
+		validator := validateAccountDetails(NewValidator(&r).
+			ValidateAndAddRequiredString(accountData, "email").
+			ValidateAndAddRequiredString(accountData, "phone").
+			ValidateAndAddRequiredString(accountData, "legal_business_name").
+			ValidateAndAddRequiredString(accountData, "business_type").
+			ValidateAndAddRequiredString(accountData, "contact_name").
+			ValidateAndAddOptionalString(accountData, "reference_id"),
+			accountData)
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		account, err := client.Account.Create(accountData, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("creating account failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(account)
+	}
+
+	return mcpgo.NewTool(
+		"create_account",
+		"Create a sub-merchant account under the partner, the first step "+
+			"of onboarding a sub-merchant. Add its stakeholders and request "+
+			"its products next",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema)
+}
+
+// FetchAccount returns a tool that fetches a sub-merchant account
+func FetchAccount(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		subMerchantAccountIDParameter("ID of the account to be fetched"),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(fields, "account_id")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		accountID := fields["account_id"].(string)
+		if result := validateID("account_id",
+			accountID, accountIDPrefix); result != nil {
+			return result, nil
+		}
+
+		account, err := client.Account.Fetch(accountID, nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching account failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(account)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_account",
+		"Fetch the details and activation status of a sub-merchant account",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema).
+		WithFieldSelection()
+}
+
+// UpdateAccount returns a tool that updates the details of a sub-merchant
+// account
+func UpdateAccount(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := append([]mcpgo.ToolParameter{
+		subMerchantAccountIDParameter("ID of the account to be updated"),
+		mcpgo.WithString(
+			"phone",
+			mcpgo.Description("Phone number of the business"),
+		),
+		mcpgo.WithString(
+			"legal_business_name",
+			mcpgo.Description("Name of the business as registered"),
+		),
+		mcpgo.WithString(
+			"contact_name",
+			mcpgo.Description("Name of the contact person of the business"),
+		),
+	}, accountDetailParameters()...)
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+		accountData := make(map[string]interface{})
+
+		validator := validateAccountDetails(NewValidator(&r).
+			ValidateAndAddRequiredString(fields, "account_id").
+			ValidateAndAddOptionalString(accountData, "phone").
+			ValidateAndAddOptionalString(accountData, "legal_business_name").
+			ValidateAndAddOptionalString(accountData, "contact_name"),
+			accountData)
+
+		if !validator.HasErrors() && len(accountData) == 0 {
+			validator.addError(errors.New("no fields to update"))
+		}
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		accountID := fields["account_id"].(string)
+		if result := validateID("account_id",
+			accountID, accountIDPrefix); result != nil {
+			return result, nil
+		}
+
+		account, err := client.Account.Edit(accountID, accountData, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("updating account failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(account)
+	}
+
+	return mcpgo.NewTool(
+		"update_account",
+		"Update the details of a sub-merchant account, such as to add the "+
+			"KYC details its activation needs",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema)
+}
+
+// CreateStakeholder returns a tool that adds a stakeholder to a
+// sub-merchant account
+func CreateStakeholder(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		subMerchantAccountIDParameter("ID of the account the stakeholder " +
+			"belongs to"),
+		mcpgo.WithString(
+			"name",
+			mcpgo.Description("Name of the stakeholder"),
+			mcpgo.Required(),
+		),
+		mcpgo.WithString(
+			"email",
+			mcpgo.Description("Email address of the stakeholder"),
+			mcpgo.Required(),
+		),
+		mcpgo.WithNumber(
+			"percentage_ownership",
+			mcpgo.Description("Percentage of the business the stakeholder "+
+				"owns"),
+			mcpgo.Min(0),
+			mcpgo.Max(100),
+		),
+		mcpgo.WithObject(
+			"relationship",
+			mcpgo.Description("Whether the stakeholder is a 'director' or an "+
+				"'executive' of the business, such as {'director': true}"),
+		),
+		mcpgo.WithObject(
+			"phone",
+			mcpgo.Description("Phone numbers of the stakeholder, with a "+
+				"'primary' and a 'secondary' number"),
+		),
+		mcpgo.WithObject(
+			"addresses",
+			mcpgo.Description("Addresses of the stakeholder, such as "+
+				"{'residential': {'street': '506, Koramangala 1st block', "+
+				"'city': 'Bengaluru', 'state': 'Karnataka', "+
+				"'postal_code': '560034', 'country': 'IN'}}"),
+		),
+		mcpgo.WithObject(
+			"kyc",
+			mcpgo.Description("KYC details of the stakeholder, such as "+
+				"{'pan': 'AVOPB1111K'}"),
+		),
+		mcpgo.WithObject(
+			"notes",
+			mcpgo.Description("Key-value pairs for storing additional "+
+				"information about the stakeholder"),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+		stakeholderData := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(fields, "account_id").
+			ValidateAndAddRequiredString(stakeholderData, "name").
+			ValidateAndAddRequiredString(stakeholderData, "email").
+			ValidateAndAddOptionalFloat(stakeholderData,
+				"percentage_ownership").
+			ValidateAndAddOptionalMap(stakeholderData, "relationship").
+			ValidateAndAddOptionalMap(stakeholderData, "phone").
+			ValidateAndAddOptionalMap(stakeholderData, "addresses").
+			ValidateAndAddOptionalMap(stakeholderData, "kyc").
+			ValidateAndAddOptionalMap(stakeholderData, "notes")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		accountID := fields["account_id"].(string)
+		if result := validateID("account_id",
+			accountID, accountIDPrefix); result != nil {
+			return result, nil
+		}
+
+		stakeholder, err := client.Stakeholder.Create(
+			accountID, stakeholderData, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("creating stakeholder failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(stakeholder)
+	}
+
+	return mcpgo.NewTool(
+		"create_stakeholder",
+		"Add a stakeholder, such as a director or proprietor, with their "+
+			"KYC details to a sub-merchant account",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema)
+}
+
+// FetchAllStakeholders returns a tool that fetches the stakeholders of a
+// sub-merchant account
+func FetchAllStakeholders(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		subMerchantAccountIDParameter("ID of the account whose " +
+			"stakeholders are to be fetched"),
+		collectionFormatParameter(),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(fields, "account_id").
+			ValidateAndAddCollectionFormat(fields)
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		accountID := fields["account_id"].(string)
+		if result := validateID("account_id",
+			accountID, accountIDPrefix); result != nil {
+			return result, nil
+		}
+
+		stakeholders, err := client.Stakeholder.All(accountID, nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching stakeholders failed: %s",
+					err.Error())), nil
+		}
+
+		return collectionResult(ctx, stakeholders, fields)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_all_stakeholders",
+		"Fetch all stakeholders of a sub-merchant account",
+		parameters,
+		handler,
+	).WithOutputSchema(entitiesOutputSchema).
+		WithFieldSelection()
+}
+
+// productSettingParameters returns the parameters of the settings of a
+// product configuration that can be set when updating it
+func productSettingParameters() []mcpgo.ToolParameter {
+	return []mcpgo.ToolParameter{
+		mcpgo.WithObject(
+			"settlements",
+			mcpgo.Description("Bank account settlements are made to, such "+
+				"as {'account_number': '1234567890', 'ifsc_code': "+
+				"'HDFC0000317', 'beneficiary_name': 'Gaurav Kumar'}"),
+		),
+		mcpgo.WithObject(
+			"checkout",
+			mcpgo.Description("Checkout settings, such as {'theme_color': "+
+				"'#FFFFFF'}"),
+		),
+		mcpgo.WithObject(
+			"refund",
+			mcpgo.Description("Refund settings, such as "+
+				"{'default_refund_speed': 'normal'}"),
+		),
+		mcpgo.WithObject(
+			"notifications",
+			mcpgo.Description("Notification settings, such as {'email': "+
+				"['gaurav.kumar@example.com']}"),
+		),
+		mcpgo.WithObject(
+			"payment_methods",
+			mcpgo.Description("Payment methods to request or change, such "+
+				"as {'netbanking': {'enabled': true}}"),
+		),
+	}
+}
+
+// productTncParameters returns the parameters of the acceptance of the
+// terms and conditions of a product
+func productTncParameters() []mcpgo.ToolParameter {
+	return []mcpgo.ToolParameter{
+		mcpgo.WithBoolean(
+			"tnc_accepted",
+			mcpgo.Description("Whether the sub-merchant accepted the terms "+
+				"and conditions of the product"),
+		),
+		mcpgo.WithString(
+			"ip",
+			mcpgo.Description("IP address the terms and conditions were "+
+				"accepted from. Required with tnc_accepted"),
+		),
+	}
+}
+
+// RequestProductConfiguration returns a tool that requests a product for a
+// sub-merchant account
+func RequestProductConfiguration(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := append([]mcpgo.ToolParameter{
+		subMerchantAccountIDParameter("ID of the account the product is " +
+			"requested for"),
+		mcpgo.WithString(
+			"product_name",
+			mcpgo.Description("Product requested"),
+			mcpgo.Required(),
+			mcpgo.Enum("payment_gateway", "payment_links", "route"),
+		),
+	}, productTncParameters()...)
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+		productData := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(fields, "account_id").
+			ValidateAndAddRequiredString(productData, "product_name").
+			ValidateAndAddOptionalBool(productData, "tnc_accepted").
+			ValidateAndAddOptionalString(productData, "ip")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		accountID := fields["account_id"].(string)
+		if result := validateID("account_id",
+			accountID, accountIDPrefix); result != nil {
+			return result, nil
+		}
+
+		product, err := client.Product.RequestProductConfiguration(
+			accountID, productData, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("requesting product configuration failed: %s",
+					err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(product)
+	}
+
+	return mcpgo.NewTool(
+		"request_product_configuration",
+		"Request a product, such as the payment gateway, for a "+
+			"sub-merchant account. The result lists the requirements still "+
+			"to be met for its activation",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema)
+}
+
+// FetchProductConfiguration returns a tool that fetches a product
+// configuration of a sub-merchant account
+func FetchProductConfiguration(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		subMerchantAccountIDParameter("ID of the account the product " +
+			"belongs to"),
+		productIDParameter("ID of the product configuration to be fetched"),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(fields, "account_id").
+			ValidateAndAddRequiredString(fields, "product_id")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		accountID, productID, result := validateProductIDs(fields)
+		if result != nil {
+			return result, nil
+		}
+
+		product, err := client.Product.Fetch(accountID, productID, nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching product configuration failed: %s",
+					err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(product)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_product_configuration",
+		"Fetch a product configuration of a sub-merchant account, with its "+
+			"activation status and the requirements still to be met",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema).
+		WithFieldSelection()
+}
+
+// UpdateProductConfiguration returns a tool that updates a product
+// configuration of a sub-merchant account
+func UpdateProductConfiguration(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := append(append([]mcpgo.ToolParameter{
+		subMerchantAccountIDParameter("ID of the account the product " +
+			"belongs to"),
+		productIDParameter("ID of the product configuration to be updated"),
+	}, productSettingParameters()...), productTncParameters()...)
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+		productData := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(fields, "account_id").
+			ValidateAndAddRequiredString(fields, "product_id").
+			ValidateAndAddOptionalMap(productData, "settlements").
+			ValidateAndAddOptionalMap(productData, "checkout").
+			ValidateAndAddOptionalMap(productData, "refund").
+			ValidateAndAddOptionalMap(productData, "notifications").
+			ValidateAndAddOptionalMap(productData, "payment_methods").
+			ValidateAndAddOptionalBool(productData, "tnc_accepted").
+			ValidateAndAddOptionalString(productData, "ip")
+
+		if !validator.HasErrors() && len(productData) == 0 {
+			validator.addError(errors.New("no fields to update"))
+		}
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		accountID, productID, result := validateProductIDs(fields)
+		if result != nil {
+			return result, nil
+		}
+
+		product, err := client.Product.Edit(
+			accountID, productID, productData, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("updating product configuration failed: %s",
+					err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(product)
+	}
+
+	return mcpgo.NewTool(
+		"update_product_configuration",
+		"Update a product configuration of a sub-merchant account, such as "+
+			"its settlement bank account or payment methods, to meet the "+
+			"requirements of its activation",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema)
+}
+
+// validateProductIDs returns the account and product IDs in fields, or an
+// error tool result if either is not a valid ID
+func validateProductIDs(
+	fields map[string]interface{},
+) (string, string, *mcpgo.ToolResult) {
+	accountID := fields["account_id"].(string)
+	if result := validateID("account_id",
+		accountID, accountIDPrefix); result != nil {
+		return "", "", result
+	}
+	productID := fields["product_id"].(string)
+	if result := validateID("product_id",
+		productID, productIDPrefix); result != nil {
+		return "", "", result
+	}
+	return accountID, productID, nil
+}
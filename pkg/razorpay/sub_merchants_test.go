@@ -0,0 +1,560 @@
+package razorpay
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/razorpay/razorpay-go/constants"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay/mock"
+)
+
+var (
+	subMerchantAccountsPath = fmt.Sprintf(
+		"/%s%s",
+		constants.VERSION_V2,
+		constants.ACCOUNT_URL,
+	)
+	subMerchantAccountPath = subMerchantAccountsPath + "/acc_GRWKk7qQsLnDjX"
+	stakeholdersPath       = subMerchantAccountPath + constants.STAKEHOLDER_URL
+	productsPath           = subMerchantAccountPath + constants.PRODUCT_URL
+	productPath            = productsPath + "/acc_prd_HEgNpywUFctQ9e"
+)
+
+var subMerchantAccountResp = map[string]interface{}{
+	"id":                  "acc_GRWKk7qQsLnDjX",
+	"type":                "standard",
+	"status":              "created",
+	"email":               "gauriagain.kumar@example.org",
+	"phone":               "9000090000",
+	"legal_business_name": "Acme Corp",
+	"business_type":       "partnership",
+	"contact_name":        "Gaurav Kumar",
+	"reference_id":        "randomId",
+	"profile": map[string]interface{}{
+		"category":    "healthcare",
+		"subcategory": "clinic",
+	},
+	"created_at": float64(1611136837),
+}
+
+var stakeholderResp = map[string]interface{}{
+	"entity":               "stakeholder",
+	"id":                   "sth_GLGgm8fFCKc92m",
+	"name":                 "Gaurav Kumar",
+	"email":                "gaurav.kumar@example.com",
+	"percentage_ownership": float64(10),
+	"relationship": map[string]interface{}{
+		"director": true,
+	},
+	"kyc": map[string]interface{}{
+		"pan": "AVOPB1111K",
+	},
+}
+
+var productResp = map[string]interface{}{
+	"id":                   "acc_prd_HEgNpywUFctQ9e",
+	"account_id":           "acc_GRWKk7qQsLnDjX",
+	"product_name":         "payment_gateway",
+	"activation_status":    "needs_clarification",
+	"tnc":                  map[string]interface{}{"accepted": true},
+	"requested_at":         float64(1625478849),
+	"requirements":         []interface{}{},
+	"active_configuration": map[string]interface{}{},
+}
+
+var subMerchantErrorResp = map[string]interface{}{
+	"error": map[string]interface{}{
+		"code":        "BAD_REQUEST_ERROR",
+		"description": "The id provided does not exist",
+	},
+}
+
+func Test_CreateAccount(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful account creation",
+			Request: map[string]interface{}{
+				"email":               "gauriagain.kumar@example.org",
+				"phone":               "9000090000",
+				"legal_business_name": "Acme Corp",
+				"business_type":       "partnership",
+				"contact_name":        "Gaurav Kumar",
+				"reference_id":        "randomId",
+				"profile": map[string]interface{}{
+					"category":    "healthcare",
+					"subcategory": "clinic",
+				},
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     subMerchantAccountsPath,
+						Method:   "POST",
+						Response: subMerchantAccountResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: subMerchantAccountResp,
+		},
+		{
+			Name: "account creation fails",
+			Request: map[string]interface{}{
+				"email":               "gauriagain.kumar@example.org",
+				"phone":               "9000090000",
+				"legal_business_name": "Acme Corp",
+				"business_type":       "partnership",
+				"contact_name":        "Gaurav Kumar",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     subMerchantAccountsPath,
+						Method:   "POST",
+						Response: subMerchantErrorResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "creating account failed: " +
+				"The id provided does not exist",
+		},
+		{
+			Name: "missing business type",
+			Request: map[string]interface{}{
+				"email":               "gauriagain.kumar@example.org",
+				"phone":               "9000090000",
+				"legal_business_name": "Acme Corp",
+				"contact_name":        "Gaurav Kumar",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: business_type",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, CreateAccount, "Account")
+		})
+	}
+}
+
+func Test_FetchAccount(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful account fetch",
+			Request: map[string]interface{}{
+				"account_id": "acc_GRWKk7qQsLnDjX",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     subMerchantAccountPath,
+						Method:   "GET",
+						Response: subMerchantAccountResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: subMerchantAccountResp,
+		},
+		{
+			Name: "account fetch fails",
+			Request: map[string]interface{}{
+				"account_id": "acc_GRWKk7qQsLnDjX",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     subMerchantAccountPath,
+						Method:   "GET",
+						Response: subMerchantErrorResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "fetching account failed: " +
+				"The id provided does not exist",
+		},
+		{
+			Name: "invalid account id",
+			Request: map[string]interface{}{
+				"account_id": "GRWKk7qQsLnDjX",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid account_id: GRWKk7qQsLnDjX",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchAccount, "Account")
+		})
+	}
+}
+
+func Test_UpdateAccount(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful account update",
+			Request: map[string]interface{}{
+				"account_id":   "acc_GRWKk7qQsLnDjX",
+				"contact_name": "Gaurav Kumar",
+				"legal_info": map[string]interface{}{
+					"pan": "AAACL1234C",
+				},
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     subMerchantAccountPath,
+						Method:   "PATCH",
+						Response: subMerchantAccountResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: subMerchantAccountResp,
+		},
+		{
+			Name: "account update fails",
+			Request: map[string]interface{}{
+				"account_id": "acc_GRWKk7qQsLnDjX",
+				"phone":      "9000090000",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     subMerchantAccountPath,
+						Method:   "PATCH",
+						Response: subMerchantErrorResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "updating account failed: " +
+				"The id provided does not exist",
+		},
+		{
+			Name: "no fields to update",
+			Request: map[string]interface{}{
+				"account_id": "acc_GRWKk7qQsLnDjX",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "no fields to update",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, UpdateAccount, "Account")
+		})
+	}
+}
+
+func Test_CreateStakeholder(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful stakeholder creation",
+			Request: map[string]interface{}{
+				"account_id":           "acc_GRWKk7qQsLnDjX",
+				"name":                 "Gaurav Kumar",
+				"email":                "gaurav.kumar@example.com",
+				"percentage_ownership": float64(10),
+				"relationship": map[string]interface{}{
+					"director": true,
+				},
+				"kyc": map[string]interface{}{
+					"pan": "AVOPB1111K",
+				},
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     stakeholdersPath,
+						Method:   "POST",
+						Response: stakeholderResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: stakeholderResp,
+		},
+		{
+			Name: "stakeholder creation fails",
+			Request: map[string]interface{}{
+				"account_id": "acc_GRWKk7qQsLnDjX",
+				"name":       "Gaurav Kumar",
+				"email":      "gaurav.kumar@example.com",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     stakeholdersPath,
+						Method:   "POST",
+						Response: subMerchantErrorResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "creating stakeholder failed: " +
+				"The id provided does not exist",
+		},
+		{
+			Name: "missing email",
+			Request: map[string]interface{}{
+				"account_id": "acc_GRWKk7qQsLnDjX",
+				"name":       "Gaurav Kumar",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: email",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, CreateStakeholder, "Stakeholder")
+		})
+	}
+}
+
+func Test_FetchAllStakeholders(t *testing.T) {
+	stakeholdersResp := map[string]interface{}{
+		"entity": "collection",
+		"count":  float64(1),
+		"items":  []interface{}{stakeholderResp},
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful stakeholders fetch",
+			Request: map[string]interface{}{
+				"account_id": "acc_GRWKk7qQsLnDjX",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     stakeholdersPath,
+						Method:   "GET",
+						Response: stakeholdersResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: stakeholdersResp,
+		},
+		{
+			Name: "stakeholders fetch fails",
+			Request: map[string]interface{}{
+				"account_id": "acc_GRWKk7qQsLnDjX",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     stakeholdersPath,
+						Method:   "GET",
+						Response: subMerchantErrorResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "fetching stakeholders failed: " +
+				"The id provided does not exist",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchAllStakeholders, "Stakeholders")
+		})
+	}
+}
+
+func Test_RequestProductConfiguration(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful product request",
+			Request: map[string]interface{}{
+				"account_id":   "acc_GRWKk7qQsLnDjX",
+				"product_name": "payment_gateway",
+				"tnc_accepted": true,
+				"ip":           "233.233.233.234",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     productsPath,
+						Method:   "POST",
+						Response: productResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: productResp,
+		},
+		{
+			Name: "product request fails",
+			Request: map[string]interface{}{
+				"account_id":   "acc_GRWKk7qQsLnDjX",
+				"product_name": "payment_gateway",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     productsPath,
+						Method:   "POST",
+						Response: subMerchantErrorResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "requesting product configuration failed: " +
+				"The id provided does not exist",
+		},
+		{
+			Name: "missing product name",
+			Request: map[string]interface{}{
+				"account_id": "acc_GRWKk7qQsLnDjX",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: product_name",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, RequestProductConfiguration, "Product")
+		})
+	}
+}
+
+func Test_FetchProductConfiguration(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful product fetch",
+			Request: map[string]interface{}{
+				"account_id": "acc_GRWKk7qQsLnDjX",
+				"product_id": "acc_prd_HEgNpywUFctQ9e",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     productPath,
+						Method:   "GET",
+						Response: productResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: productResp,
+		},
+		{
+			Name: "product fetch fails",
+			Request: map[string]interface{}{
+				"account_id": "acc_GRWKk7qQsLnDjX",
+				"product_id": "acc_prd_HEgNpywUFctQ9e",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     productPath,
+						Method:   "GET",
+						Response: subMerchantErrorResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "fetching product configuration failed: " +
+				"The id provided does not exist",
+		},
+		{
+			Name: "invalid product id",
+			Request: map[string]interface{}{
+				"account_id": "acc_GRWKk7qQsLnDjX",
+				"product_id": "prd_HEgNpywUFctQ9e",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid product_id: prd_HEgNpywUFctQ9e",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchProductConfiguration, "Product")
+		})
+	}
+}
+
+func Test_UpdateProductConfiguration(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful product update",
+			Request: map[string]interface{}{
+				"account_id": "acc_GRWKk7qQsLnDjX",
+				"product_id": "acc_prd_HEgNpywUFctQ9e",
+				"settlements": map[string]interface{}{
+					"account_number":   "1234567890",
+					"ifsc_code":        "HDFC0000317",
+					"beneficiary_name": "Gaurav Kumar",
+				},
+				"tnc_accepted": true,
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     productPath,
+						Method:   "PATCH",
+						Response: productResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: productResp,
+		},
+		{
+			Name: "product update fails",
+			Request: map[string]interface{}{
+				"account_id":   "acc_GRWKk7qQsLnDjX",
+				"product_id":   "acc_prd_HEgNpywUFctQ9e",
+				"tnc_accepted": true,
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     productPath,
+						Method:   "PATCH",
+						Response: subMerchantErrorResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "updating product configuration failed: " +
+				"The id provided does not exist",
+		},
+		{
+			Name: "no fields to update",
+			Request: map[string]interface{}{
+				"account_id": "acc_GRWKk7qQsLnDjX",
+				"product_id": "acc_prd_HEgNpywUFctQ9e",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "no fields to update",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, UpdateProductConfiguration, "Product")
+		})
+	}
+}
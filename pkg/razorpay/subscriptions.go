@@ -0,0 +1,625 @@
+package razorpay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+)
+
+// subscriptionIDParameter returns the subscription_id parameter shared by
+// the tools that operate on a single subscription
+func subscriptionIDParameter(description string) mcpgo.ToolParameter {
+	return mcpgo.WithString(
+		"subscription_id",
+		mcpgo.Description(description+" (ID should have a sub_ prefix)."),
+		mcpgo.Required(),
+		mcpgo.Pattern(mcpgo.IDPattern(subscriptionIDPrefix)),
+	)
+}
+
+// subscriptionAction is the SDK call behind a tool that changes the state
+// of a subscription
+type subscriptionAction func(
+	client *rzpsdk.Client,
+	subscriptionID string,
+	data map[string]interface{},
+) (map[string]interface{}, error)
+
+// subscriptionStateTool builds a tool that applies a state change, such as
+// pause or resume, to a subscription. amounts are the parameters that hold
+// amounts, see WithAmounts.
+func subscriptionStateTool(
+	client *rzpsdk.Client,
+	name string,
+	description string,
+	verb string,
+	parameters []mcpgo.ToolParameter,
+	buildData func(v *Validator, data map[string]interface{}) *Validator,
+	action subscriptionAction,
+	amounts ...string,
+) mcpgo.Tool {
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+		data := make(map[string]interface{})
+
+		validator := buildData(NewValidator(&r).
+			ValidateAndAddRequiredString(fields, "subscription_id"), data)
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		subscriptionID := fields["subscription_id"].(string)
+		if result := validateID("subscription_id",
+			subscriptionID, subscriptionIDPrefix); result != nil {
+			return result, nil
+		}
+
+		subscription, err := action(client, subscriptionID, data)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("%s subscription failed: %s", verb, err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(subscription)
+	}
+
+	tool := mcpgo.NewTool(name, description, parameters, handler).
+		WithOutputSchema(entityOutputSchema)
+	if len(amounts) > 0 {
+		tool = tool.WithAmounts(amounts...)
+	}
+	return tool
+}
+
+// subscriptionParameters returns the parameters shared by the tools that
+// create a subscription
+func subscriptionParameters() []mcpgo.ToolParameter {
+	return []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"plan_id",
+			mcpgo.Description("ID of the plan the subscription bills for "+
+				"(ID should have a plan_ prefix)."),
+			mcpgo.Required(),
+		),
+		mcpgo.WithNumber(
+			"total_count",
+			mcpgo.Description("Number of billing cycles the customer is "+
+				"charged for"),
+			mcpgo.Required(),
+			mcpgo.Min(1),
+		),
+		mcpgo.WithNumber(
+			"quantity",
+			mcpgo.Description("Number of times the plan amount is charged "+
+				"per billing cycle. Defaults to 1"),
+			mcpgo.Min(1),
+		),
+		mcpgo.WithNumber(
+			"start_at",
+			mcpgo.Description("Unix timestamp (in seconds) of the first "+
+				"charge. Defaults to immediately after authorisation"),
+		),
+		mcpgo.WithNumber(
+			"expire_by",
+			mcpgo.Description("Unix timestamp (in seconds) until which the "+
+				"customer can authorise the subscription"),
+		),
+		mcpgo.WithBoolean(
+			"customer_notify",
+			mcpgo.Description("Whether Razorpay sends the customer "+
+				"notifications about the subscription. Defaults to true"),
+		),
+		mcpgo.WithArray(
+			"addons",
+			mcpgo.Description("Upfront amounts charged along with the first "+
+				"payment, as objects with an 'item' holding 'name', "+
+				"'amount' and 'currency'"),
+		),
+		mcpgo.WithString(
+			"offer_id",
+			mcpgo.Description("ID of an offer to apply to the subscription"),
+		),
+		mcpgo.WithObject(
+			"notes",
+			mcpgo.Description("Key-value pairs for additional information "+
+				"(max 15 pairs, 256 chars each)"),
+			mcpgo.MaxProperties(15),
+		),
+	}
+}
+
+// validateSubscription adds the parameters shared by the tools that create
+// a subscription to the request data
+func validateSubscription(
+	v *Validator,
+	data map[string]interface{},
+) *Validator {
+	return v.
+		ValidateAndAddRequiredString(data, "plan_id").
+		ValidateAndAddRequiredInt(data, "total_count").
+		ValidateAndAddOptionalInt(data, "quantity").
+		ValidateAndAddOptionalInt(data, "start_at").
+		ValidateAndAddOptionalInt(data, "expire_by").
+		ValidateAndAddOptionalBool(data, "customer_notify").
+		ValidateAndAddOptionalArray(data, "addons").
+		ValidateAndAddOptionalString(data, "offer_id").
+		ValidateAndAddOptionalMap(data, "notes")
+}
+
+// CreateSubscription returns a tool that creates a subscription for a plan
+func CreateSubscription(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		subscriptionData := make(map[string]interface{})
+
+		validator := validateSubscription(NewValidator(&r), subscriptionData)
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		subscription, err := client.Subscription.Create(subscriptionData, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("creating subscription failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(subscription)
+	}
+
+	return mcpgo.NewTool(
+		"create_subscription",
+		"Create a subscription that charges a customer on a plan's billing "+
+			"cycle. The response contains a short_url the customer opens to "+
+			"authorise the subscription.",
+		subscriptionParameters(),
+		handler,
+	).WithOutputSchema(entityOutputSchema)
+}
+
+// CreateSubscriptionLink returns a tool that creates a subscription and
+// sends its authorisation link to the customer
+func CreateSubscriptionLink(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := append(subscriptionParameters(),
+		mcpgo.WithString(
+			"notify_phone",
+			mcpgo.Description("Phone number the subscription link is sent "+
+				"to by SMS"),
+		),
+		mcpgo.WithString(
+			"notify_email",
+			mcpgo.Description("Email address the subscription link is "+
+				"sent to"),
+		),
+	)
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		subscriptionData := make(map[string]interface{})
+		notifyInfo := make(map[string]interface{})
+
+		validator := validateSubscription(NewValidator(&r), subscriptionData).
+			ValidateAndAddOptionalString(notifyInfo, "notify_phone").
+			ValidateAndAddOptionalString(notifyInfo, "notify_email")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		if len(notifyInfo) == 0 {
+			return mcpgo.NewToolResultError(
+				"at least one of notify_phone or notify_email is required"), nil
+		}
+		subscriptionData["notify_info"] = notifyInfo
+
+		subscription, err := client.Subscription.Create(subscriptionData, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(fmt.Sprintf(
+				"creating subscription link failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(subscription)
+	}
+
+	return mcpgo.NewTool(
+		"create_subscription_link",
+		"Create a subscription link: a subscription for a plan whose "+
+			"authorisation link Razorpay sends to the customer by SMS or "+
+			"email, so they can set up the recurring mandate. The link "+
+			"expires at expire_by. The response contains the short_url.",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema)
+}
+
+// FetchSubscription returns a tool that fetches a subscription by its ID
+func FetchSubscription(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		subscriptionIDParameter("ID of the subscription to be fetched"),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(fields, "subscription_id")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		subscriptionID := fields["subscription_id"].(string)
+		if result := validateID("subscription_id",
+			subscriptionID, subscriptionIDPrefix); result != nil {
+			return result, nil
+		}
+
+		subscription, err := client.Subscription.Fetch(subscriptionID, nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching subscription failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(subscription)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_subscription",
+		"Fetch the details of a subscription, including its status, plan "+
+			"and billing cycle counts",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema).
+		WithFieldSelection()
+}
+
+// FetchAllSubscriptions returns a tool that fetches all subscriptions with
+// optional filtering
+func FetchAllSubscriptions(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := append([]mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"plan_id",
+			mcpgo.Description("Optional: Filter by the plan the "+
+				"subscriptions are linked to"),
+		),
+		mcpgo.WithNumber(
+			"from",
+			mcpgo.Description("Unix timestamp (in seconds) from when "+
+				"subscriptions are to be fetched"),
+			mcpgo.Min(0),
+		),
+		mcpgo.WithNumber(
+			"to",
+			mcpgo.Description("Unix timestamp (in seconds) up till when "+
+				"subscriptions are to be fetched"),
+			mcpgo.Min(0),
+		),
+		mcpgo.WithNumber(
+			"count",
+			mcpgo.Description("Number of subscriptions to be fetched. "+
+				"Default value is 10. Maximum value is 100"),
+			mcpgo.Min(1),
+			mcpgo.Max(100),
+		),
+		mcpgo.WithNumber(
+			"skip",
+			mcpgo.Description("Number of subscriptions to be skipped. "+
+				"Default value is 0. This can be used for pagination"),
+			mcpgo.Min(0),
+		),
+	}, collectionParameters()...)
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		queryParams := make(map[string]interface{})
+		pagination := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddOptionalString(queryParams, "plan_id").
+			ValidateAndAddOptionalInt(queryParams, "from").
+			ValidateAndAddOptionalInt(queryParams, "to").
+			ValidateAndAddPagination(queryParams).
+			ValidateAndAddCollectionOptions(pagination)
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		subscriptions, err := fetchCollection(queryParams, pagination,
+			client.Subscription.All)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching subscriptions failed: %s", err.Error())), nil
+		}
+
+		return collectionResult(ctx, subscriptions, pagination)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_all_subscriptions",
+		"Fetch all subscriptions with optional filtering by plan and "+
+			"creation date",
+		parameters,
+		handler,
+	).WithOutputSchema(entitiesOutputSchema).
+		WithFieldSelection()
+}
+
+// CancelSubscription returns a tool that cancels a subscription
+func CancelSubscription(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	return subscriptionStateTool(
+		client,
+		"cancel_subscription",
+		"Cancel a subscription, either immediately or at the end of the "+
+			"current billing cycle. A cancelled subscription cannot be "+
+			"resumed.",
+		"cancelling",
+		[]mcpgo.ToolParameter{
+			subscriptionIDParameter("ID of the subscription to be cancelled"),
+			mcpgo.WithBoolean(
+				"cancel_at_cycle_end",
+				mcpgo.Description("Cancel at the end of the current billing "+
+					"cycle instead of immediately. Defaults to false"),
+				mcpgo.DefaultValue(false),
+			),
+		},
+		func(v *Validator, data map[string]interface{}) *Validator {
+			return v.ValidateAndAddOptionalBool(data, "cancel_at_cycle_end")
+		},
+		func(
+			client *rzpsdk.Client,
+			subscriptionID string,
+			data map[string]interface{},
+		) (map[string]interface{}, error) {
+			return client.Subscription.Cancel(subscriptionID, data, nil)
+		},
+	)
+}
+
+// PauseSubscription returns a tool that pauses an active subscription
+func PauseSubscription(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	return subscriptionStateTool(
+		client,
+		"pause_subscription",
+		"Pause an active subscription immediately. No charges are made "+
+			"until it is resumed with resume_subscription.",
+		"pausing",
+		[]mcpgo.ToolParameter{
+			subscriptionIDParameter("ID of the subscription to be paused"),
+		},
+		func(v *Validator, data map[string]interface{}) *Validator {
+			data["pause_at"] = "now"
+			return v
+		},
+		func(
+			client *rzpsdk.Client,
+			subscriptionID string,
+			data map[string]interface{},
+		) (map[string]interface{}, error) {
+			return client.Subscription.Pause(subscriptionID, data, nil)
+		},
+	)
+}
+
+// ResumeSubscription returns a tool that resumes a paused subscription
+func ResumeSubscription(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	return subscriptionStateTool(
+		client,
+		"resume_subscription",
+		"Resume a paused subscription immediately",
+		"resuming",
+		[]mcpgo.ToolParameter{
+			subscriptionIDParameter("ID of the subscription to be resumed"),
+		},
+		func(v *Validator, data map[string]interface{}) *Validator {
+			data["resume_at"] = "now"
+			return v
+		},
+		func(
+			client *rzpsdk.Client,
+			subscriptionID string,
+			data map[string]interface{},
+		) (map[string]interface{}, error) {
+			return client.Subscription.Resume(subscriptionID, data, nil)
+		},
+	)
+}
+
+// UpdateSubscription returns a tool that updates a subscription
+func UpdateSubscription(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	return subscriptionStateTool(
+		client,
+		"update_subscription",
+		"Update the plan, quantity, remaining billing cycles or offer of a "+
+			"subscription, either now or at the end of the current cycle",
+		"updating",
+		[]mcpgo.ToolParameter{
+			subscriptionIDParameter("ID of the subscription to be updated"),
+			mcpgo.WithString(
+				"plan_id",
+				mcpgo.Description("ID of the new plan for the subscription"),
+			),
+			mcpgo.WithString(
+				"offer_id",
+				mcpgo.Description("ID of an offer to apply to the subscription"),
+			),
+			mcpgo.WithNumber(
+				"quantity",
+				mcpgo.Description("New number of times the plan amount is "+
+					"charged per billing cycle"),
+				mcpgo.Min(1),
+			),
+			mcpgo.WithNumber(
+				"remaining_count",
+				mcpgo.Description("New number of billing cycles remaining"),
+				mcpgo.Min(1),
+			),
+			mcpgo.WithNumber(
+				"start_at",
+				mcpgo.Description("Unix timestamp (in seconds) of the new "+
+					"first charge. Only for subscriptions not yet charged"),
+			),
+			mcpgo.WithString(
+				"schedule_change_at",
+				mcpgo.Description("When the update takes effect: 'now' or "+
+					"'cycle_end'. Defaults to 'now'"),
+				mcpgo.Enum("now", "cycle_end"),
+			),
+			mcpgo.WithBoolean(
+				"customer_notify",
+				mcpgo.Description("Whether Razorpay notifies the customer "+
+					"about the update"),
+			),
+		},
+		func(v *Validator, data map[string]interface{}) *Validator {
+			v = v.
+				ValidateAndAddOptionalString(data, "plan_id").
+				ValidateAndAddOptionalString(data, "offer_id").
+				ValidateAndAddOptionalInt(data, "quantity").
+				ValidateAndAddOptionalInt(data, "remaining_count").
+				ValidateAndAddOptionalInt(data, "start_at").
+				ValidateAndAddOptionalString(data, "schedule_change_at").
+				ValidateAndAddOptionalBool(data, "customer_notify")
+			if !v.HasErrors() && len(data) == 0 {
+				return v.addError(errors.New("no fields to update"))
+			}
+			return v
+		},
+		func(
+			client *rzpsdk.Client,
+			subscriptionID string,
+			data map[string]interface{},
+		) (map[string]interface{}, error) {
+			return client.Subscription.Update(subscriptionID, data, nil)
+		},
+	)
+}
+
+// CreateSubscriptionAddon returns a tool that adds a one-time charge to the
+// next invoice of a subscription
+func CreateSubscriptionAddon(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	return subscriptionStateTool(
+		client,
+		"create_subscription_addon",
+		"Add a one-time charge, such as a setup fee, to the next invoice of "+
+			"a subscription",
+		"creating addon for",
+		[]mcpgo.ToolParameter{
+			subscriptionIDParameter("ID of the subscription to add the charge to"),
+			mcpgo.WithString(
+				"name",
+				mcpgo.Description("Name of the charge"),
+				mcpgo.Required(),
+			),
+			mcpgo.WithNumber(
+				"amount",
+				mcpgo.Description("Amount of the charge in the smallest "+
+					"currency unit"),
+				mcpgo.Required(),
+				mcpgo.Min(100),
+			),
+			mcpgo.WithString(
+				"currency",
+				mcpgo.Description("ISO code for the currency of the charge. "+
+					"Defaults to INR"),
+			),
+			mcpgo.WithString(
+				"description",
+				mcpgo.Description("Description of the charge"),
+			),
+			mcpgo.WithNumber(
+				"quantity",
+				mcpgo.Description("Number of units of the charge. Defaults to 1"),
+				mcpgo.Min(1),
+			),
+		},
+		func(v *Validator, data map[string]interface{}) *Validator {
+			item := map[string]interface{}{"currency": "INR"}
+			data["item"] = item
+			return v.
+				ValidateAndAddRequiredString(item, "name").
+				ValidateAndAddRequiredInt(item, "amount").
+				ValidateAndAddOptionalString(item, "currency").
+				ValidateAndAddOptionalString(item, "description").
+				ValidateAndAddOptionalInt(data, "quantity")
+		},
+		func(
+			client *rzpsdk.Client,
+			subscriptionID string,
+			data map[string]interface{},
+		) (map[string]interface{}, error) {
+			return client.Subscription.CreateAddon(subscriptionID, data, nil)
+		},
+		"amount",
+	)
+}
@@ -0,0 +1,450 @@
+package razorpay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+	"github.com/razorpay/razorpay-go/constants"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay/mock"
+)
+
+var (
+	subscriptionsPath = fmt.Sprintf(
+		"/%s%s",
+		constants.VERSION_V1,
+		constants.SUBSCRIPTION_URL,
+	)
+	subscriptionPath = subscriptionsPath + "/sub_00000000000001"
+)
+
+var subscriptionResp = map[string]interface{}{
+	"id":              "sub_00000000000001",
+	"entity":          "subscription",
+	"plan_id":         "plan_00000000000001",
+	"status":          "active",
+	"quantity":        float64(1),
+	"total_count":     float64(6),
+	"paid_count":      float64(1),
+	"remaining_count": float64(5),
+	"short_url":       "https://rzp.io/i/z3b1R61A9",
+}
+
+var subscriptionErrorResp = map[string]interface{}{
+	"error": map[string]interface{}{
+		"code":        "BAD_REQUEST_ERROR",
+		"description": "The id provided does not exist",
+	},
+}
+
+// callSubscriptionTool runs a subscription tool against a server that
+// records the request body and replies with subscriptionResp
+func callSubscriptionTool(
+	t *testing.T,
+	toolFn func(*observability.Observability, *rzpsdk.Client) mcpgo.Tool,
+	args map[string]interface{},
+) (string, map[string]interface{}) {
+	var path string
+	var body map[string]interface{}
+
+	mockClient := func() (*http.Client, *httptest.Server) {
+		server := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				path = r.Method + " " + r.URL.Path
+				_ = json.NewDecoder(r.Body).Decode(&body)
+				_ = json.NewEncoder(w).Encode(subscriptionResp)
+			}))
+		return server.Client(), server
+	}
+
+	client, server := newMockRzpClient(mockClient)
+	defer server.Close()
+
+	tool := toolFn(CreateTestObservability(), client)
+	result, err := tool.GetHandler()(context.Background(),
+		createMCPRequest(args))
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Text)
+
+	return path, body
+}
+
+func Test_CreateSubscription(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful subscription creation",
+			Request: map[string]interface{}{
+				"plan_id":         "plan_00000000000001",
+				"total_count":     6,
+				"customer_notify": true,
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     subscriptionsPath,
+						Method:   "POST",
+						Response: subscriptionResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: subscriptionResp,
+		},
+		{
+			Name: "missing total_count",
+			Request: map[string]interface{}{
+				"plan_id": "plan_00000000000001",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: total_count",
+		},
+		{
+			Name: "subscription creation fails",
+			Request: map[string]interface{}{
+				"plan_id":     "plan_00000000000001",
+				"total_count": 6,
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     subscriptionsPath,
+						Method:   "POST",
+						Response: subscriptionErrorResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "creating subscription failed: " +
+				"The id provided does not exist",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, CreateSubscription, "Subscription")
+		})
+	}
+}
+
+func Test_CreateSubscriptionLink(t *testing.T) {
+	t.Run("notify info is nested", func(t *testing.T) {
+		path, body := callSubscriptionTool(t, CreateSubscriptionLink,
+			map[string]interface{}{
+				"plan_id":      "plan_00000000000001",
+				"total_count":  12,
+				"expire_by":    1893456000,
+				"notify_phone": "+919876543210",
+				"notify_email": "gaurav.kumar@example.com",
+			})
+		assert.Equal(t, "POST "+subscriptionsPath, path)
+		assert.Equal(t, map[string]interface{}{
+			"plan_id":     "plan_00000000000001",
+			"total_count": float64(12),
+			"expire_by":   float64(1893456000),
+			"notify_info": map[string]interface{}{
+				"notify_phone": "+919876543210",
+				"notify_email": "gaurav.kumar@example.com",
+			},
+		}, body)
+	})
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "missing notify info",
+			Request: map[string]interface{}{
+				"plan_id":     "plan_00000000000001",
+				"total_count": 12,
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "at least one of notify_phone or notify_email " +
+				"is required",
+		},
+		{
+			Name: "missing plan_id",
+			Request: map[string]interface{}{
+				"total_count":  12,
+				"notify_email": "gaurav.kumar@example.com",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: plan_id",
+		},
+		{
+			Name: "subscription link creation fails",
+			Request: map[string]interface{}{
+				"plan_id":      "plan_00000000000001",
+				"total_count":  12,
+				"notify_email": "gaurav.kumar@example.com",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     subscriptionsPath,
+						Method:   "POST",
+						Response: subscriptionErrorResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "creating subscription link failed: " +
+				"The id provided does not exist",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, CreateSubscriptionLink, "Subscription")
+		})
+	}
+}
+
+func Test_FetchSubscription(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful subscription fetch",
+			Request: map[string]interface{}{
+				"subscription_id": "sub_00000000000001",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     subscriptionPath,
+						Method:   "GET",
+						Response: subscriptionResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: subscriptionResp,
+		},
+		{
+			Name: "subscription not found",
+			Request: map[string]interface{}{
+				"subscription_id": "sub_00000000000001",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     subscriptionPath,
+						Method:   "GET",
+						Response: subscriptionErrorResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "fetching subscription failed: " +
+				"The id provided does not exist",
+		},
+		{
+			Name: "malformed subscription_id",
+			Request: map[string]interface{}{
+				"subscription_id": "sub_1/cancel",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid subscription_id: sub_1/cancel",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchSubscription, "Subscription")
+		})
+	}
+}
+
+func Test_FetchAllSubscriptions(t *testing.T) {
+	subscriptionsResp := map[string]interface{}{
+		"entity": "collection",
+		"count":  float64(1),
+		"items":  []interface{}{subscriptionResp},
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful subscriptions fetch",
+			Request: map[string]interface{}{
+				"plan_id": "plan_00000000000001",
+				"count":   float64(10),
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     subscriptionsPath,
+						Method:   "GET",
+						Response: subscriptionsResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: subscriptionsResp,
+		},
+		{
+			Name: "invalid from type",
+			Request: map[string]interface{}{
+				"from": "yesterday",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid parameter type: from",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchAllSubscriptions, "Subscriptions")
+		})
+	}
+}
+
+func Test_CancelSubscription(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "subscription cancellation fails",
+			Request: map[string]interface{}{
+				"subscription_id": "sub_00000000000001",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     subscriptionPath + "/cancel",
+						Method:   "POST",
+						Response: subscriptionErrorResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "cancelling subscription failed: " +
+				"The id provided does not exist",
+		},
+		{
+			Name:           "missing subscription_id",
+			Request:        map[string]interface{}{},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: subscription_id",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, CancelSubscription, "Subscription")
+		})
+	}
+
+	t.Run("cancel at cycle end", func(t *testing.T) {
+		path, body := callSubscriptionTool(t, CancelSubscription,
+			map[string]interface{}{
+				"subscription_id":     "sub_00000000000001",
+				"cancel_at_cycle_end": true,
+			})
+		assert.Equal(t, "POST "+subscriptionPath+"/cancel", path)
+		assert.Equal(t, map[string]interface{}{
+			"cancel_at_cycle_end": true,
+		}, body)
+	})
+}
+
+func Test_PauseAndResumeSubscription(t *testing.T) {
+	args := map[string]interface{}{
+		"subscription_id": "sub_00000000000001",
+	}
+
+	t.Run("pause", func(t *testing.T) {
+		path, body := callSubscriptionTool(t, PauseSubscription, args)
+		assert.Equal(t, "POST "+subscriptionPath+"/pause", path)
+		assert.Equal(t, map[string]interface{}{"pause_at": "now"}, body)
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		path, body := callSubscriptionTool(t, ResumeSubscription, args)
+		assert.Equal(t, "POST "+subscriptionPath+"/resume", path)
+		assert.Equal(t, map[string]interface{}{"resume_at": "now"}, body)
+	})
+}
+
+func Test_UpdateSubscription(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful subscription update",
+			Request: map[string]interface{}{
+				"subscription_id":    "sub_00000000000001",
+				"quantity":           2,
+				"schedule_change_at": "cycle_end",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     subscriptionPath,
+						Method:   "PATCH",
+						Response: subscriptionResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: subscriptionResp,
+		},
+		{
+			Name: "no fields to update",
+			Request: map[string]interface{}{
+				"subscription_id": "sub_00000000000001",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "no fields to update",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, UpdateSubscription, "Subscription")
+		})
+	}
+}
+
+func Test_CreateSubscriptionAddon(t *testing.T) {
+	t.Run("item is nested with default currency", func(t *testing.T) {
+		path, body := callSubscriptionTool(t, CreateSubscriptionAddon,
+			map[string]interface{}{
+				"subscription_id": "sub_00000000000001",
+				"name":            "Extra appala (papadum)",
+				"amount":          30000,
+				"quantity":        2,
+			})
+		assert.Equal(t, "POST "+subscriptionPath+"/addons", path)
+		assert.Equal(t, map[string]interface{}{
+			"item": map[string]interface{}{
+				"name":     "Extra appala (papadum)",
+				"amount":   float64(30000),
+				"currency": "INR",
+			},
+			"quantity": float64(2),
+		}, body)
+	})
+
+	tc := RazorpayToolTestCase{
+		Name: "missing amount",
+		Request: map[string]interface{}{
+			"subscription_id": "sub_00000000000001",
+			"name":            "Setup fee",
+		},
+		MockHttpClient: nil,
+		ExpectError:    true,
+		ExpectedErrMsg: "missing required parameter: amount",
+	}
+	t.Run(tc.Name, func(t *testing.T) {
+		runToolTest(t, tc, CreateSubscriptionAddon, "Subscription addon")
+	})
+}
@@ -103,7 +103,8 @@ func FetchSavedPaymentMethods(
 			" and other tokenized payment instruments.",
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(objectOutputSchema).
+		WithFieldSelection()
 }
 
 // RevokeToken returns a tool that revokes a saved payment token
@@ -198,5 +199,202 @@ func RevokeToken(
 			"Once revoked, the token cannot be used for future payments.",
 		parameters,
 		handler,
-	)
+	).WithOutputSchema(objectOutputSchema)
+}
+
+// FetchToken returns a tool that fetches a saved payment token of a
+// customer
+func FetchToken(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"customer_id",
+			mcpgo.Description("ID of the customer the token belongs to "+
+				"(ID should have a cust_ prefix)."),
+			mcpgo.Required(),
+			mcpgo.Pattern(mcpgo.IDPattern(customerIDPrefix)),
+		),
+		mcpgo.WithString(
+			"token_id",
+			mcpgo.Description("ID of the token to fetch "+
+				"(ID should have a token_ prefix)."),
+			mcpgo.Required(),
+			mcpgo.Pattern(mcpgo.IDPattern(tokenIDPrefix)),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(fields, "customer_id").
+			ValidateAndAddRequiredString(fields, "token_id")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		customerID := fields["customer_id"].(string)
+		if result := validateID("customer_id",
+			customerID, customerIDPrefix); result != nil {
+			return result, nil
+		}
+		tokenID := fields["token_id"].(string)
+		if result := validateID("token_id", tokenID, tokenIDPrefix); result != nil {
+			return result, nil
+		}
+
+		token, err := client.Token.Fetch(customerID, tokenID, nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching token failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(token)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_token",
+		"Fetch a saved payment token of a customer, such as a card or UPI "+
+			"ID, with its method, card or VPA details, expiry and status. "+
+			"Use fetch_customer_tokens to list all tokens of a customer.",
+		parameters,
+		handler,
+	).WithOutputSchema(objectOutputSchema).
+		WithFieldSelection()
+}
+
+// FetchTokenStatus returns a tool that fetches the status and card
+// properties of a network token
+func FetchTokenStatus(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"token_id",
+			mcpgo.Description("ID of the network token "+
+				"(ID should have a token_ prefix)."),
+			mcpgo.Required(),
+			mcpgo.Pattern(mcpgo.IDPattern(tokenIDPrefix)),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(fields, "token_id")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		tokenID := fields["token_id"].(string)
+		if result := validateID("token_id", tokenID, tokenIDPrefix); result != nil {
+			return result, nil
+		}
+
+		token, err := client.Token.FetchCardPropertiesByToken(
+			map[string]interface{}{"id": tokenID}, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching token status failed: %s",
+					err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(token)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_token_status",
+		"Fetch the status of a network token created with Razorpay "+
+			"Token HQ, such as active, suspended or deactivated, with its "+
+			"card properties, expiry and provisioned service providers. "+
+			"Use this to debug tokenised cards that fail to charge.",
+		parameters,
+		handler,
+	).WithOutputSchema(objectOutputSchema).
+		WithFieldSelection()
+}
+
+// FetchTokenCryptogram returns a tool that requests the service provider
+// token and cryptogram of a network token
+func FetchTokenCryptogram(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"token_id",
+			mcpgo.Description("ID of the network token "+
+				"(ID should have a token_ prefix)."),
+			mcpgo.Required(),
+			mcpgo.Pattern(mcpgo.IDPattern(tokenIDPrefix)),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(fields, "token_id")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		tokenID := fields["token_id"].(string)
+		if result := validateID("token_id", tokenID, tokenIDPrefix); result != nil {
+			return result, nil
+		}
+
+		data, err := client.Token.ProcessPaymentOnAlternatePAorPG(
+			map[string]interface{}{"id": tokenID}, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching token cryptogram failed: %s",
+					err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(data)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_token_cryptogram",
+		"Request the service provider token and a cryptogram of a network "+
+			"token created with Razorpay Token HQ, to process a payment "+
+			"with the token on another payment aggregator or gateway. "+
+			"Only available to merchants enabled for Token HQ, and the "+
+			"result holds card data that must not be shared.",
+		parameters,
+		handler,
+	).WithOutputSchema(objectOutputSchema).
+		WithFieldSelection()
 }
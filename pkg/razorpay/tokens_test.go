@@ -661,3 +661,238 @@ func Test_RevokeToken_ClientContextScenarios(t *testing.T) {
 		}
 	})
 }
+
+func Test_FetchToken(t *testing.T) {
+	tokenPath := fmt.Sprintf("/%s%s/cust_00000000000001/tokens/token_ABCDEFGH",
+		constants.VERSION_V1, constants.CUSTOMER_URL)
+
+	tokenResp := map[string]interface{}{
+		"id":     "token_ABCDEFGH",
+		"entity": "token",
+		"method": "card",
+		"card": map[string]interface{}{
+			"last4":   "1111",
+			"network": "Visa",
+		},
+		"recurring": false,
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful token fetch",
+			Request: map[string]interface{}{
+				"customer_id": "cust_00000000000001",
+				"token_id":    "token_ABCDEFGH",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     tokenPath,
+						Method:   "GET",
+						Response: tokenResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: tokenResp,
+		},
+		{
+			Name: "token not found",
+			Request: map[string]interface{}{
+				"customer_id": "cust_00000000000001",
+				"token_id":    "token_ABCDEFGH",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:   tokenPath,
+						Method: "GET",
+						Response: map[string]interface{}{
+							"error": map[string]interface{}{
+								"code":        "BAD_REQUEST_ERROR",
+								"description": "Token not found",
+							},
+						},
+					},
+				)
+			},
+			ExpectError:    true,
+			ExpectedErrMsg: "fetching token failed: Token not found",
+		},
+		{
+			Name: "missing token id",
+			Request: map[string]interface{}{
+				"customer_id": "cust_00000000000001",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: token_id",
+		},
+		{
+			Name: "invalid token id",
+			Request: map[string]interface{}{
+				"customer_id": "cust_00000000000001",
+				"token_id":    "token_1/../cancel",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid token_id: token_1/../cancel",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchToken, "Token")
+		})
+	}
+}
+
+func Test_FetchTokenStatus(t *testing.T) {
+	fetchPath := fmt.Sprintf("/%s/tokens/fetch", constants.VERSION_V1)
+
+	tokenResp := map[string]interface{}{
+		"id":     "token_4lsdksD31GaZ09",
+		"entity": "token",
+		"status": "active",
+		"card": map[string]interface{}{
+			"last4":        "1111",
+			"network":      "Visa",
+			"expiry_month": "12",
+			"expiry_year":  "2030",
+		},
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful token status fetch",
+			Request: map[string]interface{}{
+				"token_id": "token_4lsdksD31GaZ09",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     fetchPath,
+						Method:   "POST",
+						Response: tokenResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: tokenResp,
+		},
+		{
+			Name: "token not found",
+			Request: map[string]interface{}{
+				"token_id": "token_4lsdksD31GaZ09",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:   fetchPath,
+						Method: "POST",
+						Response: map[string]interface{}{
+							"error": map[string]interface{}{
+								"code":        "BAD_REQUEST_ERROR",
+								"description": "Token not found",
+							},
+						},
+					},
+				)
+			},
+			ExpectError:    true,
+			ExpectedErrMsg: "fetching token status failed: Token not found",
+		},
+		{
+			Name: "invalid token id",
+			Request: map[string]interface{}{
+				"token_id": "spt_4lsdksD31GaZ09",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid token_id: spt_4lsdksD31GaZ09",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchTokenStatus, "Token")
+		})
+	}
+}
+
+func Test_FetchTokenCryptogram(t *testing.T) {
+	cryptogramPath := fmt.Sprintf(
+		"/%s/tokens/service_provider_tokens/token_transactional_data",
+		constants.VERSION_V1)
+
+	cryptogramResp := map[string]interface{}{
+		"service_provider_tokens": []interface{}{
+			map[string]interface{}{
+				"id":             "spt_4lsdksD31GaZ09",
+				"entity":         "service_provider_token",
+				"provider_type":  "network",
+				"provider_name":  "Visa",
+				"interoperable":  true,
+				"status":         "active",
+				"provider_data":  map[string]interface{}{"cryptogram_value": "v1"},
+				"status_reasons": nil,
+			},
+		},
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful cryptogram request",
+			Request: map[string]interface{}{
+				"token_id": "token_4lsdksD31GaZ09",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     cryptogramPath,
+						Method:   "POST",
+						Response: cryptogramResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: cryptogramResp,
+		},
+		{
+			Name: "merchant not enabled",
+			Request: map[string]interface{}{
+				"token_id": "token_4lsdksD31GaZ09",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:   cryptogramPath,
+						Method: "POST",
+						Response: map[string]interface{}{
+							"error": map[string]interface{}{
+								"code":        "BAD_REQUEST_ERROR",
+								"description": "Feature not enabled",
+							},
+						},
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "fetching token cryptogram failed: " +
+				"Feature not enabled",
+		},
+		{
+			Name:           "missing token id",
+			Request:        map[string]interface{}{},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: token_id",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchTokenCryptogram, "Token")
+		})
+	}
+}
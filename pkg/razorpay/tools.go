@@ -1,6 +1,9 @@
 package razorpay
 
 import (
+	"fmt"
+	"strings"
+
 	rzpsdk "github.com/razorpay/razorpay-go"
 
 	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
@@ -20,13 +23,27 @@ func NewToolSets(
 	payments := toolsets.NewToolset("payments", "Razorpay Payments related tools").
 		AddReadTools(
 			FetchPayment(obs, client),
+			FetchPaymentTimeline(obs, client),
 			FetchPaymentCardDetails(obs, client),
 			FetchAllPayments(obs, client),
+			SearchPayments(obs, client),
+			FindPaymentByBankReference(obs, client),
+			SummarizePayments(obs, client),
+			FetchPaymentDowntimes(obs, client),
+			FetchPaymentDowntimeByID(obs, client),
+			FetchEmiPlans(obs, client),
+			LookupCardBin(obs, client),
+			ValidateVpa(obs, client),
+			WaitForPaymentStatus(obs, client),
+			SubscribeEvents(obs, client),
+			UnsubscribeEvents(obs, client),
 		).
 		AddWriteTools(
 			CapturePayment(obs, client),
+			BulkCapturePayments(obs, client),
 			UpdatePayment(obs, client),
 			InitiatePayment(obs, client),
+			GenerateOtp(obs, client),
 			ResendOtp(obs, client),
 			SubmitOtp(obs, client),
 		)
@@ -37,22 +54,28 @@ func NewToolSets(
 		AddReadTools(
 			FetchPaymentLink(obs, client),
 			FetchAllPaymentLinks(obs, client),
+			FindPaymentLinkByUPITransaction(obs, client),
 		).
 		AddWriteTools(
 			CreatePaymentLink(obs, client),
 			CreateUpiPaymentLink(obs, client),
 			ResendPaymentLinkNotification(obs, client),
 			UpdatePaymentLink(obs, client),
+			CancelPaymentLink(obs, client),
 		)
 
 	orders := toolsets.NewToolset("orders", "Razorpay Orders related tools").
 		AddReadTools(
 			FetchOrder(obs, client),
 			FetchAllOrders(obs, client),
+			FetchOrderByReceipt(obs, client),
 			FetchOrderPayments(obs, client),
+			FetchOffer(obs, client),
+			FetchAllOffers(obs, client),
 		).
 		AddWriteTools(
 			CreateOrder(obs, client),
+			CreateOrdersBatch(obs, client),
 			UpdateOrder(obs, client),
 		)
 
@@ -62,9 +85,12 @@ func NewToolSets(
 			FetchMultipleRefundsForPayment(obs, client),
 			FetchSpecificRefundForPayment(obs, client),
 			FetchAllRefunds(obs, client),
+			SummarizeRefunds(obs, client),
+			WaitForRefundCompletion(obs, client),
 		).
 		AddWriteTools(
 			CreateRefund(obs, client),
+			CreateBulkRefunds(obs, client),
 			UpdateRefund(obs, client),
 		)
 
@@ -72,6 +98,16 @@ func NewToolSets(
 		AddReadTools(
 			FetchPayout(obs, client),
 			FetchAllPayouts(obs, client),
+			FetchPayoutLink(obs, client),
+			FetchBankingBalance(obs, client),
+			FetchTransactions(obs, client),
+		).
+		AddWriteTools(
+			CreateContact(obs, client),
+			CreatePayout(obs, client),
+			CancelQueuedPayout(obs, client),
+			CreatePayoutLink(obs, client),
+			CancelPayoutLink(obs, client),
 		)
 
 	qrCodes := toolsets.NewToolset("qr_codes", "Razorpay QR Codes related tools").
@@ -81,6 +117,7 @@ func NewToolSets(
 			FetchQRCodesByCustomerID(obs, client),
 			FetchQRCodesByPaymentID(obs, client),
 			FetchPaymentsForQRCode(obs, client),
+			GetQRCodeImage(obs, client),
 		).
 		AddWriteTools(
 			CreateQRCode(obs, client),
@@ -92,6 +129,9 @@ func NewToolSets(
 		AddReadTools(
 			FetchSettlement(obs, client),
 			FetchSettlementRecon(obs, client),
+			ExportSettlementReconCSV(obs, client),
+			ReconcilePeriod(obs, client),
+			ForecastSettlements(obs, client),
 			FetchAllSettlements(obs, client),
 			FetchAllInstantSettlements(obs, client),
 			FetchInstantSettlement(obs, client),
@@ -100,9 +140,161 @@ func NewToolSets(
 			CreateInstantSettlement(obs, client),
 		)
 
-	// Add the single custom tool to an existing toolset
-	payments.AddReadTools(FetchSavedPaymentMethods(obs, client)).
-		AddWriteTools(RevokeToken(obs, client))
+	disputes := toolsets.NewToolset("disputes",
+		"Razorpay Disputes related tools").
+		AddReadTools(
+			FetchDispute(obs, client),
+			FetchAllDisputes(obs, client),
+			FetchDisputeDocument(obs, client),
+		).
+		AddWriteTools(
+			AcceptDispute(obs, client),
+			ContestDispute(obs, client),
+			UploadDocument(obs, client),
+		)
+
+	invoices := toolsets.NewToolset("invoices",
+		"Razorpay Invoices related tools").
+		AddReadTools(
+			FetchInvoice(obs, client),
+			FetchAllInvoices(obs, client),
+			FetchInvoicePdf(obs, client),
+		).
+		AddWriteTools(
+			CreateInvoice(obs, client),
+			IssueInvoice(obs, client),
+			CancelInvoice(obs, client),
+			NotifyInvoice(obs, client),
+		)
+
+	subscriptions := toolsets.NewToolset("subscriptions",
+		"Razorpay Subscriptions related tools").
+		AddReadTools(
+			FetchSubscription(obs, client),
+			FetchAllSubscriptions(obs, client),
+			FetchPlan(obs, client),
+			FetchAllPlans(obs, client),
+		).
+		AddWriteTools(
+			CreateSubscription(obs, client),
+			CreateSubscriptionLink(obs, client),
+			CancelSubscription(obs, client),
+			PauseSubscription(obs, client),
+			ResumeSubscription(obs, client),
+			UpdateSubscription(obs, client),
+			CreateSubscriptionAddon(obs, client),
+			CreatePlan(obs, client),
+		)
+
+	virtualAccounts := toolsets.NewToolset("virtual_accounts",
+		"Razorpay Smart Collect virtual account related tools").
+		AddReadTools(
+			FetchVirtualAccount(obs, client),
+			FetchAllVirtualAccounts(obs, client),
+			FetchVirtualAccountPayments(obs, client),
+		).
+		AddWriteTools(
+			CreateVirtualAccount(obs, client),
+			CloseVirtualAccount(obs, client),
+		)
+
+	transfers := toolsets.NewToolset("transfers",
+		"Razorpay Route transfers related tools").
+		AddReadTools(
+			FetchTransfer(obs, client),
+			FetchAllTransfers(obs, client),
+			FetchPaymentTransfers(obs, client),
+			FetchLinkedAccountSettlements(obs, client),
+		).
+		AddWriteTools(
+			CreatePaymentTransfer(obs, client),
+			CreateTransfer(obs, client),
+			ReverseTransfer(obs, client),
+		)
+
+	items := toolsets.NewToolset("items", "Razorpay Items related tools").
+		AddReadTools(
+			FetchItem(obs, client),
+			FetchAllItems(obs, client),
+		).
+		AddWriteTools(
+			CreateItem(obs, client),
+			UpdateItem(obs, client),
+			DeleteItem(obs, client),
+		)
+
+	customers := toolsets.NewToolset("customers",
+		"Razorpay Customers related tools").
+		AddReadTools(
+			FetchCustomer(obs, client),
+			FetchAllCustomers(obs, client),
+		).
+		AddWriteTools(
+			CreateCustomer(obs, client),
+			EditCustomer(obs, client),
+		)
+
+	fundAccounts := toolsets.NewToolset("fund_accounts",
+		"Razorpay Fund Accounts and bank account validation related tools").
+		AddReadTools(
+			FetchAllFundAccounts(obs, client),
+			FetchBankAccountValidation(obs, client),
+		).
+		AddWriteTools(
+			CreateFundAccount(obs, client),
+			ValidateBankAccount(obs, client),
+		)
+
+	webhooks := toolsets.NewToolset("webhooks",
+		"Razorpay Webhooks related tools").
+		AddReadTools(
+			FetchWebhook(obs, client),
+			FetchAllWebhooks(obs, client),
+			FetchRecentEvents(obs, client),
+			WaitForEvent(obs, client),
+		).
+		AddWriteTools(
+			CreateWebhook(obs, client),
+			UpdateWebhook(obs, client),
+			DeleteWebhook(obs, client),
+		)
+
+	subMerchants := toolsets.NewToolset("sub_merchants",
+		"Razorpay Partner sub-merchant onboarding related tools").
+		AddReadTools(
+			FetchAccount(obs, client),
+			FetchAllStakeholders(obs, client),
+			FetchProductConfiguration(obs, client),
+		).
+		AddWriteTools(
+			CreateAccount(obs, client),
+			UpdateAccount(obs, client),
+			CreateStakeholder(obs, client),
+			RequestProductConfiguration(obs, client),
+			UpdateProductConfiguration(obs, client),
+		)
+
+	// Add the saved payment method and recurring payment tools to the
+	// payments toolset
+	payments.AddReadTools(
+		FetchSavedPaymentMethods(obs, client),
+		FetchCustomerTokens(obs, client),
+		FetchToken(obs, client),
+		FetchTokenStatus(obs, client),
+	).
+		AddWriteTools(
+			RevokeToken(obs, client),
+			FetchTokenCryptogram(obs, client),
+			CreateUpiAutopayRegistrationLink(obs, client),
+			CreateRecurringPayment(obs, client),
+		)
+
+	// Add the note tools, which also change the notes of orders, refunds
+	// and payment links, to the payments toolset
+	payments.AddWriteTools(
+		AddEntityNote(obs, client),
+		RemoveEntityNote(obs, client),
+	)
 
 	// Add toolsets to the group
 	toolsetGroup.AddToolset(payments)
@@ -112,8 +304,26 @@ func NewToolSets(
 	toolsetGroup.AddToolset(payouts)
 	toolsetGroup.AddToolset(qrCodes)
 	toolsetGroup.AddToolset(settlements)
+	toolsetGroup.AddToolset(disputes)
+	toolsetGroup.AddToolset(webhooks)
+	toolsetGroup.AddToolset(invoices)
+	toolsetGroup.AddToolset(subscriptions)
+	toolsetGroup.AddToolset(virtualAccounts)
+	toolsetGroup.AddToolset(transfers)
+	toolsetGroup.AddToolset(items)
+	toolsetGroup.AddToolset(customers)
+	toolsetGroup.AddToolset(fundAccounts)
+	toolsetGroup.AddToolset(subMerchants)
 
 	// Enable the requested features
+	for _, name := range enabledToolsets {
+		if _, exists := toolsetGroup.Toolsets[name]; !exists {
+			return nil, fmt.Errorf("toolset %s does not exist, available "+
+				"toolsets are %s, and available profiles are %s", name,
+				strings.Join(sortedKeys(toolsetGroup.Toolsets), ", "),
+				describeProfiles())
+		}
+	}
 	if err := toolsetGroup.EnableToolsets(enabledToolsets); err != nil {
 		return nil, err
 	}
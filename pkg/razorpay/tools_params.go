@@ -3,6 +3,7 @@ package razorpay
 import (
 	"encoding/json"
 	"errors"
+	"sort"
 	"strings"
 	"time"
 
@@ -16,12 +17,48 @@ type Validator struct {
 	errors  []error
 }
 
-// NewValidator creates a new validator for the given request
+// NewValidator creates a new validator for the given request. For strict
+// requests, any argument not declared by the tool is recorded as an error.
 func NewValidator(r *mcpgo.CallToolRequest) *Validator {
-	return &Validator{
+	v := &Validator{
 		request: r,
 		errors:  []error{},
 	}
+	if r.Strict {
+		v.validateNoUnknownParameters()
+	}
+	return v
+}
+
+// validateNoUnknownParameters records an error for every argument key
+// that is not one of the tool's declared parameters
+func (v *Validator) validateNoUnknownParameters() {
+	args, ok := v.request.Arguments.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	known := make(map[string]bool, len(v.request.Parameters)+5)
+	known[mcpgo.StrictParameter] = true
+	known[mcpgo.DryRunParameter] = true
+	known[mcpgo.IdempotencyKeyParameter] = true
+	known[mcpgo.AccountParameter] = true
+	known[mcpgo.MerchantAccountParameter] = true
+	for _, name := range v.request.Parameters {
+		known[name] = true
+	}
+
+	unknown := make([]string, 0)
+	for name := range args {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+
+	for _, name := range unknown {
+		v.addError(errors.New("unknown parameter: " + name))
+	}
 }
 
 // addError adds a non-nil error to the collection
@@ -45,7 +82,7 @@ func (v *Validator) HandleErrorsIfAny() (*mcpgo.ToolResult, error) {
 			messages = append(messages, err.Error())
 		}
 		errorMsg := "Validation errors:\n- " + strings.Join(messages, "\n- ")
-		return mcpgo.NewToolResultError(errorMsg), nil
+		return mcpgo.NewToolResultValidationError(errorMsg, ""), nil
 	}
 	return nil, nil
 }
@@ -222,6 +259,27 @@ func (v *Validator) ValidateAndAddOptionalMap(
 	return validateAndAddOptional[map[string]interface{}](v, params, name)
 }
 
+// ValidateAndAddRequiredBase64 validates a required base64 encoded
+// parameter of at most maxBytes bytes, and adds its decoded content
+func (v *Validator) ValidateAndAddRequiredBase64(
+	params map[string]interface{},
+	name string,
+	maxBytes int,
+) *Validator {
+	value, err := extractValueGeneric[string](v.request, name, true)
+	if err != nil {
+		return v.addError(err)
+	}
+
+	data, err := mcpgo.DecodeBase64(*value, maxBytes)
+	if err != nil {
+		return v.addError(errors.New("invalid " + name + ": " + err.Error()))
+	}
+
+	params[name] = data
+	return v
+}
+
 // ValidateAndAddRequiredArray validates and adds a required array parameter
 func (v *Validator) ValidateAndAddRequiredArray(
 	params map[string]interface{},
@@ -985,6 +985,51 @@ func TestValidateAndAddPagination(t *testing.T) {
 	})
 }
 
+// Test for ValidateAndAddRequiredBase64 function
+func TestValidateAndAddRequiredBase64(t *testing.T) {
+	t.Run("decodes content", func(t *testing.T) {
+		request := &mcpgo.CallToolRequest{
+			Arguments: map[string]interface{}{"file": "aGVsbG8="},
+		}
+
+		params := make(map[string]interface{})
+		validator := NewValidator(request).
+			ValidateAndAddRequiredBase64(params, "file", 10)
+
+		assert.False(t, validator.HasErrors())
+		assert.Equal(t, []byte("hello"), params["file"])
+	})
+
+	t.Run("missing content", func(t *testing.T) {
+		request := &mcpgo.CallToolRequest{
+			Arguments: map[string]interface{}{},
+		}
+
+		params := make(map[string]interface{})
+		validator := NewValidator(request).
+			ValidateAndAddRequiredBase64(params, "file", 10)
+
+		assert.True(t, validator.HasErrors())
+		assert.Empty(t, params)
+	})
+
+	t.Run("content too large", func(t *testing.T) {
+		request := &mcpgo.CallToolRequest{
+			Arguments: map[string]interface{}{"file": "aGVsbG8="},
+		}
+
+		params := make(map[string]interface{})
+		validator := NewValidator(request).
+			ValidateAndAddRequiredBase64(params, "file", 4)
+
+		result, err := validator.HandleErrorsIfAny()
+		assert.NoError(t, err)
+		assert.Contains(t, result.Text,
+			"invalid file: content is larger than 4 bytes")
+		assert.Empty(t, params)
+	})
+}
+
 // Test for ValidateAndAddExpand function
 func TestValidateAndAddExpand(t *testing.T) {
 	t.Run("valid expand parameter", func(t *testing.T) {
@@ -1126,3 +1171,43 @@ func TestValidateAndAddTokenEdgeCases(t *testing.T) {
 		assert.Empty(t, params)
 	})
 }
+
+func TestValidatorStrictParams(t *testing.T) {
+	args := map[string]interface{}{
+		"payment_id": "pay_123",
+		"amuont":     1000,
+		"strict":     true,
+	}
+
+	t.Run("unknown parameter rejected in strict mode", func(t *testing.T) {
+		request := &mcpgo.CallToolRequest{
+			Arguments:  args,
+			Parameters: []string{"payment_id", "amount"},
+			Strict:     true,
+		}
+		params := make(map[string]interface{})
+
+		validator := NewValidator(request).
+			ValidateAndAddRequiredString(params, "payment_id")
+
+		result, err := validator.HandleErrorsIfAny()
+		assert.NoError(t, err)
+		assert.NotNil(t, result)
+		assert.Equal(t,
+			"Validation errors:\n- unknown parameter: amuont", result.Text)
+	})
+
+	t.Run("unknown parameter ignored otherwise", func(t *testing.T) {
+		request := &mcpgo.CallToolRequest{
+			Arguments:  args,
+			Parameters: []string{"payment_id", "amount"},
+		}
+		params := make(map[string]interface{})
+
+		validator := NewValidator(request).
+			ValidateAndAddRequiredString(params, "payment_id")
+
+		assert.False(t, validator.HasErrors())
+		assert.Equal(t, "pay_123", params["payment_id"])
+	})
+}
@@ -1,6 +1,7 @@
 package razorpay
 
 import (
+	"strings"
 	"testing"
 
 	rzpsdk "github.com/razorpay/razorpay-go"
@@ -58,7 +59,10 @@ func testCreateAllToolsets(t *testing.T, obs *observability.Observability,
 
 	expectedToolsets := []string{
 		"payments", "payment_links", "orders",
-		"refunds", "payouts", "qr_codes", "settlements",
+		"refunds", "payouts", "qr_codes", "settlements", "disputes",
+		"webhooks", "invoices", "subscriptions", "virtual_accounts",
+		"transfers", "items", "customers", "fund_accounts",
+		"sub_merchants",
 	}
 
 	for _, name := range expectedToolsets {
@@ -133,8 +137,9 @@ func testInvalidToolsetName(t *testing.T, obs *observability.Observability,
 		t.Fatal("Expected error for invalid toolset name")
 	}
 
-	expectedError := "toolset invalid_toolset does not exist"
-	if err.Error() != expectedError {
+	expectedError := "toolset invalid_toolset does not exist, available " +
+		"toolsets are customers"
+	if !strings.HasPrefix(err.Error(), expectedError) {
 		t.Errorf("Expected error '%s', got '%s'", expectedError, err.Error())
 	}
 }
@@ -147,8 +152,9 @@ func testMixedValidInvalidToolsets(t *testing.T,
 		t.Fatal("Expected error for invalid toolset name")
 	}
 
-	expectedError := "toolset invalid_toolset does not exist"
-	if err.Error() != expectedError {
+	expectedError := "toolset invalid_toolset does not exist, available " +
+		"toolsets are customers"
+	if !strings.HasPrefix(err.Error(), expectedError) {
 		t.Errorf("Expected error '%s', got '%s'", expectedError, err.Error())
 	}
 }
@@ -0,0 +1,27 @@
+package razorpay
+
+import (
+	"context"
+	"net/http"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+)
+
+// withTracing returns a copy of the client whose requests are traced as
+// children of the span in ctx, or the client itself if ctx has no span.
+// The SDK sends requests without a context, so the copy binds its
+// transport to ctx.
+func withTracing(ctx context.Context, client *rzpsdk.Client) *rzpsdk.Client {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return client
+	}
+
+	return withTransport(client, func(
+		next http.RoundTripper,
+	) http.RoundTripper {
+		return observability.TracingTransport(ctx, next)
+	})
+}
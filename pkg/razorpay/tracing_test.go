@@ -0,0 +1,53 @@
+package razorpay
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+)
+
+func TestWithTracing(t *testing.T) {
+	t.Run("returns the client for untraced calls", func(t *testing.T) {
+		client := rzpsdk.NewClient("test-key", "test-secret")
+
+		assert.Same(t, client, withTracing(context.Background(), client))
+	})
+
+	t.Run("traces requests of traced calls", func(t *testing.T) {
+		traceparents := make(chan string, 1)
+		server := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				traceparents <- r.Header.Get("Traceparent")
+				_, _ = w.Write([]byte(`{"id":"pay_1"}`))
+			}))
+		defer server.Close()
+
+		recorder := tracetest.NewSpanRecorder()
+		tracer := sdktrace.NewTracerProvider(
+			sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+		ctx, span := tracer.Start(context.Background(), "tools/call")
+
+		client := rzpsdk.NewClient("test-key", "test-secret")
+		client.Request.BaseURL = server.URL
+		traced := withTracing(ctx, client)
+
+		payment, err := traced.Payment.Fetch("pay_1", nil, nil)
+		require.NoError(t, err)
+		span.End()
+
+		assert.Equal(t, "pay_1", payment["id"])
+		assert.NotSame(t, client.Request, traced.Request)
+		assert.Equal(t, client.Request.Auth, traced.Request.Auth)
+		require.Len(t, recorder.Ended(), 2)
+		assert.Contains(t, <-traceparents,
+			span.SpanContext().TraceID().String())
+	})
+}
@@ -0,0 +1,177 @@
+package razorpay
+
+import (
+	"context"
+	"fmt"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+	"github.com/razorpay/razorpay-go/constants"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+)
+
+// transactionsPath is the path of the RazorpayX Transactions API, the
+// account statement, which the SDK does not cover
+var transactionsPath = fmt.Sprintf("/%s/transactions", constants.VERSION_V1)
+
+// fetchTransactions fetches a page of the account statement
+func fetchTransactions(client *rzpsdk.Client) pageFetcher {
+	return func(
+		queryParams map[string]interface{},
+		extraHeaders map[string]string,
+	) (map[string]interface{}, error) {
+		return client.Request.Get(transactionsPath, queryParams, extraHeaders)
+	}
+}
+
+// FetchBankingBalance returns a tool that fetches the current balance of a
+// RazorpayX account
+func FetchBankingBalance(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"account_number",
+			mcpgo.Description("The RazorpayX account number. For example, "+
+				"7878780080316316"),
+			mcpgo.Required(),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		queryParams := map[string]interface{}{"count": 1}
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(queryParams, "account_number")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		// Every transaction carries the balance of the account after it,
+		// so the latest one holds the current balance
+		transactions, err := fetchTransactions(client)(queryParams, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching balance failed: %s", err.Error())), nil
+		}
+
+		items, _ := transactions["items"].([]interface{})
+		if len(items) == 0 {
+			return mcpgo.NewToolResultError(
+				"fetching balance failed: the account has no transactions"), nil
+		}
+		latest, _ := items[0].(map[string]interface{})
+
+		return mcpgo.NewToolResultJSON(map[string]interface{}{
+			"account_number": queryParams["account_number"],
+			"balance":        latest["balance"],
+			"currency":       latest["currency"],
+			"as_of":          latest["created_at"],
+			"transaction_id": latest["id"],
+		})
+	}
+
+	return mcpgo.NewTool(
+		"fetch_banking_balance",
+		"Fetch the current balance of a RazorpayX account in paise, as of "+
+			"its latest transaction",
+		parameters,
+		handler,
+	).WithOutputSchema(objectOutputSchema).
+		WithFieldSelection()
+}
+
+// FetchTransactions returns a tool that fetches the account statement of a
+// RazorpayX account
+func FetchTransactions(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := append([]mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"account_number",
+			mcpgo.Description("The RazorpayX account number. For example, "+
+				"7878780080316316"),
+			mcpgo.Required(),
+		),
+		mcpgo.WithNumber(
+			"from",
+			mcpgo.Description("Unix timestamp (in seconds) from when "+
+				"transactions are to be fetched"),
+			mcpgo.Min(0),
+		),
+		mcpgo.WithNumber(
+			"to",
+			mcpgo.Description("Unix timestamp (in seconds) up till when "+
+				"transactions are to be fetched"),
+			mcpgo.Min(0),
+		),
+		mcpgo.WithNumber(
+			"count",
+			mcpgo.Description("Number of transactions to fetch "+
+				"(default: 10, max: 100)"),
+			mcpgo.Min(1),
+			mcpgo.Max(100),
+		),
+		mcpgo.WithNumber(
+			"skip",
+			mcpgo.Description("Number of transactions to skip (default: 0)"),
+			mcpgo.Min(0),
+		),
+	}, collectionParameters()...)
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		queryParams := make(map[string]interface{})
+		pagination := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(queryParams, "account_number").
+			ValidateAndAddOptionalInt(queryParams, "from").
+			ValidateAndAddOptionalInt(queryParams, "to").
+			ValidateAndAddPagination(queryParams).
+			ValidateAndAddCollectionOptions(pagination)
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		transactions, err := fetchCollection(queryParams, pagination,
+			fetchTransactions(client))
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching transactions failed: %s",
+					err.Error())), nil
+		}
+
+		return collectionResult(ctx, transactions, pagination)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_transactions",
+		"Fetch the account statement of a RazorpayX account: its "+
+			"transactions, newest first, with the amount, fees and the "+
+			"balance after each transaction",
+		parameters,
+		handler,
+	).WithOutputSchema(entitiesOutputSchema).
+		WithFieldSelection()
+}
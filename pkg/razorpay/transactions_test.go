@@ -0,0 +1,194 @@
+package razorpay
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay/mock"
+)
+
+var transactionsResp = map[string]interface{}{
+	"entity": "collection",
+	"count":  float64(2),
+	"items": []interface{}{
+		map[string]interface{}{
+			"id":             "txn_00000000000002",
+			"entity":         "transaction",
+			"account_number": "7878780080316316",
+			"amount":         float64(10000),
+			"currency":       "INR",
+			"debit":          float64(10000),
+			"credit":         float64(0),
+			"balance":        float64(990000),
+			"created_at":     float64(1705343399),
+		},
+		map[string]interface{}{
+			"id":             "txn_00000000000001",
+			"entity":         "transaction",
+			"account_number": "7878780080316316",
+			"amount":         float64(1000000),
+			"currency":       "INR",
+			"debit":          float64(0),
+			"credit":         float64(1000000),
+			"balance":        float64(1000000),
+			"created_at":     float64(1705257000),
+		},
+	},
+}
+
+func Test_FetchBankingBalance(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "balance of the latest transaction",
+			Request: map[string]interface{}{
+				"account_number": "7878780080316316",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     transactionsPath,
+						Method:   "GET",
+						Response: transactionsResp,
+					},
+				)
+			},
+			ExpectError: false,
+			ExpectedResult: map[string]interface{}{
+				"account_number": "7878780080316316",
+				"balance":        float64(990000),
+				"currency":       "INR",
+				"as_of":          float64(1705343399),
+				"transaction_id": "txn_00000000000002",
+			},
+		},
+		{
+			Name: "account without transactions",
+			Request: map[string]interface{}{
+				"account_number": "7878780080316316",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:   transactionsPath,
+						Method: "GET",
+						Response: map[string]interface{}{
+							"entity": "collection",
+							"count":  float64(0),
+							"items":  []interface{}{},
+						},
+					},
+				)
+			},
+			ExpectError:    true,
+			ExpectedErrMsg: "the account has no transactions",
+		},
+		{
+			Name:           "missing account number",
+			Request:        map[string]interface{}{},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: account_number",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchBankingBalance, "Balance")
+		})
+	}
+}
+
+func Test_FetchTransactions(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "statement for a date range",
+			Request: map[string]interface{}{
+				"account_number": "7878780080316316",
+				"from":           float64(1705257000),
+				"to":             float64(1705343399),
+				"count":          float64(10),
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     transactionsPath,
+						Method:   "GET",
+						Response: transactionsResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: transactionsResp,
+		},
+		{
+			Name: "api error",
+			Request: map[string]interface{}{
+				"account_number": "7878780080316316",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:   transactionsPath,
+						Method: "GET",
+						Response: map[string]interface{}{
+							"error": map[string]interface{}{
+								"code":        "BAD_REQUEST_ERROR",
+								"description": "invalid account number",
+							},
+						},
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "fetching transactions failed: invalid account " +
+				"number",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchTransactions, "Transactions")
+		})
+	}
+
+	t.Run("forwards the filters", func(t *testing.T) {
+		var query map[string]string
+		mockClient := func() (*http.Client, *httptest.Server) {
+			server := httptest.NewServer(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					query = map[string]string{
+						"account_number": r.URL.Query().Get("account_number"),
+						"from":           r.URL.Query().Get("from"),
+						"to":             r.URL.Query().Get("to"),
+					}
+					_ = json.NewEncoder(w).Encode(transactionsResp)
+				}))
+			return server.Client(), server
+		}
+
+		client, server := newMockRzpClient(mockClient)
+		defer server.Close()
+
+		tool := FetchTransactions(CreateTestObservability(), client)
+		result, err := tool.GetHandler()(context.Background(),
+			createMCPRequest(map[string]interface{}{
+				"account_number": "7878780080316316",
+				"from":           float64(1705257000),
+				"to":             float64(1705343399),
+			}))
+		require.NoError(t, err)
+		require.False(t, result.IsError, result.Text)
+
+		assert.Equal(t, map[string]string{
+			"account_number": "7878780080316316",
+			"from":           "1705257000",
+			"to":             "1705343399",
+		}, query)
+	})
+}
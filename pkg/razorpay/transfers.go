@@ -0,0 +1,498 @@
+package razorpay
+
+import (
+	"context"
+	"fmt"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+)
+
+// transferIDParameter returns the transfer_id parameter shared by the
+// tools that operate on a single transfer
+func transferIDParameter(description string) mcpgo.ToolParameter {
+	return mcpgo.WithString(
+		"transfer_id",
+		mcpgo.Description(description+" (ID should have a trf_ prefix)."),
+		mcpgo.Required(),
+		mcpgo.Pattern(mcpgo.IDPattern(transferIDPrefix)),
+	)
+}
+
+// transferListParameters returns the date range and pagination parameters
+// shared by the tools that list transfers
+func transferListParameters(entity string) []mcpgo.ToolParameter {
+	return []mcpgo.ToolParameter{
+		mcpgo.WithNumber(
+			"from",
+			mcpgo.Description("Unix timestamp (in seconds) from when "+
+				entity+" are to be fetched"),
+			mcpgo.Min(0),
+		),
+		mcpgo.WithNumber(
+			"to",
+			mcpgo.Description("Unix timestamp (in seconds) up till when "+
+				entity+" are to be fetched"),
+			mcpgo.Min(0),
+		),
+		mcpgo.WithNumber(
+			"count",
+			mcpgo.Description("Number of "+entity+" to be fetched. "+
+				"Default value is 10. Maximum value is 100"),
+			mcpgo.Min(1),
+			mcpgo.Max(100),
+		),
+		mcpgo.WithNumber(
+			"skip",
+			mcpgo.Description("Number of "+entity+" to be skipped. "+
+				"Default value is 0. This can be used for pagination"),
+			mcpgo.Min(0),
+		),
+	}
+}
+
+// CreatePaymentTransfer returns a tool that splits a captured payment
+// between linked accounts
+func CreatePaymentTransfer(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"payment_id",
+			mcpgo.Description("ID of the captured payment to transfer "+
+				"funds from (ID should have a pay_ prefix)."),
+			mcpgo.Required(),
+		),
+		mcpgo.WithArray(
+			"transfers",
+			mcpgo.Description("Transfers to create, one per linked account"),
+			mcpgo.Required(),
+			mcpgo.Items(map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"account": map[string]interface{}{
+						"type": "string",
+						"description": "ID of the linked account " +
+							"(ID should have an acc_ prefix)",
+					},
+					"amount": map[string]interface{}{
+						"type": "number",
+						"description": "Amount to transfer in the smallest " +
+							"currency unit",
+					},
+					"currency": map[string]interface{}{
+						"type":        "string",
+						"description": "ISO code for the currency",
+					},
+					"on_hold": map[string]interface{}{
+						"type": "boolean",
+						"description": "Whether the settlement of the " +
+							"transfer to the linked account is put on hold",
+					},
+					"notes": map[string]interface{}{
+						"type":        "object",
+						"description": "Key-value pairs for the transfer",
+					},
+				},
+				"required": []string{"account", "amount", "currency"},
+			}),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+		transferData := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(fields, "payment_id").
+			ValidateAndAddRequiredArray(transferData, "transfers")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		paymentID := fields["payment_id"].(string)
+
+		transfers, err := client.Payment.Transfer(paymentID, transferData, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("creating transfers failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(transfers)
+	}
+
+	return mcpgo.NewTool(
+		"create_payment_transfer",
+		"Split a captured payment by transferring parts of it to one or "+
+			"more linked accounts using Route",
+		parameters,
+		handler,
+	).WithOutputSchema(entitiesOutputSchema)
+}
+
+// CreateTransfer returns a tool that transfers funds from the merchant's
+// account balance directly to a linked account
+func CreateTransfer(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"account",
+			mcpgo.Description("ID of the linked account to transfer to "+
+				"(ID should have an acc_ prefix)."),
+			mcpgo.Required(),
+		),
+		mcpgo.WithNumber(
+			"amount",
+			mcpgo.Description("Amount to transfer in the smallest "+
+				"currency unit"),
+			mcpgo.Required(),
+			mcpgo.Min(100),
+		),
+		mcpgo.WithString(
+			"currency",
+			mcpgo.Description("ISO code for the currency. Only INR is "+
+				"supported"),
+			mcpgo.Required(),
+		),
+		mcpgo.WithObject(
+			"notes",
+			mcpgo.Description("Key-value pairs for additional information "+
+				"(max 15 pairs, 256 chars each)"),
+			mcpgo.MaxProperties(15),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		transferData := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(transferData, "account").
+			ValidateAndAddRequiredInt(transferData, "amount").
+			ValidateAndAddRequiredString(transferData, "currency").
+			ValidateAndAddOptionalMap(transferData, "notes")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		transfer, err := client.Transfer.Create(transferData, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("creating transfer failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(transfer)
+	}
+
+	return mcpgo.NewTool(
+		"create_transfer",
+		"Transfer funds from the merchant's account balance directly to a "+
+			"linked account, without an associated payment",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema).WithAmounts("amount")
+}
+
+// FetchTransfer returns a tool that fetches a transfer by its ID
+func FetchTransfer(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		transferIDParameter("ID of the transfer to be fetched"),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(fields, "transfer_id")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		transferID := fields["transfer_id"].(string)
+		if result := validateID("transfer_id",
+			transferID, transferIDPrefix); result != nil {
+			return result, nil
+		}
+
+		transfer, err := client.Transfer.Fetch(transferID, nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching transfer failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(transfer)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_transfer",
+		"Fetch the details of a transfer, including the linked account, "+
+			"amount and settlement status",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema).
+		WithFieldSelection()
+}
+
+// FetchAllTransfers returns a tool that fetches all transfers
+func FetchAllTransfers(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := append(transferListParameters("transfers"),
+		collectionParameters()...)
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		queryParams := make(map[string]interface{})
+		pagination := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddOptionalInt(queryParams, "from").
+			ValidateAndAddOptionalInt(queryParams, "to").
+			ValidateAndAddPagination(queryParams).
+			ValidateAndAddCollectionOptions(pagination)
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		transfers, err := fetchCollection(queryParams, pagination,
+			client.Transfer.All)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching transfers failed: %s", err.Error())), nil
+		}
+
+		return collectionResult(ctx, transfers, pagination)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_all_transfers",
+		"Fetch all transfers with optional filtering by creation date",
+		parameters,
+		handler,
+	).WithOutputSchema(entitiesOutputSchema).
+		WithFieldSelection()
+}
+
+// FetchPaymentTransfers returns a tool that fetches the transfers created
+// from a payment
+func FetchPaymentTransfers(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"payment_id",
+			mcpgo.Description("ID of the payment whose transfers are to be "+
+				"fetched (ID should have a pay_ prefix)."),
+			mcpgo.Required(),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(fields, "payment_id")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		paymentID := fields["payment_id"].(string)
+
+		transfers, err := client.Payment.Transfers(paymentID, nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching payment transfers failed: %s",
+					err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(transfers)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_payment_transfers",
+		"Fetch the transfers created from a payment",
+		parameters,
+		handler,
+	).WithOutputSchema(entitiesOutputSchema).
+		WithFieldSelection()
+}
+
+// ReverseTransfer returns a tool that reverses a transfer, fully or
+// partially, from the linked account back to the merchant
+func ReverseTransfer(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		transferIDParameter("ID of the transfer to be reversed"),
+		mcpgo.WithNumber(
+			"amount",
+			mcpgo.Description("Amount to reverse in the smallest currency "+
+				"unit. Defaults to the full transfer amount"),
+			mcpgo.Min(100),
+		),
+		mcpgo.WithObject(
+			"notes",
+			mcpgo.Description("Key-value pairs for additional information "+
+				"(max 15 pairs, 256 chars each)"),
+			mcpgo.MaxProperties(15),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+		reversalData := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(fields, "transfer_id").
+			ValidateAndAddOptionalInt(reversalData, "amount").
+			ValidateAndAddOptionalMap(reversalData, "notes")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		transferID := fields["transfer_id"].(string)
+		if result := validateID("transfer_id",
+			transferID, transferIDPrefix); result != nil {
+			return result, nil
+		}
+
+		reversal, err := client.Transfer.Reverse(transferID, reversalData, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("reversing transfer failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(reversal)
+	}
+
+	return mcpgo.NewTool(
+		"reverse_transfer",
+		"Reverse a transfer, fully or partially, moving the funds from the "+
+			"linked account back to the merchant's account",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema).WithAmounts("amount")
+}
+
+// FetchLinkedAccountSettlements returns a tool that fetches transfers
+// together with the settlements made to the linked accounts
+func FetchLinkedAccountSettlements(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := append([]mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"recipient_settlement_id",
+			mcpgo.Description("Optional: Only fetch the transfers settled "+
+				"to a linked account in this settlement (ID should have a "+
+				"setl_ prefix)"),
+		),
+	}, transferListParameters("transfers")...)
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		queryParams := map[string]interface{}{
+			"expand[]": "recipient_settlement",
+		}
+
+		validator := NewValidator(&r).
+			ValidateAndAddOptionalString(queryParams,
+				"recipient_settlement_id").
+			ValidateAndAddOptionalInt(queryParams, "from").
+			ValidateAndAddOptionalInt(queryParams, "to").
+			ValidateAndAddPagination(queryParams)
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		transfers, err := client.Transfer.All(queryParams, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching linked account settlements failed: %s",
+					err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(transfers)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_linked_account_settlements",
+		"Fetch transfers along with the settlement each was paid out in to "+
+			"its linked account. Filter by recipient_settlement_id to list "+
+			"the transfers included in one linked account settlement.",
+		parameters,
+		handler,
+	).WithOutputSchema(entitiesOutputSchema).
+		WithFieldSelection()
+}
@@ -0,0 +1,389 @@
+package razorpay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/razorpay/razorpay-go/constants"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay/mock"
+)
+
+var (
+	transfersPath = fmt.Sprintf(
+		"/%s%s",
+		constants.VERSION_V1,
+		constants.TRANSFER_URL,
+	)
+	transferPath         = transfersPath + "/trf_E9uhYLFLLZ2pks"
+	paymentTransfersPath = fmt.Sprintf(
+		"/%s%s/pay_E8JR8E0XyjUSZd/transfers",
+		constants.VERSION_V1,
+		constants.PAYMENT_URL,
+	)
+)
+
+var transferResp = map[string]interface{}{
+	"id":        "trf_E9uhYLFLLZ2pks",
+	"entity":    "transfer",
+	"source":    "pay_E8JR8E0XyjUSZd",
+	"recipient": "acc_CPRsN1LkFccllA",
+	"amount":    float64(100),
+	"currency":  "INR",
+	"on_hold":   false,
+}
+
+var transfersResp = map[string]interface{}{
+	"entity": "collection",
+	"count":  float64(1),
+	"items":  []interface{}{transferResp},
+}
+
+var transferErrorResp = map[string]interface{}{
+	"error": map[string]interface{}{
+		"code":        "BAD_REQUEST_ERROR",
+		"description": "The id provided does not exist",
+	},
+}
+
+func Test_CreatePaymentTransfer(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful payment transfer",
+			Request: map[string]interface{}{
+				"payment_id": "pay_E8JR8E0XyjUSZd",
+				"transfers": []interface{}{
+					map[string]interface{}{
+						"account":  "acc_CPRsN1LkFccllA",
+						"amount":   100,
+						"currency": "INR",
+					},
+				},
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     paymentTransfersPath,
+						Method:   "POST",
+						Response: transfersResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: transfersResp,
+		},
+		{
+			Name: "missing transfers",
+			Request: map[string]interface{}{
+				"payment_id": "pay_E8JR8E0XyjUSZd",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: transfers",
+		},
+		{
+			Name: "payment transfer fails",
+			Request: map[string]interface{}{
+				"payment_id": "pay_E8JR8E0XyjUSZd",
+				"transfers":  []interface{}{},
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     paymentTransfersPath,
+						Method:   "POST",
+						Response: transferErrorResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "creating transfers failed: " +
+				"The id provided does not exist",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, CreatePaymentTransfer, "Transfers")
+		})
+	}
+}
+
+func Test_CreateTransfer(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful direct transfer",
+			Request: map[string]interface{}{
+				"account":  "acc_CPRsN1LkFccllA",
+				"amount":   100,
+				"currency": "INR",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     transfersPath,
+						Method:   "POST",
+						Response: transferResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: transferResp,
+		},
+		{
+			Name: "missing currency",
+			Request: map[string]interface{}{
+				"account": "acc_CPRsN1LkFccllA",
+				"amount":  100,
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: currency",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, CreateTransfer, "Transfer")
+		})
+	}
+}
+
+func Test_FetchTransfer(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful transfer fetch",
+			Request: map[string]interface{}{
+				"transfer_id": "trf_E9uhYLFLLZ2pks",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     transferPath,
+						Method:   "GET",
+						Response: transferResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: transferResp,
+		},
+		{
+			Name: "transfer not found",
+			Request: map[string]interface{}{
+				"transfer_id": "trf_E9uhYLFLLZ2pks",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     transferPath,
+						Method:   "GET",
+						Response: transferErrorResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "fetching transfer failed: " +
+				"The id provided does not exist",
+		},
+		{
+			Name: "malformed transfer_id",
+			Request: map[string]interface{}{
+				"transfer_id": "trf_1/reversals",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid transfer_id: trf_1/reversals",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchTransfer, "Transfer")
+		})
+	}
+}
+
+func Test_FetchAllTransfers(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful transfers fetch",
+			Request: map[string]interface{}{
+				"count": float64(10),
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     transfersPath,
+						Method:   "GET",
+						Response: transfersResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: transfersResp,
+		},
+		{
+			Name: "invalid skip type",
+			Request: map[string]interface{}{
+				"skip": "none",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid parameter type: skip",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchAllTransfers, "Transfers")
+		})
+	}
+}
+
+func Test_FetchPaymentTransfers(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful payment transfers fetch",
+			Request: map[string]interface{}{
+				"payment_id": "pay_E8JR8E0XyjUSZd",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     paymentTransfersPath,
+						Method:   "GET",
+						Response: transfersResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: transfersResp,
+		},
+		{
+			Name:           "missing payment_id",
+			Request:        map[string]interface{}{},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: payment_id",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchPaymentTransfers, "Transfers")
+		})
+	}
+}
+
+func Test_ReverseTransfer(t *testing.T) {
+	reversalResp := map[string]interface{}{
+		"id":       "rvrsl_EB0BWgGDAu7tOz",
+		"entity":   "reversal",
+		"transfer": "trf_E9uhYLFLLZ2pks",
+		"amount":   float64(100),
+		"currency": "INR",
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful transfer reversal",
+			Request: map[string]interface{}{
+				"transfer_id": "trf_E9uhYLFLLZ2pks",
+				"amount":      100,
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     transferPath + "/reversals",
+						Method:   "POST",
+						Response: reversalResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: reversalResp,
+		},
+		{
+			Name: "transfer reversal fails",
+			Request: map[string]interface{}{
+				"transfer_id": "trf_E9uhYLFLLZ2pks",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     transferPath + "/reversals",
+						Method:   "POST",
+						Response: transferErrorResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "reversing transfer failed: " +
+				"The id provided does not exist",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, ReverseTransfer, "Reversal")
+		})
+	}
+}
+
+func Test_FetchLinkedAccountSettlements(t *testing.T) {
+	t.Run("recipient settlement is expanded", func(t *testing.T) {
+		var query map[string][]string
+
+		mockClient := func() (*http.Client, *httptest.Server) {
+			server := httptest.NewServer(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					query = r.URL.Query()
+					_ = json.NewEncoder(w).Encode(transfersResp)
+				}))
+			return server.Client(), server
+		}
+
+		client, server := newMockRzpClient(mockClient)
+		defer server.Close()
+
+		tool := FetchLinkedAccountSettlements(CreateTestObservability(), client)
+		result, err := tool.GetHandler()(context.Background(),
+			createMCPRequest(map[string]interface{}{
+				"recipient_settlement_id": "setl_DHYJ3dRPqQkAgV",
+			}))
+		require.NoError(t, err)
+		require.False(t, result.IsError, result.Text)
+
+		assert.Equal(t, []string{"recipient_settlement"}, query["expand[]"])
+		assert.Equal(t, []string{"setl_DHYJ3dRPqQkAgV"},
+			query["recipient_settlement_id"])
+	})
+
+	tc := RazorpayToolTestCase{
+		Name:    "linked account settlements fetch fails",
+		Request: map[string]interface{}{},
+		MockHttpClient: func() (*http.Client, *httptest.Server) {
+			return mock.NewHTTPClient(
+				mock.Endpoint{
+					Path:     transfersPath,
+					Method:   "GET",
+					Response: transferErrorResp,
+				},
+			)
+		},
+		ExpectError: true,
+		ExpectedErrMsg: "fetching linked account settlements failed: " +
+			"The id provided does not exist",
+	}
+	t.Run(tc.Name, func(t *testing.T) {
+		runToolTest(t, tc, FetchLinkedAccountSettlements, "Transfers")
+	})
+}
@@ -0,0 +1,415 @@
+package razorpay
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+)
+
+// virtualAccountReceiverTypes are the receivers a virtual account can have
+var virtualAccountReceiverTypes = []string{"bank_account", "vpa"}
+
+// virtualAccountIDParameter returns the virtual_account_id parameter shared
+// by the tools that operate on a single virtual account
+func virtualAccountIDParameter(description string) mcpgo.ToolParameter {
+	return mcpgo.WithString(
+		"virtual_account_id",
+		mcpgo.Description(description+" (ID should have a va_ prefix)."),
+		mcpgo.Required(),
+		mcpgo.Pattern(mcpgo.IDPattern(virtualAccountIDPrefix)),
+	)
+}
+
+// validateVirtualAccountID validates the virtual_account_id parameter of a
+// request and returns it
+func validateVirtualAccountID(
+	r *mcpgo.CallToolRequest,
+) (string, *mcpgo.ToolResult, error) {
+	fields := make(map[string]interface{})
+
+	validator := NewValidator(r).
+		ValidateAndAddRequiredString(fields, "virtual_account_id")
+
+	if result, err := validator.HandleErrorsIfAny(); result != nil {
+		return "", result, err
+	}
+
+	virtualAccountID := fields["virtual_account_id"].(string)
+	if result := validateID("virtual_account_id",
+		virtualAccountID, virtualAccountIDPrefix); result != nil {
+		return "", result, nil
+	}
+
+	return virtualAccountID, nil, nil
+}
+
+// CreateVirtualAccount returns a tool that creates a Smart Collect virtual
+// account with bank account and/or VPA receivers
+func CreateVirtualAccount(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		mcpgo.WithArray(
+			"receiver_types",
+			mcpgo.Description("Receivers customers can pay into: "+
+				"'bank_account', 'vpa' or both"),
+			mcpgo.Required(),
+			mcpgo.Items(map[string]interface{}{
+				"type": "string",
+				"enum": virtualAccountReceiverTypes,
+			}),
+		),
+		mcpgo.WithString(
+			"vpa_descriptor",
+			mcpgo.Description("Optional: Descriptor used in the VPA of the "+
+				"virtual account. Only applies to 'vpa' receivers"),
+		),
+		mcpgo.WithString(
+			"description",
+			mcpgo.Description("Description of the virtual account"),
+		),
+		mcpgo.WithString(
+			"customer_id",
+			mcpgo.Description("ID of the customer the virtual account is "+
+				"created for"),
+		),
+		mcpgo.WithNumber(
+			"close_by",
+			mcpgo.Description("Unix timestamp (in seconds) at which the "+
+				"virtual account is closed automatically"),
+		),
+		mcpgo.WithNumber(
+			"amount_expected",
+			mcpgo.Description("Amount in the smallest currency unit the "+
+				"virtual account expects to receive"),
+			mcpgo.Min(100),
+		),
+		mcpgo.WithObject(
+			"notes",
+			mcpgo.Description("Key-value pairs for additional information "+
+				"(max 15 pairs, 256 chars each)"),
+			mcpgo.MaxProperties(15),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+		virtualAccountData := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredArray(fields, "receiver_types").
+			ValidateAndAddOptionalString(fields, "vpa_descriptor").
+			ValidateAndAddOptionalString(virtualAccountData, "description").
+			ValidateAndAddOptionalString(virtualAccountData, "customer_id").
+			ValidateAndAddOptionalInt(virtualAccountData, "close_by").
+			ValidateAndAddOptionalInt(virtualAccountData, "amount_expected").
+			ValidateAndAddOptionalMap(virtualAccountData, "notes")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		types := fields["receiver_types"].([]interface{})
+		if len(types) == 0 {
+			return mcpgo.NewToolResultError(
+				"receiver_types must contain at least one receiver"), nil
+		}
+		for _, receiverType := range types {
+			name, ok := receiverType.(string)
+			if !ok || !slices.Contains(virtualAccountReceiverTypes, name) {
+				return mcpgo.NewToolResultError(fmt.Sprintf(
+					"invalid receiver type: %v", receiverType)), nil
+			}
+		}
+
+		receivers := map[string]interface{}{"types": types}
+		if descriptor, ok := fields["vpa_descriptor"]; ok {
+			receivers["vpa"] = map[string]interface{}{
+				"descriptor": descriptor,
+			}
+		}
+		virtualAccountData["receivers"] = receivers
+
+		virtualAccount, err := client.VirtualAccount.Create(
+			virtualAccountData, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("creating virtual account failed: %s",
+					err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(virtualAccount)
+	}
+
+	return mcpgo.NewTool(
+		"create_virtual_account",
+		"Create a Smart Collect virtual account that customers can pay "+
+			"into by bank transfer and/or UPI. The response contains the "+
+			"account number, IFSC and VPA to share with the customer.",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema).WithAmounts("amount_expected")
+}
+
+// FetchVirtualAccount returns a tool that fetches a virtual account by its
+// ID
+func FetchVirtualAccount(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		virtualAccountIDParameter("ID of the virtual account to be fetched"),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		virtualAccountID, result, err := validateVirtualAccountID(&r)
+		if result != nil {
+			return result, err
+		}
+
+		virtualAccount, err := client.VirtualAccount.Fetch(
+			virtualAccountID, nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching virtual account failed: %s",
+					err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(virtualAccount)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_virtual_account",
+		"Fetch the details of a virtual account, including its receivers, "+
+			"status and amount paid",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema).
+		WithFieldSelection()
+}
+
+// FetchAllVirtualAccounts returns a tool that fetches all virtual accounts
+func FetchAllVirtualAccounts(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := append([]mcpgo.ToolParameter{
+		mcpgo.WithNumber(
+			"from",
+			mcpgo.Description("Unix timestamp (in seconds) from when "+
+				"virtual accounts are to be fetched"),
+			mcpgo.Min(0),
+		),
+		mcpgo.WithNumber(
+			"to",
+			mcpgo.Description("Unix timestamp (in seconds) up till when "+
+				"virtual accounts are to be fetched"),
+			mcpgo.Min(0),
+		),
+		mcpgo.WithNumber(
+			"count",
+			mcpgo.Description("Number of virtual accounts to be fetched. "+
+				"Default value is 10. Maximum value is 100"),
+			mcpgo.Min(1),
+			mcpgo.Max(100),
+		),
+		mcpgo.WithNumber(
+			"skip",
+			mcpgo.Description("Number of virtual accounts to be skipped. "+
+				"Default value is 0. This can be used for pagination"),
+			mcpgo.Min(0),
+		),
+	}, collectionParameters()...)
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		queryParams := make(map[string]interface{})
+		pagination := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddOptionalInt(queryParams, "from").
+			ValidateAndAddOptionalInt(queryParams, "to").
+			ValidateAndAddPagination(queryParams).
+			ValidateAndAddCollectionOptions(pagination)
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		virtualAccounts, err := fetchCollection(queryParams, pagination,
+			client.VirtualAccount.All)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching virtual accounts failed: %s",
+					err.Error())), nil
+		}
+
+		return collectionResult(ctx, virtualAccounts, pagination)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_all_virtual_accounts",
+		"Fetch all virtual accounts with optional filtering by creation date",
+		parameters,
+		handler,
+	).WithOutputSchema(entitiesOutputSchema).
+		WithFieldSelection()
+}
+
+// CloseVirtualAccount returns a tool that closes a virtual account
+func CloseVirtualAccount(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		virtualAccountIDParameter("ID of the virtual account to be closed"),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		virtualAccountID, result, err := validateVirtualAccountID(&r)
+		if result != nil {
+			return result, err
+		}
+
+		virtualAccount, err := client.VirtualAccount.Close(
+			virtualAccountID, nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("closing virtual account failed: %s",
+					err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(virtualAccount)
+	}
+
+	return mcpgo.NewTool(
+		"close_virtual_account",
+		"Close a virtual account so that it stops accepting payments. A "+
+			"closed virtual account cannot be reopened.",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema)
+}
+
+// FetchVirtualAccountPayments returns a tool that lists the payments made
+// into a virtual account
+func FetchVirtualAccountPayments(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		virtualAccountIDParameter("ID of the virtual account whose payments " +
+			"are to be fetched"),
+		mcpgo.WithNumber(
+			"from",
+			mcpgo.Description("Unix timestamp (in seconds) from when "+
+				"payments are to be fetched"),
+			mcpgo.Min(0),
+		),
+		mcpgo.WithNumber(
+			"to",
+			mcpgo.Description("Unix timestamp (in seconds) up till when "+
+				"payments are to be fetched"),
+			mcpgo.Min(0),
+		),
+		mcpgo.WithNumber(
+			"count",
+			mcpgo.Description("Number of payments to be fetched. "+
+				"Default value is 10. Maximum value is 100"),
+			mcpgo.Min(1),
+			mcpgo.Max(100),
+		),
+		mcpgo.WithNumber(
+			"skip",
+			mcpgo.Description("Number of payments to be skipped. "+
+				"Default value is 0. This can be used for pagination"),
+			mcpgo.Min(0),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+		queryParams := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(fields, "virtual_account_id").
+			ValidateAndAddOptionalInt(queryParams, "from").
+			ValidateAndAddOptionalInt(queryParams, "to").
+			ValidateAndAddPagination(queryParams)
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		virtualAccountID := fields["virtual_account_id"].(string)
+		if result := validateID("virtual_account_id",
+			virtualAccountID, virtualAccountIDPrefix); result != nil {
+			return result, nil
+		}
+
+		payments, err := client.VirtualAccount.Payments(
+			virtualAccountID, queryParams, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching virtual account payments failed: %s",
+					err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(payments)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_virtual_account_payments",
+		"Fetch the payments made into a virtual account. Use this to "+
+			"reconcile bank transfers and UPI payments received through "+
+			"Smart Collect.",
+		parameters,
+		handler,
+	).WithOutputSchema(paymentsOutputSchema).
+		WithFieldSelection()
+}
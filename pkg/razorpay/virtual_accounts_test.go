@@ -0,0 +1,355 @@
+package razorpay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/razorpay/razorpay-go/constants"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay/mock"
+)
+
+var (
+	virtualAccountsPath = fmt.Sprintf(
+		"/%s%s",
+		constants.VERSION_V1,
+		constants.VIRTUAL_ACCOUNT_URL,
+	)
+	virtualAccountPath = virtualAccountsPath + "/va_DlGmm7jInLudH9"
+)
+
+var virtualAccountResp = map[string]interface{}{
+	"id":          "va_DlGmm7jInLudH9",
+	"entity":      "virtual_account",
+	"status":      "active",
+	"description": "Virtual Account for Raftar Soft",
+	"amount_paid": float64(0),
+	"customer_id": "cust_CaVDm8eDRSXYME",
+	"receivers": []interface{}{
+		map[string]interface{}{
+			"id":             "ba_DlGmm9mSj8fjRM",
+			"entity":         "bank_account",
+			"ifsc":           "RATN0VAAPIS",
+			"account_number": "2223330099089860",
+		},
+	},
+}
+
+var virtualAccountErrorResp = map[string]interface{}{
+	"error": map[string]interface{}{
+		"code":        "BAD_REQUEST_ERROR",
+		"description": "The id provided does not exist",
+	},
+}
+
+func Test_CreateVirtualAccount(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful virtual account creation",
+			Request: map[string]interface{}{
+				"receiver_types": []interface{}{"bank_account"},
+				"description":    "Virtual Account for Raftar Soft",
+				"customer_id":    "cust_CaVDm8eDRSXYME",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     virtualAccountsPath,
+						Method:   "POST",
+						Response: virtualAccountResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: virtualAccountResp,
+		},
+		{
+			Name:           "missing receiver_types",
+			Request:        map[string]interface{}{},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: receiver_types",
+		},
+		{
+			Name: "empty receiver_types",
+			Request: map[string]interface{}{
+				"receiver_types": []interface{}{},
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "receiver_types must contain at least one receiver",
+		},
+		{
+			Name: "unsupported receiver type",
+			Request: map[string]interface{}{
+				"receiver_types": []interface{}{"qr_code"},
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid receiver type: qr_code",
+		},
+		{
+			Name: "virtual account creation fails",
+			Request: map[string]interface{}{
+				"receiver_types": []interface{}{"vpa"},
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     virtualAccountsPath,
+						Method:   "POST",
+						Response: virtualAccountErrorResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "creating virtual account failed: " +
+				"The id provided does not exist",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, CreateVirtualAccount, "Virtual account")
+		})
+	}
+
+	t.Run("receivers are nested with the VPA descriptor", func(t *testing.T) {
+		var body map[string]interface{}
+
+		mockClient := func() (*http.Client, *httptest.Server) {
+			server := httptest.NewServer(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					_ = json.NewDecoder(r.Body).Decode(&body)
+					_ = json.NewEncoder(w).Encode(virtualAccountResp)
+				}))
+			return server.Client(), server
+		}
+
+		client, server := newMockRzpClient(mockClient)
+		defer server.Close()
+
+		tool := CreateVirtualAccount(CreateTestObservability(), client)
+		result, err := tool.GetHandler()(context.Background(),
+			createMCPRequest(map[string]interface{}{
+				"receiver_types": []interface{}{"bank_account", "vpa"},
+				"vpa_descriptor": "gauravkumar",
+			}))
+		require.NoError(t, err)
+		require.False(t, result.IsError, result.Text)
+
+		assert.Equal(t, map[string]interface{}{
+			"types": []interface{}{"bank_account", "vpa"},
+			"vpa": map[string]interface{}{
+				"descriptor": "gauravkumar",
+			},
+		}, body["receivers"])
+	})
+}
+
+func Test_FetchVirtualAccount(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful virtual account fetch",
+			Request: map[string]interface{}{
+				"virtual_account_id": "va_DlGmm7jInLudH9",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     virtualAccountPath,
+						Method:   "GET",
+						Response: virtualAccountResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: virtualAccountResp,
+		},
+		{
+			Name: "virtual account not found",
+			Request: map[string]interface{}{
+				"virtual_account_id": "va_DlGmm7jInLudH9",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     virtualAccountPath,
+						Method:   "GET",
+						Response: virtualAccountErrorResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "fetching virtual account failed: " +
+				"The id provided does not exist",
+		},
+		{
+			Name: "malformed virtual_account_id",
+			Request: map[string]interface{}{
+				"virtual_account_id": "va_1/close",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid virtual_account_id: va_1/close",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchVirtualAccount, "Virtual account")
+		})
+	}
+}
+
+func Test_FetchAllVirtualAccounts(t *testing.T) {
+	virtualAccountsResp := map[string]interface{}{
+		"entity": "collection",
+		"count":  float64(1),
+		"items":  []interface{}{virtualAccountResp},
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful virtual accounts fetch",
+			Request: map[string]interface{}{
+				"count": float64(10),
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     virtualAccountsPath,
+						Method:   "GET",
+						Response: virtualAccountsResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: virtualAccountsResp,
+		},
+		{
+			Name: "invalid count type",
+			Request: map[string]interface{}{
+				"count": "ten",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid parameter type: count",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchAllVirtualAccounts, "Virtual accounts")
+		})
+	}
+}
+
+func Test_CloseVirtualAccount(t *testing.T) {
+	closedResp := map[string]interface{}{
+		"id":     "va_DlGmm7jInLudH9",
+		"entity": "virtual_account",
+		"status": "closed",
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful virtual account closure",
+			Request: map[string]interface{}{
+				"virtual_account_id": "va_DlGmm7jInLudH9",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     virtualAccountPath + "/close",
+						Method:   "POST",
+						Response: closedResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: closedResp,
+		},
+		{
+			Name:           "missing virtual_account_id",
+			Request:        map[string]interface{}{},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: virtual_account_id",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, CloseVirtualAccount, "Virtual account")
+		})
+	}
+}
+
+func Test_FetchVirtualAccountPayments(t *testing.T) {
+	paymentsResp := map[string]interface{}{
+		"entity": "collection",
+		"count":  float64(1),
+		"items": []interface{}{
+			map[string]interface{}{
+				"id":     "pay_Di5iqCqA1WEHq6",
+				"entity": "payment",
+				"amount": float64(10000),
+				"method": "bank_transfer",
+				"status": "captured",
+			},
+		},
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful payments fetch",
+			Request: map[string]interface{}{
+				"virtual_account_id": "va_DlGmm7jInLudH9",
+				"count":              float64(10),
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     virtualAccountPath + "/payments",
+						Method:   "GET",
+						Response: paymentsResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: paymentsResp,
+		},
+		{
+			Name: "payments fetch fails",
+			Request: map[string]interface{}{
+				"virtual_account_id": "va_DlGmm7jInLudH9",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     virtualAccountPath + "/payments",
+						Method:   "GET",
+						Response: virtualAccountErrorResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "fetching virtual account payments failed: " +
+				"The id provided does not exist",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchVirtualAccountPayments, "Payments")
+		})
+	}
+}
@@ -0,0 +1,406 @@
+package razorpay
+
+import (
+	"context"
+	"fmt"
+
+	rzpsdk "github.com/razorpay/razorpay-go"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
+	"github.com/razorpay/razorpay-mcp-server/pkg/observability"
+)
+
+// webhookAccountIDParameter returns the account_id parameter shared by all
+// webhook tools
+func webhookAccountIDParameter() mcpgo.ToolParameter {
+	return mcpgo.WithString(
+		"account_id",
+		mcpgo.Description("Unique identifier of the account the webhook "+
+			"belongs to. Must start with 'acc_'"),
+		mcpgo.Required(),
+	)
+}
+
+// webhookIDParameter returns the webhook_id parameter shared by the tools
+// that operate on a single webhook
+func webhookIDParameter() mcpgo.ToolParameter {
+	return mcpgo.WithString(
+		"webhook_id",
+		mcpgo.Description("Unique identifier of the webhook"),
+		mcpgo.Required(),
+	)
+}
+
+// webhookEventsParameter returns the events parameter used when creating
+// or updating a webhook
+func webhookEventsParameter() mcpgo.ToolParameter {
+	return mcpgo.WithArray(
+		"events",
+		mcpgo.Description("Events the webhook subscribes to, for example "+
+			"['payment.authorized', 'payment.failed', 'refund.created']"),
+		mcpgo.Required(),
+		mcpgo.Min(1),
+		mcpgo.Items(map[string]interface{}{
+			"type": "string",
+		}),
+	)
+}
+
+// CreateWebhook returns a tool that creates a webhook for an account
+func CreateWebhook(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		webhookAccountIDParameter(),
+		mcpgo.WithString(
+			"url",
+			mcpgo.Description("URL to which the webhook events are delivered. "+
+				"Must be a publicly reachable HTTPS URL"),
+			mcpgo.Required(),
+		),
+		webhookEventsParameter(),
+		mcpgo.WithString(
+			"alert_email",
+			mcpgo.Description("Email address notified when webhook "+
+				"deliveries fail"),
+		),
+		mcpgo.WithString(
+			"secret",
+			mcpgo.Description("Secret used to sign the webhook payloads, "+
+				"for validating that events are sent by Razorpay"),
+		),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+		webhookData := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(fields, "account_id").
+			ValidateAndAddRequiredString(webhookData, "url").
+			ValidateAndAddRequiredArray(webhookData, "events").
+			ValidateAndAddOptionalString(webhookData, "alert_email").
+			ValidateAndAddOptionalString(webhookData, "secret")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		if result := validateID("account_id", fields["account_id"].(string),
+			accountIDPrefix); result != nil {
+			return result, nil
+		}
+
+		webhook, err := client.Webhook.Create(
+			fields["account_id"].(string), webhookData, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("creating webhook failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(webhook)
+	}
+
+	return mcpgo.NewTool(
+		"create_webhook",
+		"Create a webhook for an account, subscribing the given URL to "+
+			"the listed events",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema)
+}
+
+// FetchWebhook returns a tool that fetches a webhook by its ID
+func FetchWebhook(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		webhookAccountIDParameter(),
+		webhookIDParameter(),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(fields, "account_id").
+			ValidateAndAddRequiredString(fields, "webhook_id")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		if result := validateID("account_id", fields["account_id"].(string),
+			accountIDPrefix); result != nil {
+			return result, nil
+		}
+
+		if result := validateID("webhook_id", fields["webhook_id"].(string),
+			webhookIDPrefix); result != nil {
+			return result, nil
+		}
+
+		webhook, err := client.Webhook.Fetch(
+			fields["webhook_id"].(string),
+			fields["account_id"].(string),
+			nil,
+			nil,
+		)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching webhook failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(webhook)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_webhook",
+		"Fetch the details of a webhook, including its URL and the events "+
+			"it is subscribed to",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema).
+		WithFieldSelection()
+}
+
+// FetchAllWebhooks returns a tool that fetches all webhooks of an account
+func FetchAllWebhooks(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := append([]mcpgo.ToolParameter{
+		webhookAccountIDParameter(),
+		mcpgo.WithNumber(
+			"from",
+			mcpgo.Description("Unix timestamp (in seconds) from when "+
+				"webhooks are to be fetched"),
+			mcpgo.Min(0),
+		),
+		mcpgo.WithNumber(
+			"to",
+			mcpgo.Description("Unix timestamp (in seconds) up till when "+
+				"webhooks are to be fetched"),
+			mcpgo.Min(0),
+		),
+		mcpgo.WithNumber(
+			"count",
+			mcpgo.Description("Number of webhooks to be fetched. "+
+				"Default value is 10. Maximum value is 100"),
+			mcpgo.Min(1),
+			mcpgo.Max(100),
+		),
+		mcpgo.WithNumber(
+			"skip",
+			mcpgo.Description("Number of webhooks to be skipped. "+
+				"Default value is 0. This can be used for pagination"),
+			mcpgo.Min(0),
+		),
+	}, collectionParameters()...)
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+		queryParams := make(map[string]interface{})
+		pagination := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(fields, "account_id").
+			ValidateAndAddOptionalInt(queryParams, "from").
+			ValidateAndAddOptionalInt(queryParams, "to").
+			ValidateAndAddPagination(queryParams).
+			ValidateAndAddCollectionOptions(pagination)
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		accountID := fields["account_id"].(string)
+
+		if result := validateID("account_id", accountID,
+			accountIDPrefix); result != nil {
+			return result, nil
+		}
+
+		webhooks, err := fetchCollection(queryParams, pagination,
+			func(
+				queryParams map[string]interface{},
+				extraHeaders map[string]string,
+			) (map[string]interface{}, error) {
+				return client.Webhook.All(accountID, queryParams, extraHeaders)
+			})
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("fetching webhooks failed: %s", err.Error())), nil
+		}
+
+		return collectionResult(ctx, webhooks, pagination)
+	}
+
+	return mcpgo.NewTool(
+		"fetch_all_webhooks",
+		"Fetch all webhooks configured for an account",
+		parameters,
+		handler,
+	).WithOutputSchema(entitiesOutputSchema).
+		WithFieldSelection()
+}
+
+// UpdateWebhook returns a tool that updates the URL and events of a webhook
+func UpdateWebhook(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		webhookAccountIDParameter(),
+		webhookIDParameter(),
+		mcpgo.WithString(
+			"url",
+			mcpgo.Description("New URL to which the webhook events are "+
+				"delivered"),
+			mcpgo.Required(),
+		),
+		webhookEventsParameter(),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+		webhookData := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(fields, "account_id").
+			ValidateAndAddRequiredString(fields, "webhook_id").
+			ValidateAndAddRequiredString(webhookData, "url").
+			ValidateAndAddRequiredArray(webhookData, "events")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		if result := validateID("account_id", fields["account_id"].(string),
+			accountIDPrefix); result != nil {
+			return result, nil
+		}
+
+		if result := validateID("webhook_id", fields["webhook_id"].(string),
+			webhookIDPrefix); result != nil {
+			return result, nil
+		}
+
+		webhook, err := client.Webhook.Edit(
+			fields["webhook_id"].(string),
+			fields["account_id"].(string),
+			webhookData,
+			nil,
+		)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("updating webhook failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(webhook)
+	}
+
+	return mcpgo.NewTool(
+		"update_webhook",
+		"Update the URL and the subscribed events of a webhook. The events "+
+			"passed replace the existing subscription",
+		parameters,
+		handler,
+	).WithOutputSchema(entityOutputSchema)
+}
+
+// DeleteWebhook returns a tool that deletes a webhook
+func DeleteWebhook(
+	obs *observability.Observability,
+	client *rzpsdk.Client,
+) mcpgo.Tool {
+	parameters := []mcpgo.ToolParameter{
+		webhookAccountIDParameter(),
+		webhookIDParameter(),
+	}
+
+	handler := func(
+		ctx context.Context,
+		r mcpgo.CallToolRequest,
+	) (*mcpgo.ToolResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return mcpgo.NewToolResultError(err.Error()), nil
+		}
+
+		fields := make(map[string]interface{})
+
+		validator := NewValidator(&r).
+			ValidateAndAddRequiredString(fields, "account_id").
+			ValidateAndAddRequiredString(fields, "webhook_id")
+
+		if result, err := validator.HandleErrorsIfAny(); result != nil {
+			return result, err
+		}
+
+		webhookID := fields["webhook_id"].(string)
+
+		if result := validateID("account_id", fields["account_id"].(string),
+			accountIDPrefix); result != nil {
+			return result, nil
+		}
+
+		if result := validateID("webhook_id", webhookID,
+			webhookIDPrefix); result != nil {
+			return result, nil
+		}
+
+		_, err = client.Webhook.Delete(
+			webhookID, fields["account_id"].(string), nil, nil)
+		if err != nil {
+			return mcpgo.NewToolResultError(
+				fmt.Sprintf("deleting webhook failed: %s", err.Error())), nil
+		}
+
+		return mcpgo.NewToolResultJSON(map[string]interface{}{
+			"webhook_id": webhookID,
+			"deleted":    true,
+		})
+	}
+
+	return mcpgo.NewTool(
+		"delete_webhook",
+		"Delete a webhook so that its URL stops receiving events",
+		parameters,
+		handler,
+	).WithOutputSchema(objectOutputSchema)
+}
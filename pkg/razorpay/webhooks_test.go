@@ -0,0 +1,327 @@
+package razorpay
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/razorpay/razorpay-go/constants"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/razorpay/mock"
+)
+
+var (
+	webhooksPath = fmt.Sprintf(
+		"/%s%s/acc_GRWKk7qQsLnDjX%s",
+		constants.VERSION_V2,
+		constants.ACCOUNT_URL,
+		constants.WEBHOOK,
+	)
+	webhookPath = webhooksPath + "/HK890egfiItP3H"
+)
+
+var webhookResp = map[string]interface{}{
+	"id":            "HK890egfiItP3H",
+	"created_at":    float64(1623060358),
+	"updated_at":    float64(1623067148),
+	"service":       "beta-api-test",
+	"owner_id":      "H3kYHQ635sBwXG",
+	"owner_type":    "merchant",
+	"context":       []interface{}{},
+	"disabled_at":   float64(0),
+	"url":           "https://en1mwkqo5ioct.x.pipedream.net",
+	"alert_email":   "gaurav.kumar@example.com",
+	"secret_exists": true,
+	"entity":        "webhook",
+	"active":        true,
+	"events": []interface{}{
+		"payment.authorized",
+		"payment.failed",
+	},
+}
+
+var webhookErrorResp = map[string]interface{}{
+	"error": map[string]interface{}{
+		"code":        "BAD_REQUEST_ERROR",
+		"description": "The id provided does not exist",
+	},
+}
+
+func Test_CreateWebhook(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful webhook creation",
+			Request: map[string]interface{}{
+				"account_id":  "acc_GRWKk7qQsLnDjX",
+				"url":         "https://en1mwkqo5ioct.x.pipedream.net",
+				"alert_email": "gaurav.kumar@example.com",
+				"secret":      "12345",
+				"events": []interface{}{
+					"payment.authorized",
+					"payment.failed",
+				},
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     webhooksPath,
+						Method:   "POST",
+						Response: webhookResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: webhookResp,
+		},
+		{
+			Name: "webhook creation fails",
+			Request: map[string]interface{}{
+				"account_id": "acc_GRWKk7qQsLnDjX",
+				"url":        "https://en1mwkqo5ioct.x.pipedream.net",
+				"events":     []interface{}{"payment.authorized"},
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     webhooksPath,
+						Method:   "POST",
+						Response: webhookErrorResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "creating webhook failed: " +
+				"The id provided does not exist",
+		},
+		{
+			Name: "missing url and events",
+			Request: map[string]interface{}{
+				"account_id": "acc_GRWKk7qQsLnDjX",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: url",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, CreateWebhook, "Webhook")
+		})
+	}
+}
+
+func Test_FetchWebhook(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful webhook fetch",
+			Request: map[string]interface{}{
+				"account_id": "acc_GRWKk7qQsLnDjX",
+				"webhook_id": "HK890egfiItP3H",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     webhookPath,
+						Method:   "GET",
+						Response: webhookResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: webhookResp,
+		},
+		{
+			Name: "webhook not found",
+			Request: map[string]interface{}{
+				"account_id": "acc_GRWKk7qQsLnDjX",
+				"webhook_id": "HK890egfiItP3H",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     webhookPath,
+						Method:   "GET",
+						Response: webhookErrorResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "fetching webhook failed: " +
+				"The id provided does not exist",
+		},
+		{
+			Name: "missing webhook_id",
+			Request: map[string]interface{}{
+				"account_id": "acc_GRWKk7qQsLnDjX",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: webhook_id",
+		},
+		{
+			Name: "webhook_id with a path",
+			Request: map[string]interface{}{
+				"account_id": "acc_GRWKk7qQsLnDjX",
+				"webhook_id": "HK890egfiItP3H/../..",
+			},
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid webhook_id: HK890egfiItP3H/../..",
+		},
+		{
+			Name: "account_id with a path",
+			Request: map[string]interface{}{
+				"account_id": "acc_GRWKk7qQsLnDjX/../..",
+				"webhook_id": "HK890egfiItP3H",
+			},
+			ExpectError:    true,
+			ExpectedErrMsg: "invalid account_id: acc_GRWKk7qQsLnDjX/../..",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchWebhook, "Webhook")
+		})
+	}
+}
+
+func Test_FetchAllWebhooks(t *testing.T) {
+	webhooksResp := map[string]interface{}{
+		"entity": "collection",
+		"count":  float64(1),
+		"items":  []interface{}{webhookResp},
+	}
+
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful webhooks fetch",
+			Request: map[string]interface{}{
+				"account_id": "acc_GRWKk7qQsLnDjX",
+				"count":      float64(10),
+				"skip":       float64(0),
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     webhooksPath,
+						Method:   "GET",
+						Response: webhooksResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: webhooksResp,
+		},
+		{
+			Name:           "missing account_id",
+			Request:        map[string]interface{}{},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: account_id",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, FetchAllWebhooks, "Webhooks")
+		})
+	}
+}
+
+func Test_UpdateWebhook(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful webhook update",
+			Request: map[string]interface{}{
+				"account_id": "acc_GRWKk7qQsLnDjX",
+				"webhook_id": "HK890egfiItP3H",
+				"url":        "https://en1mwkqo5ioct.x.pipedream.net",
+				"events": []interface{}{
+					"payment.authorized",
+					"payment.failed",
+				},
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     webhookPath,
+						Method:   "PATCH",
+						Response: webhookResp,
+					},
+				)
+			},
+			ExpectError:    false,
+			ExpectedResult: webhookResp,
+		},
+		{
+			Name: "missing events",
+			Request: map[string]interface{}{
+				"account_id": "acc_GRWKk7qQsLnDjX",
+				"webhook_id": "HK890egfiItP3H",
+				"url":        "https://en1mwkqo5ioct.x.pipedream.net",
+			},
+			MockHttpClient: nil,
+			ExpectError:    true,
+			ExpectedErrMsg: "missing required parameter: events",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, UpdateWebhook, "Webhook")
+		})
+	}
+}
+
+func Test_DeleteWebhook(t *testing.T) {
+	tests := []RazorpayToolTestCase{
+		{
+			Name: "successful webhook deletion",
+			Request: map[string]interface{}{
+				"account_id": "acc_GRWKk7qQsLnDjX",
+				"webhook_id": "HK890egfiItP3H",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     webhookPath,
+						Method:   "DELETE",
+						Response: "[]",
+					},
+				)
+			},
+			ExpectError: false,
+			ExpectedResult: map[string]interface{}{
+				"webhook_id": "HK890egfiItP3H",
+				"deleted":    true,
+			},
+		},
+		{
+			Name: "webhook deletion fails",
+			Request: map[string]interface{}{
+				"account_id": "acc_GRWKk7qQsLnDjX",
+				"webhook_id": "HK890egfiItP3H",
+			},
+			MockHttpClient: func() (*http.Client, *httptest.Server) {
+				return mock.NewHTTPClient(
+					mock.Endpoint{
+						Path:     webhookPath,
+						Method:   "DELETE",
+						Response: webhookErrorResp,
+					},
+				)
+			},
+			ExpectError: true,
+			ExpectedErrMsg: "deleting webhook failed: " +
+				"The id provided does not exist",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			runToolTest(t, tc, DeleteWebhook, "Webhook")
+		})
+	}
+}
@@ -0,0 +1,370 @@
+// Package redis provides a minimal Redis client, covering the commands the
+// server uses to share state between replicas. It speaks RESP2 over a small
+// pool of connections.
+package redis
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultTimeout bounds commands whose context has no deadline
+const defaultTimeout = 5 * time.Second
+
+// maxIdleConns is the number of idle connections kept for reuse
+const maxIdleConns = 10
+
+// ErrClosed is returned for commands sent after the client was closed
+var ErrClosed = errors.New("redis: client is closed")
+
+// Error is an error reply of the Redis server
+type Error string
+
+func (e Error) Error() string {
+	return "redis: " + string(e)
+}
+
+// Client sends commands to a Redis server
+type Client struct {
+	addr      string
+	tlsConfig *tls.Config
+	username  string
+	password  string
+	db        int
+
+	mu     sync.Mutex
+	idle   []*conn
+	closed bool
+}
+
+// conn is a connection to the Redis server
+type conn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+// New creates a client for the server at a redis:// or rediss:// URL, such
+// as redis://:password@localhost:6379/0. Connections are opened on the
+// first command.
+func New(rawURL string) (*Client, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis url: %w", err)
+	}
+	if u.Scheme != "redis" && u.Scheme != "rediss" {
+		return nil, fmt.Errorf("invalid redis url: scheme must be redis or "+
+			"rediss, got %q", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid redis url: missing host")
+	}
+
+	c := &Client{addr: u.Host}
+	if u.Port() == "" {
+		c.addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if u.Scheme == "rediss" {
+		c.tlsConfig = &tls.Config{
+			ServerName: u.Hostname(),
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+	if u.User != nil {
+		c.username = u.User.Username()
+		c.password, _ = u.User.Password()
+	}
+	if db := strings.TrimPrefix(u.Path, "/"); db != "" {
+		c.db, err = strconv.Atoi(db)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redis url: database %q is not "+
+				"a number", db)
+		}
+	}
+
+	return c, nil
+}
+
+// Do sends a command and returns its reply: a string, an int64, nil or a
+// []interface{} of replies. Error replies are returned as Error.
+func (c *Client) Do(ctx context.Context, args ...string) (interface{}, error) {
+	cn, err := c.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := cn.do(ctx, args)
+	var replyErr Error
+	if err != nil && !errors.As(err, &replyErr) {
+		// The connection is in an unknown state after a network error
+		_ = cn.Close()
+		return nil, err
+	}
+	c.release(cn)
+
+	return reply, err
+}
+
+// Ping checks that the server can be reached
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.Do(ctx, "PING")
+	return err
+}
+
+// Get returns the value of a key, and whether the key exists
+func (c *Client) Get(ctx context.Context, key string) (string, bool, error) {
+	reply, err := c.Do(ctx, "GET", key)
+	if err != nil || reply == nil {
+		return "", false, err
+	}
+	value, ok := reply.(string)
+	if !ok {
+		return "", false, fmt.Errorf("redis: unexpected reply %v to GET",
+			reply)
+	}
+	return value, true, nil
+}
+
+// Set sets the value of a key, expiring after ttl if it is positive
+func (c *Client) Set(
+	ctx context.Context,
+	key string,
+	value string,
+	ttl time.Duration,
+) error {
+	args := []string{"SET", key, value}
+	if ttl > 0 {
+		args = append(args, "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	}
+	_, err := c.Do(ctx, args...)
+	return err
+}
+
+// Del deletes keys
+func (c *Client) Del(ctx context.Context, keys ...string) error {
+	_, err := c.Do(ctx, append([]string{"DEL"}, keys...)...)
+	return err
+}
+
+// Incr increments the integer value of a key and returns the new value
+func (c *Client) Incr(ctx context.Context, key string) (int64, error) {
+	reply, err := c.Do(ctx, "INCR", key)
+	if err != nil {
+		return 0, err
+	}
+	return integer(reply, "INCR")
+}
+
+// Exists reports whether a key exists
+func (c *Client) Exists(ctx context.Context, key string) (bool, error) {
+	reply, err := c.Do(ctx, "EXISTS", key)
+	if err != nil {
+		return false, err
+	}
+	n, err := integer(reply, "EXISTS")
+	return n > 0, err
+}
+
+// PExpire sets a key to expire after ttl, and reports whether the key
+// exists
+func (c *Client) PExpire(
+	ctx context.Context,
+	key string,
+	ttl time.Duration,
+) (bool, error) {
+	reply, err := c.Do(ctx, "PEXPIRE", key,
+		strconv.FormatInt(ttl.Milliseconds(), 10))
+	if err != nil {
+		return false, err
+	}
+	n, err := integer(reply, "PEXPIRE")
+	return n > 0, err
+}
+
+// Close closes the idle connections. Commands sent afterwards fail.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	for _, cn := range c.idle {
+		_ = cn.Close()
+	}
+	c.idle = nil
+
+	return nil
+}
+
+// integer returns an integer reply
+func integer(reply interface{}, command string) (int64, error) {
+	n, ok := reply.(int64)
+	if !ok {
+		return 0, fmt.Errorf("redis: unexpected reply %v to %s", reply,
+			command)
+	}
+	return n, nil
+}
+
+// conn returns an idle connection, or opens a new one
+func (c *Client) conn(ctx context.Context) (*conn, error) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil, ErrClosed
+	}
+	if n := len(c.idle); n > 0 {
+		cn := c.idle[n-1]
+		c.idle = c.idle[:n-1]
+		c.mu.Unlock()
+		return cn, nil
+	}
+	c.mu.Unlock()
+
+	return c.dial(ctx)
+}
+
+// release returns a connection to the pool, or closes it if the pool is
+// full
+func (c *Client) release(cn *conn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed || len(c.idle) >= maxIdleConns {
+		_ = cn.Close()
+		return
+	}
+	c.idle = append(c.idle, cn)
+}
+
+// dial opens a connection, authenticates it and selects the database
+func (c *Client) dial(ctx context.Context) (*conn, error) {
+	dialer := &net.Dialer{Timeout: defaultTimeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return nil, fmt.Errorf("redis: %w", err)
+	}
+	if c.tlsConfig != nil {
+		netConn = tls.Client(netConn, c.tlsConfig)
+	}
+	cn := &conn{Conn: netConn, reader: bufio.NewReader(netConn)}
+
+	var setup [][]string
+	switch {
+	case c.username != "" && c.password != "":
+		setup = append(setup, []string{"AUTH", c.username, c.password})
+	case c.password != "":
+		setup = append(setup, []string{"AUTH", c.password})
+	}
+	if c.db != 0 {
+		setup = append(setup, []string{"SELECT", strconv.Itoa(c.db)})
+	}
+	for _, args := range setup {
+		if _, err := cn.do(ctx, args); err != nil {
+			_ = cn.Close()
+			return nil, err
+		}
+	}
+
+	return cn, nil
+}
+
+// do sends a command on the connection and reads its reply
+func (cn *conn) do(ctx context.Context, args []string) (interface{}, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(defaultTimeout)
+	}
+	if err := cn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(cn, b.String()); err != nil {
+		return nil, fmt.Errorf("redis: %w", err)
+	}
+
+	return readReply(cn.reader)
+}
+
+// readReply reads a RESP2 reply
+func readReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("redis: %w", err)
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("redis: empty reply")
+	}
+
+	switch prefix, value := line[0], line[1:]; prefix {
+	case '+':
+		return value, nil
+	case '-':
+		return nil, Error(value)
+	case ':':
+		return strconv.ParseInt(value, 10, 64)
+	case '$':
+		return readBulkString(r, value)
+	case '*':
+		return readArray(r, value)
+	default:
+		return nil, fmt.Errorf("redis: invalid reply %q", line)
+	}
+}
+
+// readBulkString reads the bulk string of the given length, or nil for a
+// length of -1
+func readBulkString(r *bufio.Reader, length string) (interface{}, error) {
+	n, err := strconv.Atoi(length)
+	if err != nil || n < -1 {
+		return nil, fmt.Errorf("redis: invalid bulk string length %q", length)
+	}
+	if n == -1 {
+		return nil, nil
+	}
+
+	data := make([]byte, n+2)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, fmt.Errorf("redis: %w", err)
+	}
+	return string(data[:n]), nil
+}
+
+// readArray reads the array of the given length, or nil for a length of -1
+func readArray(r *bufio.Reader, length string) (interface{}, error) {
+	n, err := strconv.Atoi(length)
+	if err != nil || n < -1 {
+		return nil, fmt.Errorf("redis: invalid array length %q", length)
+	}
+	if n == -1 {
+		return nil, nil
+	}
+
+	replies := make([]interface{}, 0, n)
+	for i := 0; i < n; i++ {
+		reply, err := readReply(r)
+		var replyErr Error
+		if err != nil && !errors.As(err, &replyErr) {
+			return nil, err
+		}
+		if err != nil {
+			reply = replyErr
+		}
+		replies = append(replies, reply)
+	}
+	return replies, nil
+}
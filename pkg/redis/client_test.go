@@ -0,0 +1,143 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/razorpay/razorpay-mcp-server/pkg/redis/redistest"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		addr     string
+		password string
+		db       int
+		tls      bool
+		err      string
+	}{
+		{
+			name: "defaults the port",
+			url:  "redis://localhost",
+			addr: "localhost:6379",
+		},
+		{
+			name:     "reads the password and database",
+			url:      "rediss://:secret@cache.internal:6380/2",
+			addr:     "cache.internal:6380",
+			password: "secret",
+			db:       2,
+			tls:      true,
+		},
+		{
+			name: "rejects other schemes",
+			url:  "http://localhost:6379",
+			err:  `scheme must be redis or rediss, got "http"`,
+		},
+		{
+			name: "rejects invalid databases",
+			url:  "redis://localhost:6379/cache",
+			err:  `database "cache" is not a number`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client, err := New(tc.url)
+			if tc.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.addr, client.addr)
+			assert.Equal(t, tc.password, client.password)
+			assert.Equal(t, tc.db, client.db)
+			assert.Equal(t, tc.tls, client.tlsConfig != nil)
+		})
+	}
+}
+
+func TestClient(t *testing.T) {
+	server := redistest.NewServer(t)
+	client, err := New(server.URL())
+	require.NoError(t, err)
+	defer func() { _ = client.Close() }()
+	ctx := context.Background()
+
+	require.NoError(t, client.Ping(ctx))
+
+	t.Run("sets and gets values", func(t *testing.T) {
+		_, ok, err := client.Get(ctx, "missing")
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		require.NoError(t, client.Set(ctx, "key", "value\r\nwith lines", 0))
+		value, ok, err := client.Get(ctx, "key")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "value\r\nwith lines", value)
+
+		require.NoError(t, client.Del(ctx, "key"))
+		exists, err := client.Exists(ctx, "key")
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("expires values", func(t *testing.T) {
+		now := time.Now()
+		server.SetNow(func() time.Time { return now })
+
+		require.NoError(t, client.Set(ctx, "key", "value", time.Minute))
+		ok, err := client.PExpire(ctx, "key", 2*time.Minute)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		now = now.Add(time.Minute)
+		exists, err := client.Exists(ctx, "key")
+		require.NoError(t, err)
+		assert.True(t, exists)
+
+		now = now.Add(time.Minute)
+		exists, err = client.Exists(ctx, "key")
+		require.NoError(t, err)
+		assert.False(t, exists)
+
+		ok, err = client.PExpire(ctx, "key", time.Minute)
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("increments values", func(t *testing.T) {
+		n, err := client.Incr(ctx, "counter")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+		n, err = client.Incr(ctx, "counter")
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+	})
+
+	t.Run("returns error replies", func(t *testing.T) {
+		_, err := client.Do(ctx, "FLUSHALL")
+		var replyErr Error
+		require.ErrorAs(t, err, &replyErr)
+		assert.Equal(t, "redis: ERR unknown command 'FLUSHALL'", err.Error())
+
+		// the connection stays usable
+		require.NoError(t, client.Ping(ctx))
+	})
+
+	t.Run("fails once closed", func(t *testing.T) {
+		closed, err := New(server.URL())
+		require.NoError(t, err)
+		require.NoError(t, closed.Close())
+
+		assert.ErrorIs(t, closed.Ping(ctx), ErrClosed)
+	})
+}
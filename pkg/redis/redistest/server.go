@@ -0,0 +1,207 @@
+// Package redistest provides an in-memory Redis server for tests. It
+// supports the commands of the redis package.
+package redistest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Server is an in-memory Redis server listening on a local port
+type Server struct {
+	listener net.Listener
+
+	mu     sync.Mutex
+	values map[string]entry
+	// now returns the current time, and can be replaced to expire keys
+	now func() time.Time
+}
+
+// entry is the value of a key and when it expires, zero for never
+type entry struct {
+	value   string
+	expires time.Time
+}
+
+// NewServer starts a server that is closed when the test ends
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("starting redis test server: %v", err)
+	}
+	s := &Server{
+		listener: listener,
+		values:   make(map[string]entry),
+		now:      time.Now,
+	}
+	go s.serve()
+	t.Cleanup(func() { _ = listener.Close() })
+
+	return s
+}
+
+// URL returns the redis:// URL of the server
+func (s *Server) URL() string {
+	return "redis://" + s.listener.Addr().String()
+}
+
+// SetNow replaces the clock keys expire by
+func (s *Server) SetNow(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = now
+}
+
+// Keys returns the number of keys that have not expired
+func (s *Server) Keys() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := 0
+	for key := range s.values {
+		if _, ok := s.lookup(key); ok {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *Server) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+// handle answers the commands sent on a connection
+func (s *Server) handle(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+
+	reader := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+		if _, err := io.WriteString(conn, s.execute(args)); err != nil {
+			return
+		}
+	}
+}
+
+// readCommand reads a command sent as a RESP array of bulk strings
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSuffix(line[1:], "\r\n"))
+	if err != nil || line[0] != '*' {
+		return nil, fmt.Errorf("invalid command %q", line)
+	}
+
+	args := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		length, err := strconv.Atoi(strings.TrimSuffix(line[1:], "\r\n"))
+		if err != nil {
+			return nil, err
+		}
+		data := make([]byte, length+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		args = append(args, string(data[:length]))
+	}
+	return args, nil
+}
+
+// execute runs a command and returns its RESP reply
+func (s *Server) execute(args []string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(args) == 0 {
+		return "-ERR empty command\r\n"
+	}
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "+PONG\r\n"
+	case "AUTH", "SELECT":
+		return "+OK\r\n"
+	case "GET":
+		e, ok := s.lookup(args[1])
+		if !ok {
+			return "$-1\r\n"
+		}
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(e.value), e.value)
+	case "SET":
+		e := entry{value: args[2]}
+		if len(args) == 5 && strings.EqualFold(args[3], "PX") {
+			ms, _ := strconv.Atoi(args[4])
+			e.expires = s.now().Add(time.Duration(ms) * time.Millisecond)
+		}
+		s.values[args[1]] = e
+		return "+OK\r\n"
+	case "DEL":
+		n := 0
+		for _, key := range args[1:] {
+			if _, ok := s.lookup(key); ok {
+				delete(s.values, key)
+				n++
+			}
+		}
+		return fmt.Sprintf(":%d\r\n", n)
+	case "INCR":
+		e, _ := s.lookup(args[1])
+		n, _ := strconv.Atoi(e.value)
+		e.value = strconv.Itoa(n + 1)
+		s.values[args[1]] = e
+		return fmt.Sprintf(":%d\r\n", n+1)
+	case "EXISTS":
+		if _, ok := s.lookup(args[1]); ok {
+			return ":1\r\n"
+		}
+		return ":0\r\n"
+	case "PEXPIRE":
+		e, ok := s.lookup(args[1])
+		if !ok {
+			return ":0\r\n"
+		}
+		ms, _ := strconv.Atoi(args[2])
+		e.expires = s.now().Add(time.Duration(ms) * time.Millisecond)
+		s.values[args[1]] = e
+		return ":1\r\n"
+	default:
+		return "-ERR unknown command '" + args[0] + "'\r\n"
+	}
+}
+
+// lookup returns the entry of a key, dropping it if it expired. The caller
+// must hold s.mu.
+func (s *Server) lookup(key string) (entry, bool) {
+	e, ok := s.values[key]
+	if !ok {
+		return entry{}, false
+	}
+	if !e.expires.IsZero() && !s.now().Before(e.expires) {
+		delete(s.values, key)
+		return entry{}, false
+	}
+	return e, true
+}
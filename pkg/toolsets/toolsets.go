@@ -2,6 +2,8 @@ package toolsets
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/razorpay/razorpay-mcp-server/pkg/mcpgo"
 )
@@ -21,6 +23,10 @@ type ToolsetGroup struct {
 	Toolsets     map[string]*Toolset
 	everythingOn bool
 	readOnly     bool
+	// enabledTools, when set, limits the registered tools to those named
+	enabledTools map[string]bool
+	// disabledTools are never registered
+	disabledTools map[string]bool
 }
 
 // NewToolset creates a new toolset with the given name and description
@@ -58,21 +64,69 @@ func (t *Toolset) AddReadTools(tools ...mcpgo.Tool) *Toolset {
 
 // RegisterTools registers all active tools with the server
 func (t *Toolset) RegisterTools(s mcpgo.Server) {
+	t.registerTools(s, func(string) bool { return true })
+}
+
+// registerTools registers the active tools that the filter accepts
+func (t *Toolset) registerTools(
+	s mcpgo.Server,
+	toolEnabled func(name string) bool,
+) {
 	if !t.Enabled {
 		return
 	}
 	for _, tool := range t.readTools {
+		if !toolEnabled(tool.GetName()) {
+			continue
+		}
 		tool.SetReadOnly(true)
+		tool.SetToolset(t.Name)
 		s.AddTools(tool)
 	}
 	if !t.readOnly {
 		for _, tool := range t.writeTools {
+			if !toolEnabled(tool.GetName()) {
+				continue
+			}
 			tool.SetReadOnly(false)
+			tool.SetToolset(t.Name)
 			s.AddTools(tool)
 		}
 	}
 }
 
+// registers reports whether the toolset registers the tool when enabled
+func (t *Toolset) registers(name string) bool {
+	for _, tool := range t.readTools {
+		if tool.GetName() == name {
+			return true
+		}
+	}
+	if !t.readOnly {
+		for _, tool := range t.writeTools {
+			if tool.GetName() == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasTool reports whether the toolset contains a tool with the given name
+func (t *Toolset) hasTool(name string) bool {
+	for _, tool := range t.readTools {
+		if tool.GetName() == name {
+			return true
+		}
+	}
+	for _, tool := range t.writeTools {
+		if tool.GetName() == name {
+			return true
+		}
+	}
+	return false
+}
+
 // AddToolset adds a toolset to the group
 func (tg *ToolsetGroup) AddToolset(ts *Toolset) {
 	if tg.readOnly {
@@ -91,6 +145,26 @@ func (tg *ToolsetGroup) EnableToolset(name string) error {
 	return nil
 }
 
+// DisableToolset disables a specific toolset
+func (tg *ToolsetGroup) DisableToolset(name string) error {
+	toolset, exists := tg.Toolsets[name]
+	if !exists {
+		return fmt.Errorf("toolset %s does not exist", name)
+	}
+	toolset.Enabled = false
+	return nil
+}
+
+// SetToolsetReadOnly limits a specific toolset to its read tools
+func (tg *ToolsetGroup) SetToolsetReadOnly(name string) error {
+	toolset, exists := tg.Toolsets[name]
+	if !exists {
+		return fmt.Errorf("toolset %s does not exist", name)
+	}
+	toolset.readOnly = true
+	return nil
+}
+
 // EnableToolsets enables multiple toolsets
 func (tg *ToolsetGroup) EnableToolsets(names []string) error {
 	if len(names) == 0 {
@@ -117,9 +191,127 @@ func (tg *ToolsetGroup) EnableToolsets(names []string) error {
 	return nil
 }
 
+// EnableTools limits the registered tools to the named ones. Tools still
+// have to belong to an enabled toolset to be registered.
+func (tg *ToolsetGroup) EnableTools(names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	enabledTools, err := tg.toolSet(names)
+	if err != nil {
+		return err
+	}
+	tg.enabledTools = enabledTools
+	return nil
+}
+
+// DisableTools prevents the named tools from being registered
+func (tg *ToolsetGroup) DisableTools(names []string) error {
+	disabledTools, err := tg.toolSet(names)
+	if err != nil {
+		return err
+	}
+	tg.disabledTools = disabledTools
+	return nil
+}
+
+// toolSet returns the given tool names as a set, and fails if any of them
+// does not belong to a toolset of the group
+func (tg *ToolsetGroup) toolSet(names []string) (map[string]bool, error) {
+	tools := make(map[string]bool, len(names))
+	for _, name := range names {
+		if !tg.HasTool(name) {
+			return nil, fmt.Errorf("tool %s does not exist", name)
+		}
+		tools[name] = true
+	}
+	return tools, nil
+}
+
+// HasTool reports whether any toolset of the group contains the tool
+func (tg *ToolsetGroup) HasTool(name string) bool {
+	for _, toolset := range tg.Toolsets {
+		if toolset.hasTool(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ToolRegistered reports whether the tool is registered with the server,
+// that is whether it belongs to an enabled toolset and is not filtered out
+func (tg *ToolsetGroup) ToolRegistered(name string) bool {
+	if !tg.toolEnabled(name) {
+		return false
+	}
+	for _, toolset := range tg.Toolsets {
+		if toolset.Enabled && toolset.registers(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// toolEnabled reports whether the tool passes the tool allow and deny lists
+func (tg *ToolsetGroup) toolEnabled(name string) bool {
+	if tg.disabledTools[name] {
+		return false
+	}
+	return tg.enabledTools == nil || tg.enabledTools[name]
+}
+
 // RegisterTools registers all active toolsets with the server
 func (tg *ToolsetGroup) RegisterTools(s mcpgo.Server) {
 	for _, toolset := range tg.Toolsets {
-		toolset.RegisterTools(s)
+		toolset.registerTools(s, tg.toolEnabled)
+	}
+}
+
+// EnabledToolsets returns the names of the enabled toolsets in sorted order
+func (tg *ToolsetGroup) EnabledToolsets() []string {
+	names := make([]string, 0, len(tg.Toolsets))
+	for name, toolset := range tg.Toolsets {
+		if toolset.Enabled {
+			names = append(names, name)
+		}
 	}
+	sort.Strings(names)
+	return names
+}
+
+// Documentation returns a markdown description of a toolset that lists the
+// tools of it that are registered
+func (tg *ToolsetGroup) Documentation(name string) (string, error) {
+	toolset, exists := tg.Toolsets[name]
+	if !exists {
+		return "", fmt.Errorf("toolset %s does not exist", name)
+	}
+
+	var doc strings.Builder
+	fmt.Fprintf(&doc, "# %s\n\n%s\n", toolset.Name, toolset.Description)
+
+	writeToolList := func(title string, tools []mcpgo.Tool) {
+		var names []string
+		for _, tool := range tools {
+			if tg.toolEnabled(tool.GetName()) {
+				names = append(names, tool.GetName())
+			}
+		}
+		if len(names) == 0 {
+			return
+		}
+
+		fmt.Fprintf(&doc, "\n## %s\n\n", title)
+		for _, name := range names {
+			fmt.Fprintf(&doc, "- `%s`\n", name)
+		}
+	}
+
+	writeToolList("Read tools", toolset.readTools)
+	if !toolset.readOnly {
+		writeToolList("Write tools", toolset.writeTools)
+	}
+
+	return doc.String(), nil
 }
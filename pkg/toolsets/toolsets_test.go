@@ -11,13 +11,23 @@ import (
 
 // mockServer is a mock implementation of mcpgo.Server for testing
 type mockServer struct {
-	tools []mcpgo.Tool
+	tools     []mcpgo.Tool
+	resources []mcpgo.Resource
+	prompts   []mcpgo.Prompt
 }
 
 func (m *mockServer) AddTools(tools ...mcpgo.Tool) {
 	m.tools = append(m.tools, tools...)
 }
 
+func (m *mockServer) AddResources(resources ...mcpgo.Resource) {
+	m.resources = append(m.resources, resources...)
+}
+
+func (m *mockServer) AddPrompts(prompts ...mcpgo.Prompt) {
+	m.prompts = append(m.prompts, prompts...)
+}
+
 func (m *mockServer) GetTools() []mcpgo.Tool {
 	return m.tools
 }
@@ -344,6 +354,57 @@ func TestToolsetGroup_EnableToolset(t *testing.T) {
 	})
 }
 
+func TestToolsetGroup_DisableToolset(t *testing.T) {
+	t.Run("disables enabled toolset", func(t *testing.T) {
+		tg := NewToolsetGroup(false)
+		ts := NewToolset("test", "Test")
+		tg.AddToolset(ts)
+		assert.NoError(t, tg.EnableToolset("test"))
+
+		assert.NoError(t, tg.DisableToolset("test"))
+		assert.False(t, ts.Enabled)
+	})
+
+	t.Run("returns error for non-existent toolset", func(t *testing.T) {
+		tg := NewToolsetGroup(false)
+
+		err := tg.DisableToolset("nonexistent")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "does not exist")
+	})
+}
+
+func TestToolsetGroup_SetToolsetReadOnly(t *testing.T) {
+	t.Run("registers only read tools of the toolset", func(t *testing.T) {
+		handler := func(ctx context.Context,
+			req mcpgo.CallToolRequest) (*mcpgo.ToolResult, error) {
+			return mcpgo.NewToolResultText("result"), nil
+		}
+		tg := NewToolsetGroup(false)
+		ts := NewToolset("test", "Test").
+			AddReadTools(mcpgo.NewTool("read-tool", "Read", nil, handler)).
+			AddWriteTools(mcpgo.NewTool("write-tool", "Write", nil, handler))
+		tg.AddToolset(ts)
+		assert.NoError(t, tg.EnableToolset("test"))
+
+		assert.NoError(t, tg.SetToolsetReadOnly("test"))
+
+		mockSrv := &mockServer{}
+		tg.RegisterTools(mockSrv)
+		assert.Len(t, mockSrv.GetTools(), 1)
+		assert.Equal(t, "read-tool", mockSrv.GetTools()[0].GetName())
+		assert.False(t, tg.ToolRegistered("write-tool"))
+	})
+
+	t.Run("returns error for non-existent toolset", func(t *testing.T) {
+		tg := NewToolsetGroup(false)
+
+		err := tg.SetToolsetReadOnly("nonexistent")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "does not exist")
+	})
+}
+
 func TestToolsetGroup_EnableToolsets(t *testing.T) {
 	t.Run("enables multiple toolsets", func(t *testing.T) {
 		tg := NewToolsetGroup(false)
@@ -547,3 +608,194 @@ func TestToolsetGroup_RegisterTools(t *testing.T) {
 		assert.Len(t, mockSrv.GetTools(), 0) // No toolsets, no tools
 	})
 }
+
+// newFilterTestGroup creates a group with an enabled toolset holding the
+// read tool "fetch" and the write tool "create"
+func newFilterTestGroup() *ToolsetGroup {
+	handler := func(ctx context.Context,
+		req mcpgo.CallToolRequest) (*mcpgo.ToolResult, error) {
+		return mcpgo.NewToolResultText("result"), nil
+	}
+
+	ts := NewToolset("test", "Test")
+	ts.AddReadTools(
+		mcpgo.NewTool("fetch", "Fetch", []mcpgo.ToolParameter{}, handler))
+	ts.AddWriteTools(
+		mcpgo.NewTool("create", "Create", []mcpgo.ToolParameter{}, handler))
+	ts.Enabled = true
+
+	tg := NewToolsetGroup(false)
+	tg.AddToolset(ts)
+	return tg
+}
+
+// registeredToolNames returns the names of the tools registered with srv
+func registeredToolNames(srv *mockServer) []string {
+	names := make([]string, 0, len(srv.GetTools()))
+	for _, tool := range srv.GetTools() {
+		names = append(names, tool.GetName())
+	}
+	return names
+}
+
+func TestToolsetGroup_EnableTools(t *testing.T) {
+	t.Run("registers only enabled tools", func(t *testing.T) {
+		tg := newFilterTestGroup()
+
+		err := tg.EnableTools([]string{"create"})
+		assert.NoError(t, err)
+
+		mockSrv := &mockServer{}
+		tg.RegisterTools(mockSrv)
+
+		assert.Equal(t, []string{"create"}, registeredToolNames(mockSrv))
+	})
+
+	t.Run("registers all tools when empty", func(t *testing.T) {
+		tg := newFilterTestGroup()
+
+		err := tg.EnableTools([]string{})
+		assert.NoError(t, err)
+
+		mockSrv := &mockServer{}
+		tg.RegisterTools(mockSrv)
+
+		assert.Len(t, mockSrv.GetTools(), 2)
+	})
+
+	t.Run("does not register tools of disabled toolsets", func(t *testing.T) {
+		tg := newFilterTestGroup()
+		tg.Toolsets["test"].Enabled = false
+
+		err := tg.EnableTools([]string{"fetch"})
+		assert.NoError(t, err)
+
+		mockSrv := &mockServer{}
+		tg.RegisterTools(mockSrv)
+
+		assert.Len(t, mockSrv.GetTools(), 0)
+	})
+
+	t.Run("returns error for non-existent tool", func(t *testing.T) {
+		tg := newFilterTestGroup()
+
+		err := tg.EnableTools([]string{"fetch", "delete"})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "tool delete does not exist")
+	})
+}
+
+func TestToolsetGroup_DisableTools(t *testing.T) {
+	t.Run("does not register disabled tools", func(t *testing.T) {
+		tg := newFilterTestGroup()
+
+		err := tg.DisableTools([]string{"create"})
+		assert.NoError(t, err)
+
+		mockSrv := &mockServer{}
+		tg.RegisterTools(mockSrv)
+
+		assert.Equal(t, []string{"fetch"}, registeredToolNames(mockSrv))
+	})
+
+	t.Run("takes precedence over enabled tools", func(t *testing.T) {
+		tg := newFilterTestGroup()
+
+		err := tg.EnableTools([]string{"fetch", "create"})
+		assert.NoError(t, err)
+		err = tg.DisableTools([]string{"fetch"})
+		assert.NoError(t, err)
+
+		mockSrv := &mockServer{}
+		tg.RegisterTools(mockSrv)
+
+		assert.Equal(t, []string{"create"}, registeredToolNames(mockSrv))
+	})
+
+	t.Run("returns error for non-existent tool", func(t *testing.T) {
+		tg := newFilterTestGroup()
+
+		err := tg.DisableTools([]string{"delete"})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "tool delete does not exist")
+	})
+}
+
+func TestToolsetGroup_EnabledToolsets(t *testing.T) {
+	t.Run("returns enabled toolsets in sorted order", func(t *testing.T) {
+		tg := NewToolsetGroup(false)
+		tg.AddToolset(NewToolset("orders", "Orders"))
+		tg.AddToolset(NewToolset("items", "Items"))
+		tg.AddToolset(NewToolset("payments", "Payments"))
+
+		err := tg.EnableToolsets([]string{"payments", "items"})
+		assert.NoError(t, err)
+
+		assert.Equal(t, []string{"items", "payments"}, tg.EnabledToolsets())
+	})
+}
+
+func TestToolsetGroup_Documentation(t *testing.T) {
+	t.Run("lists read and write tools", func(t *testing.T) {
+		tg := newFilterTestGroup()
+
+		doc, err := tg.Documentation("test")
+		assert.NoError(t, err)
+		assert.Equal(t, "# test\n\nTest\n"+
+			"\n## Read tools\n\n- `fetch`\n"+
+			"\n## Write tools\n\n- `create`\n", doc)
+	})
+
+	t.Run("leaves out tools that are not registered", func(t *testing.T) {
+		tg := newFilterTestGroup()
+		tg.Toolsets["test"].readOnly = true
+		err := tg.DisableTools([]string{"fetch"})
+		assert.NoError(t, err)
+
+		doc, err := tg.Documentation("test")
+		assert.NoError(t, err)
+		assert.Equal(t, "# test\n\nTest\n", doc)
+	})
+
+	t.Run("returns error for non-existent toolset", func(t *testing.T) {
+		tg := newFilterTestGroup()
+
+		_, err := tg.Documentation("missing")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "toolset missing does not exist")
+	})
+}
+
+func TestToolsetGroup_ToolRegistered(t *testing.T) {
+	t.Run("reports registered tools", func(t *testing.T) {
+		tg := newFilterTestGroup()
+
+		assert.True(t, tg.ToolRegistered("fetch"))
+		assert.True(t, tg.ToolRegistered("create"))
+		assert.False(t, tg.ToolRegistered("delete"))
+	})
+
+	t.Run("reports write tools unregistered when readOnly", func(t *testing.T) {
+		tg := newFilterTestGroup()
+		tg.Toolsets["test"].readOnly = true
+
+		assert.True(t, tg.ToolRegistered("fetch"))
+		assert.False(t, tg.ToolRegistered("create"))
+	})
+
+	t.Run("reports filtered tools unregistered", func(t *testing.T) {
+		tg := newFilterTestGroup()
+		err := tg.DisableTools([]string{"fetch"})
+		assert.NoError(t, err)
+
+		assert.False(t, tg.ToolRegistered("fetch"))
+	})
+
+	t.Run("reports tools of disabled toolsets unregistered",
+		func(t *testing.T) {
+			tg := newFilterTestGroup()
+			tg.Toolsets["test"].Enabled = false
+
+			assert.False(t, tg.ToolRegistered("fetch"))
+		})
+}